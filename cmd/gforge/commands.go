@@ -1,16 +1,61 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"text/tabwriter"
 	"time"
 
 	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/api"
+	"github.com/astoreyai/goblin-forge/internal/bench"
+	"github.com/astoreyai/goblin-forge/internal/chart"
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/contextpack"
 	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/daemon"
+	"github.com/astoreyai/goblin-forge/internal/digest"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/gpu"
+	"github.com/astoreyai/goblin-forge/internal/hours"
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+	"github.com/astoreyai/goblin-forge/internal/ipc"
+	"github.com/astoreyai/goblin-forge/internal/notify"
+	"github.com/astoreyai/goblin-forge/internal/providers"
+	"github.com/astoreyai/goblin-forge/internal/recording"
+	"github.com/astoreyai/goblin-forge/internal/repomap"
+	"github.com/astoreyai/goblin-forge/internal/scaffold"
+	"github.com/astoreyai/goblin-forge/internal/slack"
+	"github.com/astoreyai/goblin-forge/internal/state"
+	"github.com/astoreyai/goblin-forge/internal/statusline"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/style"
+	"github.com/astoreyai/goblin-forge/internal/summarize"
+	"github.com/astoreyai/goblin-forge/internal/table"
+	"github.com/astoreyai/goblin-forge/internal/telemetry"
+	"github.com/astoreyai/goblin-forge/internal/template"
 	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/usage"
+	"github.com/astoreyai/goblin-forge/internal/voice"
+	"github.com/astoreyai/goblin-forge/internal/webhook"
+	"github.com/astoreyai/goblin-forge/internal/workflow"
 	"github.com/astoreyai/goblin-forge/internal/workspace"
 )
 
@@ -45,6 +90,7 @@ func listAgents() error {
 func scanAgents() error {
 	registry := agents.NewRegistry()
 	detected := registry.Scan()
+	registry.SaveScan(cfg.AgentScanFile, detected)
 
 	fmt.Println("Scanning for installed agents...")
 	fmt.Println()
@@ -88,319 +134,4942 @@ func scanAgents() error {
 	return nil
 }
 
+// spawnFlags are gforge spawn's flags
+type spawnFlags struct {
+	agent, project, branch string
+	devEnv                 string
+	task, priority         string
+	socket, issue, squad   string
+	paths                  []string
+	record, noContext      bool
+	allowProtected         bool
+}
+
 // spawnGoblin creates a new goblin instance
-func spawnGoblin(name, agentName, projectPath, branch string) error {
-	registry := agents.NewRegistry()
+func spawnGoblin(name string, f spawnFlags) error {
+	if _, err := coordinator.ParsePriority(f.priority); err != nil {
+		return err
+	}
+	if f.issue != "" {
+		if remote != nil {
+			return fmt.Errorf("--issue is not supported against a remote server; link it there with gforge progress link")
+		}
+		if _, err := integrations.ParseTrackerRef(f.issue); err != nil {
+			return err
+		}
+	}
+
+	if remote != nil {
+		agentName := f.agent
+		if agentName == "" {
+			agentName = defaultAgent(nil)
+		}
+		goblin, err := remote.Spawn(api.SpawnRequest{
+			Name:        name,
+			Agent:       agentName,
+			ProjectPath: f.project,
+			Branch:      f.branch,
+			Task:        f.task,
+			Priority:    f.priority,
+			Squad:       f.squad,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to spawn goblin: %w", err)
+		}
+		fmt.Printf("Spawned goblin: %s (%s) on %s\n", goblin.Name, goblin.ID, serverURL)
+		return nil
+	}
+
+	opts, project, err := spawnOptions(name, f.agent, f.project, f.branch, f.devEnv)
+	if err != nil {
+		return err
+	}
+	absPath := opts.ProjectPath
+
+	warnSignoff(absPath, project, f.paths, f.task)
+
+	// Create coordinator
+	coord := coordinator.New(db, cfg, log)
+
+	intended := append(workspace.TaskPaths(absPath, f.task), workspace.PathWords(f.task)...)
+	for _, path := range f.paths {
+		if lp, err := coordinator.LockPath(absPath, path); err == nil {
+			intended = append(intended, lp)
+		}
+	}
+	if conflicts, err := coord.LockConflicts(absPath, "", intended); err == nil {
+		warnLocks(conflicts)
+	}
+
+	// Spawn goblin
+	opts.Task = f.task
+	opts.Record = f.record
+	opts.NoContext = f.noContext
+	opts.Priority = f.priority
+	opts.TmuxSocket = f.socket
+	opts.Squad = f.squad
+	opts.AllowProtected = f.allowProtected
+	goblin, err := coord.Spawn(opts)
+	if err != nil {
+		return fmt.Errorf("failed to spawn goblin: %w", err)
+	}
+
+	fmt.Printf("Spawned goblin: %s\n", goblin.Name)
+	fmt.Printf("  ID:       %s\n", goblin.ID)
+	fmt.Printf("  Agent:    %s\n", goblin.Agent)
+	fmt.Printf("  Branch:   %s\n", goblin.Branch)
+	fmt.Printf("  Worktree: %s\n", goblin.WorktreePath)
+	fmt.Printf("  Status:   %s\n", goblin.Status)
+	if goblin.Squad != "" {
+		fmt.Printf("  Squad:    %s\n", goblin.Squad)
+	}
+	if f.issue != "" {
+		if ref, err := coord.LinkIssue(goblin, f.issue); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to link %s: %v\n", f.issue, err)
+		} else {
+			fmt.Printf("  Issue:    %s\n", ref)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Attach with: gforge attach %s\n", name)
+
+	return nil
+}
+
+// spawnOptions resolves a spawn's agent, project path, branch and dev
+// environment, with the project's .gforge.yaml filling in anything not
+// given, and returns the project's config alongside
+func spawnOptions(name, agentName, projectPath, branch, devEnv string) (coordinator.SpawnOptions, *config.ProjectConfig, error) {
+	project, err := config.LoadProject(projectPath)
+	if err != nil {
+		return coordinator.SpawnOptions{}, nil, err
+	}
+	if agentName == "" {
+		agentName = defaultAgent(project)
+	}
+	if devEnv == "" {
+		devEnv = project.DevEnv
+	}
 
-	// Validate agent
-	agent := registry.Get(agentName)
+	agent := agents.NewRegistry().Get(agentName)
 	if agent == nil {
+		return coordinator.SpawnOptions{}, nil, fmt.Errorf("unknown agent: %s (available: claude, codex, gemini, ollama)", agentName)
+	}
+
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return coordinator.SpawnOptions{}, nil, fmt.Errorf("invalid project path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return coordinator.SpawnOptions{}, nil, fmt.Errorf("project path does not exist: %s", absPath)
+	}
+
+	if branch == "" {
+		prefix := project.BranchPrefix
+		if prefix == "" {
+			prefix = cfg.Git.BranchPrefix
+		}
+		branch = prefix + name
+	}
+
+	return coordinator.SpawnOptions{
+		Name:        name,
+		Agent:       agent,
+		ProjectPath: absPath,
+		Branch:      branch,
+		DevEnv:      devEnv,
+	}, project, nil
+}
+
+// checkProviders probes every model provider and prints a health table
+func checkProviders(timeout time.Duration, jsonOutput bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results := providers.CheckAll(ctx, &http.Client{}, providers.Defaults())
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tSTATUS\tLATENCY\tAGENTS\tDETAIL")
+	fmt.Fprintln(w, "--------\t------\t-------\t------\t------")
+
+	for _, r := range results {
+		latency := "-"
+		if r.Latency > 0 {
+			latency = r.Latency.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Provider, r.Status, latency, strings.Join(r.Agents, ", "), r.Detail)
+	}
+
+	return w.Flush()
+}
+
+// runDoctor checks the tools and tmux sockets gforge depends on, fixing
+// what it safely can when fix is set
+func runDoctor(fix bool) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDETAIL")
+	fmt.Fprintln(w, "-----\t------\t------")
+
+	problems := 0
+	for _, tool := range []struct{ name, args string }{{"tmux", "-V"}, {"git", "--version"}} {
+		output, err := exec.Command(tool.name, tool.args).Output()
+		if err != nil {
+			problems++
+			fmt.Fprintf(w, "%s\tmissing\t%v\n", tool.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\tok\t%s\n", tool.name, strings.TrimSpace(string(output)))
+	}
+
+	sockets, err := coordinator.New(db, cfg, log).Sockets()
+	if err != nil {
+		return err
+	}
+	for _, name := range sockets {
+		status := tmux.CheckSocket(name)
+		state, detail := string(status.State), status.Detail
+		if detail == "" {
+			detail = status.Path
+		}
+
+		switch status.State {
+		case tmux.SocketMissing, tmux.SocketLive:
+			state = "ok (" + state + ")"
+		default:
+			if !fix {
+				problems++
+				break
+			}
+			fixed, err := status.Fix()
+			switch {
+			case err != nil:
+				problems++
+				detail = err.Error()
+			case fixed:
+				state = "fixed (" + state + ")"
+			default:
+				problems++
+			}
+		}
+		fmt.Fprintf(w, "socket %s\t%s\t%s\n", name, state, detail)
+	}
+
+	w.Flush()
+
+	if problems > 0 {
+		if !fix {
+			fmt.Println()
+			fmt.Println("Run gforge doctor --fix to clean up stale sockets and unsafe directories.")
+		}
+		return fmt.Errorf("%d problem(s) found", problems)
+	}
+	return nil
+}
+
+// defaultAgent picks the agent for a spawn without --agent: the project's
+// choice, then the user's default, then claude
+func defaultAgent(project *config.ProjectConfig) string {
+	if project != nil && project.Agent != "" {
+		return project.Agent
+	}
+	if cfg != nil && cfg.General.DefaultAgent != "" {
+		return cfg.General.DefaultAgent
+	}
+	return "claude"
+}
+
+// listGoblins displays all active goblins, or with archived only the
+// archived ones. Goblins keep their number from the full list, so it still
+// works as a reference either way.
+func listGoblins(output string, stat, archived bool) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+
+	goblins, err := fetchGoblins()
+	if err != nil {
+		return fmt.Errorf("failed to list goblins: %w", err)
+	}
+
+	var shown []*coordinator.Goblin
+	var numbers []int
+	for i, g := range goblins {
+		if (g.Status == coordinator.StatusArchived) == archived {
+			shown = append(shown, g)
+			numbers = append(numbers, i+1)
+		}
+	}
+	goblins = shown
+
+	if len(goblins) == 0 && archived && output == table.Text {
+		fmt.Println("No archived goblins.")
+		return nil
+	}
+	if len(goblins) == 0 && output == table.Text {
+		fmt.Println("No active goblins.")
+		fmt.Println()
+		fmt.Println("Spawn one with: gforge spawn <name> --agent <agent>")
+		return nil
+	}
+
+	// Aliases and sessions are only known locally
+	var aliases map[string][]string
+	if remote == nil {
+		coord := coordinator.New(db, cfg, log)
+		aliases, _ = coord.Aliases()
+		coord.CheckSessions(goblins)
+	}
+
+	header := []string{"ID", "NAME", "AGENT", "STATUS", "BRANCH", "AGE"}
+	var stats []string
+	if stat {
+		header = append(header, "CHANGES")
+		stats = worktreeStats(goblins)
+	}
+
+	t := table.New(header...)
+	t.Paint(3, style.Status)
+	for i, g := range goblins {
+		name := g.Name
+		if a := aliases[g.ID]; len(a) > 0 {
+			name = fmt.Sprintf("%s (%s)", g.Name, strings.Join(a, ", "))
+		}
+		if g.Pinned {
+			name = pinMark + name
+		}
+		row := []string{strconv.Itoa(numbers[i]), name, g.Agent, g.Status, g.Branch, g.Age()}
+		if stat {
+			row = append(row, stats[i])
+		}
+		t.Add(row...)
+	}
+
+	if err := t.Write(os.Stdout, output); err != nil {
+		return err
+	}
+	if remote == nil && !archived && output == table.Text {
+		printOverlaps(currentOverlaps(coordinator.New(db, cfg, log), goblins))
+	}
+	return nil
+}
+
+// currentOverlaps returns the goblins changing the same files: those the
+// daemon recorded at its last check when it is running, so listings don't
+// run git in every worktree, and otherwise compared now
+func currentOverlaps(coord *coordinator.Coordinator, goblins []*coordinator.Goblin) []coordinator.Overlap {
+	if cfg.Daemon.OverlapInterval > 0 && daemon.Running(cfg.DaemonPIDFile) {
+		if overlaps, err := coord.RecordedOverlaps(goblins); err == nil {
+			return overlaps
+		}
+	}
+	return coord.Overlaps(goblins)
+}
+
+// printOverlaps warns about goblins changing the same files, whose
+// branches are likely to conflict when merged
+func printOverlaps(overlaps []coordinator.Overlap) {
+	if len(overlaps) == 0 {
+		return
+	}
+	fmt.Println()
+	for _, o := range overlaps {
+		fmt.Println(style.Paint(style.Current().Warning, "⚠ "+o.String()))
+	}
+}
+
+// pinMark flags pinned goblins in listings
+const pinMark = "* "
+
+// pinGoblin pins or unpins a goblin
+func pinGoblin(name string, pinned bool) error {
+	if remote != nil {
+		return fmt.Errorf("pin is not supported with --server")
+	}
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	goblin, err := coordinator.New(db, cfg, log).Pin(name, pinned)
+	if err != nil {
+		return err
+	}
+	if pinned {
+		fmt.Printf("Pinned %s\n", goblin.Name)
+	} else {
+		fmt.Printf("Unpinned %s\n", goblin.Name)
+	}
+	return nil
+}
+
+// lockPaths claims paths in a goblin's project
+func lockPaths(name string, paths []string) error {
+	if remote != nil {
+		return fmt.Errorf("lock is not supported with --server")
+	}
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+
+	locked, err := coord.Lock(goblin, paths)
+	if err != nil {
+		return err
+	}
+	for _, path := range locked {
+		fmt.Printf("Locked %s for %s\n", path, goblin.Name)
+	}
+	return nil
+}
+
+// unlockPaths releases a goblin's locks on paths, or all of them
+func unlockPaths(name string, paths []string) error {
+	if remote != nil {
+		return fmt.Errorf("unlock is not supported with --server")
+	}
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+
+	n, err := coord.Unlock(goblin, paths)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		fmt.Printf("%s holds no such locks\n", goblin.Name)
+		return nil
+	}
+	fmt.Printf("Released %d lock(s) held by %s\n", n, goblin.Name)
+	return nil
+}
+
+// listLocks prints the paths goblins have locked, optionally in one
+// project
+func listLocks(project, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+	if remote != nil {
+		return fmt.Errorf("locks is not supported with --server")
+	}
+	if project != "" {
+		abs, err := filepath.Abs(project)
+		if err != nil {
+			return fmt.Errorf("invalid project path: %w", err)
+		}
+		project = abs
+	}
+
+	locks, err := coordinator.New(db, cfg, log).Locks(project)
+	if err != nil {
+		return err
+	}
+
+	t := table.New("PROJECT", "PATH", "GOBLIN", "SINCE")
+	for _, l := range locks {
+		t.Add(l.Project, l.Path, l.GoblinName, l.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return t.Write(os.Stdout, output)
+}
+
+// warnLocks warns about locks other goblins hold on the paths a task
+// names, and whether the task will wait for them
+func warnLocks(conflicts []*storage.PathLock) {
+	for _, l := range conflicts {
+		fmt.Fprintf(os.Stderr, "Warning: %s is locked by %s\n", l.Path, l.GoblinName)
+	}
+	if len(conflicts) > 0 && cfg.Locks.OnConflict == coordinator.LockConflictQueue {
+		fmt.Fprintf(os.Stderr, "Queueing the task until the locks are released (at most %s)...\n", cfg.Locks.MaxWait)
+	}
+}
+
+// padSquad picks the scratchpad a pad command works on: squad when
+// given, else that of the goblin named by ref, GFORGE_GOBLIN (set in
+// goblin sessions) or the worktree containing the current directory. The
+// goblin is returned too, to attribute writes to, unless squad was given.
+func padSquad(ref, squad string) (*coordinator.Coordinator, string, *coordinator.Goblin, error) {
+	if remote != nil {
+		return nil, "", nil, fmt.Errorf("pad is not supported with --server")
+	}
+	if squad != "" {
+		if ref != "" {
+			return nil, "", nil, fmt.Errorf("use --goblin or --squad, not both")
+		}
+		return coordinator.New(db, cfg, log), squad, nil, nil
+	}
+
+	if ref == "" {
+		ref = os.Getenv("GFORGE_GOBLIN")
+	}
+	if ref == "" {
+		ref = "."
+	}
+	coord, goblin, err := lookupGoblin(ref)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("%w (pick a scratchpad with --goblin or --squad)", err)
+	}
+	return coord, coordinator.SquadOf(goblin), goblin, nil
+}
+
+// listPad prints a squad's scratchpad and who shares it
+func listPad(ref, squad, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+	coord, squad, _, err := padSquad(ref, squad)
+	if err != nil {
+		return err
+	}
+
+	entries, err := coord.Pad(squad)
+	if err != nil {
+		return err
+	}
+	if output == "table" {
+		members, err := coord.SquadMembers(squad)
+		if err != nil {
+			return err
+		}
+		names := make([]string, len(members))
+		for i, g := range members {
+			names[i] = g.Name
+		}
+		fmt.Printf("Squad: %s", squad)
+		if len(names) > 0 {
+			fmt.Printf(" (%s)", strings.Join(names, ", "))
+		}
+		fmt.Println()
+		if len(entries) == 0 {
+			fmt.Println("The scratchpad is empty. Write to it with: gforge pad set <key> <value>")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	t := table.New("KEY", "BY", "UPDATED", "VALUE")
+	for _, e := range entries {
+		by := e.GoblinName
+		if by == "" {
+			by = "-"
+		}
+		t.Add(e.Key, by, e.UpdatedAt.Local().Format("2006-01-02 15:04"), firstLine(e.Value, 60))
+	}
+	return t.Write(os.Stdout, output)
+}
+
+// getPad prints the value of a scratchpad key as written
+func getPad(ref, squad, key string) error {
+	coord, squad, _, err := padSquad(ref, squad)
+	if err != nil {
+		return err
+	}
+	entry, err := coord.PadGet(squad, key)
+	if err != nil {
+		return err
+	}
+	fmt.Print(entry.Value)
+	if !strings.HasSuffix(entry.Value, "\n") {
+		fmt.Println()
+	}
+	return nil
+}
+
+// setPad writes a scratchpad key, reading the value from stdin when it
+// is "-"
+func setPad(ref, squad, key, value string) error {
+	coord, squad, goblin, err := padSquad(ref, squad)
+	if err != nil {
+		return err
+	}
+	if value == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		value = string(data)
+	}
+	if err := coord.PadSet(squad, key, value, goblin); err != nil {
+		return err
+	}
+	fmt.Printf("Set %s in the scratchpad of %s\n", key, squad)
+	return nil
+}
+
+// deletePad removes scratchpad keys
+func deletePad(ref, squad string, keys []string) error {
+	coord, squad, goblin, err := padSquad(ref, squad)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := coord.PadDelete(squad, key, goblin); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %s from the scratchpad of %s\n", key, squad)
+	}
+	return nil
+}
+
+// archiveGoblin shelves a goblin, freeing its session and worktree
+func archiveGoblin(name string) error {
+	if remote != nil {
+		return fmt.Errorf("archive is not supported with --server")
+	}
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	goblin, err := coord.Archive(name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Archived %s to %s\n", goblin.Name, coord.ArchivePath(goblin))
+	return nil
+}
+
+// unarchiveGoblin restores an archived goblin's worktree
+func unarchiveGoblin(name string) error {
+	if remote != nil {
+		return fmt.Errorf("unarchive is not supported with --server")
+	}
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	goblin, err := coordinator.New(db, cfg, log).Unarchive(name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Restored %s to %s (stopped)\n", goblin.Name, goblin.WorktreePath)
+	return nil
+}
+
+// worktreeStats summarizes each goblin's uncommitted changes, less its
+// project's ignored files, through the stat cache and in parallel.
+// Worktrees on a remote server show "-".
+func worktreeStats(goblins []*coordinator.Goblin) []string {
+	stats := make([]string, len(goblins))
+	if remote != nil {
+		for i := range stats {
+			stats[i] = "-"
+		}
+		return stats
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	managers := make(map[string]*workspace.WorktreeManager)
+	cache := workspace.NewStatCache(cfg.StatCacheFile, cfg.General.StatCacheTTL)
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, 8)
+	for i, g := range goblins {
+		wsMgr, ok := managers[g.ProjectPath]
+		if !ok {
+			wsMgr = workspace.NewWorktreeManager(workspace.Config{BasePath: cfg.WorktreeBase, Ignore: coord.DiffIgnore(g)})
+			managers[g.ProjectPath] = wsMgr
+		}
+
+		wg.Add(1)
+		go func(i int, wsMgr *workspace.WorktreeManager, path string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			stats[i] = "-"
+			if _, stat, err := cache.Get(wsMgr, path); err == nil {
+				stats[i] = stat.String()
+			}
+		}(i, wsMgr, g.WorktreePath)
+	}
+	wg.Wait()
+
+	// A cache that can't be saved only costs the next listing time
+	cache.Save()
+	return stats
+}
+
+// listEvents prints the activity log for the last window, optionally for
+// one goblin
+func listEvents(name string, since time.Duration, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+
+	events, err := db.ListEvents(time.Now().Add(-since))
+	if err != nil {
+		return err
+	}
+
+	t := table.New("TIME", "GOBLIN", "EVENT", "DETAIL")
+	for _, e := range events {
+		if name != "" && e.GoblinName != name && e.GoblinID != name {
+			continue
+		}
+		detail := e.Detail
+		if output == table.Text {
+			detail = firstLine(detail, 60)
+		}
+		t.Add(e.CreatedAt.Local().Format("2006-01-02 15:04:05"), e.GoblinName, e.Type, detail)
+	}
+
+	return t.Write(os.Stdout, output)
+}
+
+// listHistory prints killed goblins, most recent first, optionally only
+// those with a name or agent
+func listHistory(name, agent string, since time.Duration, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+	if remote != nil {
+		return fmt.Errorf("history is not supported with --server")
+	}
+
+	tombstones, err := coordinator.New(db, cfg, log).History(time.Now().Add(-since))
+	if err != nil {
+		return err
+	}
+
+	t := table.New("ENDED", "NAME", "AGENT", "OUTCOME", "DURATION", "CHANGES")
+	t.Paint(3, style.Status)
+	for _, tomb := range tombstones {
+		if (name != "" && tomb.Name != name && tomb.GoblinID != name) || (agent != "" && tomb.Agent != agent) {
+			continue
+		}
+		changes := workspace.DiffStat{Files: tomb.Files, Insertions: tomb.Insertions, Deletions: tomb.Deletions}
+		t.Add(tomb.EndedAt.Local().Format("2006-01-02 15:04"), tomb.Name, tomb.Agent, tomb.Outcome,
+			coordinator.FormatDuration(tomb.Duration()), changes.String())
+	}
+
+	return t.Write(os.Stdout, output)
+}
+
+// listTasks prints the tasks sent to a goblin in order
+func listTasks(name, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+	if remote != nil {
+		return fmt.Errorf("tasks is not supported with --server")
+	}
+
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+	goblin, err := coordinator.New(db, cfg, log).Get(name)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	tasks, err := db.ListTasks(goblin.ID)
+	if err != nil {
+		return err
+	}
+	queue, err := db.ListTaskQueue(goblin.ID)
+	if err != nil {
+		return err
+	}
+
+	t := table.New("#", "SENT", "TASK")
+	for i, task := range tasks {
+		text := task.Task
+		if output == table.Text {
+			text = firstLine(text, 80)
+		}
+		t.Add(strconv.Itoa(i+1), task.StartedAt.Local().Format("2006-01-02 15:04:05"), text)
+	}
+	// Queued tasks follow, in the order they will be sent
+	for _, q := range queue {
+		text := q.Task
+		if output == table.Text {
+			text = firstLine(text, 80)
+		}
+		t.Add("-", "queued", text)
+	}
+
+	return t.Write(os.Stdout, output)
+}
+
+// dropQueuedTasks drops the tasks queued for a goblin
+func dropQueuedTasks(name string) error {
+	if remote != nil {
+		return fmt.Errorf("tasks is not supported with --server")
+	}
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+
+	n, err := coord.ClearTaskQueue(goblin)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		fmt.Printf("No tasks queued for %s\n", goblin.Name)
+		return nil
+	}
+	fmt.Printf("Dropped %d queued task(s) for %s\n", n, goblin.Name)
+	return nil
+}
+
+// showTask shows one of a goblin's tasks, by its number in gforge tasks,
+// with the diff it produced
+func showTask(name, number string) error {
+	if remote != nil {
+		return fmt.Errorf("tasks is not supported with --server")
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid task number: %s (see gforge tasks)", number)
+	}
+
+	name, err = resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+	coord := coordinator.New(db, cfg, log)
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	tasks, err := db.ListTasks(goblin.ID)
+	if err != nil {
+		return err
+	}
+	if n > len(tasks) {
+		return fmt.Errorf("%s has %d task(s); no task %d", goblin.Name, len(tasks), n)
+	}
+	task := tasks[n-1]
+
+	fmt.Printf("Task %d of %s, sent %s\n", n, goblin.Name, task.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	if task.EndedAt.IsZero() {
+		fmt.Println("Still open")
+	} else {
+		fmt.Printf("Ended %s after %s\n", task.EndedAt.Local().Format("2006-01-02 15:04:05"), task.EndedAt.Sub(task.StartedAt).Round(time.Second))
+	}
+	if task.StartCommit != "" {
+		end := "worktree"
+		if !task.EndedAt.IsZero() {
+			end = shortCommit(task.EndCommit)
+		}
+		fmt.Printf("Commits %s..%s\n", shortCommit(task.StartCommit), end)
+	}
+	fmt.Printf("\n%s\n\n", task.Task)
+
+	diff, err := coord.TaskDiff(goblin, task)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Println("No changes")
+		return nil
+	}
+	printDiff(diff)
+	return nil
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
+
+// addNote attaches an observation, given as words, to a goblin
+func addNote(name string, words []string) error {
+	if remote != nil {
+		return fmt.Errorf("note is not supported with --server")
+	}
+	body := strings.TrimSpace(strings.Join(words, " "))
+	if body == "" {
+		return fmt.Errorf("note is empty")
+	}
+
+	_, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+	if _, err := db.AddNote(goblin.ID, body); err != nil {
+		return err
+	}
+	fmt.Printf("Noted on %s\n", goblin.Name)
+	return nil
+}
+
+// listNotes prints the notes on a goblin, oldest first
+func listNotes(name, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+	if remote != nil {
+		return fmt.Errorf("notes is not supported with --server")
+	}
+
+	_, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+
+	notes, err := db.ListNotes(goblin.ID)
+	if err != nil {
+		return err
+	}
+
+	t := table.New("#", "WRITTEN", "NOTE")
+	for i, n := range notes {
+		t.Add(strconv.Itoa(i+1), n.CreatedAt.Local().Format("2006-01-02 15:04:05"), n.Body)
+	}
+
+	return t.Write(os.Stdout, output)
+}
+
+// listArtifacts lists the files collected from a goblin, collecting the
+// project's artifacts first with collect
+func listArtifacts(name string, collect bool, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+	if remote != nil {
+		return fmt.Errorf("artifacts is not supported with --server")
+	}
+
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+
+	var artifacts []coordinator.Artifact
+	if collect {
+		project, err := config.LoadProject(goblin.ProjectPath)
+		if err != nil {
+			return err
+		}
+		if len(project.Artifacts) == 0 {
+			return fmt.Errorf("no artifacts configured; add artifacts to %s", filepath.Join(goblin.ProjectPath, config.ProjectFile))
+		}
+		artifacts, err = coord.CollectArtifacts(goblin, project.Artifacts)
+	} else {
+		artifacts, err = coord.Artifacts(goblin)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(artifacts) == 0 && output == "table" {
+		fmt.Printf("No artifacts collected from %s\n", goblin.Name)
+		return nil
+	}
+
+	t := table.New("PATH", "SIZE", "MODIFIED")
+	for _, a := range artifacts {
+		t.Add(a.Path, repomap.FormatSize(a.Size), a.ModTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	if err := t.Write(os.Stdout, output); err != nil {
+		return err
+	}
+	if output == "table" {
+		fmt.Printf("\nIn %s\n", coord.ArtifactsPath(goblin))
+	}
+	return nil
+}
+
+// firstLine shortens text to its first line, at most max characters
+func firstLine(text string, max int) string {
+	line, _, more := strings.Cut(strings.TrimSpace(text), "\n")
+	if r := []rune(line); len(r) > max {
+		return string(r[:max-3]) + "..."
+	} else if more {
+		return line + " ..."
+	}
+	return line
+}
+
+// fetchGoblins lists goblins from the remote server or the local database
+func fetchGoblins() ([]*coordinator.Goblin, error) {
+	if remote == nil {
+		return coordinator.New(db, cfg, log).List()
+	}
+
+	remoteGoblins, err := remote.ListGoblins()
+	if err != nil {
+		return nil, err
+	}
+
+	goblins := make([]*coordinator.Goblin, len(remoteGoblins))
+	for i, g := range remoteGoblins {
+		goblins[i] = &coordinator.Goblin{
+			ID:           g.ID,
+			Name:         g.Name,
+			Agent:        g.Agent,
+			Status:       g.Status,
+			ProjectPath:  g.ProjectPath,
+			WorktreePath: g.WorktreePath,
+			Branch:       g.Branch,
+			TmuxSession:  g.TmuxSession,
+			TmuxSocket:   g.TmuxSocket,
+			Pinned:       g.Pinned,
+			Squad:        g.Squad,
+			CreatedAt:    g.CreatedAt,
+			UpdatedAt:    g.UpdatedAt,
+		}
+	}
+	return goblins, nil
+}
+
+// stopGoblin stops a running goblin
+func stopGoblin(name string) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	if remote != nil {
+		err = remote.Stop(name)
+	} else {
+		err = coordinator.New(db, cfg, log).Stop(name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stop goblin: %w", err)
+	}
+
+	fmt.Printf("Stopped goblin: %s\n", name)
+	return nil
+}
+
+// showStatus displays system status
+func showStatus() error {
+	coord := coordinator.New(db, cfg, log)
+
+	// Get stats
+	stats, err := coord.Stats()
+	if err != nil {
+		return fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	fmt.Println("Goblin Forge Status")
+	fmt.Println("===================")
+	fmt.Println()
+	fmt.Printf("Goblins:\n")
+	fmt.Printf("  Running:   %d\n", stats.Running)
+	fmt.Printf("  Paused:    %d\n", stats.Paused)
+	fmt.Printf("  Completed: %d\n", stats.Completed)
+	if stats.Throttled > 0 {
+		fmt.Printf("  Throttled: %d\n", stats.Throttled)
+	}
+	fmt.Printf("  Total:     %d\n", stats.Total)
+	if goblins, err := coord.List(); err == nil {
+		printOverlaps(currentOverlaps(coord, goblins))
+	}
+	fmt.Println()
+	fmt.Printf("System:\n")
+	fmt.Printf("  Config:    %s\n", config.GetConfigPath(cfgFile))
+	fmt.Printf("  Database:  %s\n", cfg.DatabasePath)
+	fmt.Printf("  Worktrees: %s\n", cfg.WorktreeBase)
+	if cfg.Ollama.ManageServer {
+		server := "not running"
+		if coord.OllamaManaged() {
+			server = "running (tmux session " + coordinator.OllamaSession + ")"
+		}
+		fmt.Printf("  Ollama:    %s\n", server)
+	}
+	if policy, err := hours.New(cfg.WorkingHours); err != nil {
+		fmt.Printf("  Hours:     %v\n", err)
+	} else if policy != nil {
+		now := time.Now()
+		if policy.Open(now) {
+			fmt.Printf("  Hours:     open until %s\n", policy.Close(now).Format("Mon 15:04"))
+		} else {
+			fmt.Printf("  Hours:     closed until %s (autonomous work deferred)\n", policy.Next(now).Format("Mon 15:04"))
+		}
+	}
+
+	gpus := gpu.Detect()
+	if len(gpus) > 0 {
+		fmt.Println()
+		fmt.Printf("GPUs: %d (local goblin limit %d)\n", len(gpus), coord.LocalLimit())
+		for _, g := range gpus {
+			fmt.Printf("  - %s (%d/%d MB used)\n", g.Name, g.UsedMB, g.MemoryMB)
+		}
+	}
+
+	// Check for installed agents
+	registry := agents.NewRegistry()
+	detected := registry.ScanCached(cfg.AgentScanFile, cfg.General.AgentScanTTL)
+	fmt.Println()
+	fmt.Printf("Agents: %d installed\n", len(detected))
+	for _, d := range detected {
+		fmt.Printf("  - %s (%s)\n", d.Name, d.Version)
+	}
+
+	return showToolchains(coord)
+}
+
+// parseSince parses a window like 90m, 12h, 7d or 2w
+func parseSince(s string) (time.Duration, error) {
+	unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if n := len(s); n > 1 && unit[s[n-1]] > 0 {
+		count, err := strconv.Atoi(s[:n-1])
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid window: %s", s)
+		}
+		return time.Duration(count) * unit[s[n-1]], nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window: %s (e.g. 12h, 7d, 2w)", s)
+	}
+	return d, nil
+}
+
+// showStatsHistory charts goblin counts and task outcomes over a window
+func showStatsHistory(window time.Duration, width int) error {
+	if width <= 0 {
+		return fmt.Errorf("width must be positive")
+	}
+
+	end := time.Now()
+	start := end.Add(-window)
+
+	coord := coordinator.New(db, cfg, log)
+	snapshots, err := coord.StatsHistory(start)
+	if err != nil {
+		return err
+	}
+	events, err := db.ListEvents(start)
+	if err != nil {
+		return err
+	}
+	tombstones, err := coord.History(start)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Goblin activity, last %s (%s per column)\n\n", formatWindow(window), formatWindow(window/time.Duration(width)))
+
+	series := func(value func(s *storage.StatsSnapshot) int) []int {
+		samples := make([]chart.Sample, len(snapshots))
+		for i, s := range snapshots {
+			samples[i] = chart.Sample{At: s.TakenAt, Value: value(s)}
+		}
+		return chart.Peak(samples, start, end, width)
+	}
+	counts := func(eventType string) []int {
+		var times []time.Time
+		for _, e := range events {
+			if e.Type == eventType {
+				times = append(times, e.CreatedAt)
+			}
+		}
+		return chart.Count(times, start, end, width)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(snapshots) == 0 {
+		fmt.Fprintln(w, "Running\t(no snapshots yet; see stats.snapshot_interval)")
+	} else {
+		for _, row := range []struct {
+			label  string
+			values []int
+		}{
+			{"Running", series(func(s *storage.StatsSnapshot) int { return s.Running })},
+			{"Paused", series(func(s *storage.StatsSnapshot) int { return s.Paused })},
+			{"Throttled", series(func(s *storage.StatsSnapshot) int { return s.Throttled })},
+		} {
+			fmt.Fprintf(w, "%s\t%s\tpeak %d\n", row.label, chart.Sparkline(row.values), peak(row.values))
+		}
+	}
+	for _, row := range []struct {
+		label string
+		event string
+	}{
+		{"Completed", string(notify.EventTaskComplete)},
+		{"Failures", string(notify.EventFailure)},
+	} {
+		values := counts(row.event)
+		fmt.Fprintf(w, "%s\t%s\t%d total\n", row.label, chart.Sparkline(values), sum(values))
+	}
+
+	// Killed goblins, from their tombstones
+	ended := make([]time.Time, len(tombstones))
+	lifetimes := make([]time.Duration, len(tombstones))
+	for i, t := range tombstones {
+		ended[i] = t.EndedAt
+		lifetimes[i] = t.Duration()
+	}
+	values := chart.Count(ended, start, end, width)
+	summary := fmt.Sprintf("%d total", sum(values))
+	if len(lifetimes) > 0 {
+		sort.Slice(lifetimes, func(i, j int) bool { return lifetimes[i] < lifetimes[j] })
+		summary += ", median run " + coordinator.FormatDuration(lifetimes[len(lifetimes)/2])
+	}
+	fmt.Fprintf(w, "Killed\t%s\t%s\n", chart.Sparkline(values), summary)
+	return w.Flush()
+}
+
+// formatWindow prints a duration to the minute, with whole days as 7d
+// and whole hours as 12h
+func formatWindow(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return strings.TrimSuffix(d.String(), "0s")
+}
+
+func peak(values []int) int {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// showToolchains displays toolchain versions per goblin worktree and warns
+// about tools the detected project type needs but cannot find
+func showToolchains(coord *coordinator.Coordinator) error {
+	goblins, err := coord.List()
+	if err != nil {
+		return fmt.Errorf("failed to list goblins: %w", err)
+	}
+
+	detector := template.NewDetector()
+
+	fmt.Println()
+	fmt.Println("Toolchains:")
+	if len(goblins) == 0 {
+		fmt.Printf("  %s\n", formatToolchains(workspace.DetectToolchains(".")))
+		return nil
+	}
+
+	// Probe worktrees in parallel, printing in list order
+	type result struct {
+		toolchains  []workspace.Toolchain
+		projectType string
+	}
+	results := make([]*result, len(goblins))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, 4)
+	for i, g := range goblins {
+		if _, err := os.Stat(g.WorktreePath); err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			projectType, _ := detector.Detect(path)
+			results[i] = &result{workspace.DetectToolchains(path), projectType}
+		}(i, g.WorktreePath)
+	}
+	wg.Wait()
+
+	for i, g := range goblins {
+		r := results[i]
+		if r == nil {
+			continue
+		}
+
+		label := g.Name
+		if r.projectType != "" {
+			label = fmt.Sprintf("%s [%s]", g.Name, r.projectType)
+		}
+		fmt.Printf("  %s: %s\n", label, formatToolchains(r.toolchains))
+
+		if missing := workspace.MissingToolchains(r.projectType, r.toolchains); len(missing) > 0 {
+			fmt.Printf("    %s\n", style.Paint(style.Current().Warning, "Warning: missing "+strings.Join(missing, ", ")))
+		}
+	}
+
+	return nil
+}
+
+// formatToolchains renders the found toolchains as "name version" pairs
+func formatToolchains(toolchains []workspace.Toolchain) string {
+	var parts []string
+	for _, tc := range toolchains {
+		if tc.Found {
+			parts = append(parts, fmt.Sprintf("%s %s", tc.Name, tc.Version))
+		}
+	}
+	if len(parts) == 0 {
+		return "none found"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// killGoblin forcefully terminates a goblin and cleans up resources
+func killGoblin(name string) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	if remote != nil {
+		err = remote.Kill(name)
+	} else {
+		err = coordinator.New(db, cfg, log).Kill(name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to kill goblin: %w", err)
+	}
+
+	fmt.Printf("Killed goblin: %s\n", name)
+	fmt.Println("  tmux session terminated")
+	fmt.Println("  worktree removed")
+	return nil
+}
+
+// pauseGoblin suspends (pause) or continues a goblin
+func pauseGoblin(name string, pause bool) error {
+	if remote != nil {
+		return fmt.Errorf("pause and resume are not supported with --server")
+	}
+
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	if pause {
+		if err := coord.Pause(name); err != nil {
+			return fmt.Errorf("failed to pause goblin: %w", err)
+		}
+		fmt.Printf("Paused goblin: %s\n", name)
+		return nil
+	}
+
+	if err := coord.Resume(name); err != nil {
+		return fmt.Errorf("failed to resume goblin: %w", err)
+	}
+	fmt.Printf("Resumed goblin: %s\n", name)
+	return nil
+}
+
+// attachGoblin attaches to a goblin's tmux session
+func attachGoblin(name, mode string) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	// Inside tmux the session opens beside this one; nothing to read first
+	if os.Getenv("TMUX") == "" {
+		fmt.Printf("Attaching to goblin: %s\n", name)
+		fmt.Println("Use Ctrl+B D to detach")
+		fmt.Println()
+
+		// Small delay for user to read
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return coord.Attach(name, mode)
+}
+
+// showLogs displays goblin output logs
+func showLogs(name string, lines int, follow bool, output string) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	if remote != nil {
+		if follow || output != "" {
+			return fmt.Errorf("--follow and --output are not supported with --server")
+		}
+		captured, err := remote.Logs(name, lines)
+		if err != nil {
+			return fmt.Errorf("failed to get logs: %w", err)
+		}
+		fmt.Printf("=== Logs: %s (last %d lines) ===\n\n", name, lines)
+		fmt.Println(strings.Join(captured, "\n"))
+		return nil
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	// Create tmux manager to get output
+	tmuxMgr := tmux.NewManager(tmux.Config{
+		SocketName: coord.Socket(goblin),
+	})
+
+	if follow {
+		// Follow mode - continuously capture pane
+		fmt.Printf("Following logs for %s (Ctrl+C to stop)...\n\n", name)
+		lastOutput := ""
+		for {
+			captured, err := tmuxMgr.CapturePane(goblin.TmuxSession, lines)
+			if err != nil {
+				return fmt.Errorf("failed to capture output: %w", err)
+			}
+
+			// Only print new content
+			if captured != lastOutput {
+				// Clear screen and print
+				fmt.Print("\033[2J\033[H") // Clear screen, move to top
+				fmt.Printf("=== Logs: %s ===\n\n", name)
+				fmt.Println(captured)
+				lastOutput = captured
+			}
+
+			time.Sleep(500 * time.Millisecond)
+		}
+	} else {
+		// One-shot capture
+		captured, err := tmuxMgr.CapturePane(goblin.TmuxSession, lines)
+		if err != nil {
+			return fmt.Errorf("failed to capture output: %w", err)
+		}
+
+		if output != "" {
+			return writeCapture(output, goblin, captured)
+		}
+		fmt.Printf("=== Logs: %s (last %d lines) ===\n\n", name, lines)
+		fmt.Println(captured)
+	}
+
+	return nil
+}
+
+// capturePaneHistory prints a goblin's tmux scrollback, or writes it to
+// output. It reads the pane directly, so it works without recording.
+func capturePaneHistory(name string, lines int, output string) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	tmuxMgr := tmux.NewManager(tmux.Config{
+		SocketName:   coord.Socket(goblin),
+		HistoryLimit: cfg.Tmux.HistoryLimit,
+	})
+
+	captured, err := tmuxMgr.CaptureHistory(goblin.TmuxSession, lines)
+	if err != nil {
+		return fmt.Errorf("failed to capture output: %w", err)
+	}
+
+	if output != "" {
+		return writeCapture(output, goblin, captured)
+	}
+	fmt.Print(captured)
+	return nil
+}
+
+// writeCapture saves captured pane output to a file, followed by the
+// goblin's notes
+func writeCapture(path string, goblin *coordinator.Goblin, captured string) error {
+	notes, err := db.ListNotes(goblin.ID)
+	if err != nil {
+		return err
+	}
+
+	content := captured
+	if len(notes) > 0 {
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n=== Notes ===\n"
+		for _, n := range notes {
+			content += fmt.Sprintf("[%s] %s\n", n.CreatedAt.Local().Format("2006-01-02 15:04:05"), n.Body)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Printf("Wrote %d lines from %s to %s\n", strings.Count(captured, "\n"), goblin.Name, path)
+	return nil
+}
+
+// costReport prints usage and cost totals for the last window
+func costReport(since time.Duration, by, output string) error {
+	records, err := db.ListUsage(time.Now().Add(-since))
+	if err != nil {
+		return err
+	}
+
+	rows, err := usage.Aggregate(records, by)
+	if err != nil {
+		return err
+	}
+
+	return usage.Write(os.Stdout, rows, by, output)
+}
+
+// showSummary prints a goblin's output summary, refreshing it first
+// unless cached is set
+func showSummary(name string, cached bool) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	var summary *storage.Summary
+	if cached {
+		summary, err = db.GetSummary(goblin.ID)
+		if err != nil {
+			return err
+		}
+		if summary == nil {
+			return fmt.Errorf("no summary for %s yet; run gforge summary %s", goblin.Name, goblin.Name)
+		}
+	} else {
+		s, err := summarize.New(cfg.Summarizer)
+		if err != nil {
+			return err
+		}
+		summary, err = coord.Summarize(context.Background(), goblin.ID, s)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("=== Summary: %s (updated %s) ===\n\n", goblin.Name, summary.UpdatedAt.Local().Format("Jan 2 15:04"))
+	fmt.Println(summary.Summary)
+
+	return nil
+}
+
+// askAgent streams a one-shot answer from an agent to stdout
+func askAgent(agentName, question string, stdin bool) error {
+	if agentName == "" {
+		project, err := config.LoadProject(".")
+		if err != nil {
+			return err
+		}
+		agentName = defaultAgent(project)
+	}
+
+	agent := agents.NewRegistry().Get(agentName)
+	if agent == nil {
+		return fmt.Errorf("unknown agent: %s", agentName)
+	}
+
+	var input io.Reader
+	if stdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+
+		// Keep the question and input within the agent's context window
+		text := string(data)
+		if budget := coordinator.New(nil, cfg, nil).PromptBudget(agentName); budget > 0 {
+			text = tokens.TruncateMiddle(text, budget-tokens.Count(question))
+		}
+		input = strings.NewReader(text)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return agents.Ask(ctx, agent, question, input, os.Stdout, os.Stderr)
+}
+
+// pushOptions are gforge push's flags
+type pushOptions struct {
+	remote                      string
+	forceWithLease              bool
+	pr, draft                   bool
+	base                        string
+	allowProtected, allowFreeze bool
+	allowSecrets, allowLarge    bool
+	confirmDeps                 bool
+	skipChecks, noChangelog     bool
+}
+
+// pushGoblin pushes a goblin's branch with the project's git credentials
+// and opens a pull request when asked
+func pushGoblin(name string, opts pushOptions) error {
+	if remote != nil {
+		return fmt.Errorf("push is not supported with --server")
+	}
+
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	project, err := config.LoadProject(goblin.ProjectPath)
+	if err != nil {
+		return err
+	}
+	pushRemote, prRemote := pushRemotes(project, opts.remote)
+	policy := coord.BranchPolicy(project)
+	if opts.allowProtected {
+		policy = workspace.BranchPolicy{}
+	}
+	if opts.pr {
+		if err := policy.CheckPR(goblin.Branch, opts.base); err != nil {
+			return err
+		}
+	}
+
+	// Nothing lands during a release freeze or maintenance window
+	if err := coord.CheckFreeze(goblin, "push"); err != nil {
+		if !opts.allowFreeze {
+			return fmt.Errorf("%w; push after it ends, or with --allow-freeze", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: pushing anyway (--allow-freeze): %v\n", err)
+	}
+
+	// Credentials are caught before they reach a remote, where taking them
+	// back means rewriting history and rotating them anyway
+	findings, err := coord.CheckSecrets(goblin)
+	if len(findings) > 0 {
+		printSecretFindings(findings)
+	}
+	if err != nil {
+		if !opts.allowSecrets {
+			if errors.Is(err, errs.ErrSecretsFound) {
+				return fmt.Errorf("%w; remove them, or push with --allow-secrets if they are not real", err)
+			}
+			return fmt.Errorf("failed to scan for secrets: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: pushing anyway (--allow-secrets): %v\n", err)
+	}
+
+	// Branches too big to review are better split into stacked goblins,
+	// each building on the previous one's branch
+	if _, over, err := coord.CheckDiffSize(goblin, coord.DiffLimits(project)); err != nil {
+		if !opts.allowLarge || !errors.Is(err, errs.ErrDiffTooLarge) {
+			if errors.Is(err, errs.ErrDiffTooLarge) {
+				return fmt.Errorf("%w; split the work into smaller goblins, or push with --allow-large", err)
+			}
+			return fmt.Errorf("failed to measure diff: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: pushing anyway (--allow-large): %v\n", err)
+	} else if len(over) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %s changes %s; consider splitting it into smaller goblins\n",
+			goblin.Name, strings.Join(over, " and "))
+	}
+
+	// New dependencies and licenses get a human look before they're shared
+	if cfg.Git.ConfirmDeps {
+		report, err := coord.CheckDependencies(goblin)
+		if err != nil {
+			return fmt.Errorf("failed to check dependencies: %w", err)
+		}
+		if !report.Empty() && !opts.confirmDeps {
+			printDependencyReport(report)
+			if !confirm("Push with these dependency and license changes?") {
+				return fmt.Errorf("%s changes dependencies or licenses; review them and push with --confirm-deps", goblin.Name)
+			}
+		}
+	}
+
+	if !opts.skipChecks && len(project.Checks.Commands) > 0 {
+		fmt.Printf("Running %d check(s) in %s...\n", len(project.Checks.Commands), goblin.Name)
+		results, err := coord.RunChecks(goblin, project.Checks, project.Git.Env())
+		printCheckResults(results)
+		if err != nil {
+			if errors.Is(err, errs.ErrChecksFailed) && project.Checks.FixTask {
+				return fmt.Errorf("%w; the failures were sent to %s to fix", err, goblin.Name)
+			}
+			return err
+		}
+	}
+
+	if !opts.noChangelog {
+		path, err := coord.AddChangelog(goblin, project.Changelog, project.Git.Env())
+		if err != nil {
+			return fmt.Errorf("failed to add changelog entry: %w", err)
+		}
+		if path != "" {
+			fmt.Printf("Added changelog entry to %s\n", path)
+		}
+	}
+
+	wsMgr := workspace.NewWorktreeManager(workspace.Config{
+		BasePath: cfg.WorktreeBase,
+		Branches: policy,
+	})
+	branch, err := wsMgr.Push(goblin.WorktreePath, workspace.PushOptions{
+		Remote:         pushRemote,
+		ForceWithLease: opts.forceWithLease,
+		Env:            project.Git.Env(),
+	})
+	if err != nil {
+		return err
+	}
+	coord.RecordEvent(goblin.ID, coordinator.EventPushed, branch)
+
+	fmt.Printf("Pushed %s to %s\n", branch, pushRemote)
+	if url := workspace.BranchWebURL(goblin.WorktreePath, pushRemote, branch); url != "" {
+		fmt.Printf("  %s\n", url)
+	}
+
+	// The push is done; a workflow failing to start is only a warning
+	runs, err := coord.Dispatch(goblin, project.Dispatch, config.DispatchOnPush,
+		workspace.RemoteRepo(goblin.WorktreePath, pushRemote))
+	printDispatched(runs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if !opts.pr {
+		return nil
+	}
+
+	title := branch
+	if out, err := trace.Command("git", "-C", goblin.WorktreePath, "log", "-1", "--format=%s").Output(); err == nil {
+		title = strings.TrimSpace(string(out))
+	}
+
+	// A branch pushed to a fork becomes a cross-repository PR: the head is
+	// qualified with the fork's owner and the PR opened upstream
+	head := branch
+	if pushRemote != prRemote {
+		owner := workspace.RemoteOwner(goblin.WorktreePath, pushRemote)
+		if owner == "" {
+			return fmt.Errorf("cannot tell who owns remote %s to open a pull request from it", pushRemote)
+		}
+		head = owner + ":" + branch
+	}
+
+	gh := integrations.NewGitHubClient()
+	gh.Dir = goblin.WorktreePath
+	var reviewers []string
+	if cfg.Git.RequestReviews {
+		reviewers = codeOwnerReviewers(coord, gh, goblin)
+	}
+	body, err := prBody(goblin)
+	if err != nil {
+		return err
+	}
+	created, err := gh.CreatePR(head, integrations.PROptions{
+		Title:     title,
+		Body:      body,
+		Base:      opts.base,
+		Draft:     opts.draft,
+		Repo:      workspace.RemoteRepo(goblin.WorktreePath, prRemote),
+		Reviewers: reviewers,
+	})
+	if err != nil {
+		return err
+	}
+	coord.RecordEvent(goblin.ID, coordinator.EventPR, created.URL)
+	fmt.Printf("Opened pull request #%d\n", created.Number)
+	fmt.Printf("  %s\n", created.URL)
+	if len(reviewers) > 0 {
+		fmt.Printf("  Requested reviews from %s\n", strings.Join(reviewers, ", "))
+	}
+	return nil
+}
+
+// prBody fills the repository's pull request template, or the default
+// layout, from a goblin's commits, tasks and last check results
+func prBody(goblin *coordinator.Goblin) (string, error) {
+	var details integrations.PRDetails
+	commits, err := workspace.BranchCommits(goblin.WorktreePath, goblin.BaseRef)
+	if err != nil {
+		return "", err
+	}
+	details.Commits = commits
+
+	tasks, err := coordinator.New(db, cfg, log).WorkTasks(goblin)
+	if err != nil {
+		return "", err
+	}
+	details.Tasks = tasks
+
+	results, err := db.ListCheckResults(goblin.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range results {
+		status := "✓"
+		if !r.Passed {
+			status = "✗"
+		}
+		details.Tests = append(details.Tests, fmt.Sprintf("%s `%s` (%s)", status, r.Command, r.Duration.Round(100*time.Millisecond)))
+	}
+
+	body, err := integrations.BuildPRBody(goblin.WorktreePath, details)
+	if err != nil {
+		return "", err
+	}
+	body = strings.TrimRight(body, "\n")
+
+	// Collected files stay local; the PR says what there is to ask for
+	artifacts, err := coordinator.New(db, cfg, log).Artifacts(goblin)
+	if err != nil {
+		return "", err
+	}
+	if len(artifacts) > 0 {
+		body += "\n\n### Artifacts\n\nCollected by gforge from the goblin's worktree:\n"
+		for _, a := range artifacts {
+			body += fmt.Sprintf("\n- `%s` (%s)", a.Path, repomap.FormatSize(a.Size))
+		}
+	}
+
+	return fmt.Sprintf("%s\n\n---\nOpened by gforge from goblin %s (%s agent).\n",
+		body, goblin.Name, goblin.Agent), nil
+}
+
+// codeOwnerReviewers returns the code owners of a goblin's changes to
+// request reviews from, leaving out the PR's author. Failing to work them
+// out is a warning: the PR is still worth opening.
+func codeOwnerReviewers(coord *coordinator.Coordinator, gh *integrations.GitHubClient, goblin *coordinator.Goblin) []string {
+	owners, err := coord.CodeOwners(goblin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: not requesting reviews from code owners: %v\n", err)
+		return nil
+	}
+	if len(owners) == 0 {
+		return nil
+	}
+	author, _ := gh.CurrentUser()
+	return integrations.ReviewersFromOwners(owners, author)
+}
+
+// warnSignoff warns when the paths a goblin is set to change, given or
+// named in its task, belong to owners the project needs signoff from
+func warnSignoff(projectPath string, project *config.ProjectConfig, paths []string, task string) {
+	if len(project.SignoffOwners) == 0 {
+		return
+	}
+	co, err := workspace.LoadCodeOwners(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if co == nil {
+		return
+	}
+
+	// Directories are matched with a trailing slash, like CODEOWNERS
+	// writes them
+	var intended []string
+	for _, path := range paths {
+		path = filepath.ToSlash(filepath.Clean(path))
+		if info, err := os.Stat(filepath.Join(projectPath, path)); err == nil && info.IsDir() {
+			path += "/"
+		}
+		intended = append(intended, path)
+	}
+	intended = append(intended, workspace.TaskPaths(projectPath, task)...)
+
+	found := co.NeedingSignoff(intended, project.SignoffOwners)
+	for _, path := range intended {
+		if owners, ok := found[path]; ok {
+			fmt.Fprintf(os.Stderr, "Warning: %s is owned by %s in %s; changes need their signoff\n",
+				path, strings.Join(owners, ", "), co.Path)
+			delete(found, path) // Once per path, however often it's named
+		}
+	}
+}
+
+// printCheckResults shows each check with the output of failing ones
+func printCheckResults(results []*storage.CheckResult) {
+	for _, r := range results {
+		status := "ok"
+		if !r.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("  %-4s  %s (%s)\n", status, r.Command, r.Duration.Round(100*time.Millisecond))
+		if !r.Passed && r.Output != "" {
+			for _, line := range strings.Split(r.Output, "\n") {
+				fmt.Printf("        %s\n", line)
+			}
+		}
+	}
+}
+
+// doneOptions override a project's done: section from the command line
+type doneOptions struct {
+	pr, draft, archive bool
+	noPush, skipTests  bool
+	confirmDeps        bool
+	base               string
+}
+
+// finishGoblin runs a goblin's finish pipeline: tests, commit, push and
+// pull request, notification, then completed or archived. It stops at the
+// first failing step, leaving the goblin as it was.
+func finishGoblin(name string, opts doneOptions) error {
+	if remote != nil {
+		return fmt.Errorf("done is not supported with --server")
+	}
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+	if goblin.Status == coordinator.StatusCompleted || goblin.Status == coordinator.StatusArchived {
+		return fmt.Errorf("goblin %s is already %s", goblin.Name, goblin.Status)
+	}
+
+	project, err := config.LoadProject(goblin.ProjectPath)
+	if err != nil {
+		return err
+	}
+	done := project.Done
+	base := opts.base
+	if base == "" {
+		base = done.Base
+	}
+
+	if !opts.skipTests && len(done.Tests) > 0 {
+		fmt.Printf("Running %d test(s) in %s...\n", len(done.Tests), goblin.Name)
+		tests := config.ProjectChecks{Commands: done.Tests, Timeout: done.Timeout}
+		results, err := coord.RunChecks(goblin, tests, project.Git.Env())
+		printCheckResults(results)
+		if err != nil {
+			if errors.Is(err, errs.ErrChecksFailed) {
+				return fmt.Errorf("%w; fix them and run gforge done again, or finish with --skip-tests", err)
+			}
+			return err
+		}
+	}
+
+	committed, err := coord.CommitWork(goblin, fmt.Sprintf("gforge: finish %s", goblin.Name))
+	if err != nil {
+		return err
+	}
+	if committed {
+		fmt.Printf("Committed remaining work in %s\n", goblin.Name)
+	}
+
+	var artifacts []coordinator.Artifact
+	if len(project.Artifacts) > 0 {
+		artifacts, err = coord.CollectArtifacts(goblin, project.Artifacts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if len(artifacts) > 0 {
+			fmt.Printf("Collected %d artifact(s) to %s\n", len(artifacts), coord.ArtifactsPath(goblin))
+		}
+	}
+
+	if !opts.noPush {
+		pr := opts.pr || done.PR
+		draft := opts.draft || done.Draft
+		if err := pushGoblin(goblin.Name, pushOptions{pr: pr, base: base, draft: draft, confirmDeps: opts.confirmDeps}); err != nil {
+			return fmt.Errorf("%s was not marked completed: %w", goblin.Name, err)
+		}
+	}
+
+	message := fmt.Sprintf("%s finished its work on %s", goblin.Name, goblin.Branch)
+	if len(artifacts) > 0 {
+		message += fmt.Sprintf(" (%d artifacts in %s)", len(artifacts), coord.ArtifactsPath(goblin))
+	}
+	if err := notify.Send(cfg, notify.Event{Type: notify.EventDone, Goblin: goblin.Name, Message: message}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+	}
+
+	// Archiving keeps the session's transcript, so it ends the session
+	// itself rather than following Complete
+	if opts.archive || done.Archive {
+		if _, err := coord.Archive(goblin.ID); err != nil {
+			return err
+		}
+		coord.RecordEvent(goblin.ID, coordinator.EventCompleted, "archived")
+		fmt.Printf("Completed and archived %s to %s\n", goblin.Name, coord.ArchivePath(goblin))
+		return nil
+	}
+
+	if _, err := coord.Complete(goblin.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Completed %s\n", goblin.Name)
+	return nil
+}
+
+// runChecks runs a project's checks on a goblin's worktree, or shows the
+// results of the last run
+func runChecks(name string, show bool) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	if show {
+		results, err := db.ListCheckResults(goblin.ID)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Printf("No checks have run on %s\n", goblin.Name)
+			return nil
+		}
+		fmt.Printf("Checks on %s (%s):\n", goblin.Name, results[0].CreatedAt.Local().Format("Jan 2 15:04"))
+		printCheckResults(results)
+		return nil
+	}
+
+	project, err := config.LoadProject(goblin.ProjectPath)
+	if err != nil {
+		return err
+	}
+	if len(project.Checks.Commands) == 0 {
+		return fmt.Errorf("no checks configured; add checks.commands to %s", filepath.Join(goblin.ProjectPath, config.ProjectFile))
+	}
+
+	results, err := coord.RunChecks(goblin, project.Checks, project.Git.Env())
+	printCheckResults(results)
+	return err
+}
+
+// printDependencyReport shows the dependency and license changes in a
+// goblin's branch
+func printDependencyReport(report *workspace.DependencyReport) {
+	for _, manifest := range report.Manifests {
+		fmt.Printf("%s:\n", manifest)
+		listed := false
+		for _, d := range report.Deps {
+			if d.Manifest == manifest {
+				fmt.Printf("  %s\n", d)
+				listed = true
+			}
+		}
+		if !listed {
+			fmt.Println("  (modified)")
+		}
+	}
+	if len(report.Licenses) > 0 {
+		fmt.Println("New license files:")
+		for _, l := range report.Licenses {
+			fmt.Printf("  %s (%s)\n", l.File, l.License)
+		}
+	}
+}
+
+// confirm asks a yes/no question on the terminal; without one the answer
+// is no, so scripts have to opt in with a flag
+func confirm(question string) bool {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	fmt.Printf("%s [y/N] ", question)
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// showDependencies prints what a goblin's branch changes in dependency
+// manifests and licenses
+func showDependencies(name string) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	report, err := workspace.DetectDependencyChanges(goblin.WorktreePath, goblin.BaseRef)
+	if err != nil {
+		return err
+	}
+	if report.Empty() {
+		fmt.Printf("No dependency or license changes in %s\n", goblin.Name)
+		return nil
+	}
+	printDependencyReport(report)
+	return nil
+}
+
+// sizesReport lists the branch sizes measured by gforge push over a window
+func sizesReport(since time.Duration, output string) error {
+	sizes, err := db.ListDiffSizes(time.Now().Add(-since))
+	if err != nil {
+		return err
+	}
+
+	t := table.New("TIME", "GOBLIN", "PROJECT", "FILES", "ADDED", "REMOVED", "OVER")
+	oversized := 0
+	for _, d := range sizes {
+		over := ""
+		if d.Oversized {
+			over = "yes"
+			oversized++
+		}
+		t.Add(d.CreatedAt.Local().Format("2006-01-02 15:04"), d.GoblinName, filepath.Base(d.Project),
+			strconv.Itoa(d.Files), strconv.Itoa(d.Insertions), strconv.Itoa(d.Deletions), over)
+	}
+	if err := t.Write(os.Stdout, output); err != nil {
+		return err
+	}
+
+	if output == table.Text && len(sizes) > 0 {
+		fmt.Printf("\n%d of %d over the size limits\n", oversized, len(sizes))
+	}
+	return nil
+}
+
+// showFreezes lists the freeze windows on now or starting within a period
+func showFreezes(within time.Duration, output string) error {
+	if cfg.Scheduler.FreezeCalendar == "" {
+		return fmt.Errorf("no freeze calendar; set scheduler.freeze_calendar to an iCal URL or file")
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	now := time.Now()
+	windows, err := coord.Freezes(now, now.Add(within))
+	if err != nil {
+		return err
+	}
+
+	t := table.New("START", "END", "EVENT", "STATUS")
+	for _, w := range windows {
+		status := ""
+		if !w.Start.After(now) {
+			status = "on"
+		}
+		t.Add(w.Start.Local().Format("2006-01-02 15:04"), w.End.Local().Format("2006-01-02 15:04"), w.Summary, status)
+	}
+	if err := t.Write(os.Stdout, output); err != nil {
+		return err
+	}
+
+	if output == table.Text && len(windows) == 0 {
+		fmt.Println("\nNo freezes coming up")
+	}
+	return nil
+}
+
+// printSecretFindings lists credentials found in a goblin's branch
+func printSecretFindings(findings []*storage.SecretFinding) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMIT\tFILE\tRULE\tMATCH")
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s\t%s:%d\t%s\t%s\n", shortCommit(f.Commit), f.File, f.Line, f.Rule, f.Match)
+	}
+	w.Flush()
+}
+
+// showSecrets lists the findings stored by a goblin's last secret scan, or
+// scans its branch again first
+func showSecrets(name string, scan bool) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	var findings []*storage.SecretFinding
+	if scan {
+		findings, err = coord.ScanSecrets(goblin)
+	} else {
+		findings, err = db.ListSecretFindings(goblin.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(findings) == 0 {
+		fmt.Printf("No secrets found in %s\n", goblin.Name)
+		return nil
+	}
+	printSecretFindings(findings)
+	return nil
+}
+
+// pushRemotes picks the remote to push goblin branches to and the one to
+// open pull requests against: --remote, then .gforge.yaml, then git config
+func pushRemotes(project *config.ProjectConfig, flag string) (push, pr string) {
+	push, pr = cfg.Git.PushRemote, cfg.Git.PRRemote
+	if project.PushRemote != "" {
+		push = project.PushRemote
+	}
+	if project.PRRemote != "" {
+		pr = project.PRRemote
+	}
+	if flag != "" {
+		push = flag
+	}
+	if push == "" {
+		push = "origin"
+	}
+	if pr == "" {
+		pr = "origin"
+	}
+	return push, pr
+}
+
+// showDiff displays changes made by a goblin
+func showDiff(name string, staged, noIgnore bool) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	var ignore workspace.Ignore
+	if !noIgnore {
+		ignore = coord.DiffIgnore(goblin)
+	}
+
+	// Create workspace manager
+	wsMgr := workspace.NewWorktreeManager(workspace.Config{
+		BasePath: cfg.WorktreeBase,
+		Ignore:   ignore,
+	})
+
+	// Get changed files, counting the ignored ones separately
+	all, err := workspace.NewWorktreeManager(workspace.Config{BasePath: cfg.WorktreeBase}).GetChanges(goblin.WorktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to get changes: %w", err)
+	}
+	changes := ignore.Filter(all)
+	hidden := len(all) - len(changes)
+
+	if len(changes) == 0 {
+		fmt.Printf("No changes in %s\n", name)
+		if hidden > 0 {
+			fmt.Printf("%d ignored file(s) changed; --no-ignore shows them\n", hidden)
+		}
+		return nil
+	}
+
+	fmt.Printf("=== Changes in %s (%d files) ===\n\n", name, len(changes))
+
+	// Show file list
+	fmt.Println("Changed files:")
+	for _, f := range changes {
+		fmt.Printf("  %s\n", f)
+	}
+	if hidden > 0 {
+		fmt.Printf("  (%d ignored file(s) not shown; --no-ignore shows them)\n", hidden)
+	}
+	fmt.Println()
+
+	// Show diff
+	diff, err := wsMgr.GetDiff(goblin.WorktreePath, staged)
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	if diff == "" {
+		if staged {
+			fmt.Println("No staged changes.")
+		} else {
+			fmt.Println("No unstaged changes (files may be staged).")
+		}
+		return nil
+	}
+
+	printDiff(diff)
+	return nil
+}
+
+// acceptGoblin asks about each hunk of a goblin's branch and commits the
+// accepted ones to a new branch
+func acceptGoblin(name, branch, message string) error {
+	if remote != nil {
+		return fmt.Errorf("accept is not supported with --server")
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("accept asks about each change; run it in a terminal")
+	}
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+	if goblin.BaseRef == "" {
+		return fmt.Errorf("goblin %s has no recorded base commit to accept its changes onto", goblin.Name)
+	}
+
+	wsMgr := workspace.NewWorktreeManager(workspace.Config{BasePath: cfg.WorktreeBase})
+	if changes, err := wsMgr.GetChanges(goblin.WorktreePath); err == nil && len(changes) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d uncommitted change(s) in %s are not offered; commit them to include them\n", len(changes), goblin.Name)
+	}
+	patch, err := workspace.BranchPatch(goblin.WorktreePath, goblin.BaseRef)
+	if err != nil {
+		return err
+	}
+	files := workspace.ParsePatch(patch)
+	if len(files) == 0 {
+		fmt.Printf("%s has no committed changes to accept\n", goblin.Name)
+		return nil
+	}
+
+	accepted, total := reviewHunks(files)
+	kept := 0
+	for _, file := range accepted {
+		for _, ok := range file {
+			if ok {
+				kept++
+			}
+		}
+	}
+	if kept == 0 {
+		fmt.Println("Nothing accepted; no branch created")
+		return nil
+	}
+
+	if branch == "" {
+		branch = goblin.Branch + "-accepted"
+	}
+	if message == "" {
+		message = fmt.Sprintf("Accept %d of %d changes from %s", kept, total, goblin.Name)
+	}
+	out := workspace.BuildPatch(files, func(file, change int) bool { return accepted[file][change] })
+	commit, err := coord.AcceptPatch(goblin, out, branch, message)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Accepted %d of %d changes onto %s (%s); %s is unchanged\n", kept, total, branch, shortCommit(commit), goblin.Branch)
+	return nil
+}
+
+// applyGoblin applies a goblin's changes to its project's checkout
+func applyGoblin(name string, opts coordinator.ApplyOptions) error {
+	if remote != nil {
+		return fmt.Errorf("apply is not supported with --server")
+	}
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	stat, err := coord.ApplyToProject(goblin, opts)
+	fmt.Print(stat)
+	if err != nil {
+		return err
+	}
+	if opts.DryRun {
+		fmt.Printf("Would apply cleanly to %s\n", goblin.ProjectPath)
+		return nil
+	}
+	fmt.Printf("Applied %s's changes to %s\n", goblin.Name, goblin.ProjectPath)
+	return nil
+}
+
+// reviewHunks asks about each change in files, returning the answers by
+// file and change and the number of changes
+func reviewHunks(files []*workspace.FilePatch) ([][]bool, int) {
+	total := 0
+	for _, f := range files {
+		total += f.Changes()
+	}
+
+	accepted := make([][]bool, len(files))
+	reader := bufio.NewReader(os.Stdin)
+	n, quit := 0, false
+	for fi, f := range files {
+		accepted[fi] = make([]bool, f.Changes())
+		rest := "" // "a" or "d" once the rest of the file is decided
+		for ci := range accepted[fi] {
+			n++
+			if quit || rest == "d" {
+				continue
+			}
+			if rest == "a" {
+				accepted[fi][ci] = true
+				continue
+			}
+
+			if ci == 0 {
+				header := f.Header
+				for i, line := range header {
+					if line == "GIT binary patch" {
+						header = append(header[:i:i], "(binary file)")
+						break
+					}
+				}
+				fmt.Println()
+				printDiff(strings.Join(header, "\n"))
+			}
+			if len(f.Hunks) > 0 {
+				printDiff(strings.TrimSuffix(f.Hunks[ci].String(), "\n"))
+			}
+
+			for {
+				fmt.Printf("(%d/%d) Accept this change [y,n,a,d,q,?]? ", n, total)
+				line, err := reader.ReadString('\n')
+				answer := strings.TrimSpace(line)
+				if err != nil && answer == "" {
+					answer = "q"
+				}
+				switch answer {
+				case "y":
+					accepted[fi][ci] = true
+				case "n":
+				case "a":
+					accepted[fi][ci] = true
+					rest = "a"
+				case "d":
+					rest = "d"
+				case "q":
+					quit = true
+				default:
+					fmt.Println("y - accept this change\nn - reject this change\na - accept it and the rest of this file\nd - reject it and the rest of this file\nq - reject everything not yet decided")
+					continue
+				}
+				break
+			}
+		}
+	}
+	return accepted, total
+}
+
+// printDiff shows a diff with additions, removals and hunks colored
+func printDiff(diff string) {
+	theme := style.Current()
+	lines := strings.Split(diff, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+			fmt.Println(style.Paint(theme.Success, line))
+		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
+			fmt.Println(style.Paint(theme.Danger, line))
+		} else if strings.HasPrefix(line, "@@") {
+			fmt.Println(style.Paint(theme.Info, line))
+		} else if strings.HasPrefix(line, "diff") || strings.HasPrefix(line, "index") {
+			fmt.Println(style.Bold(line))
+		} else {
+			fmt.Println(line)
+		}
+	}
+}
+
+// showDiffRange shows what a goblin changed between two points: its
+// checkpoints, its base, or times
+func showDiffRange(name, from, to string, noIgnore bool) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+	if goblin.BaseRef == "" {
+		return fmt.Errorf("goblin %s has no recorded base commit to find its checkpoints from", goblin.Name)
+	}
+	checkpoints, err := workspace.BranchCheckpoints(goblin.WorktreePath, goblin.BaseRef)
+	if err != nil {
+		return err
+	}
+
+	if from == "" {
+		from = "base"
+	}
+	fromCommit, fromLabel, err := diffPoint(goblin, checkpoints, from)
+	if err != nil {
+		return err
+	}
+	toCommit, toLabel := "", "now"
+	if to != "" {
+		if toCommit, toLabel, err = diffPoint(goblin, checkpoints, to); err != nil {
+			return err
+		}
+	}
+
+	var ignore workspace.Ignore
+	if !noIgnore {
+		ignore = coord.DiffIgnore(goblin)
+	}
+	diff, err := workspace.DiffBetween(goblin.WorktreePath, fromCommit, toCommit, ignore)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Printf("No changes in %s from %s to %s\n", goblin.Name, fromLabel, toLabel)
+		return nil
+	}
+	fmt.Printf("=== Changes in %s from %s to %s ===\n\n", goblin.Name, fromLabel, toLabel)
+	printDiff(diff)
+	return nil
+}
+
+// diffPoint resolves a --from or --to point to a commit and a label: a
+// time to the checkpoint the branch was at, "base" to the branch's start,
+// anything else to a commit
+func diffPoint(goblin *coordinator.Goblin, checkpoints []workspace.Checkpoint, point string) (string, string, error) {
+	if point == "base" {
+		return goblin.BaseRef, "base", nil
+	}
+	if at, ok := parsePointTime(point, time.Now()); ok {
+		label := at.Format("Jan 2 15:04")
+		if c, found := workspace.CheckpointAt(checkpoints, at); found {
+			return c.Commit, fmt.Sprintf("%s (%s)", label, c.Short()), nil
+		}
+		return goblin.BaseRef, label + " (base)", nil
+	}
+	commit, err := workspace.ResolveCommit(goblin.WorktreePath, point)
+	if err != nil {
+		return "", "", fmt.Errorf("%w; use a checkpoint from gforge diff %s --checkpoints, base, or a time", err, goblin.Name)
+	}
+	return commit, point, nil
+}
+
+// parsePointTime reads a point as a time: a window back from now (2h,
+// 3d), a date, a date and time, or a time today
+func parsePointTime(s string, now time.Time) (time.Time, bool) {
+	if d, err := parseSince(s); err == nil {
+		return now.Add(-d), true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", s, time.Local); err == nil {
+		y, m, d := now.Date()
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, time.Local), true
+	}
+	return time.Time{}, false
+}
+
+// listCheckpoints shows the commits on a goblin's branch, the points
+// gforge diff --from and --to take
+func listCheckpoints(name string) error {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return err
+	}
+
+	goblin, err := coordinator.New(db, cfg, log).Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+	if goblin.BaseRef == "" {
+		return fmt.Errorf("goblin %s has no recorded base commit to find its checkpoints from", goblin.Name)
+	}
+	checkpoints, err := workspace.BranchCheckpoints(goblin.WorktreePath, goblin.BaseRef)
+	if err != nil {
+		return err
+	}
+	if len(checkpoints) == 0 {
+		fmt.Printf("No checkpoints in %s yet\n", goblin.Name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECKPOINT\tTIME\tSUBJECT")
+	for _, c := range checkpoints {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Short(), c.Time.Local().Format("Jan 2 15:04"), c.Subject)
+	}
+	return w.Flush()
+}
+
+// sendTask sends a task to a goblin
+func sendTask(task, goblinName, templateName string, now bool) error {
+	goblinName, err := resolveGoblinRef(goblinName)
+	if err != nil {
+		return err
+	}
+
+	if remote != nil && templateName != "" {
+		return fmt.Errorf("task templates are not supported with --server")
+	}
+	if remote != nil && now {
+		return fmt.Errorf("--now is not supported with --server")
+	}
+
+	if remote != nil {
+		if err := remote.SendTask(goblinName, task); err != nil {
+			return fmt.Errorf("failed to send task: %w", err)
+		}
+		fmt.Printf("Task submitted to %s (queued if it is busy):\n", goblinName)
+		fmt.Printf("  \"%s\"\n", task)
+		return nil
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(goblinName)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, goblinName)
+	}
+
+	if templateName != "" {
+		prompt, err := config.ProjectTask(goblin.ProjectPath, templateName)
+		if err != nil {
+			return err
+		}
+		if task != "" {
+			prompt += "\n\n" + task
+		}
+		task = prompt
+	}
+
+	if conflicts, err := coord.TaskLockConflicts(goblin, task); err == nil {
+		warnLocks(conflicts)
+	}
+	if now {
+		err = coord.SendTask(goblinName, task)
+	} else {
+		var position int
+		position, err = coord.SubmitTask(goblinName, task)
+		if err == nil && position > 0 {
+			fmt.Printf("Task queued for %s (position %d):\n", goblinName, position)
+			fmt.Printf("  \"%s\"\n", task)
+			fmt.Println("It is sent once the agent goes quiet, by gforge daemon or gforge notify watch.")
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send task: %w", err)
+	}
+
+	fmt.Printf("Task sent to %s:\n", goblinName)
+	fmt.Printf("  \"%s\"\n", task)
+
+	return nil
+}
+
+// runBench runs a benchmark suite against several agents
+func runBench(suitePath, agentList, projectPath, outputFile string, jsonOutput bool) error {
+	suite, err := bench.LoadSuite(suitePath)
+	if err != nil {
+		return err
+	}
+
+	// Suite projects are relative to the suite file
+	if projectPath == "" {
+		projectPath = suite.Project
+		if !filepath.IsAbs(projectPath) {
+			projectPath = filepath.Join(filepath.Dir(suitePath), projectPath)
+		}
+	}
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return fmt.Errorf("invalid project path: %w", err)
+	}
+
+	var agentNames []string
+	for _, name := range strings.Split(agentList, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			agentNames = append(agentNames, name)
+		}
+	}
+	if len(agentNames) == 0 {
+		return fmt.Errorf("no agents given")
+	}
+
+	fmt.Fprintf(os.Stderr, "Running %d task(s) against %d agent(s) in %s...\n",
+		len(suite.Tasks), len(agentNames), absPath)
+
+	coord := coordinator.New(db, cfg, log)
+	runner := bench.NewRunner(coord, log)
+	results, err := runner.Run(suite, absPath, agentNames)
+	if err != nil {
+		return fmt.Errorf("benchmark failed: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	bench.WriteReport(out, suite, results)
+	if outputFile != "" {
+		fmt.Printf("Report written to %s\n", outputFile)
+	}
+	return nil
+}
+
+// Suppress unused import warnings during development
+var (
+	_ = time.Now
+	_ = strings.TrimSpace
+	_ = tmux.NewManager
+	_ = workspace.NewWorktreeManager
+)
+
+// fanOutOptions are the flags of gforge fanout
+type fanOutOptions struct {
+	task, agents, project string
+	count                 int
+	autoSelect, draft     bool
+	idleTimeout, timeout  time.Duration
+}
+
+// runFanOut spawns goblins on the same task and, with --auto-select,
+// promotes the best one and archives the rest
+func runFanOut(name string, opts fanOutOptions) error {
+	if remote != nil {
+		return fmt.Errorf("fanout is not supported with --server")
+	}
+
+	// Goblin names and their agents, empty for the default
+	var names, agentNames []string
+	if opts.agents != "" {
+		for _, a := range strings.Split(opts.agents, ",") {
+			a = strings.TrimSpace(a)
+			names, agentNames = append(names, name+"-"+a), append(agentNames, a)
+		}
+	} else {
+		for i := 1; i <= opts.count; i++ {
+			names, agentNames = append(names, fmt.Sprintf("%s-%d", name, i)), append(agentNames, "")
+		}
+	}
+	if len(names) < 2 {
+		return fmt.Errorf("a fan-out needs at least 2 goblins; use --count or --agents")
+	}
+
+	project, err := config.LoadProject(opts.project)
+	if err != nil {
+		return err
+	}
+	if opts.autoSelect && len(project.Done.Tests) == 0 {
+		return fmt.Errorf("--auto-select needs a test command: add done.tests to %s", config.ProjectFile)
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	var goblins []*coordinator.Goblin
+	for i, n := range names {
+		spawnOpts, _, err := spawnOptions(n, agentNames[i], opts.project, "", "")
+		if err != nil {
+			return err
+		}
+		spawnOpts.Task = opts.task
+		goblin, err := coord.Spawn(spawnOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to spawn %s: %v\n", n, err)
+			continue
+		}
+		fmt.Printf("Spawned %s (%s)\n", goblin.Name, goblin.Agent)
+		goblins = append(goblins, goblin)
+	}
+	if len(goblins) == 0 {
+		return fmt.Errorf("failed to spawn any goblin")
+	}
+
+	if !opts.autoSelect {
+		fmt.Println()
+		fmt.Println("Compare them with gforge diff <name>, finish the best with gforge done and")
+		fmt.Println("archive the rest, or run with --auto-select to have gforge pick.")
+		return nil
+	}
+
+	fmt.Printf("\nWaiting for %d goblins to go quiet for %s...\n", len(goblins), opts.idleTimeout)
+	for _, g := range coord.WaitQuiet(goblins, opts.idleTimeout, opts.timeout) {
+		fmt.Fprintf(os.Stderr, "Warning: %s is still working after %s; scoring it as it is\n", g.Name, opts.timeout)
+	}
+
+	var candidates []*coordinator.Candidate
+	t := table.New("GOBLIN", "AGENT", "TESTS", "CHECKS", "FILES", "LINES", "SCORE")
+	for _, g := range goblins {
+		fmt.Printf("Scoring %s...\n", g.Name)
+		c := coord.Measure(g, project)
+		candidates = append(candidates, c)
+		if c.Err != nil {
+			t.Add(g.Name, g.Agent, "error", "-", "-", "-", c.Err.Error())
+			continue
+		}
+		t.Add(g.Name, g.Agent, passFail(c.TestsPassed), passFail(c.LintClean),
+			strconv.Itoa(c.Files), strconv.Itoa(c.Lines), fmt.Sprintf("%.0f", c.Score()))
+	}
+	fmt.Println()
+	if err := t.Write(os.Stdout, "table"); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	best := coordinator.SelectBest(candidates)
+	if best == nil {
+		return fmt.Errorf("no goblin passed the tests; all %d are left for review", len(goblins))
+	}
+	coord.RecordSelected(best, len(candidates))
+	fmt.Printf("Selected %s\n", best.Goblin.Name)
+
+	if err := finishGoblin(best.Goblin.Name, doneOptions{pr: true, draft: opts.draft, skipTests: true}); err != nil {
+		return fmt.Errorf("the others were left for review: %w", err)
+	}
+	for _, c := range candidates {
+		if c == best {
+			continue
+		}
+		if _, err := coord.Archive(c.Goblin.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to archive %s: %v\n", c.Goblin.Name, err)
+			continue
+		}
+		fmt.Printf("Archived %s\n", c.Goblin.Name)
+	}
+	return nil
+}
+
+// passFail shows whether a check passed
+func passFail(ok bool) string {
+	if ok {
+		return "pass"
+	}
+	return "fail"
+}
+
+// runWorkflow runs a workflow file's goblins in dependency order
+func runWorkflow(path string) error {
+	if remote != nil {
+		return fmt.Errorf("run is not supported with --server")
+	}
+
+	wf, err := workflow.Load(path)
+	if err != nil {
+		return err
+	}
+
+	runner := workflow.NewRunner(coordinator.New(db, cfg, log), log)
+	runner.Options = func(name, agent, project string) (coordinator.SpawnOptions, error) {
+		opts, _, err := spawnOptions(name, agent, project, "", "")
+		return opts, err
+	}
+	runner.OnStep = func(res workflow.StepResult) {
+		switch {
+		case res.Status == workflow.StepRunning:
+			fmt.Printf("Step %s started\n", res.Step)
+		case res.Err != nil:
+			fmt.Printf("Step %s %s: %v\n", res.Step, res.Status, res.Err)
+		default:
+			fmt.Printf("Step %s %s\n", res.Step, res.Status)
+		}
+	}
+
+	fmt.Printf("Running workflow %s (%d steps)\n", wf.Name, len(wf.Steps))
+	results, err := runner.Run(wf)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	t := table.New("STEP", "GOBLIN", "STATUS", "BRANCH")
+	for _, res := range results {
+		goblin, branch := "-", "-"
+		if res.Goblin != nil {
+			goblin, branch = res.Goblin.Name, res.Goblin.Branch
+		}
+		if res.Status != workflow.StepDone {
+			failed++
+		}
+		t.Add(res.Step, goblin, res.Status, branch)
+	}
+	fmt.Println()
+	if err := t.Write(os.Stdout, "table"); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d steps didn't finish", failed, len(results))
+	}
+	return nil
+}
+
+// replayGoblin re-runs a goblin's recorded tasks on a fresh goblin
+func replayGoblin(source, name, agentName string, idleTimeout, taskTimeout time.Duration) error {
+	source, err := resolveGoblinRef(source)
+	if err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.Get(source)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, source)
+	}
+
+	if name == "" {
+		for i := 1; ; i++ {
+			name = fmt.Sprintf("%s-replay-%d", goblin.Name, i)
+			if existing, _ := coord.Get(name); existing == nil {
+				break
+			}
+		}
+	}
+
+	var agent *agents.Agent
+	if agentName != "" {
+		agent = agents.NewRegistry().Get(agentName)
+		if agent == nil {
+			return fmt.Errorf("unknown agent: %s", agentName)
+		}
+	}
+
+	fmt.Printf("Replaying %s as %s...\n", goblin.Name, name)
+
+	replayed, err := coord.Replay(goblin.Name, coordinator.ReplayOptions{
+		Name:        name,
+		Agent:       agent,
+		IdleTimeout: idleTimeout,
+		TaskTimeout: taskTimeout,
+		OnTask: func(i int, task string) {
+			fmt.Printf("  [%d] %s\n", i+1, task)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("replay failed: %w", err)
+	}
+
+	fmt.Printf("\nReplay complete: %s\n", replayed.Name)
+	fmt.Printf("  Branch:   %s\n", replayed.Branch)
+	fmt.Printf("  Worktree: %s\n", replayed.WorktreePath)
+	fmt.Printf("\nCompare with: gforge diff %s\n", replayed.Name)
+	return nil
+}
+
+// recordPane writes pane output from stdin to an asciinema cast; it is
+// the far end of tmux pipe-pane for recorded goblins
+func recordPane(path string, width, height int, title string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+	defer f.Close()
+
+	return recording.Record(os.Stdin, f, recording.Header{
+		Width:  width,
+		Height: height,
+		Title:  title,
+		Env:    map[string]string{"TERM": "screen-256color", "SHELL": os.Getenv("SHELL")},
+	})
+}
+
+// playRecording replays a goblin's recorded session or uploads it
+func playRecording(target string, speed float64, idleLimit time.Duration, upload bool) error {
+	path := target
+	if !strings.HasSuffix(target, ".cast") {
+		name, err := resolveGoblinRef(target)
+		if err != nil {
+			return err
+		}
+		path, err = coordinator.New(db, cfg, log).FindRecording(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if upload {
+		if _, err := exec.LookPath("asciinema"); err != nil {
+			return fmt.Errorf("asciinema not installed; upload %s manually", path)
+		}
+		cmd := exec.Command("asciinema", "upload", path)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	cast, err := recording.Load(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Playing %s (%s, %dx%d)...\n",
+		path, cast.Duration().Round(time.Second), cast.Header.Width, cast.Header.Height)
+
+	// Clear the screen so cursor movements land where they were recorded
+	fmt.Print("\x1b[2J\x1b[H")
+	if err := cast.Play(os.Stdout, speed, idleLimit); err != nil {
+		return err
+	}
+	fmt.Print("\x1b[0m\n")
+	return nil
+}
+
+// watchNotifications polls goblins and sends notifications
+func watchNotifications(interval time.Duration) error {
+	if len(notify.Configured(cfg)) == 0 {
+		fmt.Fprintln(os.Stderr, "Notifications are not set up; events will only be logged.")
+		fmt.Fprintln(os.Stderr, "Set notifications.desktop: true, or matrix or telegram, in config to enable them.")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	coord := coordinator.New(db, cfg, log)
+	watcher := notify.NewWatcher(coord, cfg, log)
+	watcher.ReloadConfig(cfg)
+
+	fmt.Println("Watching goblins (Ctrl+C to stop)...")
+	fmt.Printf("Edits to %s apply live where they can; see gforge events for what needs a restart.\n", cfg.ConfigPath)
+	return watcher.Run(ctx, interval)
+}
+
+// testNotifications sends a test notification to each configured
+// transport, or the named one
+func testNotifications(transport string) error {
+	notifiers := notify.Configured(cfg)
+	// Worth trying before turning them on
+	if transport == "desktop" && !cfg.Notifications.Desktop {
+		notifiers = append(notifiers, notify.NewDesktop())
+	}
+	if transport == "voice" && !(cfg.Voice.Enabled && cfg.Voice.FeedbackSound) {
+		notifiers = append(notifiers, notify.NewSpeech(cfg.Voice))
+	}
+
+	var failed []string
+	sent := 0
+	for _, n := range notifiers {
+		if transport != "" && n.Name() != transport {
+			continue
+		}
+		sent++
+		if err := n.Send("gforge", "Notifications are working"); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", n.Name(), err)
+			failed = append(failed, n.Name())
+			continue
+		}
+		fmt.Printf("%s: sent\n", n.Name())
+	}
+
+	if sent == 0 {
+		if transport != "" {
+			return fmt.Errorf("notifications to %s are not set up (see notifications in config)", transport)
+		}
+		return fmt.Errorf("no notifications set up; enable notifications.desktop or configure matrix or telegram")
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to notify %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// voiceSession is the tmux session running the background voice listener
+const voiceSession = "gforge-voice"
+
+// voiceRecent is how many heard commands gforge voice status shows
+const voiceRecent = 10
+
+// gforgeArgs prefixes args with the global flags a child gforge needs
+func gforgeArgs(args ...string) []string {
+	if cfgFile != "" {
+		if abs, err := filepath.Abs(cfgFile); err == nil {
+			return append([]string{"--config", abs}, args...)
+		}
+	}
+	return args
+}
+
+// voiceProject resolves the project voice-spawned goblins go in
+func voiceProject(project string) (string, error) {
+	if !cfg.Voice.Enabled {
+		return "", fmt.Errorf("voice control is off; set voice.enabled: true in config")
+	}
+	if remote != nil {
+		return "", fmt.Errorf("voice control is not supported with --server")
+	}
+	abs, err := filepath.Abs(project)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project path: %w", err)
+	}
+	return abs, nil
+}
+
+// startVoice runs gforge voice serve in a detached tmux session
+func startVoice(project string) error {
+	project, err := voiceProject(project)
+	if err != nil {
+		return err
+	}
+
+	socket := cfg.Tmux.SocketName
+	if trace.Command("tmux", "-L", socket, "has-session", "-t", voiceSession).Run() == nil {
+		return fmt.Errorf("voice listener already running (tmux session %s); gforge voice stop ends it", voiceSession)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate gforge binary: %w", err)
+	}
+	args := append([]string{"-L", socket, "new-session", "-d", "-s", voiceSession, "-c", project, exe},
+		gforgeArgs("voice", "serve", "--project", project)...)
+	if output, err := trace.Command("tmux", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start voice listener: %s\n%s", err, output)
+	}
+
+	fmt.Printf("Voice listener started in tmux session %s\n", voiceSession)
+	fmt.Printf("  Hotkey: %s\n", cfg.Voice.Hotkey)
+	if cfg.Voice.WakeWord != "" {
+		fmt.Printf("  Wake word: %q\n", cfg.Voice.WakeWord)
+	}
+	fmt.Printf("  Logs: tmux -L %s attach -t %s\n", socket, voiceSession)
+	return nil
+}
+
+// stopVoice ends the background voice listener and the daemon
+func stopVoice() error {
+	socket := cfg.Tmux.SocketName
+	running := trace.Command("tmux", "-L", socket, "has-session", "-t", voiceSession).Run() == nil
+	if running {
+		if output, err := trace.Command("tmux", "-L", socket, "kill-session", "-t", voiceSession).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to stop voice listener: %s\n%s", err, output)
+		}
+	}
+	// Killing the session doesn't give serve the chance to stop the daemon
+	if ipc.IsDaemonRunning() {
+		ipc.StopDaemon()
+		running = true
+	}
+
+	if !running {
+		fmt.Println("Voice listener is not running")
+		return nil
+	}
+	fmt.Println("Voice listener stopped")
+	return nil
+}
+
+// serveVoice starts the voice daemon if needed and carries out the
+// commands it hears until interrupted
+func serveVoice(project string) error {
+	project, err := voiceProject(project)
+	if err != nil {
+		return err
+	}
+
+	if !ipc.IsDaemonRunning() {
+		fmt.Println("Starting voice daemon...")
+		v := cfg.Voice
+		err := ipc.StartDaemon(ipc.DaemonOptions{
+			Model:     v.Model,
+			ModelsDir: filepath.Join(config.GetDataPath(), "models"),
+			Hotkey:    v.Hotkey,
+			WakeWord:  v.WakeWord,
+			Language:  v.Language,
+			NoSounds:  !v.FeedbackSound,
+			Output:    os.Stderr,
+		})
+		if err != nil {
+			return err
+		}
+		defer ipc.StopDaemon()
+	}
+
+	client := ipc.NewVoiceClient("")
+	if err := client.Connect(); err != nil {
+		return err
+	}
+
+	// Results are spoken when feedback is on and an engine is installed
+	say := func(string) {}
+	if cfg.Voice.FeedbackSound {
+		if speaker, err := voice.NewSpeaker(cfg.Voice); err == nil {
+			say = func(text string) { speaker.Speak(text) }
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	listener := &voiceListener{project: project, say: say}
+	defer listener.close()
+	handle := func(cmd ipc.VoiceCommand) {
+		if listener.handle(cmd) {
+			stop()
+		}
+	}
+	client.OnCommand(handle)
+	go func() {
+		<-ctx.Done()
+		client.Disconnect()
+	}()
+
+	// Confirmations can be typed in the listener's terminal too
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			switch answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer {
+			case "y", "yes":
+				handle(ipc.VoiceCommand{Action: "confirm", Raw: answer})
+			case "n", "no":
+				handle(ipc.VoiceCommand{Action: "cancel", Raw: answer})
+			}
+		}
+	}()
+
+	fmt.Printf("Listening for voice commands, spawning in %s (Ctrl+C to stop)...\n", project)
+	return client.Listen()
+}
+
+// voiceListener carries out heard commands, holding those voice.confirm
+// gates until they are confirmed
+type voiceListener struct {
+	project string
+	say     func(string)
+
+	mu      sync.Mutex
+	held    *storage.VoiceCommand // the record of the command awaiting confirmation
+	heldCmd ipc.VoiceCommand
+	heldRun []string
+	timer   *time.Timer
+}
+
+// handle carries out and records a heard command. It reports whether the
+// listener was asked to stop.
+func (l *voiceListener) handle(cmd ipc.VoiceCommand) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	params, _ := json.Marshal(cmd)
+	record := &storage.VoiceCommand{RawText: cmd.Raw, Action: cmd.Action, Params: string(params)}
+	defer func() {
+		if err := db.RecordVoiceCommand(record); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("Heard %q\n", cmd.Raw)
+	switch cmd.Action {
+	case "confirm":
+		if l.held == nil {
+			l.say("Nothing to confirm")
+			return false
+		}
+		held, heldCmd, args := l.release()
+		l.say(voice.Confirmation(heldCmd))
+		record.Executed = l.run(heldCmd, args)
+		l.decide(held, "confirmed", record.Executed)
+		return false
+	case "cancel":
+		if l.held == nil {
+			l.say("Nothing to cancel")
+			return false
+		}
+		held, _, _ := l.release()
+		l.decide(held, "cancelled", false)
+		fmt.Println("Cancelled")
+		l.say("Cancelled")
+		return false
+	}
+
+	// Moving on to another command drops the one awaiting confirmation
+	if l.held != nil {
+		held, _, _ := l.release()
+		l.decide(held, "cancelled", false)
+		fmt.Printf("Cancelled %q\n", held.RawText)
+	}
+
+	switch cmd.Action {
+	case "exit_voice":
+		record.Decision = voice.PolicyRun
+		record.Executed = true
+		l.say(voice.Confirmation(cmd))
+		return true
+	case "unknown":
+		l.say(voice.Confirmation(cmd))
+		return false
+	}
+
+	var args []string
+	if cmd.Action != "list" && cmd.Action != "status" {
+		var err error
+		if args, err = voice.Args(cmd, l.project); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			l.say(err.Error())
+			return false
+		}
+	}
+
+	switch voice.Policy(cfg.Voice.Confirm, cmd.Action) {
+	case voice.PolicyRefuse:
+		record.Decision = "refused"
+		msg := fmt.Sprintf("%s isn't allowed by voice", cmd.Action)
+		fmt.Fprintln(os.Stderr, msg)
+		l.say(msg)
+	case voice.PolicyConfirm:
+		record.Decision = "awaiting"
+		l.hold(record, cmd, args)
+		question := voice.Question(cmd.Action, cmd.Name)
+		fmt.Printf("%s (or type y/n)\n", question)
+		l.say(question)
+	default:
+		record.Decision = voice.PolicyRun
+		if args != nil {
+			l.say(voice.Confirmation(cmd))
+		}
+		record.Executed = l.run(cmd, args)
+	}
+	return false
+}
+
+// hold keeps a command until it is confirmed, cancelled or times out
+func (l *voiceListener) hold(record *storage.VoiceCommand, cmd ipc.VoiceCommand, args []string) {
+	l.held, l.heldCmd, l.heldRun = record, cmd, args
+	l.timer = time.AfterFunc(voice.ConfirmTimeout(cfg.Voice.Confirm), func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.held != record {
+			return
+		}
+		l.release()
+		l.decide(record, "expired", false)
+		fmt.Printf("No confirmation; dropped %q\n", record.RawText)
+	})
+}
+
+// release drops the command awaiting confirmation, returning it
+func (l *voiceListener) release() (*storage.VoiceCommand, ipc.VoiceCommand, []string) {
+	held, cmd, args := l.held, l.heldCmd, l.heldRun
+	l.held, l.heldRun = nil, nil
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	return held, cmd, args
+}
+
+// decide stores what became of a command that awaited confirmation
+func (l *voiceListener) decide(record *storage.VoiceCommand, decision string, executed bool) {
+	if err := db.DecideVoiceCommand(record.ID, decision, executed); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// close cancels a command still awaiting confirmation
+func (l *voiceListener) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held != nil {
+		held, _, _ := l.release()
+		l.decide(held, "cancelled", false)
+	}
+}
+
+// run carries out a command, as the gforge command line args or, for
+// list and status, by reading out the goblins. It reports whether the
+// command succeeded.
+func (l *voiceListener) run(cmd ipc.VoiceCommand, args []string) bool {
+	if args == nil {
+		summary, err := goblinSummary()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			l.say("Couldn't list goblins")
+			return false
+		}
+		fmt.Println(summary)
+		l.say(summary)
+		return true
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to locate gforge binary: %v\n", err)
+		return false
+	}
+	fmt.Printf("> gforge %s\n", strings.Join(args, " "))
+	output, err := exec.Command(exe, gforgeArgs(args...)...).CombinedOutput()
+	os.Stdout.Write(output)
+	if err != nil {
+		l.say(fmt.Sprintf("%s failed", cmd.Action))
+		return false
+	}
+	return true
+}
+
+// goblinSummary says which goblins are running, to be read aloud
+func goblinSummary() (string, error) {
+	goblins, err := coordinator.New(db, cfg, log).List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list goblins: %w", err)
+	}
+
+	var running []string
+	for _, g := range goblins {
+		if g.Status == "running" {
+			running = append(running, g.Name)
+		}
+	}
+	switch len(running) {
+	case 0:
+		return fmt.Sprintf("No goblins running, %d in total", len(goblins)), nil
+	case 1:
+		return fmt.Sprintf("One goblin running: %s", running[0]), nil
+	}
+	return fmt.Sprintf("%d goblins running: %s and %s", len(running),
+		strings.Join(running[:len(running)-1], ", "), running[len(running)-1]), nil
+}
+
+// showVoiceStatus reports on the listener and the last commands heard
+func showVoiceStatus() error {
+	listener := "stopped"
+	if trace.Command("tmux", "-L", cfg.Tmux.SocketName, "has-session", "-t", voiceSession).Run() == nil {
+		listener = "running (tmux session " + voiceSession + ")"
+	}
+	fmt.Printf("Listener: %s\n", listener)
+
+	daemonState := "stopped"
+	if ipc.IsDaemonRunning() {
+		daemonState = "running"
+		client := ipc.NewVoiceClient("")
+		if status, err := client.GetStatus(); err == nil {
+			daemonState = fmt.Sprintf("running, model %s", status.Model)
+			if status.Recording {
+				daemonState += ", recording"
+			}
+		}
+		client.Disconnect()
+	}
+	fmt.Printf("Daemon:   %s\n", daemonState)
+	fmt.Printf("Hotkey:   %s\n", cfg.Voice.Hotkey)
+	if cfg.Voice.WakeWord != "" {
+		fmt.Printf("Wake word: %q\n", cfg.Voice.WakeWord)
+	}
+
+	commands, err := db.ListVoiceCommands(voiceRecent)
+	if err != nil {
+		return err
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tHEARD\tACTION\tDECISION\tRAN")
+	for _, c := range commands {
+		ran := "no"
+		if c.Executed {
+			ran = "yes"
+		}
+		decision := c.Decision
+		if decision == "" {
+			decision = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.CreatedAt.Local().Format("01-02 15:04"), c.RawText, c.Action, decision, ran)
+	}
+	w.Flush()
+	return nil
+}
+
+// previewDigest prints the activity digest for the last window
+func previewDigest(since time.Duration) error {
+	now := time.Now()
+	d, err := digest.Generate(db, now.Add(-since), now)
+	if err != nil {
+		return fmt.Errorf("failed to build digest: %w", err)
+	}
+
+	fmt.Printf("Subject: %s\n\n", d.Subject())
+	d.Render(os.Stdout)
+	return nil
+}
+
+// sendDigest emails the activity digest for the last window
+func sendDigest(since time.Duration) error {
+	now := time.Now()
+	d, err := digest.Generate(db, now.Add(-since), now)
+	if err != nil {
+		return fmt.Errorf("failed to build digest: %w", err)
+	}
+
+	var body strings.Builder
+	d.Render(&body)
+	if err := digest.Send(cfg.Digest, d.Subject(), body.String()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Digest sent to %s\n", strings.Join(cfg.Digest.To, ", "))
+	return nil
+}
+
+// runDigest sends digests on schedule until interrupted
+func runDigest() error {
+	if !cfg.Digest.Enabled {
+		return fmt.Errorf("digests are disabled; set digest.enabled: true in config")
+	}
+	if daemon.Running(cfg.DaemonPIDFile) {
+		return fmt.Errorf("the daemon is sending digests already; stop it first")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Sending %s digests to %s (Ctrl+C to stop)...\n",
+		cfg.Digest.Schedule, strings.Join(cfg.Digest.To, ", "))
+	return digest.Run(ctx, db, cfg.Digest, log)
+}
+
+// serveAPI serves the REST API for the goblins in the local database until
+// interrupted, on api.listen or port
+func serveAPI(port int) error {
+	if remote != nil {
+		return fmt.Errorf("serve is not supported with --server")
+	}
+
+	listen := cfg.API.Listen
+	if port != 0 {
+		host, _, err := net.SplitHostPort(listen)
+		if err != nil {
+			return fmt.Errorf("invalid api.listen %q: %w", listen, err)
+		}
+		listen = net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	tokens := cfg.API.Tokens
+	if len(tokens) == 0 {
+		if token := os.Getenv("GFORGE_API_TOKEN"); token != "" {
+			tokens = []string{token}
+		}
+	}
+	if len(tokens) == 0 && !loopbackAddr(listen) {
+		return fmt.Errorf("refusing to serve %s without a token; set api.tokens or GFORGE_API_TOKEN, or listen on 127.0.0.1", listen)
+	}
+
+	issues, err := issueWebhooks()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	backend := &localBackend{coord: coordinator.New(db, cfg, log)}
+	handler := api.NewServer(backend, tokens)
+	if issues != nil {
+		handler.Mount("/webhooks/", issues)
+	}
+	commands := slackCommands(handler, backend)
+	if commands != nil {
+		handler.Mount("/slack/", commands)
+	}
+	srv := &http.Server{Addr: listen, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving the REST API on http://%s (Ctrl+C to stop)...\n", listen)
+	if issues != nil {
+		fmt.Printf("Spawning goblins for issues labeled %q from /webhooks/github and /webhooks/linear\n", issues.Label)
+	}
+	if commands != nil {
+		fmt.Println("Answering Slack commands at /slack/commands")
+	}
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("failed to serve API: %w", err)
+	}
+	return nil
+}
+
+// slackCommands is the handler for the /gforge slash command, nil without
+// a signing secret. A Slack user's commands run against backend when
+// slack.users maps them to a token srv accepts.
+func slackCommands(srv *api.Server, backend slack.Backend) *slack.Handler {
+	h := &slack.Handler{
+		SigningSecret: cfg.Slack.SigningSecret,
+		Backend: func(userID string) (slack.Backend, bool) {
+			token, ok := cfg.Slack.Token(userID)
+			if !ok || token == "" || !srv.Accepts(token) {
+				return nil, false
+			}
+			return backend, true
+		},
+	}
+	if h.SigningSecret == "" {
+		h.SigningSecret = os.Getenv("GFORGE_SLACK_SIGNING_SECRET")
+	}
+	if h.SigningSecret == "" {
+		return nil
+	}
+	return h
+}
+
+// loopbackAddr reports whether a listen address only accepts connections
+// from this machine
+func loopbackAddr(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// localBackend answers REST API requests from the local coordinator
+type localBackend struct {
+	coord *coordinator.Coordinator
+}
+
+func (b *localBackend) ListGoblins() ([]api.Goblin, error) {
+	goblins, err := b.coord.List()
+	if err != nil {
+		return nil, err
+	}
+	b.coord.CheckSessions(goblins)
+
+	out := make([]api.Goblin, len(goblins))
+	for i, g := range goblins {
+		out[i] = apiGoblin(g)
+	}
+	return out, nil
+}
+
+func (b *localBackend) GetGoblin(nameOrID string) (*api.Goblin, error) {
+	g, err := b.get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	out := apiGoblin(g)
+	return &out, nil
+}
+
+func (b *localBackend) Spawn(req api.SpawnRequest) (*api.Goblin, error) {
+	if _, err := coordinator.ParsePriority(req.Priority); err != nil {
+		return nil, err
+	}
+	opts, _, err := spawnOptions(req.Name, req.Agent, req.ProjectPath, req.Branch, "")
+	if err != nil {
+		return nil, err
+	}
+	opts.Task = req.Task
+	opts.Priority = req.Priority
+	opts.Squad = req.Squad
+
+	g, err := b.coord.Spawn(opts)
+	if err != nil {
+		return nil, err
+	}
+	out := apiGoblin(g)
+	return &out, nil
+}
+
+func (b *localBackend) Stop(nameOrID string) error {
+	return b.coord.Stop(nameOrID)
+}
+
+func (b *localBackend) Kill(nameOrID string) error {
+	return b.coord.Kill(nameOrID)
+}
+
+func (b *localBackend) SendTask(nameOrID, task string) error {
+	_, err := b.coord.SubmitTask(nameOrID, task)
+	return err
+}
+
+func (b *localBackend) Logs(nameOrID string, lines int) ([]string, error) {
+	g, err := b.get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return b.coord.Output(g, lines)
+}
+
+func (b *localBackend) Stats() (*api.Stats, error) {
+	stats, err := b.coord.Stats()
+	if err != nil {
+		return nil, err
+	}
+	return &api.Stats{
+		Total:     stats.Total,
+		Running:   stats.Running,
+		Paused:    stats.Paused,
+		Completed: stats.Completed,
+	}, nil
+}
+
+// get looks up a goblin, failing with errs.ErrGoblinNotFound when there is
+// none
+func (b *localBackend) get(nameOrID string) (*coordinator.Goblin, error) {
+	g, err := b.coord.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	return g, nil
+}
+
+// apiGoblin is the wire form of a goblin, the inverse of fetchGoblins
+func apiGoblin(g *coordinator.Goblin) api.Goblin {
+	return api.Goblin{
+		ID:           g.ID,
+		Name:         g.Name,
+		Agent:        g.Agent,
+		Status:       g.Status,
+		ProjectPath:  g.ProjectPath,
+		WorktreePath: g.WorktreePath,
+		Branch:       g.Branch,
+		TmuxSession:  g.TmuxSession,
+		TmuxSocket:   g.TmuxSocket,
+		Pinned:       g.Pinned,
+		Squad:        g.Squad,
+		CreatedAt:    g.CreatedAt,
+		UpdatedAt:    g.UpdatedAt,
+	}
+}
+
+// testWebhooks posts a ping to each outbound webhook
+func testWebhooks() error {
+	hooks := cfg.Webhooks.Outbound
+	if len(hooks) == 0 {
+		return fmt.Errorf("no outbound webhooks set up; add webhooks.outbound to config")
+	}
+
+	sender := webhook.NewSender(cfg, log)
+	ping := webhook.Delivery{Event: webhook.EventPing, Detail: "Webhooks are working", Time: time.Now()}
+	failed := 0
+	for _, hook := range hooks {
+		if err := sender.Send(hook, ping); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", hook.URL, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s: sent\n", hook.URL)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to reach %d of %d webhooks", failed, len(hooks))
+	}
+	return nil
+}
+
+// issueWebhooks is the handler spawning goblins for labeled issues, nil
+// when no tracker has a secret
+func issueWebhooks() (*webhook.Handler, error) {
+	h := &webhook.Handler{
+		Label:        cfg.Webhooks.Label,
+		GitHubSecret: cfg.Webhooks.GitHubSecret,
+		LinearSecret: cfg.Webhooks.LinearSecret,
+		Spawn:        spawnFromWebhook,
+	}
+	if h.GitHubSecret == "" {
+		h.GitHubSecret = os.Getenv("GFORGE_GITHUB_WEBHOOK_SECRET")
+	}
+	if h.LinearSecret == "" {
+		h.LinearSecret = os.Getenv("GFORGE_LINEAR_WEBHOOK_SECRET")
+	}
+	if h.GitHubSecret == "" && h.LinearSecret == "" {
+		return nil, nil
+	}
+	if len(cfg.Webhooks.Presets) == 0 {
+		return nil, fmt.Errorf("no webhook presets; set webhooks.presets in config")
+	}
+	return h, nil
+}
+
+// webhookSpawns serializes webhook spawns, so a redelivered event finds
+// the goblin the first one spawned
+var webhookSpawns sync.Mutex
+
+// spawnFromWebhook spawns a goblin for a labeled issue, unless one is
+// already linked to it
+func spawnFromWebhook(ev webhook.IssueEvent) {
+	webhookSpawns.Lock()
+	defer webhookSpawns.Unlock()
+
+	links, err := db.ListIssueLinks()
+	if err != nil {
+		log.Error("Failed to list issue links", err)
+		return
+	}
+	for _, link := range links {
+		if link.Ref == ev.Ref.String() {
+			fmt.Printf("%s already has a goblin\n", ev.Ref)
+			return
+		}
+	}
+
+	preset, ok := cfg.Webhooks.Preset(ev.Repo)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "No webhook preset for %s; skipping %s\n", ev.Repo, ev.Ref)
+		return
+	}
+	f := spawnFlags{
+		agent:    preset.Agent,
+		project:  preset.Project,
+		task:     ev.Task(preset.Task),
+		priority: preset.Priority,
+		issue:    ev.Ref.String(),
+	}
+	if err := spawnGoblin(ev.GoblinName(), f); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to spawn for %s: %v\n", ev.Ref, err)
+	}
+}
+
+// dispatchWorkflows triggers a goblin's workflows for a push or merge
+func dispatchWorkflows(name, on string) error {
+	if on != config.DispatchOnPush && on != config.DispatchOnMerge {
+		return fmt.Errorf("invalid --on %q (want push or merge)", on)
+	}
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+	project, err := config.LoadProject(goblin.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	// Runs after a merge happen where the PR went
+	pushRemote, prRemote := pushRemotes(project, "")
+	repoRemote := pushRemote
+	if on == config.DispatchOnMerge {
+		repoRemote = prRemote
+	}
+
+	runs, err := coord.Dispatch(goblin, project.Dispatch, on, workspace.RemoteRepo(goblin.WorktreePath, repoRemote))
+	printDispatched(runs)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Printf("No workflows to dispatch on %s; add them under dispatch in %s\n", on, config.ProjectFile)
+	}
+	return nil
+}
+
+// printDispatched lists workflows just triggered
+func printDispatched(runs []*storage.WorkflowRun) {
+	for _, r := range runs {
+		if r.Ref != "" {
+			fmt.Printf("Dispatched %s on %s\n", r.Workflow, r.Ref)
+		} else {
+			fmt.Printf("Dispatched %s\n", r.Workflow)
+		}
+	}
+}
+
+// showWorkflowRuns prints the runs of a goblin's dispatched workflows,
+// refreshed from GitHub, optionally until they all finish
+func showWorkflowRuns(name string, watch bool) error {
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+
+	for {
+		runs, err := coord.RefreshWorkflowRuns(goblin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if len(runs) == 0 {
+			fmt.Printf("No workflows dispatched for %s\n", goblin.Name)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "WORKFLOW\tON\tREF\tSTATUS\tDISPATCHED\tURL")
+		done := true
+		for _, r := range runs {
+			status := r.Status
+			if r.Conclusion != "" {
+				status = r.Conclusion
+			}
+			if r.Status != "completed" {
+				done = false
+			}
+			ref := r.Ref
+			if ref == "" {
+				ref = "(default)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Workflow, r.Trigger, ref, status,
+				r.CreatedAt.Local().Format("2006-01-02 15:04"), r.URL)
+		}
+		w.Flush()
+
+		if !watch || done {
+			return nil
+		}
+		time.Sleep(15 * time.Second)
+		fmt.Println()
+	}
+}
+
+// lookupGoblin resolves a goblin reference to the goblin
+func lookupGoblin(name string) (*coordinator.Coordinator, *coordinator.Goblin, error) {
+	name, err := resolveGoblinRef(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	goblin, err := coord.Get(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return nil, nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+	return coord, goblin, nil
+}
+
+// linkIssue links a goblin to the issue it works on
+func linkIssue(name, issue string) error {
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+	ref, err := coord.LinkIssue(goblin, issue)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Linked %s to %s\n", goblin.Name, ref)
+	return nil
+}
+
+// unlinkIssue stops progress comments for a goblin
+func unlinkIssue(name string) error {
+	_, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+	if err := db.UnlinkIssue(goblin.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Unlinked %s\n", goblin.Name)
+	return nil
+}
+
+// showProgress prints the comment the next progress update would post
+func showProgress(name string) error {
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+	link, err := db.GetIssueLink(goblin.ID)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return fmt.Errorf("%s is not linked to an issue (see gforge progress link)", goblin.Name)
+	}
+
+	since := link.CommentedAt
+	if since.IsZero() {
+		since = link.CreatedAt
+	}
+	report, err := coord.ProgressReport(goblin, since)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Issue: %s\n\n%s", link.Ref, report)
+	return nil
+}
+
+// postProgress posts a goblin's progress comment now
+func postProgress(name string) error {
+	coord, goblin, err := lookupGoblin(name)
+	if err != nil {
+		return err
+	}
+	if err := coord.PostProgress(goblin); err != nil {
+		return err
+	}
+	fmt.Printf("Posted progress of %s\n", goblin.Name)
+	return nil
+}
+
+// runProgress posts due progress comments until interrupted
+func runProgress() error {
+	interval := cfg.Integrations.ProgressInterval
+	if interval <= 0 {
+		return fmt.Errorf("progress comments are disabled; set integrations.progress_interval in config")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	coord := coordinator.New(db, cfg, log)
+
+	// Checking more often than the interval keeps new links from waiting
+	// a whole interval for their first comment
+	check := interval / 4
+	if check > 5*time.Minute {
+		check = 5 * time.Minute
+	}
+	if check < time.Minute {
+		check = time.Minute
+	}
+
+	fmt.Printf("Posting progress comments every %s (Ctrl+C to stop)...\n", interval)
+	for {
+		if _, err := coord.PostDueProgress(interval, time.Now()); err != nil {
+			log.Error("Failed to post progress comments", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(check):
+		}
+	}
+}
+
+// printStatusLine prints goblin counts for status bars
+func printStatusLine(format string) error {
+	byStatus, err := db.CountByStatus()
+	if err != nil {
+		return err
+	}
+
+	line, err := statusline.Format(statusline.FromStatuses(byStatus), format)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(line)
+	return nil
+}
+
+// printPromptInfo prints the goblin owning path, or nothing
+func printPromptInfo(path, format string) error {
+	coord := coordinator.New(db, cfg, nil)
+
+	goblin, err := coord.FindByPath(path)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return nil
+	}
+
+	line, err := statusline.FormatPrompt(statusline.PromptInfo{
+		Name:   goblin.Name,
+		Agent:  goblin.Agent,
+		Status: goblin.Status,
+		Branch: goblin.Branch,
+	}, format)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(line)
+	return nil
+}
+
+// resolveGoblinRef turns "" into the current goblin (gforge use), "."
+// into the goblin whose worktree contains the current directory, and a
+// number into the goblin at that position in gforge list. Names, IDs and
+// aliases pass through.
+func resolveGoblinRef(ref string) (string, error) {
+	if ref == "" {
+		if current := config.CurrentGoblin(); current != "" {
+			return current, nil
+		}
+		return "", fmt.Errorf("no goblin given and no current goblin set (see gforge use)")
+	}
+
+	index, numErr := strconv.Atoi(ref)
+	if (ref != "." && numErr != nil) || (numErr == nil && remote != nil) {
+		return ref, nil
+	}
+	if remote != nil {
+		return "", fmt.Errorf("'.' cannot be resolved against a remote server; use the goblin name")
+	}
+
+	coord := coordinator.New(db, cfg, log)
+
+	if ref == "." {
+		goblin, err := coord.FindByPath(".")
+		if err != nil {
+			return "", err
+		}
+		if goblin == nil {
+			cwd, _ := os.Getwd()
+			return "", fmt.Errorf("not inside a goblin worktree: %s", cwd)
+		}
+		return goblin.Name, nil
+	}
+
+	// A goblin actually named with digits wins over the index
+	if goblin, err := coord.Get(ref); err == nil && goblin != nil {
+		return goblin.Name, nil
+	}
+	goblin, err := coord.GetByIndex(index)
+	if err != nil {
+		return "", err
+	}
+	if goblin == nil {
+		return "", fmt.Errorf("no goblin at index %d (see gforge list)", index)
+	}
+	return goblin.Name, nil
+}
+
+// whichGoblin prints the goblin whose worktree contains path
+func whichGoblin(path string, nameOnly bool) error {
+	coord := coordinator.New(db, cfg, log)
+
+	goblin, err := coord.FindByPath(path)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		abs, _ := filepath.Abs(path)
+		return fmt.Errorf("not inside a goblin worktree: %s", abs)
+	}
+
+	if nameOnly {
+		fmt.Println(goblin.Name)
+		return nil
+	}
+
+	fmt.Printf("Goblin:   %s\n", goblin.Name)
+	fmt.Printf("  ID:       %s\n", goblin.ID)
+	fmt.Printf("  Agent:    %s\n", goblin.Agent)
+	fmt.Printf("  Status:   %s\n", goblin.Status)
+	fmt.Printf("  Branch:   %s\n", goblin.Branch)
+	fmt.Printf("  Worktree: %s\n", goblin.WorktreePath)
+	fmt.Printf("  Project:  %s\n", goblin.ProjectPath)
+	if goblin.Squad != "" {
+		fmt.Printf("  Squad:    %s\n", goblin.Squad)
+	}
+	fmt.Printf("  Age:      %s\n", goblin.Age())
+	return nil
+}
+
+// useGoblin sets, clears or prints the current goblin context
+func useGoblin(ref string, clearCurrent bool) error {
+	if clearCurrent {
+		if err := config.SetCurrentGoblin(""); err != nil {
+			return err
+		}
+		fmt.Println("Cleared current goblin")
+		return nil
+	}
+
+	if ref == "" {
+		current := config.CurrentGoblin()
+		if current == "" {
+			fmt.Println("No current goblin. Set one with: gforge use <goblin>")
+			return nil
+		}
+		fmt.Println(current)
+		return nil
+	}
+
+	name, err := resolveGoblinRef(ref)
+	if err != nil {
+		return err
+	}
+
+	goblin, err := coordinator.New(db, cfg, log).Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to get goblin: %w", err)
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+
+	if err := config.SetCurrentGoblin(goblin.Name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Now using goblin: %s\n", goblin.Name)
+	return nil
+}
+
+// manageAliases sets, removes or lists goblin aliases
+func manageAliases(args []string, remove bool) error {
+	coord := coordinator.New(db, cfg, log)
+
+	switch {
+	case remove:
+		if len(args) != 1 {
+			return fmt.Errorf("usage: gforge alias --rm <alias>")
+		}
+		if err := coord.RemoveAlias(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed alias: %s\n", args[0])
+		return nil
+
+	case len(args) == 2:
+		name, err := resolveGoblinRef(args[1])
+		if err != nil {
+			return err
+		}
+		if err := coord.SetAlias(args[0], name); err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s\n", args[0], name)
+		return nil
+
+	case len(args) == 0:
+		aliases, err := coord.Aliases()
+		if err != nil {
+			return err
+		}
+		goblins, err := coord.List()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ALIAS\tGOBLIN")
+		for _, g := range goblins {
+			for _, alias := range aliases[g.ID] {
+				fmt.Fprintf(w, "%s\t%s\n", alias, g.Name)
+			}
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("usage: gforge alias <alias> <goblin>")
+	}
+}
+
+// bulkAction applies a single-goblin action to every goblin a selector
+// matches and summarizes the outcome
+func bulkAction(verb string, sel coordinator.Selector, dryRun bool, action func(string) error) error {
+	goblins, err := fetchGoblins()
+	if err != nil {
+		return fmt.Errorf("failed to list goblins: %w", err)
+	}
+
+	matched := sel.Filter(goblins, time.Now())
+	if len(matched) == 0 {
+		fmt.Println("No goblins matched.")
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("Would %s %d goblin(s):\n", verb, len(matched))
+		for _, g := range matched {
+			fmt.Printf("  %s (%s, %s, %s)\n", g.Name, g.Agent, g.Status, g.Age())
+		}
+		return nil
+	}
+
+	var done, failed []string
+	for _, g := range matched {
+		if err := action(g.Name); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", g.Name, err))
+			continue
+		}
+		done = append(done, g.Name)
+	}
+
+	fmt.Println()
+	fmt.Printf("Summary: %d of %d goblin(s) affected by %s\n", len(done), len(matched), verb)
+	if len(failed) > 0 {
+		fmt.Println("Failed:")
+		for _, f := range failed {
+			fmt.Printf("  %s\n", f)
+		}
+		return fmt.Errorf("%d goblin(s) failed to %s", len(failed), verb)
+	}
+	return nil
+}
+
+// shutdownGoblins stops all active goblins, saving their work per policy
+func shutdownGoblins(policy string) error {
+	results, err := coordinator.New(db, cfg, log).Shutdown(policy)
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No active goblins.")
+		return nil
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("  %-20s failed: %v\n", r.Name, r.Err)
+			continue
+		}
+		fmt.Printf("  %-20s stopped (%s)\n", r.Name, r.Saved)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d goblin(s) failed to shut down cleanly", failed)
+	}
+	fmt.Printf("Shut down %d goblin(s).\n", len(results))
+	return nil
+}
+
+// runDaemon supervises goblins until interrupted, or for one pass
+func runDaemon(once bool) error {
+	if remote != nil {
+		return fmt.Errorf("daemon is not supported with --server")
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	supervisor := daemon.NewSupervisor(coord, cfg, log)
+	supervisor.SpawnScheduled = func(s *storage.Schedule, name string) (*coordinator.Goblin, error) {
+		return spawnScheduled(coord, s, name)
+	}
+	supervisor.SendDigest = func(since, until time.Time) (bool, error) {
+		return digest.SendWindow(db, cfg.Digest, since, until)
+	}
+
+	if once {
+		report := supervisor.Tick(time.Now())
+		for _, g := range report.Exited {
+			fmt.Printf("%s: session ended, marked dead\n", g.Name)
+		}
+		for _, c := range report.Committed {
+			fmt.Printf("%s: %d new commit(s)\n", c.Goblin.Name, c.Count)
+		}
+		for _, f := range report.Fed {
+			fmt.Printf("%s: sent queued task %q\n", f.Goblin.Name, firstLine(f.Task, 60))
+		}
+		for _, r := range report.Scheduled {
+			switch {
+			case r.Err != nil:
+				fmt.Printf("%s: failed to spawn: %v\n", r.Schedule, r.Err)
+			case r.Skipped:
+				fmt.Printf("%s: skipped, %s is still running\n", r.Schedule, r.Goblin)
+			case r.Deferred:
+				fmt.Printf("%s: deferred, %s\n", r.Schedule, r.Held)
+			case r.Held != "":
+				fmt.Printf("%s: skipped, %s\n", r.Schedule, r.Held)
+			default:
+				fmt.Printf("%s: spawned %s\n", r.Schedule, r.Goblin)
+			}
+		}
+		for _, o := range report.Overlaps {
+			fmt.Printf("%s\n", o)
+		}
+		for _, g := range report.Merged {
+			fmt.Printf("%s: pull request merged\n", g.Name)
+		}
+		for _, g := range report.Cleaned {
+			fmt.Printf("%s: idle, archived\n", g.Name)
+		}
+		if len(report.Exited)+len(report.Committed)+len(report.Fed)+len(report.Scheduled)+len(report.Overlaps)+len(report.Merged)+len(report.Cleaned) == 0 {
+			fmt.Println("Nothing to do.")
+		}
+		return nil
+	}
+
+	release, err := daemon.AcquirePIDFile(cfg.DaemonPIDFile)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	supervisor.ReloadConfig()
+	fmt.Printf("Supervising goblins every %s (Ctrl+C to stop)...\n", cfg.Daemon.Interval)
+	return supervisor.Run(ctx)
+}
+
+// spawnScheduled spawns the goblin for a schedule's run
+func spawnScheduled(coord *coordinator.Coordinator, s *storage.Schedule, name string) (*coordinator.Goblin, error) {
+	opts, _, err := spawnOptions(name, s.Agent, s.ProjectPath, "", "")
+	if err != nil {
+		return nil, err
+	}
+	opts.Task = s.Task
+	opts.Priority = s.Priority
+	opts.Squad = s.Squad
+	return coord.Spawn(opts)
+}
+
+// listSchedules prints the schedules, those in the config file included,
+// and when they next run
+func listSchedules(output string) error {
+	if remote != nil {
+		return fmt.Errorf("schedule is not supported with --server")
+	}
+	if err := table.Validate(output); err != nil {
+		return err
+	}
+
+	coord := coordinator.New(db, cfg, log)
+	if err := coord.SyncSchedules(cfg.Schedules, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	schedules, err := coord.Schedules()
+	if err != nil {
+		return err
+	}
+	if len(schedules) == 0 && output == "table" {
+		fmt.Println("No schedules. Add one with: gforge schedule add <name> --cron \"0 9 * * mon\" --task <task>")
+		return nil
+	}
+
+	t := table.New("NAME", "CRON", "NEXT RUN", "LAST GOBLIN", "PROJECT", "TASK")
+	for _, s := range schedules {
+		name := s.Name
+		if s.FromConfig {
+			name += " (config)"
+		}
+		last := s.LastGoblin
+		if last == "" {
+			last = "-"
+		}
+		t.Add(name, s.Cron, s.NextRun.Local().Format("2006-01-02 15:04"), last, s.ProjectPath, firstLine(s.Task, 40))
+	}
+	return t.Write(os.Stdout, output)
+}
+
+// addSchedule stores a recurring goblin run for gforge daemon
+func addSchedule(name, cronExpr, projectPath, agentName, task, priority, squad string) error {
+	if remote != nil {
+		return fmt.Errorf("schedule is not supported with --server")
+	}
+	if agentName != "" && agents.NewRegistry().Get(agentName) == nil {
 		return fmt.Errorf("unknown agent: %s (available: claude, codex, gemini, ollama)", agentName)
 	}
-
-	// Resolve project path
 	absPath, err := filepath.Abs(projectPath)
 	if err != nil {
 		return fmt.Errorf("invalid project path: %w", err)
 	}
-
-	// Check if project exists
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
 		return fmt.Errorf("project path does not exist: %s", absPath)
 	}
 
-	// Generate branch name if not provided
-	if branch == "" {
-		branch = fmt.Sprintf("gforge/%s", name)
-	}
-
-	// Create coordinator
-	coord := coordinator.New(db, cfg, log)
-
-	// Spawn goblin
-	goblin, err := coord.Spawn(coordinator.SpawnOptions{
+	s := &storage.Schedule{
 		Name:        name,
-		Agent:       agent,
+		Cron:        cronExpr,
 		ProjectPath: absPath,
-		Branch:      branch,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to spawn goblin: %w", err)
+		Agent:       agentName,
+		Task:        task,
+		Priority:    priority,
+		Squad:       squad,
+	}
+	if err := coordinator.New(db, cfg, log).AddSchedule(s, time.Now()); err != nil {
+		return err
 	}
 
-	fmt.Printf("Spawned goblin: %s\n", goblin.Name)
-	fmt.Printf("  ID:       %s\n", goblin.ID)
-	fmt.Printf("  Agent:    %s\n", goblin.Agent)
-	fmt.Printf("  Branch:   %s\n", goblin.Branch)
-	fmt.Printf("  Worktree: %s\n", goblin.WorktreePath)
-	fmt.Printf("  Status:   %s\n", goblin.Status)
-	fmt.Println()
-	fmt.Printf("Attach with: gforge attach %s\n", name)
-
+	fmt.Printf("Added schedule %s, next run %s\n", s.Name, s.NextRun.Local().Format("Mon 2006-01-02 15:04"))
+	fmt.Println("Schedules run while gforge daemon is running.")
 	return nil
 }
 
-// listGoblins displays all active goblins
-func listGoblins() error {
+// removeSchedules deletes schedules added with gforge schedule add
+func removeSchedules(names []string) error {
+	if remote != nil {
+		return fmt.Errorf("schedule is not supported with --server")
+	}
 	coord := coordinator.New(db, cfg, log)
-	goblins, err := coord.List()
+	for _, name := range names {
+		if err := coord.RemoveSchedule(name); err != nil {
+			return err
+		}
+		fmt.Printf("Removed schedule %s\n", name)
+	}
+	return nil
+}
+
+// installDaemon writes the systemd unit that runs gforge shutdown, and
+// gforge daemon when supervise is set
+func installDaemon(printOnly, supervise bool) error {
+	exe, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("failed to list goblins: %w", err)
+		return fmt.Errorf("failed to locate gforge binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
 	}
 
-	if len(goblins) == 0 {
-		fmt.Println("No active goblins.")
-		fmt.Println()
-		fmt.Println("Spawn one with: gforge spawn <name> --agent <agent>")
-		return nil
+	opts := daemon.UnitOptions{
+		Exe:       exe,
+		Path:      os.Getenv("PATH"),
+		Supervise: supervise,
+	}
+	if cfgFile != "" {
+		if opts.Config, err = filepath.Abs(cfgFile); err != nil {
+			return fmt.Errorf("failed to resolve config path: %w", err)
+		}
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tAGENT\tSTATUS\tBRANCH\tAGE")
-	fmt.Fprintln(w, "--\t----\t-----\t------\t------\t---")
+	if printOnly {
+		unit, err := daemon.Unit(opts)
+		if err != nil {
+			return err
+		}
+		fmt.Print(unit)
+		return nil
+	}
 
-	for i, g := range goblins {
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
-			i+1, g.Name, g.Agent, g.Status, g.Branch, g.Age())
+	path, enabled, err := daemon.Install(opts)
+	if err != nil {
+		return err
 	}
 
-	w.Flush()
+	fmt.Printf("Installed %s\n", path)
+	if !enabled {
+		fmt.Println("systemctl not found; enable it with:")
+		fmt.Printf("  systemctl --user enable --now %s\n", daemon.UnitName)
+	} else {
+		fmt.Println("Goblins will be stopped cleanly when the system shuts down.")
+		if supervise {
+			fmt.Printf("gforge daemon is running; follow it with: journalctl --user -u %s -f\n", daemon.UnitName)
+		}
+	}
 	return nil
 }
 
-// stopGoblin stops a running goblin
-func stopGoblin(name string) error {
-	coord := coordinator.New(db, cfg, log)
+// exportState writes goblin definitions as YAML
+func exportState(output string) error {
+	st, err := state.Export(db)
+	if err != nil {
+		return fmt.Errorf("failed to export state: %w", err)
+	}
 
-	if err := coord.Stop(name); err != nil {
-		return fmt.Errorf("failed to stop goblin: %w", err)
+	if output == "" {
+		return state.Write(os.Stdout, st)
 	}
 
-	fmt.Printf("Stopped goblin: %s\n", name)
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	defer f.Close()
+
+	if err := state.Write(f, st); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d goblin(s) to %s\n", len(st.Goblins), output)
 	return nil
 }
 
-// showStatus displays system status
-func showStatus() error {
-	coord := coordinator.New(db, cfg, log)
+// importState recreates goblin definitions from a YAML file
+func importState(path string) error {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
 
-	// Get stats
-	stats, err := coord.Stats()
+	st, err := state.Read(r)
 	if err != nil {
-		return fmt.Errorf("failed to get stats: %w", err)
+		return err
 	}
 
-	fmt.Println("Goblin Forge Status")
-	fmt.Println("===================")
-	fmt.Println()
-	fmt.Printf("Goblins:\n")
-	fmt.Printf("  Running:   %d\n", stats.Running)
-	fmt.Printf("  Paused:    %d\n", stats.Paused)
-	fmt.Printf("  Completed: %d\n", stats.Completed)
-	fmt.Printf("  Total:     %d\n", stats.Total)
-	fmt.Println()
-	fmt.Printf("System:\n")
-	fmt.Printf("  Config:    %s\n", config.GetConfigPath(cfgFile))
-	fmt.Printf("  Database:  %s\n", cfg.DatabasePath)
-	fmt.Printf("  Worktrees: %s\n", cfg.WorktreeBase)
-
-	// Check for installed agents
-	registry := agents.NewRegistry()
-	detected := registry.Scan()
-	fmt.Println()
-	fmt.Printf("Agents: %d installed\n", len(detected))
-	for _, d := range detected {
-		fmt.Printf("  - %s (%s)\n", d.Name, d.Version)
+	result, err := state.Import(db, st)
+	if result != nil {
+		for _, name := range result.Imported {
+			fmt.Printf("  imported  %s\n", name)
+		}
+		for _, name := range result.Skipped {
+			fmt.Printf("  skipped   %s (already exists)\n", name)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to import state: %w", err)
 	}
 
+	fmt.Printf("Imported %d goblin(s), skipped %d.\n", len(result.Imported), len(result.Skipped))
 	return nil
 }
 
-// killGoblin forcefully terminates a goblin and cleans up resources
-func killGoblin(name string) error {
-	coord := coordinator.New(db, cfg, log)
+// scaffoldProject writes the starter gforge files into a repository
+func scaffoldProject(dir string, force bool) error {
+	result, err := scaffold.Write(dir, force)
+	if err != nil {
+		return err
+	}
 
-	if err := coord.Kill(name); err != nil {
-		return fmt.Errorf("failed to kill goblin: %w", err)
+	for _, path := range result.Created {
+		fmt.Printf("  created  %s\n", path)
+	}
+	for _, path := range result.Skipped {
+		fmt.Printf("  exists   %s\n", path)
+	}
+	if result.GitignoreUpdated {
+		fmt.Println("  updated  .gitignore")
 	}
 
-	fmt.Printf("Killed goblin: %s\n", name)
-	fmt.Println("  tmux session terminated")
-	fmt.Println("  worktree removed")
+	if len(result.Created) == 0 && !result.GitignoreUpdated {
+		fmt.Println("Already scaffolded; use --force to overwrite.")
+		return nil
+	}
+	fmt.Printf("Edit %s to set the team's defaults and commit the result.\n", config.ProjectFile)
 	return nil
 }
 
-// attachGoblin attaches to a goblin's tmux session
-func attachGoblin(name string) error {
-	coord := coordinator.New(db, cfg, log)
+// buildContext assembles a context pack, stores it and optionally sends it
+func buildContext(project, name string, paths []string, maxTokens, commits int, sendTo string, printPack bool) error {
+	absPath, err := filepath.Abs(project)
+	if err != nil {
+		return fmt.Errorf("invalid project path: %w", err)
+	}
+	if name == "" {
+		name = contextpack.DefaultName(absPath, paths)
+	}
 
-	goblin, err := coord.Get(name)
+	pack, err := contextpack.Build(absPath, name, contextpack.Options{
+		Paths:     paths,
+		MaxTokens: maxTokens,
+		Commits:   commits,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get goblin: %w", err)
+		return fmt.Errorf("failed to build context pack: %w", err)
 	}
-	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", name)
+
+	path, err := contextpack.Save(cfg.ContextsDir, pack)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Attaching to goblin: %s\n", name)
-	fmt.Println("Use Ctrl+B D to detach")
-	fmt.Println()
+	if printPack {
+		fmt.Print(pack.Content)
+		return nil
+	}
 
-	// Small delay for user to read
-	time.Sleep(500 * time.Millisecond)
+	fmt.Printf("Built context pack: %s\n", pack.Name)
+	fmt.Printf("  Tokens:  ~%d of %d\n", pack.Tokens, maxTokens)
+	fmt.Printf("  Files:   %d included, %d omitted\n", len(pack.Files), len(pack.Omitted))
+	fmt.Printf("  Stored:  %s\n", path)
 
-	return coord.Attach(name)
+	if sendTo != "" {
+		return sendContext(pack.Name, sendTo)
+	}
+	return nil
 }
 
-// showLogs displays goblin output logs
-func showLogs(name string, lines int, follow bool) error {
-	coord := coordinator.New(db, cfg, log)
-
-	goblin, err := coord.Get(name)
+// sendContext pastes a stored context pack into a goblin's session
+func sendContext(name, goblinName string) error {
+	goblinName, err := resolveGoblinRef(goblinName)
 	if err != nil {
-		return fmt.Errorf("failed to get goblin: %w", err)
+		return err
 	}
-	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", name)
+
+	content, err := contextpack.Load(cfg.ContextsDir, name)
+	if err != nil {
+		return err
 	}
 
-	// Create tmux manager to get output
-	tmuxMgr := tmux.NewManager(tmux.Config{
-		SocketName: cfg.Tmux.SocketName,
-	})
+	if err := coordinator.New(db, cfg, log).SendText(goblinName, content, "[context] "+name); err != nil {
+		return fmt.Errorf("failed to send context pack: %w", err)
+	}
 
-	if follow {
-		// Follow mode - continuously capture pane
-		fmt.Printf("Following logs for %s (Ctrl+C to stop)...\n\n", name)
-		lastOutput := ""
-		for {
-			output, err := tmuxMgr.CapturePane(goblin.TmuxSession, lines)
-			if err != nil {
-				return fmt.Errorf("failed to capture output: %w", err)
-			}
+	fmt.Printf("Sent context pack %s to %s\n", name, goblinName)
+	return nil
+}
 
-			// Only print new content
-			if output != lastOutput {
-				// Clear screen and print
-				fmt.Print("\033[2J\033[H") // Clear screen, move to top
-				fmt.Printf("=== Logs: %s ===\n\n", name)
-				fmt.Println(output)
-				lastOutput = output
-			}
+// showContext prints a stored context pack
+func showContext(name string) error {
+	content, err := contextpack.Load(cfg.ContextsDir, name)
+	if err != nil {
+		return err
+	}
+	fmt.Print(content)
+	return nil
+}
 
-			time.Sleep(500 * time.Millisecond)
-		}
-	} else {
-		// One-shot capture
-		output, err := tmuxMgr.CapturePane(goblin.TmuxSession, lines)
-		if err != nil {
-			return fmt.Errorf("failed to capture output: %w", err)
-		}
+// listContexts shows stored context packs
+func listContexts() error {
+	packs, err := contextpack.List(cfg.ContextsDir)
+	if err != nil {
+		return fmt.Errorf("failed to list context packs: %w", err)
+	}
 
-		fmt.Printf("=== Logs: %s (last %d lines) ===\n\n", name, lines)
-		fmt.Println(output)
+	if len(packs) == 0 {
+		fmt.Println("No context packs. Build one with: gforge context build --paths <dir>")
+		return nil
 	}
 
-	return nil
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTOKENS\tBUILT")
+	fmt.Fprintln(w, "----\t------\t-----")
+	for _, p := range packs {
+		fmt.Fprintf(w, "%s\t~%d\t%s\n", p.Name, p.Tokens, p.UpdatedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
 }
 
-// showDiff displays changes made by a goblin
-func showDiff(name string, staged bool) error {
-	coord := coordinator.New(db, cfg, log)
+// printRepoMap prints the repository map for a project
+func printRepoMap(project string, paths []string, maxTokens int) error {
+	absPath, err := filepath.Abs(project)
+	if err != nil {
+		return fmt.Errorf("invalid project path: %w", err)
+	}
 
-	goblin, err := coord.Get(name)
+	m, err := repomap.Build(absPath, paths)
 	if err != nil {
-		return fmt.Errorf("failed to get goblin: %w", err)
+		return fmt.Errorf("failed to build repository map: %w", err)
 	}
-	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", name)
+	if m.Files == 0 {
+		return fmt.Errorf("no files found in %s", absPath)
 	}
 
-	// Create workspace manager
-	wsMgr := workspace.NewWorktreeManager(workspace.Config{
-		BasePath: cfg.WorktreeBase,
-	})
+	fmt.Print(m.Render(maxTokens))
+	return nil
+}
 
-	// Get changed files
-	changes, err := wsMgr.GetChanges(goblin.WorktreePath)
+// setTelemetry turns usage telemetry on or off
+func setTelemetry(enabled bool) error {
+	t, err := telemetry.Open(cfg.TelemetryDir)
 	if err != nil {
-		return fmt.Errorf("failed to get changes: %w", err)
+		return err
+	}
+	if err := t.SetEnabled(enabled); err != nil {
+		return err
 	}
 
-	if len(changes) == 0 {
-		fmt.Printf("No changes in %s\n", name)
+	if !enabled {
+		fmt.Println("Telemetry is off; unsent events were deleted.")
 		return nil
 	}
+	fmt.Println("Telemetry is on. Thanks! See what is recorded with: gforge telemetry status")
+	if telemetry.OptedOut() {
+		fmt.Println("Note: DO_NOT_TRACK or GFORGE_TELEMETRY=0 is set, which keeps it off in this environment.")
+	}
+	if cfg.Telemetry.Endpoint == "" {
+		fmt.Println("No telemetry.endpoint is set, so events stay in the local spool.")
+	}
+	return nil
+}
+
+// telemetryStatus shows whether telemetry is on and the events waiting to
+// be sent
+func telemetryStatus() error {
+	t, err := telemetry.Open(cfg.TelemetryDir)
+	if err != nil {
+		return err
+	}
+	st := t.State()
 
-	fmt.Printf("=== Changes in %s (%d files) ===\n\n", name, len(changes))
+	switch {
+	case t.Enabled():
+		fmt.Printf("Telemetry: on (install %s)\n", st.InstallID)
+	case st.Enabled:
+		fmt.Println("Telemetry: off in this environment (DO_NOT_TRACK or GFORGE_TELEMETRY=0)")
+	default:
+		fmt.Println("Telemetry: off")
+		return nil
+	}
 
-	// Show file list
-	fmt.Println("Changed files:")
-	for _, f := range changes {
-		fmt.Printf("  %s\n", f)
+	if cfg.Telemetry.Endpoint != "" {
+		fmt.Printf("Endpoint:  %s, every %s\n", cfg.Telemetry.Endpoint, cfg.Telemetry.SendInterval)
+	} else {
+		fmt.Println("Endpoint:  none; events stay local")
+	}
+	if st.LastSent.IsZero() {
+		fmt.Println("Last sent: never")
+	} else {
+		fmt.Printf("Last sent: %s\n", st.LastSent.Local().Format("2006-01-02 15:04"))
 	}
-	fmt.Println()
 
-	// Show diff
-	diff, err := wsMgr.GetDiff(goblin.WorktreePath, staged)
+	events, err := t.Pending()
 	if err != nil {
-		return fmt.Errorf("failed to get diff: %w", err)
+		return err
 	}
-
-	if diff == "" {
-		if staged {
-			fmt.Println("No staged changes.")
-		} else {
-			fmt.Println("No unstaged changes (files may be staged).")
-		}
+	fmt.Printf("Pending:   %d event(s) in %s\n", len(events), t.SpoolPath())
+	if len(events) == 0 {
 		return nil
 	}
 
-	// Colorize diff output
-	lines := strings.Split(diff, "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
-			fmt.Printf("\033[32m%s\033[0m\n", line) // Green
-		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
-			fmt.Printf("\033[31m%s\033[0m\n", line) // Red
-		} else if strings.HasPrefix(line, "@@") {
-			fmt.Printf("\033[36m%s\033[0m\n", line) // Cyan
-		} else if strings.HasPrefix(line, "diff") || strings.HasPrefix(line, "index") {
-			fmt.Printf("\033[1m%s\033[0m\n", line) // Bold
-		} else {
-			fmt.Println(line)
+	counts := make(map[string]int)
+	for _, e := range events {
+		key := e.Command
+		if e.Agent != "" {
+			key += " (" + e.Agent + ")"
+		}
+		if e.Error != "" {
+			key += " error=" + e.Error
 		}
+		counts[key]++
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Println()
+	for _, k := range keys {
+		fmt.Printf("  %4d  %s\n", counts[k], k)
 	}
-
 	return nil
 }
 
-// sendTask sends a task to a goblin
-func sendTask(task, goblinName string) error {
-	coord := coordinator.New(db, cfg, log)
-
-	goblin, err := coord.Get(goblinName)
-	if err != nil {
-		return fmt.Errorf("failed to get goblin: %w", err)
+// agentKind reports a built-in agent by name and anything else as custom,
+// so user-defined agent names are never recorded
+func agentKind(name string) string {
+	if name == "" {
+		return ""
 	}
-	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", goblinName)
+	if agents.NewRegistry().Get(name) != nil {
+		return name
 	}
+	return "custom"
+}
 
-	if err := coord.SendTask(goblinName, task); err != nil {
-		return fmt.Errorf("failed to send task: %w", err)
+// listConfigEnv prints the environment variable for each config key and
+// its value where set
+func listConfigEnv(setOnly bool, output string) error {
+	if err := table.Validate(output); err != nil {
+		return err
 	}
 
-	fmt.Printf("Task sent to %s:\n", goblinName)
-	fmt.Printf("  \"%s\"\n", task)
-
-	return nil
+	t := table.New("VARIABLE", "KEY", "VALUE")
+	for _, v := range config.EnvVars() {
+		value, set := v.Value()
+		if setOnly && !set {
+			continue
+		}
+		if set && value != "" && secretKey(v.Key) {
+			value = "********"
+		}
+		t.Add(v.Name, v.Key, value)
+	}
+	return t.Write(os.Stdout, output)
 }
 
-// Suppress unused import warnings during development
-var (
-	_ = time.Now
-	_ = strings.TrimSpace
-	_ = tmux.NewManager
-	_ = workspace.NewWorktreeManager
-)
+// secretKey reports whether a config key holds a credential
+func secretKey(key string) bool {
+	for _, word := range []string{"secret", "token", "password", "api_key"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
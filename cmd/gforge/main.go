@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/astoreyai/goblin-forge/internal/api"
 	"github.com/astoreyai/goblin-forge/internal/config"
 	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/daemon"
+	"github.com/astoreyai/goblin-forge/internal/errs"
 	"github.com/astoreyai/goblin-forge/internal/logging"
 	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/style"
+	"github.com/astoreyai/goblin-forge/internal/telemetry"
+	"github.com/astoreyai/goblin-forge/internal/trace"
 	"github.com/astoreyai/goblin-forge/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -19,11 +30,14 @@ var (
 )
 
 var (
-	cfgFile string
-	verbose bool
-	cfg     *config.Config
-	db      *storage.DB
-	log     *logging.Logger
+	cfgFile   string
+	verbose   int
+	serverURL string
+	noColor   bool
+	cfg       *config.Config
+	db        *storage.DB
+	log       *logging.Logger
+	remote    *api.Client
 )
 
 func main() {
@@ -36,42 +50,192 @@ designed to coordinate and execute multiple coding-focused CLI agents in paralle
 "Where code is forged by many small minds."`,
 		PersistentPreRunE: initializeApp,
 		SilenceUsage:      true,
+		SilenceErrors:     true, // printed by presentError
 	}
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default ~/.config/gforge/config.yaml)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "verbose output (-vv also echoes git, tmux and gh commands)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&serverURL, "server", "", "control a remote gforge server (e.g. http://host:7474)")
 
 	// Add commands
 	rootCmd.AddCommand(
 		newVersionCmd(),
 		newConfigCmd(),
 		newAgentsCmd(),
+		newProvidersCmd(),
+		newDoctorCmd(),
 		newSpawnCmd(),
 		newListCmd(),
+		newEventsCmd(),
+		newHistoryCmd(),
+		newTasksCmd(),
+		newNoteCmd(),
+		newPinCmd(),
+		newUnpinCmd(),
+		newLockCmd(),
+		newUnlockCmd(),
+		newLocksCmd(),
+		newPadCmd(),
+		newArchiveCmd(),
+		newUnarchiveCmd(),
+		newNotesCmd(),
+		newArtifactsCmd(),
 		newStopCmd(),
 		newKillCmd(),
+		newPauseCmd(),
+		newResumeCmd(),
 		newAttachCmd(),
 		newLogsCmd(),
+		newSummaryCmd(),
+		newAskCmd(),
 		newDiffCmd(),
+		newPushCmd(),
+		newDoneCmd(),
+		newAcceptCmd(),
+		newApplyCmd(),
+		newSecretsCmd(),
+		newDepsCmd(),
+		newCheckCmd(),
+		newSizesCmd(),
+		newFreezesCmd(),
 		newTaskCmd(),
 		newStatusCmd(),
+		newStatsCmd(),
+		newCostCmd(),
 		newTopCmd(),
+		newOpenAPICmd(),
+		newServeCmd(),
+		newBenchCmd(),
+		newReplayCmd(),
+		newFanOutCmd(),
+		newRunCmd(),
+		newPlayCmd(),
+		newNotifyCmd(),
+		newVoiceCmd(),
+		newDigestCmd(),
+		newProgressCmd(),
+		newWebhookCmd(),
+		newActionsCmd(),
+		newStatusLineCmd(),
+		newPromptInfoCmd(),
+		newWhichCmd(),
+		newUseCmd(),
+		newAliasCmd(),
+		newShutdownCmd(),
+		newStateCmd(),
+		newScaffoldCmd(),
+		newContextCmd(),
+		newMapCmd(),
+		newDaemonCmd(),
+		newScheduleCmd(),
+		newTelemetryCmd(),
+		newRecordPaneCmd(),
 	)
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	recordUsage(cmd, err)
+	if err != nil {
+		presentError(os.Stderr, cmd, err)
+		os.Exit(errs.ExitCode(err))
+	}
+}
+
+// usageErrors start the messages cobra fails with before a command runs
+var usageErrors = []string{"unknown command", "unknown flag", "unknown shorthand flag", "invalid argument",
+	"flag needs an argument", "accepts ", "requires at least", "requires at most"}
+
+// presentError prints the error a command failed with, followed by the
+// command that fixes it: the one attached with errs.WithHint, or the
+// command's help for a usage mistake
+func presentError(w io.Writer, cmd *cobra.Command, err error) {
+	fmt.Fprintf(w, "Error: %v\n", err)
+
+	hint := errs.Hint(err)
+	for _, prefix := range usageErrors {
+		if hint == "" && cmd != nil && strings.HasPrefix(err.Error(), prefix) {
+			hint = cmd.CommandPath() + " --help"
+		}
+	}
+	if hint != "" {
+		fmt.Fprintf(w, "  fix: %s\n", hint)
+	}
+}
+
+// telemetrySendTimeout bounds sending the spool as a command exits; a
+// running daemon sends it instead
+const telemetrySendTimeout = 500 * time.Millisecond
+
+// recordUsage spools a telemetry event for a finished command, if
+// telemetry is on. When the spool is due and no daemon is running to send
+// it, it is sent here, giving up quickly. Failures are never the command's
+// problem.
+func recordUsage(cmd *cobra.Command, err error) {
+	if cfg == nil || cmd == nil {
+		return
+	}
+	t, openErr := telemetry.Open(cfg.TelemetryDir)
+	if openErr != nil || !t.Enabled() {
+		return
+	}
+
+	event := telemetry.Event{
+		Version: Version,
+		Command: strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Error:   errorCategory(err),
+	}
+	if flag := cmd.Flags().Lookup("agent"); flag != nil {
+		name := flag.Value.String()
+		if name == "" && cmd.Name() == "spawn" {
+			name = cfg.General.DefaultAgent
+		}
+		event.Agent = agentKind(name)
+	}
+	if err := t.Record(event); err != nil && log != nil {
+		log.Debug("Failed to record telemetry", logging.Err(err))
 	}
+
+	if cfg.Telemetry.Endpoint != "" && t.SendDue(cfg.Telemetry.SendInterval) && !daemon.Running(cfg.DaemonPIDFile) {
+		ctx, cancel := context.WithTimeout(context.Background(), telemetrySendTimeout)
+		defer cancel()
+		if _, err := t.Send(ctx, cfg.Telemetry.Endpoint); err != nil && log != nil {
+			log.Debug("Failed to send telemetry", logging.Err(err))
+		}
+	}
+}
+
+// errorCategory reduces an error to a category safe to report: its errs
+// code, "usage" for command-line mistakes, or "other"
+func errorCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+	if code := errs.Code(err); code != "" {
+		return code
+	}
+	for _, prefix := range usageErrors {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return "usage"
+		}
+	}
+	return "other"
 }
 
 func initializeApp(cmd *cobra.Command, args []string) error {
-	// Skip initialization for version command
-	if cmd.Name() == "version" {
+	// The default theme until the config names another
+	style.Configure("", noColor)
+
+	// Skip initialization for commands that need no local state
+	if cmd.Name() == "version" || cmd.Name() == "openapi" || cmd.Name() == "record-pane" {
 		return nil
 	}
 
-	// Initialize logger
-	log = logging.New(verbose)
+	// Remote mode routes supported commands through the REST API
+	if serverURL != "" {
+		remote = api.NewClient(serverURL)
+		remote.Token = os.Getenv("GFORGE_API_TOKEN")
+	}
 
 	// Load configuration
 	var err error
@@ -80,12 +244,29 @@ func initializeApp(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if err := style.Configure(cfg.UI.Theme, noColor || cfg.UI.NoColor); err != nil {
+		return fmt.Errorf("invalid ui.theme: %w", err)
+	}
+
+	// Initialize logger
+	log = logging.New(verbose > 0)
+
+	// Trace external commands
+	var echo io.Writer
+	if verbose > 1 {
+		echo = os.Stderr
+	}
+	trace.Configure(echo, trace.FileFromEnv(cfg.TraceFile))
+
 	// Initialize database
 	db, err = storage.New(cfg.DatabasePath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	// Any command keeps the stats history going; failures only cost a point
+	coordinator.New(db, cfg, log).SnapshotStats(cfg.Stats.SnapshotInterval)
+
 	return nil
 }
 
@@ -104,6 +285,62 @@ func newVersionCmd() *cobra.Command {
 	}
 }
 
+// === OpenAPI Command ===
+
+func newOpenAPICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "openapi",
+		Short: "Print the OpenAPI document for the REST API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := json.MarshalIndent(api.Spec(Version), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to generate spec: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+}
+
+// === Serve Command ===
+
+func newServeCmd() *cobra.Command {
+	var port int
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the REST API until interrupted",
+		Long: `Serve the REST API (see gforge openapi) for the goblins on this machine,
+so scripts and other machines can spawn, list, stop and kill goblins, send
+them tasks and read their logs. Other gforge installs drive it with
+--server http://host:port.
+
+Clients send one of api.tokens (or GFORGE_API_TOKEN) as a bearer token.
+Without a token configured only a loopback address is served; set
+api.listen to 0.0.0.0:7474 with a token to accept other machines.
+
+With webhooks.github_secret or webhooks.linear_secret set, it also spawns
+a goblin for each issue given the webhooks.label label (default gforge),
+set up as the preset in webhooks.presets for its repository or team says,
+and links it for gforge progress comments. Point a GitHub "Issues" webhook
+(content type application/json) at /webhooks/github and a Linear "Issues"
+webhook at /webhooks/linear. These paths need no API token; deliveries are
+checked against the secret, and Linear ones more than a minute old are
+refused.
+
+With slack.signing_secret set, a Slack app's /gforge slash command pointed
+at /slack/commands runs spawn, status and logs here, for Slack users that
+slack.users maps to one of api.tokens; anyone else is refused.`,
+		Example: `  gforge serve --port 7474
+  curl -H "Authorization: Bearer $GFORGE_API_TOKEN" localhost:7474/v1/goblins`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serveAPI(port)
+		},
+	}
+	cmd.Flags().IntVar(&port, "port", 0, "Port to listen on (default from api.listen, 7474)")
+	return cmd
+}
+
 // === Config Command ===
 
 func newConfigCmd() *cobra.Command {
@@ -136,6 +373,30 @@ func newConfigCmd() *cobra.Command {
 		},
 	})
 
+	var (
+		setOnly bool
+		output  string
+	)
+	envCmd := &cobra.Command{
+		Use:   "env",
+		Short: "List the environment variables that override config keys",
+		Long: `Every config key can be overridden by an environment variable named after
+it: GFORGE_ followed by the key in upper case with dots as underscores.
+Variables win over the config file. Lists take comma-separated values; maps
+such as pricing can only be set in the file. Values of secrets are masked.
+
+Examples:
+  GFORGE_TMUX_SOCKET_NAME=ci gforge list
+  GFORGE_GENERAL_DEFAULT_AGENT=codex gforge spawn coder
+  gforge config env --set`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listConfigEnv(setOnly, output)
+		},
+	}
+	envCmd.Flags().BoolVar(&setOnly, "set", false, "Only list variables set in this environment")
+	envCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+	cmd.AddCommand(envCmd)
+
 	return cmd
 }
 
@@ -166,15 +427,64 @@ func newAgentsCmd() *cobra.Command {
 	return cmd
 }
 
-// === Spawn Command ===
+// === Providers Command ===
 
-func newSpawnCmd() *cobra.Command {
+func newProvidersCmd() *cobra.Command {
 	var (
-		agent   string
-		project string
-		branch  string
+		timeout    time.Duration
+		jsonOutput bool
 	)
 
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "Check model provider reachability and credentials",
+		Long: `Probe each model provider used by the built-in agents (Anthropic, OpenAI,
+Google and the local ollama server) and report whether it is reachable,
+whether its API key is accepted, and how long it took to answer.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkProviders(timeout, jsonOutput)
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "Timeout per provider")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+
+	return cmd
+}
+
+// === Doctor Command ===
+
+func newDoctorCmd() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check tmux, git and tmux sockets",
+		Long: `Check that tmux and git are installed and that every tmux socket gforge
+uses (tmux.socket_name and the sockets of existing goblins) can be used:
+not owned by another user, not in a directory tmux considers unsafe, and
+not left behind by a server that died.
+
+--fix removes stale sockets and restricts socket directories open to
+other users. Sockets owned by another user are never touched; choose
+another tmux.socket_name or tmux.socket_template instead.`,
+		Example: `  gforge doctor
+  gforge doctor --fix`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(fix)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Clean up stale sockets and unsafe socket directories")
+
+	return cmd
+}
+
+// === Spawn Command ===
+
+func newSpawnCmd() *cobra.Command {
+	var f spawnFlags
+
 	cmd := &cobra.Command{
 		Use:   "spawn <name>",
 		Short: "Spawn a new goblin (agent instance)",
@@ -183,17 +493,50 @@ func newSpawnCmd() *cobra.Command {
 Examples:
   gforge spawn coder --agent claude
   gforge spawn reviewer --agent gemini --project ./myapp
-  gforge spawn tester --agent codex --branch feat/tests`,
+  gforge spawn tester --agent codex --branch feat/tests
+  gforge spawn builder --agent claude --dev-env auto
+  gforge spawn demo --agent claude --record
+  gforge spawn fixer --task "Fix the failing storage tests"
+  gforge spawn hotfix --priority high
+  gforge spawn api --squad auth --task "Write the login API"
+
+When general.max_concurrent_agents goblins (or ollama.max_local_goblins
+local-model goblins) are running, the spawn waits in a queue. Queued spawns
+start highest priority first; with scheduler.preempt a high-priority spawn
+pauses the newest lower-priority goblin, which resumes once a slot frees.
+
+Branches matching git.protected_branches (main, master, release/* by
+default) are refused unless --allow-protected is given.
+
+The session runs on --tmux-socket, else the project's tmux_socket, else
+tmux.socket_template (e.g. "gforge-{project}"), else tmux.socket_name.
+
+When the project's .gforge.yaml lists signoff_owners, paths given with
+--paths or named in the task that CODEOWNERS gives to those owners are
+warned about before the goblin starts.
+
+Goblins in one --squad share a scratchpad (see gforge pad); without one,
+a goblin shares its project's.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
-			return spawnGoblin(name, agent, project, branch)
+			return spawnGoblin(name, f)
 		},
 	}
 
-	cmd.Flags().StringVarP(&agent, "agent", "a", "claude", "Agent to use (claude, codex, gemini, ollama)")
-	cmd.Flags().StringVarP(&project, "project", "p", ".", "Project directory")
-	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Git branch name (auto-generated if empty)")
+	cmd.Flags().StringVarP(&f.agent, "agent", "a", "", "Agent to use: claude, codex, gemini, ollama (default from .gforge.yaml, then general.default_agent)")
+	cmd.Flags().StringVarP(&f.project, "project", "p", ".", "Project directory")
+	cmd.Flags().StringVarP(&f.branch, "branch", "b", "", "Git branch name (auto-generated if empty)")
+	cmd.Flags().StringVar(&f.devEnv, "dev-env", "", "Run inside the project environment: off, auto, devcontainer, nix (default from config)")
+	cmd.Flags().BoolVar(&f.record, "record", false, "Record the session to an asciinema cast (see gforge play)")
+	cmd.Flags().StringVarP(&f.task, "task", "t", "", "First task to send once the agent has started")
+	cmd.Flags().StringVar(&f.issue, "issue", "", "Issue to post progress comments on: gh:owner/repo#123, linear:PROJ-456 or jira:PROJ-789")
+	cmd.Flags().StringSliceVar(&f.paths, "paths", nil, "Comma-separated paths the goblin is expected to change, checked against CODEOWNERS and locks")
+	cmd.Flags().BoolVar(&f.noContext, "no-context", false, "Do not send the project's onboarding context (context files and repository map)")
+	cmd.Flags().StringVar(&f.priority, "priority", "normal", "Queue priority when the goblin limit is reached: high, normal, low")
+	cmd.Flags().BoolVar(&f.allowProtected, "allow-protected", false, "Allow a branch matching git.protected_branches")
+	cmd.Flags().StringVar(&f.socket, "tmux-socket", "", "tmux socket for the session (default from .gforge.yaml, then tmux.socket_template)")
+	cmd.Flags().StringVar(&f.squad, "squad", "", "Squad whose scratchpad the goblin shares (default: the project's)")
 
 	return cmd
 }
@@ -201,153 +544,2260 @@ Examples:
 // === List Command ===
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		output   string
+		stat     bool
+		archived bool
+	)
+
+	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List all goblins",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return listGoblins()
+			return listGoblins(output, stat, archived)
 		},
 	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+	cmd.Flags().BoolVar(&stat, "stat", false, "Show each worktree's uncommitted changes")
+	cmd.Flags().BoolVar(&archived, "archived", false, "List archived goblins instead")
+
+	return cmd
 }
 
-// === Stop Command ===
+// === Events Command ===
 
-func newStopCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "stop <name>",
-		Short: "Stop a running goblin",
-		Args:  cobra.ExactArgs(1),
+func newEventsCmd() *cobra.Command {
+	var (
+		since  time.Duration
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events [name]",
+		Short: "Show the activity log",
+		Long: `List recorded activity (spawns, tasks, completions, failures, stops)
+across all goblins, or for one goblin.`,
+		Example: `  gforge events
+  gforge events coder --since 168h
+  gforge events -o csv > events.csv`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return stopGoblin(args[0])
+			return listEvents(optionalArg(args), since, output)
 		},
 	}
+
+	cmd.Flags().DurationVar(&since, "since", 24*time.Hour, "Window to show")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+
+	return cmd
 }
 
-// === Kill Command ===
+// === History Command ===
 
-func newKillCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "kill <name>",
-		Short: "Kill a goblin and cleanup its resources",
-		Long:  `Forcefully terminate a goblin, remove its tmux session and optionally its worktree.`,
-		Args:  cobra.ExactArgs(1),
+func newHistoryCmd() *cobra.Command {
+	var (
+		since  string
+		agent  string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history [name]",
+		Short: "Browse past goblin runs",
+		Long: `List goblins that have been killed, most recent first. Killing a goblin
+deletes it, but leaves a tombstone with its name, agent, how long it ran,
+how it ended (killed while working, stopped, failed or archived) and the
+final diffstat of its branch. gforge stats charts the same runs.`,
+		Example: `  gforge history
+  gforge history --since 30d --agent codex
+  gforge history -o csv > runs.csv`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return killGoblin(args[0])
+			window, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+			return listHistory(optionalArg(args), agent, window, output)
 		},
 	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", "Window to show (e.g. 12h, 7d, 2w)")
+	cmd.Flags().StringVar(&agent, "agent", "", "Only show goblins of this agent")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+
+	return cmd
 }
 
-// === Attach Command ===
+// === Tasks Command ===
 
-func newAttachCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "attach <name>",
-		Aliases: []string{"a"},
-		Short:   "Attach to a goblin's tmux session",
-		Long: `Attach to a running goblin's tmux session.
-Use Ctrl+B D to detach and return to gforge.`,
-		Args: cobra.ExactArgs(1),
+func newTasksCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "tasks [name]",
+		Short: "List the tasks sent to a goblin, then those queued for it",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listTasks(optionalArg(args), output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show [name] <#>",
+		Short: "Show a task and the changes it produced",
+		Long: `Show a task, numbered as gforge tasks lists them, with the diff it
+produced: from the commit the goblin's worktree was at when the task was
+sent to the one it was at when the task ended. A task ends when the next
+one is sent, when gforge notify watch sees the agent go quiet, or when the
+goblin stops. While a task is open its diff runs to the worktree as it is
+now, uncommitted changes to tracked files included.
+
+Examples:
+  gforge tasks show coder 3
+  gforge tasks show 2   # the current goblin's second task`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return showTask("", args[0])
+			}
+			return showTask(args[0], args[1])
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "drop [name]",
+		Short: "Drop the tasks queued for a goblin",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return attachGoblin(args[0])
+			return dropQueuedTasks(optionalArg(args))
+		},
+	})
+
+	return cmd
+}
+
+// === Note Command ===
+
+func newNoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "note <name> <observation>",
+		Short: "Add a note to a goblin",
+		Long: `Write down an observation about a goblin while supervising it. Notes
+are listed by gforge notes, and go with the goblin into gforge logs
+--output captures and gforge state export.`,
+		Example: `  gforge note coder "rewrote the retry logic instead of fixing the test"`,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return addNote(args[0], args[1:])
 		},
 	}
+
+	return cmd
 }
 
-// === Logs Command ===
+// === Notes Command ===
 
-func newLogsCmd() *cobra.Command {
+func newNotesCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "notes [name]",
+		Short: "List the notes on a goblin",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listNotes(optionalArg(args), output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+
+	return cmd
+}
+
+// === Artifacts Command ===
+
+func newArtifactsCmd() *cobra.Command {
 	var (
-		lines int
-		follow bool
+		collect bool
+		output  string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "logs <name>",
-		Short: "View goblin output logs",
-		Args:  cobra.ExactArgs(1),
+		Use:   "artifacts [name]",
+		Short: "List the files collected from a goblin's worktree",
+		Long: `List the artifacts collected from a goblin's worktree into the data
+directory: coverage reports, built binaries, screenshots. gforge done
+collects what the artifacts patterns in .gforge.yaml match once the tests
+pass, lists them in the pull request and counts them in the done
+notification; workflow steps add their own patterns. Use --collect to
+collect them now.
+
+  artifacts:
+    - coverage.out
+    - dist/*
+    - screenshots`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return showLogs(args[0], lines, follow)
+			return listArtifacts(optionalArg(args), collect, output)
 		},
 	}
 
-	cmd.Flags().IntVarP(&lines, "lines", "n", 50, "Number of lines to show")
-	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output")
+	cmd.Flags().BoolVar(&collect, "collect", false, "Collect the project's artifacts from the worktree first")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
 
 	return cmd
 }
 
-// === Diff Command ===
+// === Pin Command ===
 
-func newDiffCmd() *cobra.Command {
-	var staged bool
+func newPinCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin [name]",
+		Short: "Pin a goblin to the top of listings",
+		Long: `Pin an important goblin. Pinned goblins are listed first (marked *) by
+gforge list and the dashboard, and the scheduler never preempts them to
+admit a higher-priority spawn. The pin is stored and survives restarts.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pinGoblin(optionalArg(args), true)
+		},
+	}
+
+	return cmd
+}
+
+// === Unpin Command ===
 
+func newUnpinCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "diff <name>",
-		Short: "Show changes made by a goblin",
-		Args:  cobra.ExactArgs(1),
+		Use:   "unpin [name]",
+		Short: "Unpin a goblin",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return showDiff(args[0], staged)
+			return pinGoblin(optionalArg(args), false)
 		},
 	}
 
-	cmd.Flags().BoolVarP(&staged, "staged", "s", false, "Show staged changes only")
+	return cmd
+}
+
+// === Lock Command ===
+
+func newLockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock <name> <path>...",
+		Short: "Claim paths for a goblin so others keep off them",
+		Long: `Lock files or directories in a goblin's project while it works on them.
+A task sent to another goblin that names a locked path (or one inside a
+locked directory) is warned about or, with locks.on_conflict set to
+queue, held until the lock is released. Paths another goblin already
+holds can't be locked.
+
+Directories may be written src/auth/, src/auth/... or src/auth/**. Locks
+are advisory: nothing stops an agent from editing the files. They are
+released by gforge unlock, and when the goblin completes, is archived or
+is killed.`,
+		Example: `  gforge lock coder src/auth/...
+  gforge lock coder go.mod go.sum`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return lockPaths(args[0], args[1:])
+		},
+	}
 
 	return cmd
 }
 
-// === Task Command ===
+// === Unlock Command ===
 
-func newTaskCmd() *cobra.Command {
-	var goblin string
+func newUnlockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock <name> [path...]",
+		Short: "Release a goblin's locks, all of them when no path is given",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return unlockPaths(args[0], args[1:])
+		},
+	}
+
+	return cmd
+}
+
+// === Locks Command ===
+
+func newLocksCmd() *cobra.Command {
+	var (
+		project string
+		output  string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "task <description>",
-		Short: "Send a task to a goblin",
-		Long: `Send a task description to a running goblin.
-The task will be typed into the goblin's terminal session.`,
-		Args: cobra.ExactArgs(1),
+		Use:   "locks",
+		Short: "List the paths goblins have locked",
+		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return sendTask(args[0], goblin)
+			return listLocks(project, output)
 		},
 	}
 
-	cmd.Flags().StringVarP(&goblin, "goblin", "g", "", "Target goblin name (required)")
-	cmd.MarkFlagRequired("goblin")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Only locks in this project")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
 
 	return cmd
 }
 
-// === Status Command ===
+// === Pad Command ===
 
-func newStatusCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "status",
-		Short: "Show system status",
+func newPadCmd() *cobra.Command {
+	var (
+		goblin string
+		squad  string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pad",
+		Short: "Read and write the scratchpad a squad of goblins shares",
+		Long: `Keep notes cooperating goblins need from each other, such as API contracts
+or who does which part of a task, in a scratchpad shared by their squad.
+Goblins spawned with the same --squad share one; a goblin without a squad
+shares its project's.
+
+Agents run gforge pad in their worktree: the goblin is found from
+GFORGE_GOBLIN, set in every goblin's session, or the current directory.
+Elsewhere, name a goblin with --goblin or a squad with --squad. Writes
+by goblins are recorded in gforge events.`,
+		Example: `  gforge pad set api-contract "POST /v1/login {user, password} -> {token}"
+  gforge pad set split - < plan.md
+  gforge pad get api-contract
+  gforge pad list --squad auth
+  gforge pad rm split`,
+	}
+	cmd.PersistentFlags().StringVarP(&goblin, "goblin", "g", "", "Use this goblin's squad (default: the current worktree's)")
+	cmd.PersistentFlags().StringVar(&squad, "squad", "", "Use this squad's scratchpad")
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the keys in the scratchpad",
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return showStatus()
+			return listPad(goblin, squad, output)
+		},
+	}
+	listCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+	cmd.AddCommand(listCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getPad(goblin, squad, args[0])
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a key, reading the value from stdin when it is -",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setPad(goblin, squad, args[0], args[1])
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "rm <key>...",
+		Aliases: []string{"delete"},
+		Short:   "Delete keys",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deletePad(goblin, squad, args)
+		},
+	})
+
+	return cmd
+}
+
+// === Archive Command ===
+
+func newArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive [name]",
+		Short: "Shelve a goblin without losing its work",
+		Long: `Archive a goblin you may come back to. Its tmux session and worktree are
+removed, freeing the slot and the disk, but its record, tasks and notes
+stay, and its scrollback and commits are kept under the data directory
+(uncommitted changes are committed first). Archived goblins are hidden
+from gforge list; see them with gforge list --archived.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return archiveGoblin(optionalArg(args))
 		},
 	}
+
+	return cmd
+}
+
+// === Unarchive Command ===
+
+func newUnarchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unarchive <name>",
+		Short: "Restore an archived goblin into a fresh worktree",
+		Long: `Check an archived goblin's branch out into a new worktree, restoring the
+branch from the archive if it was deleted. The goblin comes back stopped.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return unarchiveGoblin(args[0])
+		},
+	}
+
+	return cmd
+}
+
+// === Stop Command ===
+
+// bulkFlags select sets of goblins for stop and kill
+type bulkFlags struct {
+	all       bool
+	status    string
+	olderThan time.Duration
+	selector  string
+	dryRun    bool
+}
+
+func (f *bulkFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.all, "all", false, "Select every goblin")
+	cmd.Flags().StringVar(&f.status, "status", "", "Select goblins with this status")
+	cmd.Flags().DurationVar(&f.olderThan, "older-than", 0, "Select goblins older than this (e.g. 24h)")
+	cmd.Flags().StringVarP(&f.selector, "selector", "l", "", "Select by field: agent, branch, name, project, status (e.g. agent=claude,name=test-*)")
+	cmd.Flags().BoolVar(&f.dryRun, "dry-run", false, "Show what would be affected without doing it")
+}
+
+// resolve builds the selector; ok is false when no bulk flag was given
+func (f *bulkFlags) resolve() (sel coordinator.Selector, ok bool, err error) {
+	fields, err := coordinator.ParseSelector(f.selector)
+	if err != nil {
+		return sel, false, err
+	}
+	sel = coordinator.Selector{
+		All:       f.all,
+		Status:    f.status,
+		OlderThan: f.olderThan,
+		Fields:    fields,
+	}
+	return sel, !sel.Empty(), nil
+}
+
+// runSingleOrBulk dispatches to the single-goblin or bulk form of a command
+func runSingleOrBulk(args []string, flags *bulkFlags, verb string, single func(string) error) error {
+	sel, bulk, err := flags.resolve()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case bulk && len(args) > 0:
+		return fmt.Errorf("give either a goblin name or selection flags, not both")
+	case bulk:
+		return bulkAction(verb, sel, flags.dryRun, single)
+	case len(args) == 0:
+		return fmt.Errorf("requires a goblin name or one of --all, --status, --older-than, --selector")
+	default:
+		return single(args[0])
+	}
+}
+
+func newStopCmd() *cobra.Command {
+	var flags bulkFlags
+
+	cmd := &cobra.Command{
+		Use:   "stop [name]",
+		Short: "Stop a running goblin, or a selection of goblins",
+		Long: `Stop a goblin by name, or every goblin matching selection flags.
+
+Examples:
+  gforge stop coder
+  gforge stop --all
+  gforge stop --status running --older-than 24h
+  gforge stop --selector agent=codex,name=test-* --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSingleOrBulk(args, &flags, "stop", stopGoblin)
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
+
+// === Kill Command ===
+
+func newKillCmd() *cobra.Command {
+	var flags bulkFlags
+
+	cmd := &cobra.Command{
+		Use:   "kill [name]",
+		Short: "Kill a goblin and cleanup its resources",
+		Long: `Forcefully terminate a goblin, remove its tmux session and optionally its worktree.
+Selection flags kill every matching goblin.
+
+Examples:
+  gforge kill coder
+  gforge kill --status stopped
+  gforge kill --older-than 72h --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSingleOrBulk(args, &flags, "kill", killGoblin)
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
+
+// === Pause/Resume Commands ===
+
+func newPauseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause [name]",
+		Short: "Suspend a goblin's agent, freeing its slot",
+		Long: `Stop a goblin's agent process (like Ctrl+Z) without ending its session.
+A paused goblin does not count toward the goblin limits, so queued spawns
+can start. Continue it with gforge resume.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pauseGoblin(optionalArg(args), true)
+		},
+	}
+}
+
+func newResumeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume [name]",
+		Short: "Continue a paused or preempted goblin",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pauseGoblin(optionalArg(args), false)
+		},
+	}
+}
+
+// === Shutdown Command ===
+
+func newShutdownCmd() *cobra.Command {
+	var policy string
+
+	cmd := &cobra.Command{
+		Use:   "shutdown",
+		Short: "Stop all goblins cleanly, saving their work",
+		Long: `Stop every active goblin before the machine or daemon goes down.
+Uncommitted work in each worktree is first committed to the goblin's branch,
+stashed, or left alone according to git.shutdown_policy (or --policy).
+
+Run automatically on shutdown by installing the systemd unit:
+  gforge daemon install`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return shutdownGoblins(policy)
+		},
+	}
+
+	cmd.Flags().StringVar(&policy, "policy", "", "Uncommitted work policy: commit, stash, none (default from config)")
+
+	return cmd
+}
+
+// === Scaffold Command ===
+
+func newScaffoldCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "scaffold [dir]",
+		Short: "Add a shared gforge setup to a repository",
+		Long: `Drop a starter gforge setup into a repository so a team shares one
+configuration:
+
+  .gforge.yaml                  default agent, branch prefix, dev env, hooks
+  .gforge/tasks/*.md            task templates (gforge task --template <name>)
+  .gforge/hooks/post-spawn.sh   example worktree setup hook
+  .gitignore                    entries for project-local worktrees and recordings
+
+Existing files are left alone unless --force is given.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := optionalArg(args)
+			if dir == "" {
+				dir = "."
+			}
+			return scaffoldProject(dir, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+
+	return cmd
+}
+
+// === Context Command ===
+
+func newContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Build and send context packs to goblins",
+		Long: `Assemble a trimmed context bundle (file tree, key files, recent commits)
+from parts of a repository, store it, and send it to one or more goblins.
+
+Examples:
+  gforge context build --paths src/api,docs/adr --max-tokens 8000
+  gforge context build --paths src/api --name api --send coder
+  gforge context send api -g reviewer`,
+	}
+
+	var (
+		paths     []string
+		project   string
+		name      string
+		maxTokens int
+		commits   int
+		sendTo    string
+		printPack bool
+	)
+	buildCmd := &cobra.Command{
+		Use:   "build",
+		Short: "Assemble and store a context pack",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return buildContext(project, name, paths, maxTokens, commits, sendTo, printPack)
+		},
+	}
+	buildCmd.Flags().StringSliceVar(&paths, "paths", nil, "Comma-separated paths to include (default: whole project)")
+	buildCmd.Flags().StringVarP(&project, "project", "p", ".", "Project directory")
+	buildCmd.Flags().StringVar(&name, "name", "", "Name to store the pack under (default from paths)")
+	buildCmd.Flags().IntVar(&maxTokens, "max-tokens", 8000, "Token budget for the pack")
+	buildCmd.Flags().IntVar(&commits, "commits", 10, "Recent commits to list")
+	buildCmd.Flags().StringVar(&sendTo, "send", "", "Send the pack to this goblin once built")
+	buildCmd.Flags().BoolVar(&printPack, "print", false, "Print the pack to stdout")
+	cmd.AddCommand(buildCmd)
+
+	var goblin string
+	sendCmd := &cobra.Command{
+		Use:   "send <pack>",
+		Short: "Send a stored context pack to a goblin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendContext(args[0], goblin)
+		},
+	}
+	sendCmd.Flags().StringVarP(&goblin, "goblin", "g", "", "Target goblin (default: the current goblin)")
+	cmd.AddCommand(sendCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List stored context packs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listContexts()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show <pack>",
+		Short: "Print a stored context pack",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showContext(args[0])
+		},
+	})
+
+	return cmd
+}
+
+// === Map Command ===
+
+func newMapCmd() *cobra.Command {
+	var (
+		paths     []string
+		maxTokens int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "map [project]",
+		Short: "Print a compact repository map for agents",
+		Long: `Print the repository's packages, files, sizes and public symbols.
+Go packages are loaded with go/packages (import paths, build constraints);
+Python, JavaScript/TypeScript, Rust and Ruby declarations are read by
+lexing each file. Other languages are listed by file and size only.
+
+New goblins get the map unless .gforge.yaml sets repo_map: false or they
+are spawned with --no-context.
+
+Examples:
+  gforge map
+  gforge map --paths internal,cmd --max-tokens 2000`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			project := optionalArg(args)
+			if project == "" {
+				project = "."
+			}
+			return printRepoMap(project, paths, maxTokens)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&paths, "paths", nil, "Comma-separated paths to map (default: whole project)")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Stop before this many tokens (0 for no limit)")
+
+	return cmd
+}
+
+// === State Command ===
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Export and import goblin definitions as YAML",
+		Long: `Serialize goblins, their aliases, task history and notes to YAML for backup,
+review, or moving definitions between machines. Worktree paths, tmux
+sessions and output logs are not included.
+
+Examples:
+  gforge state export > state.yaml
+  gforge state import state.yaml`,
+	}
+
+	var output string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write goblin definitions as YAML",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportState(output)
+		},
+	}
+	exportCmd.Flags().StringVarP(&output, "output", "o", "", "Write to a file instead of stdout")
+	cmd.AddCommand(exportCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "import <file>",
+		Short: "Recreate goblin definitions from YAML ('-' reads stdin)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return importState(args[0])
+		},
+	})
+
+	return cmd
+}
+
+// === Telemetry Command ===
+
+func newTelemetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Turn anonymous usage telemetry on or off",
+		Long: `Telemetry is off unless turned on here. When on, each command records its
+name, the kind of agent it used (built-in name or "custom") and the category
+of any error - never names, paths, prompts, arguments or messages - with a
+random install ID, the day, gforge version and platform.
+
+Events wait in a local spool and are sent every telemetry.send_interval to
+telemetry.endpoint, by gforge daemon when it is running; with no endpoint
+set nothing leaves the machine.
+DO_NOT_TRACK=1 or GFORGE_TELEMETRY=0 keeps telemetry off regardless.
+
+Examples:
+  gforge telemetry status
+  gforge telemetry on
+  gforge telemetry off   # also deletes unsent events`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "on",
+		Short: "Start recording usage events",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setTelemetry(true)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "off",
+		Short: "Stop recording and delete unsent events",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setTelemetry(false)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show whether telemetry is on and what is waiting to be sent",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telemetryStatus()
+		},
+	})
+
+	return cmd
+}
+
+// === Daemon Command ===
+
+func newDaemonCmd() *cobra.Command {
+	var once bool
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Supervise goblins: mark exited agents dead and archive idle goblins",
+		Long: `Run a supervisor that checks every goblin's tmux session each
+daemon.interval (default 15s). A goblin whose agent exited without gforge
+stopping it is marked dead, its open task ends and its slot goes to the
+spawn queue.
+
+Goblins with tasks queued by gforge task get the next one once their pane
+has shown no new output for notifications.idle_timeout, unless the agent
+is waiting at an approval prompt.
+
+Schedules (see gforge schedule) spawn a goblin each time they come round.
+
+Each daemon.cleanup_interval (default 1h) goblins without a session for
+general.auto_cleanup_days are archived, keeping their work (see gforge
+archive); pinned goblins stay. Set auto_cleanup_days to 0 to turn this off.
+
+Only one daemon runs at a time. Use gforge daemon install --supervise to
+run it as a systemd user service.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemon(once)
+		},
+	}
+
+	cmd.Flags().BoolVar(&once, "once", false, "Make one pass (cleanup included) and exit")
+
+	var printOnly, supervise bool
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install a systemd user unit that runs gforge shutdown on shutdown",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return installDaemon(printOnly, supervise)
+		},
+	}
+	installCmd.Flags().BoolVar(&printOnly, "print", false, "Print the unit instead of installing it")
+	installCmd.Flags().BoolVar(&supervise, "supervise", false, "Have the unit run gforge daemon too")
+	cmd.AddCommand(installCmd)
+
+	return cmd
+}
+
+// === Schedule Command ===
+
+func newScheduleCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Spawn goblins on a recurring schedule",
+		Long: `Define recurring goblin runs, such as a dependency update every Monday.
+gforge daemon spawns a goblin named <schedule>-<MMDD-HHMM> with the
+schedule's task each time its cron expression comes round; a run is
+skipped while the goblin from the last one is still at work.
+
+Cron expressions have five fields, minute hour day month weekday, e.g.
+"0 9 * * mon", or are one of @hourly, @daily, @weekly, @monthly and
+@yearly. Times are local. Schedules can also be set under schedules in the
+config file; those are changed there.`,
+		Example: `  gforge schedule add deps-update --cron "0 9 * * mon" -p ~/src/app \
+    -t "Update outdated dependencies, run the tests and open a PR"
+  gforge schedule list
+  gforge schedule rm deps-update`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List schedules and when they next run",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listSchedules(output)
+		},
+	}
+	listCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+	cmd.AddCommand(listCmd)
+
+	var cronExpr, project, agent, task, priority, squad string
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return addSchedule(args[0], cronExpr, project, agent, task, priority, squad)
+		},
+	}
+	addCmd.Flags().StringVar(&cronExpr, "cron", "", "When to run, e.g. \"0 9 * * mon\" or @daily (required)")
+	addCmd.Flags().StringVarP(&project, "project", "p", ".", "Project path")
+	addCmd.Flags().StringVarP(&agent, "agent", "a", "", "Agent to use (default: the project's or general.default_agent)")
+	addCmd.Flags().StringVarP(&task, "task", "t", "", "Task to send each goblin (required)")
+	addCmd.Flags().StringVar(&priority, "priority", "normal", "Queue priority when the goblin limit is reached: high, normal, low")
+	addCmd.Flags().StringVar(&squad, "squad", "", "Squad the goblins join")
+	addCmd.MarkFlagRequired("cron")
+	addCmd.MarkFlagRequired("task")
+	cmd.AddCommand(addCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "rm <name>...",
+		Aliases: []string{"delete"},
+		Short:   "Remove schedules",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return removeSchedules(args)
+		},
+	})
+
+	return cmd
+}
+
+// === Attach Command ===
+
+func newAttachCmd() *cobra.Command {
+	var mode string
+
+	cmd := &cobra.Command{
+		Use:     "attach [name]",
+		Aliases: []string{"a"},
+		Short:   "Attach to a goblin's tmux session",
+		Long: `Attach to a running goblin's tmux session (default: the current
+goblin set with gforge use).
+Use Ctrl+B D to detach and return to gforge.
+
+Inside tmux, sessions aren't nested: --mode (default tmux.attach_mode)
+auto switches the client when the goblin runs on the same tmux server and
+opens a new window otherwise; switch, window, popup and nest force one.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return attachGoblin(optionalArg(args), mode)
+		},
+	}
+
+	cmd.Flags().StringVar(&mode, "mode", "", "Inside tmux: auto, switch, window, popup or nest (default from tmux.attach_mode)")
+
+	return cmd
+}
+
+// === Logs Command ===
+
+func newLogsCmd() *cobra.Command {
+	var (
+		lines  int
+		follow bool
+		pane   bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs [name]",
+		Short: "View goblin output logs",
+		Long: `View a goblin's recent output.
+
+With --pane the scrollback is read straight from the goblin's tmux pane,
+up to tmux.history_limit lines (all of it unless --lines is given), with
+wrapped lines joined. This works whether or not the session is recorded.
+--output writes the capture to a file instead, for sharing.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if follow && (pane || output != "") {
+				return fmt.Errorf("--pane and --output can't be used with --follow")
+			}
+			if pane {
+				if !cmd.Flags().Changed("lines") {
+					lines = 0
+				}
+				return capturePaneHistory(optionalArg(args), lines, output)
+			}
+			return showLogs(optionalArg(args), lines, follow, output)
+		},
+	}
+
+	cmd.Flags().IntVarP(&lines, "lines", "n", 50, "Number of lines to show")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output")
+	cmd.Flags().BoolVar(&pane, "pane", false, "Capture the pane's full scrollback (up to tmux.history_limit)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to a file instead of stdout")
+
+	return cmd
+}
+
+// === Summary Command ===
+
+func newSummaryCmd() *cobra.Command {
+	var cached bool
+
+	cmd := &cobra.Command{
+		Use:   "summary [name]",
+		Short: "Summarize a goblin's recent output",
+		Long: `Condense a goblin's recent output into a short summary using the model
+configured under summarizer (a local ollama model by default).
+
+Summaries roll forward: each run updates the previous one with the latest
+output, and they are included in activity digests.`,
+		Example: `  gforge summary coder
+  gforge summary --cached`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showSummary(optionalArg(args), cached)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cached, "cached", false, "Show the stored summary without calling the model")
+
+	return cmd
+}
+
+// === Cost Command ===
+
+func newCostCmd() *cobra.Command {
+	var (
+		since  time.Duration
+		by     string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Report estimated token usage and cost",
+		Long: `Total the estimated token usage and cost of goblins over a window,
+grouped by day, agent, project or goblin.
+
+Input tokens are counted from the tasks and text sent to goblins, output
+tokens from their pane history when they are stopped or killed. Prices
+come from pricing in config, or the providers' list prices.`,
+		Example: `  gforge cost
+  gforge cost --by agent --since 720h
+  gforge cost --by project -o csv > costs.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return costReport(since, by, output)
+		},
+	}
+
+	cmd.Flags().DurationVar(&since, "since", 7*24*time.Hour, "Window to report")
+	cmd.Flags().StringVar(&by, "by", "day", "Group by: day, agent, project, goblin")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, json, csv, markdown")
+
+	return cmd
+}
+
+// === Ask Command ===
+
+func newAskCmd() *cobra.Command {
+	var (
+		agent string
+		stdin bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ask <question>",
+		Short: "Ask an agent a one-off question",
+		Long: `Run an agent once and stream its answer, without a goblin: no worktree,
+no tmux session and nothing recorded. With --stdin, piped input is passed to
+the agent along with the question.`,
+		Example: `  gforge ask "what does internal/tmux do?"
+  git diff | gforge ask --agent ollama --stdin "explain this diff"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return askAgent(agent, args[0], stdin)
+		},
+	}
+
+	cmd.Flags().StringVarP(&agent, "agent", "a", "", "Agent to ask (default: project or config default)")
+	cmd.Flags().BoolVar(&stdin, "stdin", false, "Pass standard input to the agent")
+
+	return cmd
+}
+
+// === Diff Command ===
+
+func newDiffCmd() *cobra.Command {
+	var (
+		staged      bool
+		from, to    string
+		checkpoints bool
+		noIgnore    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff [name]",
+		Short: "Show changes made by a goblin",
+		Long: `Show a goblin's uncommitted changes, or with --from and --to what it
+changed between two points. A point is one of its checkpoints (the commits
+on its branch, listed by --checkpoints), "base" for where the branch
+started, or a time: the branch as it was then. Times are a window back
+from now (90m, 12h, 2d), a date (2026-01-02), a date and time
+(2026-01-02 15:04) or a time today (15:04).
+
+--from defaults to base; --to defaults to the worktree as it is now,
+uncommitted changes to tracked files included.
+
+Files matching the project's ignore patterns (ignore in .gforge.yaml,
+e.g. lockfiles and generated code) are left out unless --no-ignore.
+
+Examples:
+  gforge diff coder --checkpoints
+  gforge diff coder --from 2h
+  gforge diff coder --from 09:00 --to 12:00
+  gforge diff coder --from 3f2a91c0 --to 8b1d44e7`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checkpoints {
+				return listCheckpoints(optionalArg(args))
+			}
+			if from != "" || to != "" {
+				if staged {
+					return fmt.Errorf("--staged cannot be combined with --from or --to")
+				}
+				return showDiffRange(optionalArg(args), from, to, noIgnore)
+			}
+			return showDiff(optionalArg(args), staged, noIgnore)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&staged, "staged", "s", false, "Show staged changes only")
+	cmd.Flags().StringVar(&from, "from", "", "Start point: a checkpoint, base or a time (default: base)")
+	cmd.Flags().StringVar(&to, "to", "", "End point: a checkpoint, base or a time (default: the worktree now)")
+	cmd.Flags().BoolVar(&checkpoints, "checkpoints", false, "List the goblin's checkpoints")
+	cmd.Flags().BoolVar(&noIgnore, "no-ignore", false, "Include files matching the project's ignore patterns")
+
+	return cmd
+}
+
+// === Push Command ===
+
+func newPushCmd() *cobra.Command {
+	var opts pushOptions
+
+	cmd := &cobra.Command{
+		Use:   "push [name]",
+		Short: "Push a goblin's branch and optionally open a pull request",
+		Long: `Push a goblin's branch to git.push_remote (origin by default), set it as
+the upstream and print its URL. With --pr a GitHub pull request is opened
+(needs the gh CLI) against git.pr_remote; when the two differ, as when
+pushing to a fork, the PR is opened across repositories. Its body fills
+the repository's .github/PULL_REQUEST_TEMPLATE.md from the goblin's tasks,
+commits and check results. With git.request_reviews on, reviews are
+requested from the CODEOWNERS of the files the branch changes.
+
+Rewritten history is only pushed with --force-with-lease, which refuses to
+overwrite commits on the remote that this worktree has not seen. Branches
+matching git.protected_branches are refused unless --allow-protected is given.
+While a release freeze or maintenance window in scheduler.freeze_calendar is
+on, pushes are refused unless --allow-freeze is given; see gforge freezes.
+
+With git.scan_secrets on, the goblin's commits are first scanned for
+credentials such as API keys, tokens and private keys. Findings are stored
+on the goblin (see gforge secrets) and stop the push unless --allow-secrets
+is given; lines marked gforge:allow-secret are not reported.
+
+Branches changing more than git.max_diff_files files or git.max_diff_lines
+lines are flagged, and with git.diff_limit set to block refused unless
+--allow-large is given. Each push's size is kept for gforge sizes.
+
+With git.confirm_dependencies on, changes to dependency manifests and newly
+added license files are listed first (see gforge deps) and the push asks
+for confirmation; pass --confirm-deps when running without a terminal.
+
+The project's checks (checks.commands in .gforge.yaml, e.g. go vet or a
+linter) then run in the worktree and must pass; see gforge check.
+
+Projects with changelog.format in .gforge.yaml (keepachangelog or
+towncrier) get an entry for the goblin's work, from its task and commits,
+committed to the branch before it is pushed.
+
+After the push, GitHub Actions workflows listed under dispatch in
+.gforge.yaml with on: push are triggered; see gforge actions.
+
+Examples:
+  gforge push fixer
+  gforge push fixer --pr --base develop
+  gforge push fixer --force-with-lease
+  gforge push fixer --remote fork --pr`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pushGoblin(optionalArg(args), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.remote, "remote", "", "Remote to push to (default: push_remote from .gforge.yaml, then git.push_remote)")
+	cmd.Flags().BoolVar(&opts.forceWithLease, "force-with-lease", false, "Overwrite the remote branch if it hasn't moved since it was last fetched")
+	cmd.Flags().BoolVar(&opts.pr, "pr", false, "Open a pull request for the branch")
+	cmd.Flags().StringVar(&opts.base, "base", "", "Base branch for the pull request (default: the repository's default branch)")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "Open the pull request as a draft")
+	cmd.Flags().BoolVar(&opts.allowProtected, "allow-protected", false, "Allow pushing a branch matching git.protected_branches")
+	cmd.Flags().BoolVar(&opts.allowFreeze, "allow-freeze", false, "Push even during a release freeze in scheduler.freeze_calendar")
+	cmd.Flags().BoolVar(&opts.allowSecrets, "allow-secrets", false, "Push even if the secret scan finds credentials")
+	cmd.Flags().BoolVar(&opts.allowLarge, "allow-large", false, "Push even if the branch is over the diff size limits")
+	cmd.Flags().BoolVar(&opts.confirmDeps, "confirm-deps", false, "Accept dependency and license changes without asking")
+	cmd.Flags().BoolVar(&opts.skipChecks, "skip-checks", false, "Push without running the project's checks")
+	cmd.Flags().BoolVar(&opts.noChangelog, "no-changelog", false, "Push without adding a changelog entry")
+
+	return cmd
+}
+
+// === Done Command ===
+
+func newDoneCmd() *cobra.Command {
+	var opts doneOptions
+
+	cmd := &cobra.Command{
+		Use:   "done [name]",
+		Short: "Wrap up a goblin: test, commit, push, open a PR, notify and mark it completed",
+		Long: `Run a goblin's finish pipeline, stopping at the first step that fails so it
+can be fixed and gforge done run again:
+
+  1. the test commands under done.tests in .gforge.yaml
+  2. commit anything left uncommitted in the worktree
+  3. gforge push, with its secret, size, dependency and check gates, and
+     a pull request with done.pr or --pr
+  4. notify the configured transports (the "done" route)
+  5. mark the goblin completed, ending its session; with done.archive or
+     --archive it is archived instead
+
+  done:
+    tests:
+      - go test ./...
+    pr: true
+    archive: false
+
+Examples:
+  gforge done fixer
+  gforge done fixer --pr --draft
+  gforge done fixer --no-push --archive`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return finishGoblin(optionalArg(args), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.pr, "pr", false, "Open a pull request (default: done.pr)")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "Open the pull request as a draft (default: done.draft)")
+	cmd.Flags().StringVar(&opts.base, "base", "", "Base branch for the pull request (default: done.base, then the default branch)")
+	cmd.Flags().BoolVar(&opts.archive, "archive", false, "Archive the goblin afterwards (default: done.archive)")
+	cmd.Flags().BoolVar(&opts.noPush, "no-push", false, "Finish without pushing")
+	cmd.Flags().BoolVar(&opts.skipTests, "skip-tests", false, "Finish without running done.tests")
+	cmd.Flags().BoolVar(&opts.confirmDeps, "confirm-deps", false, "Accept dependency and license changes without asking")
+
+	return cmd
+}
+
+// === Accept Command ===
+
+func newAcceptCmd() *cobra.Command {
+	var branch, message string
+
+	cmd := &cobra.Command{
+		Use:   "accept [name]",
+		Short: "Pick a goblin's changes hunk by hunk onto a clean branch",
+		Long: `Walk through the changes committed on a goblin's branch one hunk at a
+time, like git add -p, and commit the ones accepted to a new branch started
+at the goblin's base. Rejected hunks are left out; the goblin's own branch
+and worktree are not changed. Push or merge the new branch as usual.
+
+Uncommitted changes in the worktree are not offered; commit them first.
+Binary files, renames and mode changes are offered whole.
+
+  y  accept this hunk          n  reject this hunk
+  a  accept the rest of file   d  reject the rest of file
+  q  reject everything left    ?  help
+
+Examples:
+  gforge accept coder
+  gforge accept coder --branch review/coder-parser`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return acceptGoblin(optionalArg(args), branch, message)
+		},
+	}
+
+	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Branch to create (default: the goblin's branch with -accepted)")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Commit message (default: names the goblin and what was accepted)")
+
+	return cmd
+}
+
+// === Apply Command ===
+
+func newApplyCmd() *cobra.Command {
+	var opts coordinator.ApplyOptions
+
+	cmd := &cobra.Command{
+		Use:   "apply [name]",
+		Short: "Apply a goblin's changes to the project checkout without merging",
+		Long: `Apply everything a goblin changed since its base (commits, uncommitted
+edits and new files) to the project checkout it was spawned from, as plain
+working-tree changes: no branch is merged and nothing is committed. With
+--files only those paths are applied.
+
+Nothing changes unless every file applies cleanly. With --3way changes that
+don't are merged, leaving conflict markers to resolve.
+
+Examples:
+  gforge apply coder
+  gforge apply coder --files internal/parser/lexer.go,README.md
+  gforge apply coder --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return applyGoblin(optionalArg(args), opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.Files, "files", nil, "Only apply changes to these paths (comma-separated)")
+	cmd.Flags().BoolVar(&opts.ThreeWay, "3way", false, "Merge changes that don't apply cleanly, leaving conflict markers")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Check and show what would change without applying")
+
+	return cmd
+}
+
+// === Check Command ===
+
+func newCheckCmd() *cobra.Command {
+	var show bool
+
+	cmd := &cobra.Command{
+		Use:   "check [name]",
+		Short: "Run the project's lint and vet checks on a goblin",
+		Long: `Run the commands under checks in the project's .gforge.yaml in a goblin's
+worktree, as gforge push does before pushing. Results are stored on the
+goblin; with checks.fix_task the failures are sent to it as a task.
+
+  checks:
+    commands:
+      - go vet ./...
+      - golangci-lint run
+    timeout: 5m
+    fix_task: true`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChecks(optionalArg(args), show)
+		},
+	}
+
+	cmd.Flags().BoolVar(&show, "show", false, "Show the results of the last run instead of running the checks")
+
+	return cmd
+}
+
+// === Deps Command ===
+
+func newDepsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deps [name]",
+		Short: "Show dependency and license changes in a goblin's branch",
+		Long: `List what a goblin's commits change in dependency manifests (go.mod,
+package.json, requirements.txt and Cargo.toml are broken down by package;
+other manifests are reported as modified) and any added files that carry a
+license, so agent-introduced dependencies get reviewed.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showDependencies(optionalArg(args))
+		},
+	}
+}
+
+// === Sizes Command ===
+
+func newSizesCmd() *cobra.Command {
+	var (
+		since  string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sizes",
+		Short: "Report the size of pushed goblin branches",
+		Long: `List the files and lines changed by each goblin branch pushed over a
+window, marking those over git.max_diff_files or git.max_diff_lines.`,
+		Example: `  gforge sizes
+  gforge sizes --since 30d -o csv > sizes.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+			return sizesReport(window, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Window to report (e.g. 12h, 7d, 2w)")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+
+	return cmd
+}
+
+// === Freezes Command ===
+
+func newFreezesCmd() *cobra.Command {
+	var (
+		within string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "freezes",
+		Short: "List release freezes and maintenance windows",
+		Long: `List the windows in scheduler.freeze_calendar, an iCal URL or file, that
+are on now or start within a period. Events count when their summary or
+categories contain one of scheduler.freeze_keywords (freeze and maintenance
+by default). While one is on, gforge push refuses to push or open pull
+requests unless --allow-freeze is given.
+
+Repeating events with daily or weekly rules are followed; other rules
+count only their first occurrence.`,
+		Example: `  gforge freezes
+  gforge freezes --within 30d`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseSince(within)
+			if err != nil {
+				return err
+			}
+			return showFreezes(window, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&within, "within", "14d", "How far ahead to look (e.g. 12h, 7d, 2w)")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, markdown")
+
+	return cmd
+}
+
+// === Secrets Command ===
+
+func newSecretsCmd() *cobra.Command {
+	var scan bool
+
+	cmd := &cobra.Command{
+		Use:   "secrets [name]",
+		Short: "Show credentials found in a goblin's branch",
+		Long: `Show what the last secret scan of a goblin's branch found. gforge push
+scans before pushing; --scan checks the branch again now.
+
+Matches are redacted. A line containing gforge:allow-secret (or
+gitleaks:allow) is never reported, for test fixtures and examples.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showSecrets(optionalArg(args), scan)
+		},
+	}
+
+	cmd.Flags().BoolVar(&scan, "scan", false, "Scan the branch again instead of showing the stored findings")
+
+	return cmd
+}
+
+// === Task Command ===
+
+func newTaskCmd() *cobra.Command {
+	var (
+		goblin       string
+		templateName string
+		now          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "task [description]",
+		Short: "Send a task to a goblin",
+		Long: `Send a task description to a running goblin (default: the current
+goblin set with gforge use).
+The task will be typed into the goblin's terminal session.
+
+While the goblin is still on an earlier task, the new one is queued
+instead and sent once the agent goes quiet (notifications.idle_timeout
+without output), by gforge daemon or gforge notify watch. See the queue
+with gforge tasks and drop it with gforge tasks drop. With
+locks.on_conflict set to queue, a task naming paths another goblin has
+locked is queued too, until they are unlocked. --now types the task in
+straight away.
+
+With --template the prompt is read from .gforge/tasks/<name>.md in the
+goblin's project; a description, if given, is appended to it.
+
+Examples:
+  gforge task "Add input validation" -g coder
+  gforge task --template review -g coder
+  gforge task -t fix-tests "Only the storage package" -g coder
+  gforge task --now "Stop and run the tests first" -g coder`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			description := optionalArg(args)
+			if description == "" && templateName == "" {
+				return fmt.Errorf("requires a task description or --template")
+			}
+			return sendTask(description, goblin, templateName, now)
+		},
+	}
+
+	cmd.Flags().StringVarP(&goblin, "goblin", "g", "", "Target goblin name, or '.' for the current worktree")
+	cmd.Flags().StringVarP(&templateName, "template", "t", "", "Task template from the project's .gforge/tasks")
+	cmd.Flags().BoolVar(&now, "now", false, "Send the task even if the goblin is busy, skipping its queue")
+
+	return cmd
+}
+
+// === Status Command ===
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show system status",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showStatus()
+		},
+	}
+}
+
+// === Stats Command ===
+
+func newStatsCmd() *cobra.Command {
+	var (
+		since string
+		width int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show goblin activity trends",
+		Long: `Chart running, paused and throttled goblin counts from periodic
+snapshots (see stats.snapshot_interval), with task completions and
+failures from the activity log and killed goblins from gforge history,
+over a recent window.`,
+		Example: `  gforge stats
+  gforge stats --since 7d
+  gforge stats --since 12h --width 24`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+			return showStatsHistory(window, width)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "24h", "Window to chart (e.g. 12h, 7d, 2w)")
+	cmd.Flags().IntVar(&width, "width", 48, "Columns per chart")
+
+	return cmd
+}
+
+// === Bench Command ===
+
+func newBenchCmd() *cobra.Command {
+	var (
+		suite      string
+		agentList  string
+		project    string
+		outputFile string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark agents against a suite of tasks",
+		Long: `Run each task in a suite against each agent in an isolated goblin,
+score the results by tests passed, diff size and time, and print a
+comparison report.
+
+Suite format (YAML):
+  name: smoke
+  project: ./myapp        # relative to the suite file
+  tasks:
+    - name: fizzbuzz
+      prompt: "Implement fizzbuzz in main.go"
+      test: "go test ./..."
+      timeout: 10m        # hard limit (default 15m)
+      idle_timeout: 1m    # quiet period that counts as done (default 60s)
+      test_timeout: 5m    # hard limit for the test (default 10m)
+
+Diff size is what the goblin's branch changes from where it started,
+committed or not.
+
+Examples:
+  gforge bench --suite tasks.yaml --agents claude,codex,ollama-qwen
+  gforge bench --suite tasks.yaml --agents claude,codex -o report.md`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBench(suite, agentList, project, outputFile, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&suite, "suite", "", "Suite file (required)")
+	cmd.Flags().StringVar(&agentList, "agents", "", "Comma-separated agents to compare (required)")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project directory (overrides the suite)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the report to a file instead of stdout")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Emit raw results as JSON")
+	cmd.MarkFlagRequired("suite")
+	cmd.MarkFlagRequired("agents")
+
+	return cmd
+}
+
+// === Replay Command ===
+
+func newReplayCmd() *cobra.Command {
+	var (
+		name        string
+		agent       string
+		idleTimeout time.Duration
+		taskTimeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay <goblin>",
+		Short: "Re-run a goblin's tasks on a fresh goblin",
+		Long: `Spawn a fresh goblin on the same project and base commit as an
+existing goblin, then send it the same tasks in the same order. Each task
+is sent once the agent has been quiet for the idle timeout.
+
+Useful for reproducing a session or comparing agents on identical work.
+
+Examples:
+  gforge replay coder
+  gforge replay coder --agent codex --name coder-codex`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replayGoblin(args[0], name, agent, idleTimeout, taskTimeout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Name for the new goblin (default: <goblin>-replay-N)")
+	cmd.Flags().StringVarP(&agent, "agent", "a", "", "Agent to use (default: the original agent)")
+	cmd.Flags().DurationVar(&idleTimeout, "idle-timeout", 60*time.Second, "Quiet period that marks a task as done")
+	cmd.Flags().DurationVar(&taskTimeout, "task-timeout", 30*time.Minute, "Maximum time to wait per task")
+
+	return cmd
+}
+
+// === Fan-out Command ===
+
+func newFanOutCmd() *cobra.Command {
+	var opts fanOutOptions
+
+	cmd := &cobra.Command{
+		Use:   "fanout <name>",
+		Short: "Give one task to several goblins and keep the best result",
+		Long: `Spawn several goblins on the same task, one per agent in --agents or
+--count copies of the default agent, named <name>-<agent> or <name>-N.
+Compare them yourself with gforge diff, or let gforge pick:
+
+With --auto-select, gforge waits for every goblin to go quiet for
+--idle-timeout, commits what each left uncommitted and scores it: the
+tests under done.tests in .gforge.yaml passing (required), the commands
+under checks passing, and a smaller diff. The best goblin goes through
+gforge done with a pull request and the rest are archived. When none pass
+their tests, all are left for review.
+
+  done:
+    tests:
+      - go test ./...
+  checks:
+    commands:
+      - golangci-lint run`,
+		Example: `  gforge fanout retry-fix --agents claude,codex,gemini -t "Fix the flaky retry test"
+  gforge fanout parser -n 3 -t "Rewrite the config parser" --auto-select`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFanOut(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.task, "task", "t", "", "Task to give every goblin (required)")
+	cmd.Flags().StringVar(&opts.agents, "agents", "", "Comma-separated agents, one goblin each")
+	cmd.Flags().IntVarP(&opts.count, "count", "n", 2, "Goblins to spawn with the default agent, without --agents")
+	cmd.Flags().StringVarP(&opts.project, "project", "p", ".", "Project path")
+	cmd.Flags().BoolVar(&opts.autoSelect, "auto-select", false, "Score the goblins, open a PR for the best and archive the rest")
+	cmd.Flags().DurationVar(&opts.idleTimeout, "idle-timeout", 60*time.Second, "Quiet period that marks a goblin as done")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 30*time.Minute, "Maximum time to wait for the goblins")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "Open the pull request as a draft")
+	cmd.MarkFlagRequired("task")
+
+	return cmd
+}
+
+// === Run Command ===
+
+func newRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <workflow.yaml>",
+		Short: "Run a workflow of goblins in dependency order",
+		Long: `Run a pipeline of goblins described in a YAML file. Each step spawns a
+goblin, named <workflow>-<step>, once the steps it needs are done; steps
+that don't depend on each other run side by side.
+
+A step starts from the branch of the step it is from (the first of its
+needs unless set), and its task may refer to a finished step's
+{step.branch}, {step.worktree} or {step.name}. A step is done once its
+agent goes quiet for idle_timeout: its work is committed and the goblin
+completed. A step still busy at its timeout fails, and the steps after
+it are skipped. The goblins share a scratchpad named after the workflow
+(gforge pad).
+
+  name: release          # Defaults to the file name
+  project: .             # Relative to the file
+  steps:
+    - name: plan
+      task: Write a plan for the feature to PLAN.md
+    - name: backend
+      agent: codex
+      needs: [plan]
+      task: Implement the backend part of PLAN.md
+      timeout: 1h
+    - name: review
+      needs: [backend]
+      task: Review the changes on {backend.branch}
+      idle_timeout: 2m`,
+		Example: `  gforge run release.yaml`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorkflow(args[0])
+		},
+	}
+}
+
+// === Play Command ===
+
+func newPlayCmd() *cobra.Command {
+	var (
+		speed     float64
+		idleLimit time.Duration
+		upload    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "play <goblin|file.cast>",
+		Short: "Replay a recorded goblin session",
+		Long: `Play back a goblin's terminal session recorded with --record (or
+tmux.record in config). Recordings are asciinema v2 casts, so they can
+also be played with asciinema or shared on asciinema.org.
+
+Examples:
+  gforge play coder
+  gforge play coder --speed 4 --idle-limit 1s
+  gforge play coder --upload`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return playRecording(args[0], speed, idleLimit, upload)
+		},
+	}
+
+	cmd.Flags().Float64VarP(&speed, "speed", "s", 1, "Playback speed multiplier")
+	cmd.Flags().DurationVarP(&idleLimit, "idle-limit", "i", 2*time.Second, "Cap pauses between output (0 = no cap)")
+	cmd.Flags().BoolVar(&upload, "upload", false, "Upload with the asciinema CLI instead of playing")
+
+	return cmd
+}
+
+func newRecordPaneCmd() *cobra.Command {
+	var (
+		width  int
+		height int
+		title  string
+	)
+
+	cmd := &cobra.Command{
+		Use:    "record-pane <file.cast>",
+		Short:  "Write tmux pane output from stdin to a cast file",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return recordPane(args[0], width, height, title)
+		},
+	}
+
+	cmd.Flags().IntVar(&width, "width", 80, "Terminal width")
+	cmd.Flags().IntVar(&height, "height", 24, "Terminal height")
+	cmd.Flags().StringVar(&title, "title", "", "Recording title")
+
+	return cmd
+}
+
+// === Notify Command ===
+
+func newNotifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Notifications for goblin events",
+		Long: `Watch running goblins and send notifications when a task completes, an
+agent fails, or an agent is waiting for approval.
+
+Notifications go to the desktop (notifications.desktop), a Matrix room
+(notifications.matrix) and a Telegram chat (notifications.telegram), each
+once set up. With voice.enabled and voice.feedback_sound on, the events in
+voice.announce are also read aloud (transport voice). Each event can be
+switched off individually (on_complete, on_failure, on_approval) or sent
+only to some transports (notifications.routes).`,
+	}
+
+	var interval time.Duration
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch goblins and send notifications until interrupted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return watchNotifications(interval)
+		},
+	}
+	watchCmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval")
+	cmd.AddCommand(watchCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "test [transport]",
+		Short: "Send a test notification to every transport, or one",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return testNotifications(optionalArg(args))
+		},
+	})
+
+	return cmd
+}
+
+// === Voice Command ===
+
+func newVoiceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "voice",
+		Short: "Hands-free control by voice",
+		Long: `Run the voice daemon in the background so commands can be spoken at any
+time: press voice.hotkey (an evdev key name such as KEY_SCROLLLOCK, or a
+combination such as super+shift+g) and speak, or say voice.wake_word
+followed by the command ("hey goblin, list goblins").
+
+Heard commands are parsed by the daemon and carried out here: spawn (in
+the project given to start), stop, kill, push, task, list and status.
+Commands that show something on screen (attach, diff, the dashboard) need
+a terminal. Under voice.confirm, read-only commands run at once while
+destructive ones (stop, kill) wait for "confirm" to be spoken, or y typed
+in the listener; any class can also be set to run or be refused. Every
+command heard is stored with what was decided; see gforge voice status.
+
+Needs voice.enabled, python3 and the daemon's requirements
+(voice/requirements.txt).`,
+	}
+
+	var project string
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start listening for voice commands in the background",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return startVoice(project)
+		},
+	}
+	startCmd.Flags().StringVarP(&project, "project", "p", ".", "Project directory for spawned goblins")
+	cmd.AddCommand(startCmd)
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Listen for voice commands in the foreground until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serveVoice(project)
+		},
+	}
+	serveCmd.Flags().StringVarP(&project, "project", "p", ".", "Project directory for spawned goblins")
+	cmd.AddCommand(serveCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stop",
+		Short: "Stop listening for voice commands",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return stopVoice()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the voice listener and the last commands heard",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showVoiceStatus()
+		},
+	})
+
+	return cmd
+}
+
+// === Digest Command ===
+
+func newDigestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Email digests of goblin activity",
+		Long: `Summarize goblins spawned, tasks sent and completed, failures and
+PRs over a time window, and email it over SMTP (see digest in config).`,
+	}
+
+	var since time.Duration
+	previewCmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Print the digest for a recent window",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return previewDigest(since)
+		},
+	}
+	previewCmd.Flags().DurationVar(&since, "since", 24*time.Hour, "Window to summarize")
+	cmd.AddCommand(previewCmd)
+
+	var sendSince time.Duration
+	sendCmd := &cobra.Command{
+		Use:   "send",
+		Short: "Email the digest for a recent window now",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sendDigest(sendSince)
+		},
+	}
+	sendCmd.Flags().DurationVar(&sendSince, "since", 24*time.Hour, "Window to summarize")
+	cmd.AddCommand(sendCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "run",
+		Short: "Send digests on the configured schedule until interrupted, without the daemon",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDigest()
+		},
+	})
+
+	return cmd
+}
+
+// === Progress Command ===
+
+func newProgressCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "progress",
+		Short: "Progress comments on the issues goblins work on",
+		Long: `Link a goblin to its GitHub, Linear or Jira issue and post comments there
+summarizing its latest activity and diffstat, so people can follow along
+without gforge. Issues are written gh:owner/repo#123, linear:PROJ-456 or
+jira:PROJ-789 (gh needs the gh CLI, Linear LINEAR_API_KEY, Jira
+JIRA_BASE_URL, JIRA_EMAIL and JIRA_API_TOKEN).
+
+gforge progress run posts every integrations.progress_interval for goblins
+that have had activity since their last comment.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "link <name> <issue>",
+		Short: "Link a goblin to the issue it is working on",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return linkIssue(args[0], args[1])
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "unlink [name]",
+		Short: "Stop progress comments for a goblin",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return unlinkIssue(optionalArg(args))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show [name]",
+		Short: "Print the comment the next progress update would post",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showProgress(optionalArg(args))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "post [name]",
+		Short: "Post a progress comment now",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postProgress(optionalArg(args))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "run",
+		Short: "Post progress comments on schedule until interrupted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProgress()
+		},
+	})
+
+	return cmd
+}
+
+// === Webhook Command ===
+
+func newWebhookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Post goblin lifecycle events to outbound webhooks",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "test",
+		Short: "Send a ping to each outbound webhook",
+		Long: `Post a ping event to each URL under webhooks.outbound. Goblin lifecycle
+events (spawned, completed, failed, stopped, killed, commit) are posted to
+them as they happen, as JSON signed with the webhook's secret in
+X-Gforge-Signature (sha256=<hex HMAC-SHA256 of the body>):
+
+  {"event": "completed", "goblin_id": "...", "goblin": "fixer",
+   "agent": "claude", "project": "/src/app", "branch": "gforge/fixer",
+   "time": "2026-01-02T15:04:05Z"}
+
+Commit events carry the commit and its subject in commit and detail.
+Commits the agent makes are seen by gforge daemon; those gforge makes, on
+done or shutdown, are sent straight away.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return testWebhooks()
+		},
+	})
+
+	return cmd
+}
+
+// === Actions Command ===
+
+func newActionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "actions",
+		Short: "GitHub Actions workflows dispatched for goblins",
+		Long: `Trigger GitHub Actions workflows for a goblin's branch and follow their
+runs (needs the gh CLI). Workflows are listed under dispatch in the
+project's .gforge.yaml:
+
+  dispatch:
+    - workflow: e2e.yml        # workflow_dispatch, on the goblin's branch
+      inputs: {suite: full}
+    - on: merge
+      event_type: deploy       # repository_dispatch, on the default branch
+
+Those with on: push (the default) are triggered by gforge push; run
+gforge actions dispatch --on merge once the goblin's PR is merged. Inputs
+may use {branch} and {name}.`,
+	}
+
+	var watch bool
+	statusCmd := &cobra.Command{
+		Use:   "status [name]",
+		Short: "Show the runs of a goblin's dispatched workflows",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showWorkflowRuns(optionalArg(args), watch)
+		},
+	}
+	statusCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Keep refreshing until every run finishes")
+	cmd.AddCommand(statusCmd)
+
+	var on string
+	dispatchCmd := &cobra.Command{
+		Use:   "dispatch [name]",
+		Short: "Trigger a goblin's workflows now",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dispatchWorkflows(optionalArg(args), on)
+		},
+	}
+	dispatchCmd.Flags().StringVar(&on, "on", config.DispatchOnPush, "Which workflows to trigger: push or merge")
+	cmd.AddCommand(dispatchCmd)
+
+	return cmd
+}
+
+// === Statusline Command ===
+
+func newStatusLineCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "statusline",
+		Short: "Print a one-line goblin summary for status bars",
+		Long: `Print running, waiting and stopped goblin counts on one line, for
+embedding in tmux, starship or polybar status bars.
+
+Formats: emoji (default), plain, tmux, or a Go template over
+.Running, .Waiting, .Stopped and .Total.
+
+Examples:
+  # ~/.tmux.conf
+  set -g status-right '#(gforge statusline --format tmux)'
+
+  gforge statusline --format '{{.Running}}/{{.Total}} goblins'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printStatusLine(format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "emoji", "Output format: emoji, plain, tmux or a Go template")
+
+	return cmd
+}
+
+// === Prompt Info Command ===
+
+func newPromptInfoCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "prompt-info [path]",
+		Short: "Print the goblin owning the current directory, for shell prompts",
+		Long: `Print the name, agent and status of the goblin whose worktree contains
+the current directory (or path). Prints nothing outside a worktree, so it
+can be dropped straight into PS1 or a starship custom module.
+
+Formats: default, plain, name, or a Go template over .Name, .Agent,
+.Status and .Branch.
+
+Examples:
+  # bash
+  PS1='$(gforge prompt-info --format "[{{.Name}}] ")'$PS1
+
+  # starship.toml
+  [custom.goblin]
+  command = "gforge prompt-info"
+  when = "gforge prompt-info --format name | grep -q ."`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return printPromptInfo(path, format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "default", "Output format: default, plain, name or a Go template")
+
+	return cmd
+}
+
+// === Which Command ===
+
+func newWhichCmd() *cobra.Command {
+	var nameOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "which [path]",
+		Short: "Show the goblin owning a directory",
+		Long: `Resolve the current directory (or path) to the goblin whose worktree
+contains it and print its details.
+
+Inside a worktree, other commands accept '.' in place of a goblin name:
+  gforge diff .
+  gforge task "add tests" --goblin .`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return whichGoblin(path, nameOnly)
+		},
+	}
+
+	cmd.Flags().BoolVar(&nameOnly, "name", false, "Print only the goblin name")
+
+	return cmd
+}
+
+// === Use Command ===
+
+func newUseCmd() *cobra.Command {
+	var clearCurrent bool
+
+	cmd := &cobra.Command{
+		Use:   "use [goblin]",
+		Short: "Set the current goblin for task, logs, diff and attach",
+		Long: `Set a current goblin so task, logs, diff and attach can omit the
+goblin name. With no argument, print the current goblin.
+
+Examples:
+  gforge use coder
+  gforge task "add tests"     # goes to coder
+  gforge use --clear`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if clearCurrent {
+				return useGoblin("", true)
+			}
+			return useGoblin(optionalArg(args), false)
+		},
+	}
+
+	cmd.Flags().BoolVar(&clearCurrent, "clear", false, "Clear the current goblin")
+
+	return cmd
+}
+
+// === Alias Command ===
+
+func newAliasCmd() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "alias [alias goblin]",
+		Short: "Give goblins short names",
+		Long: `Define aliases usable anywhere a goblin name is accepted. With no
+arguments, list aliases. Numeric list indexes work too: gforge attach 2
+attaches to the second goblin in gforge list.
+
+Examples:
+  gforge alias auth refactor-authentication-module
+  gforge attach auth
+  gforge alias --rm auth`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return manageAliases(args, remove)
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "rm", false, "Remove an alias")
+
+	return cmd
+}
+
+// optionalArg returns the first positional argument, or "" if none
+func optionalArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
 }
 
 // === Top Command (TUI Dashboard) ===
 
 func newTopCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "top",
-		Short: "Launch interactive TUI dashboard",
-		Long: `Launch an htop-like interactive terminal UI for managing goblins.
+		Use:     "top",
+		Aliases: []string{"ui"},
+		Short:   "Launch interactive TUI dashboard",
+		Long: `Launch an htop-like interactive terminal UI for managing goblins: every
+goblin with its live status, agent, age and branch, and the recent output
+of the selected one.
 
 Keybindings:
   j/k, Up/Down  Navigate goblin list
   a, Enter      Attach to selected goblin
+  t             Send a task to selected goblin
   s             Stop selected goblin
-  K (Shift+K)   Kill selected goblin
+  K (Shift+K)   Kill selected goblin, once confirmed with y
+  P (Shift+P)   Pin or unpin selected goblin
   d             Show diff for selected goblin
   r             Refresh goblin list
   ?             Show help
   q             Quit`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if remote != nil {
+				return fmt.Errorf("top is not supported with --server")
+			}
 			coord := coordinator.New(db, cfg, log)
 			return tui.Run(coord)
 		},
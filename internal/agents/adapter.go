@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -140,10 +141,15 @@ type LifecycleEvent struct {
 	Details   map[string]string
 }
 
+// maxLifecycleEvents caps the events a long-running manager keeps
+const maxLifecycleEvents = 1000
+
 // LifecycleManager handles agent lifecycle events
 type LifecycleManager struct {
+	mu       sync.Mutex
 	events   []LifecycleEvent
 	handlers []func(LifecycleEvent)
+	running  sync.WaitGroup
 }
 
 // NewLifecycleManager creates a new lifecycle manager
@@ -156,21 +162,42 @@ func NewLifecycleManager() *LifecycleManager {
 
 // OnEvent registers a handler for lifecycle events
 func (lm *LifecycleManager) OnEvent(handler func(LifecycleEvent)) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
 	lm.handlers = append(lm.handlers, handler)
 }
 
-// Emit emits a lifecycle event
+// Emit emits a lifecycle event, running each handler in the background
 func (lm *LifecycleManager) Emit(event LifecycleEvent) {
 	event.Timestamp = time.Now()
-	lm.events = append(lm.events, event)
 
-	for _, h := range lm.handlers {
-		go h(event)
+	lm.mu.Lock()
+	lm.events = append(lm.events, event)
+	if len(lm.events) > maxLifecycleEvents {
+		lm.events = lm.events[len(lm.events)-maxLifecycleEvents:]
 	}
+	handlers := lm.handlers
+	lm.mu.Unlock()
+
+	for _, h := range handlers {
+		lm.running.Add(1)
+		go func(h func(LifecycleEvent)) {
+			defer lm.running.Done()
+			h(event)
+		}(h)
+	}
+}
+
+// Wait blocks until the handlers of every emitted event have returned,
+// so a short-lived process doesn't exit mid-delivery
+func (lm *LifecycleManager) Wait() {
+	lm.running.Wait()
 }
 
 // RecentEvents returns recent lifecycle events
 func (lm *LifecycleManager) RecentEvents(limit int) []LifecycleEvent {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
 	if len(lm.events) <= limit {
 		return lm.events
 	}
@@ -1,6 +1,7 @@
 package agents
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
@@ -184,6 +185,28 @@ func TestLifecycleManager(t *testing.T) {
 	}
 }
 
+func TestLifecycleManagerWait(t *testing.T) {
+	lm := NewLifecycleManager()
+
+	var mu sync.Mutex
+	delivered := 0
+	for i := 0; i < 3; i++ {
+		lm.OnEvent(func(e LifecycleEvent) {
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			delivered++
+			mu.Unlock()
+		})
+	}
+
+	lm.Emit(LifecycleEvent{Type: "spawned", GoblinID: "test-123"})
+	lm.Wait()
+
+	if delivered != 3 {
+		t.Errorf("Expected all 3 handlers done after Wait, got %d", delivered)
+	}
+}
+
 func TestLifecycleManagerRecentEvents(t *testing.T) {
 	lm := NewLifecycleManager()
 
@@ -0,0 +1,53 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// OneShotCommand returns the command line that answers prompt and exits
+func (a *Agent) OneShotCommand(prompt string) ([]string, error) {
+	if a.PromptArgs == nil {
+		return nil, fmt.Errorf("agent %s does not support one-shot prompts", a.Name)
+	}
+
+	cmd := []string{a.Command}
+	cmd = append(cmd, a.PromptArgs...)
+	return append(cmd, prompt), nil
+}
+
+// Ask runs an agent once in a transient process: input (if any) is piped
+// to its stdin and its answer streamed to out as it is produced. Nothing
+// is recorded and no worktree or tmux session is involved.
+func Ask(ctx context.Context, agent *Agent, prompt string, input io.Reader, out, errOut io.Writer) error {
+	argv, err := agent.OneShotCommand(prompt)
+	if err != nil {
+		return err
+	}
+
+	if err := agent.CheckInstalled(); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = input
+	cmd.Stdout = out
+	cmd.Stderr = errOut
+
+	env := os.Environ()
+	for k, v := range agent.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	cmd.Env = env
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%s failed: %w", agent.Name, err)
+	}
+	return nil
+}
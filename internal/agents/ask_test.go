@@ -0,0 +1,56 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestOneShotCommand(t *testing.T) {
+	r := NewRegistry()
+
+	cmd, err := r.Get("claude").OneShotCommand("explain this")
+	if err != nil || strings.Join(cmd, " ") != "claude -p explain this" {
+		t.Errorf("Unexpected claude command %v (%v)", cmd, err)
+	}
+
+	cmd, _ = r.Get("ollama-qwen").OneShotCommand("hi")
+	if strings.Join(cmd, " ") != "ollama run qwen2.5-coder:7b hi" {
+		t.Errorf("Unexpected ollama command %v", cmd)
+	}
+
+	for _, a := range r.List() {
+		if a.PromptArgs == nil {
+			t.Errorf("Built-in agent %s has no one-shot mode", a.Name)
+		}
+	}
+
+	if _, err := (&Agent{Name: "custom", Command: "x"}).OneShotCommand("hi"); err == nil {
+		t.Error("Expected an error for an agent without PromptArgs")
+	}
+}
+
+func TestAsk(t *testing.T) {
+	// The prompt lands in $0 of the script
+	agent := &Agent{
+		Name:       "echo",
+		Command:    "sh",
+		PromptArgs: []string{"-c", `printf '%s: ' "$0"; cat; printf ' %s' "$ASK_VAR"`},
+		Env:        map[string]string{"ASK_VAR": "set"},
+	}
+
+	var out, errOut bytes.Buffer
+	err := Ask(context.Background(), agent, "question", strings.NewReader("piped diff"), &out, &errOut)
+	if err != nil {
+		t.Fatalf("Ask failed: %v (%s)", err, errOut.String())
+	}
+	if out.String() != "question: piped diff set" {
+		t.Errorf("Unexpected answer %q", out.String())
+	}
+
+	failing := &Agent{Name: "failing", Command: "sh", PromptArgs: []string{"-c", "exit 2"}}
+	if err := Ask(context.Background(), failing, "q", nil, &out, &errOut); err == nil {
+		t.Error("Expected an error when the agent fails")
+	}
+}
@@ -1,8 +1,11 @@
 package agents
 
 import (
+	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
 )
 
 // Registry manages agent definitions
@@ -21,6 +24,16 @@ type Agent struct {
 	InstallHint  string
 	Env          map[string]string
 	AutoAccept   bool
+
+	// ContextWindow is the model's context size in tokens (0 if unknown)
+	ContextWindow int
+
+	// Provider is the service the agent's requests count against
+	Provider string
+
+	// PromptArgs run the agent once, non-interactively: the prompt is
+	// appended and the answer written to stdout. Nil if unsupported.
+	PromptArgs []string
 }
 
 // Detection defines how to detect if an agent is installed
@@ -58,6 +71,7 @@ func (r *Registry) registerBuiltinAgents() {
 		Command:     "claude",
 		Args:        []string{},
 		Description: "Anthropic Claude Code CLI - AI coding assistant",
+		Provider:    "anthropic",
 		Capabilities: []string{
 			"code",     // Code generation and editing
 			"git",      // Git operations
@@ -71,8 +85,10 @@ func (r *Registry) registerBuiltinAgents() {
 			VersionCmd:  "claude",
 			VersionArgs: []string{"--version"},
 		},
-		InstallHint: "Visit https://claude.ai/code or install via: npm install -g @anthropic/claude-code",
-		AutoAccept:  false,
+		InstallHint:   "Visit https://claude.ai/code or install via: npm install -g @anthropic/claude-code",
+		AutoAccept:    false,
+		ContextWindow: 200000,
+		PromptArgs:    []string{"-p"},
 	}
 
 	// Claude with auto-accept (dangerous mode)
@@ -81,6 +97,7 @@ func (r *Registry) registerBuiltinAgents() {
 		Command:     "claude",
 		Args:        []string{"--dangerously-skip-permissions"},
 		Description: "Claude Code with auto-accept mode (use with caution)",
+		Provider:    "anthropic",
 		Capabilities: []string{
 			"code", "git", "fs", "web", "mcp", "terminal",
 		},
@@ -89,8 +106,10 @@ func (r *Registry) registerBuiltinAgents() {
 			VersionCmd:  "claude",
 			VersionArgs: []string{"--version"},
 		},
-		InstallHint: "Same as claude - uses dangerous auto-accept flag",
-		AutoAccept:  true,
+		InstallHint:   "Same as claude - uses dangerous auto-accept flag",
+		AutoAccept:    true,
+		ContextWindow: 200000,
+		PromptArgs:    []string{"-p", "--dangerously-skip-permissions"},
 	}
 
 	// OpenAI Codex CLI
@@ -99,6 +118,7 @@ func (r *Registry) registerBuiltinAgents() {
 		Command:     "codex",
 		Args:        []string{},
 		Description: "OpenAI Codex CLI - Code generation agent",
+		Provider:    "openai",
 		Capabilities: []string{
 			"code",
 			"terminal",
@@ -108,8 +128,10 @@ func (r *Registry) registerBuiltinAgents() {
 			VersionCmd:  "codex",
 			VersionArgs: []string{"--version"},
 		},
-		InstallHint: "Install via: npm install -g @openai/codex",
-		AutoAccept:  false,
+		InstallHint:   "Install via: npm install -g @openai/codex",
+		AutoAccept:    false,
+		ContextWindow: 200000,
+		PromptArgs:    []string{"exec"},
 	}
 
 	// Google Gemini CLI
@@ -118,6 +140,7 @@ func (r *Registry) registerBuiltinAgents() {
 		Command:     "gemini",
 		Args:        []string{},
 		Description: "Google Gemini CLI - Multimodal AI assistant",
+		Provider:    "google",
 		Capabilities: []string{
 			"code",
 			"web",
@@ -128,8 +151,10 @@ func (r *Registry) registerBuiltinAgents() {
 			VersionCmd:  "gemini",
 			VersionArgs: []string{"--version"},
 		},
-		InstallHint: "Install via: pip install google-generativeai or npm install -g @google/gemini-cli",
-		AutoAccept:  false,
+		InstallHint:   "Install via: pip install google-generativeai or npm install -g @google/gemini-cli",
+		AutoAccept:    false,
+		ContextWindow: 1000000,
+		PromptArgs:    []string{"-p"},
 	}
 
 	// Ollama - Local LLM runner
@@ -138,6 +163,7 @@ func (r *Registry) registerBuiltinAgents() {
 		Command:     "ollama",
 		Args:        []string{"run", "codellama"},
 		Description: "Ollama - Run local LLMs (CodeLlama, DeepSeek, etc.)",
+		Provider:    "ollama",
 		Capabilities: []string{
 			"code",
 			"local", // Runs locally, no API needed
@@ -147,8 +173,10 @@ func (r *Registry) registerBuiltinAgents() {
 			VersionCmd:  "ollama",
 			VersionArgs: []string{"--version"},
 		},
-		InstallHint: "Install from https://ollama.ai or: curl -fsSL https://ollama.ai/install.sh | sh",
-		AutoAccept:  false,
+		InstallHint:   "Install from https://ollama.ai or: curl -fsSL https://ollama.ai/install.sh | sh",
+		AutoAccept:    false,
+		ContextWindow: 16384,
+		PromptArgs:    []string{"run", "codellama"},
 		Env: map[string]string{
 			"OLLAMA_HOST": "127.0.0.1:11434",
 		},
@@ -160,6 +188,7 @@ func (r *Registry) registerBuiltinAgents() {
 		Command:     "ollama",
 		Args:        []string{"run", "deepseek-coder:6.7b"},
 		Description: "Ollama with DeepSeek Coder model",
+		Provider:    "ollama",
 		Capabilities: []string{
 			"code",
 			"local",
@@ -169,8 +198,10 @@ func (r *Registry) registerBuiltinAgents() {
 			VersionCmd:  "ollama",
 			VersionArgs: []string{"--version"},
 		},
-		InstallHint: "Install ollama, then: ollama pull deepseek-coder:6.7b",
-		AutoAccept:  false,
+		InstallHint:   "Install ollama, then: ollama pull deepseek-coder:6.7b",
+		AutoAccept:    false,
+		ContextWindow: 16384,
+		PromptArgs:    []string{"run", "deepseek-coder:6.7b"},
 	}
 
 	// Ollama with Qwen Coder
@@ -179,6 +210,7 @@ func (r *Registry) registerBuiltinAgents() {
 		Command:     "ollama",
 		Args:        []string{"run", "qwen2.5-coder:7b"},
 		Description: "Ollama with Qwen 2.5 Coder model",
+		Provider:    "ollama",
 		Capabilities: []string{
 			"code",
 			"local",
@@ -188,8 +220,10 @@ func (r *Registry) registerBuiltinAgents() {
 			VersionCmd:  "ollama",
 			VersionArgs: []string{"--version"},
 		},
-		InstallHint: "Install ollama, then: ollama pull qwen2.5-coder:7b",
-		AutoAccept:  false,
+		InstallHint:   "Install ollama, then: ollama pull qwen2.5-coder:7b",
+		AutoAccept:    false,
+		ContextWindow: 32768,
+		PromptArgs:    []string{"run", "qwen2.5-coder:7b"},
 	}
 }
 
@@ -207,67 +241,6 @@ func (r *Registry) List() []*Agent {
 	return agents
 }
 
-// Scan discovers which agents are installed on the system
-func (r *Registry) Scan() []DetectedAgent {
-	var detected []DetectedAgent
-	seen := make(map[string]bool) // Track by binary to avoid duplicates
-
-	for _, agent := range r.agents {
-		// Skip if we've already checked this binary
-		if seen[agent.Detection.Binary] {
-			continue
-		}
-		seen[agent.Detection.Binary] = true
-
-		// Check if binary exists in PATH
-		path, err := exec.LookPath(agent.Detection.Binary)
-		if err != nil {
-			continue // Not installed
-		}
-
-		// Get version
-		version := r.getVersion(agent)
-
-		detected = append(detected, DetectedAgent{
-			Name:    agent.Name,
-			Path:    path,
-			Version: version,
-		})
-	}
-
-	return detected
-}
-
-// getVersion runs the version command and extracts version string
-func (r *Registry) getVersion(agent *Agent) string {
-	if agent.Detection.VersionCmd == "" {
-		return "unknown"
-	}
-
-	args := agent.Detection.VersionArgs
-	cmd := exec.Command(agent.Detection.VersionCmd, args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "unknown"
-	}
-
-	// Extract first line and clean it up
-	lines := strings.Split(string(output), "\n")
-	if len(lines) > 0 {
-		version := strings.TrimSpace(lines[0])
-		// Try to extract just the version number
-		parts := strings.Fields(version)
-		for _, p := range parts {
-			if strings.ContainsAny(p, "0123456789.") && !strings.HasPrefix(p, "-") {
-				return p
-			}
-		}
-		return version
-	}
-
-	return "unknown"
-}
-
 // NotInstalled returns agent names that are not installed
 func (r *Registry) NotInstalled(detected []DetectedAgent) []string {
 	installed := make(map[string]bool)
@@ -319,6 +292,15 @@ func (a *Agent) HasCapability(cap string) bool {
 	return false
 }
 
+// CheckInstalled returns ErrAgentNotInstalled, with the install hint,
+// when the agent's command is not on PATH
+func (a *Agent) CheckInstalled() error {
+	if _, err := exec.LookPath(a.Command); err != nil {
+		return fmt.Errorf("%w: %s. %s", errs.ErrAgentNotInstalled, a.Name, a.InstallHint)
+	}
+	return nil
+}
+
 // GetCommand returns the full command to run the agent
 func (a *Agent) GetCommand() []string {
 	cmd := []string{a.Command}
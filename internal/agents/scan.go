@@ -0,0 +1,140 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// scanWorkers bounds how many version commands run at once
+	scanWorkers = 4
+
+	// versionTimeout caps each version command, so one stalled agent
+	// can't hang a scan
+	versionTimeout = 3 * time.Second
+)
+
+// Scan discovers which agents are installed on the system. Version
+// commands run in parallel; one that times out reports "unknown".
+func (r *Registry) Scan() []DetectedAgent {
+	// Variants share a binary; sorting makes the base agent ("claude",
+	// "ollama") the one reported for it
+	names := make([]string, 0, len(r.agents))
+	for name := range r.agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var candidates []DetectedAgent
+	var pending []*Agent
+	seen := make(map[string]bool) // Track by binary to avoid duplicates
+	for _, name := range names {
+		agent := r.agents[name]
+		if seen[agent.Detection.Binary] {
+			continue
+		}
+		seen[agent.Detection.Binary] = true
+
+		path, err := exec.LookPath(agent.Detection.Binary)
+		if err != nil {
+			continue // Not installed
+		}
+		candidates = append(candidates, DetectedAgent{Name: agent.Name, Path: path})
+		pending = append(pending, agent)
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, scanWorkers)
+	for i := range candidates {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			candidates[i].Version = r.getVersion(pending[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return candidates
+}
+
+// getVersion runs the version command and extracts version string
+func (r *Registry) getVersion(agent *Agent) string {
+	if agent.Detection.VersionCmd == "" {
+		return "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, agent.Detection.VersionCmd, agent.Detection.VersionArgs...)
+	// Don't wait on children that inherited the output pipe
+	cmd.WaitDelay = 100 * time.Millisecond
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "unknown"
+	}
+
+	// Extract first line and clean it up
+	lines := strings.Split(string(output), "\n")
+	if len(lines) > 0 {
+		version := strings.TrimSpace(lines[0])
+		// Try to extract just the version number
+		parts := strings.Fields(version)
+		for _, p := range parts {
+			if strings.ContainsAny(p, "0123456789.") && !strings.HasPrefix(p, "-") {
+				return p
+			}
+		}
+		return version
+	}
+
+	return "unknown"
+}
+
+// scanCache is the on-disk record of the last scan
+type scanCache struct {
+	Path      string          `json:"path"` // $PATH the scan ran with
+	ScannedAt time.Time       `json:"scanned_at"`
+	Agents    []DetectedAgent `json:"agents"`
+}
+
+// ScanCached returns the scan stored in file when it is younger than ttl
+// and was made with the current $PATH, and otherwise scans and stores the
+// result. A ttl of 0 always scans.
+func (r *Registry) ScanCached(file string, ttl time.Duration) []DetectedAgent {
+	if ttl > 0 {
+		if data, err := os.ReadFile(file); err == nil {
+			var cache scanCache
+			if json.Unmarshal(data, &cache) == nil &&
+				cache.Path == os.Getenv("PATH") && time.Since(cache.ScannedAt) < ttl {
+				return cache.Agents
+			}
+		}
+	}
+
+	detected := r.Scan()
+	r.SaveScan(file, detected)
+	return detected
+}
+
+// SaveScan stores a scan for ScanCached. Failures are ignored: the cache
+// only saves time.
+func (r *Registry) SaveScan(file string, detected []DetectedAgent) {
+	data, err := json.Marshal(scanCache{Path: os.Getenv("PATH"), ScannedAt: time.Now(), Agents: detected})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return
+	}
+	os.WriteFile(file, data, 0644)
+}
@@ -0,0 +1,83 @@
+package agents
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeAgent installs a script named name in dir that runs body
+func fakeAgent(t *testing.T, dir, name, body string) *Agent {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return &Agent{Name: name, Command: name, Detection: Detection{
+		Binary: name, VersionCmd: name, VersionArgs: []string{"--version"},
+	}}
+}
+
+func TestScanParallel(t *testing.T) {
+	sleepPath, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep not installed")
+	}
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	versionTimeout = 300 * time.Millisecond
+	defer func() { versionTimeout = 3 * time.Second }()
+
+	r := &Registry{agents: make(map[string]*Agent)}
+	r.Register(fakeAgent(t, dir, "fast", "echo fast 1.2.3"))
+	for _, name := range []string{"stall-a", "stall-b", "stall-c", "stall-d", "stall-e"} {
+		r.Register(fakeAgent(t, dir, name, sleepPath+" 30"))
+	}
+
+	start := time.Now()
+	detected := r.Scan()
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("Expected stalled version commands cut short, scan took %s", elapsed)
+	}
+
+	if len(detected) != 6 {
+		t.Fatalf("Expected 6 agents, got %d", len(detected))
+	}
+	for _, d := range detected {
+		want := "unknown"
+		if d.Name == "fast" {
+			want = "1.2.3"
+		}
+		if d.Version != want {
+			t.Errorf("Expected %s version %q, got %q", d.Name, want, d.Version)
+		}
+	}
+}
+
+func TestScanCached(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	file := filepath.Join(t.TempDir(), "scan.json")
+
+	r := &Registry{agents: make(map[string]*Agent)}
+	r.Register(fakeAgent(t, dir, "agent", "echo agent 1.0"))
+
+	if got := r.ScanCached(file, time.Hour); len(got) != 1 || got[0].Version != "1.0" {
+		t.Fatalf("Expected a fresh scan, got %+v", got)
+	}
+
+	fakeAgent(t, dir, "agent", "echo agent 2.0")
+	if got := r.ScanCached(file, time.Hour); got[0].Version != "1.0" {
+		t.Errorf("Expected the cached scan reused, got %s", got[0].Version)
+	}
+	if got := r.ScanCached(file, 0); got[0].Version != "2.0" {
+		t.Errorf("Expected a zero TTL to rescan, got %s", got[0].Version)
+	}
+
+	fakeAgent(t, dir, "agent", "echo agent 3.0")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+t.TempDir())
+	if got := r.ScanCached(file, time.Hour); got[0].Version != "3.0" {
+		t.Errorf("Expected a changed PATH to rescan, got %s", got[0].Version)
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+func TestSpecCoversAllRoutes(t *testing.T) {
+	spec := Spec("1.2.3")
+
+	if spec["openapi"] != "3.0.3" {
+		t.Errorf("Expected openapi 3.0.3, got %v", spec["openapi"])
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	for _, r := range Routes {
+		item, ok := paths[r.Path].(map[string]interface{})
+		if !ok {
+			t.Errorf("Path %s missing from spec", r.Path)
+			continue
+		}
+		if _, ok := item[strings.ToLower(r.Method)]; !ok {
+			t.Errorf("Operation %s %s missing from spec", r.Method, r.Path)
+		}
+	}
+
+	// Spec must be serializable
+	if _, err := json.Marshal(spec); err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+}
+
+func TestSpecSchemas(t *testing.T) {
+	spec := Spec("dev")
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	for _, name := range []string{"Goblin", "SpawnRequest", "TaskRequest", "Logs", "Stats", "Error"} {
+		if schemas[name] == nil {
+			t.Errorf("Schema %s missing", name)
+		}
+	}
+
+	goblin := schemas["Goblin"].(map[string]interface{})
+	props := goblin["properties"].(map[string]interface{})
+	created := props["created_at"].(map[string]interface{})
+	if created["format"] != "date-time" {
+		t.Errorf("Expected created_at to be date-time, got %v", created["format"])
+	}
+
+	spawn := schemas["SpawnRequest"].(map[string]interface{})
+	for _, r := range spawn["required"].([]string) {
+		if r == "branch" || r == "task" {
+			t.Errorf("Optional field %s should not be required", r)
+		}
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	params := pathParams("/v1/goblins/{name}/task")
+	if len(params) != 1 || params[0] != "name" {
+		t.Errorf("Expected [name], got %v", params)
+	}
+
+	if params := pathParams("/v1/stats"); len(params) != 0 {
+		t.Errorf("Expected no params, got %v", params)
+	}
+}
+
+func TestClient(t *testing.T) {
+	var lastTask string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/goblins", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Goblin{{Name: "coder", Agent: "claude"}})
+	})
+	mux.HandleFunc("/v1/goblins/coder/task", func(w http.ResponseWriter, r *http.Request) {
+		var req TaskRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		lastTask = req.Task
+	})
+	mux.HandleFunc("/v1/goblins/coder/logs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(Error{Error: "unauthorized"})
+			return
+		}
+		json.NewEncoder(w).Encode(Logs{Lines: []string{"line " + r.URL.Query().Get("lines")}})
+	})
+	mux.HandleFunc("/v1/goblins/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(NewError(errs.WithHint(fmt.Errorf("%w: missing", errs.ErrGoblinNotFound), "gforge list")))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewClient(srv.URL + "/")
+
+	goblins, err := client.ListGoblins()
+	if err != nil {
+		t.Fatalf("ListGoblins failed: %v", err)
+	}
+	if len(goblins) != 1 || goblins[0].Name != "coder" {
+		t.Errorf("Unexpected goblins: %+v", goblins)
+	}
+
+	if err := client.SendTask("coder", "write tests"); err != nil {
+		t.Fatalf("SendTask failed: %v", err)
+	}
+	if lastTask != "write tests" {
+		t.Errorf("Expected task 'write tests', got '%s'", lastTask)
+	}
+
+	if _, err := client.Logs("coder", 5); err == nil {
+		t.Error("Expected logs refused without a token")
+	}
+	client.Token = "t0ken"
+	lines, err := client.Logs("coder", 5)
+	if err != nil || len(lines) != 1 || lines[0] != "line 5" {
+		t.Errorf("Expected the logs with a token, got %v, %v", lines, err)
+	}
+
+	_, err = client.GetGoblin("missing")
+	if err == nil {
+		t.Fatal("Expected error for missing goblin")
+	}
+	if !strings.Contains(err.Error(), "goblin not found") {
+		t.Errorf("Expected server error message, got: %v", err)
+	}
+	if !errors.Is(err, errs.ErrGoblinNotFound) {
+		t.Errorf("Expected the error code mapped back to ErrGoblinNotFound, got: %v", err)
+	}
+	if errs.Hint(err) != "gforge list" {
+		t.Errorf("Expected the server's hint, got %q", errs.Hint(err))
+	}
+}
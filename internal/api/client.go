@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+// Client is a typed client for the gforge REST API
+type Client struct {
+	baseURL string
+	client  *http.Client
+
+	// Token is sent as a bearer token to servers that require one
+	Token string
+}
+
+// NewClient creates a new API client for the server at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ListGoblins returns all goblins
+func (c *Client) ListGoblins() ([]Goblin, error) {
+	var goblins []Goblin
+	if err := c.do("GET", "/v1/goblins", nil, &goblins); err != nil {
+		return nil, err
+	}
+	return goblins, nil
+}
+
+// GetGoblin retrieves a goblin by name or ID
+func (c *Client) GetGoblin(nameOrID string) (*Goblin, error) {
+	var g Goblin
+	if err := c.do("GET", "/v1/goblins/"+url.PathEscape(nameOrID), nil, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Spawn creates and starts a new goblin
+func (c *Client) Spawn(req SpawnRequest) (*Goblin, error) {
+	var g Goblin
+	if err := c.do("POST", "/v1/goblins", req, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Stop stops a running goblin
+func (c *Client) Stop(nameOrID string) error {
+	return c.do("POST", "/v1/goblins/"+url.PathEscape(nameOrID)+"/stop", nil, nil)
+}
+
+// Kill kills a goblin and cleans up its resources
+func (c *Client) Kill(nameOrID string) error {
+	return c.do("DELETE", "/v1/goblins/"+url.PathEscape(nameOrID), nil, nil)
+}
+
+// SendTask sends a task to a goblin
+func (c *Client) SendTask(nameOrID, task string) error {
+	return c.do("POST", "/v1/goblins/"+url.PathEscape(nameOrID)+"/task", TaskRequest{Task: task}, nil)
+}
+
+// Logs returns up to lines of a goblin's recent output
+func (c *Client) Logs(nameOrID string, lines int) ([]string, error) {
+	var logs Logs
+	path := fmt.Sprintf("/v1/goblins/%s/logs?lines=%d", url.PathEscape(nameOrID), lines)
+	if err := c.do("GET", path, nil, &logs); err != nil {
+		return nil, err
+	}
+	return logs.Lines, nil
+}
+
+// Stats returns aggregate goblin statistics
+func (c *Client) Stats() (*Stats, error) {
+	var s Stats
+	if err := c.do("GET", "/v1/stats", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// do performs a request and decodes the JSON response into out
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr Error
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			// Keep the cause and hint so the CLI fails as it would locally
+			if cause := errs.FromCode(apiErr.Code, apiErr.Error); cause != nil {
+				return errs.WithHint(fmt.Errorf("server error (%d): %w", resp.StatusCode, cause), apiErr.Hint)
+			}
+			return errs.WithHint(fmt.Errorf("server error (%d): %s", resp.StatusCode, apiErr.Error), apiErr.Hint)
+		}
+		return fmt.Errorf("server error (%d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Spec builds the OpenAPI 3 document for the REST API from the route table
+func Spec(version string) map[string]interface{} {
+	schemas := make(map[string]interface{})
+	paths := make(map[string]interface{})
+
+	// Every non-2xx response shares the Error body
+	errorRef := schemaFor(reflect.TypeOf(Error{}), schemas)
+
+	for _, r := range Routes {
+		op := map[string]interface{}{
+			"operationId": r.OperationID,
+			"summary":     r.Summary,
+		}
+
+		if params := pathParams(r.Path); len(params) > 0 {
+			var list []interface{}
+			for _, p := range params {
+				list = append(list, map[string]interface{}{
+					"name":     p,
+					"in":       "path",
+					"required": true,
+					"schema":   map[string]interface{}{"type": "string"},
+				})
+			}
+			op["parameters"] = list
+		}
+
+		if r.Request != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": schemaFor(reflect.TypeOf(r.Request), schemas),
+					},
+				},
+			}
+		}
+
+		success := map[string]interface{}{"description": "OK"}
+		if r.Response != nil {
+			success["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": schemaFor(reflect.TypeOf(r.Response), schemas),
+				},
+			}
+		}
+
+		op["responses"] = map[string]interface{}{
+			"200": success,
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": errorRef},
+				},
+			},
+		}
+
+		item, ok := paths[r.Path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[r.Path] = item
+		}
+		item[strings.ToLower(r.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Goblin Forge API",
+			"description": "Remote control for gforge goblins",
+			"version":     version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		// Servers may run without tokens, hence the empty alternative
+		"security": []interface{}{
+			map[string]interface{}{"bearerAuth": []string{}},
+			map[string]interface{}{},
+		},
+	}
+}
+
+// schemaFor returns the schema for a Go type, registering named structs
+// under components and returning a $ref to them
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaFor(t.Elem(), schemas),
+		}
+	case t.Kind() == reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem(), schemas),
+		}
+	case t.Kind() == reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+		if _, done := schemas[t.Name()]; done {
+			return ref
+		}
+		// Reserve the name first so self-referencing types terminate
+		schemas[t.Name()] = nil
+
+		props := make(map[string]interface{})
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, omitEmpty := jsonName(f)
+			if name == "-" {
+				continue
+			}
+			props[name] = schemaFor(f.Type, schemas)
+			if !omitEmpty {
+				required = append(required, name)
+			}
+		}
+
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": props,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		schemas[t.Name()] = schema
+		return ref
+	}
+
+	return map[string]interface{}{}
+}
+
+// jsonName returns the JSON field name and whether it is omitempty
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+// pathParams extracts {param} names from a route path
+func pathParams(path string) []string {
+	var params []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params = append(params, seg[1:len(seg)-1])
+		}
+	}
+	return params
+}
@@ -0,0 +1,71 @@
+package api
+
+// Route describes a single REST endpoint. The route table is the source of
+// truth for both the OpenAPI document and the typed client.
+type Route struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Request     interface{} // nil when the endpoint takes no body
+	Response    interface{} // nil when the endpoint returns no body
+}
+
+// Routes lists every endpoint exposed by the gforge REST API
+var Routes = []Route{
+	{
+		Method:      "GET",
+		Path:        "/v1/goblins",
+		OperationID: "listGoblins",
+		Summary:     "List all goblins",
+		Response:    []Goblin{},
+	},
+	{
+		Method:      "POST",
+		Path:        "/v1/goblins",
+		OperationID: "spawnGoblin",
+		Summary:     "Spawn a new goblin",
+		Request:     SpawnRequest{},
+		Response:    Goblin{},
+	},
+	{
+		Method:      "GET",
+		Path:        "/v1/goblins/{name}",
+		OperationID: "getGoblin",
+		Summary:     "Get a goblin by name or ID",
+		Response:    Goblin{},
+	},
+	{
+		Method:      "DELETE",
+		Path:        "/v1/goblins/{name}",
+		OperationID: "killGoblin",
+		Summary:     "Kill a goblin and cleanup its resources",
+	},
+	{
+		Method:      "POST",
+		Path:        "/v1/goblins/{name}/stop",
+		OperationID: "stopGoblin",
+		Summary:     "Stop a running goblin",
+	},
+	{
+		Method:      "POST",
+		Path:        "/v1/goblins/{name}/task",
+		OperationID: "sendTask",
+		Summary:     "Send a task to a goblin",
+		Request:     TaskRequest{},
+	},
+	{
+		Method:      "GET",
+		Path:        "/v1/goblins/{name}/logs",
+		OperationID: "getLogs",
+		Summary:     "Get a goblin's recent output",
+		Response:    Logs{},
+	},
+	{
+		Method:      "GET",
+		Path:        "/v1/stats",
+		OperationID: "getStats",
+		Summary:     "Get aggregate goblin statistics",
+		Response:    Stats{},
+	},
+}
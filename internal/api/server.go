@@ -0,0 +1,250 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+// Lines of output returned by the logs endpoint, by default and at most
+const (
+	defaultLogLines = 50
+	maxLogLines     = 5000
+)
+
+// Backend is what the server runs requests against: the local
+// coordinator, or another server through a *Client
+type Backend interface {
+	ListGoblins() ([]Goblin, error)
+	GetGoblin(nameOrID string) (*Goblin, error)
+	Spawn(req SpawnRequest) (*Goblin, error)
+	Stop(nameOrID string) error
+	Kill(nameOrID string) error
+	SendTask(nameOrID, task string) error
+	Logs(nameOrID string, lines int) ([]string, error)
+	Stats() (*Stats, error)
+}
+
+var _ Backend = (*Client)(nil)
+
+// errBadRequest marks a request the server can't make sense of
+var errBadRequest = errors.New("bad request")
+
+// Server serves the routes in Routes over HTTP
+type Server struct {
+	backend Backend
+	tokens  []string
+	mux     *http.ServeMux
+	mounted *http.ServeMux // Handlers that check their own signatures
+}
+
+// NewServer creates a server for backend. Requests must carry one of
+// tokens as a bearer token; with none, every request is served.
+func NewServer(backend Backend, tokens []string) *Server {
+	s := &Server{backend: backend, tokens: tokens}
+
+	handlers := map[string]http.HandlerFunc{
+		"listGoblins": s.listGoblins,
+		"spawnGoblin": s.spawnGoblin,
+		"getGoblin":   s.getGoblin,
+		"killGoblin":  s.killGoblin,
+		"stopGoblin":  s.stopGoblin,
+		"sendTask":    s.sendTask,
+		"getLogs":     s.getLogs,
+		"getStats":    s.getStats,
+	}
+	s.mux = http.NewServeMux()
+	for _, r := range Routes {
+		s.mux.HandleFunc(r.Method+" "+r.Path, handlers[r.OperationID])
+	}
+	s.mounted = http.NewServeMux()
+	return s
+}
+
+// Mount serves h under pattern next to the API, e.g. issue webhooks under
+// /webhooks/. Senders such as GitHub can't carry an API token, so
+// requests to h skip it and h must verify them itself.
+func (s *Server) Mount(pattern string, h http.Handler) {
+	s.mounted.Handle(pattern, h)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h, pattern := s.mounted.Handler(r); pattern != "" {
+		h.ServeHTTP(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSON(w, http.StatusUnauthorized, Error{Error: "missing or invalid API token"})
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether r carries one of the server's tokens
+func (s *Server) authorized(r *http.Request) bool {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return s.Accepts(token)
+}
+
+// Accepts reports whether token is one of the server's tokens, or the
+// server takes any request
+func (s *Server) Accepts(token string) bool {
+	if len(s.tokens) == 0 {
+		return true
+	}
+	if token == "" {
+		return false
+	}
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listGoblins(w http.ResponseWriter, r *http.Request) {
+	goblins, err := s.backend.ListGoblins()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if goblins == nil {
+		goblins = []Goblin{}
+	}
+	writeJSON(w, http.StatusOK, goblins)
+}
+
+func (s *Server) spawnGoblin(w http.ResponseWriter, r *http.Request) {
+	var req SpawnRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.Name == "" || req.ProjectPath == "" {
+		writeError(w, fmt.Errorf("%w: name and project_path are required", errBadRequest))
+		return
+	}
+
+	g, err := s.backend.Spawn(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+func (s *Server) getGoblin(w http.ResponseWriter, r *http.Request) {
+	g, err := s.backend.GetGoblin(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+func (s *Server) killGoblin(w http.ResponseWriter, r *http.Request) {
+	writeResult(w, s.backend.Kill(r.PathValue("name")))
+}
+
+func (s *Server) stopGoblin(w http.ResponseWriter, r *http.Request) {
+	writeResult(w, s.backend.Stop(r.PathValue("name")))
+}
+
+func (s *Server) sendTask(w http.ResponseWriter, r *http.Request) {
+	var req TaskRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if strings.TrimSpace(req.Task) == "" {
+		writeError(w, fmt.Errorf("%w: task is required", errBadRequest))
+		return
+	}
+	writeResult(w, s.backend.SendTask(r.PathValue("name"), req.Task))
+}
+
+func (s *Server) getLogs(w http.ResponseWriter, r *http.Request) {
+	lines := defaultLogLines
+	if v := r.URL.Query().Get("lines"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, fmt.Errorf("%w: lines must be a positive number", errBadRequest))
+			return
+		}
+		lines = min(n, maxLogLines)
+	}
+
+	output, err := s.backend.Logs(r.PathValue("name"), lines)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if output == nil {
+		output = []string{}
+	}
+	writeJSON(w, http.StatusOK, Logs{Lines: output})
+}
+
+func (s *Server) getStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.backend.Stats()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// decode reads a JSON request body into v
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid JSON body: %v", errBadRequest, err)
+	}
+	return nil
+}
+
+// writeResult answers a request without a response body
+func writeResult(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeError answers with the Error body for err and the status its cause
+// calls for
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), NewError(err))
+}
+
+// statusFor returns the HTTP status err is answered with
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, errs.ErrGoblinNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrWorktreeExists), errors.Is(err, errs.ErrPathLocked):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrProtectedBranch), errors.Is(err, errs.ErrFrozen):
+		return http.StatusForbidden
+	case errors.Is(err, errs.ErrAgentNotInstalled), errors.Is(err, errs.ErrTmuxUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+// fakeBackend keeps goblins in memory
+type fakeBackend struct {
+	goblins map[string]*Goblin
+	tasks   map[string]string
+	lines   int
+}
+
+func (f *fakeBackend) get(name string) (*Goblin, error) {
+	g, ok := f.goblins[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, name)
+	}
+	return g, nil
+}
+
+func (f *fakeBackend) ListGoblins() ([]Goblin, error) {
+	var out []Goblin
+	for _, g := range f.goblins {
+		out = append(out, *g)
+	}
+	return out, nil
+}
+
+func (f *fakeBackend) GetGoblin(name string) (*Goblin, error) { return f.get(name) }
+
+func (f *fakeBackend) Spawn(req SpawnRequest) (*Goblin, error) {
+	if _, ok := f.goblins[req.Name]; ok {
+		return nil, fmt.Errorf("%w: %s", errs.ErrWorktreeExists, req.Name)
+	}
+	g := &Goblin{ID: "id-" + req.Name, Name: req.Name, Agent: req.Agent, ProjectPath: req.ProjectPath, Status: "running"}
+	f.goblins[req.Name] = g
+	if req.Task != "" {
+		f.tasks[req.Name] = req.Task
+	}
+	return g, nil
+}
+
+func (f *fakeBackend) Stop(name string) error {
+	g, err := f.get(name)
+	if err != nil {
+		return err
+	}
+	g.Status = "stopped"
+	return nil
+}
+
+func (f *fakeBackend) Kill(name string) error {
+	if _, err := f.get(name); err != nil {
+		return err
+	}
+	delete(f.goblins, name)
+	return nil
+}
+
+func (f *fakeBackend) SendTask(name, task string) error {
+	if _, err := f.get(name); err != nil {
+		return err
+	}
+	f.tasks[name] = task
+	return nil
+}
+
+func (f *fakeBackend) Logs(name string, lines int) ([]string, error) {
+	if _, err := f.get(name); err != nil {
+		return nil, err
+	}
+	f.lines = lines
+	return []string{"$ make test", "ok"}, nil
+}
+
+func (f *fakeBackend) Stats() (*Stats, error) {
+	return &Stats{Total: len(f.goblins)}, nil
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{goblins: make(map[string]*Goblin), tasks: make(map[string]string)}
+}
+
+func TestServerWithClient(t *testing.T) {
+	backend := newFakeBackend()
+	srv := httptest.NewServer(NewServer(backend, nil))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+
+	g, err := client.Spawn(SpawnRequest{Name: "coder", Agent: "claude", ProjectPath: "/src/app", Task: "fix lint"})
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	if g.ID != "id-coder" || backend.tasks["coder"] != "fix lint" {
+		t.Errorf("Expected coder spawned with its task, got %+v, %v", g, backend.tasks)
+	}
+
+	goblins, err := client.ListGoblins()
+	if err != nil || len(goblins) != 1 || goblins[0].Name != "coder" {
+		t.Errorf("Expected coder listed, got %v, %v", goblins, err)
+	}
+
+	if err := client.SendTask("coder", "write tests"); err != nil {
+		t.Fatalf("SendTask failed: %v", err)
+	}
+	if backend.tasks["coder"] != "write tests" {
+		t.Errorf("Expected the task sent, got %q", backend.tasks["coder"])
+	}
+
+	lines, err := client.Logs("coder", 20)
+	if err != nil || len(lines) != 2 || backend.lines != 20 {
+		t.Errorf("Expected 2 lines asked for 20, got %v (%d), %v", lines, backend.lines, err)
+	}
+
+	if err := client.Stop("coder"); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if g, _ := client.GetGoblin("coder"); g == nil || g.Status != "stopped" {
+		t.Errorf("Expected coder stopped, got %+v", g)
+	}
+
+	if stats, err := client.Stats(); err != nil || stats.Total != 1 {
+		t.Errorf("Expected 1 goblin in stats, got %+v, %v", stats, err)
+	}
+
+	if err := client.Kill("coder"); err != nil {
+		t.Fatalf("Kill failed: %v", err)
+	}
+	_, err = client.GetGoblin("coder")
+	if !errors.Is(err, errs.ErrGoblinNotFound) {
+		t.Errorf("Expected a killed goblin not found, got %v", err)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	backend := newFakeBackend()
+	backend.goblins["coder"] = &Goblin{Name: "coder"}
+	srv := httptest.NewServer(NewServer(backend, nil))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"missing goblin", "POST", "/v1/goblins/ghost/stop", "", http.StatusNotFound},
+		{"name taken", "POST", "/v1/goblins", `{"name":"coder","agent":"claude","project_path":"/src"}`, http.StatusConflict},
+		{"no project", "POST", "/v1/goblins", `{"name":"tester"}`, http.StatusBadRequest},
+		{"bad json", "POST", "/v1/goblins/coder/task", `{`, http.StatusBadRequest},
+		{"empty task", "POST", "/v1/goblins/coder/task", `{"task":" "}`, http.StatusBadRequest},
+		{"bad lines", "GET", "/v1/goblins/coder/logs?lines=x", "", http.StatusBadRequest},
+		{"wrong method", "PUT", "/v1/goblins/coder", "", http.StatusMethodNotAllowed},
+		{"unknown route", "GET", "/v2/goblins", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+
+	// Logs ask for a bounded number of lines
+	resp, _ := http.Get(srv.URL + "/v1/goblins/coder/logs?lines=999999")
+	resp.Body.Close()
+	if backend.lines != maxLogLines {
+		t.Errorf("Expected lines capped at %d, got %d", maxLogLines, backend.lines)
+	}
+	resp, _ = http.Get(srv.URL + "/v1/goblins/coder/logs")
+	resp.Body.Close()
+	if backend.lines != defaultLogLines {
+		t.Errorf("Expected %d lines by default, got %d", defaultLogLines, backend.lines)
+	}
+}
+
+func TestServerTokens(t *testing.T) {
+	srv := httptest.NewServer(NewServer(newFakeBackend(), []string{"t0ken", "other"}))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+
+	if _, err := client.ListGoblins(); err == nil {
+		t.Error("Expected a request without a token refused")
+	}
+	client.Token = "wrong"
+	if _, err := client.ListGoblins(); err == nil {
+		t.Error("Expected a request with the wrong token refused")
+	}
+	client.Token = "other"
+	if _, err := client.ListGoblins(); err != nil {
+		t.Errorf("Expected any configured token accepted, got %v", err)
+	}
+
+	s := NewServer(newFakeBackend(), []string{"t0ken"})
+	if !s.Accepts("t0ken") || s.Accepts("") || s.Accepts("wrong") {
+		t.Error("Expected only the configured token accepted")
+	}
+	if !NewServer(newFakeBackend(), nil).Accepts("") {
+		t.Error("Expected a server without tokens to accept anything")
+	}
+}
+
+func TestServerMount(t *testing.T) {
+	s := NewServer(newFakeBackend(), []string{"t0ken"})
+	s.Mount("/webhooks/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/webhooks/github", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected the mounted handler served without a token, got %d", resp.StatusCode)
+	}
+
+	if _, err := NewClient(srv.URL).ListGoblins(); err == nil {
+		t.Error("Expected the API still to need a token")
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+// Goblin is the wire representation of a goblin
+type Goblin struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Agent        string    `json:"agent"`
+	Status       string    `json:"status"`
+	ProjectPath  string    `json:"project_path"`
+	WorktreePath string    `json:"worktree_path"`
+	Branch       string    `json:"branch"`
+	TmuxSession  string    `json:"tmux_session"`
+	TmuxSocket   string    `json:"tmux_socket,omitempty"`
+	Pinned       bool      `json:"pinned,omitempty"`
+	Squad        string    `json:"squad,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// SpawnRequest is the body for creating a goblin
+type SpawnRequest struct {
+	Name        string `json:"name"`
+	Agent       string `json:"agent"`
+	ProjectPath string `json:"project_path"`
+	Branch      string `json:"branch,omitempty"`
+	Task        string `json:"task,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	Squad       string `json:"squad,omitempty"`
+}
+
+// TaskRequest is the body for sending a task to a goblin
+type TaskRequest struct {
+	Task string `json:"task"`
+}
+
+// Logs is a goblin's recent output, oldest line first
+type Logs struct {
+	Lines []string `json:"lines"`
+}
+
+// Stats is the wire representation of aggregate goblin statistics
+type Stats struct {
+	Total     int `json:"total"`
+	Running   int `json:"running"`
+	Paused    int `json:"paused"`
+	Completed int `json:"completed"`
+}
+
+// Error is the body returned for any non-2xx response. Code names the
+// failure cause (e.g. goblin_not_found) when there is one, and Hint the
+// command that fixes it.
+type Error struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+	Hint  string `json:"hint,omitempty"`
+}
+
+// NewError builds the response body for err
+func NewError(err error) Error {
+	return Error{Error: err.Error(), Code: errs.Code(err), Hint: errs.Hint(err)}
+}
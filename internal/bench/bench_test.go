@@ -0,0 +1,130 @@
+package bench
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSuite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	os.WriteFile(path, []byte(`
+name: smoke
+tasks:
+  - name: fizzbuzz
+    prompt: "Write fizzbuzz in main.go"
+    test: "go run main.go"
+    timeout: 5m
+  - prompt: "Add a README"
+`), 0644)
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite failed: %v", err)
+	}
+
+	if suite.Project != "." {
+		t.Errorf("Expected default project '.', got '%s'", suite.Project)
+	}
+	if len(suite.Tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(suite.Tasks))
+	}
+	if suite.Tasks[0].Timeout != 5*time.Minute {
+		t.Errorf("Expected 5m timeout, got %s", suite.Tasks[0].Timeout)
+	}
+	if suite.Tasks[1].TestTimeout != defaultTestTimeout {
+		t.Errorf("Expected the default test timeout, got %s", suite.Tasks[1].TestTimeout)
+	}
+	if suite.Tasks[1].Name != "task-2" {
+		t.Errorf("Expected generated name 'task-2', got '%s'", suite.Tasks[1].Name)
+	}
+	if suite.Tasks[1].IdleTimeout != defaultIdleTimeout {
+		t.Errorf("Expected default idle timeout, got %s", suite.Tasks[1].IdleTimeout)
+	}
+}
+
+func TestLoadSuiteInvalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":     "name: empty\n",
+		"no prompt": "tasks:\n  - name: a\n",
+		"duplicate": "tasks:\n  - name: a\n    prompt: x\n  - name: a\n    prompt: y\n",
+	}
+
+	for name, content := range tests {
+		path := filepath.Join(t.TempDir(), "tasks.yaml")
+		os.WriteFile(path, []byte(content), 0644)
+		if _, err := LoadSuite(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	pass := Result{Tested: true, Passed: true, LinesChanged: 10, Duration: time.Minute, Timeout: 10 * time.Minute}
+	fail := Result{Tested: true, Passed: false, LinesChanged: 10, Duration: time.Minute, Timeout: 10 * time.Minute}
+	bigger := pass
+	bigger.LinesChanged = 1000
+	errored := Result{Error: "spawn failed", Passed: true}
+
+	if pass.Score() <= fail.Score() {
+		t.Error("Passing result should outscore failing result")
+	}
+	if pass.Score() <= bigger.Score() {
+		t.Error("Smaller diff should outscore larger diff")
+	}
+	if errored.Score() != 0 {
+		t.Errorf("Errored result should score 0, got %.1f", errored.Score())
+	}
+	if pass.Score() > 100 {
+		t.Errorf("Score should not exceed 100, got %.1f", pass.Score())
+	}
+}
+
+func TestRunTest(t *testing.T) {
+	dir := t.TempDir()
+	if passed, err := runTest(dir, "true", time.Minute); !passed || err != nil {
+		t.Errorf("Expected a passing test, got %v, %v", passed, err)
+	}
+	if passed, err := runTest(dir, "exit 1", time.Minute); passed || err != nil {
+		t.Errorf("Expected a failing test, got %v, %v", passed, err)
+	}
+
+	start := time.Now()
+	passed, err := runTest(dir, "sleep 30", 100*time.Millisecond)
+	if passed || err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected the test timed out, got %v, %v", passed, err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected the hung test killed, took %s", time.Since(start))
+	}
+}
+
+func TestSummarizeAndReport(t *testing.T) {
+	results := []Result{
+		{Agent: "codex", Task: "a", Tested: true, Passed: false, Timeout: time.Minute},
+		{Agent: "claude", Task: "a", Tested: true, Passed: true, Timeout: time.Minute},
+		{Agent: "claude", Task: "b", Error: "boom"},
+	}
+
+	summaries := Summarize(results)
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 summaries, got %d", len(summaries))
+	}
+	claude := summaries[0]
+	if claude.Agent != "claude" || claude.Passed != 1 || claude.Errors != 1 {
+		t.Errorf("Unexpected claude summary: %+v", claude)
+	}
+
+	var buf bytes.Buffer
+	WriteReport(&buf, &Suite{Name: "smoke"}, results)
+	report := buf.String()
+
+	for _, want := range []string{"# smoke", "| 1 | claude |", "| a | codex | fail |", "**claude / b**: boom"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Report missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// AgentSummary aggregates results for one agent across a suite
+type AgentSummary struct {
+	Agent     string
+	Runs      int
+	Passed    int
+	Failed    int
+	Errors    int
+	AvgScore  float64
+	TotalTime time.Duration
+}
+
+// Summarize aggregates results per agent, best average score first
+func Summarize(results []Result) []AgentSummary {
+	byAgent := make(map[string]*AgentSummary)
+	var order []string
+
+	for _, r := range results {
+		s, ok := byAgent[r.Agent]
+		if !ok {
+			s = &AgentSummary{Agent: r.Agent}
+			byAgent[r.Agent] = s
+			order = append(order, r.Agent)
+		}
+
+		s.Runs++
+		s.AvgScore += r.Score()
+		s.TotalTime += r.Duration
+		switch {
+		case r.Error != "":
+			s.Errors++
+		case r.Passed:
+			s.Passed++
+		case r.Tested:
+			s.Failed++
+		}
+	}
+
+	summaries := make([]AgentSummary, 0, len(order))
+	for _, name := range order {
+		s := byAgent[name]
+		s.AvgScore /= float64(s.Runs)
+		summaries = append(summaries, *s)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].AvgScore > summaries[j].AvgScore
+	})
+
+	return summaries
+}
+
+// WriteReport writes a Markdown comparison report
+func WriteReport(w io.Writer, suite *Suite, results []Result) {
+	title := suite.Name
+	if title == "" {
+		title = "Benchmark"
+	}
+
+	fmt.Fprintf(w, "# %s\n\n", title)
+
+	fmt.Fprintln(w, "## Summary")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Rank | Agent | Avg Score | Passed | Failed | Errors | Total Time |")
+	fmt.Fprintln(w, "|------|-------|-----------|--------|--------|--------|------------|")
+	for i, s := range Summarize(results) {
+		fmt.Fprintf(w, "| %d | %s | %.1f | %d | %d | %d | %s |\n",
+			i+1, s.Agent, s.AvgScore, s.Passed, s.Failed, s.Errors, s.TotalTime.Round(time.Second))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Results")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Task | Agent | Tests | Files | Lines | Time | Score |")
+	fmt.Fprintln(w, "|------|-------|-------|-------|-------|------|-------|")
+	for _, r := range results {
+		tests := "-"
+		switch {
+		case r.Error != "":
+			tests = "error"
+		case r.Passed:
+			tests = "pass"
+		case r.Tested:
+			tests = "fail"
+		}
+
+		elapsed := r.Duration.Round(time.Second).String()
+		if r.TimedOut {
+			elapsed += " (timeout)"
+		}
+
+		fmt.Fprintf(w, "| %s | %s | %s | %d | %d | %s | %.1f |\n",
+			r.Task, r.Agent, tests, r.FilesChanged, r.LinesChanged, elapsed, r.Score())
+	}
+
+	var errored []Result
+	for _, r := range results {
+		if r.Error != "" {
+			errored = append(errored, r)
+		}
+	}
+	if len(errored) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Errors")
+		fmt.Fprintln(w)
+		for _, r := range errored {
+			fmt.Fprintf(w, "- **%s / %s**: %s\n", r.Agent, r.Task, r.Error)
+		}
+	}
+}
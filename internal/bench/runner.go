@@ -0,0 +1,205 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// Result is the outcome of one task run by one agent
+type Result struct {
+	Agent        string        `json:"agent"`
+	Task         string        `json:"task"`
+	Tested       bool          `json:"tested"`
+	Passed       bool          `json:"passed"`
+	TimedOut     bool          `json:"timed_out"`
+	FilesChanged int           `json:"files_changed"`
+	LinesChanged int           `json:"lines_changed"`
+	Duration     time.Duration `json:"duration"`
+	Timeout      time.Duration `json:"timeout"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// Score ranks a result out of 100: passing tests dominate (60), then
+// smaller diffs (20) and faster completion (20)
+func (r Result) Score() float64 {
+	if r.Error != "" {
+		return 0
+	}
+
+	score := 0.0
+	if r.Passed {
+		score += 60
+	}
+
+	score += 20 / (1 + float64(r.LinesChanged)/200)
+
+	if r.Timeout > 0 {
+		frac := float64(r.Duration) / float64(r.Timeout)
+		if frac > 1 {
+			frac = 1
+		}
+		score += 20 * (1 - frac)
+	}
+
+	return score
+}
+
+// Runner executes suites against agents in isolated goblins
+type Runner struct {
+	coord        *coordinator.Coordinator
+	registry     *agents.Registry
+	log          *logging.Logger
+	pollInterval time.Duration
+}
+
+// NewRunner creates a new benchmark runner
+func NewRunner(coord *coordinator.Coordinator, log *logging.Logger) *Runner {
+	return &Runner{
+		coord:        coord,
+		registry:     agents.NewRegistry(),
+		log:          log,
+		pollInterval: 2 * time.Second,
+	}
+}
+
+// Run executes every task in the suite against every agent, sequentially,
+// so agents do not compete for CPU and skew timings. Outside working hours
+// the next run waits for the window to open.
+func (r *Runner) Run(suite *Suite, projectPath string, agentNames []string) ([]Result, error) {
+	var selected []*agents.Agent
+	for _, name := range agentNames {
+		agent := r.registry.Get(name)
+		if agent == nil {
+			return nil, fmt.Errorf("unknown agent: %s", name)
+		}
+		selected = append(selected, agent)
+	}
+
+	var results []Result
+	for _, task := range suite.Tasks {
+		for _, agent := range selected {
+			work := fmt.Sprintf("bench task %s on %s", task.Name, agent.Name)
+			if err := r.coord.WaitForWorkingHours(context.Background(), "", "bench", work); err != nil {
+				return results, err
+			}
+			if r.log != nil {
+				r.log.Info("Running benchmark task",
+					logging.String("agent", agent.Name),
+					logging.String("task", task.Name))
+			}
+			results = append(results, r.runOne(projectPath, agent, task))
+		}
+	}
+
+	return results, nil
+}
+
+// runOne spawns a throwaway goblin, sends the task, waits for the agent to
+// go quiet, then measures its branch and runs the task's test command
+func (r *Runner) runOne(projectPath string, agent *agents.Agent, task Task) Result {
+	result := Result{Agent: agent.Name, Task: task.Name, Timeout: task.Timeout}
+
+	name := fmt.Sprintf("bench-%s-%s-%s", agent.Name, task.Name,
+		strconv.FormatInt(time.Now().UnixNano()%1e6, 36))
+	branch := "gforge/" + name
+
+	goblin, err := r.coord.Spawn(coordinator.SpawnOptions{
+		Name:        name,
+		Agent:       agent,
+		ProjectPath: projectPath,
+		Branch:      branch,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer func() {
+		if err := r.coord.Kill(goblin.Name); err != nil && r.log != nil {
+			r.log.Warn("Failed to kill benchmark goblin",
+				logging.String("goblin", goblin.Name),
+				logging.Err(err))
+		}
+		output, err := trace.Retry(trace.DefaultBackoff, r.log, func() *trace.Cmd {
+			return trace.Command("git", "-C", projectPath, "branch", "-D", branch)
+		})
+		if err != nil && r.log != nil {
+			r.log.Warn("Failed to delete benchmark branch",
+				logging.String("branch", branch),
+				logging.String("output", strings.TrimSpace(string(output))),
+				logging.Err(err))
+		}
+	}()
+
+	start := time.Now()
+	if err := r.coord.SendTask(goblin.Name, task.Prompt); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	mgr := tmux.NewManager(tmux.Config{SocketName: r.coord.Socket(goblin)})
+	result.TimedOut = !mgr.WaitIdle(goblin.TmuxSession, task.IdleTimeout, task.Timeout, r.pollInterval)
+	result.Duration = time.Since(start)
+
+	files, lines, err := r.diffStat(goblin)
+	if err != nil && r.log != nil {
+		r.log.Warn("Failed to measure benchmark diff",
+			logging.String("goblin", goblin.Name),
+			logging.Err(err))
+	}
+	result.FilesChanged, result.LinesChanged = files, lines
+
+	if task.Test != "" {
+		result.Tested = true
+		result.Passed, err = runTest(goblin.WorktreePath, task.Test, task.TestTimeout)
+		if err != nil && r.log != nil {
+			r.log.Warn("Benchmark test did not finish",
+				logging.String("goblin", goblin.Name),
+				logging.String("test", task.Test),
+				logging.Err(err))
+		}
+	}
+
+	return result
+}
+
+// diffStat counts the files and lines a goblin's branch changes from
+// where it started, committing whatever the agent left uncommitted first
+// so that committed and uncommitted work count alike
+func (r *Runner) diffStat(g *coordinator.Goblin) (files, lines int, err error) {
+	if _, err := r.coord.CommitWork(g, "bench: uncommitted work"); err != nil {
+		return 0, 0, err
+	}
+	stat, err := workspace.BranchDiffStat(g.WorktreePath, g.BaseRef, r.coord.DiffIgnore(g))
+	if err != nil {
+		return 0, 0, err
+	}
+	return stat.Files, stat.Insertions + stat.Deletions, nil
+}
+
+// runTest runs a task's test command in dir, reporting whether it passed.
+// A command still running after timeout is killed and fails, with an
+// error saying so.
+func runTest(dir, command string, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := trace.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	// Children the shell left holding its output mustn't outlast the kill
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return false, fmt.Errorf("timed out after %s", timeout)
+	}
+	return err == nil, nil
+}
@@ -0,0 +1,80 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTimeout     = 15 * time.Minute
+	defaultIdleTimeout = 60 * time.Second
+	defaultTestTimeout = 10 * time.Minute
+)
+
+// Suite is a set of benchmark tasks run against one project
+type Suite struct {
+	Name    string `yaml:"name"`
+	Project string `yaml:"project"`
+	Tasks   []Task `yaml:"tasks"`
+}
+
+// Task is a single benchmark task
+type Task struct {
+	Name        string        `yaml:"name"`
+	Prompt      string        `yaml:"prompt"`
+	Test        string        `yaml:"test"`         // Shell command, exit 0 = pass
+	Timeout     time.Duration `yaml:"timeout"`      // Hard limit for the agent
+	IdleTimeout time.Duration `yaml:"idle_timeout"` // Quiet period that counts as done
+	TestTimeout time.Duration `yaml:"test_timeout"` // Hard limit for the test command
+}
+
+// LoadSuite reads and validates a suite file
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite: %w", err)
+	}
+
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("failed to parse suite: %w", err)
+	}
+
+	if len(suite.Tasks) == 0 {
+		return nil, fmt.Errorf("suite %s has no tasks", path)
+	}
+
+	seen := make(map[string]bool)
+	for i := range suite.Tasks {
+		task := &suite.Tasks[i]
+		if task.Name == "" {
+			task.Name = fmt.Sprintf("task-%d", i+1)
+		}
+		if seen[task.Name] {
+			return nil, fmt.Errorf("duplicate task name: %s", task.Name)
+		}
+		seen[task.Name] = true
+
+		if task.Prompt == "" {
+			return nil, fmt.Errorf("task %s has no prompt", task.Name)
+		}
+		if task.Timeout == 0 {
+			task.Timeout = defaultTimeout
+		}
+		if task.IdleTimeout == 0 {
+			task.IdleTimeout = defaultIdleTimeout
+		}
+		if task.TestTimeout == 0 {
+			task.TestTimeout = defaultTestTimeout
+		}
+	}
+
+	if suite.Project == "" {
+		suite.Project = "."
+	}
+
+	return &suite, nil
+}
@@ -0,0 +1,360 @@
+// Package calendar reads iCalendar (RFC 5545) feeds for the windows, such
+// as release freezes and maintenance, when goblins' work must not land.
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a calendar event, repeating when it has a rule
+type Event struct {
+	Summary    string
+	Categories []string
+	Start      time.Time
+	End        time.Time
+	rule       *rule
+	exdates    []time.Time // Occurrences the rule skips
+}
+
+// Window is one occurrence of an event
+type Window struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// rule is the part of an RRULE gforge follows: daily or weekly repeats,
+// on given weekdays, for a count or until a time
+type rule struct {
+	freq     string // DAILY or WEEKLY
+	interval int
+	count    int       // 0 for no limit
+	until    time.Time // Zero for no limit
+	byDay    []time.Weekday
+}
+
+// Fetch reads a calendar from an http(s) or webcal URL, or a local file
+func Fetch(ctx context.Context, source string) ([]Event, error) {
+	data, err := Read(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(data))
+}
+
+// Read returns the iCalendar document at an http(s) or webcal URL, or in
+// a local file, unparsed
+func Read(ctx context.Context, source string) ([]byte, error) {
+	// webcal:// feeds are plain https
+	if rest, ok := strings.CutPrefix(source, "webcal://"); ok {
+		source = "https://" + rest
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(strings.TrimPrefix(source, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open calendar: %w", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calendar: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calendar: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch calendar: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch calendar: %w", err)
+	}
+	return data, nil
+}
+
+// Parse reads the events of an iCalendar document. Events it can't make
+// sense of are skipped rather than failing the whole calendar.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events   []Event
+		cur      *Event
+		duration time.Duration
+		hasEnd   bool
+		allDay   bool
+		bad      bool
+	)
+	for _, line := range lines {
+		name, params, value := property(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			cur, duration, hasEnd, allDay, bad = &Event{}, 0, false, false, false
+		case cur == nil:
+			continue
+		case name == "END" && value == "VEVENT":
+			if !bad && !cur.Start.IsZero() {
+				if !hasEnd {
+					cur.End = cur.Start.Add(duration)
+					if duration == 0 && allDay {
+						cur.End = cur.Start.AddDate(0, 0, 1)
+					}
+				}
+				events = append(events, *cur)
+			}
+			cur = nil
+		case name == "SUMMARY":
+			cur.Summary = unescape(value)
+		case name == "CATEGORIES":
+			for _, c := range strings.Split(value, ",") {
+				cur.Categories = append(cur.Categories, unescape(strings.TrimSpace(c)))
+			}
+		case name == "DTSTART":
+			cur.Start, allDay, err = parseTime(value, params)
+			bad = bad || err != nil
+		case name == "DTEND":
+			cur.End, _, err = parseTime(value, params)
+			hasEnd = err == nil
+			bad = bad || err != nil
+		case name == "DURATION":
+			duration, err = parseDuration(value)
+			bad = bad || err != nil
+		case name == "RRULE":
+			// Rules beyond daily and weekly leave just the first occurrence
+			cur.rule, _ = parseRule(value)
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if t, _, err := parseTime(strings.TrimSpace(v), params); err == nil {
+					cur.exdates = append(cur.exdates, t)
+				}
+			}
+		}
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// property splits NAME;PARAM=V;...:VALUE
+func property(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseTime reads a DATE or DATE-TIME value: UTC with a Z, in TZID, or
+// local time. It reports whether the value is a whole day.
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	loc := time.Local
+	if tz := params["TZID"]; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+var durationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration reads an iCalendar duration such as PT2H or P1DT12H
+func parseDuration(value string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if n, err := strconv.Atoi(m[i+2]); err == nil {
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRule reads a daily or weekly RRULE
+func parseRule(value string) (*rule, error) {
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL: %s", v)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT: %s", v)
+			}
+			r.count = n
+		case "UNTIL":
+			t, _, err := parseTime(v, nil)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL: %s", v)
+			}
+			r.until = t
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					// Ordinals such as 1MO need monthly rules
+					return nil, fmt.Errorf("unsupported BYDAY: %s", d)
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		default:
+			if k != "WKST" {
+				return nil, fmt.Errorf("unsupported RRULE part: %s", k)
+			}
+		}
+	}
+	if r.freq != "DAILY" && r.freq != "WEEKLY" {
+		return nil, fmt.Errorf("unsupported FREQ: %s", r.freq)
+	}
+	return r, nil
+}
+
+// maxOccurrences bounds how far an unlimited rule is followed
+const maxOccurrences = 5000
+
+// Occurrences returns the windows of the event overlapping [from, to)
+func (e Event) Occurrences(from, to time.Time) []Window {
+	length := e.End.Sub(e.Start)
+	var windows []Window
+	add := func(start time.Time) {
+		for _, ex := range e.exdates {
+			if ex.Equal(start) {
+				return
+			}
+		}
+		end := start.Add(length)
+		if end.After(from) && start.Before(to) {
+			windows = append(windows, Window{Summary: e.Summary, Start: start, End: end})
+		}
+	}
+
+	if e.rule == nil {
+		add(e.Start)
+		return windows
+	}
+
+	r := e.rule
+	n := 0
+	for i := 0; n < maxOccurrences; i += r.interval {
+		var starts []time.Time
+		if r.freq == "DAILY" {
+			starts = []time.Time{e.Start.AddDate(0, 0, i)}
+		} else {
+			week := e.Start.AddDate(0, 0, 7*i)
+			if len(r.byDay) == 0 {
+				starts = []time.Time{week}
+			}
+			for _, wd := range r.byDay {
+				offset := (int(wd) - int(e.Start.Weekday()) + 7) % 7
+				starts = append(starts, week.AddDate(0, 0, offset))
+			}
+			sort.Slice(starts, func(a, b int) bool { return starts[a].Before(starts[b]) })
+		}
+
+		for _, start := range starts {
+			if (r.count > 0 && n >= r.count) || (!r.until.IsZero() && start.After(r.until)) || !start.Before(to) {
+				return windows
+			}
+			n++
+			add(start)
+		}
+	}
+	return windows
+}
+
+// HasKeyword reports whether any keyword appears in the event's summary or
+// categories, ignoring case
+func (e Event) HasKeyword(keywords []string) bool {
+	text := strings.ToLower(e.Summary + " " + strings.Join(e.Categories, " "))
+	for _, k := range keywords {
+		if k != "" && strings.Contains(text, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Windows returns the occurrences of the events with any of the keywords
+// overlapping [from, to), by start time
+func Windows(events []Event, keywords []string, from, to time.Time) []Window {
+	var windows []Window
+	for _, e := range events {
+		if e.HasKeyword(keywords) {
+			windows = append(windows, e.Occurrences(from, to)...)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Start.Before(windows[j].Start) })
+	return windows
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,131 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Release freeze\\, v2\r\n" +
+	"DTSTART:20261020T090000Z\r\n" +
+	"DTEND:20261022T090000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Database\r\n" +
+	"  window\r\n" +
+	"CATEGORIES:Maintenance,Ops\r\n" +
+	"DTSTART;TZID=UTC:20261006T220000\r\n" +
+	"DURATION:PT2H\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=6\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Team lunch\r\n" +
+	"DTSTART;VALUE=DATE:20261021\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Broken freeze\r\n" +
+	"DTSTART:not-a-time\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(testCalendar))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events with the broken one skipped, got %d", len(events))
+	}
+
+	if events[0].Summary != "Release freeze, v2" || events[0].End.Sub(events[0].Start) != 48*time.Hour {
+		t.Errorf("Unexpected first event %+v", events[0])
+	}
+	if events[1].Summary != "Database window" || len(events[1].Categories) != 2 || events[1].End.Sub(events[1].Start) != 2*time.Hour {
+		t.Errorf("Expected the folded summary and DURATION read, got %+v", events[1])
+	}
+	if events[2].End.Sub(events[2].Start) != 24*time.Hour {
+		t.Errorf("Expected an all-day event to last a day, got %+v", events[2])
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	events, _ := Parse(strings.NewReader(testCalendar))
+	maint := events[1]
+
+	// Tuesdays and Thursdays from Oct 6, six times: 6, 8, 13, 15, 20, 22
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	windows := maint.Occurrences(from, to)
+	if len(windows) != 6 {
+		t.Fatalf("Expected 6 occurrences, got %d: %+v", len(windows), windows)
+	}
+	if got := windows[1].Start; !got.Equal(time.Date(2026, 10, 8, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the second occurrence on Thursday, got %v", got)
+	}
+
+	// An occurrence still running at from counts
+	late := time.Date(2026, 10, 13, 23, 0, 0, 0, time.UTC)
+	if windows := maint.Occurrences(late, late.Add(time.Minute)); len(windows) != 1 {
+		t.Errorf("Expected the running occurrence, got %+v", windows)
+	}
+
+	// EXDATE drops occurrences, which still count towards COUNT
+	excluded := strings.Replace(testCalendar, "RRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=6\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=6\r\nEXDATE:20261008T220000Z,20261015T220000Z\r\nEXDATE;TZID=UTC:20261022T220000\r\n", 1)
+	events, _ = Parse(strings.NewReader(excluded))
+	windows = events[1].Occurrences(from, to)
+	if len(windows) != 3 {
+		t.Fatalf("Expected 3 occurrences left, got %d: %+v", len(windows), windows)
+	}
+	for _, w := range windows {
+		if w.Start.Weekday() != time.Tuesday {
+			t.Errorf("Expected only the Tuesdays left, got %v", w.Start)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	events, _ := Parse(strings.NewReader(testCalendar))
+	keywords := []string{"freeze", "maintenance"}
+
+	at := time.Date(2026, 10, 21, 12, 0, 0, 0, time.UTC)
+	windows := Windows(events, keywords, at, at.Add(time.Nanosecond))
+	if len(windows) != 1 || windows[0].Summary != "Release freeze, v2" {
+		t.Errorf("Expected only the release freeze active, got %+v", windows)
+	}
+
+	at = time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC)
+	windows = Windows(events, keywords, at, at.Add(time.Nanosecond))
+	if len(windows) != 1 || windows[0].Summary != "Database window" {
+		t.Errorf("Expected the maintenance matched by category, got %+v", windows)
+	}
+
+	if windows := Windows(events, nil, at, at.Add(time.Hour)); len(windows) != 0 {
+		t.Errorf("Expected no windows without keywords, got %+v", windows)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cal.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testCalendar))
+	}))
+	defer srv.Close()
+
+	events, err := Fetch(context.Background(), srv.URL+"/cal.ics")
+	if err != nil || len(events) != 3 {
+		t.Errorf("Expected 3 events fetched, got %d, %v", len(events), err)
+	}
+	if _, err := Fetch(context.Background(), srv.URL+"/missing.ics"); err == nil {
+		t.Error("Expected an error for a missing calendar")
+	}
+}
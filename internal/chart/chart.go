@@ -0,0 +1,82 @@
+// Package chart draws small text charts of values over time.
+package chart
+
+import (
+	"strings"
+	"time"
+)
+
+var ticks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws one tick per value, scaled to the largest. Negative
+// values mark gaps with no data and draw as spaces.
+func Sparkline(values []int) string {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case v < 0:
+			b.WriteRune(' ')
+		case max == 0:
+			b.WriteRune(ticks[0])
+		default:
+			b.WriteRune(ticks[v*(len(ticks)-1)/max])
+		}
+	}
+	return b.String()
+}
+
+// Sample is a value observed at a time
+type Sample struct {
+	At    time.Time
+	Value int
+}
+
+// bucket returns which of n equal buckets between start and end t falls
+// in, or -1 if outside
+func bucket(t, start, end time.Time, n int) int {
+	if t.Before(start) || !t.Before(end) || n <= 0 {
+		return -1
+	}
+	return int(int64(t.Sub(start)) * int64(n) / int64(end.Sub(start)))
+}
+
+// Peak splits start..end into n buckets holding the largest sample in
+// each. Buckets without samples carry the previous value forward, as a
+// count stays put between snapshots, or are -1 before the first sample.
+func Peak(samples []Sample, start, end time.Time, n int) []int {
+	values := make([]int, n)
+	seen := make([]bool, n)
+	for _, s := range samples {
+		if i := bucket(s.At, start, end, n); i >= 0 && (!seen[i] || s.Value > values[i]) {
+			values[i], seen[i] = s.Value, true
+		}
+	}
+
+	last := -1
+	for i := range values {
+		if seen[i] {
+			last = values[i]
+		} else {
+			values[i] = last
+		}
+	}
+	return values
+}
+
+// Count splits start..end into n buckets counting the times in each
+func Count(times []time.Time, start, end time.Time, n int) []int {
+	values := make([]int, n)
+	for _, t := range times {
+		if i := bucket(t, start, end, n); i >= 0 {
+			values[i]++
+		}
+	}
+	return values
+}
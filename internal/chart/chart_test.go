@@ -0,0 +1,48 @@
+package chart
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 1, 2, 3, 4, 5, 6, 7}); got != "▁▂▃▄▅▆▇█" {
+		t.Errorf("Unexpected sparkline %q", got)
+	}
+	if got := Sparkline([]int{-1, 0, 0}); got != " ▁▁" {
+		t.Errorf("Expected gaps as spaces and flat zeros, got %q", got)
+	}
+	if got := Sparkline([]int{10, 5, 0}); got != "█▄▁" {
+		t.Errorf("Unexpected scaled sparkline %q", got)
+	}
+}
+
+func TestPeak(t *testing.T) {
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	samples := []Sample{
+		{start.Add(70 * time.Minute), 2},
+		{start.Add(80 * time.Minute), 5},
+		{start.Add(100 * time.Minute), 3},
+		{start.Add(5 * time.Hour), 9}, // Outside the window
+	}
+
+	got := Peak(samples, start, end, 4)
+	want := []int{-1, 5, 5, 5}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Peak = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	times := []time.Time{start, start.Add(time.Minute), start.Add(90 * time.Minute), end}
+
+	got := Count(times, start, end, 2)
+	if got[0] != 2 || got[1] != 1 {
+		t.Errorf("Count = %v, want [2 1]", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -11,29 +13,87 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	General      GeneralConfig      `mapstructure:"general" yaml:"general"`
-	Tmux         TmuxConfig         `mapstructure:"tmux" yaml:"tmux"`
-	Git          GitConfig          `mapstructure:"git" yaml:"git"`
-	Voice        VoiceConfig        `mapstructure:"voice" yaml:"voice"`
-	Integrations IntegrationsConfig `mapstructure:"integrations" yaml:"integrations"`
+	General       GeneralConfig       `mapstructure:"general" yaml:"general"`
+	Tmux          TmuxConfig          `mapstructure:"tmux" yaml:"tmux"`
+	Git           GitConfig           `mapstructure:"git" yaml:"git"`
+	Voice         VoiceConfig         `mapstructure:"voice" yaml:"voice"`
+	Integrations  IntegrationsConfig  `mapstructure:"integrations" yaml:"integrations"`
+	Notifications NotificationsConfig `mapstructure:"notifications" yaml:"notifications"`
+	Digest        DigestConfig        `mapstructure:"digest" yaml:"digest"`
+	Summarizer    SummarizerConfig    `mapstructure:"summarizer" yaml:"summarizer"`
+	RateLimits    RateLimitConfig     `mapstructure:"rate_limits" yaml:"rate_limits"`
+	Locks         LocksConfig         `mapstructure:"locks" yaml:"locks"`
+	Ollama        OllamaConfig        `mapstructure:"ollama" yaml:"ollama"`
+	Scheduler     SchedulerConfig     `mapstructure:"scheduler" yaml:"scheduler"`
+	WorkingHours  WorkingHoursConfig  `mapstructure:"working_hours" yaml:"working_hours"`
+	Stats         StatsConfig         `mapstructure:"stats" yaml:"stats"`
+	API           APIConfig           `mapstructure:"api" yaml:"api"`
+	Webhooks      WebhooksConfig      `mapstructure:"webhooks" yaml:"webhooks"`
+	Slack         SlackConfig         `mapstructure:"slack" yaml:"slack"`
+	UI            UIConfig            `mapstructure:"ui" yaml:"ui"`
+	Telemetry     TelemetryConfig     `mapstructure:"telemetry" yaml:"telemetry"`
+	Daemon        DaemonConfig        `mapstructure:"daemon" yaml:"daemon"`
+
+	// Pricing overrides token prices by agent name or provider
+	Pricing map[string]PriceConfig `mapstructure:"pricing" yaml:"pricing,omitempty"`
+
+	// Schedules are recurring goblin runs by name, spawned by gforge
+	// daemon alongside those added with gforge schedule add
+	Schedules map[string]ScheduleConfig `mapstructure:"schedules" yaml:"schedules,omitempty"`
 
 	// Computed paths
-	DatabasePath string `mapstructure:"-" yaml:"-"`
-	WorktreeBase string `mapstructure:"-" yaml:"-"`
-	ConfigPath   string `mapstructure:"-" yaml:"-"`
+	DatabasePath  string `mapstructure:"-" yaml:"-"`
+	WorktreeBase  string `mapstructure:"-" yaml:"-"`
+	ConfigPath    string `mapstructure:"-" yaml:"-"`
+	RecordingsDir string `mapstructure:"-" yaml:"-"`
+	ArchiveDir    string `mapstructure:"-" yaml:"-"`
+	ArtifactsDir  string `mapstructure:"-" yaml:"-"`
+	ContextsDir   string `mapstructure:"-" yaml:"-"`
+	AgentScanFile string `mapstructure:"-" yaml:"-"`
+	FreezeFile    string `mapstructure:"-" yaml:"-"`
+	StatCacheFile string `mapstructure:"-" yaml:"-"`
+	TraceFile     string `mapstructure:"-" yaml:"-"`
+	TelemetryDir  string `mapstructure:"-" yaml:"-"`
+	DaemonPIDFile string `mapstructure:"-" yaml:"-"`
 }
 
 type GeneralConfig struct {
 	DefaultAgent        string `mapstructure:"default_agent" yaml:"default_agent"`
 	WorktreeBase        string `mapstructure:"worktree_base" yaml:"worktree_base"`
+	WorktreeLayout      string `mapstructure:"worktree_layout" yaml:"worktree_layout"`
 	AutoCleanupDays     int    `mapstructure:"auto_cleanup_days" yaml:"auto_cleanup_days"`
 	MaxConcurrentAgents int    `mapstructure:"max_concurrent_agents" yaml:"max_concurrent_agents"`
+	DevEnv              string `mapstructure:"dev_env" yaml:"dev_env"`
+
+	// AgentScanTTL is how long `gforge status` reuses the last agent scan
+	AgentScanTTL time.Duration `mapstructure:"agent_scan_ttl" yaml:"agent_scan_ttl"`
+
+	// StatCacheTTL caps how long `gforge list --stat` reuses a worktree's
+	// changes when nothing it watches has moved
+	StatCacheTTL time.Duration `mapstructure:"stat_cache_ttl" yaml:"stat_cache_ttl"`
+
+	// MinFreeDiskMB is the free space, in MB, a spawn needs where its
+	// worktree goes; 0 skips the check
+	MinFreeDiskMB int `mapstructure:"min_free_disk_mb" yaml:"min_free_disk_mb"`
+
+	// ContextWindows overrides the context window, in tokens, of agents
+	// by name. Prompts larger than the window are truncated before sending.
+	ContextWindows map[string]int `mapstructure:"context_windows" yaml:"context_windows,omitempty"`
 }
 
 type TmuxConfig struct {
 	SocketName   string `mapstructure:"socket_name" yaml:"socket_name"`
 	DefaultShell string `mapstructure:"default_shell" yaml:"default_shell"`
 	HistoryLimit int    `mapstructure:"history_limit" yaml:"history_limit"`
+	Record       bool   `mapstructure:"record" yaml:"record"`
+
+	// SocketTemplate names a socket per goblin from {project}, {name} and
+	// {user}, e.g. "gforge-{project}"; empty uses SocketName for all
+	SocketTemplate string `mapstructure:"socket_template" yaml:"socket_template"`
+
+	// AttachMode is how `gforge attach` shows a session when run inside
+	// tmux: auto, switch, window, popup or nest
+	AttachMode string `mapstructure:"attach_mode" yaml:"attach_mode"`
 }
 
 type GitConfig struct {
@@ -41,21 +101,360 @@ type GitConfig struct {
 	BranchStyle  string `mapstructure:"branch_style" yaml:"branch_style"`
 	AutoFetch    bool   `mapstructure:"auto_fetch" yaml:"auto_fetch"`
 	AutoStash    bool   `mapstructure:"auto_stash" yaml:"auto_stash"`
+
+	// Submodules checks out submodules in new worktrees; SubmoduleDepth
+	// above zero makes shallow clones of them
+	Submodules     bool `mapstructure:"submodules" yaml:"submodules"`
+	SubmoduleDepth int  `mapstructure:"submodule_depth" yaml:"submodule_depth"`
+
+	// LFS installs Git LFS hooks and pulls LFS content in new worktrees of
+	// repositories that use it
+	LFS bool `mapstructure:"lfs" yaml:"lfs"`
+
+	// ProtectedBranches are branch names or globs gforge never creates,
+	// pushes to or merges into without an explicit override
+	ProtectedBranches []string `mapstructure:"protected_branches" yaml:"protected_branches"`
+
+	// PushRemote is where gforge push sends goblin branches (a fork, for
+	// contributors without push access) and PRRemote the repository pull
+	// requests are opened against
+	PushRemote string `mapstructure:"push_remote" yaml:"push_remote"`
+	PRRemote   string `mapstructure:"pr_remote" yaml:"pr_remote"`
+
+	// ScanSecrets checks a goblin's commits for credentials before
+	// gforge push sends them anywhere
+	ScanSecrets bool `mapstructure:"scan_secrets" yaml:"scan_secrets"`
+
+	// MaxDiffFiles and MaxDiffLines flag goblin branches that change more
+	// files, or add and remove more lines, than fit in one review (zero
+	// turns a limit off). DiffLimit is what gforge push does about it:
+	// warn or block.
+	MaxDiffFiles int    `mapstructure:"max_diff_files" yaml:"max_diff_files"`
+	MaxDiffLines int    `mapstructure:"max_diff_lines" yaml:"max_diff_lines"`
+	DiffLimit    string `mapstructure:"diff_limit" yaml:"diff_limit"`
+
+	// ConfirmDeps has gforge push stop for confirmation when a
+	// goblin's commits change dependency manifests or add licensed files
+	ConfirmDeps bool `mapstructure:"confirm_dependencies" yaml:"confirm_dependencies"`
+
+	// RequestReviews has gforge push --pr request reviews from the
+	// CODEOWNERS of the files a goblin's branch changes
+	RequestReviews bool `mapstructure:"request_reviews" yaml:"request_reviews"`
+
+	// RefuseDirty refuses to spawn from a project with uncommitted changes
+	// instead of warning, when auto_stash is off
+	RefuseDirty bool `mapstructure:"refuse_dirty" yaml:"refuse_dirty"`
+
+	// ShutdownPolicy decides what happens to uncommitted work when goblins
+	// are stopped by gforge shutdown: commit, stash or none
+	ShutdownPolicy string `mapstructure:"shutdown_policy" yaml:"shutdown_policy"`
+
+	// CopyMethod is how project files are copied into workspaces: auto
+	// (copy-on-write clones where supported), reflink or copy
+	CopyMethod string `mapstructure:"copy_method" yaml:"copy_method"`
+
+	// NonGit decides where goblins work on projects that are not git
+	// repositories: init (a copy with a throwaway repo), copy or direct
+	NonGit string `mapstructure:"non_git" yaml:"non_git"`
 }
 
 type VoiceConfig struct {
-	Enabled       bool   `mapstructure:"enabled" yaml:"enabled"`
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	// Model is a Whisper size (tiny ... large-v3) for local faster-whisper,
+	// or backend:model with backend local, whisper.cpp or openai
 	Model         string `mapstructure:"model" yaml:"model"`
 	Hotkey        string `mapstructure:"hotkey" yaml:"hotkey"`
 	Language      string `mapstructure:"language" yaml:"language"`
 	WakeWord      string `mapstructure:"wake_word" yaml:"wake_word"`
 	FeedbackSound bool   `mapstructure:"feedback_sound" yaml:"feedback_sound"`
+
+	// Spoken feedback, with voice and feedback_sound on. TTS picks the
+	// engine (auto, piper, say, espeak-ng, espeak, spd-say); piper speaks
+	// with PiperModel (.onnx). Announce lists the goblin events read aloud
+	// (task_complete, failure, approval, done).
+	TTS        string   `mapstructure:"tts" yaml:"tts"`
+	PiperModel string   `mapstructure:"piper_model" yaml:"piper_model"`
+	Announce   []string `mapstructure:"announce" yaml:"announce"`
+
+	Confirm VoiceConfirmConfig `mapstructure:"confirm" yaml:"confirm"`
+}
+
+// VoiceConfirmConfig sets what happens to each class of voice command:
+// read (list, status), change (spawn, task, push) and destructive (stop,
+// kill, merge). Each runs at once (run), waits up to Timeout for a spoken
+// or typed confirmation (confirm), or is refused (refuse).
+type VoiceConfirmConfig struct {
+	Read        string        `mapstructure:"read" yaml:"read"`
+	Change      string        `mapstructure:"change" yaml:"change"`
+	Destructive string        `mapstructure:"destructive" yaml:"destructive"`
+	Timeout     time.Duration `mapstructure:"timeout" yaml:"timeout"`
+}
+
+type NotificationsConfig struct {
+	Desktop     bool          `mapstructure:"desktop" yaml:"desktop"`
+	OnComplete  bool          `mapstructure:"on_complete" yaml:"on_complete"`
+	OnFailure   bool          `mapstructure:"on_failure" yaml:"on_failure"`
+	OnApproval  bool          `mapstructure:"on_approval" yaml:"on_approval"`
+	IdleTimeout time.Duration `mapstructure:"idle_timeout" yaml:"idle_timeout"`
+
+	// Chat transports, used once configured
+	Matrix   MatrixConfig   `mapstructure:"matrix" yaml:"matrix"`
+	Telegram TelegramConfig `mapstructure:"telegram" yaml:"telegram"`
+
+	// Routes sends an event type (task_complete, failure, approval, done) only
+	// to the transports listed (desktop, matrix, telegram, voice); event
+	// types not listed go to all of them
+	Routes map[string][]string `mapstructure:"routes" yaml:"routes,omitempty"`
+}
+
+// MatrixConfig posts notifications to a Matrix room
+type MatrixConfig struct {
+	Homeserver  string `mapstructure:"homeserver" yaml:"homeserver"` // e.g. https://matrix.org
+	RoomID      string `mapstructure:"room_id" yaml:"room_id"`       // e.g. !abc123:matrix.org
+	AccessToken string `mapstructure:"access_token" yaml:"access_token"`
+}
+
+// TelegramConfig sends notifications from a Telegram bot to a chat
+type TelegramConfig struct {
+	BotToken string `mapstructure:"bot_token" yaml:"bot_token"`
+	ChatID   string `mapstructure:"chat_id" yaml:"chat_id"`
+}
+
+type DigestConfig struct {
+	Enabled  bool       `mapstructure:"enabled" yaml:"enabled"`
+	Schedule string     `mapstructure:"schedule" yaml:"schedule"` // hourly, daily
+	Hour     int        `mapstructure:"hour" yaml:"hour"`         // Local hour for daily digests
+	From     string     `mapstructure:"from" yaml:"from"`
+	To       []string   `mapstructure:"to" yaml:"to"`
+	SMTP     SMTPConfig `mapstructure:"smtp" yaml:"smtp"`
+}
+
+// SummarizerConfig selects the model that condenses long goblin output
+type SummarizerConfig struct {
+	Backend   string `mapstructure:"backend" yaml:"backend"` // ollama or openai (any OpenAI-compatible API)
+	Model     string `mapstructure:"model" yaml:"model"`
+	URL       string `mapstructure:"url" yaml:"url"`         // Defaults to the backend's usual endpoint
+	APIKey    string `mapstructure:"api_key" yaml:"api_key"` // openai only; falls back to OPENAI_API_KEY
+	Lines     int    `mapstructure:"lines" yaml:"lines"`     // Pane history captured per summary
+	MaxTokens int    `mapstructure:"max_tokens" yaml:"max_tokens"`
+
+	// OnComplete summarizes a goblin's output as it is completed, before
+	// its session and output are gone
+	OnComplete bool `mapstructure:"on_complete" yaml:"on_complete"`
+}
+
+// RateLimitConfig caps how often gforge starts agents or sends them
+// tasks, by agent name or provider (anthropic, openai, google, ollama)
+type RateLimitConfig struct {
+	RequestsPerMinute map[string]int `mapstructure:"requests_per_minute" yaml:"requests_per_minute,omitempty"`
+	MaxWait           time.Duration  `mapstructure:"max_wait" yaml:"max_wait"` // Longest a request queues
+}
+
+// LocksConfig decides what happens to a task naming paths another goblin
+// has locked with gforge lock
+type LocksConfig struct {
+	// OnConflict is warn (send the task anyway) or queue (hold it until
+	// the locks are released)
+	OnConflict string        `mapstructure:"on_conflict" yaml:"on_conflict"`
+	MaxWait    time.Duration `mapstructure:"max_wait" yaml:"max_wait"` // Longest a task queues
+}
+
+// OllamaConfig controls the local model server used by ollama agents
+type OllamaConfig struct {
+	// ManageServer starts `ollama serve` when an ollama goblin is spawned
+	// and none is running, and stops it after the last one exits
+	ManageServer bool          `mapstructure:"manage_server" yaml:"manage_server"`
+	StartTimeout time.Duration `mapstructure:"start_timeout" yaml:"start_timeout"`
+
+	// MaxLocalGoblins caps concurrent local-model goblins; 0 sizes the cap
+	// from detected VRAM at VRAMPerGoblinMB each, and without a GPU leaves
+	// them unlimited. Extra spawns queue (see SchedulerConfig).
+	MaxLocalGoblins int `mapstructure:"max_local_goblins" yaml:"max_local_goblins"`
+	VRAMPerGoblinMB int `mapstructure:"vram_per_goblin_mb" yaml:"vram_per_goblin_mb"`
+}
+
+// SchedulerConfig controls spawns queued behind general.max_concurrent_agents
+// or ollama.max_local_goblins. Queued spawns are admitted by priority.
+type SchedulerConfig struct {
+	// Preempt pauses the newest lower-priority goblin to admit a queued
+	// higher-priority spawn; it resumes when a slot frees up
+	Preempt      bool          `mapstructure:"preempt" yaml:"preempt"`
+	QueueTimeout time.Duration `mapstructure:"queue_timeout" yaml:"queue_timeout"`
+
+	// FreezeCalendar is an iCal URL or file; while one of its events whose
+	// summary or categories contain a FreezeKeywords entry is on, pushes
+	// and pull requests are refused
+	FreezeCalendar string   `mapstructure:"freeze_calendar" yaml:"freeze_calendar"`
+	FreezeKeywords []string `mapstructure:"freeze_keywords" yaml:"freeze_keywords"`
+}
+
+// WorkingHoursConfig limits when autonomous loops (benchmark suites,
+// replays) may start work; outside the window they wait
+type WorkingHoursConfig struct {
+	Enabled  bool     `mapstructure:"enabled" yaml:"enabled"`
+	Days     []string `mapstructure:"days" yaml:"days"`   // mon..sun; default weekdays
+	Start    string   `mapstructure:"start" yaml:"start"` // HH:MM
+	End      string   `mapstructure:"end" yaml:"end"`     // HH:MM; before start spans midnight
+	Timezone string   `mapstructure:"timezone" yaml:"timezone"`
+}
+
+// StatsConfig controls the goblin count history behind gforge stats
+type StatsConfig struct {
+	// SnapshotInterval is the least time between snapshots, which any
+	// gforge command takes when due; 0 disables them
+	SnapshotInterval time.Duration `mapstructure:"snapshot_interval" yaml:"snapshot_interval"`
+}
+
+// UIConfig controls how gforge colors its output
+type UIConfig struct {
+	// Theme is default, light or high-contrast
+	Theme string `mapstructure:"theme" yaml:"theme"`
+	// NoColor turns color off, like --no-color or NO_COLOR
+	NoColor bool `mapstructure:"no_color" yaml:"no_color"`
+}
+
+// TelemetryConfig says where opt-in usage events go. Whether they are
+// collected at all is set with gforge telemetry on|off, not here.
+type TelemetryConfig struct {
+	// Endpoint receives spooled events as a JSON array; events stay in the
+	// local spool while it is empty
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint"`
+	// SendInterval is the least time between sends
+	SendInterval time.Duration `mapstructure:"send_interval" yaml:"send_interval"`
+}
+
+// DaemonConfig paces the supervisor run by gforge daemon
+type DaemonConfig struct {
+	// Interval is how often goblins' tmux sessions are checked
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+	// CleanupInterval is how often goblins idle for
+	// general.auto_cleanup_days are archived
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval" yaml:"cleanup_interval"`
+	// OverlapInterval is how often goblins sharing a project are compared
+	// for files they both change; zero turns the check off
+	OverlapInterval time.Duration `mapstructure:"overlap_interval" yaml:"overlap_interval"`
+	// MergeInterval is how often the pull requests gforge push opened are
+	// checked for a merge; zero turns the check off
+	MergeInterval time.Duration `mapstructure:"merge_interval" yaml:"merge_interval"`
+}
+
+// APIConfig sets up the REST API served by gforge serve
+type APIConfig struct {
+	Listen string `mapstructure:"listen" yaml:"listen"`
+
+	// Tokens are the bearer tokens clients must send; falls back to
+	// GFORGE_API_TOKEN. Without any, only loopback addresses are served.
+	Tokens []string `mapstructure:"tokens" yaml:"tokens,omitempty"`
+}
+
+// WebhooksConfig sets up the issue webhooks `gforge serve` spawns a
+// goblin from for each issue given the label on GitHub or Linear, and the
+// outbound ones goblin events are posted to
+type WebhooksConfig struct {
+	Label string `mapstructure:"label" yaml:"label"`
+
+	// Secrets the trackers sign deliveries with; a tracker without one is
+	// refused. They fall back to GFORGE_GITHUB_WEBHOOK_SECRET and
+	// GFORGE_LINEAR_WEBHOOK_SECRET.
+	GitHubSecret string `mapstructure:"github_secret" yaml:"github_secret"`
+	LinearSecret string `mapstructure:"linear_secret" yaml:"linear_secret"`
+
+	// Presets say how to spawn for each GitHub repository (owner/repo) or
+	// Linear team key, with "default" for the rest
+	Presets map[string]WebhookPreset `mapstructure:"presets" yaml:"presets,omitempty"`
+
+	// Outbound are URLs gforge posts goblin lifecycle events to
+	Outbound []OutboundWebhook `mapstructure:"outbound" yaml:"outbound,omitempty"`
+}
+
+// OutboundWebhook receives goblin lifecycle events as JSON POSTs
+type OutboundWebhook struct {
+	URL string `mapstructure:"url" yaml:"url"`
+
+	// Secret signs each delivery: X-Gforge-Signature carries sha256= and
+	// the hex HMAC-SHA256 of the body
+	Secret string `mapstructure:"secret" yaml:"secret"`
+
+	// Events limits what is sent (spawned, completed, failed, stopped,
+	// killed, commit); empty sends them all
+	Events []string `mapstructure:"events" yaml:"events,omitempty"`
+}
+
+// WebhookPreset is how a webhook spawns goblins
+type WebhookPreset struct {
+	Project  string `mapstructure:"project" yaml:"project"` // Local checkout to work in
+	Agent    string `mapstructure:"agent" yaml:"agent"`
+	Priority string `mapstructure:"priority" yaml:"priority"`
+	Task     string `mapstructure:"task" yaml:"task"` // Instructions put before the issue
+}
+
+// Preset returns the preset for a GitHub repository or Linear team key
+func (w WebhooksConfig) Preset(repo string) (WebhookPreset, bool) {
+	// viper lowercases map keys
+	p, ok := w.Presets[strings.ToLower(repo)]
+	if !ok {
+		p, ok = w.Presets["default"]
+	}
+	p.Project = expandPath(p.Project)
+	return p, ok
+}
+
+// ScheduleConfig is a recurring goblin run
+type ScheduleConfig struct {
+	Cron     string `mapstructure:"cron" yaml:"cron"`       // e.g. "0 9 * * mon" or @daily
+	Project  string `mapstructure:"project" yaml:"project"` // Local checkout to work in
+	Agent    string `mapstructure:"agent" yaml:"agent"`
+	Task     string `mapstructure:"task" yaml:"task"`
+	Priority string `mapstructure:"priority" yaml:"priority"`
+	Squad    string `mapstructure:"squad" yaml:"squad"`
+}
+
+// ProjectPath returns the schedule's project with ~ expanded
+func (s ScheduleConfig) ProjectPath() string {
+	return expandPath(s.Project)
+}
+
+// SlackConfig sets up the /gforge slash command, served by gforge serve
+// at /slack/commands
+type SlackConfig struct {
+	// SigningSecret verifies requests come from the Slack app; falls back
+	// to GFORGE_SLACK_SIGNING_SECRET
+	SigningSecret string `mapstructure:"signing_secret" yaml:"signing_secret"`
+
+	// Users maps Slack user IDs to the API token their commands are run
+	// with; anyone else, or a token the server doesn't accept, is refused
+	Users map[string]string `mapstructure:"users" yaml:"users,omitempty"`
+}
+
+// Token returns a Slack user's API token
+func (s SlackConfig) Token(userID string) (string, bool) {
+	// viper lowercases map keys
+	token, ok := s.Users[strings.ToLower(userID)]
+	return token, ok
+}
+
+// PriceConfig is a model's price in USD per million tokens
+type PriceConfig struct {
+	Input  float64 `mapstructure:"input" yaml:"input"`
+	Output float64 `mapstructure:"output" yaml:"output"`
+}
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host" yaml:"host"`
+	Port     int    `mapstructure:"port" yaml:"port"`
+	Username string `mapstructure:"username" yaml:"username"`
+	Password string `mapstructure:"password" yaml:"password"`
 }
 
 type IntegrationsConfig struct {
-	GitHub GitHubConfig `mapstructure:"github" yaml:"github"`
-	Linear LinearConfig `mapstructure:"linear" yaml:"linear"`
-	Jira   JiraConfig   `mapstructure:"jira" yaml:"jira"`
+	GitHub GitHubConfig      `mapstructure:"github" yaml:"github"`
+	Linear LinearConfig      `mapstructure:"linear" yaml:"linear"`
+	Jira   JiraConfig        `mapstructure:"jira" yaml:"jira"`
+	Slack  SlackNotifyConfig `mapstructure:"slack" yaml:"slack"`
+
+	// ProgressInterval is how often goblins linked to an issue comment
+	// their progress on it; zero turns progress comments off
+	ProgressInterval time.Duration `mapstructure:"progress_interval" yaml:"progress_interval"`
 }
 
 type GitHubConfig struct {
@@ -74,6 +473,28 @@ type JiraConfig struct {
 	Token   string `mapstructure:"token" yaml:"token"`
 }
 
+// SlackNotifyConfig posts to a Slack channel when goblins complete or
+// commit. It is separate from the /gforge slash command (slack).
+type SlackNotifyConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// WebhookURL is an incoming webhook, used over BotToken when both are
+	// set; falls back to SLACK_WEBHOOK_URL
+	WebhookURL string `mapstructure:"webhook_url" yaml:"webhook_url"`
+
+	// BotToken posts as a bot to Channel; falls back to SLACK_BOT_TOKEN
+	BotToken string `mapstructure:"bot_token" yaml:"bot_token"`
+	Channel  string `mapstructure:"channel" yaml:"channel"`
+
+	// Events are the events posted, completed and commit; all when empty
+	Events []string `mapstructure:"events" yaml:"events,omitempty"`
+
+	// Templates replace the message for an event, as Go templates over
+	// .Goblin, .Agent, .Project, .Branch, .Commit, .Subject, .DiffStat
+	// and .PRURL
+	Templates map[string]string `mapstructure:"templates" yaml:"templates,omitempty"`
+}
+
 // GetConfigPath returns the configuration file path
 func GetConfigPath(override string) string {
 	if override != "" {
@@ -111,9 +532,11 @@ func Load(configFile string) (*Config, error) {
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("yaml")
 
-	// Environment variable support
-	viper.SetEnvPrefix("GFORGE")
+	// Environment variable support, e.g. GFORGE_TMUX_SOCKET_NAME
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	bindEnv()
 
 	// Read config file if it exists
 	if err := viper.ReadInConfig(); err != nil {
@@ -135,6 +558,18 @@ func Load(configFile string) (*Config, error) {
 	cfg.ConfigPath = configPath
 	cfg.DatabasePath = filepath.Join(GetDataPath(), "gforge.db")
 	cfg.WorktreeBase = expandPath(cfg.General.WorktreeBase)
+	cfg.Scheduler.FreezeCalendar = expandPath(cfg.Scheduler.FreezeCalendar)
+	cfg.Voice.PiperModel = expandPath(cfg.Voice.PiperModel)
+	cfg.RecordingsDir = filepath.Join(GetDataPath(), "recordings")
+	cfg.ArchiveDir = filepath.Join(GetDataPath(), "archives")
+	cfg.ArtifactsDir = filepath.Join(GetDataPath(), "artifacts")
+	cfg.ContextsDir = filepath.Join(GetDataPath(), "contexts")
+	cfg.AgentScanFile = filepath.Join(GetDataPath(), "agent-scan.json")
+	cfg.FreezeFile = filepath.Join(GetDataPath(), "freeze-calendar.json")
+	cfg.StatCacheFile = filepath.Join(GetDataPath(), "stat-cache.json")
+	cfg.TraceFile = filepath.Join(GetDataPath(), "trace.log")
+	cfg.TelemetryDir = filepath.Join(GetDataPath(), "telemetry")
+	cfg.DaemonPIDFile = filepath.Join(GetDataPath(), "daemon.pid")
 
 	// Ensure directories exist
 	if err := ensureDirectories(&cfg); err != nil {
@@ -149,19 +584,43 @@ func setDefaults() {
 	// General
 	viper.SetDefault("general.default_agent", "claude")
 	viper.SetDefault("general.worktree_base", "~/.local/share/gforge/worktrees")
+	viper.SetDefault("general.worktree_layout", "{id}")
 	viper.SetDefault("general.auto_cleanup_days", 7)
 	viper.SetDefault("general.max_concurrent_agents", 10)
+	viper.SetDefault("general.dev_env", "off")
+	viper.SetDefault("general.agent_scan_ttl", 10*time.Minute)
+	viper.SetDefault("general.stat_cache_ttl", 30*time.Second)
+	viper.SetDefault("general.min_free_disk_mb", 1024)
 
 	// Tmux
 	viper.SetDefault("tmux.socket_name", "gforge")
+	viper.SetDefault("tmux.socket_template", "")
+	viper.SetDefault("tmux.attach_mode", "auto")
 	viper.SetDefault("tmux.default_shell", os.Getenv("SHELL"))
 	viper.SetDefault("tmux.history_limit", 50000)
+	viper.SetDefault("tmux.record", false)
 
 	// Git
 	viper.SetDefault("git.branch_prefix", "gforge/")
 	viper.SetDefault("git.branch_style", "kebab-case")
 	viper.SetDefault("git.auto_fetch", true)
 	viper.SetDefault("git.auto_stash", true)
+	viper.SetDefault("git.refuse_dirty", false)
+	viper.SetDefault("git.submodules", true)
+	viper.SetDefault("git.submodule_depth", 0)
+	viper.SetDefault("git.lfs", true)
+	viper.SetDefault("git.protected_branches", []string{"main", "master", "release/*"})
+	viper.SetDefault("git.push_remote", "origin")
+	viper.SetDefault("git.pr_remote", "origin")
+	viper.SetDefault("git.scan_secrets", true)
+	viper.SetDefault("git.max_diff_files", 50)
+	viper.SetDefault("git.max_diff_lines", 1000)
+	viper.SetDefault("git.diff_limit", "warn")
+	viper.SetDefault("git.confirm_dependencies", true)
+	viper.SetDefault("git.request_reviews", true)
+	viper.SetDefault("git.shutdown_policy", "commit")
+	viper.SetDefault("git.non_git", "init")
+	viper.SetDefault("git.copy_method", "auto")
 
 	// Voice
 	viper.SetDefault("voice.enabled", false)
@@ -170,11 +629,88 @@ func setDefaults() {
 	viper.SetDefault("voice.language", "auto")
 	viper.SetDefault("voice.wake_word", "")
 	viper.SetDefault("voice.feedback_sound", true)
+	viper.SetDefault("voice.tts", "auto")
+	viper.SetDefault("voice.piper_model", "")
+	viper.SetDefault("voice.announce", []string{"task_complete", "failure", "approval"})
+	viper.SetDefault("voice.confirm.read", "run")
+	viper.SetDefault("voice.confirm.change", "run")
+	viper.SetDefault("voice.confirm.destructive", "confirm")
+	viper.SetDefault("voice.confirm.timeout", "30s")
 
 	// Integrations
 	viper.SetDefault("integrations.github.enabled", true)
 	viper.SetDefault("integrations.linear.enabled", false)
 	viper.SetDefault("integrations.jira.enabled", false)
+	viper.SetDefault("integrations.slack.enabled", false)
+	viper.SetDefault("integrations.progress_interval", time.Hour)
+
+	// Notifications
+	viper.SetDefault("notifications.desktop", false)
+	viper.SetDefault("notifications.on_complete", true)
+	viper.SetDefault("notifications.on_failure", true)
+	viper.SetDefault("notifications.on_approval", true)
+	viper.SetDefault("notifications.idle_timeout", 30*time.Second)
+
+	// Digest
+	viper.SetDefault("digest.enabled", false)
+	viper.SetDefault("digest.schedule", "daily")
+	viper.SetDefault("digest.hour", 8)
+	viper.SetDefault("digest.smtp.port", 587)
+
+	// REST API
+	viper.SetDefault("api.listen", "127.0.0.1:7474")
+
+	// Webhooks
+	viper.SetDefault("webhooks.label", "gforge")
+
+	// Summarizer
+	viper.SetDefault("summarizer.backend", "ollama")
+	viper.SetDefault("summarizer.model", "qwen2.5:1.5b")
+	viper.SetDefault("summarizer.lines", 2000)
+	viper.SetDefault("summarizer.max_tokens", 6000)
+	viper.SetDefault("summarizer.on_complete", true)
+
+	// Rate limits
+	viper.SetDefault("rate_limits.max_wait", 10*time.Minute)
+
+	// Path locks
+	viper.SetDefault("locks.on_conflict", "warn")
+	viper.SetDefault("locks.max_wait", 30*time.Minute)
+
+	// Ollama
+	viper.SetDefault("ollama.manage_server", false)
+	viper.SetDefault("ollama.start_timeout", 30*time.Second)
+	viper.SetDefault("ollama.max_local_goblins", 0)
+	viper.SetDefault("ollama.vram_per_goblin_mb", 6144)
+
+	// Scheduler
+	viper.SetDefault("scheduler.preempt", false)
+	viper.SetDefault("scheduler.queue_timeout", 30*time.Minute)
+	viper.SetDefault("scheduler.freeze_calendar", "")
+	viper.SetDefault("scheduler.freeze_keywords", []string{"freeze", "maintenance"})
+
+	// Working hours
+	viper.SetDefault("working_hours.enabled", false)
+	viper.SetDefault("working_hours.days", []string{"mon", "tue", "wed", "thu", "fri"})
+	viper.SetDefault("working_hours.start", "09:00")
+	viper.SetDefault("working_hours.end", "18:00")
+
+	// Stats
+	viper.SetDefault("stats.snapshot_interval", 15*time.Minute)
+
+	// UI
+	viper.SetDefault("ui.theme", "default")
+	viper.SetDefault("ui.no_color", false)
+
+	// Telemetry
+	viper.SetDefault("telemetry.endpoint", "")
+	viper.SetDefault("telemetry.send_interval", 24*time.Hour)
+
+	// Daemon
+	viper.SetDefault("daemon.interval", 15*time.Second)
+	viper.SetDefault("daemon.cleanup_interval", time.Hour)
+	viper.SetDefault("daemon.overlap_interval", 5*time.Minute)
+	viper.SetDefault("daemon.merge_interval", 5*time.Minute)
 }
 
 // Show displays the current configuration
@@ -209,19 +745,39 @@ func Initialize() error {
 		General: GeneralConfig{
 			DefaultAgent:        "claude",
 			WorktreeBase:        "~/.local/share/gforge/worktrees",
+			WorktreeLayout:      "{id}",
 			AutoCleanupDays:     7,
 			MaxConcurrentAgents: 10,
+			DevEnv:              "off",
+			AgentScanTTL:        10 * time.Minute,
+			StatCacheTTL:        30 * time.Second,
+			MinFreeDiskMB:       1024,
 		},
 		Tmux: TmuxConfig{
 			SocketName:   "gforge",
+			AttachMode:   "auto",
 			DefaultShell: "$SHELL",
 			HistoryLimit: 50000,
 		},
 		Git: GitConfig{
-			BranchPrefix: "gforge/",
-			BranchStyle:  "kebab-case",
-			AutoFetch:    true,
-			AutoStash:    true,
+			BranchPrefix:      "gforge/",
+			BranchStyle:       "kebab-case",
+			AutoFetch:         true,
+			AutoStash:         true,
+			ShutdownPolicy:    "commit",
+			NonGit:            "init",
+			CopyMethod:        "auto",
+			Submodules:        true,
+			LFS:               true,
+			ProtectedBranches: []string{"main", "master", "release/*"},
+			PushRemote:        "origin",
+			PRRemote:          "origin",
+			ScanSecrets:       true,
+			MaxDiffFiles:      50,
+			MaxDiffLines:      1000,
+			DiffLimit:         "warn",
+			ConfirmDeps:       true,
+			RequestReviews:    true,
 		},
 		Voice: VoiceConfig{
 			Enabled:       false,
@@ -229,11 +785,82 @@ func Initialize() error {
 			Hotkey:        "super+shift+g",
 			Language:      "auto",
 			FeedbackSound: true,
+			TTS:           "auto",
+			Announce:      []string{"task_complete", "failure", "approval"},
+			Confirm: VoiceConfirmConfig{
+				Read:        "run",
+				Change:      "run",
+				Destructive: "confirm",
+				Timeout:     30 * time.Second,
+			},
 		},
 		Integrations: IntegrationsConfig{
 			GitHub: GitHubConfig{Enabled: true},
 			Linear: LinearConfig{Enabled: false},
 			Jira:   JiraConfig{Enabled: false},
+
+			ProgressInterval: time.Hour,
+		},
+		Notifications: NotificationsConfig{
+			Desktop:     false,
+			OnComplete:  true,
+			OnFailure:   true,
+			OnApproval:  true,
+			IdleTimeout: 30 * time.Second,
+		},
+		Digest: DigestConfig{
+			Enabled:  false,
+			Schedule: "daily",
+			Hour:     8,
+			SMTP:     SMTPConfig{Port: 587},
+		},
+		Summarizer: SummarizerConfig{
+			Backend:    "ollama",
+			Model:      "qwen2.5:1.5b",
+			Lines:      2000,
+			MaxTokens:  6000,
+			OnComplete: true,
+		},
+		RateLimits: RateLimitConfig{
+			MaxWait: 10 * time.Minute,
+		},
+		Locks: LocksConfig{
+			OnConflict: "warn",
+			MaxWait:    30 * time.Minute,
+		},
+		Ollama: OllamaConfig{
+			StartTimeout:    30 * time.Second,
+			VRAMPerGoblinMB: 6144,
+		},
+		Scheduler: SchedulerConfig{
+			QueueTimeout:   30 * time.Minute,
+			FreezeKeywords: []string{"freeze", "maintenance"},
+		},
+		WorkingHours: WorkingHoursConfig{
+			Days:  []string{"mon", "tue", "wed", "thu", "fri"},
+			Start: "09:00",
+			End:   "18:00",
+		},
+		Stats: StatsConfig{
+			SnapshotInterval: 15 * time.Minute,
+		},
+		UI: UIConfig{
+			Theme: "default",
+		},
+		Telemetry: TelemetryConfig{
+			SendInterval: 24 * time.Hour,
+		},
+		Daemon: DaemonConfig{
+			Interval:        15 * time.Second,
+			CleanupInterval: time.Hour,
+			OverlapInterval: 5 * time.Minute,
+			MergeInterval:   5 * time.Minute,
+		},
+		API: APIConfig{
+			Listen: "127.0.0.1:7474",
+		},
+		Webhooks: WebhooksConfig{
+			Label: "gforge",
 		},
 	}
 
@@ -198,3 +198,34 @@ func TestConfigIntegrations(t *testing.T) {
 		t.Error("Linear API key should be set")
 	}
 }
+
+func TestWebhookPreset(t *testing.T) {
+	w := WebhooksConfig{Presets: map[string]WebhookPreset{
+		"acme/app": {Project: "/src/app", Agent: "codex"},
+		"default":  {Project: "~/src/main", Agent: "claude"},
+	}}
+
+	if p, ok := w.Preset("Acme/App"); !ok || p.Agent != "codex" {
+		t.Errorf("Expected the acme/app preset, got %+v", p)
+	}
+
+	home, _ := os.UserHomeDir()
+	if p, ok := w.Preset("ENG"); !ok || p.Project != filepath.Join(home, "src/main") {
+		t.Errorf("Expected the default preset with its path expanded, got %+v", p)
+	}
+
+	if _, ok := (WebhooksConfig{}).Preset("ENG"); ok {
+		t.Error("Expected no preset without presets")
+	}
+}
+
+func TestSlackToken(t *testing.T) {
+	s := SlackConfig{Users: map[string]string{"u024be7lh": "t0ken"}}
+
+	if token, ok := s.Token("U024BE7LH"); !ok || token != "t0ken" {
+		t.Errorf("Expected the user's token, got %q, %v", token, ok)
+	}
+	if _, ok := s.Token("U999"); ok {
+		t.Error("Expected an unmapped user to have no token")
+	}
+}
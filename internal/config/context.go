@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// currentGoblinPath returns the file holding the current goblin context
+func currentGoblinPath() string {
+	return filepath.Join(GetDataPath(), "current-goblin")
+}
+
+// CurrentGoblin returns the goblin set with `gforge use`, or "" if none
+func CurrentGoblin() string {
+	data, err := os.ReadFile(currentGoblinPath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetCurrentGoblin stores the current goblin context; an empty name
+// clears it
+func SetCurrentGoblin(name string) error {
+	path := currentGoblinPath()
+
+	if name == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear current goblin: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to set current goblin: %w", err)
+	}
+	return nil
+}
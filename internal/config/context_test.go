@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestCurrentGoblin(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if got := CurrentGoblin(); got != "" {
+		t.Errorf("Expected no current goblin, got %q", got)
+	}
+
+	if err := SetCurrentGoblin("coder"); err != nil {
+		t.Fatalf("SetCurrentGoblin failed: %v", err)
+	}
+	if got := CurrentGoblin(); got != "coder" {
+		t.Errorf("Expected 'coder', got %q", got)
+	}
+
+	if err := SetCurrentGoblin(""); err != nil {
+		t.Fatalf("Clearing failed: %v", err)
+	}
+	if got := CurrentGoblin(); got != "" {
+		t.Errorf("Expected cleared context, got %q", got)
+	}
+
+	// Clearing twice is fine
+	if err := SetCurrentGoblin(""); err != nil {
+		t.Errorf("Clearing an unset context failed: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix starts every environment variable that overrides a config key
+const EnvPrefix = "GFORGE"
+
+// EnvVar is an environment variable bound to a config key
+type EnvVar struct {
+	Name string // e.g. GFORGE_TMUX_SOCKET_NAME
+	Key  string // e.g. tmux.socket_name
+}
+
+// Value returns the variable's value and whether it is set
+func (e EnvVar) Value() (string, bool) {
+	return os.LookupEnv(e.Name)
+}
+
+// EnvName returns the environment variable that overrides key
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvVars lists a variable for every scalar and list key in Config, sorted
+// by name. Maps (pricing, routes...) can only be set in the file. Lists
+// are read from the environment as comma-separated values.
+func EnvVars() []EnvVar {
+	var vars []EnvVar
+	collectEnvVars(reflect.TypeOf(Config{}), "", &vars)
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// bindEnv binds each key to its variable explicitly. AutomaticEnv alone
+// only finds nested keys viper already knows and never maps the dots.
+func bindEnv() {
+	for _, v := range EnvVars() {
+		viper.BindEnv(v.Key, v.Name)
+	}
+}
+
+func collectEnvVars(t reflect.Type, prefix string, vars *[]EnvVar) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+
+		switch ft := field.Type; ft.Kind() {
+		case reflect.Map:
+			continue
+		case reflect.Slice:
+			if ft.Elem().Kind() == reflect.Struct {
+				continue
+			}
+		case reflect.Struct:
+			if ft != reflect.TypeOf(time.Time{}) {
+				collectEnvVars(ft, key+".", vars)
+				continue
+			}
+		}
+		*vars = append(*vars, EnvVar{Name: EnvName(key), Key: key})
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvVars(t *testing.T) {
+	names := make(map[string]string)
+	for _, v := range EnvVars() {
+		names[v.Name] = v.Key
+	}
+
+	for name, key := range map[string]string{
+		"GFORGE_TMUX_SOCKET_NAME":      "tmux.socket_name",
+		"GFORGE_GENERAL_DEFAULT_AGENT": "general.default_agent",
+		"GFORGE_WORKING_HOURS_DAYS":    "working_hours.days",
+		"GFORGE_DIGEST_SMTP_HOST":      "digest.smtp.host",
+	} {
+		if names[name] != key {
+			t.Errorf("Expected %s bound to %s, got %q", name, key, names[name])
+		}
+	}
+	if _, ok := names["GFORGE_PRICING"]; ok {
+		t.Error("Expected maps left to the config file")
+	}
+	if _, ok := names["GFORGE_DATABASE_PATH"]; ok {
+		t.Error("Expected computed paths left out")
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", tmpDir)
+
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte("tmux:\n  socket_name: fromfile\ngeneral:\n  default_agent: codex\n"), 0644)
+
+	t.Setenv("GFORGE_TMUX_SOCKET_NAME", "fromenv")
+	t.Setenv("GFORGE_GIT_AUTO_FETCH", "false")
+	t.Setenv("GFORGE_WORKING_HOURS_DAYS", "sat,sun")
+	t.Setenv("GFORGE_SLACK_SIGNING_SECRET", "s3cret")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.Tmux.SocketName != "fromenv" {
+		t.Errorf("Expected the environment to override the file, got %q", cfg.Tmux.SocketName)
+	}
+	if cfg.General.DefaultAgent != "codex" {
+		t.Errorf("Expected unset variables to leave the file alone, got %q", cfg.General.DefaultAgent)
+	}
+	if cfg.Git.AutoFetch {
+		t.Error("Expected GFORGE_GIT_AUTO_FETCH=false to turn auto_fetch off")
+	}
+	if len(cfg.WorkingHours.Days) != 2 || cfg.WorkingHours.Days[1] != "sun" {
+		t.Errorf("Expected a comma-separated list, got %v", cfg.WorkingHours.Days)
+	}
+	if cfg.Slack.SigningSecret != "s3cret" {
+		t.Errorf("Expected a key without a default set from the environment, got %q", cfg.Slack.SigningSecret)
+	}
+}
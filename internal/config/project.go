@@ -0,0 +1,289 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFile is the per-repository settings file, committed alongside the
+// code so a team shares one gforge setup
+const ProjectFile = ".gforge.yaml"
+
+// ProjectTasksDir holds reusable task prompts, one Markdown file per task
+const ProjectTasksDir = ".gforge/tasks"
+
+// ProjectConfig holds settings for goblins spawned in one repository.
+// Empty fields fall back to the user's global config.
+type ProjectConfig struct {
+	Agent        string       `yaml:"agent"`
+	BranchPrefix string       `yaml:"branch_prefix"`
+	DevEnv       string       `yaml:"dev_env"`
+	Hooks        ProjectHooks `yaml:"hooks"`
+
+	// Context lists onboarding files (README, architecture notes,
+	// CONTRIBUTING) every new goblin is asked to read before its first task
+	Context []string `yaml:"context"`
+
+	// RepoMap sends new goblins a map of the repository (packages,
+	// files, public symbols) trimmed to RepoMapTokens; on unless set false
+	RepoMap       bool `yaml:"repo_map"`
+	RepoMapTokens int  `yaml:"repo_map_tokens"`
+
+	// Git sets the identity and credentials goblins use here
+	Git ProjectGit `yaml:"git"`
+
+	// ProtectedBranches adds to git.protected_branches for this repository
+	ProtectedBranches []string `yaml:"protected_branches"`
+
+	// WorktreeBase and WorktreeLayout override general.worktree_base and
+	// general.worktree_layout; a relative base is taken from the project
+	WorktreeBase   string `yaml:"worktree_base"`
+	WorktreeLayout string `yaml:"worktree_layout"`
+
+	// CopyPaths are untracked files or directories (node_modules, .venv,
+	// generated assets) copied from the project into each new worktree,
+	// as copy-on-write clones where the filesystem supports them
+	CopyPaths []string `yaml:"copy_paths"`
+
+	// PushRemote and PRRemote override git.push_remote and git.pr_remote,
+	// e.g. push: fork with PRs against origin
+	PushRemote string `yaml:"push_remote"`
+	PRRemote   string `yaml:"pr_remote"`
+
+	// Checks are lint and vet commands gforge push runs in a goblin's
+	// worktree first
+	Checks ProjectChecks `yaml:"checks"`
+
+	// Done is the finish pipeline gforge done runs on a goblin
+	Done ProjectDone `yaml:"done"`
+
+	// Artifacts are files gforge done collects from a goblin's worktree
+	// into the data directory once its tests pass, e.g. coverage.out,
+	// dist/* or screenshots/. Patterns are globs relative to the worktree;
+	// a directory is collected whole.
+	Artifacts []string `yaml:"artifacts"`
+
+	// Changelog has gforge push add an entry for a goblin's work to the
+	// branch before it is pushed
+	Changelog ProjectChangelog `yaml:"changelog"`
+
+	// Dispatch triggers GitHub Actions workflows after a goblin's branch
+	// is pushed or merged, e.g. an e2e suite too slow for every commit
+	Dispatch []ProjectDispatch `yaml:"dispatch"`
+
+	// SignoffOwners are CODEOWNERS owners (e.g. @acme/security) whose
+	// paths need their signoff; gforge spawn warns when a goblin is set to
+	// change them
+	SignoffOwners []string `yaml:"signoff_owners"`
+
+	// MaxDiffFiles and MaxDiffLines override git.max_diff_files and
+	// git.max_diff_lines; -1 turns a limit off here
+	MaxDiffFiles int `yaml:"max_diff_files"`
+	MaxDiffLines int `yaml:"max_diff_lines"`
+
+	// Ignore leaves files out of change counts, diff sizes and review
+	// diffs: build output, lockfiles, generated code. Patterns use
+	// .gitignore syntax, e.g. dist/, *.lock or **/*.pb.go.
+	Ignore []string `yaml:"ignore"`
+
+	// TmuxSocket runs this project's goblins on their own tmux server,
+	// overriding tmux.socket_template; may use {project}, {name}, {user}
+	TmuxSocket string `yaml:"tmux_socket"`
+}
+
+// ProjectGit scopes the git identity and credentials goblins use in a
+// repository, e.g. a bot account with a deploy key, so they push with that
+// access rather than the user's
+type ProjectGit struct {
+	UserName  string `yaml:"user_name"`
+	UserEmail string `yaml:"user_email"`
+
+	// SSHKey is a private key (e.g. a deploy key) used for ssh remotes in
+	// place of the user's keys and agent
+	SSHKey string `yaml:"ssh_key"`
+
+	// CredentialHelper replaces the user's credential helpers for https
+	// remotes, e.g. "store --file ~/.config/gforge/bot-credentials"
+	CredentialHelper string `yaml:"credential_helper"`
+}
+
+// Env returns the environment variables that apply these settings to any
+// git command run with them
+func (g ProjectGit) Env() []string {
+	var env []string
+	if g.UserName != "" {
+		env = append(env, "GIT_AUTHOR_NAME="+g.UserName, "GIT_COMMITTER_NAME="+g.UserName)
+	}
+	if g.UserEmail != "" {
+		env = append(env, "GIT_AUTHOR_EMAIL="+g.UserEmail, "GIT_COMMITTER_EMAIL="+g.UserEmail)
+	}
+	if g.SSHKey != "" {
+		env = append(env, fmt.Sprintf("GIT_SSH_COMMAND=ssh -i %s -o IdentitiesOnly=yes -o IdentityAgent=none",
+			workspace.ShellQuote(expandPath(g.SSHKey))))
+	}
+	if g.CredentialHelper != "" {
+		// An empty helper first clears the ones from the user's config
+		env = append(env,
+			"GIT_CONFIG_COUNT=2",
+			"GIT_CONFIG_KEY_0=credential.helper", "GIT_CONFIG_VALUE_0=",
+			"GIT_CONFIG_KEY_1=credential.helper", "GIT_CONFIG_VALUE_1="+g.CredentialHelper)
+	}
+	return env
+}
+
+// WorktreeDir returns the project's worktree_base as an absolute path, or
+// "" when it doesn't set one
+func (pc *ProjectConfig) WorktreeDir(projectPath string) string {
+	if pc.WorktreeBase == "" {
+		return ""
+	}
+	dir := expandPath(pc.WorktreeBase)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectPath, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// ProjectHooks are shell commands run in a goblin's worktree
+type ProjectHooks struct {
+	PostSpawn []string `yaml:"post_spawn"` // Before the agent starts
+	PreStop   []string `yaml:"pre_stop"`   // Before the session is stopped
+}
+
+// ProjectChecks gate gforge push on a project's static analysis
+type ProjectChecks struct {
+	Commands []string      `yaml:"commands"` // Run with sh -c; all must pass
+	Timeout  time.Duration `yaml:"timeout"`  // Per command; 10 minutes when unset
+
+	// FixTask sends failing output back to the goblin as a task to fix
+	FixTask bool `yaml:"fix_task"`
+}
+
+// ProjectDone configures gforge done, which wraps up a goblin: tests,
+// commit, push, pull request, notification, then completed (or archived)
+type ProjectDone struct {
+	Tests   []string      `yaml:"tests"`   // Run with sh -c in the worktree; all must pass
+	Timeout time.Duration `yaml:"timeout"` // Per test command; 10 minutes when unset
+
+	PR    bool   `yaml:"pr"`    // Open a pull request after pushing
+	Draft bool   `yaml:"draft"` // Open it as a draft
+	Base  string `yaml:"base"`  // Its base branch; the default branch when unset
+
+	// Archive shelves the goblin afterwards instead of leaving it
+	// completed with its worktree
+	Archive bool `yaml:"archive"`
+}
+
+// ProjectChangelog is how a project records changes
+type ProjectChangelog struct {
+	// Format is keepachangelog (entries under Unreleased in a
+	// CHANGELOG.md) or towncrier (a fragment file per change); empty
+	// leaves changelogs alone
+	Format string `yaml:"format"`
+
+	// Path is the changelog file, or towncrier's fragment directory;
+	// CHANGELOG.md and changelog.d by default
+	Path string `yaml:"path"`
+
+	// Type is the section (keepachangelog) or fragment type (towncrier)
+	// for changes whose commits don't say, e.g. "Changed" or "misc"
+	Type string `yaml:"type"`
+}
+
+// Dispatch triggers
+const (
+	DispatchOnPush  = "push"
+	DispatchOnMerge = "merge"
+)
+
+// ProjectDispatch is a GitHub Actions workflow to trigger, by
+// workflow_dispatch (Workflow) or repository_dispatch (EventType)
+type ProjectDispatch struct {
+	On        string `yaml:"on"`       // push (the default) or merge
+	Workflow  string `yaml:"workflow"` // Workflow file or name, e.g. e2e.yml
+	EventType string `yaml:"event_type"`
+
+	// Ref is the branch the workflow runs on: the goblin's branch after a
+	// push and the repository's default branch after a merge when unset
+	Ref string `yaml:"ref"`
+
+	// Inputs are workflow_dispatch inputs, or the client_payload of a
+	// repository_dispatch
+	Inputs map[string]string `yaml:"inputs"`
+}
+
+// Trigger returns when the workflow is dispatched
+func (d ProjectDispatch) Trigger() string {
+	if d.On == "" {
+		return DispatchOnPush
+	}
+	return d.On
+}
+
+// Name is what the dispatch is shown as
+func (d ProjectDispatch) Name() string {
+	if d.Workflow != "" {
+		return d.Workflow
+	}
+	return d.EventType
+}
+
+// LoadProject reads a repository's .gforge.yaml. A missing file yields an
+// empty config.
+func LoadProject(projectPath string) (*ProjectConfig, error) {
+	pc := ProjectConfig{RepoMap: true}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, ProjectFile))
+	if os.IsNotExist(err) {
+		return &pc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ProjectFile, err)
+	}
+
+	if err := yaml.Unmarshal(data, &pc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ProjectFile, err)
+	}
+
+	return &pc, nil
+}
+
+// ProjectTask reads a named task prompt from .gforge/tasks/<name>.md
+func ProjectTask(projectPath, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid task template name: %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, ProjectTasksDir, name+".md"))
+	if os.IsNotExist(err) {
+		available := ProjectTasks(projectPath)
+		if len(available) == 0 {
+			return "", fmt.Errorf("task template not found: %s (no templates in %s)", name, ProjectTasksDir)
+		}
+		return "", fmt.Errorf("task template not found: %s (available: %s)", name, strings.Join(available, ", "))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read task template: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ProjectTasks lists the task templates a repository defines
+func ProjectTasks(projectPath string) []string {
+	matches, _ := filepath.Glob(filepath.Join(projectPath, ProjectTasksDir, "*.md"))
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), ".md"))
+	}
+	sort.Strings(names)
+	return names
+}
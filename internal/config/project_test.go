@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadProject(t *testing.T) {
+	dir := t.TempDir()
+
+	pc, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("LoadProject without file failed: %v", err)
+	}
+	if pc.Agent != "" || len(pc.Hooks.PostSpawn) != 0 || !pc.RepoMap {
+		t.Errorf("Expected the default config, got %+v", pc)
+	}
+
+	os.WriteFile(filepath.Join(dir, ProjectFile), []byte(`
+agent: codex
+branch_prefix: bots/
+hooks:
+  post_spawn:
+    - npm ci
+checks:
+  commands:
+    - go vet ./...
+  timeout: 5m
+  fix_task: true
+done:
+  tests:
+    - go test ./...
+  pr: true
+  archive: true
+changelog:
+  format: towncrier
+  path: newsfragments
+ignore:
+  - "*.lock"
+  - dist/
+repo_map: false
+`), 0644)
+
+	pc, err = LoadProject(dir)
+	if err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+	if pc.Agent != "codex" || pc.BranchPrefix != "bots/" {
+		t.Errorf("Unexpected config: %+v", pc)
+	}
+	if len(pc.Hooks.PostSpawn) != 1 || pc.Hooks.PostSpawn[0] != "npm ci" {
+		t.Errorf("Unexpected hooks: %+v", pc.Hooks)
+	}
+	if len(pc.Checks.Commands) != 1 || pc.Checks.Timeout != 5*time.Minute || !pc.Checks.FixTask {
+		t.Errorf("Unexpected checks: %+v", pc.Checks)
+	}
+	if len(pc.Done.Tests) != 1 || !pc.Done.PR || pc.Done.Draft || !pc.Done.Archive {
+		t.Errorf("Unexpected done: %+v", pc.Done)
+	}
+	if len(pc.Ignore) != 2 || pc.Ignore[1] != "dist/" {
+		t.Errorf("Unexpected ignore: %v", pc.Ignore)
+	}
+	if pc.Changelog.Format != "towncrier" || pc.Changelog.Path != "newsfragments" {
+		t.Errorf("Unexpected changelog: %+v", pc.Changelog)
+	}
+	if pc.RepoMap {
+		t.Error("Expected repo_map: false to turn the repository map off")
+	}
+
+	os.WriteFile(filepath.Join(dir, ProjectFile), []byte("agent: [unclosed"), 0644)
+	if _, err := LoadProject(dir); err == nil {
+		t.Error("Expected error for invalid YAML")
+	}
+}
+
+func TestProjectTask(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ProjectTask(dir, "review"); err == nil || !strings.Contains(err.Error(), "no templates") {
+		t.Errorf("Expected no templates error, got %v", err)
+	}
+
+	tasksDir := filepath.Join(dir, ProjectTasksDir)
+	os.MkdirAll(tasksDir, 0755)
+	os.WriteFile(filepath.Join(tasksDir, "review.md"), []byte("Review the diff.\n"), 0644)
+	os.WriteFile(filepath.Join(tasksDir, "fix-tests.md"), []byte("Fix tests."), 0644)
+
+	prompt, err := ProjectTask(dir, "review")
+	if err != nil || prompt != "Review the diff." {
+		t.Errorf("Expected review prompt, got %q (%v)", prompt, err)
+	}
+
+	if names := ProjectTasks(dir); len(names) != 2 || names[0] != "fix-tests" {
+		t.Errorf("Unexpected task list: %v", names)
+	}
+
+	if _, err := ProjectTask(dir, "missing"); err == nil || !strings.Contains(err.Error(), "fix-tests, review") {
+		t.Errorf("Expected available list in error, got %v", err)
+	}
+	if _, err := ProjectTask(dir, "../secret"); err == nil {
+		t.Error("Expected error for path in template name")
+	}
+}
+
+func TestProjectGitEnv(t *testing.T) {
+	if env := (ProjectGit{}).Env(); len(env) != 0 {
+		t.Errorf("Expected no environment by default, got %v", env)
+	}
+
+	env := strings.Join(ProjectGit{
+		UserName:         "gforge-bot",
+		UserEmail:        "bot@example.com",
+		SSHKey:           "/keys/deploy key",
+		CredentialHelper: "store --file /keys/creds",
+	}.Env(), "\n")
+
+	for _, want := range []string{
+		"GIT_AUTHOR_NAME=gforge-bot",
+		"GIT_COMMITTER_EMAIL=bot@example.com",
+		"GIT_SSH_COMMAND=ssh -i '/keys/deploy key' -o IdentitiesOnly=yes",
+		"GIT_CONFIG_VALUE_0=\n",
+		"GIT_CONFIG_VALUE_1=store --file /keys/creds",
+	} {
+		if !strings.Contains(env, want) {
+			t.Errorf("Expected %q in environment:\n%s", want, env)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// liveKeys are the settings a long-running process can take up without a
+// restart, as keys or whole sections: limits, notifications, agent
+// settings and scheduling. Agent definitions themselves are built in, so
+// there is nothing else about agents to reload. Anything else (sockets,
+// paths, listeners, voice) is fixed when the process starts.
+var liveKeys = []string{
+	"general.default_agent",
+	"general.agent_scan_ttl",
+	"general.max_concurrent_agents",
+	"general.min_free_disk_mb",
+	"general.auto_cleanup_days",
+	"general.context_windows",
+	"notifications",
+	"digest",
+	"summarizer",
+	"rate_limits",
+	"locks",
+	"pricing",
+	"ollama.max_local_goblins",
+	"ollama.vram_per_goblin_mb",
+	"scheduler",
+	"working_hours",
+	"stats",
+	"ui",
+	"telemetry",
+	"daemon",
+	"schedules",
+	"webhooks.outbound",
+	"integrations.slack",
+}
+
+// Change is a config key whose value differs between two loads
+type Change struct {
+	Key     string
+	Restart bool // Only takes effect after a restart
+}
+
+// Live reports whether key can change without a restart
+func Live(key string) bool {
+	for _, live := range liveKeys {
+		if key == live || strings.HasPrefix(key, live+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff lists the keys that differ between two configs. Maps count as one
+// key; computed paths are ignored.
+func Diff(old, updated *Config) []Change {
+	var changes []Change
+	walkConfig(reflect.ValueOf(old).Elem(), reflect.ValueOf(updated).Elem(), "", func(key string, a, b reflect.Value) {
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			changes = append(changes, Change{Key: key, Restart: !Live(key)})
+		}
+	})
+	return changes
+}
+
+// Reloader re-reads a running process's config file when it changes and
+// applies the live settings to the config in use
+type Reloader struct {
+	cfg     *Config
+	modTime time.Time
+	size    int64
+}
+
+// NewReloader watches the file cfg was loaded from
+func NewReloader(cfg *Config) *Reloader {
+	r := &Reloader{cfg: cfg}
+	r.modTime, r.size = r.stat()
+	return r
+}
+
+// Config returns the config in use
+func (r *Reloader) Config() *Config {
+	return r.cfg
+}
+
+// Check reloads the file if it changed since the last check. Live changes
+// are copied into the config in use; the rest are reported with Restart
+// set. It returns nil when nothing changed, and leaves the config alone
+// when the file can't be loaded.
+func (r *Reloader) Check() ([]Change, error) {
+	modTime, size := r.stat()
+	if modTime.Equal(r.modTime) && size == r.size {
+		return nil, nil
+	}
+	r.modTime, r.size = modTime, size
+
+	fresh, err := Load(r.cfg.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	changes := Diff(r.cfg, fresh)
+
+	cur := reflect.ValueOf(r.cfg).Elem()
+	walkConfig(cur, reflect.ValueOf(fresh).Elem(), "", func(key string, a, b reflect.Value) {
+		if Live(key) {
+			a.Set(b)
+		}
+	})
+	return changes, nil
+}
+
+func (r *Reloader) stat() (time.Time, int64) {
+	info, err := os.Stat(r.cfg.ConfigPath)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// walkConfig calls fn with the matching leaf settings of two configs:
+// scalars, lists and maps, keyed like viper
+func walkConfig(a, b reflect.Value, prefix string, fn func(key string, a, b reflect.Value)) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			walkConfig(a.Field(i), b.Field(i), key+".", fn)
+			continue
+		}
+		fn(key, a.Field(i), b.Field(i))
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLive(t *testing.T) {
+	for key, want := range map[string]bool{
+		"notifications.desktop":           true,
+		"general.max_concurrent_agents":   true,
+		"general.agent_scan_ttl":          true,
+		"rate_limits.requests_per_minute": true,
+		"tmux.socket_name":                false,
+		"general.worktree_base":           false,
+		"notificationsx":                  false,
+	} {
+		if got := Live(key); got != want {
+			t.Errorf("Live(%s) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old, updated := &Config{}, &Config{}
+	updated.Notifications.Desktop = true
+	updated.Tmux.SocketName = "other"
+	updated.Pricing = map[string]PriceConfig{"codex": {Input: 1}}
+	updated.DatabasePath = "/elsewhere.db"
+
+	changes := Diff(old, updated)
+	got := make(map[string]bool)
+	for _, c := range changes {
+		got[c.Key] = c.Restart
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 changes, got %+v", changes)
+	}
+	if got["notifications.desktop"] || !got["tmux.socket_name"] || got["pricing"] {
+		t.Errorf("Unexpected restart flags: %+v", changes)
+	}
+}
+
+func TestReloader(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", tmpDir)
+	path := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(path, []byte("general:\n  max_concurrent_agents: 4\n"), 0644)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	r := NewReloader(cfg)
+	if changes, err := r.Check(); changes != nil || err != nil {
+		t.Fatalf("Expected no changes before an edit, got %v, %v", changes, err)
+	}
+
+	os.WriteFile(path, []byte("general:\n  max_concurrent_agents: 8\ntmux:\n  socket_name: moved\n"), 0644)
+	os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+
+	changes, err := r.Check()
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v, %v", changes, err)
+	}
+	if cfg.General.MaxConcurrentAgents != 8 {
+		t.Errorf("Expected the live limit applied, got %d", cfg.General.MaxConcurrentAgents)
+	}
+	if cfg.Tmux.SocketName != "gforge" {
+		t.Errorf("Expected the socket kept until a restart, got %s", cfg.Tmux.SocketName)
+	}
+	if changes, _ := r.Check(); changes != nil {
+		t.Errorf("Expected no changes on a second check, got %+v", changes)
+	}
+}
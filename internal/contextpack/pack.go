@@ -0,0 +1,181 @@
+package contextpack
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/repomap"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+const (
+	defaultMaxTokens = 8000
+	defaultCommits   = 10
+	maxFileSize      = 256 * 1024
+)
+
+// Options control what goes into a context pack
+type Options struct {
+	Paths     []string // Repository-relative paths to include (default: everything)
+	MaxTokens int      // Budget for the whole bundle
+	Commits   int      // Recent commits to list
+}
+
+// Pack is an assembled context bundle ready to hand to an agent
+type Pack struct {
+	Name      string
+	Repo      string
+	Paths     []string
+	Content   string
+	Tokens    int
+	Files     []string // Included in full
+	Omitted   []string // Listed in the tree but over budget
+	CreatedAt time.Time
+}
+
+// Build assembles a context bundle for paths in a repository: a file
+// tree, recent commits touching those paths, and as many key files as fit
+// in the token budget, READMEs and docs first.
+func Build(repo, name string, opts Options) (*Pack, error) {
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = defaultMaxTokens
+	}
+	if opts.Commits <= 0 {
+		opts.Commits = defaultCommits
+	}
+
+	for _, p := range opts.Paths {
+		if _, err := os.Stat(filepath.Join(repo, p)); err != nil {
+			return nil, fmt.Errorf("path not found in %s: %s", repo, p)
+		}
+	}
+
+	files, err := repomap.ListFiles(repo, opts.Paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files found under %s", strings.Join(opts.Paths, ", "))
+	}
+
+	pack := &Pack{Name: name, Repo: repo, Paths: opts.Paths, CreatedAt: time.Now()}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Context: %s\n\n", name)
+	fmt.Fprintf(&b, "Repository: %s\n", filepath.Base(repo))
+	if len(opts.Paths) > 0 {
+		fmt.Fprintf(&b, "Paths: %s\n", strings.Join(opts.Paths, ", "))
+	}
+
+	// The tree gets at most half the budget, so files still fit
+	b.WriteString("\n## File tree\n\n")
+	treeBudget := opts.MaxTokens / 2
+	for i, f := range files {
+		line := fmt.Sprintf("%s (%s)\n", f.Path, repomap.FormatSize(f.Size))
+		if tokens.Count(b.String()+line) > treeBudget {
+			fmt.Fprintf(&b, "... %d more files\n", len(files)-i)
+			break
+		}
+		b.WriteString(line)
+	}
+
+	if commits := recentCommits(repo, opts.Paths, opts.Commits); commits != "" {
+		section := "\n## Recent commits\n\n" + commits + "\n"
+		if tokens.Count(b.String()+section) <= opts.MaxTokens {
+			b.WriteString(section)
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		pi, pj := priority(files[i].Path), priority(files[j].Path)
+		if pi != pj {
+			return pi < pj
+		}
+		return files[i].Size < files[j].Size
+	})
+
+	b.WriteString("\n## Files\n")
+	for _, f := range files {
+		content, ok := readText(filepath.Join(repo, f.Path), f.Size)
+		if !ok {
+			continue
+		}
+
+		section := fmt.Sprintf("\n### %s\n\n```%s\n%s\n```\n", f.Path, fence(f.Path), strings.TrimRight(content, "\n"))
+		if tokens.Count(b.String()+section) > opts.MaxTokens {
+			pack.Omitted = append(pack.Omitted, f.Path)
+			continue
+		}
+		b.WriteString(section)
+		pack.Files = append(pack.Files, f.Path)
+	}
+
+	if len(pack.Omitted) > 0 {
+		note := fmt.Sprintf("\n%d file(s) omitted to fit the %d token budget.\n", len(pack.Omitted), opts.MaxTokens)
+		if tokens.Count(b.String()+note) <= opts.MaxTokens {
+			b.WriteString(note)
+		}
+	}
+
+	pack.Content = b.String()
+	pack.Tokens = tokens.Count(pack.Content)
+	return pack, nil
+}
+
+// recentCommits lists recent commits touching paths, one per line
+func recentCommits(repo string, paths []string, n int) string {
+	args := append([]string{"-C", repo, "log", "--oneline", fmt.Sprintf("-n%d", n), "--"}, paths...)
+	output, err := trace.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// priority orders files so onboarding material is included first
+func priority(path string) int {
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasPrefix(base, "readme"):
+		return 0
+	case strings.HasSuffix(base, ".md") || strings.HasSuffix(base, ".rst") || strings.HasSuffix(base, ".txt"):
+		return 1
+	case base == "go.mod" || base == "package.json" || base == "cargo.toml" || base == "pyproject.toml":
+		return 2
+	case strings.Contains(base, "_test.") || strings.Contains(base, ".test.") || strings.HasPrefix(base, "test_"):
+		return 4
+	default:
+		return 3
+	}
+}
+
+// readText reads a file unless it is too large or binary
+func readText(path string, size int64) (string, bool) {
+	if size > maxFileSize {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 {
+		return "", false
+	}
+	return string(data), true
+}
+
+// fence returns the code fence language for a file
+func fence(path string) string {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	switch ext {
+	case "md":
+		return "markdown"
+	case "yml":
+		return "yaml"
+	default:
+		return ext
+	}
+}
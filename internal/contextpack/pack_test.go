@@ -0,0 +1,92 @@
+package contextpack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	path := filepath.Join(root, rel)
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", rel, err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, repo, "src/api/handler.go", "package api\n\nfunc Handle() {}\n")
+	writeFile(t, repo, "src/api/README.md", "# API\n")
+	writeFile(t, repo, "src/other/skip.go", "package other\n")
+	writeFile(t, repo, "docs/adr/0001-use-sqlite.md", "# Use SQLite\n")
+	writeFile(t, repo, "src/api/logo.png", "\x89PNG\x00\x00")
+
+	if _, err := exec.LookPath("git"); err == nil {
+		exec.Command("git", "init", repo).Run()
+		exec.Command("git", "-C", repo, "add", ".").Run()
+		exec.Command("git", "-C", repo, "-c", "user.name=t", "-c", "user.email=t@t",
+			"commit", "-m", "Add API handler").Run()
+	}
+
+	pack, err := Build(repo, "api", Options{Paths: []string{"src/api", "docs/adr"}})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for _, want := range []string{"# Context: api", "## File tree", "src/api/handler.go (", "### src/api/README.md", "```go\npackage api"} {
+		if !strings.Contains(pack.Content, want) {
+			t.Errorf("Pack missing %q:\n%s", want, pack.Content)
+		}
+	}
+	if strings.Contains(pack.Content, "src/other") {
+		t.Error("Pack should only cover the requested paths")
+	}
+	if strings.Contains(pack.Content, "### src/api/logo.png") {
+		t.Error("Binary files should not be inlined")
+	}
+	if len(pack.Files) == 0 || pack.Files[0] != "src/api/README.md" {
+		t.Errorf("Expected README first, got %v", pack.Files)
+	}
+	if pack.Tokens != tokens.Count(pack.Content) {
+		t.Errorf("Token count mismatch: %d", pack.Tokens)
+	}
+
+	if _, err := Build(repo, "x", Options{Paths: []string{"missing"}}); err == nil {
+		t.Error("Expected error for missing path")
+	}
+}
+
+func TestBuildBudget(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, repo, "README.md", "# Small\n")
+	writeFile(t, repo, "big.go", strings.Repeat("// filler line for the budget\n", 400))
+
+	pack, err := Build(repo, "budget", Options{MaxTokens: 300})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if pack.Tokens > 300 {
+		t.Errorf("Pack exceeds budget: %d tokens", pack.Tokens)
+	}
+	if len(pack.Omitted) != 1 || pack.Omitted[0] != "big.go" {
+		t.Errorf("Expected big.go omitted, got %v", pack.Omitted)
+	}
+	if !strings.Contains(pack.Content, "big.go (") {
+		t.Error("Omitted files should still appear in the tree")
+	}
+}
+
+func TestPriority(t *testing.T) {
+	ordered := []string{"README.md", "docs/design.md", "go.mod", "main.go", "main_test.go"}
+	for i := 1; i < len(ordered); i++ {
+		if priority(ordered[i-1]) >= priority(ordered[i]) {
+			t.Errorf("Expected %s before %s", ordered[i-1], ordered[i])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package contextpack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stored is a saved context pack
+type Stored struct {
+	Name      string
+	Path      string
+	Tokens    int
+	UpdatedAt time.Time
+}
+
+// Save stores a pack's content under dir so it can be sent to other
+// goblins later, replacing any pack with the same name
+func Save(dir string, pack *Pack) (string, error) {
+	if err := validName(pack.Name); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create contexts directory: %w", err)
+	}
+
+	path := filepath.Join(dir, pack.Name+".md")
+	if err := os.WriteFile(path, []byte(pack.Content), 0644); err != nil {
+		return "", fmt.Errorf("failed to save context pack: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads a stored pack's content
+func Load(dir, name string) (string, error) {
+	if err := validName(name); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name+".md"))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("context pack not found: %s (see gforge context list)", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read context pack: %w", err)
+	}
+	return string(data), nil
+}
+
+// List returns stored packs, most recently built first
+func List(dir string) ([]Stored, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []Stored
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		packs = append(packs, Stored{
+			Name:      strings.TrimSuffix(filepath.Base(path), ".md"),
+			Path:      path,
+			Tokens:    int((info.Size() + 3) / 4),
+			UpdatedAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(packs, func(i, j int) bool { return packs[i].UpdatedAt.After(packs[j].UpdatedAt) })
+	return packs, nil
+}
+
+// DefaultName derives a pack name from the paths it covers
+func DefaultName(repo string, paths []string) string {
+	if len(paths) == 0 {
+		return filepath.Base(repo)
+	}
+
+	var parts []string
+	for _, p := range paths {
+		parts = append(parts, filepath.Base(filepath.Clean(p)))
+	}
+	return strings.Join(parts, "-")
+}
+
+func validName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid context pack name: %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package contextpack
+
+import (
+	"testing"
+)
+
+func TestSaveLoadList(t *testing.T) {
+	dir := t.TempDir()
+
+	if packs, err := List(dir); err != nil || len(packs) != 0 {
+		t.Fatalf("Expected no packs, got %v (%v)", packs, err)
+	}
+
+	path, err := Save(dir, &Pack{Name: "api", Content: "# Context: api\n"})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if path == "" {
+		t.Error("Expected saved path")
+	}
+
+	content, err := Load(dir, "api")
+	if err != nil || content != "# Context: api\n" {
+		t.Errorf("Load = %q (%v)", content, err)
+	}
+
+	packs, _ := List(dir)
+	if len(packs) != 1 || packs[0].Name != "api" || packs[0].Tokens == 0 {
+		t.Errorf("Unexpected list: %+v", packs)
+	}
+
+	if _, err := Load(dir, "missing"); err == nil {
+		t.Error("Expected error for missing pack")
+	}
+	if _, err := Save(dir, &Pack{Name: "../escape"}); err == nil {
+		t.Error("Expected error for invalid name")
+	}
+}
+
+func TestDefaultName(t *testing.T) {
+	if got := DefaultName("/src/app", nil); got != "app" {
+		t.Errorf("Expected repo name, got %q", got)
+	}
+	if got := DefaultName("/src/app", []string{"src/api/", "docs/adr"}); got != "api-adr" {
+		t.Errorf("Expected api-adr, got %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// EventAccepted records part of a goblin's work accepted onto a branch
+const EventAccepted = "accepted"
+
+// AcceptPatch commits patch, the parts of a goblin's changes that were
+// accepted, onto a new branch started at the goblin's base. The work is
+// done in a throwaway worktree, so neither the goblin's branch nor the
+// project's checkout changes. It returns the new commit.
+func (c *Coordinator) AcceptPatch(g *Goblin, patch, branch, message string) (string, error) {
+	if g.BaseRef == "" || !isGitRepo(g.ProjectPath) {
+		return "", fmt.Errorf("goblin %s has no base commit to accept its changes onto", g.Name)
+	}
+	if gitRun(g.ProjectPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch) == nil {
+		return "", fmt.Errorf("branch %s already exists; pick another with --branch", branch)
+	}
+
+	dir := filepath.Join(c.cfg.WorktreeBase, "accept-"+g.ID)
+	os.RemoveAll(dir)
+	if err := c.worktreeRun(g.ProjectPath, "add", "-b", branch, dir, g.BaseRef); err != nil {
+		return "", fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	defer func() {
+		c.worktreeRun(g.ProjectPath, "remove", "--force", dir)
+		os.RemoveAll(dir)
+	}()
+
+	commit, err := commitPatch(dir, patch, message)
+	if err != nil {
+		// The branch is only worth keeping with the accepted work on it
+		c.worktreeRun(g.ProjectPath, "remove", "--force", dir)
+		gitRun(g.ProjectPath, "branch", "-D", branch)
+		return "", err
+	}
+
+	c.recordEvent(g.ID, g.Name, EventAccepted, branch)
+	if c.log != nil {
+		c.log.Info("Accepted goblin changes",
+			logging.String("name", g.Name),
+			logging.String("branch", branch))
+	}
+	return commit, nil
+}
+
+// commitPatch applies patch to the index and worktree in dir and commits it
+func commitPatch(dir, patch, message string) (string, error) {
+	apply := trace.Command("git", "-C", dir, "apply", "--index", "--whitespace=nowarn", "-")
+	apply.Stdin = strings.NewReader(patch)
+	if output, err := apply.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to apply accepted changes: %s", strings.TrimSpace(string(output)))
+	}
+
+	args := append(identityArgs(dir), "commit", "--no-verify", "-m", message)
+	if err := gitRun(dir, args...); err != nil {
+		return "", fmt.Errorf("failed to commit accepted changes: %w", err)
+	}
+	return headCommit(dir), nil
+}
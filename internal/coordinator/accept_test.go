@@ -0,0 +1,70 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+func TestAcceptPatch(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-pick"), "gforge/pick", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	base := headCommit(worktree)
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-pick", Name: "pick", Agent: "claude", Status: "stopped",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/pick", BaseRef: base, CreatedAt: now, UpdatedAt: now})
+	g, _ := coord.Get("pick")
+
+	os.WriteFile(filepath.Join(worktree, "keep.txt"), []byte("keep\n"), 0644)
+	os.WriteFile(filepath.Join(worktree, "drop.txt"), []byte("drop\n"), 0644)
+	commitAll(g, "Add both")
+	head := headCommit(worktree)
+
+	patch, _ := workspace.BranchPatch(worktree, base)
+	files := workspace.ParsePatch(patch)
+	kept := workspace.BuildPatch(files, func(file, change int) bool { return files[file].Path == "keep.txt" })
+
+	commit, err := coord.AcceptPatch(g, kept, "gforge/pick-accepted", "Accept keep.txt")
+	if err != nil {
+		t.Fatalf("AcceptPatch failed: %v", err)
+	}
+	out, _ := exec.Command("git", "-C", repo, "ls-tree", "-r", "--name-only", "gforge/pick-accepted").Output()
+	if !strings.Contains(string(out), "keep.txt") || strings.Contains(string(out), "drop.txt") {
+		t.Errorf("Expected only keep.txt on the new branch, got %s", out)
+	}
+	if tip, _ := exec.Command("git", "-C", repo, "rev-parse", "gforge/pick-accepted").Output(); strings.TrimSpace(string(tip)) != commit {
+		t.Errorf("Expected the branch at %s, got %s", commit, tip)
+	}
+	if headCommit(worktree) != head {
+		t.Error("Expected the goblin's branch left alone")
+	}
+
+	if _, err := coord.AcceptPatch(g, kept, "gforge/pick-accepted", "again"); err == nil {
+		t.Error("Expected an existing branch to be refused")
+	}
+	// A patch that doesn't apply leaves no branch behind
+	if _, err := coord.AcceptPatch(g, "not a patch\n", "gforge/pick-bad", "bad"); err == nil {
+		t.Error("Expected a bad patch to fail")
+	}
+	if gitRun(repo, "rev-parse", "--verify", "--quiet", "refs/heads/gforge/pick-bad") == nil {
+		t.Error("Expected the branch removed after a failed apply")
+	}
+}
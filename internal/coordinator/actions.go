@@ -0,0 +1,186 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// GitHub Actions activity log events
+const (
+	EventDispatched  = "dispatched"   // A workflow was triggered for the goblin
+	EventWorkflowRun = "workflow_run" // A dispatched workflow's run finished
+)
+
+// Events a dispatched workflow's run is started by
+const (
+	eventWorkflowDispatch   = "workflow_dispatch"
+	eventRepositoryDispatch = "repository_dispatch"
+)
+
+// runMatchSkew allows for the clocks here and at GitHub disagreeing when a
+// dispatch is matched to the run it started
+const runMatchSkew = time.Minute
+
+// actionsClient is the part of the GitHub client dispatching uses
+type actionsClient interface {
+	DispatchWorkflow(repo, workflow, ref string, inputs map[string]string) error
+	DispatchRepository(repo, eventType string, payload map[string]string) error
+	ListWorkflowRuns(repo, workflow, branch, event string, limit int) ([]*integrations.WorkflowRun, error)
+	GetWorkflowRun(repo string, id int64) (*integrations.WorkflowRun, error)
+}
+
+// newActionsClient returns a GitHub client working in dir; tests replace it
+var newActionsClient = func(dir string) actionsClient {
+	gh := integrations.NewGitHubClient()
+	gh.Dir = dir
+	return gh
+}
+
+// Dispatch triggers a project's workflows for the trigger (push or merge)
+// in repo, recording each so RefreshWorkflowRuns can follow its run.
+// Inputs may use {branch} and {name}. Every workflow is tried; the
+// failures are returned together.
+func (c *Coordinator) Dispatch(g *Goblin, dispatches []config.ProjectDispatch, trigger, repo string) ([]*storage.WorkflowRun, error) {
+	vars := strings.NewReplacer("{branch}", g.Branch, "{name}", g.Name)
+	gh := newActionsClient(g.WorktreePath)
+
+	var (
+		runs     []*storage.WorkflowRun
+		failures []error
+	)
+	for _, d := range dispatches {
+		if d.Trigger() != trigger {
+			continue
+		}
+		if (d.Workflow == "") == (d.EventType == "") {
+			failures = append(failures, fmt.Errorf("dispatch needs one of workflow or event_type"))
+			continue
+		}
+
+		ref := d.Ref
+		if ref == "" && trigger == config.DispatchOnPush {
+			ref = g.Branch
+		}
+		inputs := make(map[string]string, len(d.Inputs))
+		for k, v := range d.Inputs {
+			inputs[k] = vars.Replace(v)
+		}
+
+		run := &storage.WorkflowRun{
+			GoblinID: g.ID,
+			Trigger:  trigger,
+			Workflow: d.Name(),
+			Repo:     repo,
+			Ref:      ref,
+			Status:   "dispatched",
+		}
+		var err error
+		if d.Workflow != "" {
+			run.Event = eventWorkflowDispatch
+			err = gh.DispatchWorkflow(repo, d.Workflow, ref, inputs)
+		} else {
+			run.Event = eventRepositoryDispatch
+			// repository_dispatch runs on the default branch; the ref goes
+			// along in the payload for workflows that check it out
+			if ref != "" {
+				if _, ok := inputs["ref"]; !ok {
+					inputs["ref"] = ref
+				}
+				run.Ref = ""
+			}
+			err = gh.DispatchRepository(repo, d.EventType, inputs)
+		}
+		if err != nil {
+			failures = append(failures, err)
+			continue
+		}
+
+		if err := c.db.CreateWorkflowRun(run); err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		c.recordEvent(g.ID, g.Name, EventDispatched, d.Name())
+		runs = append(runs, run)
+	}
+	return runs, errors.Join(failures...)
+}
+
+// RefreshWorkflowRuns finds the runs a goblin's dispatches started and
+// updates those not yet finished from GitHub, returning them all
+func (c *Coordinator) RefreshWorkflowRuns(g *Goblin) ([]*storage.WorkflowRun, error) {
+	runs, err := c.db.ListWorkflowRuns(g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	gh := newActionsClient(g.WorktreePath)
+	claimed := make(map[int64]bool)
+	for _, r := range runs {
+		if r.RunID != 0 {
+			claimed[r.RunID] = true
+		}
+	}
+
+	var failures []error
+	for _, r := range runs {
+		if r.Status == "completed" {
+			continue
+		}
+
+		var latest *integrations.WorkflowRun
+		if r.RunID == 0 {
+			latest, err = findRun(gh, r, claimed)
+		} else {
+			latest, err = gh.GetWorkflowRun(r.Repo, r.RunID)
+		}
+		if err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		if latest == nil {
+			// Not started yet
+			continue
+		}
+
+		claimed[latest.ID] = true
+		r.RunID, r.Status, r.Conclusion, r.URL = latest.ID, latest.Status, latest.Conclusion, latest.URL
+		if err := c.db.UpdateWorkflowRun(r); err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		if r.Status == "completed" {
+			c.recordEvent(g.ID, g.Name, EventWorkflowRun, fmt.Sprintf("%s: %s", r.Workflow, r.Conclusion))
+		}
+	}
+	return runs, errors.Join(failures...)
+}
+
+// findRun looks for the run a dispatch started: the earliest of its
+// workflow's runs since, not already taken by another dispatch
+func findRun(gh actionsClient, r *storage.WorkflowRun, claimed map[int64]bool) (*integrations.WorkflowRun, error) {
+	workflow := ""
+	if r.Event == eventWorkflowDispatch {
+		workflow = r.Workflow
+	}
+	candidates, err := gh.ListWorkflowRuns(r.Repo, workflow, r.Ref, r.Event, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *integrations.WorkflowRun
+	for _, run := range candidates {
+		if claimed[run.ID] || run.CreatedAt.Before(r.CreatedAt.Add(-runMatchSkew)) {
+			continue
+		}
+		if found == nil || run.CreatedAt.Before(found.CreatedAt) {
+			found = run
+		}
+	}
+	return found, nil
+}
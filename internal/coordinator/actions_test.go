@@ -0,0 +1,115 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// fakeActions records dispatches and serves runs from a list
+type fakeActions struct {
+	dispatched []string
+	runs       []*integrations.WorkflowRun
+}
+
+func (f *fakeActions) DispatchWorkflow(repo, workflow, ref string, inputs map[string]string) error {
+	f.dispatched = append(f.dispatched, workflow+"@"+ref+" "+inputs["target"])
+	return nil
+}
+
+func (f *fakeActions) DispatchRepository(repo, eventType string, payload map[string]string) error {
+	f.dispatched = append(f.dispatched, eventType+" "+payload["ref"])
+	return nil
+}
+
+func (f *fakeActions) ListWorkflowRuns(repo, workflow, branch, event string, limit int) ([]*integrations.WorkflowRun, error) {
+	var runs []*integrations.WorkflowRun
+	for _, r := range f.runs {
+		if (workflow == "" || r.Workflow == workflow) && (branch == "" || r.Branch == branch) && r.Event == event {
+			runs = append(runs, r)
+		}
+	}
+	return runs, nil
+}
+
+func (f *fakeActions) GetWorkflowRun(repo string, id int64) (*integrations.WorkflowRun, error) {
+	for _, r := range f.runs {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestDispatch(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	fake := &fakeActions{}
+	orig := newActionsClient
+	newActionsClient = func(string) actionsClient { return fake }
+	defer func() { newActionsClient = orig }()
+
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "e2e", Agent: "claude", Status: "running",
+		ProjectPath: "/tmp", WorktreePath: "/tmp", Branch: "gforge/e2e"})
+	g, _ := coord.Get("e2e")
+
+	dispatches := []config.ProjectDispatch{
+		{Workflow: "e2e.yml", Inputs: map[string]string{"target": "{name}"}},
+		{On: config.DispatchOnMerge, EventType: "deploy"},
+		{On: config.DispatchOnPush},
+	}
+	runs, err := coord.Dispatch(g, dispatches, config.DispatchOnPush, "acme/app")
+	if err == nil {
+		t.Error("Expected an error for the dispatch naming no workflow")
+	}
+	if len(runs) != 1 || len(fake.dispatched) != 1 || fake.dispatched[0] != "e2e.yml@gforge/e2e e2e" {
+		t.Fatalf("Expected only e2e.yml dispatched on the branch, got %v", fake.dispatched)
+	}
+
+	// Not started yet
+	runs, err = coord.RefreshWorkflowRuns(g)
+	if err != nil || len(runs) != 1 || runs[0].RunID != 0 {
+		t.Fatalf("Expected the run not found yet, got %+v, %v", runs, err)
+	}
+
+	started := &integrations.WorkflowRun{ID: 7, Workflow: "e2e.yml", Event: "workflow_dispatch", Branch: "gforge/e2e",
+		Status: "in_progress", CreatedAt: time.Now().UTC()}
+	fake.runs = []*integrations.WorkflowRun{
+		{ID: 6, Workflow: "e2e.yml", Event: "workflow_dispatch", Branch: "gforge/e2e",
+			Status: "completed", Conclusion: "failure", CreatedAt: time.Now().Add(-time.Hour)},
+		started,
+	}
+	runs, _ = coord.RefreshWorkflowRuns(g)
+	if runs[0].RunID != 7 || runs[0].Status != "in_progress" {
+		t.Fatalf("Expected the run started after the dispatch, got %+v", runs[0])
+	}
+
+	started.Status, started.Conclusion = "completed", "success"
+	runs, _ = coord.RefreshWorkflowRuns(g)
+	if runs[0].Conclusion != "success" {
+		t.Errorf("Expected the run's conclusion, got %+v", runs[0])
+	}
+	events, _ := coord.db.ListEvents(time.Time{})
+	found := false
+	for _, e := range events {
+		if e.Type == EventWorkflowRun && e.Detail == "e2e.yml: success" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the finished run in the activity log")
+	}
+
+	// Merges dispatch the rest, with the ref in the payload
+	fake.dispatched = nil
+	if _, err := coord.Dispatch(g, dispatches[:2], config.DispatchOnMerge, ""); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if len(fake.dispatched) != 1 || fake.dispatched[0] != "deploy " {
+		t.Errorf("Expected deploy dispatched on the default branch, got %v", fake.dispatched)
+	}
+}
@@ -0,0 +1,307 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/gpu"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// Spawn priorities, highest first
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// StatusPreempted marks a goblin paused by the scheduler to admit a
+// higher-priority spawn. It resumes when a slot frees up.
+const StatusPreempted = "preempted"
+
+// How often a queued spawn checks for a free slot
+var admitPoll = 5 * time.Second
+
+// ParsePriority validates a priority, defaulting to normal
+func ParsePriority(p string) (string, error) {
+	switch p {
+	case "":
+		return PriorityNormal, nil
+	case PriorityHigh, PriorityNormal, PriorityLow:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid priority %q (want high, normal or low)", p)
+}
+
+// priorityRank orders priorities for the spawn queue
+func priorityRank(p string) int {
+	switch p {
+	case PriorityHigh:
+		return 2
+	case PriorityLow:
+		return 0
+	}
+	return 1
+}
+
+// LocalLimit returns how many local-model goblins may run at once:
+// ollama.max_local_goblins, or as many as fit in detected VRAM. Without
+// either there is no limit (-1).
+func (c *Coordinator) LocalLimit() int {
+	if c.cfg.Ollama.MaxLocalGoblins > 0 {
+		return c.cfg.Ollama.MaxLocalGoblins
+	}
+	gpus := gpu.Detect()
+	if len(gpus) == 0 {
+		return -1
+	}
+	return gpu.LocalSlots(gpus, c.cfg.Ollama.VRAMPerGoblinMB)
+}
+
+// pending is a claim on a slot: a queued spawn or a preempted goblin
+type pending struct {
+	rank   int
+	local  bool
+	ticket int64   // Queue ID of a waiting spawn
+	goblin *Goblin // Preempted goblin to resume
+}
+
+// slots tracks free capacity; a negative count is unlimited
+type slots struct {
+	all, local int
+}
+
+func (s *slots) fits(local bool) bool {
+	return s.all != 0 && (!local || s.local != 0)
+}
+
+func (s *slots) take(local bool) {
+	if s.all > 0 {
+		s.all--
+	}
+	if local && s.local > 0 {
+		s.local--
+	}
+}
+
+// admit queues a spawn until a slot is free under
+// general.max_concurrent_agents and, for local-model agents, LocalLimit.
+// Waiting spawns are admitted highest priority first, then first come.
+// The returned release func takes the spawn out of the queue; call it once
+// the goblin is recorded so its slot is never counted twice or not at all.
+// Gives up after scheduler.queue_timeout.
+func (c *Coordinator) admit(agent *agents.Agent, name, priority string) (func(), error) {
+	local := agent.HasCapability("local")
+	if c.cfg.General.MaxConcurrentAgents <= 0 && !local {
+		return func() {}, nil
+	}
+
+	ticket, err := c.db.EnqueueSpawn(&storage.QueuedSpawn{
+		Name:  name,
+		Local: local,
+		Rank:  priorityRank(priority),
+		PID:   os.Getpid(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	release := func() { c.db.DequeueSpawn(ticket) }
+
+	timeout := c.cfg.Scheduler.QueueTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Minute
+	}
+
+	queued := false
+	for deadline := time.Now().Add(timeout); ; time.Sleep(admitPoll) {
+		admitted, err := c.schedule(ticket)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		if admitted {
+			return release, nil
+		}
+		if time.Now().After(deadline) {
+			release()
+			return nil, fmt.Errorf("no free goblin slot for %s; gave up waiting after %s", name, timeout)
+		}
+
+		if !queued && c.log != nil {
+			c.log.Warn("Goblin limit reached, queueing spawn",
+				logging.String("name", name),
+				logging.String("priority", priority))
+		}
+		queued = true
+	}
+}
+
+// resumePreempted resumes preempted goblins that fit now that a slot has
+// freed up, unless a queued spawn outranks them
+func (c *Coordinator) resumePreempted() {
+	goblins, err := c.List()
+	if err != nil {
+		return
+	}
+	for _, g := range goblins {
+		if g.Status == StatusPreempted {
+			c.schedule(0)
+			return
+		}
+	}
+}
+
+// schedule hands free slots to preempted goblins and queued spawns in
+// priority order, resuming the goblins it reaches. It reports whether the
+// spawn holding ticket got a slot; if not and scheduler.preempt is set,
+// it may pause a lower-priority goblin so the spawn is admitted next time.
+func (c *Coordinator) schedule(ticket int64) (bool, error) {
+	goblins, err := c.List()
+	if err != nil {
+		return false, err
+	}
+	queue, err := c.db.ListSpawnQueue()
+	if err != nil {
+		return false, err
+	}
+
+	var active []*Goblin
+	var claims []pending
+	for _, g := range goblins {
+		switch g.Status {
+		case "stopped", "failed", "paused", StatusDead, StatusCompleted, StatusArchived:
+		case StatusPreempted:
+			claims = append(claims, pending{rank: priorityRank(g.Priority), local: c.isLocal(g.Agent), goblin: g})
+		default:
+			active = append(active, g)
+		}
+	}
+	for _, q := range queue {
+		// Spawns whose process died without dequeuing hold no claim
+		if q.ID != ticket && !ProcessAlive(q.PID) {
+			c.db.DequeueSpawn(q.ID)
+			continue
+		}
+		claims = append(claims, pending{rank: q.Rank, local: q.Local, ticket: q.ID})
+	}
+
+	// Only detect GPUs when a local-model claim needs the limit
+	free := slots{all: -1, local: -1}
+	if c.cfg.General.MaxConcurrentAgents > 0 {
+		free.all = c.cfg.General.MaxConcurrentAgents
+	}
+	for _, p := range claims {
+		if p.local {
+			free.local = c.LocalLimit()
+			break
+		}
+	}
+	for _, g := range active {
+		free.take(c.isLocal(g.Agent))
+	}
+
+	// Preempted goblins were running first, so they go ahead of queued
+	// spawns of the same priority
+	sort.SliceStable(claims, func(i, j int) bool {
+		if claims[i].rank != claims[j].rank {
+			return claims[i].rank > claims[j].rank
+		}
+		return claims[i].goblin != nil && claims[j].goblin == nil
+	})
+
+	blockedAhead := false
+	for _, p := range claims {
+		if !free.fits(p.local) {
+			if p.ticket == ticket && ticket != 0 && !blockedAhead && c.cfg.Scheduler.Preempt {
+				c.preemptFor(p, free, active)
+			}
+			blockedAhead = true
+			if p.ticket == ticket && ticket != 0 {
+				return false, nil
+			}
+			continue
+		}
+
+		free.take(p.local)
+		if p.goblin != nil {
+			if err := c.resume(p.goblin); err != nil && c.log != nil {
+				c.log.Warn("Failed to resume preempted goblin",
+					logging.String("name", p.goblin.Name),
+					logging.Err(err))
+			}
+		}
+		if p.ticket == ticket && ticket != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// preemptFor pauses the lowest-priority, most recently spawned goblin
+// ranked below claim. Only a local goblin frees a local slot, so one is
+// required when that pool is full. Pinned goblins are never preempted, and
+// throttled goblins are skipped: they restore their own status once the
+// rate limit lets them through.
+func (c *Coordinator) preemptFor(claim pending, free slots, active []*Goblin) {
+	victim := c.pickVictim(claim, free, active)
+	if victim == nil {
+		return
+	}
+
+	if err := c.pause(victim, StatusPreempted); err != nil {
+		if c.log != nil {
+			c.log.Warn("Failed to preempt goblin",
+				logging.String("name", victim.Name),
+				logging.Err(err))
+		}
+		return
+	}
+	if c.log != nil {
+		c.log.Info("Preempted goblin for a higher-priority spawn",
+			logging.String("name", victim.Name),
+			logging.String("priority", victim.Priority))
+	}
+}
+
+// pickVictim chooses the goblin preemptFor pauses, or nil for none
+func (c *Coordinator) pickVictim(claim pending, free slots, active []*Goblin) *Goblin {
+	needLocal := claim.local && free.local == 0
+
+	var victim *Goblin
+	for _, g := range active {
+		rank := priorityRank(g.Priority)
+		if rank >= claim.rank || g.Pinned || g.Status == StatusThrottled {
+			continue
+		}
+		if needLocal && !c.isLocal(g.Agent) {
+			continue
+		}
+		if victim == nil || rank < priorityRank(victim.Priority) ||
+			(rank == priorityRank(victim.Priority) && g.CreatedAt.After(victim.CreatedAt)) {
+			victim = g
+		}
+	}
+	return victim
+}
+
+// isLocal reports whether an agent runs a local model
+func (c *Coordinator) isLocal(agentName string) bool {
+	a := agents.NewRegistry().Get(agentName)
+	return a != nil && a.HasCapability("local")
+}
+
+// ProcessAlive reports whether pid is a running process. One owned by
+// another user, which can't be signalled, still counts.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
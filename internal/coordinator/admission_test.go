@@ -0,0 +1,192 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestAdmitLocal(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	admitPoll = time.Millisecond
+	cfg.Ollama.MaxLocalGoblins = 1
+	cfg.Scheduler.QueueTimeout = 20 * time.Millisecond
+
+	registry := agents.NewRegistry()
+	local := registry.Get("ollama")
+
+	release, err := coord.admit(local, "first", PriorityNormal)
+	if err != nil {
+		t.Fatalf("Expected a free slot, got %v", err)
+	}
+	release()
+
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "first", Agent: "ollama-qwen", Status: "running", ProjectPath: "/tmp"})
+
+	if _, err := coord.admit(local, "second", PriorityNormal); err == nil {
+		t.Error("Expected the second local goblin to time out in the queue")
+	}
+	if release, err := coord.admit(registry.Get("claude"), "cloud", PriorityNormal); err != nil {
+		t.Errorf("Expected hosted agents to bypass the local limit, got %v", err)
+	} else {
+		release()
+	}
+
+	// A slot that frees up while queued admits the spawn
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		coord.db.UpdateGoblinStatus("id-1", "stopped")
+	}()
+	cfg.Scheduler.QueueTimeout = time.Second
+	if release, err := coord.admit(local, "second", PriorityNormal); err != nil {
+		t.Errorf("Expected the queued spawn admitted once a slot freed, got %v", err)
+	} else {
+		release()
+	}
+
+	if queue, _ := coord.db.ListSpawnQueue(); len(queue) != 0 {
+		t.Errorf("Expected the queue emptied, got %d entries", len(queue))
+	}
+}
+
+func TestLocalLimitWithoutGPU(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	// Neither nvidia-smi nor rocm-smi to find
+	t.Setenv("PATH", t.TempDir())
+	if limit := coord.LocalLimit(); limit != -1 {
+		t.Errorf("Expected no local limit without a GPU, got %d", limit)
+	}
+
+	cfg.Ollama.MaxLocalGoblins = 2
+	if limit := coord.LocalLimit(); limit != 2 {
+		t.Errorf("Expected ollama.max_local_goblins, got %d", limit)
+	}
+
+	cfg.Ollama.MaxLocalGoblins = 0
+	cfg.Scheduler.QueueTimeout = 20 * time.Millisecond
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "first", Agent: "ollama-qwen", Status: "running", ProjectPath: "/tmp"})
+	if release, err := coord.admit(agents.NewRegistry().Get("ollama"), "second", PriorityNormal); err != nil {
+		t.Errorf("Expected local goblins unlimited without a GPU, got %v", err)
+	} else {
+		release()
+	}
+}
+
+func TestSchedulePriority(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	cfg.General.MaxConcurrentAgents = 1
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "busy", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	low, _ := coord.db.EnqueueSpawn(&storage.QueuedSpawn{Name: "low", Rank: priorityRank(PriorityLow), PID: os.Getpid()})
+	high, _ := coord.db.EnqueueSpawn(&storage.QueuedSpawn{Name: "high", Rank: priorityRank(PriorityHigh), PID: os.Getpid()})
+	// A spawn whose process is gone gives up its place
+	coord.db.EnqueueSpawn(&storage.QueuedSpawn{Name: "orphan", Rank: priorityRank(PriorityHigh), PID: 1 << 22})
+
+	if ok, _ := coord.schedule(high); ok {
+		t.Fatal("Expected no slot while the limit is reached")
+	}
+
+	coord.db.UpdateGoblinStatus("id-1", "stopped")
+
+	if ok, _ := coord.schedule(low); ok {
+		t.Error("Expected the low-priority spawn to wait behind the high one")
+	}
+	if ok, _ := coord.schedule(high); !ok {
+		t.Error("Expected the high-priority spawn admitted first")
+	}
+
+	queue, _ := coord.db.ListSpawnQueue()
+	if len(queue) != 2 {
+		t.Errorf("Expected the orphaned spawn pruned, got %d entries", len(queue))
+	}
+}
+
+func TestSchedulePreempt(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	cfg.General.MaxConcurrentAgents = 1
+	cfg.Scheduler.Preempt = true
+
+	// An interactive shell without startup files, so job control is on
+	// and the agent starts promptly
+	if err := exec.Command("tmux", "-L", cfg.Tmux.SocketName, "new-session", "-d", "-s", "gforge-victim",
+		"bash --norc --noprofile -i").Run(); err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	exec.Command("tmux", "-L", cfg.Tmux.SocketName, "send-keys", "-t", "gforge-victim", "sleep 300", "Enter").Run()
+	time.Sleep(500 * time.Millisecond)
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "victim", Agent: "claude", Status: "running",
+		ProjectPath: "/tmp", TmuxSession: "gforge-victim", Priority: PriorityLow})
+
+	high, _ := coord.db.EnqueueSpawn(&storage.QueuedSpawn{Name: "urgent", Rank: priorityRank(PriorityHigh), PID: os.Getpid()})
+
+	if ok, _ := coord.schedule(high); ok {
+		t.Fatal("Expected the spawn to wait for the preempted slot")
+	}
+	victim, _ := coord.Get("victim")
+	if victim.Status != StatusPreempted {
+		t.Fatalf("Expected the low-priority goblin preempted, got %s", victim.Status)
+	}
+	if state := agentState(t, cfg.Tmux.SocketName, "gforge-victim"); state != "T" {
+		t.Errorf("Expected the pane process stopped, got state %q", state)
+	}
+
+	if ok, _ := coord.schedule(high); !ok {
+		t.Fatal("Expected the spawn admitted after preemption")
+	}
+
+	// Once the spawn leaves the queue the slot goes back to the victim
+	coord.db.DequeueSpawn(high)
+	coord.resumePreempted()
+
+	victim, _ = coord.Get("victim")
+	if victim.Status != "running" {
+		t.Errorf("Expected the goblin resumed, got %s", victim.Status)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if state := agentState(t, cfg.Tmux.SocketName, "gforge-victim"); state == "T" {
+		t.Error("Expected the pane process continued")
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	if p, err := ParsePriority(""); err != nil || p != PriorityNormal {
+		t.Errorf("Expected normal by default, got %q, %v", p, err)
+	}
+	if _, err := ParsePriority("urgent"); err == nil {
+		t.Error("Expected an unknown priority rejected")
+	}
+}
+
+// agentState returns the ps state of the process the session's shell runs
+func agentState(t *testing.T, socket, session string) string {
+	out, err := exec.Command("tmux", "-L", socket, "display-message", "-p", "-t", session, "#{pane_pid}").Output()
+	if err != nil {
+		t.Fatalf("Failed to get pane pid: %v", err)
+	}
+	out, err = exec.Command("pgrep", "-P", strings.TrimSpace(string(out))).Output()
+	if err != nil {
+		t.Fatalf("Expected the shell to be running a command: %v", err)
+	}
+	state, err := exec.Command("ps", "-o", "stat=", "-p", strings.Fields(string(out))[0]).Output()
+	if err != nil {
+		t.Fatalf("Failed to read process state: %v", err)
+	}
+	return strings.TrimSpace(string(state))[:1]
+}
@@ -0,0 +1,87 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventApplied records a goblin's changes applied to its project's checkout
+const EventApplied = "applied"
+
+// ApplyOptions choose what ApplyToProject applies and how
+type ApplyOptions struct {
+	Files    []string // Only these paths; all changes when empty
+	ThreeWay bool     // Fall back to a 3-way merge, leaving conflict markers
+	DryRun   bool     // Check and summarize without changing anything
+}
+
+// ApplyToProject applies what a goblin changed since its base, uncommitted
+// work included, to the project checkout it was spawned from, without
+// merging its branch. Nothing is changed unless every file applies
+// cleanly (or, with ThreeWay, merges). It returns a diffstat of the
+// changes.
+func (c *Coordinator) ApplyToProject(g *Goblin, opts ApplyOptions) (string, error) {
+	if g.WorktreePath == "" || g.WorktreePath == g.ProjectPath {
+		return "", fmt.Errorf("goblin %s works directly in %s; its changes are already there", g.Name, g.ProjectPath)
+	}
+	if g.BaseRef == "" || !isGitRepo(g.WorktreePath) {
+		return "", fmt.Errorf("goblin %s has no base commit to take its changes from", g.Name)
+	}
+
+	patch, err := workspace.WorkPatch(g.WorktreePath, g.BaseRef, opts.Files)
+	if err != nil {
+		return "", err
+	}
+	if patch == "" {
+		if len(opts.Files) > 0 {
+			return "", fmt.Errorf("goblin %s has not changed %s", g.Name, strings.Join(opts.Files, ", "))
+		}
+		return "", fmt.Errorf("goblin %s has no changes to apply", g.Name)
+	}
+
+	stat, err := gitApply(g.ProjectPath, patch, "--stat")
+	if err != nil {
+		return "", err
+	}
+	args := []string{"--check"}
+	if !opts.DryRun {
+		args = nil
+	}
+	if opts.ThreeWay {
+		args = append(args, "--3way")
+	}
+	if output, err := gitApply(g.ProjectPath, patch, args...); err != nil {
+		// A 3-way merge applies what it can and marks the rest
+		if opts.ThreeWay && !opts.DryRun && strings.Contains(output, "with conflicts") {
+			c.recordEvent(g.ID, g.Name, EventApplied, g.ProjectPath)
+			return stat, fmt.Errorf("applied with conflicts; resolve them in %s:\n%s", g.ProjectPath, strings.TrimSpace(output))
+		}
+		return "", err
+	}
+	if opts.DryRun {
+		return stat, nil
+	}
+
+	c.recordEvent(g.ID, g.Name, EventApplied, g.ProjectPath)
+	if c.log != nil {
+		c.log.Info("Applied goblin changes",
+			logging.String("name", g.Name),
+			logging.String("project", g.ProjectPath))
+	}
+	return stat, nil
+}
+
+// gitApply runs git apply in dir with patch on stdin, returning its output
+func gitApply(dir, patch string, args ...string) (string, error) {
+	cmd := trace.Command("git", append([]string{"-C", dir, "apply", "--whitespace=nowarn"}, append(args, "-")...)...)
+	cmd.Stdin = strings.NewReader(patch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("changes do not apply to %s:\n%s", dir, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
@@ -0,0 +1,71 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestApplyToProject(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-give"), "gforge/give", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-give", Name: "give", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/give", BaseRef: headCommit(worktree), CreatedAt: now, UpdatedAt: now})
+	g, _ := coord.Get("give")
+
+	// A commit, an uncommitted edit and a new file
+	os.WriteFile(filepath.Join(worktree, "committed.txt"), []byte("committed\n"), 0644)
+	commitAll(g, "Add committed.txt")
+	os.WriteFile(filepath.Join(worktree, "README.md"), []byte("# Edited\n"), 0644)
+	os.WriteFile(filepath.Join(worktree, "untracked.txt"), []byte("new\n"), 0644)
+
+	stat, err := coord.ApplyToProject(g, ApplyOptions{DryRun: true})
+	if err != nil || !strings.Contains(stat, "3 files changed") {
+		t.Fatalf("Expected a dry run over 3 files, got %q, %v", stat, err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "committed.txt")); !os.IsNotExist(err) {
+		t.Fatal("Expected a dry run to change nothing")
+	}
+
+	if _, err := coord.ApplyToProject(g, ApplyOptions{Files: []string{"untracked.txt", "README.md"}}); err != nil {
+		t.Fatalf("ApplyToProject failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo, "README.md")); string(data) != "# Edited\n" {
+		t.Errorf("Expected the edit applied, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "committed.txt")); !os.IsNotExist(err) {
+		t.Error("Expected files not selected left out")
+	}
+	if out, _ := exec.Command("git", "-C", worktree, "diff", "--cached", "--name-only").Output(); len(out) != 0 {
+		t.Errorf("Expected the goblin's index untouched, got %s", out)
+	}
+
+	// Applying again conflicts with what is now there, and changes nothing
+	if _, err := coord.ApplyToProject(g, ApplyOptions{}); err == nil {
+		t.Error("Expected changes already applied not to apply again")
+	}
+	if _, err := os.Stat(filepath.Join(repo, "committed.txt")); !os.IsNotExist(err) {
+		t.Error("Expected a failed apply to change nothing")
+	}
+	if _, err := coord.ApplyToProject(g, ApplyOptions{Files: []string{"nope.txt"}}); err == nil {
+		t.Error("Expected an unchanged path to fail")
+	}
+}
@@ -0,0 +1,168 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// StatusArchived marks a goblin whose session and worktree are gone but
+// whose record, tasks, notes, transcript and work are kept
+const StatusArchived = "archived"
+
+// Events for archiving and restoring goblins
+const (
+	EventArchived   = "archived"
+	EventUnarchived = "unarchived"
+)
+
+// Files kept in a goblin's archive directory
+const (
+	archiveTranscript = "transcript.log"
+	archiveBundle     = "work.bundle"
+)
+
+// ArchivePath returns the directory holding an archived goblin's
+// transcript and bundle
+func (c *Coordinator) ArchivePath(g *Goblin) string {
+	return filepath.Join(c.cfg.ArchiveDir, g.ID)
+}
+
+// Archive frees a goblin's tmux session and worktree but keeps
+// everything needed to pick it up again: its record, tasks and notes, the
+// pane's scrollback as a transcript, and its commits (uncommitted work is
+// committed first) as a git bundle.
+func (c *Coordinator) Archive(nameOrID string) (*Goblin, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if goblin == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	if goblin.Status == StatusArchived {
+		return nil, fmt.Errorf("goblin %s is already archived", goblin.Name)
+	}
+	if !isGitRepo(goblin.ProjectPath) || !c.ownsWorktree(goblin.ProjectPath, goblin.WorktreePath) {
+		return nil, fmt.Errorf("goblin %s has no worktree of its own to archive; stop it instead", goblin.Name)
+	}
+
+	dir := c.ArchivePath(goblin)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	if active(goblin.Status) {
+		c.runHooks(HookPreStop, goblin)
+		c.recordOutputUsage(goblin)
+		c.saveTranscript(goblin, filepath.Join(dir, archiveTranscript))
+	}
+	c.killTmuxSession(c.Socket(goblin), goblin.TmuxSession)
+
+	if _, err := c.saveWork(goblin, ShutdownCommit); err != nil {
+		return nil, err
+	}
+	c.endTasks(goblin)
+	if err := bundleBranch(goblin, filepath.Join(dir, archiveBundle)); err != nil {
+		return nil, err
+	}
+
+	c.removeWorktree(goblin.ProjectPath, goblin.WorktreePath)
+
+	if err := c.db.UpdateGoblinStatus(goblin.ID, StatusArchived); err != nil {
+		return nil, err
+	}
+	goblin.Status = StatusArchived
+	c.recordEvent(goblin.ID, goblin.Name, EventArchived, "")
+	c.releaseLocks(goblin)
+	c.releaseOllama()
+	c.resumePreempted()
+
+	if c.log != nil {
+		c.log.Info("Archived goblin",
+			logging.String("name", goblin.Name),
+			logging.String("archive", dir))
+	}
+	return goblin, nil
+}
+
+// Unarchive checks an archived goblin's branch out into a fresh worktree,
+// restoring the branch from its bundle if it has since been deleted. The
+// goblin comes back stopped, with no session.
+func (c *Coordinator) Unarchive(nameOrID string) (*Goblin, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if goblin == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	if goblin.Status != StatusArchived {
+		return nil, fmt.Errorf("goblin %s is not archived", goblin.Name)
+	}
+
+	ref := "refs/heads/" + goblin.Branch
+	if gitRun(goblin.ProjectPath, "rev-parse", "--verify", "--quiet", ref) != nil {
+		bundle := filepath.Join(c.ArchivePath(goblin), archiveBundle)
+		if _, err := os.Stat(bundle); err != nil {
+			return nil, fmt.Errorf("branch %s no longer exists and %s has no bundle to restore it from", goblin.Branch, goblin.Name)
+		}
+		if err := gitRun(goblin.ProjectPath, "fetch", bundle, ref+":"+ref); err != nil {
+			return nil, fmt.Errorf("failed to restore %s from its bundle: %w", goblin.Branch, err)
+		}
+	}
+
+	// Forget the removed worktree so its path can be reused
+	c.worktreeRun(goblin.ProjectPath, "prune")
+	if _, err := c.createWorktree(goblin.ProjectPath, goblin.WorktreePath, goblin.Branch, ""); err != nil {
+		return nil, fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	if err := c.db.UpdateGoblinStatus(goblin.ID, "stopped"); err != nil {
+		return nil, err
+	}
+	goblin.Status = "stopped"
+	c.recordEvent(goblin.ID, goblin.Name, EventUnarchived, goblin.WorktreePath)
+	return goblin, nil
+}
+
+// saveTranscript writes the goblin's pane scrollback to path. A missing
+// transcript never blocks archiving.
+func (c *Coordinator) saveTranscript(g *Goblin, path string) {
+	mgr := tmux.NewManager(tmux.Config{
+		SocketName:   c.Socket(g),
+		HistoryLimit: c.cfg.Tmux.HistoryLimit,
+	})
+	captured, err := mgr.CaptureHistory(g.TmuxSession, 0)
+	if err == nil {
+		err = os.WriteFile(path, []byte(captured), 0644)
+	}
+	if err != nil && c.log != nil {
+		c.log.Warn("Failed to save transcript",
+			logging.String("name", g.Name),
+			logging.Err(err))
+	}
+}
+
+// bundleBranch writes the goblin's commits since it started to a git
+// bundle. A branch without new commits needs no bundle.
+func bundleBranch(g *Goblin, path string) error {
+	rev := "refs/heads/" + g.Branch
+	if g.BaseRef != "" {
+		out, err := trace.Command("git", "-C", g.WorktreePath, "rev-list", "--count", g.BaseRef+".."+rev).Output()
+		if err == nil && strings.TrimSpace(string(out)) == "0" {
+			return nil
+		}
+		rev = g.BaseRef + ".." + rev
+	}
+	if err := gitRun(g.WorktreePath, "bundle", "create", path, rev); err != nil {
+		return fmt.Errorf("failed to bundle the work of %s: %w", g.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestArchive(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-shelf"), "gforge/shelf", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-shelf", Name: "shelf", Agent: "claude", Status: "stopped",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/shelf", BaseRef: strings.TrimSpace(string(base)),
+		CreatedAt: now, UpdatedAt: now})
+
+	os.WriteFile(filepath.Join(worktree, "done.txt"), []byte("done\n"), 0644)
+	gitRun(worktree, "add", "done.txt")
+	gitRun(worktree, append(identityArgs(worktree), "commit", "-m", "Finish part one")...)
+	os.WriteFile(filepath.Join(worktree, "wip.txt"), []byte("wip\n"), 0644)
+
+	g, err := coord.Archive("shelf")
+	if err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	if g.Status != StatusArchived {
+		t.Errorf("Expected status %s, got %s", StatusArchived, g.Status)
+	}
+	if _, err := os.Stat(worktree); !os.IsNotExist(err) {
+		t.Error("Expected the worktree removed")
+	}
+	if _, err := os.Stat(filepath.Join(coord.ArchivePath(g), archiveBundle)); err != nil {
+		t.Errorf("Expected a bundle: %v", err)
+	}
+	if _, err := coord.Archive("shelf"); err == nil {
+		t.Error("Expected archiving twice to fail")
+	}
+	if err := coord.Stop("shelf"); err == nil {
+		t.Error("Expected stopping an archived goblin to fail")
+	}
+
+	// The bundle brings the branch back once it is gone
+	if err := gitRun(repo, "branch", "-D", "gforge/shelf"); err != nil {
+		t.Fatalf("Failed to delete branch: %v", err)
+	}
+
+	g, err = coord.Unarchive("shelf")
+	if err != nil {
+		t.Fatalf("Unarchive failed: %v", err)
+	}
+	if g.Status != "stopped" {
+		t.Errorf("Expected status stopped, got %s", g.Status)
+	}
+	for _, name := range []string{"done.txt", "wip.txt"} {
+		if _, err := os.Stat(filepath.Join(worktree, name)); err != nil {
+			t.Errorf("Expected %s restored: %v", name, err)
+		}
+	}
+	if _, err := coord.Unarchive("shelf"); err == nil {
+		t.Error("Expected unarchiving a goblin that is not archived to fail")
+	}
+
+	events, _ := coord.db.ListEvents(now.Add(-time.Minute))
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	// Archiving commits the work in progress, reporting the agent's commit
+	// along with it
+	want := []string{EventCommitted, EventCommitted, EventArchived, EventUnarchived}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v events, got %v", want, types)
+	}
+}
+
+func TestArchiveNeedsWorktree(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-plain", Name: "plain", Agent: "claude", Status: "stopped",
+		ProjectPath: dir, WorktreePath: dir, CreatedAt: now, UpdatedAt: now})
+
+	if _, err := coord.Archive("plain"); err == nil {
+		t.Error("Expected archiving a goblin without its own worktree to fail")
+	}
+}
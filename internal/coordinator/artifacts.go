@@ -0,0 +1,105 @@
+package coordinator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventArtifacts records files collected from a goblin's worktree
+const EventArtifacts = "artifacts"
+
+// Artifact is a file collected from a goblin's worktree
+type Artifact struct {
+	Path    string // Relative to the worktree
+	Size    int64
+	ModTime time.Time
+}
+
+// ArtifactsPath returns the directory holding a goblin's collected
+// artifacts, laid out as they were in its worktree
+func (c *Coordinator) ArtifactsPath(g *Goblin) string {
+	return filepath.Join(c.cfg.ArtifactsDir, g.ID)
+}
+
+// CollectArtifacts copies what matches patterns in a goblin's worktree
+// into its artifacts directory, replacing earlier copies, and returns
+// everything collected from it so far. Patterns are globs relative to the
+// worktree and a matching directory is copied whole. A pattern matching
+// nothing is skipped: a report a failed build never wrote is no error.
+func (c *Coordinator) CollectArtifacts(g *Goblin, patterns []string) ([]Artifact, error) {
+	dir := c.ArtifactsPath(g)
+	var collected []string
+	for _, pattern := range patterns {
+		pattern = filepath.Clean(pattern)
+		if filepath.IsAbs(pattern) || pattern == "." || strings.HasPrefix(pattern, "..") {
+			return nil, fmt.Errorf("artifact %s must be inside the worktree", pattern)
+		}
+
+		matches, err := filepath.Glob(filepath.Join(g.WorktreePath, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact pattern %s: %w", pattern, err)
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(g.WorktreePath, match)
+			if err != nil || rel == ".git" || strings.HasPrefix(rel, ".git"+string(filepath.Separator)) {
+				continue
+			}
+
+			dst := filepath.Join(dir, rel)
+			os.RemoveAll(dst)
+			if err := workspace.CopyPath(match, dst, c.cfg.Git.CopyMethod); err != nil {
+				return nil, fmt.Errorf("failed to collect artifact %s: %w", rel, err)
+			}
+			collected = append(collected, rel)
+		}
+	}
+
+	if len(collected) > 0 {
+		c.recordEvent(g.ID, g.Name, EventArtifacts, strings.Join(collected, ", "))
+		if c.log != nil {
+			c.log.Info("Collected artifacts",
+				logging.String("goblin", g.Name),
+				logging.Int("paths", len(collected)),
+				logging.String("dir", dir))
+		}
+	}
+	return c.Artifacts(g)
+}
+
+// Artifacts lists the files collected from a goblin, by path
+func (c *Coordinator) Artifacts(g *Goblin) ([]Artifact, error) {
+	dir := c.ArtifactsPath(g)
+	var artifacts []Artifact
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		artifacts = append(artifacts, Artifact{Path: rel, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Path < artifacts[j].Path })
+	return artifacts, nil
+}
@@ -0,0 +1,58 @@
+package coordinator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCollectArtifacts(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	worktree := t.TempDir()
+	os.WriteFile(filepath.Join(worktree, "coverage.out"), []byte("mode: set\n"), 0644)
+	os.MkdirAll(filepath.Join(worktree, "screenshots", "mobile"), 0755)
+	os.WriteFile(filepath.Join(worktree, "screenshots", "home.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(worktree, "screenshots", "mobile", "home.png"), []byte("png"), 0644)
+	g := &Goblin{ID: "id-1", Name: "cov", WorktreePath: worktree}
+
+	if artifacts, err := coord.Artifacts(g); err != nil || len(artifacts) != 0 {
+		t.Fatalf("Expected no artifacts before collecting, got %v, %v", artifacts, err)
+	}
+
+	for _, bad := range []string{"../secrets", "/etc/passwd", "[", "."} {
+		if _, err := coord.CollectArtifacts(g, []string{bad}); err == nil {
+			t.Errorf("Expected %s refused", bad)
+		}
+	}
+
+	artifacts, err := coord.CollectArtifacts(g, []string{"*.out", "screenshots", "dist/*"})
+	if err != nil {
+		t.Fatalf("CollectArtifacts failed: %v", err)
+	}
+	var paths []string
+	for _, a := range artifacts {
+		paths = append(paths, a.Path)
+	}
+	want := []string{"coverage.out", filepath.Join("screenshots", "home.png"), filepath.Join("screenshots", "mobile", "home.png")}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, paths)
+		}
+	}
+	if artifacts[0].Size != int64(len("mode: set\n")) {
+		t.Errorf("Expected coverage.out's size, got %d", artifacts[0].Size)
+	}
+
+	// Collecting again replaces the earlier copy
+	os.WriteFile(filepath.Join(worktree, "coverage.out"), []byte("mode: atomic\n"), 0644)
+	coord.CollectArtifacts(g, []string{"coverage.out"})
+	data, _ := os.ReadFile(filepath.Join(coord.ArtifactsPath(g), "coverage.out"))
+	if string(data) != "mode: atomic\n" {
+		t.Errorf("Expected the new coverage.out collected, got %q", data)
+	}
+}
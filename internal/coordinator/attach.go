@@ -0,0 +1,111 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// Attach modes for running `gforge attach` from inside tmux. Outside tmux
+// every mode attaches in the terminal.
+const (
+	AttachAuto   = "auto"   // switch on the same server, else window
+	AttachSwitch = "switch" // switch-client to the goblin's session
+	AttachWindow = "window" // open the session in a new window
+	AttachPopup  = "popup"  // open the session in a popup (tmux 3.2+)
+	AttachNest   = "nest"   // attach inside the current pane
+)
+
+// AttachCommand returns the tmux command that shows a goblin's session
+// with mode (tmux.attach_mode when empty), taking into account whether
+// gforge runs inside a tmux client
+func (c *Coordinator) AttachCommand(g *Goblin, mode string) (*trace.Cmd, error) {
+	if mode == "" {
+		mode = c.cfg.Tmux.AttachMode
+	}
+	if mode == "" {
+		mode = AttachAuto
+	}
+
+	socket := c.Socket(g)
+	attach := []string{"tmux", "-L", socket, "attach-session", "-t", g.TmuxSession}
+
+	client := os.Getenv("TMUX")
+	if client == "" {
+		return trace.Command(attach[0], attach[1:]...), nil
+	}
+
+	// $TMUX is "socket-path,server-pid,session-index"
+	clientSocket := strings.SplitN(client, ",", 2)[0]
+	sameServer := filepath.Clean(clientSocket) == filepath.Clean(tmux.SocketPath(socket))
+
+	// Commands for the user's own server; window and popup shells run a
+	// nested client, which tmux only allows with $TMUX unset
+	nested := "TMUX= " + shellJoin(attach)
+
+	switch mode {
+	case AttachAuto:
+		if sameServer {
+			return trace.Command("tmux", "switch-client", "-t", g.TmuxSession), nil
+		}
+		return trace.Command("tmux", "new-window", "-n", g.Name, nested), nil
+	case AttachSwitch:
+		if !sameServer {
+			return nil, fmt.Errorf("can't switch to %s: it runs on tmux socket %q, not this client's server; use --mode window or popup", g.Name, socket)
+		}
+		return trace.Command("tmux", "switch-client", "-t", g.TmuxSession), nil
+	case AttachWindow:
+		if sameServer {
+			// Share the goblin's window rather than nesting a client in it
+			return trace.Command("tmux", "link-window", "-s", g.TmuxSession+":"), nil
+		}
+		return trace.Command("tmux", "new-window", "-n", g.Name, nested), nil
+	case AttachPopup:
+		return trace.Command("tmux", "display-popup", "-E", "-w", "90%", "-h", "90%", nested), nil
+	case AttachNest:
+		cmd := trace.Command(attach[0], attach[1:]...)
+		cmd.Env = withoutEnv(os.Environ(), "TMUX")
+		return cmd, nil
+	}
+	return nil, fmt.Errorf("unknown attach mode: %s (use auto, switch, window, popup or nest)", mode)
+}
+
+// Output returns the last lines of a goblin's pane, oldest first, with
+// trailing blank rows dropped
+func (c *Coordinator) Output(g *Goblin, lines int) ([]string, error) {
+	mgr := tmux.NewManager(tmux.Config{SocketName: c.Socket(g)})
+	output, err := mgr.CapturePane(g.TmuxSession, lines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+	output = strings.TrimRight(output, "\n ")
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// shellJoin quotes args into one shell command line
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = workspace.ShellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// withoutEnv drops a variable from an environment list
+func withoutEnv(env []string, key string) []string {
+	kept := env[:0:0]
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, key+"=") {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
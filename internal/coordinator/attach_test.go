@@ -0,0 +1,60 @@
+package coordinator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+)
+
+func TestAttachCommand(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	g := &Goblin{Name: "fixer", TmuxSession: "gforge-abc"}
+	args := func(mode string) string {
+		t.Helper()
+		cmd, err := coord.AttachCommand(g, mode)
+		if err != nil {
+			t.Fatalf("AttachCommand(%q) failed: %v", mode, err)
+		}
+		return strings.Join(cmd.Args, " ")
+	}
+
+	t.Setenv("TMUX", "")
+	if got := args(AttachWindow); got != "tmux -L gforge-test-coord attach-session -t gforge-abc" {
+		t.Errorf("Expected a plain attach outside tmux, got %q", got)
+	}
+
+	// Inside a client of the goblin's own server
+	t.Setenv("TMUX", tmux.SocketPath(cfg.Tmux.SocketName)+",123,0")
+	if got := args(""); got != "tmux switch-client -t gforge-abc" {
+		t.Errorf("Expected auto to switch clients on the same server, got %q", got)
+	}
+	if got := args(AttachWindow); !strings.HasPrefix(got, "tmux link-window -s gforge-abc:") {
+		t.Errorf("Expected the window linked on the same server, got %q", got)
+	}
+
+	// Inside a client of the user's own server
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,123,0")
+	if got := args(AttachAuto); !strings.HasPrefix(got, "tmux new-window -n fixer TMUX= 'tmux' '-L'") {
+		t.Errorf("Expected auto to open a window with a nested client, got %q", got)
+	}
+	if got := args(AttachPopup); !strings.HasPrefix(got, "tmux display-popup -E") {
+		t.Errorf("Expected a popup, got %q", got)
+	}
+	if _, err := coord.AttachCommand(g, AttachSwitch); err == nil {
+		t.Error("Expected switch refused across servers")
+	}
+
+	cmd, _ := coord.AttachCommand(g, AttachNest)
+	for _, kv := range cmd.Env {
+		if strings.HasPrefix(kv, "TMUX=") {
+			t.Error("Expected a nested attach to run without $TMUX")
+		}
+	}
+
+	if _, err := coord.AttachCommand(g, "tab"); err == nil {
+		t.Error("Expected an unknown mode rejected")
+	}
+}
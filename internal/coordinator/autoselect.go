@@ -0,0 +1,126 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventSelected records the goblin of a fan-out run that auto-selection
+// promoted
+const EventSelected = "selected"
+
+// Candidate is a goblin of a fan-out run, measured for auto-selection
+type Candidate struct {
+	Goblin      *Goblin
+	TestsPassed bool
+	LintClean   bool // No checks, or all passed
+	Files       int  // Files its branch changes
+	Lines       int  // Lines its branch changes
+	Err         error
+}
+
+// Score ranks a candidate out of 100: passing tests dominate (60), then
+// clean checks (20) and a smaller diff (20). A candidate that changed
+// nothing or couldn't be measured scores 0.
+func (c *Candidate) Score() float64 {
+	if c.Err != nil || c.Files == 0 {
+		return 0
+	}
+
+	score := 0.0
+	if c.TestsPassed {
+		score += 60
+	}
+	if c.LintClean {
+		score += 20
+	}
+	return score + 20/(1+float64(c.Lines)/200)
+}
+
+// SelectBest returns the highest-scoring candidate whose tests passed,
+// the first one on a tie, or nil when none passed
+func SelectBest(candidates []*Candidate) *Candidate {
+	var best *Candidate
+	for _, c := range candidates {
+		if !c.TestsPassed || c.Score() == 0 {
+			continue
+		}
+		if best == nil || c.Score() > best.Score() {
+			best = c
+		}
+	}
+	return best
+}
+
+// WaitQuiet waits for each goblin's pane to go unchanged for idle, all
+// within timeout, and returns those still busy when it ran out
+func (c *Coordinator) WaitQuiet(goblins []*Goblin, idle, timeout time.Duration) []*Goblin {
+	poll := time.Second
+	if idle < 4*poll {
+		poll = idle / 4
+	}
+	deadline := time.Now().Add(timeout)
+
+	var busy []*Goblin
+	for _, g := range goblins {
+		mgr := tmux.NewManager(tmux.Config{SocketName: c.Socket(g)})
+		if !mgr.WaitIdle(g.TmuxSession, idle, time.Until(deadline), poll) {
+			busy = append(busy, g)
+		}
+	}
+	return busy
+}
+
+// Measure commits what a fan-out goblin has left uncommitted, then runs
+// the tests (done.tests) and checks of its project in its worktree and
+// measures its branch. Failing checks aren't sent to the goblin to fix.
+func (c *Coordinator) Measure(g *Goblin, project *config.ProjectConfig) *Candidate {
+	candidate := &Candidate{Goblin: g}
+	if _, err := c.CommitWork(g, fmt.Sprintf("gforge: work of %s", g.Name)); err != nil {
+		candidate.Err = err
+		return candidate
+	}
+
+	tests := config.ProjectChecks{Commands: project.Done.Tests, Timeout: project.Done.Timeout}
+	_, err := c.RunChecks(g, tests, project.Git.Env())
+	if err != nil && !errors.Is(err, errs.ErrChecksFailed) {
+		candidate.Err = err
+		return candidate
+	}
+	candidate.TestsPassed = err == nil && len(tests.Commands) > 0
+
+	checks := project.Checks
+	checks.FixTask = false
+	_, err = c.RunChecks(g, checks, project.Git.Env())
+	if err != nil && !errors.Is(err, errs.ErrChecksFailed) {
+		candidate.Err = err
+		return candidate
+	}
+	candidate.LintClean = err == nil
+
+	stat, err := workspace.BranchDiffStat(g.WorktreePath, g.BaseRef, c.DiffIgnore(g))
+	if err != nil {
+		candidate.Err = err
+		return candidate
+	}
+	candidate.Files, candidate.Lines = stat.Files, stat.Insertions+stat.Deletions
+	return candidate
+}
+
+// RecordSelected records that a candidate was promoted over the others
+func (c *Coordinator) RecordSelected(best *Candidate, of int) {
+	c.recordEvent(best.Goblin.ID, best.Goblin.Name, EventSelected,
+		fmt.Sprintf("best of %d (score %.0f)", of, best.Score()))
+	if c.log != nil {
+		c.log.Info("Selected fan-out goblin",
+			logging.String("goblin", best.Goblin.Name),
+			logging.Int("candidates", of))
+	}
+}
@@ -0,0 +1,82 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestSelectBest(t *testing.T) {
+	small := &Candidate{Goblin: &Goblin{Name: "small"}, TestsPassed: true, LintClean: true, Files: 1, Lines: 10}
+	big := &Candidate{Goblin: &Goblin{Name: "big"}, TestsPassed: true, LintClean: true, Files: 9, Lines: 900}
+	messy := &Candidate{Goblin: &Goblin{Name: "messy"}, TestsPassed: true, Files: 1, Lines: 10}
+	failing := &Candidate{Goblin: &Goblin{Name: "failing"}, LintClean: true, Files: 1, Lines: 5}
+	idle := &Candidate{Goblin: &Goblin{Name: "idle"}, TestsPassed: true, LintClean: true}
+
+	if small.Score() <= big.Score() || small.Score() <= messy.Score() {
+		t.Errorf("Expected a small clean diff to outscore a big or unlinted one: %.1f, %.1f, %.1f",
+			small.Score(), big.Score(), messy.Score())
+	}
+	if idle.Score() != 0 {
+		t.Errorf("Expected a goblin that changed nothing to score 0, got %.1f", idle.Score())
+	}
+
+	if best := SelectBest([]*Candidate{failing, big, small, messy}); best != small {
+		t.Errorf("Expected small selected, got %v", best)
+	}
+	if best := SelectBest([]*Candidate{failing, idle}); best != nil {
+		t.Errorf("Expected nothing selected without passing tests, got %v", best.Goblin.Name)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	worktree := filepath.Join(t.TempDir(), "ab-1")
+	if out, err := exec.Command("git", "-C", repo, "worktree", "add", "-b", "gforge/ab-1", worktree).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to add worktree: %v\n%s", err, out)
+	}
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "ab-1", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/ab-1", BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("ab-1")
+
+	// Left uncommitted, as an agent might
+	os.WriteFile(filepath.Join(worktree, "fix.txt"), []byte("one\ntwo\n"), 0644)
+
+	project := &config.ProjectConfig{
+		Done:   config.ProjectDone{Tests: []string{"test -f fix.txt"}},
+		Checks: config.ProjectChecks{Commands: []string{"false"}, FixTask: true},
+	}
+	c := coord.Measure(g, project)
+	if c.Err != nil {
+		t.Fatalf("Measure failed: %v", c.Err)
+	}
+	if !c.TestsPassed || c.LintClean || c.Files != 1 || c.Lines != 2 {
+		t.Errorf("Expected passing tests, failed lint and 1 file of 2 lines, got %+v", c)
+	}
+
+	// Failing checks aren't sent to a goblin being measured
+	tasks, _ := coord.db.ListTasks(g.ID)
+	if len(tasks) != 0 {
+		t.Errorf("Expected no fix task sent, got %v", tasks)
+	}
+
+	coord.RecordSelected(c, 3)
+	events, _ := coord.db.ListEvents(g.CreatedAt.AddDate(0, 0, -1))
+	if len(events) == 0 || events[len(events)-1].Type != EventSelected {
+		t.Errorf("Expected a selected event, got %v", events)
+	}
+}
@@ -0,0 +1,55 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// AddChangelog adds an entry for a goblin's work to the project's
+// changelog and commits it to the goblin's branch, returning the file
+// changed. Nothing is done without a changelog format, before the goblin
+// has commits, or when its entry is already there.
+func (c *Coordinator) AddChangelog(g *Goblin, changelog config.ProjectChangelog, gitEnv []string) (string, error) {
+	if changelog.Format == "" {
+		return "", nil
+	}
+
+	commits, err := workspace.BranchCommits(g.WorktreePath, g.BaseRef)
+	if err != nil || len(commits) == 0 {
+		return "", err
+	}
+	tasks, err := c.WorkTasks(g)
+	if err != nil {
+		return "", err
+	}
+
+	entry := workspace.NewChangelogEntry(changelog.Format, changelog.Type, g.Name, tasks, commits)
+	path, err := workspace.AddChangelogEntry(g.WorktreePath, changelog.Format, changelog.Path, entry)
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	if err := gitRun(g.WorktreePath, "add", "--", path); err != nil {
+		return "", fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	args := append([]string{"-C", g.WorktreePath}, identityArgs(g.WorktreePath)...)
+	args = append(args, "commit", "--no-verify", "-m", "Add changelog entry for "+g.Name, "--", path)
+	cmd := trace.Command("git", args...)
+	cmd.Env = append(os.Environ(), gitEnv...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to commit %s: %s", path, strings.TrimSpace(string(output)))
+	}
+
+	if c.log != nil {
+		c.log.Info("Added changelog entry",
+			logging.String("name", g.Name),
+			logging.String("file", path))
+	}
+	return path, nil
+}
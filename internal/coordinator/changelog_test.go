@@ -0,0 +1,61 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestAddChangelog(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "uploads", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: repo, BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("uploads")
+	changelog := config.ProjectChangelog{Format: "towncrier"}
+
+	// Nothing to record before the goblin commits
+	if path, err := coord.AddChangelog(g, changelog, nil); err != nil || path != "" {
+		t.Fatalf("Expected no entry without commits, got %q, %v", path, err)
+	}
+
+	coord.db.RecordTask("id-1", "[context] README.md")
+	coord.db.RecordTask("id-1", "Retry failed uploads")
+	os.WriteFile(filepath.Join(repo, "upload.go"), []byte("package main\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "fix: retry uploads").Run()
+
+	path, err := coord.AddChangelog(g, changelog, nil)
+	if err != nil {
+		t.Fatalf("AddChangelog failed: %v", err)
+	}
+	if path != filepath.Join("changelog.d", "uploads.bugfix.md") {
+		t.Errorf("Unexpected fragment %s", path)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo, path)); string(data) != "Retry failed uploads.\n" {
+		t.Errorf("Unexpected fragment content %q", data)
+	}
+
+	subject, _ := exec.Command("git", "-C", repo, "log", "-1", "--format=%s").Output()
+	if strings.TrimSpace(string(subject)) != "Add changelog entry for uploads" {
+		t.Errorf("Expected the entry committed, got %s", subject)
+	}
+
+	// A second push leaves the entry as it is
+	if path, err := coord.AddChangelog(g, changelog, nil); err != nil || path != "" {
+		t.Errorf("Expected the existing entry kept, got %q, %v", path, err)
+	}
+}
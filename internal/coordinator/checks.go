@@ -0,0 +1,128 @@
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// EventChecks records a run of a project's checks that failed
+const EventChecks = "checks"
+
+// FixChecksTask is how the task asking a goblin to fix failing checks is
+// recorded
+const FixChecksTask = "fix failing checks"
+
+var (
+	// checkTimeout bounds a check command without a project timeout
+	checkTimeout = 10 * time.Minute
+
+	// checkOutputLimit is how much of the end of a check's output is kept
+	checkOutputLimit = 16 * 1024
+)
+
+// RunChecks runs a project's check commands in a goblin's worktree and
+// stores the results on the goblin, replacing the last run. Every command
+// runs even after one fails, so the goblin hears about all the problems at
+// once. Failures return errs.ErrChecksFailed, and with checks.fix_task the
+// failing output is also sent to the goblin as a task.
+func (c *Coordinator) RunChecks(g *Goblin, checks config.ProjectChecks, gitEnv []string) ([]*storage.CheckResult, error) {
+	if len(checks.Commands) == 0 {
+		return nil, nil
+	}
+	timeout := checks.Timeout
+	if timeout <= 0 {
+		timeout = checkTimeout
+	}
+
+	var (
+		results []*storage.CheckResult
+		failed  []string
+	)
+	for _, command := range checks.Commands {
+		result := c.runCheck(g, command, timeout, gitEnv)
+		results = append(results, result)
+		if !result.Passed {
+			failed = append(failed, command)
+		}
+	}
+
+	if err := c.db.SaveCheckResults(g.ID, results); err != nil {
+		return results, err
+	}
+	if len(failed) == 0 {
+		return results, nil
+	}
+
+	c.recordEvent(g.ID, g.Name, EventChecks, "failed: "+strings.Join(failed, ", "))
+	if c.log != nil {
+		c.log.Warn("Checks failed",
+			logging.String("name", g.Name),
+			logging.String("commands", strings.Join(failed, ", ")))
+	}
+
+	if checks.FixTask {
+		if err := c.SendText(g.ID, fixTask(results), FixChecksTask); err != nil && c.log != nil {
+			c.log.Warn("Failed to send checks to goblin",
+				logging.String("name", g.Name),
+				logging.Err(err))
+		}
+	}
+
+	return results, fmt.Errorf("%w: %s", errs.ErrChecksFailed, strings.Join(failed, ", "))
+}
+
+// runCheck runs one check command like a project hook
+func (c *Coordinator) runCheck(g *Goblin, command string, timeout time.Duration, gitEnv []string) *storage.CheckResult {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = g.WorktreePath
+	cmd.Env = append(append(os.Environ(), hookEnv(g)...), gitEnv...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &storage.CheckResult{
+		GoblinID: g.ID,
+		Command:  command,
+		Passed:   err == nil,
+		Duration: time.Since(start),
+	}
+
+	text := output.String()
+	if len(text) > checkOutputLimit {
+		text = "...\n" + text[len(text)-checkOutputLimit:]
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		text += fmt.Sprintf("\n(timed out after %s)", timeout)
+	}
+	result.Output = strings.TrimSpace(text)
+	return result
+}
+
+// fixTask asks the goblin to fix the failing checks, quoting their output
+func fixTask(results []*storage.CheckResult) string {
+	var b strings.Builder
+	b.WriteString("These checks failed on your branch. Fix the problems they report, then commit:\n")
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		fmt.Fprintf(&b, "\n$ %s\n%s\n", r.Command, r.Output)
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package coordinator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestRunChecks(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "linted", Agent: "claude", Status: "running",
+		ProjectPath: dir, WorktreePath: dir})
+	g, _ := coord.Get("linted")
+
+	results, err := coord.RunChecks(g, config.ProjectChecks{
+		Commands: []string{"test \"$GFORGE_GOBLIN\" = linted", "echo 'main.go:3: unused x'; exit 1", "sleep 5"},
+		Timeout:  200 * time.Millisecond,
+	}, nil)
+	if !errors.Is(err, errs.ErrChecksFailed) {
+		t.Fatalf("Expected ErrChecksFailed, got %v", err)
+	}
+	if len(results) != 3 || !results[0].Passed || results[1].Passed || results[2].Passed {
+		t.Fatalf("Expected the first check to pass and the rest to fail, got %+v", results)
+	}
+	if results[1].Output != "main.go:3: unused x" {
+		t.Errorf("Expected the failing output kept, got %q", results[1].Output)
+	}
+	if !strings.Contains(results[2].Output, "timed out") || results[2].Duration > 3*time.Second {
+		t.Errorf("Expected the slow check cut off, got %q after %s", results[2].Output, results[2].Duration)
+	}
+
+	stored, _ := coord.db.ListCheckResults("id-1")
+	if len(stored) != 3 {
+		t.Errorf("Expected the results stored on the goblin, got %d", len(stored))
+	}
+
+	if _, err := coord.RunChecks(g, config.ProjectChecks{Commands: []string{"true"}}, nil); err != nil {
+		t.Errorf("Expected passing checks, got %v", err)
+	}
+	if stored, _ := coord.db.ListCheckResults("id-1"); len(stored) != 1 {
+		t.Errorf("Expected the last run to replace the previous one, got %d", len(stored))
+	}
+}
+
+func TestFixTask(t *testing.T) {
+	task := fixTask([]*storage.CheckResult{
+		{Command: "go vet ./...", Passed: true},
+		{Command: "golangci-lint run", Output: "main.go:3: unused x"},
+	})
+	if strings.Contains(task, "go vet") || !strings.Contains(task, "$ golangci-lint run\nmain.go:3: unused x") {
+		t.Errorf("Expected only the failing check quoted, got %q", task)
+	}
+}
@@ -0,0 +1,21 @@
+package coordinator
+
+import (
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// CodeOwners returns the CODEOWNERS owners of the files the commits on a
+// goblin's branch change since it was spawned, or nil when the project
+// has no CODEOWNERS file
+func (c *Coordinator) CodeOwners(g *Goblin) ([]string, error) {
+	co, err := workspace.LoadCodeOwners(g.WorktreePath)
+	if err != nil || co == nil {
+		return nil, err
+	}
+
+	files, err := workspace.BranchFiles(g.WorktreePath, g.BaseRef)
+	if err != nil {
+		return nil, err
+	}
+	return co.OwnersOf(files), nil
+}
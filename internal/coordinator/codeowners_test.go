@@ -0,0 +1,51 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestCodeOwners(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	os.MkdirAll(filepath.Join(repo, ".github"), 0755)
+	os.WriteFile(filepath.Join(repo, ".github", "CODEOWNERS"), []byte("* @acme/core\n/auth/ @acme/security\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add CODEOWNERS").Run()
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "owned", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: repo, BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("owned")
+
+	os.MkdirAll(filepath.Join(repo, "auth"), 0755)
+	os.WriteFile(filepath.Join(repo, "auth", "token.go"), []byte("package auth\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add auth").Run()
+
+	owners, err := coord.CodeOwners(g)
+	if err != nil {
+		t.Fatalf("CodeOwners failed: %v", err)
+	}
+	if strings.Join(owners, ",") != "@acme/security" {
+		t.Errorf("Expected the auth owners, got %v", owners)
+	}
+
+	// Without a CODEOWNERS file there is no one to ask
+	os.Remove(filepath.Join(repo, ".github", "CODEOWNERS"))
+	if owners, err := coord.CodeOwners(g); err != nil || owners != nil {
+		t.Errorf("Expected no owners, got %v, %v", owners, err)
+	}
+}
@@ -0,0 +1,164 @@
+package coordinator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/repomap"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+)
+
+// How long a freshly started agent must be quiet before it is sent its
+// first message, and the longest spawn waits for that
+var (
+	settleIdle    = 2 * time.Second
+	settleTimeout = 20 * time.Second
+	settlePoll    = 500 * time.Millisecond
+)
+
+// defaultRepoMapTokens bounds the repository map sent on spawn
+const defaultRepoMapTokens = 2000
+
+// contextPrompt asks the agent to read the project's onboarding files
+// that exist in its worktree. Files are referenced rather than pasted so
+// the first message stays small; the agent reads them itself.
+func contextPrompt(worktreePath string, files []string) string {
+	var found []string
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(worktreePath, f)); err == nil {
+			found = append(found, f)
+		}
+	}
+	if len(found) == 0 {
+		return ""
+	}
+
+	return "Before starting, read these files for project context: " + strings.Join(found, ", ") + "."
+}
+
+// buildRepoMap renders the worktree's repository map for a new goblin
+func buildRepoMap(worktreePath string, maxTokens int) string {
+	if maxTokens <= 0 {
+		maxTokens = defaultRepoMapTokens
+	}
+	m, err := repomap.Build(worktreePath, nil)
+	if err != nil || m.Files == 0 {
+		return ""
+	}
+	return m.Render(maxTokens)
+}
+
+// initialContextLabel is the task history entry for onboarding sent
+// without a task
+const initialContextLabel = "[context] onboarding"
+
+// initialMessage prepends the onboarding context to the spawn task
+func initialMessage(context, task string) string {
+	switch {
+	case context == "":
+		return task
+	case task == "":
+		return context + " Then wait for instructions."
+	default:
+		return context + " Then: " + task
+	}
+}
+
+// sendInitial gives a new goblin its onboarding context and first task
+// once the agent has settled. Failures are logged; the goblin is usable
+// without them.
+func (c *Coordinator) sendInitial(g *Goblin, task string, skipContext bool) {
+	var context, repoMap string
+	if !skipContext {
+		pc, err := config.LoadProject(g.ProjectPath)
+		if err != nil && c.log != nil {
+			c.log.Warn("Failed to load project settings", logging.Err(err))
+		}
+		if pc != nil {
+			context = contextPrompt(g.WorktreePath, pc.Context)
+			if pc.RepoMap {
+				repoMap = buildRepoMap(g.WorktreePath, pc.RepoMapTokens)
+			}
+		}
+	}
+
+	message := initialMessage(context, task)
+	if message == "" && repoMap == "" {
+		return
+	}
+
+	tmux.NewManager(tmux.Config{SocketName: c.Socket(g)}).
+		WaitIdle(g.TmuxSession, settleIdle, settleTimeout, settlePoll)
+
+	// Only the task goes in the task history; onboarding alone is kept
+	// as context so changelogs and summaries leave it out
+	label := task
+	if label == "" {
+		label = initialContextLabel
+	}
+
+	// The multi-line map has to be pasted; plain messages are typed
+	var err error
+	if repoMap != "" {
+		if message == "" {
+			message = initialMessage("The repository map below describes this project.", "")
+		}
+		err = c.SendText(g.ID, message+"\n\n"+repoMap, label)
+	} else {
+		err = c.sendTask(g.ID, message, label)
+	}
+	if err != nil && c.log != nil {
+		c.log.Warn("Failed to send initial message",
+			logging.String("name", g.Name),
+			logging.Err(err))
+	}
+}
+
+// ContextWindow returns an agent's context size in tokens, preferring the
+// general.context_windows setting over the built-in size. 0 means unknown.
+func (c *Coordinator) ContextWindow(agentName string) int {
+	if w, ok := c.cfg.General.ContextWindows[agentName]; ok {
+		return w
+	}
+	if a := agents.NewRegistry().Get(agentName); a != nil {
+		return a.ContextWindow
+	}
+	return 0
+}
+
+// PromptBudget is the largest prompt, in tokens, sent to an agent. A
+// quarter of the window is left for the agent's own instructions and its
+// reply. 0 means no limit.
+func (c *Coordinator) PromptBudget(agentName string) int {
+	w := c.ContextWindow(agentName)
+	return w - w/4
+}
+
+// fitPrompt truncates text that would overflow the goblin's context
+// window, keeping its beginning and end. Sizes are tokens.Count
+// estimates, not the agent's own tokenizer; the quarter PromptBudget
+// holds back also covers the estimate running low.
+func (c *Coordinator) fitPrompt(g *Goblin, text string) string {
+	budget := c.PromptBudget(g.Agent)
+	if budget <= 0 {
+		return text
+	}
+	count := tokens.Count(text)
+	if count <= budget {
+		return text
+	}
+
+	if c.log != nil {
+		c.log.Warn("Prompt exceeds the agent's context window, truncating",
+			logging.String("goblin", g.Name),
+			logging.Int("tokens", count),
+			logging.Int("budget", budget))
+	}
+	return tokens.TruncateMiddle(text, budget)
+}
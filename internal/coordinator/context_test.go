@@ -0,0 +1,147 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+)
+
+func TestContextPrompt(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# App\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "docs"), 0755)
+	os.WriteFile(filepath.Join(dir, "docs", "ARCHITECTURE.md"), []byte("# Design\n"), 0644)
+
+	got := contextPrompt(dir, []string{"README.md", "CONTRIBUTING.md", "docs/ARCHITECTURE.md"})
+	want := "Before starting, read these files for project context: README.md, docs/ARCHITECTURE.md."
+	if got != want {
+		t.Errorf("contextPrompt = %q, want %q", got, want)
+	}
+
+	if got := contextPrompt(dir, []string{"MISSING.md"}); got != "" {
+		t.Errorf("Expected empty prompt when no files exist, got %q", got)
+	}
+	if got := contextPrompt(dir, nil); got != "" {
+		t.Errorf("Expected empty prompt without context files, got %q", got)
+	}
+}
+
+func TestInitialMessage(t *testing.T) {
+	tests := []struct {
+		context, task, expected string
+	}{
+		{"", "", ""},
+		{"", "Fix the bug", "Fix the bug"},
+		{"Read README.md.", "", "Read README.md. Then wait for instructions."},
+		{"Read README.md.", "Fix the bug", "Read README.md. Then: Fix the bug"},
+	}
+
+	for _, tc := range tests {
+		if got := initialMessage(tc.context, tc.task); got != tc.expected {
+			t.Errorf("initialMessage(%q, %q) = %q, want %q", tc.context, tc.task, got, tc.expected)
+		}
+	}
+}
+
+func TestBuildRepoMap(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc Run() {}\n"), 0644)
+
+	out := buildRepoMap(dir, 0)
+	if !strings.Contains(out, "main.go (") || !strings.Contains(out, ": Run") {
+		t.Errorf("Unexpected repo map:\n%s", out)
+	}
+
+	if out := buildRepoMap(t.TempDir(), 0); out != "" {
+		t.Errorf("Expected no map for an empty worktree, got %q", out)
+	}
+}
+
+func TestPromptBudget(t *testing.T) {
+	cfg := &config.Config{General: config.GeneralConfig{
+		ContextWindows: map[string]int{"ollama": 4000},
+	}}
+	coord := New(nil, cfg, nil)
+
+	if got := coord.ContextWindow("ollama"); got != 4000 {
+		t.Errorf("Expected the configured window to win, got %d", got)
+	}
+	if got := coord.ContextWindow("claude"); got != 200000 {
+		t.Errorf("Expected the built-in claude window, got %d", got)
+	}
+	if got := coord.PromptBudget("ollama"); got != 3000 {
+		t.Errorf("Expected a budget of 3000, got %d", got)
+	}
+	if got := coord.PromptBudget("unknown"); got != 0 {
+		t.Errorf("Expected no budget for an unknown agent, got %d", got)
+	}
+}
+
+func TestFitPrompt(t *testing.T) {
+	cfg := &config.Config{General: config.GeneralConfig{
+		ContextWindows: map[string]int{"ollama": 400},
+	}}
+	coord := New(nil, cfg, nil)
+	g := &Goblin{Name: "coder", Agent: "ollama"}
+
+	short := "Fix the bug"
+	if got := coord.fitPrompt(g, short); got != short {
+		t.Errorf("Expected a short prompt unchanged, got %q", got)
+	}
+
+	long := "Instructions first." + strings.Repeat(" filler words here", 500) + " Latest output."
+	got := coord.fitPrompt(g, long)
+	if tokens.Count(got) > 300 {
+		t.Errorf("Expected the prompt within 300 tokens, got %d", tokens.Count(got))
+	}
+	if !strings.HasPrefix(got, "Instructions first.") || !strings.HasSuffix(got, "Latest output.") {
+		t.Errorf("Expected the beginning and end kept, got %q", got)
+	}
+
+	g.Agent = "unknown"
+	if got := coord.fitPrompt(g, long); got != long {
+		t.Error("Expected no truncation for an agent without a known window")
+	}
+}
+
+func TestSendInitialRecordsTask(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	idle, timeout := settleIdle, settleTimeout
+	settleIdle, settleTimeout = 0, 0
+	defer func() { settleIdle, settleTimeout = idle, timeout }()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# App\n"), 0644)
+	os.WriteFile(filepath.Join(dir, config.ProjectFile), []byte("context: [README.md]\n"), 0644)
+
+	session := "gforge-initial-test"
+	exec.Command("tmux", "-L", cfg.Tmux.SocketName, "new-session", "-d", "-s", session, "cat").Run()
+	defer exec.Command("tmux", "-L", cfg.Tmux.SocketName, "kill-session", "-t", session).Run()
+
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "wt", Agent: "claude", Status: "running",
+		ProjectPath: dir, WorktreePath: dir, TmuxSession: session})
+	g, _ := coord.Get("wt")
+
+	coord.sendInitial(g, "Fix the login bug", false)
+	coord.sendInitial(g, "", false)
+
+	tasks, err := coord.WorkTasks(g)
+	if err != nil {
+		t.Fatalf("WorkTasks failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0] != "Fix the login bug" {
+		t.Errorf("Expected only the user's task recorded, got %q", tasks)
+	}
+}
@@ -1,17 +1,26 @@
 package coordinator
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/astoreyai/goblin-forge/internal/agents"
 	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
 	"github.com/astoreyai/goblin-forge/internal/logging"
 	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/webhook"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
 	"github.com/google/uuid"
 )
 
@@ -20,15 +29,23 @@ type Coordinator struct {
 	db  *storage.DB
 	cfg *config.Config
 	log *logging.Logger
+
+	// lifecycle carries spawns, stops, failures and commits to outbound
+	// webhooks and anything else subscribed
+	lifecycle *agents.LifecycleManager
 }
 
 // New creates a new coordinator
 func New(db *storage.DB, cfg *config.Config, log *logging.Logger) *Coordinator {
-	return &Coordinator{
-		db:  db,
-		cfg: cfg,
-		log: log,
+	c := &Coordinator{
+		db:        db,
+		cfg:       cfg,
+		log:       log,
+		lifecycle: agents.NewLifecycleManager(),
 	}
+	webhook.NewSender(cfg, log).Subscribe(c.lifecycle)
+	c.lifecycle.OnEvent(c.postSlack)
+	return c
 }
 
 // SpawnOptions contains options for spawning a goblin
@@ -38,6 +55,16 @@ type SpawnOptions struct {
 	ProjectPath string
 	Branch      string
 	Task        string
+	DevEnv      string // off, auto, devcontainer, nix (defaults to config)
+	BaseRef     string // Commit to start the branch from (defaults to HEAD)
+	Record      bool   // Record the pane to an asciinema cast (or tmux.record)
+	NoContext   bool   // Skip the onboarding context from .gforge.yaml
+	Priority    string // high, normal (default) or low, for queued spawns
+	TmuxSocket  string // Socket for the session (defaults to project/config)
+	Squad       string // Scratchpad to share (defaults to the project's)
+
+	// AllowProtected permits a branch matching git.protected_branches
+	AllowProtected bool
 }
 
 // Goblin represents a running agent instance
@@ -50,14 +77,44 @@ type Goblin struct {
 	WorktreePath string
 	Branch       string
 	TmuxSession  string
+	TmuxSocket   string
+	BaseRef      string
+	Priority     string
+	Pinned       bool
+	Squad        string
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
 
+// newGoblin converts a stored goblin
+func newGoblin(g *storage.Goblin) *Goblin {
+	return &Goblin{
+		ID:           g.ID,
+		Name:         g.Name,
+		Agent:        g.Agent,
+		Status:       g.Status,
+		ProjectPath:  g.ProjectPath,
+		WorktreePath: g.WorktreePath,
+		Branch:       g.Branch,
+		TmuxSession:  g.TmuxSession,
+		TmuxSocket:   g.TmuxSocket,
+		BaseRef:      g.BaseRef,
+		Priority:     g.Priority,
+		Pinned:       g.Pinned,
+		Squad:        g.Squad,
+		CreatedAt:    g.CreatedAt,
+		UpdatedAt:    g.UpdatedAt,
+	}
+}
+
 // Age returns a human-readable age string
 func (g *Goblin) Age() string {
-	duration := time.Since(g.CreatedAt)
+	return FormatDuration(time.Since(g.CreatedAt))
+}
 
+// FormatDuration renders a duration as briefly as Age does: 45s, 12m,
+// 3h 20m or 2d
+func FormatDuration(duration time.Duration) string {
 	if duration < time.Minute {
 		return fmt.Sprintf("%ds", int(duration.Seconds()))
 	} else if duration < time.Hour {
@@ -81,27 +138,122 @@ func (c *Coordinator) Spawn(opts SpawnOptions) (*Goblin, error) {
 		return nil, fmt.Errorf("goblin with name '%s' already exists", opts.Name)
 	}
 
+	priority, err := ParsePriority(opts.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := config.LoadProject(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.AllowProtected {
+		if err := c.BranchPolicy(project).Check("create", opts.Branch); err != nil {
+			return nil, err
+		}
+	}
+	socket, err := c.tmuxSocket(opts.TmuxSocket, opts.ProjectPath, project, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find everything that would fail midway before creating anything
+	if err := c.preflight(opts, project, socket); err != nil {
+		return nil, err
+	}
+
+	// In-progress edits in the project would be invisible to the goblin
+	changes, err := c.checkBase(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for a goblin (and GPU) slot before allocating anything
+	release, err := c.admit(opts.Agent, opts.Name, priority)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// Generate IDs
 	goblinID := uuid.New().String()[:8]
 	tmuxSession := fmt.Sprintf("gforge-%s", goblinID)
 
+	// Resolve the project's development environment
+	devEnvMode := opts.DevEnv
+	if devEnvMode == "" {
+		devEnvMode = c.cfg.General.DevEnv
+	}
+	devEnv, err := workspace.ResolveDevEnv(devEnvMode, opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create git worktree
-	worktreePath, err := c.createWorktree(opts.ProjectPath, goblinID, opts.Branch)
+	worktreePath, err := c.worktreePath(opts.ProjectPath, project, goblinID, opts.Name, opts.Branch)
+	if err != nil {
+		return nil, err
+	}
+	worktreePath, err = c.createWorktree(opts.ProjectPath, worktreePath, opts.Branch, opts.BaseRef)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create worktree: %w", err)
 	}
+	if changes != nil {
+		c.carryChanges(changes, opts.ProjectPath, worktreePath, opts.Name, opts.BaseRef)
+	}
+	if isGitRepo(opts.ProjectPath) {
+		c.copyPaths(opts.ProjectPath, worktreePath, project.CopyPaths)
+	}
+	baseRef := headCommit(worktreePath)
 
-	// Create tmux session
-	if err := c.createTmuxSession(tmuxSession, worktreePath); err != nil {
+	// Project setup (dependency installs, env files) runs before the agent
+	setup := &Goblin{
+		ID:           goblinID,
+		Name:         opts.Name,
+		ProjectPath:  opts.ProjectPath,
+		WorktreePath: worktreePath,
+		Branch:       opts.Branch,
+	}
+	c.runHooks(HookPostSpawn, setup)
+
+	// Create tmux session, with the project's git identity and credentials,
+	// telling gforge commands run by the agent which goblin they are for
+	if err := c.createTmuxSession(socket, tmuxSession, worktreePath, append(project.Git.Env(), hookEnv(setup)...)); err != nil {
 		// Cleanup worktree on failure
-		c.removeWorktree(worktreePath)
+		c.removeWorktree(opts.ProjectPath, worktreePath)
 		return nil, fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
+	// Recording is best effort; a missing cast never blocks the agent
+	if opts.Record || c.cfg.Tmux.Record {
+		castPath := c.recordingPath(opts.Name, goblinID)
+		if err := c.startRecording(socket, tmuxSession, opts.Name, castPath); err != nil && c.log != nil {
+			c.log.Warn("Failed to start recording",
+				logging.String("name", opts.Name),
+				logging.Err(err))
+		}
+	}
+
+	// Local models need a server before the agent starts
+	if err := c.ensureOllama(opts.Agent); err != nil {
+		c.killTmuxSession(socket, tmuxSession)
+		c.removeWorktree(opts.ProjectPath, worktreePath)
+		return nil, err
+	}
+
+	// Queue behind the agent's rate limit
+	if err := c.throttle(&Goblin{Name: opts.Name}, opts.Agent.Name); err != nil {
+		c.killTmuxSession(socket, tmuxSession)
+		c.removeWorktree(opts.ProjectPath, worktreePath)
+		c.releaseOllama()
+		return nil, err
+	}
+
 	// Start the agent in tmux
-	if err := c.startAgent(tmuxSession, opts.Agent, worktreePath); err != nil {
-		c.killTmuxSession(tmuxSession)
-		c.removeWorktree(worktreePath)
+	if err := c.startAgent(socket, tmuxSession, opts.Agent, worktreePath, devEnv); err != nil {
+		c.killTmuxSession(socket, tmuxSession)
+		c.removeWorktree(opts.ProjectPath, worktreePath)
+		c.releaseOllama()
 		return nil, fmt.Errorf("failed to start agent: %w", err)
 	}
 
@@ -115,14 +267,21 @@ func (c *Coordinator) Spawn(opts SpawnOptions) (*Goblin, error) {
 		WorktreePath: worktreePath,
 		Branch:       opts.Branch,
 		TmuxSession:  tmuxSession,
+		TmuxSocket:   socket,
+		BaseRef:      baseRef,
+		Priority:     priority,
+		Squad:        opts.Squad,
 	}
 
 	if err := c.db.CreateGoblin(goblin); err != nil {
-		c.killTmuxSession(tmuxSession)
-		c.removeWorktree(worktreePath)
+		c.killTmuxSession(socket, tmuxSession)
+		c.removeWorktree(opts.ProjectPath, worktreePath)
+		c.releaseOllama()
 		return nil, fmt.Errorf("failed to save goblin: %w", err)
 	}
 
+	c.recordEvent(goblinID, opts.Name, EventSpawned, opts.Agent.Name)
+
 	if c.log != nil {
 		c.log.Info("Spawned goblin",
 			logging.String("name", opts.Name),
@@ -130,7 +289,7 @@ func (c *Coordinator) Spawn(opts SpawnOptions) (*Goblin, error) {
 			logging.String("branch", opts.Branch))
 	}
 
-	return &Goblin{
+	spawned := &Goblin{
 		ID:           goblinID,
 		Name:         opts.Name,
 		Agent:        opts.Agent.Name,
@@ -139,59 +298,142 @@ func (c *Coordinator) Spawn(opts SpawnOptions) (*Goblin, error) {
 		WorktreePath: worktreePath,
 		Branch:       opts.Branch,
 		TmuxSession:  tmuxSession,
+		TmuxSocket:   socket,
+		BaseRef:      baseRef,
+		Priority:     priority,
+		Squad:        opts.Squad,
 		CreatedAt:    time.Now(),
-	}, nil
+	}
+
+	c.sendInitial(spawned, opts.Task, opts.NoContext)
+
+	return spawned, nil
 }
 
-// createWorktree creates a git worktree for isolation
-func (c *Coordinator) createWorktree(projectPath, goblinID, branch string) (string, error) {
-	worktreePath := filepath.Join(c.cfg.WorktreeBase, goblinID)
+// createWorktree creates a git worktree for isolation at worktreePath,
+// branching from baseRef when given
+func (c *Coordinator) createWorktree(projectPath, worktreePath, branch, baseRef string) (string, error) {
+	if _, err := os.Stat(worktreePath); err == nil {
+		return "", c.worktreeTaken(projectPath, worktreePath)
+	}
 
-	// Check if project is a git repo
-	gitDir := filepath.Join(projectPath, ".git")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		// Not a git repo, just create a symlink or copy
-		if err := os.MkdirAll(worktreePath, 0755); err != nil {
-			return "", err
-		}
-		// For non-git projects, we'll work in the original directory
-		return projectPath, nil
+	// Projects outside git get a copied workspace (see git.non_git)
+	if !isGitRepo(projectPath) {
+		return c.isolateProject(projectPath, worktreePath, branch)
 	}
 
 	// Create worktree with new branch
-	cmd := exec.Command("git", "-C", projectPath, "worktree", "add", "-b", branch, worktreePath)
-	output, err := cmd.CombinedOutput()
+	args := []string{"add", "-b", branch, worktreePath}
+	if baseRef != "" {
+		args = append(args, baseRef)
+	}
+	output, err := workspace.GitWorktree(projectPath, c.log, args...)
 	if err != nil {
 		// Branch might already exist, try without -b
-		cmd = exec.Command("git", "-C", projectPath, "worktree", "add", worktreePath, branch)
-		output, err = cmd.CombinedOutput()
+		output, err = workspace.GitWorktree(projectPath, c.log, "add", worktreePath, branch)
 		if err != nil {
-			return "", fmt.Errorf("git worktree add failed: %s\n%s", err, string(output))
+			return "", c.worktreeAddFailed(projectPath, branch, err, string(output))
+		}
+	}
+
+	// Submodules are best effort: the goblin can still work on the rest
+	if c.cfg.Git.Submodules {
+		if err := workspace.UpdateSubmodules(worktreePath, c.cfg.Git.SubmoduleDepth); err != nil && c.log != nil {
+			c.log.Warn("Failed to check out submodules",
+				logging.String("worktree", worktreePath),
+				logging.Err(err))
+		}
+	}
+	if c.cfg.Git.LFS {
+		if err := workspace.SetupLFS(worktreePath); err != nil && c.log != nil {
+			c.log.Warn("Failed to fetch Git LFS content",
+				logging.String("worktree", worktreePath),
+				logging.Err(err))
 		}
 	}
 
 	return worktreePath, nil
 }
 
-// removeWorktree removes a git worktree
-func (c *Coordinator) removeWorktree(worktreePath string) error {
-	// Find the main repo to run git worktree remove
-	cmd := exec.Command("git", "-C", worktreePath, "worktree", "remove", worktreePath, "--force")
-	cmd.Run() // Ignore errors
+// BranchPolicy returns the protected branches for a project: the global
+// git.protected_branches plus any from its .gforge.yaml
+func (c *Coordinator) BranchPolicy(project *config.ProjectConfig) workspace.BranchPolicy {
+	protected := append([]string(nil), c.cfg.Git.ProtectedBranches...)
+	if project != nil {
+		protected = append(protected, project.ProtectedBranches...)
+	}
+	return workspace.BranchPolicy{Protected: protected}
+}
+
+// DiffIgnore returns the patterns from a goblin's .gforge.yaml for files
+// left out of its change counts and diffs; none when the file can't be
+// read
+func (c *Coordinator) DiffIgnore(g *Goblin) workspace.Ignore {
+	project, err := config.LoadProject(g.ProjectPath)
+	if err != nil {
+		return nil
+	}
+	return workspace.Ignore(project.Ignore)
+}
+
+// headCommit returns the full commit hash checked out in a directory
+func headCommit(path string) string {
+	output, err := trace.Command("git", "-C", path, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// removeWorktree removes a git worktree. Only directories under a
+// worktree base are removed, so a goblin working directly in a project
+// never deletes it.
+func (c *Coordinator) removeWorktree(projectPath, worktreePath string) error {
+	if !c.ownsWorktree(projectPath, worktreePath) {
+		return nil
+	}
+
+	if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+		return nil
+	}
+
+	output, err := workspace.GitWorktree(projectPath, c.log, "remove", worktreePath, "--force")
+	if err != nil && c.log != nil {
+		c.log.Warn("Failed to remove worktree; deleting its directory",
+			logging.String("worktree", worktreePath),
+			logging.String("output", strings.TrimSpace(string(output))))
+	}
 
 	// Also try to remove the directory if it still exists
 	os.RemoveAll(worktreePath)
 	return nil
 }
 
-// createTmuxSession creates a new tmux session
-func (c *Coordinator) createTmuxSession(sessionName, workdir string) error {
-	socketName := c.cfg.Tmux.SocketName
-
-	cmd := exec.Command("tmux", "-L", socketName,
-		"new-session", "-d", "-s", sessionName, "-c", workdir)
+// createTmuxSession creates a new tmux session on socketName
+func (c *Coordinator) createTmuxSession(socketName, sessionName, workdir string, env []string) error {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return errs.WithHint(fmt.Errorf("%w: %v", errs.ErrTmuxUnavailable, err), tmux.InstallHint)
+	}
+	// Another user's socket, or an unsafe directory, fails in tmux with
+	// errors that don't say what to do
+	if err := tmux.CheckSocket(socketName).Err(); err != nil {
+		return err
+	}
 
-	output, err := cmd.CombinedOutput()
+	args := []string{"-L", socketName}
+	// history-limit only applies to panes created after it is set, so set
+	// it on the server before the session's pane exists
+	if limit := c.cfg.Tmux.HistoryLimit; limit > 0 {
+		args = append(args, "start-server", ";",
+			"set-option", "-g", "history-limit", strconv.Itoa(limit), ";")
+	}
+	args = append(args, "new-session", "-d", "-s", sessionName, "-c", workdir)
+	for _, kv := range env {
+		args = append(args, "-e", kv)
+	}
+	output, err := trace.Retry(trace.DefaultBackoff, c.log, func() *trace.Cmd {
+		return trace.Command("tmux", args...)
+	})
 	if err != nil {
 		return fmt.Errorf("tmux new-session failed: %s\n%s", err, string(output))
 	}
@@ -199,28 +441,103 @@ func (c *Coordinator) createTmuxSession(sessionName, workdir string) error {
 	return nil
 }
 
-// killTmuxSession kills a tmux session
-func (c *Coordinator) killTmuxSession(sessionName string) error {
-	socketName := c.cfg.Tmux.SocketName
+// killTmuxSession kills a tmux session on socketName. A session that has
+// already ended is no failure.
+func (c *Coordinator) killTmuxSession(socketName, sessionName string) error {
+	output, err := trace.Retry(trace.DefaultBackoff, c.log, func() *trace.Cmd {
+		return trace.Command("tmux", "-L", socketName, "kill-session", "-t", sessionName)
+	})
+	if err != nil && !tmux.SessionGone(string(output)) && c.log != nil {
+		c.log.Warn("Failed to kill tmux session",
+			logging.String("session", sessionName),
+			logging.String("output", strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// startRecording pipes the session's pane output into a hidden
+// `gforge record-pane` process that writes an asciinema cast
+func (c *Coordinator) startRecording(socketName, sessionName, title, castPath string) error {
+	if err := os.MkdirAll(filepath.Dir(castPath), 0755); err != nil {
+		return fmt.Errorf("failed to create recordings directory: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate gforge binary: %w", err)
+	}
+
+	width, height := 80, 24
+	output, err := trace.Command("tmux", "-L", socketName, "display-message", "-p",
+		"-t", sessionName, "#{pane_width} #{pane_height}").Output()
+	if err == nil {
+		fmt.Sscanf(strings.TrimSpace(string(output)), "%d %d", &width, &height)
+	}
+
+	pipe := fmt.Sprintf("exec %s record-pane --width %d --height %d --title %s %s",
+		workspace.ShellQuote(exe), width, height,
+		workspace.ShellQuote(title), workspace.ShellQuote(castPath))
+
+	cmd := trace.Command("tmux", "-L", socketName, "pipe-pane", "-o", "-t", sessionName, pipe)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux pipe-pane failed: %s\n%s", err, string(output))
+	}
 
-	cmd := exec.Command("tmux", "-L", socketName, "kill-session", "-t", sessionName)
-	cmd.Run() // Ignore errors
 	return nil
 }
 
-// startAgent starts the agent CLI in the tmux session
-func (c *Coordinator) startAgent(sessionName string, agent *agents.Agent, workdir string) error {
-	socketName := c.cfg.Tmux.SocketName
+// recordingPath returns where a goblin's cast is written
+func (c *Coordinator) recordingPath(name, goblinID string) string {
+	return filepath.Join(c.cfg.RecordingsDir, fmt.Sprintf("%s-%s.cast", name, goblinID))
+}
+
+// FindRecording returns the cast file for a goblin. Goblins that have
+// been killed are matched by name, newest recording first.
+func (c *Coordinator) FindRecording(nameOrID string) (string, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []string
+	if goblin != nil {
+		candidates = []string{c.recordingPath(goblin.Name, goblin.ID)}
+	} else {
+		// IDs are always 8 characters
+		candidates, _ = filepath.Glob(filepath.Join(c.cfg.RecordingsDir, nameOrID+"-????????.cast"))
+	}
+
+	var newest string
+	var newestTime time.Time
+	for _, path := range candidates {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestTime) {
+			newest, newestTime = path, info.ModTime()
+		}
+	}
+
+	if newest == "" {
+		return "", fmt.Errorf("no recording found for %s (spawn with --record or set tmux.record)", nameOrID)
+	}
+	return newest, nil
+}
 
+// startAgent starts the agent CLI in the tmux session, inside devEnv if set
+func (c *Coordinator) startAgent(socketName, sessionName string, agent *agents.Agent, workdir string, devEnv *workspace.DevEnv) error {
 	// Build command string
 	cmdParts := agent.GetCommand()
 	cmdStr := strings.Join(cmdParts, " ")
+	if devEnv != nil {
+		cmdStr = devEnv.WrapCommand(cmdStr, workdir)
+	}
 
 	// Send the command to tmux
-	cmd := exec.Command("tmux", "-L", socketName,
-		"send-keys", "-t", sessionName, cmdStr, "Enter")
-
-	output, err := cmd.CombinedOutput()
+	output, err := trace.Retry(trace.DefaultBackoff, c.log, func() *trace.Cmd {
+		return trace.Command("tmux", "-L", socketName, "send-keys", "-t", sessionName, cmdStr, "Enter")
+	})
 	if err != nil {
 		return fmt.Errorf("tmux send-keys failed: %s\n%s", err, string(output))
 	}
@@ -228,7 +545,7 @@ func (c *Coordinator) startAgent(sessionName string, agent *agents.Agent, workdi
 	return nil
 }
 
-// List returns all goblins
+// List returns all goblins, pinned ones first
 func (c *Coordinator) List() ([]*Goblin, error) {
 	dbGoblins, err := c.db.ListGoblins()
 	if err != nil {
@@ -237,23 +554,53 @@ func (c *Coordinator) List() ([]*Goblin, error) {
 
 	goblins := make([]*Goblin, len(dbGoblins))
 	for i, g := range dbGoblins {
-		goblins[i] = &Goblin{
-			ID:           g.ID,
-			Name:         g.Name,
-			Agent:        g.Agent,
-			Status:       g.Status,
-			ProjectPath:  g.ProjectPath,
-			WorktreePath: g.WorktreePath,
-			Branch:       g.Branch,
-			TmuxSession:  g.TmuxSession,
-			CreatedAt:    g.CreatedAt,
-			UpdatedAt:    g.UpdatedAt,
-		}
+		goblins[i] = newGoblin(g)
 	}
 
 	return goblins, nil
 }
 
+// StatusDead marks a goblin whose status says it should have a tmux
+// session but the session is gone (the agent or tmux server exited)
+const StatusDead = "dead"
+
+// CheckSessions sets StatusDead on goblins whose tmux session has ended,
+// with one tmux call per socket. The stored status is left alone. Goblins
+// on a socket tmux fails to list keep their status, and the failure is
+// returned.
+func (c *Coordinator) CheckSessions(goblins []*Goblin) error {
+	live := make(map[string]map[string]tmux.SessionInfo)
+	var failed error
+	for _, g := range goblins {
+		if g.TmuxSession == "" {
+			continue
+		}
+		switch g.Status {
+		case "running", "paused", StatusPreempted, StatusThrottled:
+		default:
+			continue
+		}
+
+		socket := c.Socket(g)
+		sessions, ok := live[socket]
+		if !ok {
+			var err error
+			sessions, err = tmux.NewManager(tmux.Config{SocketName: socket}).Sessions()
+			if err != nil && failed == nil {
+				failed = err
+			}
+			live[socket] = sessions
+		}
+		if sessions == nil {
+			continue
+		}
+		if _, ok := sessions[g.TmuxSession]; !ok {
+			g.Status = StatusDead
+		}
+	}
+	return failed
+}
+
 // Get retrieves a goblin by name or ID
 func (c *Coordinator) Get(nameOrID string) (*Goblin, error) {
 	g, err := c.db.GetGoblin(nameOrID)
@@ -264,38 +611,131 @@ func (c *Coordinator) Get(nameOrID string) (*Goblin, error) {
 		return nil, nil
 	}
 
-	return &Goblin{
-		ID:           g.ID,
-		Name:         g.Name,
-		Agent:        g.Agent,
-		Status:       g.Status,
-		ProjectPath:  g.ProjectPath,
-		WorktreePath: g.WorktreePath,
-		Branch:       g.Branch,
-		TmuxSession:  g.TmuxSession,
-		CreatedAt:    g.CreatedAt,
-		UpdatedAt:    g.UpdatedAt,
-	}, nil
+	return newGoblin(g), nil
 }
 
-// Stop stops a running goblin
-func (c *Coordinator) Stop(nameOrID string) error {
+// FindByPath returns the goblin whose worktree contains path, or nil if
+// path is outside every worktree. The deepest matching worktree wins, so
+// goblins sharing a non-git project directory resolve to the newest.
+func (c *Coordinator) FindByPath(path string) (*Goblin, error) {
+	abs, err := canonicalPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	goblins, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var best *Goblin
+	bestLen := -1
+	for _, g := range goblins {
+		if g.WorktreePath == "" {
+			continue
+		}
+		root, err := canonicalPath(g.WorktreePath)
+		if err != nil {
+			continue
+		}
+		if abs != root && !strings.HasPrefix(abs, root+string(filepath.Separator)) {
+			continue
+		}
+		if len(root) > bestLen {
+			best, bestLen = g, len(root)
+		}
+	}
+
+	return best, nil
+}
+
+// canonicalPath makes a path absolute and resolves symlinks where the
+// path exists
+func canonicalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	}
+	return abs, nil
+}
+
+// GetByIndex returns the goblin at a 1-based position in List order
+func (c *Coordinator) GetByIndex(index int) (*Goblin, error) {
+	g, err := c.db.GetGoblinByIndex(index)
+	if err != nil || g == nil {
+		return nil, err
+	}
+	return newGoblin(g), nil
+}
+
+// SetAlias gives a goblin a short name usable anywhere a goblin name is.
+// Aliases may not be numeric (those are list indexes) or shadow a name.
+func (c *Coordinator) SetAlias(alias, nameOrID string) error {
+	if alias == "" || alias == "." {
+		return fmt.Errorf("invalid alias: %q", alias)
+	}
+	if _, err := strconv.Atoi(alias); err == nil {
+		return fmt.Errorf("alias %s is numeric and would shadow a list index", alias)
+	}
+
+	existing, err := c.db.GetGoblin(alias)
+	if err != nil {
+		return err
+	}
+	if existing != nil && (existing.Name == alias || existing.ID == alias) {
+		return fmt.Errorf("alias %s is already a goblin name", alias)
+	}
+
 	goblin, err := c.Get(nameOrID)
 	if err != nil {
 		return err
 	}
 	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", nameOrID)
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
 	}
 
-	// Kill tmux session
-	c.killTmuxSession(goblin.TmuxSession)
+	return c.db.SetAlias(alias, goblin.ID)
+}
 
-	// Remove worktree (optional - could keep for review)
-	// c.removeWorktree(goblin.WorktreePath)
+// RemoveAlias deletes an alias
+func (c *Coordinator) RemoveAlias(alias string) error {
+	return c.db.DeleteAlias(alias)
+}
 
-	// Update status
-	if err := c.db.UpdateGoblinStatus(goblin.ID, "stopped"); err != nil {
+// Aliases returns aliases grouped by goblin ID
+func (c *Coordinator) Aliases() (map[string][]string, error) {
+	all, err := c.db.ListAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	byGoblin := make(map[string][]string)
+	for alias, goblinID := range all {
+		byGoblin[goblinID] = append(byGoblin[goblinID], alias)
+	}
+	for _, aliases := range byGoblin {
+		sort.Strings(aliases)
+	}
+	return byGoblin, nil
+}
+
+// Stop stops a running goblin
+func (c *Coordinator) Stop(nameOrID string) error {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	if goblin.Status == StatusArchived {
+		return fmt.Errorf("goblin %s is archived; unarchive it first", goblin.Name)
+	}
+
+	if err := c.stop(goblin, "stopped", EventStopped); err != nil {
 		return err
 	}
 
@@ -308,6 +748,31 @@ func (c *Coordinator) Stop(nameOrID string) error {
 	return nil
 }
 
+// stop ends a goblin's session and leaves it with status, recording event
+func (c *Coordinator) stop(goblin *Goblin, status, event string) error {
+	c.runHooks(HookPreStop, goblin)
+
+	c.recordOutputUsage(goblin)
+	c.endTasks(goblin)
+
+	// Kill tmux session
+	c.killTmuxSession(c.Socket(goblin), goblin.TmuxSession)
+
+	// Remove worktree (optional - could keep for review)
+	// c.removeWorktree(goblin.WorktreePath)
+
+	// Update status
+	if err := c.db.UpdateGoblinStatus(goblin.ID, status); err != nil {
+		return err
+	}
+	goblin.Status = status
+	c.recordEvent(goblin.ID, goblin.Name, event, "")
+	c.releaseLocks(goblin)
+	c.releaseOllama()
+	c.resumePreempted()
+	return nil
+}
+
 // Kill forcefully kills a goblin and cleans up
 func (c *Coordinator) Kill(nameOrID string) error {
 	goblin, err := c.Get(nameOrID)
@@ -315,19 +780,27 @@ func (c *Coordinator) Kill(nameOrID string) error {
 		return err
 	}
 	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", nameOrID)
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
 	}
 
+	c.recordOutputUsage(goblin)
+	c.bury(goblin)
+
 	// Kill tmux session
-	c.killTmuxSession(goblin.TmuxSession)
+	c.killTmuxSession(c.Socket(goblin), goblin.TmuxSession)
 
-	// Remove worktree
-	c.removeWorktree(goblin.WorktreePath)
+	// Remove worktree and any archive or artifacts
+	c.removeWorktree(goblin.ProjectPath, goblin.WorktreePath)
+	os.RemoveAll(c.ArchivePath(goblin))
+	os.RemoveAll(c.ArtifactsPath(goblin))
 
 	// Delete from database
 	if err := c.db.DeleteGoblin(goblin.ID); err != nil {
 		return err
 	}
+	c.recordEvent(goblin.ID, goblin.Name, EventKilled, "")
+	c.releaseOllama()
+	c.resumePreempted()
 
 	if c.log != nil {
 		c.log.Info("Killed goblin",
@@ -338,20 +811,25 @@ func (c *Coordinator) Kill(nameOrID string) error {
 	return nil
 }
 
-// Attach attaches to a goblin's tmux session
-func (c *Coordinator) Attach(nameOrID string) error {
+// Attach shows a goblin's tmux session: attached in the terminal, or
+// from inside tmux as mode says (see AttachCommand)
+func (c *Coordinator) Attach(nameOrID, mode string) error {
 	goblin, err := c.Get(nameOrID)
 	if err != nil {
 		return err
 	}
 	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", nameOrID)
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
 	}
 
-	socketName := c.cfg.Tmux.SocketName
+	if err := c.requireSession(goblin); err != nil {
+		return err
+	}
 
-	// Attach to tmux session (this replaces the current process)
-	cmd := exec.Command("tmux", "-L", socketName, "attach-session", "-t", goblin.TmuxSession)
+	cmd, err := c.AttachCommand(goblin, mode)
+	if err != nil {
+		return err
+	}
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -365,6 +843,7 @@ type Stats struct {
 	Running   int
 	Paused    int
 	Completed int
+	Throttled int
 }
 
 // Stats returns goblin statistics
@@ -379,23 +858,44 @@ func (c *Coordinator) Stats() (*Stats, error) {
 		Running:   dbStats.Running,
 		Paused:    dbStats.Paused,
 		Completed: dbStats.Completed,
+		Throttled: dbStats.Throttled,
 	}, nil
 }
 
 // SendTask sends a task to a goblin
 func (c *Coordinator) SendTask(nameOrID, task string) error {
+	return c.sendTask(nameOrID, task, "")
+}
+
+// sendTask types a task into a goblin's session. label is what the task
+// history keeps, the task itself when empty.
+func (c *Coordinator) sendTask(nameOrID, task, label string) error {
 	goblin, err := c.Get(nameOrID)
 	if err != nil {
 		return err
 	}
 	if goblin == nil {
-		return fmt.Errorf("goblin not found: %s", nameOrID)
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
 	}
 
-	socketName := c.cfg.Tmux.SocketName
+	if err := c.requireSession(goblin); err != nil {
+		return err
+	}
+
+	if err := c.checkLocks(goblin, task); err != nil {
+		return err
+	}
+
+	task = c.fitPrompt(goblin, task)
+
+	if err := c.throttle(goblin, goblin.Agent); err != nil {
+		return err
+	}
+
+	head := headCommit(goblin.WorktreePath)
 
 	// Send the task as input to the tmux session
-	cmd := exec.Command("tmux", "-L", socketName,
+	cmd := trace.Command("tmux", "-L", c.Socket(goblin),
 		"send-keys", "-t", goblin.TmuxSession, task, "Enter")
 
 	output, err := cmd.CombinedOutput()
@@ -403,6 +903,13 @@ func (c *Coordinator) SendTask(nameOrID, task string) error {
 		return fmt.Errorf("failed to send task: %s\n%s", err, string(output))
 	}
 
+	if label == "" {
+		label = task
+	}
+	c.startTask(goblin, label, head)
+	c.recordEvent(goblin.ID, goblin.Name, EventTask, label)
+	c.recordUsage(goblin, tokens.Count(task), 0)
+
 	if c.log != nil {
 		c.log.Info("Sent task to goblin",
 			logging.String("goblin", goblin.Name),
@@ -411,3 +918,207 @@ func (c *Coordinator) SendTask(nameOrID, task string) error {
 
 	return nil
 }
+
+// SendText pastes multi-line text (context bundles, documents) into a
+// goblin's session as one message and submits it. Only label is stored in
+// the task history, as the text itself can be large.
+func (c *Coordinator) SendText(nameOrID, text, label string) error {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+
+	if err := c.requireSession(goblin); err != nil {
+		return err
+	}
+
+	text = c.fitPrompt(goblin, text)
+
+	if err := c.throttle(goblin, goblin.Agent); err != nil {
+		return err
+	}
+
+	head := headCommit(goblin.WorktreePath)
+	mgr := tmux.NewManager(tmux.Config{SocketName: c.Socket(goblin)})
+	if err := mgr.Paste(goblin.TmuxSession, text); err != nil {
+		return err
+	}
+	if err := mgr.SendKeys(goblin.TmuxSession, "Enter"); err != nil {
+		return err
+	}
+
+	c.startTask(goblin, label, head)
+	c.recordEvent(goblin.ID, goblin.Name, EventTask, label)
+	c.recordUsage(goblin, tokens.Count(text), 0)
+
+	if c.log != nil {
+		c.log.Info("Sent text to goblin",
+			logging.String("goblin", goblin.Name),
+			logging.String("label", label),
+			logging.Int("bytes", len(text)))
+	}
+
+	return nil
+}
+
+// WorkTasks returns the tasks a goblin was given to work on, in the order
+// they were sent, leaving out onboarding context and requests to fix
+// failing checks
+func (c *Coordinator) WorkTasks(g *Goblin) ([]string, error) {
+	records, err := c.db.ListTasks(g.ID)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []string
+	for _, r := range records {
+		if strings.HasPrefix(r.Task, "[context]") || r.Task == FixChecksTask {
+			continue
+		}
+		tasks = append(tasks, r.Task)
+	}
+	return tasks, nil
+}
+
+// Activity log event types recorded by the coordinator. Watchers add
+// their own (task_complete, failure, approval).
+const (
+	EventSpawned  = "spawned"
+	EventTask     = "task"
+	EventStopped  = "stopped"
+	EventKilled   = "killed"
+	EventPaused   = "paused"
+	EventResumed  = "resumed"
+	EventPushed   = "pushed"
+	EventPR       = "pr_opened" // Detail is the pull request's URL
+	EventPRMerged = "pr_merged" // Detail is the merged pull request's URL
+)
+
+// RecordEvent adds an entry to the activity log for a goblin
+func (c *Coordinator) RecordEvent(nameOrID, eventType, detail string) error {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	return c.db.RecordEvent(goblin.ID, goblin.Name, eventType, detail)
+}
+
+// EventRecorded reports whether an event of eventType was recorded for a
+// goblin at or after since
+func (c *Coordinator) EventRecorded(g *Goblin, eventType string, since time.Time) (bool, error) {
+	events, err := c.db.ListEvents(since)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range events {
+		if e.GoblinID == g.ID && e.Type == eventType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// recordEvent logs an activity event; failures never interrupt the
+// operation being recorded
+func (c *Coordinator) recordEvent(goblinID, name, eventType, detail string) {
+	if err := c.db.RecordEvent(goblinID, name, eventType, detail); err != nil && c.log != nil {
+		c.log.Warn("Failed to record event",
+			logging.String("goblin", name),
+			logging.String("event", eventType))
+	}
+	c.emitLifecycle(goblinID, name, eventType, detail)
+}
+
+// ReplayOptions contains options for replaying a goblin's tasks
+type ReplayOptions struct {
+	Name        string        // Name for the fresh goblin
+	Agent       *agents.Agent // nil reuses the original agent
+	IdleTimeout time.Duration // Quiet period that marks a task as done
+	TaskTimeout time.Duration // Upper bound per task
+	OnTask      func(index int, task string)
+}
+
+// Replay spawns a fresh goblin on the same project and base commit as an
+// existing one and re-sends its recorded tasks in order, waiting for the
+// agent to go idle between tasks
+func (c *Coordinator) Replay(nameOrID string, opts ReplayOptions) (*Goblin, error) {
+	source, err := c.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+
+	tasks, err := c.db.ListTasks(source.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("goblin %s has no recorded tasks", source.Name)
+	}
+
+	agent := opts.Agent
+	if agent == nil {
+		agent = agents.NewRegistry().Get(source.Agent)
+		if agent == nil {
+			return nil, fmt.Errorf("unknown agent: %s", source.Agent)
+		}
+	}
+
+	if opts.IdleTimeout == 0 {
+		opts.IdleTimeout = 60 * time.Second
+	}
+	if opts.TaskTimeout == 0 {
+		opts.TaskTimeout = 30 * time.Minute
+	}
+
+	goblin, err := c.Spawn(SpawnOptions{
+		Name:        opts.Name,
+		Agent:       agent,
+		ProjectPath: source.ProjectPath,
+		Branch:      c.cfg.Git.BranchPrefix + opts.Name,
+		BaseRef:     source.BaseRef,
+		TmuxSocket:  source.TmuxSocket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := tmux.NewManager(tmux.Config{SocketName: c.Socket(goblin)})
+	poll := time.Second
+	if opts.IdleTimeout < 4*poll {
+		poll = opts.IdleTimeout / 4
+	}
+
+	// Let the agent boot before typing into it
+	boot := 5 * time.Second
+	if opts.IdleTimeout < boot {
+		boot = opts.IdleTimeout
+	}
+	mgr.WaitIdle(goblin.TmuxSession, boot, time.Minute, poll)
+
+	for i, t := range tasks {
+		if err := c.WaitForWorkingHours(context.Background(), goblin.ID, goblin.Name, fmt.Sprintf("replay task %d", i+1)); err != nil {
+			return goblin, err
+		}
+		if opts.OnTask != nil {
+			opts.OnTask(i, t.Task)
+		}
+		if err := c.SendTask(goblin.Name, t.Task); err != nil {
+			return goblin, err
+		}
+		if !mgr.WaitIdle(goblin.TmuxSession, opts.IdleTimeout, opts.TaskTimeout, poll) && c.log != nil {
+			c.log.Warn("Replay task timed out",
+				logging.String("goblin", goblin.Name),
+				logging.Int("task", i+1))
+		}
+	}
+
+	return goblin, nil
+}
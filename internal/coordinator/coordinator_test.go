@@ -1,14 +1,17 @@
 package coordinator
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/astoreyai/goblin-forge/internal/agents"
 	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
 	"github.com/astoreyai/goblin-forge/internal/logging"
 	"github.com/astoreyai/goblin-forge/internal/storage"
 )
@@ -98,8 +101,11 @@ func setupCoordinator(t *testing.T) (*Coordinator, *config.Config, func()) {
 	}
 
 	cfg := &config.Config{
-		DatabasePath: dbPath,
-		WorktreeBase: filepath.Join(tmpDir, "worktrees"),
+		DatabasePath:  dbPath,
+		WorktreeBase:  filepath.Join(tmpDir, "worktrees"),
+		RecordingsDir: filepath.Join(tmpDir, "recordings"),
+		ArchiveDir:    filepath.Join(tmpDir, "archives"),
+		ArtifactsDir:  filepath.Join(tmpDir, "artifacts"),
 		Tmux: config.TmuxConfig{
 			SocketName: "gforge-test-coord",
 		},
@@ -442,6 +448,46 @@ func TestSendTask(t *testing.T) {
 	}
 }
 
+func TestSendText(t *testing.T) {
+	if !gitAvailable() || !tmuxAvailable() {
+		t.Skip("git or tmux not available")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	_, err := coord.Spawn(SpawnOptions{
+		Name:        "text-test",
+		Agent:       &agents.Agent{Name: "cat", Command: "cat"},
+		ProjectPath: repoPath,
+		Branch:      "gforge/text-test",
+		NoContext:   true,
+	})
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	defer coord.Kill("text-test")
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := coord.SendText("text-test", "# Context\n\nline one\nline two", "[context] api"); err != nil {
+		t.Fatalf("SendText failed: %v", err)
+	}
+
+	goblin, _ := coord.Get("text-test")
+	tasks, _ := coord.db.ListTasks(goblin.ID)
+	if len(tasks) != 1 || tasks[0].Task != "[context] api" {
+		t.Errorf("Expected only the label in task history, got %+v", tasks)
+	}
+
+	if err := coord.SendText("nonexistent", "text", "label"); err == nil {
+		t.Error("Expected error for nonexistent goblin")
+	}
+}
+
 func TestSendTaskNonexistent(t *testing.T) {
 	coord, _, cleanup := setupCoordinator(t)
 	defer cleanup()
@@ -457,7 +503,7 @@ func TestNonGitProject(t *testing.T) {
 		t.Skip("tmux not available")
 	}
 
-	coord, _, cleanup := setupCoordinator(t)
+	coord, cfg, cleanup := setupCoordinator(t)
 	defer cleanup()
 
 	// Create a non-git directory
@@ -485,8 +531,300 @@ func TestNonGitProject(t *testing.T) {
 	}
 	defer coord.Kill("nongit-test")
 
-	// WorktreePath should be the original dir for non-git projects
-	if goblin.WorktreePath != tmpDir {
-		t.Errorf("Expected worktree path '%s', got '%s'", tmpDir, goblin.WorktreePath)
+	// Non-git projects get an isolated copy under the worktree base
+	if filepath.Dir(goblin.WorktreePath) != cfg.WorktreeBase {
+		t.Errorf("Expected a workspace under '%s', got '%s'", cfg.WorktreeBase, goblin.WorktreePath)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	if !gitAvailable() || !tmuxAvailable() {
+		t.Skip("git or tmux not available")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	agent := &agents.Agent{
+		Name:    "cat",
+		Command: "cat",
+		Args:    []string{},
+	}
+
+	source, err := coord.Spawn(SpawnOptions{
+		Name:        "replay-src",
+		Agent:       agent,
+		ProjectPath: repoPath,
+		Branch:      "gforge/replay-src",
+		NoContext:   true,
+	})
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	defer coord.Kill("replay-src")
+
+	if source.BaseRef == "" {
+		t.Error("Spawn should record the base commit")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	for _, task := range []string{"first", "second"} {
+		if err := coord.SendTask("replay-src", task); err != nil {
+			t.Fatalf("SendTask failed: %v", err)
+		}
+	}
+
+	var sent []string
+	replayed, err := coord.Replay("replay-src", ReplayOptions{
+		Name:        "replay-dst",
+		Agent:       agent,
+		IdleTimeout: 200 * time.Millisecond,
+		TaskTimeout: 2 * time.Second,
+		OnTask:      func(i int, task string) { sent = append(sent, task) },
+	})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	defer coord.Kill("replay-dst")
+
+	if replayed.BaseRef != source.BaseRef {
+		t.Errorf("Expected base ref %s, got %s", source.BaseRef, replayed.BaseRef)
+	}
+	if len(sent) != 2 || sent[0] != "first" || sent[1] != "second" {
+		t.Errorf("Expected tasks replayed in order, got %v", sent)
+	}
+}
+
+func TestReplayNonexistent(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if _, err := coord.Replay("nonexistent", ReplayOptions{Name: "x"}); err == nil {
+		t.Error("Should error when replaying nonexistent goblin")
+	}
+}
+
+func TestFindRecording(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if _, err := coord.FindRecording("coder"); err == nil {
+		t.Error("Should error when no recording exists")
+	}
+
+	os.MkdirAll(cfg.RecordingsDir, 0755)
+	older := filepath.Join(cfg.RecordingsDir, "coder-aaaaaaaa.cast")
+	newer := filepath.Join(cfg.RecordingsDir, "coder-bbbbbbbb.cast")
+	other := filepath.Join(cfg.RecordingsDir, "coder-2-cccccccc.cast")
+	for _, path := range []string{older, newer, other} {
+		os.WriteFile(path, []byte("{}\n"), 0644)
+	}
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(older, past, past)
+
+	path, err := coord.FindRecording("coder")
+	if err != nil {
+		t.Fatalf("FindRecording failed: %v", err)
+	}
+	if path != newer {
+		t.Errorf("Expected newest recording %s, got %s", newer, path)
+	}
+}
+
+func TestFindByPath(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	worktree := filepath.Join(cfg.WorktreeBase, "abcd1234")
+	os.MkdirAll(filepath.Join(worktree, "src", "pkg"), 0755)
+
+	coord.db.CreateGoblin(&storage.Goblin{
+		ID:           "abcd1234",
+		Name:         "coder",
+		Agent:        "claude",
+		Status:       "running",
+		ProjectPath:  "/tmp/project",
+		WorktreePath: worktree,
+	})
+
+	for _, path := range []string{worktree, filepath.Join(worktree, "src", "pkg")} {
+		goblin, err := coord.FindByPath(path)
+		if err != nil {
+			t.Fatalf("FindByPath failed: %v", err)
+		}
+		if goblin == nil || goblin.Name != "coder" {
+			t.Errorf("Expected coder for %s, got %v", path, goblin)
+		}
+	}
+
+	// A sibling with a shared prefix is not inside the worktree
+	goblin, err := coord.FindByPath(worktree + "-other")
+	if err != nil {
+		t.Fatalf("FindByPath failed: %v", err)
+	}
+	if goblin != nil {
+		t.Errorf("Expected no goblin outside worktree, got %s", goblin.Name)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	coord.db.CreateGoblin(&storage.Goblin{
+		ID: "aaaa1111", Name: "refactor-authentication", Agent: "claude", Status: "running", ProjectPath: "/tmp",
+	})
+	coord.db.CreateGoblin(&storage.Goblin{
+		ID: "bbbb2222", Name: "tester", Agent: "codex", Status: "running", ProjectPath: "/tmp",
+	})
+
+	if err := coord.SetAlias("auth", "refactor-authentication"); err != nil {
+		t.Fatalf("SetAlias failed: %v", err)
+	}
+
+	goblin, err := coord.Get("auth")
+	if err != nil || goblin == nil || goblin.Name != "refactor-authentication" {
+		t.Errorf("Expected alias to resolve, got %v (%v)", goblin, err)
+	}
+
+	for _, bad := range []string{"", ".", "2", "tester"} {
+		if err := coord.SetAlias(bad, "refactor-authentication"); err == nil {
+			t.Errorf("SetAlias(%q) should fail", bad)
+		}
+	}
+	if err := coord.SetAlias("x", "missing"); err == nil {
+		t.Error("SetAlias to a missing goblin should fail")
+	}
+
+	aliases, err := coord.Aliases()
+	if err != nil {
+		t.Fatalf("Aliases failed: %v", err)
+	}
+	if len(aliases["aaaa1111"]) != 1 || aliases["aaaa1111"][0] != "auth" {
+		t.Errorf("Unexpected aliases: %v", aliases)
+	}
+
+	if g, _ := coord.GetByIndex(1); g == nil {
+		t.Error("Expected a goblin at index 1")
+	}
+
+	if err := coord.RemoveAlias("auth"); err != nil {
+		t.Fatalf("RemoveAlias failed: %v", err)
+	}
+	if g, _ := coord.Get("auth"); g != nil {
+		t.Error("Removed alias should not resolve")
+	}
+}
+
+func TestSpawnGitIdentity(t *testing.T) {
+	if !gitAvailable() || !tmuxAvailable() {
+		t.Skip("git or tmux not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	os.WriteFile(filepath.Join(repoPath, config.ProjectFile), []byte(`
+git:
+  user_name: gforge-bot
+  ssh_key: /keys/deploy
+`), 0644)
+
+	goblin, err := coord.Spawn(SpawnOptions{
+		Name:        "bot",
+		Agent:       &agents.Agent{Name: "echo", Command: "echo"},
+		ProjectPath: repoPath,
+		Branch:      "gforge/bot",
+	})
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	defer coord.Kill("bot")
+
+	for _, want := range []string{"GIT_AUTHOR_NAME=gforge-bot", "GIT_SSH_COMMAND=ssh -i '/keys/deploy'"} {
+		name := want[:strings.Index(want, "=")]
+		out, err := exec.Command("tmux", "-L", cfg.Tmux.SocketName, "show-environment", "-t", goblin.TmuxSession, name).Output()
+		if err != nil || !strings.HasPrefix(string(out), want) {
+			t.Errorf("Expected %s in the session environment, got %q (%v)", want, out, err)
+		}
+	}
+}
+
+func TestSpawnProtectedBranch(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	cfg.Git.ProtectedBranches = []string{"main"}
+	project := t.TempDir()
+	os.WriteFile(filepath.Join(project, config.ProjectFile), []byte("protected_branches: [release/*]\n"), 0644)
+
+	for _, branch := range []string{"main", "release/1.0"} {
+		_, err := coord.Spawn(SpawnOptions{
+			Name:        "rogue",
+			Agent:       &agents.Agent{Name: "echo", Command: "echo"},
+			ProjectPath: project,
+			Branch:      branch,
+		})
+		if !errors.Is(err, errs.ErrProtectedBranch) {
+			t.Errorf("Expected %s refused, got %v", branch, err)
+		}
+	}
+}
+
+func TestCheckSessions(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if err := exec.Command("tmux", "-L", cfg.Tmux.SocketName, "new-session", "-d", "-s", "gforge-alive").Run(); err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	defer exec.Command("tmux", "-L", cfg.Tmux.SocketName, "kill-session", "-t", "gforge-alive").Run()
+
+	goblins := []*Goblin{
+		{Name: "alive", Status: "running", TmuxSession: "gforge-alive"},
+		{Name: "gone", Status: "running", TmuxSession: "gforge-gone"},
+		{Name: "paused", Status: "paused", TmuxSession: "gforge-gone"},
+		{Name: "stopped", Status: "stopped", TmuxSession: "gforge-gone"},
+	}
+	coord.CheckSessions(goblins)
+
+	want := []string{"running", StatusDead, StatusDead, "stopped"}
+	for i, g := range goblins {
+		if g.Status != want[i] {
+			t.Errorf("Expected %s to be %s, got %s", g.Name, want[i], g.Status)
+		}
+	}
+}
+
+func TestCreateTmuxSessionHistoryLimit(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	cfg.Tmux.HistoryLimit = 12345
+	if err := coord.createTmuxSession(cfg.Tmux.SocketName, "gforge-history", os.TempDir(), nil); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	out, err := exec.Command("tmux", "-L", cfg.Tmux.SocketName, "display-message", "-p",
+		"-t", "gforge-history", "#{history_limit}").Output()
+	if err != nil {
+		t.Fatalf("Failed to read history limit: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "12345" {
+		t.Errorf("Expected the pane created with history_limit 12345, got %s", got)
 	}
 }
@@ -0,0 +1,30 @@
+package coordinator
+
+import (
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventDependencies records dependency or license changes found in a
+// goblin's branch
+const EventDependencies = "dependencies"
+
+// CheckDependencies reports what the commits on a goblin's branch since
+// it was spawned change in dependency manifests and licenses, recording
+// an event when there is anything to review
+func (c *Coordinator) CheckDependencies(g *Goblin) (*workspace.DependencyReport, error) {
+	report, err := workspace.DetectDependencyChanges(g.WorktreePath, g.BaseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if !report.Empty() {
+		c.recordEvent(g.ID, g.Name, EventDependencies, report.Summary())
+		if c.log != nil {
+			c.log.Info("Dependency changes in goblin branch",
+				logging.String("name", g.Name),
+				logging.String("changes", report.Summary()))
+		}
+	}
+	return report, nil
+}
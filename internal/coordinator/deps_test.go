@@ -0,0 +1,50 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "deps", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: repo, BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("deps")
+
+	report, err := coord.CheckDependencies(g)
+	if err != nil || !report.Empty() {
+		t.Fatalf("Expected nothing to review yet, got %+v, %v", report, err)
+	}
+
+	os.WriteFile(filepath.Join(repo, "package.json"), []byte(`{"dependencies": {"left-pad": "1.3.0"}}`), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add left-pad").Run()
+
+	report, err = coord.CheckDependencies(g)
+	if err != nil {
+		t.Fatalf("CheckDependencies failed: %v", err)
+	}
+	if len(report.Deps) != 1 || report.Deps[0].Name != "left-pad" {
+		t.Errorf("Expected left-pad added, got %+v", report.Deps)
+	}
+
+	events, _ := coord.db.ListEvents(time.Now().Add(-time.Hour))
+	if len(events) != 1 || events[0].Type != EventDependencies {
+		t.Errorf("Expected one dependencies event, got %+v", events)
+	}
+}
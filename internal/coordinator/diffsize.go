@@ -0,0 +1,97 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventOversized records a goblin branch over the diff size limits
+const EventOversized = "oversized"
+
+// What gforge push does about a branch over the limits (git.diff_limit)
+const (
+	DiffLimitWarn  = "warn"
+	DiffLimitBlock = "block"
+)
+
+// DiffLimits are the most files and lines a goblin branch should change;
+// zero is no limit
+type DiffLimits struct {
+	Files int
+	Lines int
+}
+
+// DiffLimits returns the size limits for a project: .gforge.yaml, then
+// git.max_diff_files and git.max_diff_lines
+func (c *Coordinator) DiffLimits(project *config.ProjectConfig) DiffLimits {
+	limits := DiffLimits{Files: c.cfg.Git.MaxDiffFiles, Lines: c.cfg.Git.MaxDiffLines}
+	if project != nil {
+		if project.MaxDiffFiles != 0 {
+			limits.Files = project.MaxDiffFiles
+		}
+		if project.MaxDiffLines != 0 {
+			limits.Lines = project.MaxDiffLines
+		}
+	}
+	return limits
+}
+
+// Exceeded describes each limit stat is over, or nil when it fits
+func (l DiffLimits) Exceeded(stat *workspace.DiffStat) []string {
+	var over []string
+	if l.Files > 0 && stat.Files > l.Files {
+		over = append(over, fmt.Sprintf("%d files (limit %d)", stat.Files, l.Files))
+	}
+	if lines := stat.Insertions + stat.Deletions; l.Lines > 0 && lines > l.Lines {
+		over = append(over, fmt.Sprintf("%d lines (limit %d)", lines, l.Lines))
+	}
+	return over
+}
+
+// CheckDiffSize measures the changes committed on a goblin's branch since
+// it was spawned and records the size for gforge sizes. It returns the
+// limits the branch is over; with git.diff_limit set to block, being over
+// any of them fails with errs.ErrDiffTooLarge.
+func (c *Coordinator) CheckDiffSize(g *Goblin, limits DiffLimits) (*workspace.DiffStat, []string, error) {
+	switch c.cfg.Git.DiffLimit {
+	case "", DiffLimitWarn, DiffLimitBlock:
+	default:
+		return nil, nil, fmt.Errorf("unknown git.diff_limit: %s (use warn or block)", c.cfg.Git.DiffLimit)
+	}
+
+	stat, err := workspace.BranchDiffStat(g.WorktreePath, g.BaseRef, c.DiffIgnore(g))
+	if err != nil {
+		return nil, nil, err
+	}
+	over := limits.Exceeded(stat)
+
+	if err := c.db.RecordDiffSize(&storage.DiffSize{
+		GoblinID:   g.ID,
+		GoblinName: g.Name,
+		Project:    g.ProjectPath,
+		Files:      stat.Files,
+		Insertions: stat.Insertions,
+		Deletions:  stat.Deletions,
+		Oversized:  len(over) > 0,
+	}); err != nil && c.log != nil {
+		c.log.Warn("Failed to record diff size",
+			logging.String("name", g.Name),
+			logging.Err(err))
+	}
+
+	if len(over) == 0 {
+		return stat, nil, nil
+	}
+
+	c.recordEvent(g.ID, g.Name, EventOversized, strings.Join(over, ", "))
+	if c.cfg.Git.DiffLimit == DiffLimitBlock {
+		return stat, over, fmt.Errorf("%w: %s changes %s", errs.ErrDiffTooLarge, g.Name, strings.Join(over, " and "))
+	}
+	return stat, over, nil
+}
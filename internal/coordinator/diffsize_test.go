@@ -0,0 +1,86 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+func TestDiffLimits(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	cfg.Git.MaxDiffFiles, cfg.Git.MaxDiffLines = 50, 1000
+
+	if l := coord.DiffLimits(&config.ProjectConfig{}); l != (DiffLimits{Files: 50, Lines: 1000}) {
+		t.Errorf("Expected the global limits, got %+v", l)
+	}
+	l := coord.DiffLimits(&config.ProjectConfig{MaxDiffFiles: 200, MaxDiffLines: -1})
+	if l != (DiffLimits{Files: 200, Lines: -1}) {
+		t.Errorf("Expected the project's limits, got %+v", l)
+	}
+	if over := l.Exceeded(&workspace.DiffStat{Files: 10, Insertions: 90000}); over != nil {
+		t.Errorf("Expected a disabled line limit ignored, got %v", over)
+	}
+	if over := l.Exceeded(&workspace.DiffStat{Files: 201}); len(over) != 1 || over[0] != "201 files (limit 200)" {
+		t.Errorf("Expected the file limit exceeded, got %v", over)
+	}
+}
+
+func TestCheckDiffSize(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "sprawl", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: repo, BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("sprawl")
+
+	for i := 0; i < 3; i++ {
+		os.WriteFile(filepath.Join(repo, fmt.Sprintf("file%d.txt", i)), []byte("line\n"), 0644)
+	}
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Sprawl").Run()
+
+	limits := DiffLimits{Files: 2}
+
+	cfg.Git.DiffLimit = DiffLimitWarn
+	stat, over, err := coord.CheckDiffSize(g, limits)
+	if err != nil || stat.Files != 3 || len(over) != 1 {
+		t.Fatalf("Expected a warning for 3 files, got %v, %v, %v", stat, over, err)
+	}
+
+	cfg.Git.DiffLimit = DiffLimitBlock
+	if _, _, err := coord.CheckDiffSize(g, limits); !errors.Is(err, errs.ErrDiffTooLarge) {
+		t.Errorf("Expected ErrDiffTooLarge, got %v", err)
+	}
+	if _, over, err := coord.CheckDiffSize(g, DiffLimits{Files: 3}); err != nil || over != nil {
+		t.Errorf("Expected a branch within the limits to pass, got %v, %v", over, err)
+	}
+
+	sizes, _ := coord.db.ListDiffSizes(time.Now().Add(-time.Hour))
+	if len(sizes) != 3 || !sizes[0].Oversized || sizes[2].Oversized || sizes[0].Files != 3 {
+		t.Errorf("Expected each check recorded, got %+v", sizes)
+	}
+
+	cfg.Git.DiffLimit = "refuse"
+	if _, _, err := coord.CheckDiffSize(g, limits); err == nil {
+		t.Error("Expected an unknown diff_limit rejected")
+	}
+}
@@ -0,0 +1,116 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// baseChanges are the uncommitted files in a project's own checkout,
+// which a new worktree would not contain
+type baseChanges struct {
+	tracked   []string
+	untracked []string
+}
+
+// uncommittedChanges lists the uncommitted files in a git checkout; nil
+// when it is clean or not a repository
+func uncommittedChanges(path string) (*baseChanges, error) {
+	if !isGitRepo(path) {
+		return nil, nil
+	}
+
+	// Without optional locks, status leaves the index alone: spawns side by
+	// side would otherwise find each other holding index.lock
+	output, err := trace.Command("git", "--no-optional-locks", "-C", path, "status", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s for uncommitted changes: %w", path, err)
+	}
+
+	changes := &baseChanges{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		if strings.HasPrefix(line, "??") {
+			changes.untracked = append(changes.untracked, line[3:])
+		} else {
+			changes.tracked = append(changes.tracked, line[3:])
+		}
+	}
+	if len(changes.tracked)+len(changes.untracked) == 0 {
+		return nil, nil
+	}
+	return changes, nil
+}
+
+// checkBase looks for in-progress edits in the project before a goblin is
+// spawned from it. With git.refuse_dirty (and no git.auto_stash to carry
+// them over) the spawn is refused.
+func (c *Coordinator) checkBase(projectPath string) (*baseChanges, error) {
+	changes, err := uncommittedChanges(projectPath)
+	if err != nil || changes == nil {
+		return nil, err
+	}
+
+	if c.cfg.Git.RefuseDirty && !c.cfg.Git.AutoStash {
+		return nil, fmt.Errorf("%s has %d uncommitted file(s) the goblin would not see; commit or stash them first",
+			projectPath, len(changes.tracked)+len(changes.untracked))
+	}
+	return changes, nil
+}
+
+// carryChanges brings the project's uncommitted edits to tracked files
+// into the new worktree as one commit, when git.auto_stash is set and the
+// goblin branches from the current HEAD. The project's own checkout is
+// left as it was. Anything not carried over is warned about.
+func (c *Coordinator) carryChanges(changes *baseChanges, projectPath, worktreePath, name, baseRef string) {
+	missing := changes.untracked
+	if len(changes.tracked) > 0 {
+		if c.cfg.Git.AutoStash && baseRef == "" {
+			if err := c.applyChanges(projectPath, worktreePath); err != nil {
+				if c.log != nil {
+					c.log.Warn("Failed to carry uncommitted changes into worktree",
+						logging.String("name", name),
+						logging.Err(err))
+				}
+				missing = append(changes.tracked, missing...)
+			}
+		} else {
+			missing = append(changes.tracked, missing...)
+		}
+	}
+
+	if len(missing) > 0 && c.log != nil {
+		c.log.Warn("Project has uncommitted changes the goblin cannot see",
+			logging.String("name", name),
+			logging.String("project", projectPath),
+			logging.Int("files", len(missing)),
+			logging.String("first", missing[0]))
+	}
+}
+
+// applyChanges snapshots the project's working tree with git stash create,
+// which doesn't touch it, and commits the snapshot in the worktree
+func (c *Coordinator) applyChanges(projectPath, worktreePath string) error {
+	output, err := trace.Command("git", "-C", projectPath, "stash", "create").Output()
+	if err != nil {
+		return fmt.Errorf("git stash create: %w", err)
+	}
+	stash := strings.TrimSpace(string(output))
+	if stash == "" {
+		return nil
+	}
+
+	commit := append(identityArgs(worktreePath),
+		"commit", "-q", "--no-verify", "-m", "gforge: uncommitted changes from "+projectPath)
+	for _, args := range [][]string{{"stash", "apply", "-q", stash}, {"add", "-A"}, commit} {
+		if err := gitRun(worktreePath, args...); err != nil {
+			gitRun(worktreePath, "reset", "-q", "--hard")
+			return err
+		}
+	}
+	return nil
+}
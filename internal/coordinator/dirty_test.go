@@ -0,0 +1,63 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCarryChanges(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	if changes, err := coord.checkBase(repoPath); err != nil || changes != nil {
+		t.Fatalf("Expected a clean checkout, got %+v, %v", changes, err)
+	}
+
+	os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Test\nin progress\n"), 0644)
+	os.WriteFile(filepath.Join(repoPath, "notes.txt"), []byte("scratch\n"), 0644)
+
+	cfg.Git.RefuseDirty = true
+	if _, err := coord.checkBase(repoPath); err == nil {
+		t.Error("Expected a dirty checkout refused")
+	}
+
+	cfg.Git.AutoStash = true
+	changes, err := coord.checkBase(repoPath)
+	if err != nil {
+		t.Fatalf("Expected auto_stash to override refuse_dirty, got %v", err)
+	}
+	if len(changes.tracked) != 1 || len(changes.untracked) != 1 {
+		t.Fatalf("Expected one tracked and one untracked change, got %+v", changes)
+	}
+
+	worktree, err := coord.createWorktree(repoPath, filepath.Join(cfg.WorktreeBase, "g-dirty"), "gforge/dirty", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	coord.carryChanges(changes, repoPath, worktree, "dirty", "")
+
+	data, _ := os.ReadFile(filepath.Join(worktree, "README.md"))
+	if !strings.Contains(string(data), "in progress") {
+		t.Errorf("Expected the edit carried into the worktree, got %q", data)
+	}
+	status, _ := exec.Command("git", "-C", worktree, "status", "--porcelain").Output()
+	if len(strings.TrimSpace(string(status))) != 0 {
+		t.Errorf("Expected the carried edit committed, got status %q", status)
+	}
+
+	// The project's checkout is left exactly as it was
+	status, _ = exec.Command("git", "-C", repoPath, "status", "--porcelain").Output()
+	if !strings.Contains(string(status), "README.md") || !strings.Contains(string(status), "notes.txt") {
+		t.Errorf("Expected the project's edits kept, got status %q", status)
+	}
+}
@@ -0,0 +1,88 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// StatusCompleted marks a goblin whose work was wrapped up with gforge
+// done: its session is gone and its branch pushed
+const StatusCompleted = "completed"
+
+// EventCompleted records a goblin marked completed
+const EventCompleted = "completed"
+
+// CommitWork commits everything uncommitted in a goblin's worktree with
+// message, reporting whether there was anything to commit
+func (c *Coordinator) CommitWork(g *Goblin, message string) (bool, error) {
+	if g.WorktreePath == "" || g.WorktreePath == g.ProjectPath || !isGitRepo(g.WorktreePath) {
+		return false, fmt.Errorf("goblin %s has no worktree of its own to commit in", g.Name)
+	}
+	status, err := trace.Command("git", "-C", g.WorktreePath, "status", "--porcelain").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to check worktree of %s: %w", g.Name, err)
+	}
+	if strings.TrimSpace(string(status)) == "" {
+		return false, nil
+	}
+	if err := commitAll(g, message); err != nil {
+		return false, err
+	}
+	c.recordCommits(g)
+	return true, nil
+}
+
+// Complete marks a goblin's work finished, ending its session like Stop
+// when it still has one. With summarizer.on_complete the session's output
+// is summarized first.
+func (c *Coordinator) Complete(nameOrID string) (*Goblin, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if goblin == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	if goblin.Status == StatusArchived {
+		return nil, fmt.Errorf("goblin %s is archived; unarchive it first", goblin.Name)
+	}
+
+	if active(goblin.Status) {
+		// Captured first: stopping ends the session holding it
+		output := c.completionOutput(goblin)
+		err = c.stop(goblin, StatusCompleted, EventCompleted)
+		if err == nil && output != "" {
+			c.summarizeCompleted(goblin, output)
+		}
+	} else {
+		err = c.db.UpdateGoblinStatus(goblin.ID, StatusCompleted)
+		if err == nil {
+			goblin.Status = StatusCompleted
+			c.recordEvent(goblin.ID, goblin.Name, EventCompleted, "")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.releaseLocks(goblin)
+
+	if c.log != nil {
+		c.log.Info("Completed goblin",
+			logging.String("name", goblin.Name),
+			logging.String("id", goblin.ID))
+	}
+	return goblin, nil
+}
+
+// active reports whether a goblin with status may still have a session
+func active(status string) bool {
+	switch status {
+	case "stopped", "failed", StatusDead, StatusCompleted, StatusArchived:
+		return false
+	}
+	return true
+}
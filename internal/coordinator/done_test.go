@@ -0,0 +1,60 @@
+package coordinator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestCommitWorkAndComplete(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-finish"), "gforge/finish", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-finish", Name: "finish", Agent: "claude", Status: "stopped",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/finish", CreatedAt: now, UpdatedAt: now})
+	g, _ := coord.Get("finish")
+
+	if committed, err := coord.CommitWork(g, "gforge: finish finish"); err != nil || committed {
+		t.Errorf("Expected nothing to commit in a clean worktree, got %v, %v", committed, err)
+	}
+	os.WriteFile(filepath.Join(worktree, "result.txt"), []byte("result\n"), 0644)
+	if committed, err := coord.CommitWork(g, "gforge: finish finish"); err != nil || !committed {
+		t.Fatalf("Expected the new file committed, got %v, %v", committed, err)
+	}
+	if committed, _ := coord.CommitWork(g, "again"); committed {
+		t.Error("Expected a clean worktree after committing")
+	}
+
+	g, err = coord.Complete("finish")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if g.Status != StatusCompleted {
+		t.Errorf("Expected status %s, got %s", StatusCompleted, g.Status)
+	}
+	events, _ := coord.db.ListEvents(time.Time{})
+	if len(events) == 0 || events[len(events)-1].Type != EventCompleted {
+		t.Errorf("Expected a completed event, got %+v", events)
+	}
+	if got := outcome(g.Status); got != OutcomeCompleted {
+		t.Errorf("Expected outcome %s, got %s", OutcomeCompleted, got)
+	}
+	if active(g.Status) {
+		t.Error("Expected a completed goblin to have no session")
+	}
+}
@@ -0,0 +1,118 @@
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/calendar"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// EventFrozen records work held back by a release freeze
+const EventFrozen = "frozen"
+
+// freezeFetchTimeout bounds reading scheduler.freeze_calendar
+const freezeFetchTimeout = 15 * time.Second
+
+// freezeCacheTTL is how long a read of scheduler.freeze_calendar is
+// reused before it is fetched again
+var freezeCacheTTL = 10 * time.Minute
+
+// freezeCache is the last calendar read, kept in cfg.FreezeFile
+type freezeCache struct {
+	Source    string    `json:"source"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Data      []byte    `json:"data"`
+}
+
+// Freezes returns the windows in scheduler.freeze_calendar overlapping
+// [from, to) whose events match scheduler.freeze_keywords. Without a
+// calendar there are none.
+func (c *Coordinator) Freezes(from, to time.Time) ([]calendar.Window, error) {
+	source := c.cfg.Scheduler.FreezeCalendar
+	if source == "" {
+		return nil, nil
+	}
+
+	data, err := c.freezeCalendar(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read freeze calendar: %w", err)
+	}
+	events, err := calendar.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read freeze calendar: %w", err)
+	}
+	return calendar.Windows(events, c.cfg.Scheduler.FreezeKeywords, from, to), nil
+}
+
+// freezeCalendar returns the calendar at source, from cfg.FreezeFile
+// while it is younger than freezeCacheTTL. When a fetch fails the last
+// good copy is used, with a warning, so a calendar host that is down
+// doesn't decide whether work may land.
+func (c *Coordinator) freezeCalendar(source string) ([]byte, error) {
+	var cache freezeCache
+	if c.cfg.FreezeFile != "" {
+		if data, err := os.ReadFile(c.cfg.FreezeFile); err == nil {
+			if json.Unmarshal(data, &cache) != nil || cache.Source != source {
+				cache = freezeCache{}
+			}
+		}
+	}
+	if cache.Data != nil && time.Since(cache.FetchedAt) < freezeCacheTTL {
+		return cache.Data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), freezeFetchTimeout)
+	defer cancel()
+	data, err := calendar.Read(ctx, source)
+	if err != nil {
+		if cache.Data == nil {
+			return nil, err
+		}
+		if c.log != nil {
+			c.log.Warn("Using the last freeze calendar read",
+				logging.Time("read", cache.FetchedAt),
+				logging.Err(err))
+		}
+		return cache.Data, nil
+	}
+
+	if c.cfg.FreezeFile != "" {
+		cache = freezeCache{Source: source, FetchedAt: time.Now(), Data: data}
+		if encoded, err := json.Marshal(cache); err == nil && os.MkdirAll(filepath.Dir(c.cfg.FreezeFile), 0755) == nil {
+			// The cache only saves fetches; failing to write it is harmless
+			os.WriteFile(c.cfg.FreezeFile, encoded, 0644)
+		}
+	}
+	return data, nil
+}
+
+// CheckFreeze returns errs.ErrFrozen, recording a frozen event, when a
+// freeze is on for the goblin's work (a push or pull request). A calendar
+// that can't be read, and was never read before, also stops the work,
+// since it can't be shown to be clear.
+func (c *Coordinator) CheckFreeze(g *Goblin, work string) error {
+	now := time.Now()
+	windows, err := c.Freezes(now, now.Add(time.Nanosecond))
+	if err != nil || len(windows) == 0 {
+		return err
+	}
+
+	w := windows[0]
+	until := w.End.Local().Format("Mon Jan 2 15:04")
+	c.recordEvent(g.ID, g.Name, EventFrozen, fmt.Sprintf("%s held by %s until %s", work, w.Summary, until))
+	if c.log != nil {
+		c.log.Info("Release freeze on, holding work",
+			logging.String("goblin", g.Name),
+			logging.String("work", work),
+			logging.String("freeze", w.Summary),
+			logging.Time("until", w.End))
+	}
+	return fmt.Errorf("%w: %s until %s", errs.ErrFrozen, w.Summary, until)
+}
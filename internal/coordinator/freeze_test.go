@@ -0,0 +1,123 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+func writeCalendar(t *testing.T, summary string, start, end time.Time) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "freeze.ics")
+	ics := fmt.Sprintf("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:%s\r\nDTSTART:%s\r\nDTEND:%s\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		summary, start.UTC().Format("20060102T150405Z"), end.UTC().Format("20060102T150405Z"))
+	if err := os.WriteFile(path, []byte(ics), 0644); err != nil {
+		t.Fatalf("Failed to write calendar: %v", err)
+	}
+	return path
+}
+
+func TestCheckFreeze(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	g := &Goblin{ID: "id-1", Name: "coder"}
+	if err := coord.CheckFreeze(g, "push"); err != nil {
+		t.Fatalf("Expected no freeze without a calendar, got %v", err)
+	}
+
+	now := time.Now()
+	cfg.Scheduler.FreezeKeywords = []string{"freeze", "maintenance"}
+	cfg.Scheduler.FreezeCalendar = writeCalendar(t, "Team offsite", now.Add(-time.Hour), now.Add(time.Hour))
+	if err := coord.CheckFreeze(g, "push"); err != nil {
+		t.Fatalf("Expected events without a keyword ignored, got %v", err)
+	}
+
+	cfg.Scheduler.FreezeCalendar = writeCalendar(t, "Q4 release freeze", now.Add(-time.Hour), now.Add(time.Hour))
+	err := coord.CheckFreeze(g, "push")
+	if !errors.Is(err, errs.ErrFrozen) {
+		t.Fatalf("Expected ErrFrozen, got %v", err)
+	}
+
+	events, _ := coord.db.ListEvents(now.Add(-time.Minute))
+	if len(events) != 1 || events[0].Type != EventFrozen {
+		t.Fatalf("Expected one frozen event, got %+v", events)
+	}
+
+	cfg.Scheduler.FreezeCalendar = filepath.Join(t.TempDir(), "missing.ics")
+	if err := coord.CheckFreeze(g, "push"); err == nil || errors.Is(err, errs.ErrFrozen) {
+		t.Errorf("Expected an unreadable calendar to fail, got %v", err)
+	}
+}
+
+func TestFreezeCache(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	cfg.FreezeFile = filepath.Join(t.TempDir(), "freeze-calendar.json")
+
+	now := time.Now()
+	g := &Goblin{ID: "id-1", Name: "coder"}
+	cfg.Scheduler.FreezeKeywords = []string{"freeze"}
+	cfg.Scheduler.FreezeCalendar = writeCalendar(t, "Release freeze", now.Add(-time.Hour), now.Add(time.Hour))
+	if err := coord.CheckFreeze(g, "push"); !errors.Is(err, errs.ErrFrozen) {
+		t.Fatalf("Expected ErrFrozen, got %v", err)
+	}
+
+	// Within the TTL the calendar isn't read again
+	os.WriteFile(cfg.Scheduler.FreezeCalendar, []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), 0644)
+	if err := coord.CheckFreeze(g, "push"); !errors.Is(err, errs.ErrFrozen) {
+		t.Fatalf("Expected the cached freeze, got %v", err)
+	}
+
+	// Past it a calendar that can't be read falls back to the last copy
+	ttl := freezeCacheTTL
+	freezeCacheTTL = 0
+	defer func() { freezeCacheTTL = ttl }()
+	os.Remove(cfg.Scheduler.FreezeCalendar)
+	if err := coord.CheckFreeze(g, "push"); !errors.Is(err, errs.ErrFrozen) {
+		t.Fatalf("Expected the last copy used, got %v", err)
+	}
+
+	// and one that can is read afresh
+	cfg.Scheduler.FreezeCalendar = writeCalendar(t, "Release freeze", now.Add(time.Hour), now.Add(2*time.Hour))
+	if err := coord.CheckFreeze(g, "push"); err != nil {
+		t.Errorf("Expected the new calendar read, got %v", err)
+	}
+}
+
+func TestFreezeLeavesSpawnsAndTasks(t *testing.T) {
+	if !gitAvailable() || !tmuxAvailable() {
+		t.Skip("git or tmux not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	// Only pushes and pull requests are held; work goes on meanwhile
+	now := time.Now()
+	cfg.Scheduler.FreezeKeywords = []string{"freeze"}
+	cfg.Scheduler.FreezeCalendar = writeCalendar(t, "Release freeze", now.Add(-time.Hour), now.Add(time.Hour))
+
+	opts := SpawnOptions{
+		Name:        "frozen",
+		Agent:       &agents.Agent{Name: "cat", Command: "cat"},
+		ProjectPath: repoPath,
+		Branch:      "gforge/frozen",
+	}
+	if _, err := coord.Spawn(opts); err != nil {
+		t.Fatalf("Expected the spawn to go ahead during a freeze, got %v", err)
+	}
+	defer coord.Kill("frozen")
+
+	if err := coord.SendTask("frozen", "hello"); err != nil {
+		t.Errorf("Expected the task sent during a freeze, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// worktreeTaken explains a worktree path that already exists, with how to
+// free it: through the goblin that owns it, or by hand
+func (c *Coordinator) worktreeTaken(projectPath, path string) error {
+	err := fmt.Errorf("%w: %s", errs.ErrWorktreeExists, path)
+	if g, _ := c.FindByPath(path); g != nil {
+		return errs.WithHint(err, fmt.Sprintf("gforge kill %s, or gforge archive %s to keep its work", g.Name, g.Name))
+	}
+	return errs.WithHint(err, fmt.Sprintf("move %s out of the way, then git -C %s worktree prune", path, projectPath))
+}
+
+// worktreeAddFailed explains a failed git worktree add, with the fix for
+// a branch that is checked out elsewhere
+func (c *Coordinator) worktreeAddFailed(projectPath, branch string, err error, output string) error {
+	if !strings.Contains(output, "already checked out") && !strings.Contains(output, "already used by worktree") {
+		return fmt.Errorf("git worktree add failed: %s\n%s", err, output)
+	}
+
+	failure := fmt.Errorf("branch %s is already checked out in another worktree", branch)
+	path := checkedOutAt(projectPath, branch)
+	if g, _ := c.FindByPath(path); path != "" && g != nil {
+		return errs.WithHint(failure, fmt.Sprintf("gforge kill %s, or spawn with another --branch", g.Name))
+	}
+	if path != "" {
+		return errs.WithHint(failure, fmt.Sprintf("git -C %s worktree remove %s, or spawn with another --branch", projectPath, path))
+	}
+	return errs.WithHint(failure, "spawn with another --branch")
+}
+
+// checkedOutAt returns the worktree of projectPath that has branch
+// checked out, or ""
+func checkedOutAt(projectPath, branch string) string {
+	list, err := trace.Command("git", "-C", projectPath, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return ""
+	}
+	path := ""
+	for _, line := range strings.Split(string(list), "\n") {
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			path = strings.TrimPrefix(line, "worktree ")
+		case line == "branch refs/heads/"+branch:
+			return path
+		}
+	}
+	return ""
+}
+
+// requireSession returns an error saying how to recover when g has no
+// tmux session to work with
+func (c *Coordinator) requireSession(g *Goblin) error {
+	c.CheckSessions([]*Goblin{g})
+	switch g.Status {
+	case StatusDead:
+		return errs.WithHint(fmt.Errorf("the tmux session of %s has ended (its agent or tmux server exited)", g.Name),
+			fmt.Sprintf("gforge replay %s to rerun its tasks on a fresh goblin, or gforge kill %s", g.Name, g.Name))
+	case StatusArchived:
+		return errs.WithHint(fmt.Errorf("goblin %s is archived", g.Name),
+			fmt.Sprintf("gforge unarchive %s", g.Name))
+	case "stopped", "failed", StatusCompleted:
+		return errs.WithHint(fmt.Errorf("goblin %s is %s and has no session", g.Name, g.Status),
+			fmt.Sprintf("gforge replay %s to rerun its tasks on a fresh goblin", g.Name))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package coordinator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestWorktreeHints(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	taken, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-first"), "gforge/shared", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+
+	// The branch is checked out by a worktree no goblin owns
+	_, err = coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-second"), "gforge/shared", "")
+	if err == nil || !strings.Contains(errs.Hint(err), "worktree remove "+taken) {
+		t.Errorf("Expected a hint to remove %s, got %v (hint %q)", taken, err, errs.Hint(err))
+	}
+
+	// Once a goblin owns the path, the hint names it
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-first", Name: "first", Agent: "claude", Status: "stopped",
+		ProjectPath: repo, WorktreePath: taken, Branch: "gforge/shared", CreatedAt: now, UpdatedAt: now})
+
+	_, err = coord.createWorktree(repo, taken, "gforge/other", "")
+	if !strings.HasPrefix(errs.Hint(err), "gforge kill first") || errs.ExitCode(err) != errs.ExitWorktreeExists {
+		t.Errorf("Expected a hint to kill first, got %v (hint %q)", err, errs.Hint(err))
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	now := time.Now()
+	for name, status := range map[string]string{"done": "stopped", "shelved": StatusArchived} {
+		coord.db.RestoreGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: status,
+			ProjectPath: "/tmp", TmuxSession: "gforge-" + name, CreatedAt: now, UpdatedAt: now})
+	}
+
+	if err := coord.SendTask("done", "more work"); errs.Hint(err) != "gforge replay done to rerun its tasks on a fresh goblin" {
+		t.Errorf("Expected a replay hint, got %v (hint %q)", err, errs.Hint(err))
+	}
+	if err := coord.Attach("shelved", ""); errs.Hint(err) != "gforge unarchive shelved" {
+		t.Errorf("Expected an unarchive hint, got %v (hint %q)", err, errs.Hint(err))
+	}
+}
@@ -0,0 +1,68 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// Outcomes recorded in a killed goblin's tombstone
+const (
+	OutcomeKilled    = "killed" // Still at work when it was killed
+	OutcomeStopped   = "stopped"
+	OutcomeFailed    = "failed" // Failed, or its session ended on its own
+	OutcomeArchived  = "archived"
+	OutcomeCompleted = "completed" // Wrapped up with gforge done
+)
+
+// outcome names how a goblin with the given status ended
+func outcome(status string) string {
+	switch status {
+	case "stopped":
+		return OutcomeStopped
+	case "failed", StatusDead:
+		return OutcomeFailed
+	case StatusArchived:
+		return OutcomeArchived
+	case StatusCompleted:
+		return OutcomeCompleted
+	}
+	return OutcomeKilled
+}
+
+// bury records a tombstone for a goblin about to be killed, with the
+// diffstat of its branch while the worktree still exists. A goblin that
+// can't be recorded is still killed.
+func (c *Coordinator) bury(g *Goblin) {
+	c.CheckSessions([]*Goblin{g})
+
+	t := &storage.Tombstone{
+		GoblinID:  g.ID,
+		Name:      g.Name,
+		Agent:     g.Agent,
+		Project:   g.ProjectPath,
+		Branch:    g.Branch,
+		Outcome:   outcome(g.Status),
+		CreatedAt: g.CreatedAt,
+		EndedAt:   time.Now(),
+	}
+	if g.Status != StatusArchived && isGitRepo(g.ProjectPath) {
+		if stat, err := workspace.BranchDiffStat(g.WorktreePath, g.BaseRef, c.DiffIgnore(g)); err == nil {
+			t.Files, t.Insertions, t.Deletions = stat.Files, stat.Insertions, stat.Deletions
+		}
+	}
+
+	if err := c.db.AddTombstone(t); err != nil && c.log != nil {
+		c.log.Warn("Failed to record goblin history",
+			logging.String("name", g.Name),
+			logging.Err(err))
+	}
+}
+
+// History returns the tombstones of goblins killed at or after since,
+// most recent first
+func (c *Coordinator) History(since time.Time) ([]*storage.Tombstone, error) {
+	return c.db.ListTombstones(since)
+}
@@ -0,0 +1,75 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestKillLeavesTombstone(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-gone"), "gforge/gone", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	spawned := time.Now().Add(-2 * time.Hour)
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-gone", Name: "gone", Agent: "codex", Status: "failed",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/gone", BaseRef: strings.TrimSpace(string(base)),
+		CreatedAt: spawned, UpdatedAt: spawned})
+
+	os.WriteFile(filepath.Join(worktree, "fix.go"), []byte("package fix\n\nfunc Fix() {}\n"), 0644)
+	gitRun(worktree, "add", "fix.go")
+	gitRun(worktree, append(identityArgs(worktree), "commit", "-m", "Add fix")...)
+
+	if err := coord.Kill("gone"); err != nil {
+		t.Fatalf("Kill failed: %v", err)
+	}
+
+	history, err := coord.History(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Expected 1 tombstone, got %d", len(history))
+	}
+	tomb := history[0]
+	if tomb.Name != "gone" || tomb.Agent != "codex" || tomb.Outcome != OutcomeFailed {
+		t.Errorf("Unexpected tombstone %+v", tomb)
+	}
+	if tomb.Files != 1 || tomb.Insertions != 3 {
+		t.Errorf("Expected a diffstat of 1 file +3, got %d files +%d", tomb.Files, tomb.Insertions)
+	}
+	if d := tomb.Duration().Round(time.Hour); d != 2*time.Hour {
+		t.Errorf("Expected a 2h run, got %s", d)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	for status, want := range map[string]string{
+		"running":      OutcomeKilled,
+		"paused":       OutcomeKilled,
+		"stopped":      OutcomeStopped,
+		"failed":       OutcomeFailed,
+		StatusDead:     OutcomeFailed,
+		StatusArchived: OutcomeArchived,
+	} {
+		if got := outcome(status); got != want {
+			t.Errorf("outcome(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// Hook stages configured in a project's .gforge.yaml
+const (
+	HookPostSpawn = "post_spawn"
+	HookPreStop   = "pre_stop"
+)
+
+// runHooks runs a project's hooks for a stage in the goblin's worktree.
+// Hooks are best effort: a failing hook is logged and the rest still run,
+// so a broken setup script never strands a goblin half-created.
+func (c *Coordinator) runHooks(stage string, g *Goblin) []error {
+	if g.WorktreePath == "" {
+		return nil
+	}
+
+	pc, err := config.LoadProject(g.ProjectPath)
+	if err != nil {
+		c.warnHook(stage, g.Name, err)
+		return []error{err}
+	}
+
+	var hooks []string
+	switch stage {
+	case HookPostSpawn:
+		hooks = pc.Hooks.PostSpawn
+	case HookPreStop:
+		hooks = pc.Hooks.PreStop
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		cmd := exec.Command("sh", "-c", hook)
+		cmd.Dir = g.WorktreePath
+		cmd.Env = append(append(os.Environ(), hookEnv(g)...), pc.Git.Env()...)
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			err = fmt.Errorf("%s hook %q failed: %s\n%s", stage, hook, err, strings.TrimSpace(string(output)))
+			c.warnHook(stage, g.Name, err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// hookEnv describes the goblin to hook scripts
+func hookEnv(g *Goblin) []string {
+	return []string{
+		"GFORGE_GOBLIN=" + g.Name,
+		"GFORGE_GOBLIN_ID=" + g.ID,
+		"GFORGE_BRANCH=" + g.Branch,
+		"GFORGE_PROJECT=" + g.ProjectPath,
+		"GFORGE_WORKTREE=" + g.WorktreePath,
+	}
+}
+
+func (c *Coordinator) warnHook(stage, name string, err error) {
+	if c.log != nil {
+		c.log.Warn("Project hook failed",
+			logging.String("stage", stage),
+			logging.String("name", name),
+			logging.Err(err))
+	}
+}
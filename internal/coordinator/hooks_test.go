@@ -0,0 +1,57 @@
+package coordinator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestRunHooks(t *testing.T) {
+	project := t.TempDir()
+	worktree := t.TempDir()
+
+	os.WriteFile(filepath.Join(project, config.ProjectFile), []byte(`
+hooks:
+  post_spawn:
+    - echo "$GFORGE_GOBLIN on $GFORGE_BRANCH" > setup.txt
+    - exit 3
+    - touch after-failure.txt
+  pre_stop:
+    - touch stopped.txt
+`), 0644)
+
+	coord := New(nil, nil, nil)
+	g := &Goblin{Name: "hooked", Branch: "gforge/hooked", ProjectPath: project, WorktreePath: worktree}
+
+	errs := coord.runHooks(HookPostSpawn, g)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "exit 3") {
+		t.Errorf("Expected one failing hook, got %v", errs)
+	}
+
+	data, err := os.ReadFile(filepath.Join(worktree, "setup.txt"))
+	if err != nil || strings.TrimSpace(string(data)) != "hooked on gforge/hooked" {
+		t.Errorf("Expected hook to run in worktree with goblin env, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "after-failure.txt")); err != nil {
+		t.Error("Hooks after a failure should still run")
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "stopped.txt")); err == nil {
+		t.Error("pre_stop hooks should not run at post_spawn")
+	}
+
+	if errs := coord.runHooks(HookPreStop, g); len(errs) != 0 {
+		t.Errorf("Unexpected pre_stop errors: %v", errs)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "stopped.txt")); err != nil {
+		t.Error("Expected pre_stop hook to run")
+	}
+
+	// Projects without .gforge.yaml have no hooks
+	g.ProjectPath = t.TempDir()
+	if errs := coord.runHooks(HookPostSpawn, g); len(errs) != 0 {
+		t.Errorf("Expected no errors without project file, got %v", errs)
+	}
+}
@@ -0,0 +1,47 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/hours"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// EventDeferred records autonomous work held back until working hours
+const EventDeferred = "deferred"
+
+// WaitForWorkingHours blocks autonomous work outside working_hours until
+// the next window opens, recording a deferred event against goblinID/name
+// (goblinID may be empty for work not tied to a goblin yet). work describes
+// what is waiting.
+func (c *Coordinator) WaitForWorkingHours(ctx context.Context, goblinID, name, work string) error {
+	policy, err := hours.New(c.cfg.WorkingHours)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if policy.Open(now) {
+		return nil
+	}
+
+	next := policy.Next(now)
+	c.recordEvent(goblinID, name, EventDeferred,
+		fmt.Sprintf("%s deferred until %s", work, next.Format("Mon 15:04")))
+	if c.log != nil {
+		c.log.Info("Outside working hours, deferring",
+			logging.String("work", work),
+			logging.Time("until", next))
+	}
+
+	t := time.NewTimer(time.Until(next))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWaitForWorkingHours(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if err := coord.WaitForWorkingHours(context.Background(), "", "bench", "task"); err != nil {
+		t.Fatalf("Expected no wait without working hours, got %v", err)
+	}
+
+	// A one-minute window that opened an hour ago is closed now
+	now := time.Now()
+	start := now.Add(-time.Hour)
+	cfg.WorkingHours.Enabled = true
+	cfg.WorkingHours.Days = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	cfg.WorkingHours.Start = start.Format("15:04")
+	cfg.WorkingHours.End = start.Add(time.Minute).Format("15:04")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := coord.WaitForWorkingHours(ctx, "id-1", "coder", "replay task 1"); err != context.DeadlineExceeded {
+		t.Fatalf("Expected the work held until the deadline, got %v", err)
+	}
+
+	events, _ := coord.db.ListEvents(now.Add(-time.Minute))
+	if len(events) != 1 || events[0].Type != EventDeferred {
+		t.Fatalf("Expected one deferred event, got %+v", events)
+	}
+	want := fmt.Sprintf("replay task 1 deferred until %s", start.AddDate(0, 0, 1).Format("Mon 15:04"))
+	if events[0].Detail != want {
+		t.Errorf("Expected %q, got %q", want, events[0].Detail)
+	}
+}
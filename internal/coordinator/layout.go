@@ -0,0 +1,110 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// DefaultWorktreeLayout keeps every worktree in a directory named after
+// the goblin's ID
+const DefaultWorktreeLayout = "{id}"
+
+// worktreeBase returns the directory a project's worktrees live under:
+// worktree_base from its .gforge.yaml, else general.worktree_base
+func (c *Coordinator) worktreeBase(projectPath string, project *config.ProjectConfig) string {
+	if project != nil {
+		if base := project.WorktreeDir(projectPath); base != "" {
+			return base
+		}
+	}
+	return c.cfg.WorktreeBase
+}
+
+// worktreePath lays out a new goblin's worktree under the project's base.
+// The layout (worktree_layout, e.g. "{project}/{name}") may use {project},
+// {name}, {id} and {branch}; it must stay inside the base.
+func (c *Coordinator) worktreePath(projectPath string, project *config.ProjectConfig, goblinID, name, branch string) (string, error) {
+	layout := c.cfg.General.WorktreeLayout
+	if project != nil && project.WorktreeLayout != "" {
+		layout = project.WorktreeLayout
+	}
+	if layout == "" {
+		layout = DefaultWorktreeLayout
+	}
+
+	flat := func(s string) string { return strings.ReplaceAll(s, "/", "-") }
+	rel := strings.NewReplacer(
+		"{project}", flat(filepath.Base(projectPath)),
+		"{name}", flat(name),
+		"{id}", goblinID,
+		"{branch}", flat(branch),
+	).Replace(layout)
+
+	rel = filepath.Clean(rel)
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("worktree_layout %q must name a directory inside the worktree base", layout)
+	}
+
+	base := c.worktreeBase(projectPath, project)
+	path := filepath.Join(base, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	return path, nil
+}
+
+// ownsWorktree reports whether path lies under one of the directories
+// gforge creates worktrees in for the project, so it is safe to delete
+func (c *Coordinator) ownsWorktree(projectPath, path string) bool {
+	bases := []string{c.cfg.WorktreeBase}
+	if project, err := config.LoadProject(projectPath); err == nil {
+		bases = append(bases, c.worktreeBase(projectPath, project))
+	}
+
+	for _, base := range bases {
+		rel, err := filepath.Rel(base, path)
+		if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// copyPaths copies the project's copy_paths (untracked dependencies and
+// generated assets) into a new worktree. Copies are best effort: a
+// missing path only costs the goblin a rebuild.
+func (c *Coordinator) copyPaths(projectPath, worktreePath string, paths []string) {
+	for _, rel := range paths {
+		rel = filepath.Clean(rel)
+		if filepath.IsAbs(rel) || rel == "." || strings.HasPrefix(rel, "..") {
+			c.warnCopy(rel, fmt.Errorf("copy_paths must be inside the project"))
+			continue
+		}
+
+		src := filepath.Join(projectPath, rel)
+		dst := filepath.Join(worktreePath, rel)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := workspace.CopyPath(src, dst, c.cfg.Git.CopyMethod); err != nil {
+			c.warnCopy(rel, err)
+		}
+	}
+}
+
+func (c *Coordinator) warnCopy(path string, err error) {
+	if c.log != nil {
+		c.log.Warn("Failed to copy into worktree",
+			logging.String("path", path),
+			logging.Err(err))
+	}
+}
@@ -0,0 +1,73 @@
+package coordinator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestWorktreePath(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	project := filepath.Join(t.TempDir(), "webapp")
+	os.MkdirAll(project, 0755)
+
+	path, err := coord.worktreePath(project, &config.ProjectConfig{}, "ab12cd34", "fixer", "gforge/fixer")
+	if err != nil || path != filepath.Join(cfg.WorktreeBase, "ab12cd34") {
+		t.Errorf("Expected the ID under the global base by default, got %s (%v)", path, err)
+	}
+
+	cfg.General.WorktreeLayout = "{project}/{name}-{branch}"
+	path, _ = coord.worktreePath(project, &config.ProjectConfig{}, "ab12cd34", "fixer", "gforge/fixer")
+	if path != filepath.Join(cfg.WorktreeBase, "webapp", "fixer-gforge-fixer") {
+		t.Errorf("Unexpected layout result %s", path)
+	}
+
+	// The project's own base and layout win, relative to the project
+	pc := &config.ProjectConfig{WorktreeBase: "../webapp-worktrees", WorktreeLayout: "{name}"}
+	path, _ = coord.worktreePath(project, pc, "ab12cd34", "fixer", "gforge/fixer")
+	want := filepath.Join(filepath.Dir(project), "webapp-worktrees", "fixer")
+	if path != want {
+		t.Errorf("Expected %s, got %s", want, path)
+	}
+
+	// Worktrees under the project's base may be removed; the project not
+	os.WriteFile(filepath.Join(project, config.ProjectFile), []byte("worktree_base: ../webapp-worktrees\n"), 0644)
+	if !coord.ownsWorktree(project, want) {
+		t.Error("Expected a worktree under the project's base to be owned")
+	}
+	if coord.ownsWorktree(project, project) {
+		t.Error("Expected the project itself never owned")
+	}
+
+	for _, layout := range []string{"../{name}", "/tmp/{name}", "."} {
+		pc.WorktreeLayout = layout
+		if _, err := coord.worktreePath(project, pc, "ab12cd34", "fixer", "gforge/fixer"); err == nil {
+			t.Errorf("Expected layout %q rejected", layout)
+		}
+	}
+}
+
+func TestCopyPaths(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	project := t.TempDir()
+	worktree := t.TempDir()
+	os.MkdirAll(filepath.Join(project, "node_modules", "left-pad"), 0755)
+	os.WriteFile(filepath.Join(project, "node_modules", "left-pad", "index.js"), []byte("pad"), 0644)
+	os.WriteFile(filepath.Join(worktree, "keep.txt"), []byte("worktree"), 0644)
+	os.WriteFile(filepath.Join(project, "keep.txt"), []byte("project"), 0644)
+
+	coord.copyPaths(project, worktree, []string{"node_modules", "keep.txt", "missing", "../outside"})
+
+	if _, err := os.Stat(filepath.Join(worktree, "node_modules", "left-pad", "index.js")); err != nil {
+		t.Errorf("Expected node_modules copied: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktree, "keep.txt")); string(data) != "worktree" {
+		t.Errorf("Expected existing worktree files left alone, got %q", data)
+	}
+}
@@ -0,0 +1,124 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/webhook"
+)
+
+// EventCommitted records a commit added to a goblin's branch, by its
+// agent or by gforge
+const EventCommitted = "committed"
+
+// maxCommitEvents caps the commits reported at once, e.g. after a rebase
+const maxCommitEvents = 20
+
+// lifecycleEvents are the recorded events emitted as lifecycle events,
+// under the names outbound webhooks know them by
+var lifecycleEvents = map[string]string{
+	EventSpawned:   webhook.EventSpawned,
+	EventCompleted: webhook.EventCompleted,
+	EventExited:    webhook.EventFailed,
+	EventStopped:   webhook.EventStopped,
+	EventKilled:    webhook.EventKilled,
+	EventCommitted: webhook.EventCommit,
+}
+
+// Lifecycle returns the manager goblin lifecycle events are emitted
+// through, to subscribe to them
+func (c *Coordinator) Lifecycle() *agents.LifecycleManager {
+	return c.lifecycle
+}
+
+// emitLifecycle emits a recorded event that is part of a goblin's
+// lifecycle and waits for its handlers, so a command doesn't exit before
+// its webhooks are delivered
+func (c *Coordinator) emitLifecycle(goblinID, name, eventType, detail string) {
+	kind, ok := lifecycleEvents[eventType]
+	if !ok {
+		return
+	}
+
+	event := agents.LifecycleEvent{
+		Type:     kind,
+		GoblinID: goblinID,
+		Details:  map[string]string{"name": name, "detail": detail},
+	}
+	if eventType == EventCommitted {
+		event.Details["commit"], event.Details["detail"], _ = strings.Cut(detail, " ")
+	}
+	// A killed goblin is already gone
+	if g, _ := c.db.GetGoblin(goblinID); g != nil {
+		event.AgentName = g.Agent
+		event.Details["project"] = g.ProjectPath
+		event.Details["branch"] = g.Branch
+	}
+
+	c.lifecycle.Emit(event)
+	c.lifecycle.Wait()
+}
+
+// RecordCommits records each commit added to a goblin's branch since the
+// last one recorded (or since its base), oldest first, and returns how
+// many there were. Commits are found by comparing HEADs, so ones made by
+// the agent count as much as gforge's own.
+func (c *Coordinator) RecordCommits(g *Goblin) (int, error) {
+	if g.WorktreePath == "" || g.WorktreePath == g.ProjectPath || !isGitRepo(g.WorktreePath) {
+		return 0, nil
+	}
+	head := headCommit(g.WorktreePath)
+	if head == "" {
+		return 0, nil
+	}
+
+	seen, err := c.db.CommitSeen(g.ID)
+	if err != nil {
+		return 0, err
+	}
+	if seen == "" {
+		seen = g.BaseRef
+	}
+	if seen == head {
+		return 0, nil
+	}
+	// With no base to start from, or a branch rewritten since, only
+	// later commits can be told apart
+	if seen == "" || gitRun(g.WorktreePath, "cat-file", "-e", seen+"^{commit}") != nil {
+		return 0, c.db.SetCommitSeen(g.ID, head)
+	}
+
+	output, err := trace.Command("git", "-C", g.WorktreePath, "log", "--reverse", "--format=%h %s", head, "^"+seen).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list commits of %s: %w", g.Name, err)
+	}
+
+	commits := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if commits[0] == "" {
+		commits = nil
+	}
+	if len(commits) > maxCommitEvents {
+		commits = commits[len(commits)-maxCommitEvents:]
+	}
+	for _, commit := range commits {
+		c.recordEvent(g.ID, g.Name, EventCommitted, commit)
+	}
+
+	if err := c.db.SetCommitSeen(g.ID, head); err != nil {
+		return 0, err
+	}
+	return len(commits), nil
+}
+
+// recordCommits records a goblin's new commits, warning when they can't
+// be listed: the commit itself went through
+func (c *Coordinator) recordCommits(g *Goblin) {
+	if _, err := c.RecordCommits(g); err != nil && c.log != nil {
+		c.log.Warn("Failed to record commits",
+			logging.String("goblin", g.Name),
+			logging.Err(err))
+	}
+}
@@ -0,0 +1,95 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/webhook"
+)
+
+func TestRecordCommits(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	var (
+		mu        sync.Mutex
+		delivered []webhook.Delivery
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var d webhook.Delivery
+		json.NewDecoder(r.Body).Decode(&d)
+		mu.Lock()
+		delivered = append(delivered, d)
+		mu.Unlock()
+	}))
+	defer server.Close()
+	cfg.Webhooks.Outbound = []config.OutboundWebhook{{URL: server.URL}}
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	worktree := filepath.Join(t.TempDir(), "wt")
+	if out, err := exec.Command("git", "-C", repo, "worktree", "add", "-b", "gforge/wt", worktree).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to add worktree: %v\n%s", err, out)
+	}
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "wt", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/wt", BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("wt")
+
+	if n, err := coord.RecordCommits(g); err != nil || n != 0 {
+		t.Fatalf("Expected nothing new on a fresh branch, got %d, %v", n, err)
+	}
+
+	// As the agent would
+	for _, msg := range []string{"Add a", "Add b"} {
+		os.WriteFile(filepath.Join(worktree, strings.ToLower(msg[4:])+".txt"), []byte(msg), 0644)
+		exec.Command("git", "-C", worktree, "add", "-A").Run()
+		exec.Command("git", "-C", worktree, "commit", "-m", msg).Run()
+	}
+	if n, err := coord.RecordCommits(g); err != nil || n != 2 {
+		t.Fatalf("Expected 2 new commits, got %d, %v", n, err)
+	}
+	if n, _ := coord.RecordCommits(g); n != 0 {
+		t.Errorf("Expected commits reported once, got %d more", n)
+	}
+
+	// gforge's own commits are reported as they are made
+	os.WriteFile(filepath.Join(worktree, "c.txt"), []byte("c"), 0644)
+	if _, err := coord.CommitWork(g, "gforge: finish wt"); err != nil {
+		t.Fatalf("CommitWork failed: %v", err)
+	}
+
+	var subjects []string
+	for _, d := range delivered {
+		if d.Event != webhook.EventCommit || d.Goblin != "wt" || d.Agent != "claude" || d.Branch != "gforge/wt" || d.Commit == "" {
+			t.Errorf("Unexpected delivery: %+v", d)
+		}
+		subjects = append(subjects, d.Detail)
+	}
+	if got := strings.Join(subjects, ", "); got != "Add a, Add b, gforge: finish wt" {
+		t.Errorf("Expected the three commits oldest first, got %s", got)
+	}
+
+	// Events outside the lifecycle aren't sent
+	delivered = nil
+	coord.Pin(g.Name, true)
+	if _, err := coord.Complete(g.ID); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if len(delivered) != 1 || delivered[0].Event != webhook.EventCompleted {
+		t.Errorf("Expected only completed delivered, got %+v", delivered)
+	}
+}
@@ -0,0 +1,215 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// Events recorded for path locks
+const (
+	EventLocked       = "locked"        // A goblin claimed paths
+	EventUnlocked     = "unlocked"      // A goblin released paths
+	EventLockConflict = "lock_conflict" // A task named paths another goblin holds
+)
+
+// Conflict policies (locks.on_conflict)
+const (
+	LockConflictWarn  = "warn"
+	LockConflictQueue = "queue"
+)
+
+// LockPath turns a path given on the command line into the form locks are
+// stored in: relative to the project, with a trailing slash for
+// directories. "src/auth/...", "src/auth/**" and "src/auth/" all lock the
+// directory.
+func LockPath(projectPath, path string) (string, error) {
+	dir := false
+	for _, suffix := range []string{"/...", "/**", "/"} {
+		if strings.HasSuffix(path, suffix) {
+			path = strings.TrimSuffix(path, suffix)
+			dir = true
+			break
+		}
+	}
+
+	clean := filepath.ToSlash(filepath.Clean(path))
+	if clean == "." || filepath.IsAbs(path) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("lock paths inside the project, not %q", path)
+	}
+	if !dir {
+		if info, err := os.Stat(filepath.Join(projectPath, clean)); err == nil && info.IsDir() {
+			dir = true
+		}
+	}
+	if dir {
+		clean += "/"
+	}
+	return clean, nil
+}
+
+// lockOverlaps reports whether two lock paths cover any of the same files
+func lockOverlaps(a, b string) bool {
+	if a == b {
+		return true
+	}
+	return strings.HasSuffix(a, "/") && strings.HasPrefix(b, a) ||
+		strings.HasSuffix(b, "/") && strings.HasPrefix(a, b)
+}
+
+// Lock claims paths in a goblin's project so other goblins' tasks that
+// name them are warned about or queued (locks.on_conflict). Paths held by
+// another goblin fail with errs.ErrPathLocked and nothing is locked. It
+// returns the paths as stored.
+func (c *Coordinator) Lock(g *Goblin, paths []string) ([]string, error) {
+	var locked []string
+	for _, p := range paths {
+		lp, err := LockPath(g.ProjectPath, p)
+		if err != nil {
+			return nil, err
+		}
+		locked = append(locked, lp)
+	}
+
+	conflicts, err := c.LockConflicts(g.ProjectPath, g.ID, locked)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%w: %s", errs.ErrPathLocked, describeLocks(conflicts))
+	}
+
+	for _, lp := range locked {
+		if err := c.db.AddPathLock(g.ID, g.ProjectPath, lp); err != nil {
+			return nil, err
+		}
+	}
+	c.recordEvent(g.ID, g.Name, EventLocked, strings.Join(locked, " "))
+	return locked, nil
+}
+
+// Unlock releases a goblin's claims on paths, or all of them when none are
+// given, and returns how many were released
+func (c *Coordinator) Unlock(g *Goblin, paths []string) (int, error) {
+	var unlocked []string
+	for _, p := range paths {
+		lp, err := LockPath(g.ProjectPath, p)
+		if err != nil {
+			return 0, err
+		}
+		unlocked = append(unlocked, lp)
+	}
+
+	n, err := c.db.RemovePathLocks(g.ID, unlocked...)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		detail := strings.Join(unlocked, " ")
+		if detail == "" {
+			detail = "all"
+		}
+		c.recordEvent(g.ID, g.Name, EventUnlocked, detail)
+	}
+	return n, nil
+}
+
+// releaseLocks drops every claim a goblin holds once it is done working
+func (c *Coordinator) releaseLocks(g *Goblin) {
+	if n, err := c.db.RemovePathLocks(g.ID); err == nil && n > 0 {
+		c.recordEvent(g.ID, g.Name, EventUnlocked, "all")
+	}
+}
+
+// Locks lists the paths claimed in a project, or in all of them when
+// projectPath is empty
+func (c *Coordinator) Locks(projectPath string) ([]*storage.PathLock, error) {
+	return c.db.ListPathLocks(projectPath)
+}
+
+// LockConflicts returns the locks other goblins than goblinID hold in a
+// project that overlap paths (as stored by LockPath)
+func (c *Coordinator) LockConflicts(projectPath, goblinID string, paths []string) ([]*storage.PathLock, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	locks, err := c.db.ListPathLocks(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []*storage.PathLock
+	for _, l := range locks {
+		if l.GoblinID == goblinID {
+			continue
+		}
+		for _, p := range paths {
+			if lockOverlaps(l.Path, p) {
+				conflicts = append(conflicts, l)
+				break
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// TaskLockConflicts returns the locks other goblins hold on the files and
+// directories a task names, including ones that don't exist yet
+func (c *Coordinator) TaskLockConflicts(g *Goblin, task string) ([]*storage.PathLock, error) {
+	root := g.WorktreePath
+	if _, err := os.Stat(root); err != nil {
+		root = g.ProjectPath
+	}
+	return c.LockConflicts(g.ProjectPath, g.ID, append(workspace.TaskPaths(root, task), workspace.PathWords(task)...))
+}
+
+// checkLocks handles a task about to be sent that names paths other
+// goblins hold: it records a lock_conflict event and, with
+// locks.on_conflict set to warn, lets the task through. Set to queue, it
+// fails with errs.ErrPathLocked; SubmitTask queues such a task instead,
+// for SendNextTask to send once the paths are free.
+func (c *Coordinator) checkLocks(g *Goblin, task string) error {
+	conflicts, err := c.TaskLockConflicts(g, task)
+	if err != nil || len(conflicts) == 0 {
+		return err
+	}
+	c.recordEvent(g.ID, g.Name, EventLockConflict, describeLocks(conflicts))
+
+	switch c.cfg.Locks.OnConflict {
+	case "", LockConflictWarn:
+		if c.log != nil {
+			c.log.Warn("Task names paths locked by other goblins",
+				logging.String("goblin", g.Name),
+				logging.String("locks", describeLocks(conflicts)))
+		}
+		return nil
+	case LockConflictQueue:
+		return errs.WithHint(fmt.Errorf("%w: %s", errs.ErrPathLocked, describeLocks(conflicts)),
+			fmt.Sprintf("gforge task -g %s without --now queues it until they are unlocked", g.Name))
+	}
+	return fmt.Errorf("unknown locks.on_conflict: %s (use warn or queue)", c.cfg.Locks.OnConflict)
+}
+
+// heldBack returns the locks that keep a task in its goblin's queue: the
+// ones it names that other goblins hold, when locks.on_conflict is queue
+func (c *Coordinator) heldBack(g *Goblin, task string) ([]*storage.PathLock, error) {
+	if c.cfg.Locks.OnConflict != LockConflictQueue {
+		return nil, nil
+	}
+	return c.TaskLockConflicts(g, task)
+}
+
+// describeLocks renders locks as "src/auth/ by fixer, api.go by tester"
+func describeLocks(locks []*storage.PathLock) string {
+	parts := make([]string, len(locks))
+	for i, l := range locks {
+		parts[i] = l.Path + " by " + l.GoblinName
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,127 @@
+package coordinator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestLockPath(t *testing.T) {
+	project := t.TempDir()
+	os.MkdirAll(filepath.Join(project, "src", "auth"), 0755)
+
+	tests := []struct {
+		path, want string
+	}{
+		{"src/auth/...", "src/auth/"},
+		{"src/auth/**", "src/auth/"},
+		{"./src/auth", "src/auth/"}, // An existing directory
+		{"src/auth/login.go", "src/auth/login.go"},
+		{"go.mod", "go.mod"},
+	}
+	for _, tt := range tests {
+		if got, err := LockPath(project, tt.path); err != nil || got != tt.want {
+			t.Errorf("LockPath(%q) = %q, %v; want %q", tt.path, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{".", "../elsewhere", "/etc/passwd"} {
+		if _, err := LockPath(project, bad); err == nil {
+			t.Errorf("Expected LockPath(%q) to fail", bad)
+		}
+	}
+}
+
+func TestLockOverlaps(t *testing.T) {
+	if !lockOverlaps("src/auth/", "src/auth/login.go") || !lockOverlaps("src/auth/login.go", "src/") {
+		t.Error("Expected a directory to overlap the files inside it")
+	}
+	if lockOverlaps("src/auth/", "src/authz/") || lockOverlaps("go.mod", "go.sum") {
+		t.Error("Expected separate paths not to overlap")
+	}
+}
+
+func TestLocks(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	project := t.TempDir()
+	os.MkdirAll(filepath.Join(project, "src", "auth"), 0755)
+	os.WriteFile(filepath.Join(project, "src", "auth", "login.go"), []byte("package auth\n"), 0644)
+	now := time.Now()
+	for _, name := range []string{"coder", "tester"} {
+		coord.db.RestoreGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "stopped",
+			ProjectPath: project, WorktreePath: project, CreatedAt: now, UpdatedAt: now})
+	}
+	coder, _ := coord.Get("coder")
+	tester, _ := coord.Get("tester")
+
+	if _, err := coord.Lock(coder, []string{"src/auth/..."}); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if _, err := coord.Lock(tester, []string{"src/auth/login.go"}); !errors.Is(err, errs.ErrPathLocked) {
+		t.Errorf("Expected a file in a locked directory to be refused, got %v", err)
+	}
+
+	// coder's own tasks may name its paths; tester's are flagged
+	if conflicts, _ := coord.TaskLockConflicts(coder, "Fix src/auth/login.go"); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts with its own lock, got %v", conflicts)
+	}
+	conflicts, _ := coord.TaskLockConflicts(tester, "Add tests for src/auth/login.go")
+	if len(conflicts) != 1 || conflicts[0].GoblinName != "coder" {
+		t.Fatalf("Expected coder's lock in the way, got %v", conflicts)
+	}
+
+	// Files locked before they exist are matched by name
+	if _, err := coord.Lock(coder, []string{"src/auth/token.go"}); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if conflicts, _ := coord.TaskLockConflicts(tester, "Create src/auth/token.go"); len(conflicts) != 2 {
+		t.Errorf("Expected the directory and new file locks, got %v", conflicts)
+	}
+
+	// warn lets the task through; queue refuses to send it straight away
+	if err := coord.checkLocks(tester, "Add tests for src/auth/login.go"); err != nil {
+		t.Errorf("Expected warn to let the task through, got %v", err)
+	}
+	cfg.Locks = config.LocksConfig{OnConflict: LockConflictQueue, MaxWait: time.Hour}
+	if err := coord.checkLocks(tester, "Add tests for src/auth/login.go"); !errors.Is(err, errs.ErrPathLocked) {
+		t.Errorf("Expected the task refused, got %v", err)
+	}
+
+	// Queued, it waits for the paths, and is dropped after max_wait
+	coord.db.EnqueueTask(tester.ID, "Add tests for src/auth/login.go")
+	if next, err := coord.SendNextTask("tester"); err != nil || next != nil {
+		t.Errorf("Expected the task held back, got %v, %v", next, err)
+	}
+	if queue, _ := coord.TaskQueue(tester); len(queue) != 1 {
+		t.Fatalf("Expected the task still queued, got %v", queue)
+	}
+	cfg.Locks.MaxWait = 0
+	if _, err := coord.SendNextTask("tester"); !errors.Is(err, errs.ErrPathLocked) {
+		t.Errorf("Expected the task dropped after max_wait, got %v", err)
+	}
+	if queue, _ := coord.TaskQueue(tester); len(queue) != 0 {
+		t.Errorf("Expected the queue empty, got %v", queue)
+	}
+
+	// Completing coder frees its paths
+	if _, err := coord.Complete("coder"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if err := coord.checkLocks(tester, "Add tests for src/auth/login.go"); err != nil {
+		t.Errorf("Expected the task through once unlocked, got %v", err)
+	}
+	if _, err := coord.Lock(tester, []string{"src/auth/login.go"}); err != nil {
+		t.Errorf("Expected the path free, got %v", err)
+	}
+	if n, err := coord.Unlock(tester, nil); err != nil || n != 1 {
+		t.Errorf("Expected 1 lock released, got %d, %v", n, err)
+	}
+}
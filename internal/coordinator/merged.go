@@ -0,0 +1,52 @@
+package coordinator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+)
+
+// prStateMerged is the state gh reports for a merged pull request
+const prStateMerged = "MERGED"
+
+// prClient is the part of the GitHub client merge checks use
+type prClient interface {
+	GetPRByURL(url string) (*integrations.PullRequest, error)
+}
+
+// newPRClient returns a GitHub client working in dir; tests replace it
+var newPRClient = func(dir string) prClient {
+	gh := integrations.NewGitHubClient()
+	gh.Dir = dir
+	return gh
+}
+
+// CheckMerged looks up the pull request gforge push last opened for each
+// goblin and records a pr_merged event for those merged since they were
+// last checked, returning those goblins. A goblin whose lookup fails is
+// skipped; the failures are returned together.
+func (c *Coordinator) CheckMerged(goblins []*Goblin) ([]*Goblin, error) {
+	var (
+		merged   []*Goblin
+		failures []error
+	)
+	for _, g := range goblins {
+		url := c.lastEventDetail(g, EventPR)
+		if url == "" || c.lastEventDetail(g, EventPRMerged) == url {
+			continue
+		}
+
+		pr, err := newPRClient(g.WorktreePath).GetPRByURL(url)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", g.Name, err))
+			continue
+		}
+		if pr.State != prStateMerged {
+			continue
+		}
+		c.recordEvent(g.ID, g.Name, EventPRMerged, url)
+		merged = append(merged, g)
+	}
+	return merged, errors.Join(failures...)
+}
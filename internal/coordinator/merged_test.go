@@ -0,0 +1,59 @@
+package coordinator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// fakePRs answers pull request lookups by URL
+type fakePRs map[string]string
+
+func (f fakePRs) GetPRByURL(url string) (*integrations.PullRequest, error) {
+	state, ok := f[url]
+	if !ok {
+		return nil, fmt.Errorf("no such PR: %s", url)
+	}
+	return &integrations.PullRequest{URL: url, State: state}, nil
+}
+
+func TestCheckMerged(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	prs := fakePRs{"https://github.com/acme/app/pull/1": "OPEN"}
+	orig := newPRClient
+	newPRClient = func(string) prClient { return prs }
+	defer func() { newPRClient = orig }()
+
+	for _, name := range []string{"fixer", "idle"} {
+		coord.db.CreateGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "completed",
+			ProjectPath: "/tmp", WorktreePath: "/tmp", Branch: "gforge/" + name})
+	}
+	coord.RecordEvent("fixer", EventPR, "https://github.com/acme/app/pull/1")
+	goblins, _ := coord.List()
+
+	if merged, err := coord.CheckMerged(goblins); err != nil || len(merged) != 0 {
+		t.Fatalf("Expected nothing merged while the PR is open, got %v, %v", merged, err)
+	}
+
+	prs["https://github.com/acme/app/pull/1"] = "MERGED"
+	merged, err := coord.CheckMerged(goblins)
+	if err != nil || len(merged) != 1 || merged[0].Name != "fixer" {
+		t.Fatalf("Expected fixer merged, got %v, %v", merged, err)
+	}
+	if merged, _ := coord.CheckMerged(goblins); len(merged) != 0 {
+		t.Errorf("Expected a merge recorded once, got %v", merged)
+	}
+	if e, _ := coord.db.LastEvent("id-fixer", EventPRMerged); e == nil || e.Detail != "https://github.com/acme/app/pull/1" {
+		t.Errorf("Expected a pr_merged event, got %+v", e)
+	}
+
+	// A later PR from the same goblin is followed in turn
+	coord.RecordEvent("fixer", EventPR, "https://github.com/acme/app/pull/2")
+	if _, err := coord.CheckMerged(goblins); err == nil {
+		t.Error("Expected a failed lookup reported")
+	}
+}
@@ -0,0 +1,66 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// How goblins work on projects that are not git repositories
+// (git.non_git)
+const (
+	NonGitInit   = "init"   // Copy into the workspace and git init it, so diffs work
+	NonGitCopy   = "copy"   // Copy into the workspace without version control
+	NonGitDirect = "direct" // Work in the project directory itself
+)
+
+// isGitRepo reports whether path is the top of a git checkout (a .git
+// directory, or the .git file of a worktree)
+func isGitRepo(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// isolateProject gives a non-git project its own workspace at
+// worktreePath according to git.non_git, returning the directory the
+// agent works in
+func (c *Coordinator) isolateProject(projectPath, worktreePath, branch string) (string, error) {
+	mode := c.cfg.Git.NonGit
+	if mode == "" {
+		mode = NonGitInit
+	}
+
+	switch mode {
+	case NonGitDirect:
+		return projectPath, nil
+	case NonGitCopy, NonGitInit:
+	default:
+		return "", fmt.Errorf("unknown git.non_git mode: %s (use init, copy or direct)", mode)
+	}
+
+	if err := workspace.CopyTree(projectPath, worktreePath, c.cfg.Git.CopyMethod); err != nil {
+		os.RemoveAll(worktreePath)
+		return "", fmt.Errorf("failed to copy %s: %w", projectPath, err)
+	}
+	if mode == NonGitCopy {
+		return worktreePath, nil
+	}
+
+	// A baseline commit of the untouched copy gives diffs, checkpoints and
+	// shutdown commits something to work against
+	steps := [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		append(identityArgs(worktreePath), "commit", "-q", "--no-verify", "--allow-empty", "-m", "gforge: baseline of "+filepath.Base(projectPath)),
+		{"checkout", "-q", "-b", branch},
+	}
+	for _, args := range steps {
+		if err := gitRun(worktreePath, args...); err != nil {
+			os.RemoveAll(worktreePath)
+			return "", fmt.Errorf("failed to initialise workspace: git %s: %w", args[0], err)
+		}
+	}
+	return worktreePath, nil
+}
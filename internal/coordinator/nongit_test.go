@@ -0,0 +1,68 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsolateProject(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	project := t.TempDir()
+	os.WriteFile(filepath.Join(project, "main.txt"), []byte("original\n"), 0644)
+
+	// init: an isolated copy with a baseline commit, so edits show as diffs
+	workdir, err := coord.createWorktree(project, filepath.Join(cfg.WorktreeBase, "g-init"), "gforge/init", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	if workdir == project {
+		t.Fatal("Expected an isolated workspace, got the project directory")
+	}
+	os.WriteFile(filepath.Join(workdir, "main.txt"), []byte("changed\n"), 0644)
+
+	diff, err := exec.Command("git", "-C", workdir, "diff", "--stat").Output()
+	if err != nil || !strings.Contains(string(diff), "main.txt") {
+		t.Errorf("Expected the edit visible as a diff, got %q (%v)", diff, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(project, "main.txt")); string(data) != "original\n" {
+		t.Errorf("Expected the project untouched, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(project, ".git")); err == nil {
+		t.Error("Expected no repository created in the project")
+	}
+
+	// copy: isolated but without version control
+	cfg.Git.NonGit = NonGitCopy
+	workdir, err = coord.createWorktree(project, filepath.Join(cfg.WorktreeBase, "g-copy"), "gforge/copy", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	if isGitRepo(workdir) {
+		t.Error("Expected a plain copy")
+	}
+
+	// direct: the project itself, which cleanup must never delete
+	cfg.Git.NonGit = NonGitDirect
+	workdir, err = coord.createWorktree(project, filepath.Join(cfg.WorktreeBase, "g-direct"), "gforge/direct", "")
+	if err != nil || workdir != project {
+		t.Fatalf("Expected the project directory, got %s (%v)", workdir, err)
+	}
+	coord.removeWorktree(project, workdir)
+	if _, err := os.Stat(filepath.Join(project, "main.txt")); err != nil {
+		t.Errorf("Expected the project kept on cleanup: %v", err)
+	}
+
+	cfg.Git.NonGit = "symlink"
+	if _, err := coord.createWorktree(project, filepath.Join(cfg.WorktreeBase, "g-bad"), "gforge/bad", ""); err == nil {
+		t.Error("Expected an unknown mode rejected")
+	}
+}
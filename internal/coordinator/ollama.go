@@ -0,0 +1,133 @@
+package coordinator
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/providers"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// OllamaSession is the tmux session running a gforge-managed ollama server
+const OllamaSession = "gforge-ollama"
+
+var ollamaPoll = 250 * time.Millisecond
+
+// ollamaReachable reports whether an ollama server answers at url
+func ollamaReachable(url string) bool {
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(url + "/api/version")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// OllamaManaged reports whether gforge is running the ollama server
+func (c *Coordinator) OllamaManaged() bool {
+	return trace.Command("tmux", "-L", c.cfg.Tmux.SocketName, "has-session", "-t", OllamaSession).Run() == nil
+}
+
+// ensureOllama starts `ollama serve` in a tmux session for an ollama
+// agent when ollama.manage_server is set and no server is answering. A
+// running server, managed or not, is reused.
+func (c *Coordinator) ensureOllama(agent *agents.Agent) error {
+	if !c.cfg.Ollama.ManageServer || agent.Provider != "ollama" {
+		return nil
+	}
+
+	url := providers.OllamaURL()
+	if ollamaReachable(url) {
+		return nil
+	}
+
+	if !c.OllamaManaged() {
+		args := []string{"-L", c.cfg.Tmux.SocketName, "new-session", "-d", "-s", OllamaSession, "env"}
+		for k, v := range agent.Env {
+			args = append(args, fmt.Sprintf("%s=%s", k, v))
+		}
+		args = append(args, agent.Command, "serve")
+
+		output, err := trace.Retry(trace.DefaultBackoff, c.log, func() *trace.Cmd {
+			return trace.Command("tmux", args...)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start ollama server: %s\n%s", err, output)
+		}
+		if c.log != nil {
+			c.log.Info("Started managed ollama server", logging.String("session", OllamaSession))
+		}
+	}
+
+	timeout := c.cfg.Ollama.StartTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(ollamaPoll) {
+		if ollamaReachable(url) {
+			return nil
+		}
+	}
+
+	c.killTmuxSession(c.cfg.Tmux.SocketName, OllamaSession)
+	return fmt.Errorf("ollama server did not answer at %s within %s", url, timeout)
+}
+
+// releaseOllama stops the managed ollama server once no goblin runs an
+// ollama agent. A goblin whose session ended, or whose agent exited back
+// to the shell, no longer counts, whether or not it has been stopped. A
+// local spawn still under way keeps the server for the goblin it starts.
+func (c *Coordinator) releaseOllama() {
+	if !c.OllamaManaged() {
+		return
+	}
+
+	queue, err := c.db.ListSpawnQueue()
+	if err != nil {
+		return
+	}
+	for _, q := range queue {
+		if q.Local && ProcessAlive(q.PID) {
+			return
+		}
+	}
+
+	goblins, err := c.List()
+	if err != nil {
+		return
+	}
+	registry := agents.NewRegistry()
+	live := make(map[string]map[string]tmux.SessionInfo)
+	for _, g := range goblins {
+		if !active(g.Status) {
+			continue
+		}
+		if a := registry.Get(g.Agent); a == nil || a.Provider != "ollama" {
+			continue
+		}
+
+		socket := c.Socket(g)
+		sessions, ok := live[socket]
+		if !ok {
+			sessions, err = tmux.NewManager(tmux.Config{SocketName: socket}).Sessions()
+			if err != nil {
+				// Can't tell whether it is still running
+				return
+			}
+			live[socket] = sessions
+		}
+		if info, ok := sessions[g.TmuxSession]; ok && !tmux.Shell(info.Command) {
+			return
+		}
+	}
+
+	c.killTmuxSession(c.cfg.Tmux.SocketName, OllamaSession)
+	if c.log != nil {
+		c.log.Info("Stopped managed ollama server", logging.String("session", OllamaSession))
+	}
+}
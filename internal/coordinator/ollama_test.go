@@ -0,0 +1,89 @@
+package coordinator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestEnsureOllamaReusesServer(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"0.5.0"}`))
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	agent := agents.NewRegistry().Get("ollama")
+
+	if err := coord.ensureOllama(agent); err != nil || coord.OllamaManaged() {
+		t.Fatalf("Expected nothing started when management is off, got %v", err)
+	}
+
+	cfg.Ollama.ManageServer = true
+	if err := coord.ensureOllama(agent); err != nil {
+		t.Fatalf("ensureOllama failed: %v", err)
+	}
+	if coord.OllamaManaged() {
+		t.Error("Expected the running server to be reused")
+	}
+
+	if err := coord.ensureOllama(agents.NewRegistry().Get("claude")); err != nil {
+		t.Errorf("Expected non-ollama agents to be ignored, got %v", err)
+	}
+}
+
+func TestReleaseOllama(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	socket := cfg.Tmux.SocketName
+
+	exec.Command("tmux", "-L", socket, "new-session", "-d", "-s", OllamaSession, "sleep", "60").Run()
+	if !coord.OllamaManaged() {
+		t.Fatal("Expected the test session to be running")
+	}
+
+	// The local goblin's agent is running; the cloud one doesn't count
+	exec.Command("tmux", "-L", socket, "new-session", "-d", "-s", "gforge-local", "sleep", "60").Run()
+	defer exec.Command("tmux", "-L", socket, "kill-session", "-t", "gforge-local").Run()
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "local", Agent: "ollama-qwen", Status: "running",
+		ProjectPath: "/tmp", TmuxSession: "gforge-local", TmuxSocket: socket})
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-2", Name: "cloud", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	coord.releaseOllama()
+	if !coord.OllamaManaged() {
+		t.Fatal("Expected the server kept while an ollama goblin is running")
+	}
+
+	// A local spawn under way keeps it too
+	ticket, _ := coord.db.EnqueueSpawn(&storage.QueuedSpawn{Name: "next", Local: true, PID: os.Getpid()})
+	exec.Command("tmux", "-L", socket, "respawn-pane", "-k", "-t", "gforge-local", "sh").Run()
+	coord.releaseOllama()
+	if !coord.OllamaManaged() {
+		t.Fatal("Expected the server kept for a local spawn")
+	}
+	coord.db.DequeueSpawn(ticket)
+
+	// The agent exiting to the shell leaves the goblin running, but not
+	// the server
+	if _, err := coord.Reconcile(); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if coord.OllamaManaged() {
+		t.Error("Expected the server stopped once the last ollama agent exited")
+	}
+	if g, _ := coord.Get("local"); g.Status != "running" {
+		t.Errorf("Expected the goblin itself left running, got %s", g.Status)
+	}
+}
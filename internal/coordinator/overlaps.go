@@ -0,0 +1,176 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventOverlap is recorded when two goblins in a project start changing
+// the same files
+const EventOverlap = "overlap"
+
+// Overlap is a predicted merge conflict: two goblins in one project that
+// change the same files
+type Overlap struct {
+	Goblin *Goblin
+	Other  *Goblin
+	Files  []string
+}
+
+// String renders the overlap as "coder overlaps with reviewer on 3 files:
+// a.go, b.go, c.go", naming at most three files
+func (o Overlap) String() string {
+	files := "files"
+	if len(o.Files) == 1 {
+		files = "file"
+	}
+	shown := o.Files
+	if len(shown) > 3 {
+		shown = append(shown[:3:3], "...")
+	}
+	return fmt.Sprintf("%s overlaps with %s on %d %s: %s",
+		o.Goblin.Name, o.Other.Name, len(o.Files), files, strings.Join(shown, ", "))
+}
+
+// Overlaps compares the files changed by goblins sharing a project and
+// returns each pair that changes some of the same ones, in name order.
+// Goblins no longer active, goblins whose changes can't be read and files
+// the project ignores (see DiffIgnore) are left out. It runs git in every
+// worktree; RecordedOverlaps reads the daemon's last result instead.
+func (c *Coordinator) Overlaps(goblins []*Goblin) []Overlap {
+	byProject := make(map[string][]*Goblin)
+	for _, g := range goblins {
+		if active(g.Status) {
+			byProject[g.ProjectPath] = append(byProject[g.ProjectPath], g)
+		}
+	}
+
+	var overlaps []Overlap
+	for _, group := range byProject {
+		if len(group) < 2 {
+			continue
+		}
+
+		touched := make(map[string]map[string]bool)
+		for _, g := range group {
+			if files := c.touchedFiles(g); len(files) > 0 {
+				touched[g.ID] = files
+			}
+		}
+
+		for i, a := range group {
+			for _, b := range group[i+1:] {
+				// Goblins sharing a directory don't merge with each other
+				if a.WorktreePath == b.WorktreePath {
+					continue
+				}
+				var shared []string
+				for file := range touched[a.ID] {
+					if touched[b.ID][file] {
+						shared = append(shared, file)
+					}
+				}
+				if len(shared) == 0 {
+					continue
+				}
+				sort.Strings(shared)
+				if b.Name < a.Name {
+					overlaps = append(overlaps, Overlap{Goblin: b, Other: a, Files: shared})
+				} else {
+					overlaps = append(overlaps, Overlap{Goblin: a, Other: b, Files: shared})
+				}
+			}
+		}
+	}
+
+	sortOverlaps(overlaps)
+	return overlaps
+}
+
+// sortOverlaps orders overlaps by the names of their goblins
+func sortOverlaps(overlaps []Overlap) {
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i].Goblin.Name != overlaps[j].Goblin.Name {
+			return overlaps[i].Goblin.Name < overlaps[j].Goblin.Name
+		}
+		return overlaps[i].Other.Name < overlaps[j].Other.Name
+	})
+}
+
+// RecordedOverlaps returns the overlaps stored by the last RecordOverlaps
+// among goblins that are still active, in name order
+func (c *Coordinator) RecordedOverlaps(goblins []*Goblin) ([]Overlap, error) {
+	stored, err := c.db.ListOverlaps()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Goblin, len(goblins))
+	for _, g := range goblins {
+		if active(g.Status) {
+			byID[g.ID] = g
+		}
+	}
+
+	var overlaps []Overlap
+	for _, o := range stored {
+		a, b := byID[o.GoblinID], byID[o.OtherID]
+		if a == nil || b == nil {
+			continue
+		}
+		overlaps = append(overlaps, Overlap{Goblin: a, Other: b, Files: o.Files})
+	}
+	sortOverlaps(overlaps)
+	return overlaps, nil
+}
+
+// touchedFiles returns the set of files a goblin's worktree changes since
+// it was spawned, less ignored ones; nil when they can't be read
+func (c *Coordinator) touchedFiles(g *Goblin) map[string]bool {
+	if _, err := os.Stat(g.WorktreePath); err != nil || !isGitRepo(g.WorktreePath) {
+		return nil
+	}
+	files, err := workspace.TouchedFiles(g.WorktreePath, g.BaseRef)
+	if err != nil {
+		return nil
+	}
+
+	set := make(map[string]bool)
+	for _, file := range c.DiffIgnore(g).Filter(files) {
+		set[file] = true
+	}
+	return set
+}
+
+// RecordOverlaps stores the current overlaps for RecordedOverlaps and
+// records an overlap event on both goblins of each pair that didn't
+// overlap in seen, the set returned by the previous call. It returns the
+// new overlaps and the set to pass next time.
+func (c *Coordinator) RecordOverlaps(overlaps []Overlap, seen map[string]bool) ([]Overlap, map[string]bool) {
+	stored := make([]*storage.Overlap, len(overlaps))
+	for i, o := range overlaps {
+		stored[i] = &storage.Overlap{GoblinID: o.Goblin.ID, OtherID: o.Other.ID, Files: o.Files}
+	}
+	if err := c.db.SaveOverlaps(stored); err != nil && c.log != nil {
+		c.log.Warn("Failed to store overlaps", logging.Err(err))
+	}
+
+	var fresh []Overlap
+	current := make(map[string]bool, len(overlaps))
+	for _, o := range overlaps {
+		key := o.Goblin.ID + " " + o.Other.ID
+		current[key] = true
+		if seen[key] {
+			continue
+		}
+		fresh = append(fresh, o)
+		c.recordEvent(o.Goblin.ID, o.Goblin.Name, EventOverlap, o.String())
+		c.recordEvent(o.Other.ID, o.Other.Name, EventOverlap, Overlap{Goblin: o.Other, Other: o.Goblin, Files: o.Files}.String())
+	}
+	return fresh, current
+}
@@ -0,0 +1,127 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestOverlaps(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+	os.WriteFile(filepath.Join(repo, ".gforge.yaml"), []byte("ignore: [\"*.lock\"]\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Config").Run()
+	out, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	base := strings.TrimSpace(string(out))
+
+	now := time.Now()
+	worktrees := make(map[string]string)
+	for _, name := range []string{"coder", "reviewer", "docs"} {
+		worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-"+name), "gforge/"+name, "")
+		if err != nil {
+			t.Fatalf("createWorktree failed: %v", err)
+		}
+		worktrees[name] = worktree
+		coord.db.RestoreGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "running",
+			ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/" + name, BaseRef: base, CreatedAt: now, UpdatedAt: now})
+	}
+
+	write := func(name, file string) {
+		path := filepath.Join(worktrees[name], file)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(name+"\n"), 0644)
+	}
+	// coder commits one shared file and leaves another uncommitted
+	write("coder", "api.go")
+	exec.Command("git", "-C", worktrees["coder"], "add", ".").Run()
+	exec.Command("git", "-C", worktrees["coder"], "commit", "--no-gpg-sign", "-m", "API").Run()
+	write("coder", "README.md")
+	write("coder", "go.lock")
+	write("reviewer", "api.go")
+	write("reviewer", "README.md")
+	write("reviewer", "go.lock")
+	write("docs", "docs/guide.md")
+
+	goblins, _ := coord.List()
+	overlaps := coord.Overlaps(goblins)
+	if len(overlaps) != 1 {
+		t.Fatalf("Expected only coder and reviewer to overlap, got %v", overlaps)
+	}
+	o := overlaps[0]
+	if o.Goblin.Name != "coder" || o.Other.Name != "reviewer" {
+		t.Errorf("Expected coder and reviewer, got %s and %s", o.Goblin.Name, o.Other.Name)
+	}
+	if strings.Join(o.Files, ",") != "README.md,api.go" {
+		t.Errorf("Expected README.md and api.go, ignored go.lock left out, got %v", o.Files)
+	}
+	if o.String() != "coder overlaps with reviewer on 2 files: README.md, api.go" {
+		t.Errorf("Unexpected description: %s", o)
+	}
+
+	// Each pair is recorded once, on both goblins
+	fresh, seen := coord.RecordOverlaps(overlaps, nil)
+	if len(fresh) != 1 {
+		t.Errorf("Expected the overlap new the first time, got %v", fresh)
+	}
+	if fresh, _ := coord.RecordOverlaps(overlaps, seen); len(fresh) != 0 {
+		t.Errorf("Expected a known overlap not recorded again, got %v", fresh)
+	}
+	events, _ := coord.db.ListEvents(now.Add(-time.Minute))
+	recorded := 0
+	for _, e := range events {
+		if e.Type == EventOverlap {
+			recorded++
+		}
+	}
+	if recorded != 2 {
+		t.Errorf("Expected an overlap event on each goblin, got %d", recorded)
+	}
+
+	// The recorded overlaps are read back without git
+	recordedOverlaps, err := coord.RecordedOverlaps(goblins)
+	if err != nil || len(recordedOverlaps) != 1 || recordedOverlaps[0].String() != o.String() {
+		t.Errorf("Expected the recorded overlap read back, got %v, %v", recordedOverlaps, err)
+	}
+
+	// Goblins no longer active don't count, live or recorded
+	coord.db.UpdateGoblinStatus("id-reviewer", StatusCompleted)
+	goblins, _ = coord.List()
+	if overlaps := coord.Overlaps(goblins); len(overlaps) != 0 {
+		t.Errorf("Expected no overlaps with reviewer completed, got %v", overlaps)
+	}
+	if overlaps, _ := coord.RecordedOverlaps(goblins); len(overlaps) != 0 {
+		t.Errorf("Expected no recorded overlaps with reviewer completed, got %v", overlaps)
+	}
+}
+
+func TestOverlapString(t *testing.T) {
+	o := Overlap{
+		Goblin: &Goblin{Name: "a"},
+		Other:  &Goblin{Name: "b"},
+		Files:  []string{"1.go", "2.go", "3.go", "4.go"},
+	}
+	if got := o.String(); got != "a overlaps with b on 4 files: 1.go, 2.go, 3.go, ..." {
+		t.Errorf("Unexpected description: %s", got)
+	}
+	if len(o.Files) != 4 {
+		t.Errorf("Expected String to leave Files alone, got %v", o.Files)
+	}
+
+	o.Files = o.Files[:1]
+	if got := o.String(); got != "a overlaps with b on 1 file: 1.go" {
+		t.Errorf("Unexpected description: %s", got)
+	}
+}
@@ -0,0 +1,124 @@
+package coordinator
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// isPaused reports whether a goblin's processes are stopped
+func isPaused(status string) bool {
+	return status == "paused" || status == StatusPreempted
+}
+
+// Pause suspends a goblin's agent with SIGSTOP. Its session and worktree
+// stay in place and Resume continues it where it left off.
+func (c *Coordinator) Pause(nameOrID string) error {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	if isPaused(goblin.Status) {
+		return fmt.Errorf("goblin %s is already %s", goblin.Name, goblin.Status)
+	}
+
+	if err := c.pause(goblin, "paused"); err != nil {
+		return err
+	}
+	// A paused goblin frees its slot for queued spawns
+	c.resumePreempted()
+	return nil
+}
+
+// Resume continues a paused or preempted goblin
+func (c *Coordinator) Resume(nameOrID string) error {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	if !isPaused(goblin.Status) {
+		return fmt.Errorf("goblin %s is not paused", goblin.Name)
+	}
+
+	return c.resume(goblin)
+}
+
+// pause stops the goblin's agent and records status (paused, or
+// preempted when the scheduler does it)
+func (c *Coordinator) pause(g *Goblin, status string) error {
+	pgid, err := c.foregroundGroup(g)
+	if err != nil {
+		return err
+	}
+	// Like Ctrl-Z, but SIGSTOP can't be caught: the pane's shell takes the
+	// terminal back and holds the agent as a stopped job
+	if err := syscall.Kill(-pgid, syscall.SIGSTOP); err != nil {
+		return fmt.Errorf("failed to stop %s: %w", g.Name, err)
+	}
+	if err := c.db.UpdateGoblinStatus(g.ID, status); err != nil {
+		return err
+	}
+	g.Status = status
+	c.recordEvent(g.ID, g.Name, EventPaused, status)
+
+	if c.log != nil {
+		c.log.Info("Paused goblin",
+			logging.String("name", g.Name),
+			logging.String("status", status))
+	}
+	return nil
+}
+
+// resume brings the stopped agent back to the foreground and marks the
+// goblin running
+func (c *Coordinator) resume(g *Goblin) error {
+	output, err := trace.Command("tmux", "-L", c.Socket(g),
+		"send-keys", "-t", g.TmuxSession, "fg", "Enter").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("tmux send-keys failed: %s\n%s", err, string(output))
+	}
+	if err := c.db.UpdateGoblinStatus(g.ID, "running"); err != nil {
+		return err
+	}
+	g.Status = "running"
+	c.recordEvent(g.ID, g.Name, EventResumed, "")
+
+	if c.log != nil {
+		c.log.Info("Resumed goblin", logging.String("name", g.Name))
+	}
+	return nil
+}
+
+// foregroundGroup returns the process group running in the foreground of
+// the goblin's pane, which is the agent started by startAgent. The pane
+// process itself is left alone: tmux continues it if it stops.
+func (c *Coordinator) foregroundGroup(g *Goblin) (int, error) {
+	output, err := trace.Command("tmux", "-L", c.Socket(g), "display-message", "-p",
+		"-t", g.TmuxSession, "#{pane_pid}").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find pane process for %s: %w", g.Name, err)
+	}
+	shell := strings.TrimSpace(string(output))
+
+	output, err = exec.Command("ps", "-o", "tpgid=", "-p", shell).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find foreground process for %s: %w", g.Name, err)
+	}
+	pgid, _ := strconv.Atoi(strings.TrimSpace(string(output)))
+	if pgid <= 0 || strconv.Itoa(pgid) == shell {
+		return 0, fmt.Errorf("no agent running in %s", g.Name)
+	}
+	return pgid, nil
+}
@@ -0,0 +1,37 @@
+package coordinator
+
+import (
+	"fmt"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+// EventPinned records a goblin being pinned or unpinned
+const EventPinned = "pinned"
+
+// Pin pins or unpins a goblin. Pinned goblins are listed first and the
+// scheduler never preempts them.
+func (c *Coordinator) Pin(nameOrID string, pinned bool) (*Goblin, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if goblin == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	if goblin.Pinned == pinned {
+		return goblin, nil
+	}
+
+	if err := c.db.SetGoblinPinned(goblin.ID, pinned); err != nil {
+		return nil, err
+	}
+	goblin.Pinned = pinned
+
+	detail := "pinned"
+	if !pinned {
+		detail = "unpinned"
+	}
+	c.recordEvent(goblin.ID, goblin.Name, EventPinned, detail)
+	return goblin, nil
+}
@@ -0,0 +1,72 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestPin(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	now := time.Now()
+	for i, name := range []string{"old", "middle", "new"} {
+		coord.db.RestoreGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "stopped",
+			ProjectPath: "/tmp", CreatedAt: now.Add(time.Duration(i) * time.Minute), UpdatedAt: now})
+	}
+
+	g, err := coord.Pin("old", true)
+	if err != nil {
+		t.Fatalf("Pin failed: %v", err)
+	}
+	if !g.Pinned {
+		t.Error("Expected the goblin pinned")
+	}
+
+	goblins, _ := coord.List()
+	var names []string
+	for _, g := range goblins {
+		names = append(names, g.Name)
+	}
+	if len(names) != 3 || names[0] != "old" || names[1] != "new" || names[2] != "middle" {
+		t.Errorf("Expected the pinned goblin first, then newest first, got %v", names)
+	}
+
+	events, _ := coord.db.ListEvents(now.Add(-time.Minute))
+	if len(events) != 1 || events[0].Type != EventPinned || events[0].Detail != "pinned" {
+		t.Errorf("Expected a pinned event, got %+v", events)
+	}
+
+	// Pinning again changes nothing
+	coord.Pin("old", true)
+	if events, _ := coord.db.ListEvents(now.Add(-time.Minute)); len(events) != 1 {
+		t.Errorf("Expected no new event, got %d", len(events))
+	}
+
+	if g, _ = coord.Pin("old", false); g.Pinned {
+		t.Error("Expected the goblin unpinned")
+	}
+	if _, err := coord.Pin("missing", true); err == nil {
+		t.Error("Expected an error for a missing goblin")
+	}
+}
+
+func TestPreemptSkipsPinned(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	claim := pending{rank: priorityRank(PriorityHigh)}
+	pinned := &Goblin{ID: "id-1", Name: "keeper", Status: "running", Priority: PriorityLow, Pinned: true,
+		CreatedAt: time.Now()}
+	other := &Goblin{ID: "id-2", Name: "other", Status: "running", Priority: PriorityNormal,
+		CreatedAt: time.Now().Add(-time.Hour)}
+
+	if victim := coord.pickVictim(claim, slots{}, []*Goblin{pinned, other}); victim != other {
+		t.Errorf("Expected the unpinned goblin picked, got %+v", victim)
+	}
+	if victim := coord.pickVictim(claim, slots{}, []*Goblin{pinned}); victim != nil {
+		t.Errorf("Expected the pinned goblin left alone, got %s", victim.Name)
+	}
+}
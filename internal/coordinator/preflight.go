@@ -0,0 +1,241 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// PreflightProblem is one thing that would make a spawn fail
+type PreflightProblem struct {
+	Check  string // disk, tmux, agent or git
+	Detail string
+	Fix    string // What to do about it
+	err    error  // Cause, for exit codes
+}
+
+// PreflightError lists everything found wrong before a spawn, so all of it
+// can be fixed at once. It matches the errs sentinel of each problem.
+type PreflightError struct {
+	Name     string
+	Problems []PreflightProblem
+}
+
+func (e *PreflightError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "preflight checks for %s found %d problem(s):", e.Name, len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %-5s %s", p.Check, p.Detail)
+		if p.Fix != "" {
+			fmt.Fprintf(&b, "\n        fix: %s", p.Fix)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns the causes of the problems that have one
+func (e *PreflightError) Unwrap() []error {
+	var causes []error
+	for _, p := range e.Problems {
+		if p.err != nil {
+			causes = append(causes, p.err)
+		}
+	}
+	return causes
+}
+
+// preflight checks everything a spawn needs before it allocates anything:
+// free disk where the worktree goes, a usable tmux, the agent's binary and
+// a healthy git repository. It returns a *PreflightError listing every
+// problem, or nil.
+func (c *Coordinator) preflight(opts SpawnOptions, project *config.ProjectConfig, socket string) error {
+	var problems []PreflightProblem
+	add := func(p *PreflightProblem) {
+		if p != nil {
+			problems = append(problems, *p)
+		}
+	}
+
+	add(c.checkDisk(c.worktreeBase(opts.ProjectPath, project)))
+	add(checkTmux(socket))
+	if opts.Agent != nil {
+		if err := opts.Agent.CheckInstalled(); err != nil {
+			add(&PreflightProblem{
+				Check:  "agent",
+				Detail: fmt.Sprintf("%s runs %s, which is not on PATH", opts.Agent.Name, opts.Agent.Command),
+				Fix:    opts.Agent.InstallHint,
+				err:    errs.ErrAgentNotInstalled,
+			})
+		}
+	}
+	if isGitRepo(opts.ProjectPath) {
+		for _, p := range c.checkRepo(opts.ProjectPath, opts.Branch, opts.BaseRef) {
+			add(p)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &PreflightError{Name: opts.Name, Problems: problems}
+}
+
+// checkDisk reports too little free space at base (or, before it exists,
+// the nearest directory above it) under general.min_free_disk_mb
+func (c *Coordinator) checkDisk(base string) *PreflightProblem {
+	need := c.cfg.General.MinFreeDiskMB
+	if need <= 0 || base == "" {
+		return nil
+	}
+
+	dir := base
+	for {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return nil
+	}
+	free := int(fs.Bavail * uint64(fs.Bsize) / (1 << 20))
+	if free >= need {
+		return nil
+	}
+	return &PreflightProblem{
+		Check:  "disk",
+		Detail: fmt.Sprintf("%d MB free at %s, need %d MB", free, base, need),
+		Fix:    "free up space (gforge kill or gforge archive frees a goblin's worktree), or lower general.min_free_disk_mb",
+	}
+}
+
+// checkTmux reports a missing tmux, or a socket tmux can't use
+func checkTmux(socket string) *PreflightProblem {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return &PreflightProblem{
+			Check:  "tmux",
+			Detail: "tmux is not on PATH",
+			Fix:    tmux.InstallHint,
+			err:    errs.ErrTmuxUnavailable,
+		}
+	}
+	if err := tmux.CheckSocket(socket).Err(); err != nil {
+		return &PreflightProblem{
+			Check:  "tmux",
+			Detail: err.Error(),
+			Fix:    errs.Hint(err),
+			err:    errs.ErrTmuxUnavailable,
+		}
+	}
+	return nil
+}
+
+// checkRepo reports what would stop git from creating the worktree: no
+// commit to branch from, an unknown base, a stale index lock, or the
+// branch already checked out elsewhere
+func (c *Coordinator) checkRepo(projectPath, branch, baseRef string) []*PreflightProblem {
+	var problems []*PreflightProblem
+	git := func(args ...string) (string, error) {
+		out, err := trace.Command("git", append([]string{"-C", projectPath}, args...)...).Output()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	if baseRef != "" {
+		if _, err := git("rev-parse", "--verify", "--quiet", baseRef+"^{commit}"); err != nil {
+			problems = append(problems, &PreflightProblem{
+				Check:  "git",
+				Detail: fmt.Sprintf("base %s is not a commit in %s", baseRef, projectPath),
+				Fix:    fmt.Sprintf("git -C %s fetch, if it only exists on a remote", projectPath),
+			})
+		}
+	} else if _, err := git("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		problems = append(problems, &PreflightProblem{
+			Check:  "git",
+			Detail: fmt.Sprintf("%s has no commits to branch from", projectPath),
+			Fix:    fmt.Sprintf("git -C %s commit --allow-empty -m \"Initial commit\"", projectPath),
+		})
+	}
+
+	if lock, err := git("rev-parse", "--git-path", "index.lock"); err == nil {
+		if !filepath.IsAbs(lock) {
+			lock = filepath.Join(projectPath, lock)
+		}
+		if staleLock(lock, projectPath) {
+			problems = append(problems, &PreflightProblem{
+				Check:  "git",
+				Detail: fmt.Sprintf("%s exists but no git process is running in %s; one was killed holding the index", lock, projectPath),
+				Fix:    fmt.Sprintf("rm %s", lock),
+			})
+		}
+	}
+
+	if path := checkedOutAt(projectPath, branch); branch != "" && path != "" {
+		fix := fmt.Sprintf("git -C %s worktree remove %s, or spawn with another --branch", projectPath, path)
+		if g, _ := c.FindByPath(path); g != nil {
+			fix = fmt.Sprintf("gforge kill %s, or spawn with another --branch", g.Name)
+		}
+		problems = append(problems, &PreflightProblem{
+			Check:  "git",
+			Detail: fmt.Sprintf("branch %s is already checked out at %s", branch, path),
+			Fix:    fix,
+		})
+	}
+
+	return problems
+}
+
+// indexLockWait is how long a spawn waits for another git process to
+// release the project's index before looking for who holds it
+var indexLockWait = 2 * time.Second
+
+// staleLock reports whether lock outlasts indexLockWait with no git
+// process running in dir to release it. A lock held by a live git is
+// left to the worktree creation, which retries until it goes.
+func staleLock(lock, dir string) bool {
+	deadline := time.Now().Add(indexLockWait)
+	for {
+		if _, err := os.Stat(lock); err != nil {
+			return false
+		}
+		if time.Now().After(deadline) {
+			return !gitRunningIn(dir)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// gitRunningIn reports whether a git process is working in dir. Without
+// /proc to tell where processes run, any running git counts.
+func gitRunningIn(dir string) bool {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return exec.Command("pgrep", "-x", "git").Run() == nil
+	}
+	dir, _ = filepath.EvalSymlinks(dir)
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join("/proc", e.Name(), "comm"))
+		if err != nil || strings.TrimSpace(string(comm)) != "git" {
+			continue
+		}
+		cwd, err := os.Readlink(filepath.Join("/proc", e.Name(), "cwd"))
+		if err == nil && (cwd == dir || strings.HasPrefix(cwd, dir+string(filepath.Separator))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package coordinator
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+func TestPreflightReportsEveryProblem(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	cfg.General.MinFreeDiskMB = 1 << 40
+	wait := indexLockWait
+	indexLockWait = 0
+	defer func() { indexLockWait = wait }()
+
+	// A repository without commits, with its index locked
+	repo := t.TempDir()
+	if err := exec.Command("git", "init", repo).Run(); err != nil {
+		t.Fatalf("git init failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(repo, ".git", "index.lock"), nil, 0644)
+
+	_, err := coord.Spawn(SpawnOptions{
+		Name:        "doomed",
+		Agent:       &agents.Agent{Name: "ghost", Command: "gforge-no-such-agent", InstallHint: "Install ghost"},
+		ProjectPath: repo,
+		Branch:      "gforge/doomed",
+	})
+
+	var preflight *PreflightError
+	if !errors.As(err, &preflight) {
+		t.Fatalf("Expected a PreflightError, got %v", err)
+	}
+	checks := make(map[string]int)
+	for _, p := range preflight.Problems {
+		checks[p.Check]++
+	}
+	if checks["disk"] != 1 || checks["agent"] != 1 || checks["git"] != 2 {
+		t.Errorf("Expected disk, agent and two git problems, got %+v", preflight.Problems)
+	}
+	if !errors.Is(err, errs.ErrAgentNotInstalled) {
+		t.Error("Expected the error to match ErrAgentNotInstalled")
+	}
+	if !strings.Contains(err.Error(), "fix: Install ghost") {
+		t.Errorf("Expected the install hint in the report:\n%s", err)
+	}
+
+	// Nothing was created
+	if entries, _ := os.ReadDir(cfg.WorktreeBase); len(entries) != 0 {
+		t.Errorf("Expected no worktree, found %d entries", len(entries))
+	}
+	if g, _ := coord.Get("doomed"); g != nil {
+		t.Error("Expected no goblin record")
+	}
+}
+
+func TestStaleLock(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+	lock := filepath.Join(repo, ".git", "index.lock")
+
+	wait := indexLockWait
+	indexLockWait = 300 * time.Millisecond
+	defer func() { indexLockWait = wait }()
+
+	if staleLock(lock, repo) {
+		t.Error("Expected no lock to be fine")
+	}
+
+	// Released while waiting
+	os.WriteFile(lock, nil, 0644)
+	time.AfterFunc(100*time.Millisecond, func() { os.Remove(lock) })
+	if staleLock(lock, repo) {
+		t.Error("Expected a lock released in time to be fine")
+	}
+
+	// Held by a git process still running in the repository
+	os.WriteFile(lock, nil, 0644)
+	defer os.Remove(lock)
+	cmd := exec.Command("git", "-C", repo, "cat-file", "--batch")
+	stdin, _ := cmd.StdinPipe()
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start git: %v", err)
+	}
+	if staleLock(lock, repo) {
+		t.Error("Expected a lock with a live git process not to be stale")
+	}
+	stdin.Close()
+	cmd.Wait()
+
+	if !staleLock(lock, repo) {
+		t.Error("Expected a lock left with no git process to be stale")
+	}
+}
+
+func TestCheckRepoBranchInUse(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	other := filepath.Join(t.TempDir(), "other")
+	if err := gitRun(repo, "worktree", "add", "-b", "gforge/busy", other); err != nil {
+		t.Fatalf("worktree add failed: %v", err)
+	}
+
+	problems := coord.checkRepo(repo, "gforge/busy", "")
+	if len(problems) != 1 || !strings.Contains(problems[0].Detail, other) {
+		t.Errorf("Expected the branch reported as checked out at %s, got %+v", other, problems)
+	}
+
+	if problems := coord.checkRepo(repo, "gforge/free", "no-such-ref"); len(problems) != 1 || !strings.Contains(problems[0].Detail, "no-such-ref") {
+		t.Errorf("Expected an unknown base reported, got %+v", problems)
+	}
+	if problems := coord.checkRepo(repo, "gforge/free", ""); len(problems) != 0 {
+		t.Errorf("Expected a healthy repo, got %+v", problems)
+	}
+}
@@ -0,0 +1,191 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventProgress records a progress comment posted on a goblin's issue
+const EventProgress = "progress"
+
+// progressEventLimit is how many of the latest events a progress comment lists
+const progressEventLimit = 10
+
+// postIssueComment posts a comment on an issue; tests replace it
+var postIssueComment = integrations.CommentOnIssue
+
+// LinkIssue links a goblin to the issue it is working on, which then gets
+// its progress comments
+func (c *Coordinator) LinkIssue(g *Goblin, ref string) (integrations.IssueRef, error) {
+	issue, err := integrations.ParseTrackerRef(ref)
+	if err != nil {
+		return issue, err
+	}
+	if err := c.db.LinkIssue(g.ID, issue.String()); err != nil {
+		return issue, err
+	}
+	return issue, nil
+}
+
+// ProgressReport renders a goblin's progress for its issue: its status,
+// what its branch changes, its latest summary and its activity since the
+// given time
+func (c *Coordinator) ProgressReport(g *Goblin, since time.Time) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**gforge progress: %s** (%s agent, %s)\n\n", g.Name, g.Agent, g.Status)
+
+	var changes []string
+	ignore := c.DiffIgnore(g)
+	if stat, err := workspace.BranchDiffStat(g.WorktreePath, g.BaseRef, ignore); err == nil {
+		changes = append(changes, stat.String()+" committed")
+	}
+	if stat, err := workspace.NewWorktreeManager(workspace.Config{Ignore: ignore}).GetDiffStat(g.WorktreePath); err == nil && stat.Files > 0 {
+		changes = append(changes, stat.String()+" uncommitted")
+	}
+	if len(changes) > 0 {
+		fmt.Fprintf(&b, "Branch `%s`: %s\n\n", g.Branch, strings.Join(changes, ", "))
+	}
+
+	summary, err := c.db.GetSummary(g.ID)
+	if err != nil {
+		return "", err
+	}
+	if summary != nil && summary.Summary != "" {
+		for _, line := range strings.Split(strings.TrimSpace(summary.Summary), "\n") {
+			fmt.Fprintf(&b, "> %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+
+	events, err := c.progressEvents(g, since)
+	if err != nil {
+		return "", err
+	}
+	if len(events) > 0 {
+		b.WriteString("Latest activity:\n")
+		for _, e := range events {
+			line := e.Type
+			if e.Detail != "" {
+				line += ": " + eventLine(e.Detail)
+			}
+			fmt.Fprintf(&b, "- %s %s\n", e.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"), line)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n", nil
+}
+
+// progressEvents returns a goblin's latest events since the given time,
+// other than its own progress comments
+func (c *Coordinator) progressEvents(g *Goblin, since time.Time) ([]*storage.Event, error) {
+	all, err := c.db.ListEvents(since)
+	if err != nil {
+		return nil, err
+	}
+	var events []*storage.Event
+	for _, e := range all {
+		if e.GoblinID == g.ID && e.Type != EventProgress {
+			events = append(events, e)
+		}
+	}
+	if len(events) > progressEventLimit {
+		events = events[len(events)-progressEventLimit:]
+	}
+	return events, nil
+}
+
+// eventLine shortens an event's detail to its first line
+func eventLine(s string) string {
+	line, _, more := strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(line); len(r) > 120 {
+		return string(r[:117]) + "..."
+	} else if more {
+		return line + " ..."
+	}
+	return line
+}
+
+// PostProgress comments a goblin's progress on its linked issue
+func (c *Coordinator) PostProgress(g *Goblin) error {
+	link, err := c.db.GetIssueLink(g.ID)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return fmt.Errorf("%s is not linked to an issue (see gforge progress link)", g.Name)
+	}
+	issue, err := integrations.ParseTrackerRef(link.Ref)
+	if err != nil {
+		return err
+	}
+
+	since := link.CommentedAt
+	if since.IsZero() {
+		since = link.CreatedAt
+	}
+	report, err := c.ProgressReport(g, since)
+	if err != nil {
+		return err
+	}
+	if err := postIssueComment(issue, report); err != nil {
+		return err
+	}
+
+	if err := c.db.MarkIssueCommented(g.ID, time.Now()); err != nil {
+		return err
+	}
+	c.recordEvent(g.ID, g.Name, EventProgress, issue.String())
+	if c.log != nil {
+		c.log.Info("Posted progress comment",
+			logging.String("name", g.Name),
+			logging.String("issue", issue.String()))
+	}
+	return nil
+}
+
+// PostDueProgress comments on the issues of linked goblins whose last
+// comment is at least interval old and that have had activity since.
+// Failures are logged and the rest still posted; the number posted is
+// returned.
+func (c *Coordinator) PostDueProgress(interval time.Duration, now time.Time) (int, error) {
+	links, err := c.db.ListIssueLinks()
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, link := range links {
+		if !link.CommentedAt.IsZero() && now.Sub(link.CommentedAt) < interval {
+			continue
+		}
+		g, err := c.Get(link.GoblinID)
+		if err != nil || g == nil {
+			continue
+		}
+
+		since := link.CommentedAt
+		if since.IsZero() {
+			since = link.CreatedAt
+		}
+		if events, err := c.progressEvents(g, since); err != nil || len(events) == 0 {
+			continue
+		}
+
+		if err := c.PostProgress(g); err != nil {
+			if c.log != nil {
+				c.log.Warn("Failed to post progress comment",
+					logging.String("name", g.Name),
+					logging.Err(err))
+			}
+			continue
+		}
+		posted++
+	}
+	return posted, nil
+}
@@ -0,0 +1,78 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestPostProgress(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	var posted []string
+	postIssueComment = func(ref integrations.IssueRef, body string) error {
+		posted = append(posted, ref.String()+"\n"+body)
+		return nil
+	}
+	defer func() { postIssueComment = integrations.CommentOnIssue }()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "tracked", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: repo, Branch: "gforge/tracked", BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("tracked")
+
+	if err := coord.PostProgress(g); err == nil {
+		t.Fatal("Expected an error for a goblin without an issue")
+	}
+	if _, err := coord.LinkIssue(g, "jira:nope"); err == nil {
+		t.Fatal("Expected an invalid reference refused")
+	}
+	if _, err := coord.LinkIssue(g, "gh:acme/app#12"); err != nil {
+		t.Fatalf("LinkIssue failed: %v", err)
+	}
+
+	// Nothing has happened yet, so nothing is due
+	now := time.Now()
+	if n, err := coord.PostDueProgress(time.Hour, now); err != nil || n != 0 {
+		t.Fatalf("Expected no comments without activity, got %d, %v", n, err)
+	}
+
+	os.WriteFile(filepath.Join(repo, "fix.go"), []byte("package main\n\nfunc fix() {}\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Fix").Run()
+	coord.db.RecordEvent("id-1", "tracked", EventTask, "Fix the login crash\nwith details")
+
+	if n, err := coord.PostDueProgress(time.Hour, now); err != nil || n != 1 {
+		t.Fatalf("Expected one comment, got %d, %v", n, err)
+	}
+	if len(posted) != 1 {
+		t.Fatalf("Expected one comment posted, got %d", len(posted))
+	}
+	for _, want := range []string{"gh:acme/app#12", "**gforge progress: tracked**", "Branch `gforge/tracked`: 1 file +3 -0 committed", "task: Fix the login crash ..."} {
+		if !strings.Contains(posted[0], want) {
+			t.Errorf("Expected %q in the comment:\n%s", want, posted[0])
+		}
+	}
+
+	// Not due again until the interval has passed
+	coord.db.RecordEvent("id-1", "tracked", EventTask, "Add a test")
+	if n, _ := coord.PostDueProgress(time.Hour, time.Now()); n != 0 {
+		t.Errorf("Expected no comment within the interval, got %d", n)
+	}
+	if n, _ := coord.PostDueProgress(time.Hour, time.Now().Add(2*time.Hour)); n != 1 {
+		t.Errorf("Expected a comment once the interval passed, got %d", n)
+	}
+}
@@ -0,0 +1,48 @@
+package coordinator
+
+import (
+	"context"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/ratelimit"
+)
+
+// StatusThrottled marks a goblin whose request is queued behind a rate limit
+const StatusThrottled = "throttled"
+
+// throttle waits for the agent's rate limit before a spawn or task. A
+// goblin that already exists (g.ID set) shows as throttled while it waits.
+func (c *Coordinator) throttle(g *Goblin, agentName string) error {
+	limits := c.cfg.RateLimits.RequestsPerMinute
+	if len(limits) == 0 {
+		return nil
+	}
+
+	keys := []string{agentName}
+	if a := agents.NewRegistry().Get(agentName); a != nil && a.Provider != "" {
+		keys = append(keys, a.Provider)
+	}
+
+	limiter := ratelimit.New(c.db, limits, c.cfg.RateLimits.MaxWait)
+
+	throttled := false
+	err := limiter.Wait(context.Background(), func(key string, wait time.Duration) {
+		throttled = true
+		if c.log != nil {
+			c.log.Warn("Rate limited, queueing request",
+				logging.String("goblin", g.Name),
+				logging.String("limit", key),
+				logging.String("wait", wait.Round(time.Second).String()))
+		}
+		if g.ID != "" {
+			c.db.UpdateGoblinStatus(g.ID, StatusThrottled)
+		}
+	}, keys...)
+
+	if throttled && g.ID != "" {
+		c.db.UpdateGoblinStatus(g.ID, g.Status)
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestThrottle(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	g := &Goblin{ID: "id-1", Name: "coder", Agent: "claude", Status: "running"}
+	coord.db.CreateGoblin(&storage.Goblin{ID: g.ID, Name: g.Name, Agent: g.Agent, Status: g.Status, ProjectPath: "/tmp"})
+
+	if err := coord.throttle(g, "claude"); err != nil {
+		t.Fatalf("Expected no limit without configuration, got %v", err)
+	}
+
+	cfg.RateLimits.RequestsPerMinute = map[string]int{"anthropic": 1}
+	cfg.RateLimits.MaxWait = time.Millisecond
+
+	if err := coord.throttle(g, "claude"); err != nil {
+		t.Fatalf("Expected the first request allowed, got %v", err)
+	}
+	if err := coord.throttle(g, "claude-auto"); err == nil {
+		t.Error("Expected the provider limit to be shared by claude agents")
+	}
+
+	stored, _ := coord.db.GetGoblin(g.ID)
+	if stored.Status != "running" {
+		t.Errorf("Expected status restored after throttling, got %s", stored.Status)
+	}
+
+	if err := coord.throttle(g, "codex"); err != nil {
+		t.Errorf("Expected other providers unaffected, got %v", err)
+	}
+}
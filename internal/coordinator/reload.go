@@ -0,0 +1,50 @@
+package coordinator
+
+import (
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// EventConfigReloaded records a config file change picked up by a running
+// process. It belongs to no goblin.
+const EventConfigReloaded = "config_reloaded"
+
+// configEventName stands in for the goblin name on events about gforge
+// itself
+const configEventName = "gforge"
+
+// ConfigReloaded records which changed settings took effect and which wait
+// for a restart, and returns that summary
+func (c *Coordinator) ConfigReloaded(changes []config.Change) string {
+	var applied, pending []string
+	for _, ch := range changes {
+		if ch.Restart {
+			pending = append(pending, ch.Key)
+		} else {
+			applied = append(applied, ch.Key)
+		}
+	}
+
+	var parts []string
+	if len(applied) > 0 {
+		parts = append(parts, "applied "+strings.Join(applied, ", "))
+	}
+	if len(pending) > 0 {
+		parts = append(parts, "restart needed for "+strings.Join(pending, ", "))
+	}
+	detail := strings.Join(parts, "; ")
+
+	c.recordEvent("", configEventName, EventConfigReloaded, detail)
+	if c.log != nil {
+		c.log.Info("Reloaded config",
+			logging.String("path", c.cfg.ConfigPath),
+			logging.String("changes", detail))
+		if len(pending) > 0 {
+			c.log.Warn("Some config changes need a restart to take effect",
+				logging.String("keys", strings.Join(pending, ", ")))
+		}
+	}
+	return detail
+}
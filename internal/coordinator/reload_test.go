@@ -0,0 +1,30 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestConfigReloaded(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	detail := coord.ConfigReloaded([]config.Change{
+		{Key: "notifications.desktop"},
+		{Key: "tmux.socket_name", Restart: true},
+	})
+	want := "applied notifications.desktop; restart needed for tmux.socket_name"
+	if detail != want {
+		t.Errorf("ConfigReloaded() = %q, want %q", detail, want)
+	}
+
+	events, err := coord.db.ListEvents(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventConfigReloaded || events[0].Detail != want {
+		t.Errorf("Expected a config_reloaded event, got %+v", events)
+	}
+}
@@ -0,0 +1,221 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/cron"
+	"github.com/astoreyai/goblin-forge/internal/hours"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// EventScheduledRun records a goblin spawned by a schedule
+const EventScheduledRun = "scheduled_run"
+
+// checkScheduleName rejects names that can't start a goblin or branch name
+func checkScheduleName(name string) error {
+	valid := name != "" && strings.IndexFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	}) < 0
+	if !valid {
+		return fmt.Errorf("invalid schedule name %q: use letters, digits, - and _, e.g. deps-update", name)
+	}
+	return nil
+}
+
+// nextRun returns when a cron expression next comes round after now
+func nextRun(expr string, now time.Time) (time.Time, error) {
+	sched, err := cron.Parse(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := sched.Next(now)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression %q never comes round", expr)
+	}
+	return next, nil
+}
+
+// AddSchedule stores a recurring run, first due when its cron expression
+// next comes round
+func (c *Coordinator) AddSchedule(s *storage.Schedule, now time.Time) error {
+	if err := checkScheduleName(s.Name); err != nil {
+		return err
+	}
+	if s.ProjectPath == "" {
+		return fmt.Errorf("schedule %s has no project", s.Name)
+	}
+	if _, err := ParsePriority(s.Priority); err != nil {
+		return err
+	}
+	next, err := nextRun(s.Cron, now)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.db.GetSchedule(s.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("schedule %s already exists", s.Name)
+	}
+
+	s.NextRun = next
+	return c.db.SaveSchedule(s)
+}
+
+// Schedules returns every schedule by name
+func (c *Coordinator) Schedules() ([]*storage.Schedule, error) {
+	return c.db.ListSchedules()
+}
+
+// RemoveSchedule deletes a schedule added with AddSchedule; those from
+// the config file are removed there
+func (c *Coordinator) RemoveSchedule(name string) error {
+	s, err := c.db.GetSchedule(name)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("no schedule named %s", name)
+	}
+	if s.FromConfig {
+		return fmt.Errorf("schedule %s is defined in the config file; remove it there", name)
+	}
+	_, err = c.db.DeleteSchedule(name)
+	return err
+}
+
+// SyncSchedules brings the stored schedules in line with those defined
+// in the config file, which replace added ones of the same name. A
+// schedule whose cron expression is unchanged keeps its next run.
+func (c *Coordinator) SyncSchedules(defined map[string]config.ScheduleConfig, now time.Time) error {
+	stored, err := c.db.ListSchedules()
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*storage.Schedule)
+	for _, s := range stored {
+		byName[s.Name] = s
+		if _, ok := defined[s.Name]; s.FromConfig && !ok {
+			if _, err := c.db.DeleteSchedule(s.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	var invalid []string
+	for name, d := range defined {
+		s := &storage.Schedule{
+			Name:        name,
+			Cron:        d.Cron,
+			ProjectPath: d.ProjectPath(),
+			Agent:       d.Agent,
+			Task:        d.Task,
+			Priority:    d.Priority,
+			Squad:       d.Squad,
+			FromConfig:  true,
+		}
+		old := byName[name]
+		if old != nil && old.FromConfig && old.Cron == s.Cron {
+			s.NextRun, s.LastRun, s.LastGoblin = old.NextRun, old.LastRun, old.LastGoblin
+		} else {
+			if err := checkScheduleName(name); err != nil {
+				invalid = append(invalid, err.Error())
+				continue
+			}
+			if s.NextRun, err = nextRun(s.Cron, now); err != nil {
+				invalid = append(invalid, fmt.Sprintf("schedule %s: %v", name, err))
+				continue
+			}
+			if old != nil {
+				s.LastRun, s.LastGoblin = old.LastRun, old.LastGoblin
+			}
+		}
+		if err := c.db.SaveSchedule(s); err != nil {
+			return err
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid schedules in config: %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+// DueSchedules returns the schedules whose next run is at or before now.
+// One that came round more than once while nothing checked is due once.
+func (c *Coordinator) DueSchedules(now time.Time) ([]*storage.Schedule, error) {
+	schedules, err := c.db.ListSchedules()
+	if err != nil {
+		return nil, err
+	}
+	var due []*storage.Schedule
+	for _, s := range schedules {
+		if !s.NextRun.After(now) {
+			due = append(due, s)
+		}
+	}
+	return due, nil
+}
+
+// ScheduledName returns the name of the goblin a schedule spawns at now
+func ScheduledName(s *storage.Schedule, now time.Time) string {
+	return s.Name + "-" + now.Format("0102-1504")
+}
+
+// StillRunning returns the goblin a schedule's last run spawned while
+// it is still at work, so runs don't pile up
+func (c *Coordinator) StillRunning(s *storage.Schedule) (*Goblin, error) {
+	if s.LastGoblin == "" {
+		return nil, nil
+	}
+	g, err := c.Get(s.LastGoblin)
+	if err != nil || g == nil {
+		return nil, err
+	}
+	switch g.Status {
+	case "stopped", "failed", StatusDead, StatusCompleted, StatusArchived:
+		return nil, nil
+	}
+	return g, nil
+}
+
+// HoldSchedule returns why a due schedule mustn't spawn at now, empty when
+// it may. Outside working_hours the run is deferred: it stays due until
+// the next window opens.
+func (c *Coordinator) HoldSchedule(s *storage.Schedule, now time.Time) (reason string, deferred bool, err error) {
+	policy, err := hours.New(c.cfg.WorkingHours)
+	if err != nil {
+		return "", false, err
+	}
+	if !policy.Open(now) {
+		return "outside working hours until " + policy.Next(now).Format("Mon 15:04"), true, nil
+	}
+
+	return "", false, nil
+}
+
+// ScheduleRan moves a schedule on to its next run after now, recording
+// the goblin spawned, if any
+func (c *Coordinator) ScheduleRan(s *storage.Schedule, spawned *Goblin, now time.Time) error {
+	next, err := nextRun(s.Cron, now)
+	if err != nil {
+		return err
+	}
+	s.NextRun = next
+	if spawned != nil {
+		s.LastRun, s.LastGoblin = now, spawned.Name
+		c.recordEvent(spawned.ID, spawned.Name, EventScheduledRun, s.Name)
+		if c.log != nil {
+			c.log.Info("Spawned scheduled goblin",
+				logging.String("schedule", s.Name),
+				logging.String("goblin", spawned.Name))
+		}
+	}
+	return c.db.SaveSchedule(s)
+}
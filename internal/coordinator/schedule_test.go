@@ -0,0 +1,102 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestSchedules(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	// A Friday morning
+	now := time.Date(2026, 10, 16, 10, 30, 0, 0, time.Local)
+
+	for _, s := range []*storage.Schedule{
+		{Name: "deps update", Cron: "0 9 * * mon", ProjectPath: "/src/app"},
+		{Name: "deps", Cron: "0 9 * * someday", ProjectPath: "/src/app"},
+		{Name: "deps", Cron: "0 9 * * mon"},
+		{Name: "deps", Cron: "0 9 * * mon", ProjectPath: "/src/app", Priority: "urgent"},
+	} {
+		if err := coord.AddSchedule(s, now); err == nil {
+			t.Errorf("Expected %+v refused", s)
+		}
+	}
+
+	deps := &storage.Schedule{Name: "deps", Cron: "0 9 * * mon", ProjectPath: "/src/app", Task: "Update dependencies"}
+	if err := coord.AddSchedule(deps, now); err != nil {
+		t.Fatalf("AddSchedule failed: %v", err)
+	}
+	if want := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local); !deps.NextRun.Equal(want) {
+		t.Errorf("Expected deps first due Monday at 9, got %s", deps.NextRun)
+	}
+	if err := coord.AddSchedule(&storage.Schedule{Name: "deps", Cron: "@daily", ProjectPath: "/src/app"}, now); err == nil {
+		t.Error("Expected a second deps refused")
+	}
+
+	defined := map[string]config.ScheduleConfig{
+		"audit": {Cron: "0 * * * *", Project: "/src/app", Task: "Audit"},
+	}
+	if err := coord.SyncSchedules(defined, now); err != nil {
+		t.Fatalf("SyncSchedules failed: %v", err)
+	}
+	if err := coord.RemoveSchedule("audit"); err == nil {
+		t.Error("Expected a schedule from the config file kept")
+	}
+
+	due, err := coord.DueSchedules(now.Add(30 * time.Minute))
+	if err != nil || len(due) != 1 || due[0].Name != "audit" || !due[0].FromConfig {
+		t.Fatalf("Expected audit due at 11, got %v, %v", due, err)
+	}
+
+	ranAt := now.Add(30 * time.Minute)
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-audit", Name: ScheduledName(due[0], ranAt), Agent: "claude",
+		Status: "running", ProjectPath: "/src/app", CreatedAt: ranAt, UpdatedAt: ranAt})
+	spawned, _ := coord.Get("audit-1016-1100")
+	if spawned == nil {
+		t.Fatal("Expected the scheduled goblin named after the schedule and time")
+	}
+	if err := coord.ScheduleRan(due[0], spawned, ranAt); err != nil {
+		t.Fatalf("ScheduleRan failed: %v", err)
+	}
+	if due, _ := coord.DueSchedules(ranAt); len(due) != 0 {
+		t.Errorf("Expected nothing due right after the run, got %v", due)
+	}
+
+	// The last run's goblin holds back the next one while it works
+	audit, _ := coord.db.GetSchedule("audit")
+	if g, err := coord.StillRunning(audit); err != nil || g == nil || g.Name != spawned.Name {
+		t.Errorf("Expected %s still running, got %v, %v", spawned.Name, g, err)
+	}
+	coord.db.UpdateGoblinStatus("id-audit", StatusCompleted)
+	if g, _ := coord.StillRunning(audit); g != nil {
+		t.Errorf("Expected a completed goblin not to hold back the schedule, got %v", g)
+	}
+
+	// An unchanged config keeps the run; a removed schedule goes
+	coord.SyncSchedules(defined, ranAt)
+	if audit, _ := coord.db.GetSchedule("audit"); audit.LastGoblin != spawned.Name {
+		t.Errorf("Expected the last run kept, got %+v", audit)
+	}
+	if err := coord.SyncSchedules(nil, ranAt); err != nil {
+		t.Fatalf("SyncSchedules failed: %v", err)
+	}
+	schedules, _ := coord.Schedules()
+	if len(schedules) != 1 || schedules[0].Name != "deps" {
+		t.Errorf("Expected only deps left, got %v", schedules)
+	}
+
+	if err := coord.SyncSchedules(map[string]config.ScheduleConfig{"bad": {Cron: "every monday"}}, now); err == nil {
+		t.Error("Expected an invalid cron expression in config reported")
+	}
+
+	if err := coord.RemoveSchedule("deps"); err != nil {
+		t.Errorf("RemoveSchedule failed: %v", err)
+	}
+	if err := coord.RemoveSchedule("deps"); err == nil {
+		t.Error("Expected removing an unknown schedule to fail")
+	}
+}
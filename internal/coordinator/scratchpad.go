@@ -0,0 +1,108 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// Events recorded for scratchpad writes
+const (
+	EventPadSet    = "pad_set"    // A goblin wrote a scratchpad key
+	EventPadDelete = "pad_delete" // A goblin deleted a scratchpad key
+)
+
+// maxPadValue caps a scratchpad value, so a runaway agent can't fill the
+// database
+const maxPadValue = 1 << 20
+
+// SquadOf returns the scratchpad a goblin shares: its squad's, else its
+// project's, named by the project path
+func SquadOf(g *Goblin) string {
+	if g.Squad != "" {
+		return g.Squad
+	}
+	return g.ProjectPath
+}
+
+// SquadMembers returns the goblins sharing a squad's scratchpad, leaving
+// out archived ones
+func (c *Coordinator) SquadMembers(squad string) ([]*Goblin, error) {
+	goblins, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	var members []*Goblin
+	for _, g := range goblins {
+		if g.Status != StatusArchived && SquadOf(g) == squad {
+			members = append(members, g)
+		}
+	}
+	return members, nil
+}
+
+// checkPadKey rejects keys that can't be typed as one shell word
+func checkPadKey(key string) error {
+	if key == "" || strings.IndexFunc(key, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("invalid scratchpad key %q: use one word, e.g. api-contract", key)
+	}
+	return nil
+}
+
+// PadSet writes a key of a squad's scratchpad. by is the goblin writing
+// it, or nil when a person does.
+func (c *Coordinator) PadSet(squad, key, value string, by *Goblin) error {
+	if err := checkPadKey(key); err != nil {
+		return err
+	}
+	if len(value) > maxPadValue {
+		return fmt.Errorf("scratchpad value for %s is %d bytes; the limit is %d", key, len(value), maxPadValue)
+	}
+
+	entry := &storage.PadEntry{Squad: squad, Key: key, Value: value}
+	if by != nil {
+		entry.GoblinName = by.Name
+	}
+	if err := c.db.SetPadEntry(entry); err != nil {
+		return err
+	}
+	if by != nil {
+		c.recordEvent(by.ID, by.Name, EventPadSet, key)
+	}
+	return nil
+}
+
+// PadGet reads a key of a squad's scratchpad
+func (c *Coordinator) PadGet(squad, key string) (*storage.PadEntry, error) {
+	entry, err := c.db.GetPadEntry(squad, key)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%s is not set in the scratchpad of %s", key, squad)
+	}
+	return entry, nil
+}
+
+// Pad returns a squad's scratchpad by key
+func (c *Coordinator) Pad(squad string) ([]*storage.PadEntry, error) {
+	return c.db.ListPadEntries(squad)
+}
+
+// PadDelete removes a key of a squad's scratchpad. by is the goblin
+// deleting it, or nil when a person does.
+func (c *Coordinator) PadDelete(squad, key string, by *Goblin) error {
+	deleted, err := c.db.DeletePadEntry(squad, key)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%s is not set in the scratchpad of %s", key, squad)
+	}
+	if by != nil {
+		c.recordEvent(by.ID, by.Name, EventPadDelete, key)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package coordinator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestScratchpad(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, g := range []*storage.Goblin{
+		{ID: "id-api", Name: "api", Squad: "auth"},
+		{ID: "id-ui", Name: "ui", Squad: "auth"},
+		{ID: "id-docs", Name: "docs"},
+		{ID: "id-old", Name: "old", Squad: "auth", Status: StatusArchived},
+	} {
+		g.Agent, g.ProjectPath, g.CreatedAt, g.UpdatedAt = "claude", "/src/app", now, now
+		if g.Status == "" {
+			g.Status = "running"
+		}
+		coord.db.RestoreGoblin(g)
+	}
+
+	api, _ := coord.Get("api")
+	docs, _ := coord.Get("docs")
+	if SquadOf(api) != "auth" || SquadOf(docs) != "/src/app" {
+		t.Errorf("Expected squads auth and the project's, got %s and %s", SquadOf(api), SquadOf(docs))
+	}
+	members, err := coord.SquadMembers("auth")
+	if err != nil || len(members) != 2 {
+		t.Errorf("Expected api and ui in auth, archived old left out, got %v, %v", members, err)
+	}
+
+	if err := coord.PadSet("auth", "contract", "POST /v1/login", api); err != nil {
+		t.Fatalf("PadSet failed: %v", err)
+	}
+	entry, err := coord.PadGet("auth", "contract")
+	if err != nil || entry.Value != "POST /v1/login" || entry.GoblinName != "api" {
+		t.Errorf("Expected api's contract, got %+v, %v", entry, err)
+	}
+
+	// Squads don't see each other's keys
+	if _, err := coord.PadGet(SquadOf(docs), "contract"); err == nil {
+		t.Error("Expected the project's scratchpad not to have auth's key")
+	}
+
+	for _, key := range []string{"", "api contract", "a\nb"} {
+		if err := coord.PadSet("auth", key, "x", api); err == nil {
+			t.Errorf("Expected key %q refused", key)
+		}
+	}
+	if err := coord.PadSet("auth", "huge", strings.Repeat("x", maxPadValue+1), api); err == nil {
+		t.Error("Expected an oversized value refused")
+	}
+
+	// People write without a goblin
+	if err := coord.PadSet("auth", "split", "api: handlers", nil); err != nil {
+		t.Fatalf("PadSet failed: %v", err)
+	}
+	if entries, _ := coord.Pad("auth"); len(entries) != 2 || entries[1].GoblinName != "" {
+		t.Errorf("Expected contract and an unattributed split, got %v", entries)
+	}
+
+	if err := coord.PadDelete("auth", "split", api); err != nil {
+		t.Errorf("PadDelete failed: %v", err)
+	}
+	if err := coord.PadDelete("auth", "split", api); err == nil {
+		t.Error("Expected deleting an unset key to fail")
+	}
+
+	events, _ := coord.db.ListEvents(now.Add(-time.Minute))
+	var recorded []string
+	for _, e := range events {
+		if e.Type == EventPadSet || e.Type == EventPadDelete {
+			recorded = append(recorded, e.Type+" "+e.Detail)
+		}
+	}
+	if len(recorded) != 2 {
+		t.Errorf("Expected api's set and delete recorded, got %v", recorded)
+	}
+}
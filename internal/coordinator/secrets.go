@@ -0,0 +1,66 @@
+package coordinator
+
+import (
+	"fmt"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// EventSecrets records credentials found in a goblin's branch
+const EventSecrets = "secrets"
+
+// ScanSecrets checks the commits on a goblin's branch since it was spawned
+// for credentials. The findings are stored on the goblin, replacing those
+// of any earlier scan.
+func (c *Coordinator) ScanSecrets(g *Goblin) ([]*storage.SecretFinding, error) {
+	found, err := workspace.ScanSecrets(g.WorktreePath, g.BaseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*storage.SecretFinding, len(found))
+	for i, f := range found {
+		findings[i] = &storage.SecretFinding{
+			GoblinID: g.ID,
+			Commit:   f.Commit,
+			File:     f.File,
+			Line:     f.Line,
+			Rule:     f.Rule,
+			Match:    f.Match,
+		}
+	}
+	if err := c.db.SaveSecretFindings(g.ID, findings); err != nil {
+		return nil, err
+	}
+
+	if len(findings) > 0 {
+		c.recordEvent(g.ID, g.Name, EventSecrets, fmt.Sprintf("%d possible secret(s)", len(findings)))
+		if c.log != nil {
+			c.log.Warn("Secrets found in goblin branch",
+				logging.String("name", g.Name),
+				logging.Int("findings", len(findings)))
+		}
+	}
+	return findings, nil
+}
+
+// CheckSecrets scans a goblin's branch before it leaves the machine,
+// failing with errs.ErrSecretsFound when anything turns up. It does
+// nothing when git.scan_secrets is off.
+func (c *Coordinator) CheckSecrets(g *Goblin) ([]*storage.SecretFinding, error) {
+	if !c.cfg.Git.ScanSecrets {
+		return nil, nil
+	}
+
+	findings, err := c.ScanSecrets(g)
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) > 0 {
+		return findings, fmt.Errorf("%w: %d in %s", errs.ErrSecretsFound, len(findings), g.Name)
+	}
+	return nil, nil
+}
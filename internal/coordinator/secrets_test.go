@@ -0,0 +1,77 @@
+package coordinator
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestCheckSecrets(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "leaky", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: repo, BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("leaky")
+
+	// Assembled at run time so the source doesn't trip secret scanners
+	token := "ghp" + "_" + strings.Repeat("aB3dE5", 6)
+	os.WriteFile(filepath.Join(repo, "deploy.sh"), []byte("export GH_TOKEN="+token+"\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add deploy").Run()
+
+	if findings, err := coord.CheckSecrets(g); err != nil || findings != nil {
+		t.Errorf("Expected no scan with scan_secrets off, got %v, %v", findings, err)
+	}
+
+	cfg.Git.ScanSecrets = true
+	findings, err := coord.CheckSecrets(g)
+	if !errors.Is(err, errs.ErrSecretsFound) {
+		t.Fatalf("Expected ErrSecretsFound, got %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("Expected one finding, got %d", len(findings))
+	}
+
+	stored, _ := coord.db.ListSecretFindings("id-1")
+	if len(stored) != 1 || stored[0].Rule != "github-token" || strings.Contains(stored[0].Match, token[8:]) {
+		t.Errorf("Expected the redacted finding stored on the goblin, got %+v", stored)
+	}
+	events, _ := coord.db.ListEvents(time.Now().Add(-time.Hour))
+	if len(events) != 1 || events[0].Type != EventSecrets {
+		t.Errorf("Expected a secrets event, got %+v", events)
+	}
+
+	// Removing the secret in a later commit leaves it in the history
+	os.WriteFile(filepath.Join(repo, "deploy.sh"), []byte("export GH_TOKEN=$GH_TOKEN\n"), 0644)
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-am", "Use env").Run()
+
+	if _, err := coord.CheckSecrets(g); !errors.Is(err, errs.ErrSecretsFound) {
+		t.Errorf("Expected the secret still found in history, got %v", err)
+	}
+
+	// Rewriting the branch without it clears the findings on the next scan
+	exec.Command("git", "-C", repo, "reset", "--soft", g.BaseRef).Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add deploy").Run()
+
+	if _, err := coord.CheckSecrets(g); err != nil {
+		t.Errorf("Expected a clean branch, got %v", err)
+	}
+	if stored, _ := coord.db.ListSecretFindings("id-1"); len(stored) != 0 {
+		t.Errorf("Expected findings cleared, got %d", len(stored))
+	}
+}
@@ -0,0 +1,101 @@
+package coordinator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// selectorFields are the goblin fields a selector may match on
+var selectorFields = []string{"agent", "branch", "name", "project", "status"}
+
+// Selector picks a set of goblins for bulk operations. All set criteria
+// must match.
+type Selector struct {
+	All       bool
+	Status    string
+	OlderThan time.Duration
+	Fields    map[string]string // field=glob, see selectorFields
+}
+
+// Empty reports whether no criteria are set
+func (s Selector) Empty() bool {
+	return !s.All && s.Status == "" && s.OlderThan == 0 && len(s.Fields) == 0
+}
+
+// ParseSelector parses "k=v,k2=v2" into field matches. Values may be
+// shell globs, e.g. name=test-*.
+func ParseSelector(expr string) (map[string]string, error) {
+	fields := make(map[string]string)
+	if strings.TrimSpace(expr) == "" {
+		return fields, nil
+	}
+
+	for _, part := range strings.Split(expr, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid selector %q: expected key=value", part)
+		}
+
+		i := sort.SearchStrings(selectorFields, key)
+		if i == len(selectorFields) || selectorFields[i] != key {
+			return nil, fmt.Errorf("unknown selector key %q (use %s)", key, strings.Join(selectorFields, ", "))
+		}
+
+		value = strings.TrimSpace(value)
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern for %s: %w", key, err)
+		}
+		fields[key] = value
+	}
+
+	return fields, nil
+}
+
+// Matches reports whether a goblin satisfies every criterion
+func (s Selector) Matches(g *Goblin, now time.Time) bool {
+	if s.Empty() {
+		return false
+	}
+	if s.Status != "" && g.Status != s.Status {
+		return false
+	}
+	if s.OlderThan > 0 && now.Sub(g.CreatedAt) < s.OlderThan {
+		return false
+	}
+
+	for key, pattern := range s.Fields {
+		var value string
+		switch key {
+		case "agent":
+			value = g.Agent
+		case "branch":
+			value = g.Branch
+		case "name":
+			value = g.Name
+		case "project":
+			value = g.ProjectPath
+		case "status":
+			value = g.Status
+		}
+		if ok, _ := path.Match(pattern, value); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns the goblins a selector matches
+func (s Selector) Filter(goblins []*Goblin, now time.Time) []*Goblin {
+	var matched []*Goblin
+	for _, g := range goblins {
+		if s.Matches(g, now) {
+			matched = append(matched, g)
+		}
+	}
+	return matched
+}
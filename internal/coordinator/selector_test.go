@@ -0,0 +1,55 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSelector(t *testing.T) {
+	fields, err := ParseSelector("agent=claude, name=test-*")
+	if err != nil {
+		t.Fatalf("ParseSelector failed: %v", err)
+	}
+	if fields["agent"] != "claude" || fields["name"] != "test-*" {
+		t.Errorf("Unexpected fields: %v", fields)
+	}
+
+	if fields, err := ParseSelector(""); err != nil || len(fields) != 0 {
+		t.Errorf("Empty selector should parse to nothing, got %v (%v)", fields, err)
+	}
+
+	for _, bad := range []string{"agent", "=claude", "color=red", "name=[bad"} {
+		if _, err := ParseSelector(bad); err == nil {
+			t.Errorf("ParseSelector(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	now := time.Now()
+	goblins := []*Goblin{
+		{Name: "test-api", Agent: "claude", Status: "running", CreatedAt: now.Add(-48 * time.Hour)},
+		{Name: "test-ui", Agent: "codex", Status: "stopped", CreatedAt: now.Add(-time.Hour)},
+		{Name: "refactor", Agent: "claude", Status: "running", CreatedAt: now.Add(-30 * time.Hour)},
+	}
+
+	tests := []struct {
+		name     string
+		selector Selector
+		expected int
+	}{
+		{"empty matches nothing", Selector{}, 0},
+		{"all", Selector{All: true}, 3},
+		{"status", Selector{Status: "running"}, 2},
+		{"older than", Selector{OlderThan: 24 * time.Hour}, 2},
+		{"field glob", Selector{Fields: map[string]string{"name": "test-*"}}, 2},
+		{"combined", Selector{Status: "running", Fields: map[string]string{"name": "test-*"}}, 1},
+		{"agent and age", Selector{OlderThan: 36 * time.Hour, Fields: map[string]string{"agent": "claude"}}, 1},
+	}
+
+	for _, tc := range tests {
+		if got := len(tc.selector.Filter(goblins, now)); got != tc.expected {
+			t.Errorf("%s: expected %d matches, got %d", tc.name, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// Policies for uncommitted work when goblins are shut down
+const (
+	ShutdownCommit = "commit"
+	ShutdownStash  = "stash"
+	ShutdownNone   = "none"
+)
+
+// Outcomes of saving a goblin's work
+const (
+	SavedCommitted = "committed"
+	SavedStashed   = "stashed"
+	SavedClean     = "clean"
+	SavedSkipped   = "skipped"
+)
+
+// ShutdownResult describes what happened to one goblin during shutdown
+type ShutdownResult struct {
+	Name  string
+	Saved string
+	Err   error
+}
+
+// Shutdown stops every active goblin. Each agent is stopped first so it
+// cannot keep writing, then its uncommitted work is saved according to
+// policy (git.shutdown_policy when empty).
+func (c *Coordinator) Shutdown(policy string) ([]ShutdownResult, error) {
+	if policy == "" {
+		policy = c.cfg.Git.ShutdownPolicy
+	}
+	switch policy {
+	case ShutdownCommit, ShutdownStash, ShutdownNone:
+	default:
+		return nil, fmt.Errorf("unknown shutdown policy: %s (use commit, stash or none)", policy)
+	}
+
+	goblins, err := c.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goblins: %w", err)
+	}
+
+	var results []ShutdownResult
+	for _, g := range goblins {
+		if !active(g.Status) {
+			continue
+		}
+
+		result := ShutdownResult{Name: g.Name}
+		if err := c.Stop(g.ID); err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+
+		result.Saved, result.Err = c.saveWork(g, policy)
+		if c.log != nil {
+			if result.Err != nil {
+				c.log.Error("Failed to save goblin work on shutdown", result.Err)
+			} else {
+				c.log.Info("Saved goblin work on shutdown",
+					logging.String("name", g.Name),
+					logging.String("saved", result.Saved))
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// saveWork commits or stashes uncommitted changes in a goblin's worktree.
+// Goblins working directly in a non-git project directory, or in a copy
+// without version control, are skipped so the user's own checkout is
+// never touched.
+func (c *Coordinator) saveWork(g *Goblin, policy string) (string, error) {
+	if policy == ShutdownNone || g.WorktreePath == "" || g.WorktreePath == g.ProjectPath || !isGitRepo(g.WorktreePath) {
+		return SavedSkipped, nil
+	}
+
+	status, err := trace.Command("git", "-C", g.WorktreePath, "status", "--porcelain").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to check worktree of %s: %w", g.Name, err)
+	}
+	if strings.TrimSpace(string(status)) == "" {
+		return SavedClean, nil
+	}
+
+	switch policy {
+	case ShutdownStash:
+		message := fmt.Sprintf("gforge: %s at shutdown", g.Name)
+		if err := gitRun(g.WorktreePath, "stash", "push", "--include-untracked", "-m", message); err != nil {
+			return "", fmt.Errorf("failed to stash work of %s: %w", g.Name, err)
+		}
+		return SavedStashed, nil
+	default:
+		if err := commitAll(g, "gforge: checkpoint at shutdown"); err != nil {
+			return "", err
+		}
+		c.recordCommits(g)
+		return SavedCommitted, nil
+	}
+}
+
+// commitAll stages and commits everything in a goblin's worktree
+func commitAll(g *Goblin, message string) error {
+	if err := gitRun(g.WorktreePath, "add", "-A"); err != nil {
+		return fmt.Errorf("failed to stage work of %s: %w", g.Name, err)
+	}
+	args := append(identityArgs(g.WorktreePath), "commit", "--no-verify", "-m", message)
+	if err := gitRun(g.WorktreePath, args...); err != nil {
+		return fmt.Errorf("failed to commit work of %s: %w", g.Name, err)
+	}
+	return nil
+}
+
+// identityArgs supplies a fallback author when the repository has no git
+// identity, so a shutdown checkpoint never fails for want of one
+func identityArgs(dir string) []string {
+	if out, err := trace.Command("git", "-C", dir, "config", "user.email").Output(); err == nil && len(strings.TrimSpace(string(out))) > 0 {
+		return nil
+	}
+	return []string{"-c", "user.name=gforge", "-c", "user.email=gforge@localhost"}
+}
+
+// worktreeRun runs a git worktree command in a project holding its
+// worktree lock, folding the output into the error like gitRun
+func (c *Coordinator) worktreeRun(projectPath string, args ...string) error {
+	output, err := workspace.GitWorktree(projectPath, c.log, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// gitRun runs a git command in dir, folding its output into the error.
+// A lock held by another git process is waited out.
+func gitRun(dir string, args ...string) error {
+	output, err := trace.Retry(trace.DefaultBackoff, nil, func() *trace.Cmd {
+		return trace.Command("git", append([]string{"-C", dir}, args...)...)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
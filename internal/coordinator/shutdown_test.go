@@ -0,0 +1,117 @@
+package coordinator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+)
+
+func TestShutdown(t *testing.T) {
+	if !gitAvailable() || !tmuxAvailable() {
+		t.Skip("git or tmux not available")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	agent := &agents.Agent{Name: "echo", Command: "echo", Args: []string{"hello"}}
+
+	for _, name := range []string{"dirty", "clean"} {
+		if _, err := coord.Spawn(SpawnOptions{
+			Name:        name,
+			Agent:       agent,
+			ProjectPath: repoPath,
+			Branch:      "gforge/" + name,
+		}); err != nil {
+			t.Fatalf("Spawn %s failed: %v", name, err)
+		}
+		defer coord.Kill(name)
+	}
+
+	dirty, _ := coord.Get("dirty")
+	os.WriteFile(filepath.Join(dirty.WorktreePath, "work.txt"), []byte("progress\n"), 0644)
+
+	results, err := coord.Shutdown(ShutdownCommit)
+	if err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	saved := make(map[string]string)
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("Unexpected error for %s: %v", r.Name, r.Err)
+		}
+		saved[r.Name] = r.Saved
+	}
+	if saved["dirty"] != SavedCommitted || saved["clean"] != SavedClean {
+		t.Errorf("Unexpected outcomes: %v", saved)
+	}
+
+	subject, _ := exec.Command("git", "-C", dirty.WorktreePath, "log", "-1", "--format=%s").Output()
+	if !strings.Contains(string(subject), "checkpoint at shutdown") {
+		t.Errorf("Expected checkpoint commit, got %q", subject)
+	}
+
+	for _, name := range []string{"dirty", "clean"} {
+		if g, _ := coord.Get(name); g.Status != "stopped" {
+			t.Errorf("Expected %s stopped, got %s", name, g.Status)
+		}
+	}
+
+	// Already stopped goblins are left alone
+	results, _ = coord.Shutdown(ShutdownCommit)
+	if len(results) != 0 {
+		t.Errorf("Expected no results on second shutdown, got %d", len(results))
+	}
+}
+
+func TestSaveWorkStash(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	coord := New(nil, nil, nil)
+	os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Changed\n"), 0644)
+
+	g := &Goblin{Name: "stasher", WorktreePath: repoPath}
+	saved, err := coord.saveWork(g, ShutdownStash)
+	if err != nil {
+		t.Fatalf("saveWork failed: %v", err)
+	}
+	if saved != SavedStashed {
+		t.Errorf("Expected stashed, got %s", saved)
+	}
+
+	list, _ := exec.Command("git", "-C", repoPath, "stash", "list").Output()
+	if !strings.Contains(string(list), "gforge: stasher at shutdown") {
+		t.Errorf("Expected stash entry, got %q", list)
+	}
+
+	// Goblins working in the project directory itself are never touched
+	g.ProjectPath = repoPath
+	if saved, _ := coord.saveWork(g, ShutdownCommit); saved != SavedSkipped {
+		t.Errorf("Expected skipped, got %s", saved)
+	}
+}
+
+func TestShutdownUnknownPolicy(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if _, err := coord.Shutdown("discard"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
@@ -0,0 +1,71 @@
+package coordinator
+
+import (
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/webhook"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// postSlack posts a goblin completing or committing to Slack when
+// integrations.slack is on. The config is read at each event so a
+// reloaded one applies; a failed post is logged, never retried.
+func (c *Coordinator) postSlack(e agents.LifecycleEvent) {
+	sc := c.cfg.Integrations.Slack
+	if !sc.Enabled || !slackWants(sc.Events, e.Type) {
+		return
+	}
+
+	msg := integrations.SlackMessage{
+		Event:   e.Type,
+		Goblin:  e.Details["name"],
+		Agent:   e.AgentName,
+		Project: e.Details["project"],
+		Branch:  e.Details["branch"],
+	}
+	if e.Type == webhook.EventCommit {
+		msg.Commit, msg.Subject = e.Details["commit"], e.Details["detail"]
+	}
+	if g, _ := c.Get(e.GoblinID); g != nil {
+		if stat, err := workspace.BranchDiffStat(g.WorktreePath, g.BaseRef, c.DiffIgnore(g)); err == nil && stat.Files > 0 {
+			msg.DiffStat = stat.String()
+		}
+		msg.PRURL = c.lastEventDetail(g, EventPR)
+	}
+
+	text, err := integrations.RenderSlackMessage(sc.Templates, msg)
+	if err == nil {
+		err = integrations.NewSlackClient(sc.WebhookURL, sc.BotToken, sc.Channel).Post(text)
+	}
+	if err != nil && c.log != nil {
+		c.log.Warn("Failed to post to Slack",
+			logging.String("goblin", msg.Goblin),
+			logging.String("event", e.Type),
+			logging.Err(err))
+	}
+}
+
+// slackWants reports whether an event is posted to Slack: one of events,
+// or with none given, a completion or a commit
+func slackWants(events []string, event string) bool {
+	if len(events) == 0 {
+		return event == webhook.EventCompleted || event == webhook.EventCommit
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// lastEventDetail returns the detail of the latest event of eventType
+// recorded for a goblin, empty when there is none
+func (c *Coordinator) lastEventDetail(g *Goblin, eventType string) string {
+	e, err := c.db.LastEvent(g.ID, eventType)
+	if err != nil || e == nil {
+		return ""
+	}
+	return e.Detail
+}
@@ -0,0 +1,74 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestPostSlack(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	var (
+		mu     sync.Mutex
+		posted []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		posted = append(posted, payload["text"])
+		mu.Unlock()
+	}))
+	defer server.Close()
+	cfg.Integrations.Slack.WebhookURL = server.URL
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	worktree := filepath.Join(t.TempDir(), "wt")
+	if out, err := exec.Command("git", "-C", repo, "worktree", "add", "-b", "gforge/wt", worktree).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to add worktree: %v\n%s", err, out)
+	}
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "wt", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/wt", BaseRef: strings.TrimSpace(string(base))})
+	g, _ := coord.Get("wt")
+
+	// Off until enabled
+	os.WriteFile(filepath.Join(worktree, "a.txt"), []byte("a\n"), 0644)
+	coord.CommitWork(g, "Add a")
+	if len(posted) != 0 {
+		t.Fatalf("Expected nothing posted with slack disabled, got %v", posted)
+	}
+
+	cfg.Integrations.Slack.Enabled = true
+	os.WriteFile(filepath.Join(worktree, "b.txt"), []byte("b\n"), 0644)
+	coord.CommitWork(g, "Add b")
+	if len(posted) != 1 || !strings.Contains(posted[0], "*wt* committed `") || !strings.Contains(posted[0], "Add b on `gforge/wt` (2 files +2 -0 so far)") {
+		t.Errorf("Unexpected commit message: %v", posted)
+	}
+
+	coord.RecordEvent(g.ID, EventPR, "https://github.com/acme/app/pull/7")
+	cfg.Integrations.Slack.Events = []string{"completed"}
+	coord.Pin(g.Name, true)
+	if _, err := coord.Complete(g.ID); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	want := "*wt* finished on `gforge/wt` (2 files +2 -0)\n<https://github.com/acme/app/pull/7|Pull request>"
+	if len(posted) != 2 || posted[1] != want {
+		t.Errorf("Expected %q posted, got %v", want, posted)
+	}
+}
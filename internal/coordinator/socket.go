@@ -0,0 +1,75 @@
+package coordinator
+
+import (
+	"fmt"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+// socketUnsafe matches characters that can't appear in a tmux -L name
+var socketUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// tmuxSocket picks the socket a new goblin's session runs on: the spawn's
+// own, else the project's tmux_socket, else tmux.socket_template, else
+// tmux.socket_name. Names may use {project}, {name} and {user}, so a
+// template like "gforge-{project}" gives each project its own server.
+func (c *Coordinator) tmuxSocket(requested, projectPath string, project *config.ProjectConfig, name string) (string, error) {
+	socket := requested
+	if socket == "" && project != nil {
+		socket = project.TmuxSocket
+	}
+	if socket == "" {
+		socket = c.cfg.Tmux.SocketTemplate
+	}
+	if socket == "" {
+		return c.cfg.Tmux.SocketName, nil
+	}
+
+	username := "user"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+	safe := func(s string) string { return socketUnsafe.ReplaceAllString(s, "-") }
+	resolved := strings.NewReplacer(
+		"{project}", safe(filepath.Base(projectPath)),
+		"{name}", safe(name),
+		"{user}", safe(username),
+	).Replace(socket)
+
+	if resolved == "" || socketUnsafe.MatchString(resolved) {
+		return "", fmt.Errorf("invalid tmux socket name %q: use letters, digits, '.', '_' and '-'", resolved)
+	}
+	return resolved, nil
+}
+
+// Socket returns the tmux socket a goblin's session runs on. Goblins from
+// before per-project sockets use tmux.socket_name.
+func (c *Coordinator) Socket(g *Goblin) string {
+	if g.TmuxSocket != "" {
+		return g.TmuxSocket
+	}
+	return c.cfg.Tmux.SocketName
+}
+
+// Sockets lists the tmux sockets in use: tmux.socket_name and those of
+// existing goblins
+func (c *Coordinator) Sockets() ([]string, error) {
+	goblins, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	sockets := []string{c.cfg.Tmux.SocketName}
+	seen := map[string]bool{c.cfg.Tmux.SocketName: true}
+	for _, g := range goblins {
+		if socket := c.Socket(g); !seen[socket] {
+			seen[socket] = true
+			sockets = append(sockets, socket)
+		}
+	}
+	return sockets, nil
+}
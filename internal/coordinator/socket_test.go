@@ -0,0 +1,82 @@
+package coordinator
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestTmuxSocket(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if socket, _ := coord.tmuxSocket("", "/src/webapp", &config.ProjectConfig{}, "fixer"); socket != cfg.Tmux.SocketName {
+		t.Errorf("Expected tmux.socket_name by default, got %s", socket)
+	}
+
+	cfg.Tmux.SocketTemplate = "gforge-{project}"
+	if socket, _ := coord.tmuxSocket("", "/src/my app", &config.ProjectConfig{}, "fixer"); socket != "gforge-my-app" {
+		t.Errorf("Expected the template expanded with a safe project name, got %s", socket)
+	}
+
+	project := &config.ProjectConfig{TmuxSocket: "squad-{name}"}
+	if socket, _ := coord.tmuxSocket("", "/src/webapp", project, "fix/login"); socket != "squad-fix-login" {
+		t.Errorf("Expected the project's socket, got %s", socket)
+	}
+	if socket, _ := coord.tmuxSocket("mine", "/src/webapp", project, "fixer"); socket != "mine" {
+		t.Errorf("Expected the spawn's socket to win, got %s", socket)
+	}
+	if _, err := coord.tmuxSocket("../other", "/src/webapp", project, "fixer"); err == nil {
+		t.Error("Expected a socket name with a path rejected")
+	}
+
+	if socket := coord.Socket(&Goblin{}); socket != cfg.Tmux.SocketName {
+		t.Errorf("Expected goblins without a socket on tmux.socket_name, got %s", socket)
+	}
+}
+
+func TestSpawnProjectSocket(t *testing.T) {
+	if !gitAvailable() || !tmuxAvailable() {
+		t.Skip("git or tmux not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+	cfg.Tmux.SocketTemplate = "gforge-test-{name}"
+	defer exec.Command("tmux", "-L", "gforge-test-own", "kill-server").Run()
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	goblin, err := coord.Spawn(SpawnOptions{
+		Name:        "own",
+		Agent:       &agents.Agent{Name: "echo", Command: "echo"},
+		ProjectPath: repoPath,
+		Branch:      "gforge/own",
+	})
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+
+	stored, _ := coord.Get("own")
+	if stored.TmuxSocket != "gforge-test-own" {
+		t.Fatalf("Expected the socket stored, got %q", stored.TmuxSocket)
+	}
+	out, err := exec.Command("tmux", "-L", "gforge-test-own", "list-sessions", "-F", "#{session_name}").Output()
+	if err != nil || strings.TrimSpace(string(out)) != goblin.TmuxSession {
+		t.Errorf("Expected the session on its own server, got %q, %v", out, err)
+	}
+	if exec.Command("tmux", "-L", cfg.Tmux.SocketName, "has-session", "-t", goblin.TmuxSession).Run() == nil {
+		t.Error("Expected no session on the default server")
+	}
+
+	if err := coord.Kill("own"); err != nil {
+		t.Fatalf("Kill failed: %v", err)
+	}
+	if exec.Command("tmux", "-L", "gforge-test-own", "has-session", "-t", goblin.TmuxSession).Run() == nil {
+		t.Error("Expected Kill to end the session on the goblin's socket")
+	}
+}
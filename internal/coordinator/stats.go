@@ -0,0 +1,38 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// SnapshotStats records the current goblin counts for trend history if
+// the last snapshot is at least interval old. Reports whether one was
+// taken; a zero interval never takes one.
+func (c *Coordinator) SnapshotStats(interval time.Duration) (bool, error) {
+	if interval <= 0 {
+		return false, nil
+	}
+
+	last, err := c.db.LastStatsSnapshot()
+	if err != nil {
+		return false, err
+	}
+	if !last.IsZero() && time.Since(last) < interval {
+		return false, nil
+	}
+
+	stats, err := c.db.GetStats()
+	if err != nil {
+		return false, err
+	}
+	if err := c.db.SaveStatsSnapshot(stats); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StatsHistory returns snapshots taken since the given time
+func (c *Coordinator) StatsHistory(since time.Time) ([]*storage.StatsSnapshot, error) {
+	return c.db.ListStatsSnapshots(since)
+}
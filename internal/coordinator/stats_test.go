@@ -0,0 +1,33 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestSnapshotStats(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "coder", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	if taken, _ := coord.SnapshotStats(0); taken {
+		t.Error("Expected a zero interval to disable snapshots")
+	}
+	if taken, err := coord.SnapshotStats(time.Hour); err != nil || !taken {
+		t.Fatalf("Expected the first snapshot taken, got %v, %v", taken, err)
+	}
+	if taken, _ := coord.SnapshotStats(time.Hour); taken {
+		t.Error("Expected no second snapshot within the interval")
+	}
+
+	history, err := coord.StatsHistory(time.Now().Add(-time.Hour))
+	if err != nil || len(history) != 1 {
+		t.Fatalf("Expected one snapshot, got %v, %v", history, err)
+	}
+	if history[0].Running != 1 || history[0].Total != 1 {
+		t.Errorf("Unexpected snapshot %+v", history[0])
+	}
+}
@@ -0,0 +1,123 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/summarize"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+)
+
+// Summarizer condenses output, updating a previous summary
+type Summarizer interface {
+	Summarize(ctx context.Context, previous, output string) (string, error)
+}
+
+// newSummarizer builds the summarizer goblins are summarized with on
+// completion
+var newSummarizer = func(cfg config.SummarizerConfig) (Summarizer, error) {
+	return summarize.New(cfg)
+}
+
+// completeSummaryTimeout bounds the summary written as a goblin completes
+var completeSummaryTimeout = time.Minute
+
+// Summarize refreshes a goblin's rolling summary from its recent pane
+// output and stores it. The stored summary is returned unchanged when the
+// output has not moved since it was written.
+func (c *Coordinator) Summarize(ctx context.Context, nameOrID string, s Summarizer) (*storage.Summary, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if goblin == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+
+	mgr := tmux.NewManager(tmux.Config{SocketName: c.Socket(goblin)})
+	output, err := mgr.CapturePane(goblin.TmuxSession, c.cfg.Summarizer.Lines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+
+	return c.summarizeOutput(ctx, goblin, output, s)
+}
+
+func (c *Coordinator) summarizeOutput(ctx context.Context, g *Goblin, output string, s Summarizer) (*storage.Summary, error) {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, fmt.Errorf("no output to summarize for %s", g.Name)
+	}
+
+	sum := sha256.Sum256([]byte(output))
+	hash := hex.EncodeToString(sum[:])
+
+	previous, err := c.db.GetSummary(g.ID)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.OutputHash == hash {
+		return previous, nil
+	}
+
+	prevText := ""
+	if previous != nil {
+		prevText = previous.Summary
+	}
+
+	text, err := s.Summarize(ctx, prevText, output)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &storage.Summary{GoblinID: g.ID, GoblinName: g.Name, Summary: text, OutputHash: hash}
+	if err := c.db.SaveSummary(summary); err != nil {
+		return nil, err
+	}
+
+	if c.log != nil {
+		c.log.Info("Summarized goblin output",
+			logging.String("goblin", g.Name),
+			logging.Int("bytes", len(output)))
+	}
+
+	return c.db.GetSummary(g.ID)
+}
+
+// completionOutput captures a goblin's pane for its completion summary,
+// empty when summarizer.on_complete is off or the pane can't be read
+func (c *Coordinator) completionOutput(g *Goblin) string {
+	if !c.cfg.Summarizer.OnComplete || g.TmuxSession == "" {
+		return ""
+	}
+	mgr := tmux.NewManager(tmux.Config{SocketName: c.Socket(g)})
+	output, err := mgr.CapturePane(g.TmuxSession, c.cfg.Summarizer.Lines)
+	if err != nil {
+		return ""
+	}
+	return output
+}
+
+// summarizeCompleted stores a final summary of a completed goblin's
+// output. Failures are logged and leave the completion standing.
+func (c *Coordinator) summarizeCompleted(g *Goblin, output string) {
+	s, err := newSummarizer(c.cfg.Summarizer)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), completeSummaryTimeout)
+		defer cancel()
+		_, err = c.summarizeOutput(ctx, g, output, s)
+	}
+	if err != nil && c.log != nil {
+		c.log.Warn("Failed to summarize completed goblin",
+			logging.String("name", g.Name),
+			logging.Err(err))
+	}
+}
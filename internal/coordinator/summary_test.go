@@ -0,0 +1,97 @@
+package coordinator
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// fakeSummarizer records what it was asked to summarize
+type fakeSummarizer struct {
+	calls    int
+	previous string
+}
+
+func (f *fakeSummarizer) Summarize(ctx context.Context, previous, output string) (string, error) {
+	f.calls++
+	f.previous = previous
+	return "summary " + output, nil
+}
+
+func TestSummarizeOutput(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	coord.db.CreateGoblin(&storage.Goblin{ID: "id-1", Name: "coder", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+	g := &Goblin{ID: "id-1", Name: "coder"}
+	fake := &fakeSummarizer{}
+
+	s, err := coord.summarizeOutput(context.Background(), g, "built the parser\n", fake)
+	if err != nil {
+		t.Fatalf("summarizeOutput failed: %v", err)
+	}
+	if s.Summary != "summary built the parser" || fake.previous != "" {
+		t.Errorf("Unexpected first summary %q (previous %q)", s.Summary, fake.previous)
+	}
+
+	// Unchanged output reuses the stored summary
+	coord.summarizeOutput(context.Background(), g, "built the parser", fake)
+	if fake.calls != 1 {
+		t.Errorf("Expected unchanged output not to be summarized again, got %d calls", fake.calls)
+	}
+
+	// New output rolls the previous summary forward
+	s, _ = coord.summarizeOutput(context.Background(), g, "tests pass", fake)
+	if fake.previous != "summary built the parser" || s.Summary != "summary tests pass" {
+		t.Errorf("Expected a rolling summary, got %q (previous %q)", s.Summary, fake.previous)
+	}
+
+	if _, err := coord.summarizeOutput(context.Background(), g, "  \n", fake); err == nil {
+		t.Error("Expected an error for empty output")
+	}
+}
+
+func TestCompleteSummarizes(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not installed")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	fake := &fakeSummarizer{}
+	build := newSummarizer
+	newSummarizer = func(config.SummarizerConfig) (Summarizer, error) { return fake, nil }
+	defer func() { newSummarizer = build }()
+
+	for _, name := range []string{"coder", "quiet"} {
+		session := "gforge-summary-" + name
+		exec.Command("tmux", "-L", cfg.Tmux.SocketName, "new-session", "-d", "-s", session, "echo all tests pass; sleep 60").Run()
+		defer exec.Command("tmux", "-L", cfg.Tmux.SocketName, "kill-session", "-t", session).Run()
+		coord.db.CreateGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "running",
+			ProjectPath: "/tmp", TmuxSession: session})
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	cfg.Summarizer.OnComplete = false
+	if _, err := coord.Complete("quiet"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if fake.calls != 0 {
+		t.Errorf("Expected no summary with on_complete off, got %d calls", fake.calls)
+	}
+
+	cfg.Summarizer.OnComplete = true
+	if _, err := coord.Complete("coder"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	s, _ := coord.db.GetSummary("id-coder")
+	if s == nil || !strings.Contains(s.Summary, "all tests pass") {
+		t.Errorf("Expected the final output summarized, got %+v", s)
+	}
+}
@@ -0,0 +1,112 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+)
+
+// Events recorded by the supervisor (gforge daemon)
+const (
+	EventExited  = "exited"  // A goblin's session ended without gforge stopping it
+	EventCleaned = "cleaned" // An idle goblin was archived by cleanup
+)
+
+// Reconcile stores StatusDead on goblins whose tmux session ended on its
+// own, so their status stops claiming they run: their open task ends and
+// their slot goes to the queue. It returns the goblins marked. When tmux
+// fails to list sessions nothing is marked, so one failed call doesn't
+// take a fleet for dead. Each pass also stops the managed ollama server
+// once no local goblin is left running.
+func (c *Coordinator) Reconcile() ([]*Goblin, error) {
+	goblins, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]string, len(goblins))
+	for _, g := range goblins {
+		stored[g.ID] = g.Status
+	}
+	if err := c.CheckSessions(goblins); err != nil {
+		return nil, err
+	}
+
+	var exited []*Goblin
+	for _, g := range goblins {
+		if g.Status != StatusDead || stored[g.ID] == StatusDead {
+			continue
+		}
+		if err := c.db.UpdateGoblinStatus(g.ID, StatusDead); err != nil {
+			return exited, err
+		}
+		c.endTasks(g)
+		c.recordEvent(g.ID, g.Name, EventExited, "session ended")
+		c.releaseLocks(g)
+		exited = append(exited, g)
+
+		if c.log != nil {
+			c.log.Info("Goblin session ended",
+				logging.String("name", g.Name),
+				logging.String("id", g.ID))
+		}
+	}
+
+	// Checked every pass: an agent exiting to the shell leaves its session
+	// up, so it never shows among the exited
+	c.releaseOllama()
+	if len(exited) > 0 {
+		c.resumePreempted()
+	}
+	return exited, nil
+}
+
+// SessionAlive reports whether a goblin's tmux session exists
+func (c *Coordinator) SessionAlive(g *Goblin) (bool, error) {
+	sessions, err := tmux.NewManager(tmux.Config{SocketName: c.Socket(g)}).Sessions()
+	if err != nil {
+		return false, err
+	}
+	_, ok := sessions[g.TmuxSession]
+	return ok, nil
+}
+
+// Cleanup archives goblins that have had no session for at least maxAge
+// (stopped, failed, dead or completed, and not pinned), freeing their
+// worktrees while keeping their work, then prunes stale worktree entries
+// in their projects. Goblins that can't be archived, such as those
+// working directly in a project, are left alone. It returns the goblins
+// archived.
+func (c *Coordinator) Cleanup(maxAge time.Duration) ([]*Goblin, error) {
+	goblins, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var cleaned []*Goblin
+	projects := make(map[string]bool)
+	for _, g := range goblins {
+		if active(g.Status) || g.Status == StatusArchived || g.Pinned || time.Since(g.UpdatedAt) < maxAge {
+			continue
+		}
+		archived, err := c.Archive(g.ID)
+		if err != nil {
+			if c.log != nil {
+				c.log.Debug("Left idle goblin in place",
+					logging.String("name", g.Name),
+					logging.Err(err))
+			}
+			continue
+		}
+		c.recordEvent(g.ID, g.Name, EventCleaned, "idle since "+g.UpdatedAt.Local().Format("Jan 2 15:04"))
+		cleaned = append(cleaned, archived)
+		projects[g.ProjectPath] = true
+	}
+
+	for project := range projects {
+		if isGitRepo(project) {
+			c.worktreeRun(project, "prune")
+		}
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,111 @@
+package coordinator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestReconcile(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-gone", Name: "gone", Agent: "claude", Status: "running",
+		ProjectPath: dir, WorktreePath: dir, TmuxSession: "gforge-no-such-session", CreatedAt: now, UpdatedAt: now})
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-idle", Name: "idle", Agent: "claude", Status: "stopped",
+		ProjectPath: dir, WorktreePath: dir, CreatedAt: now, UpdatedAt: now})
+	coord.db.RecordTask("id-gone", "Fix the tests")
+	coord.db.AddPathLock("id-gone", dir, "src/")
+
+	// A tmux that fails to list sessions marks nothing dead
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "tmux"), []byte("#!/bin/sh\necho 'protocol version mismatch' >&2\nexit 1\n"), 0755)
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", bin)
+	exited, err := coord.Reconcile()
+	os.Setenv("PATH", path)
+	if err == nil || len(exited) != 0 {
+		t.Fatalf("Expected an error and nothing marked, got %v, %v", exited, err)
+	}
+	if g, _ := coord.Get("gone"); g.Status != "running" {
+		t.Fatalf("Expected gone left running, got %s", g.Status)
+	}
+
+	exited, err = coord.Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(exited) != 1 || exited[0].Name != "gone" {
+		t.Fatalf("Expected only gone to have exited, got %v", exited)
+	}
+
+	g, _ := coord.Get("gone")
+	if g.Status != StatusDead {
+		t.Errorf("Expected gone stored as %s, got %s", StatusDead, g.Status)
+	}
+	if g, _ := coord.Get("idle"); g.Status != "stopped" {
+		t.Errorf("Expected idle left stopped, got %s", g.Status)
+	}
+	tasks, _ := coord.db.ListTasks("id-gone")
+	if len(tasks) != 1 || tasks[0].EndedAt.IsZero() {
+		t.Errorf("Expected the open task ended, got %+v", tasks)
+	}
+	if locks, _ := coord.Locks(dir); len(locks) != 0 {
+		t.Errorf("Expected its locks released, got %v", locks)
+	}
+
+	// Already dead goblins aren't reported again
+	if exited, _ := coord.Reconcile(); len(exited) != 0 {
+		t.Errorf("Expected nothing new on a second pass, got %v", exited)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	for _, name := range []string{"old", "fresh"} {
+		worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-"+name), "gforge/"+name, "")
+		if err != nil {
+			t.Fatalf("createWorktree failed: %v", err)
+		}
+		updated := old
+		if name == "fresh" {
+			updated = time.Now()
+		}
+		coord.db.RestoreGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "stopped",
+			ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/" + name, CreatedAt: old, UpdatedAt: updated})
+	}
+
+	cleaned, err := coord.Cleanup(7 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if len(cleaned) != 1 {
+		t.Fatalf("Expected only old archived, got %v", cleaned)
+	}
+	if g, _ := coord.Get("old"); g.Status != StatusArchived {
+		t.Errorf("Expected old archived, got %s", g.Status)
+	}
+	if g, _ := coord.Get("fresh"); g.Status != "stopped" {
+		t.Errorf("Expected fresh left alone, got %s", g.Status)
+	}
+
+	coord.db.SetGoblinPinned("fresh", true)
+	if cleaned, _ := coord.Cleanup(0); len(cleaned) != 0 {
+		t.Errorf("Expected a pinned goblin never cleaned up, got %v", cleaned)
+	}
+}
@@ -0,0 +1,144 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// EventTaskQueued records a task queued behind the one its goblin is on
+const EventTaskQueued = "task_queued"
+
+// SubmitTask sends a task to a goblin when it is free, else queues it
+// behind the task it is on, to be sent by SendNextTask once that one
+// ends. A goblin is busy while its last task is open (see EndTask) or
+// tasks are already queued for it. With locks.on_conflict set to queue,
+// a task naming paths other goblins hold is queued too, until they are
+// unlocked. It returns the task's place in the queue, 0 when it was sent.
+func (c *Coordinator) SubmitTask(nameOrID, task string) (int, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return 0, err
+	}
+	if goblin == nil {
+		return 0, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+
+	queue, err := c.db.ListTaskQueue(goblin.ID)
+	if err != nil {
+		return 0, err
+	}
+	busy := len(queue) > 0
+	if !busy {
+		if busy, err = c.busy(goblin); err != nil {
+			return 0, err
+		}
+	}
+	if !busy {
+		locks, err := c.heldBack(goblin, task)
+		if err != nil {
+			return 0, err
+		}
+		if len(locks) == 0 {
+			return 0, c.SendTask(goblin.ID, task)
+		}
+		c.recordEvent(goblin.ID, goblin.Name, EventLockConflict, describeLocks(locks))
+	}
+
+	// A goblin without a session would never get to the task
+	if err := c.requireSession(goblin); err != nil {
+		return 0, err
+	}
+	if _, err := c.db.EnqueueTask(goblin.ID, task); err != nil {
+		return 0, err
+	}
+	c.recordEvent(goblin.ID, goblin.Name, EventTaskQueued, task)
+
+	if c.log != nil {
+		c.log.Info("Queued task for goblin",
+			logging.String("goblin", goblin.Name),
+			logging.Int("position", len(queue)+1))
+	}
+	return len(queue) + 1, nil
+}
+
+// busy reports whether a goblin's last task is still open
+func (c *Coordinator) busy(g *Goblin) (bool, error) {
+	tasks, err := c.db.ListTasks(g.ID)
+	if err != nil {
+		return false, err
+	}
+	return len(tasks) > 0 && tasks[len(tasks)-1].EndedAt.IsZero(), nil
+}
+
+// TaskQueue returns the tasks queued for a goblin, next to be sent first
+func (c *Coordinator) TaskQueue(g *Goblin) ([]*storage.QueuedTask, error) {
+	return c.db.ListTaskQueue(g.ID)
+}
+
+// QueuedTasks returns the tasks queued for every goblin
+func (c *Coordinator) QueuedTasks() ([]*storage.QueuedTask, error) {
+	return c.db.ListTaskQueue("")
+}
+
+// ClearTaskQueue drops the tasks queued for a goblin and returns how many
+// there were
+func (c *Coordinator) ClearTaskQueue(g *Goblin) (int, error) {
+	return c.db.ClearTaskQueue(g.ID)
+}
+
+// SendNextTask sends a goblin the first task in its queue, claiming it
+// first so that two callers racing can't both send it; a task that fails
+// to send goes back to the head of the queue. A task waiting on path
+// locks stays queued, and is dropped with errs.ErrPathLocked once it has
+// waited locks.max_wait. Call it when the goblin's agent has finished
+// the task it was on. It returns the task sent, nil when none was queued
+// or the next one is still held back.
+func (c *Coordinator) SendNextTask(nameOrID string) (*storage.QueuedTask, error) {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if goblin == nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+
+	queue, err := c.db.ListTaskQueue(goblin.ID)
+	if err != nil || len(queue) == 0 {
+		return nil, err
+	}
+	next := queue[0]
+	locks, err := c.heldBack(goblin, next.Task)
+	if err != nil {
+		return nil, err
+	}
+	if len(locks) > 0 {
+		if time.Since(next.QueuedAt) < c.cfg.Locks.MaxWait {
+			return nil, nil
+		}
+		if dropped, err := c.db.DequeueTask(next.ID); err != nil || !dropped {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: dropped %q, still held after %s: %s",
+			errs.ErrPathLocked, next.Task, c.cfg.Locks.MaxWait, describeLocks(locks))
+	}
+
+	claimed, err := c.db.DequeueTask(next.ID)
+	if err != nil || !claimed {
+		// Another caller took it and sends it
+		return nil, err
+	}
+	if err := c.SendTask(goblin.ID, next.Task); err != nil {
+		if rerr := c.db.RequeueTask(next); rerr != nil && c.log != nil {
+			c.log.Warn("Failed to put back queued task",
+				logging.String("goblin", goblin.Name),
+				logging.String("task", next.Task),
+				logging.Err(rerr))
+		}
+		return nil, err
+	}
+	return next, nil
+}
@@ -0,0 +1,102 @@
+package coordinator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestTaskQueue(t *testing.T) {
+	if !gitAvailable() || !tmuxAvailable() {
+		t.Skip("git or tmux not available")
+	}
+
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	goblin, err := coord.Spawn(SpawnOptions{
+		Name:        "queue-test",
+		Agent:       &agents.Agent{Name: "cat", Command: "cat"},
+		ProjectPath: repoPath,
+		Branch:      "gforge/queue-test",
+		NoContext:   true,
+	})
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	defer coord.Kill("queue-test")
+	time.Sleep(100 * time.Millisecond)
+
+	// A free goblin gets the task right away
+	if pos, err := coord.SubmitTask("queue-test", "first"); err != nil || pos != 0 {
+		t.Fatalf("Expected the first task sent, got position %d, %v", pos, err)
+	}
+
+	// Later ones wait for it to end
+	for i, task := range []string{"second", "third"} {
+		if pos, err := coord.SubmitTask("queue-test", task); err != nil || pos != i+1 {
+			t.Fatalf("Expected %s queued at %d, got %d, %v", task, i+1, pos, err)
+		}
+	}
+	queue, _ := coord.TaskQueue(goblin)
+	if len(queue) != 2 || queue[0].Task != "second" {
+		t.Fatalf("Expected second and third queued, got %v", queue)
+	}
+
+	// Even once the task ends, queued tasks go first
+	coord.EndTask("queue-test")
+	if pos, _ := coord.SubmitTask("queue-test", "fourth"); pos != 3 {
+		t.Errorf("Expected fourth queued behind the others, got %d", pos)
+	}
+
+	next, err := coord.SendNextTask("queue-test")
+	if err != nil || next == nil || next.Task != "second" {
+		t.Fatalf("Expected second sent next, got %v, %v", next, err)
+	}
+	tasks, _ := coord.db.ListTasks(goblin.ID)
+	if len(tasks) != 2 || tasks[1].Task != "second" || !tasks[1].EndedAt.IsZero() {
+		t.Errorf("Expected second recorded as the open task, got %v", tasks)
+	}
+
+	if n, err := coord.ClearTaskQueue(goblin); err != nil || n != 2 {
+		t.Errorf("Expected third and fourth dropped, got %d, %v", n, err)
+	}
+	if next, err := coord.SendNextTask("queue-test"); err != nil || next != nil {
+		t.Errorf("Expected nothing to send, got %v, %v", next, err)
+	}
+
+	events, _ := coord.db.ListEvents(time.Now().Add(-time.Minute))
+	queued := 0
+	for _, e := range events {
+		if e.Type == EventTaskQueued {
+			queued++
+		}
+	}
+	if queued != 3 {
+		t.Errorf("Expected 3 task_queued events, got %d", queued)
+	}
+}
+
+func TestSubmitTaskStopped(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if _, err := coord.SubmitTask("nonexistent", "task"); err == nil {
+		t.Error("Expected an error for a nonexistent goblin")
+	}
+
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-stopped", Name: "stopped-test", Agent: "claude", Status: "stopped",
+		ProjectPath: "/src/app", CreatedAt: now, UpdatedAt: now})
+	coord.db.RecordTask("id-stopped", "still open")
+	_, err := coord.SubmitTask("stopped-test", "task")
+	if err == nil || !strings.Contains(err.Error(), "stopped") {
+		t.Errorf("Expected a goblin without a session refused, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// startTask records a task sent to a goblin whose worktree was at head,
+// ending the task before it there: whatever the agent committed since
+// belongs to the earlier task
+func (c *Coordinator) startTask(g *Goblin, task, head string) {
+	if _, err := c.db.EndTasks(g.ID, head); err != nil && c.log != nil {
+		c.log.Warn("Failed to end task", logging.String("goblin", g.Name), logging.Err(err))
+	}
+	if err := c.db.RecordTaskFrom(g.ID, task, head); err != nil && c.log != nil {
+		c.log.Warn("Failed to record task", logging.String("goblin", g.Name))
+	}
+}
+
+// endTasks closes a goblin's open task at the commit its worktree is at
+func (c *Coordinator) endTasks(g *Goblin) {
+	if _, err := c.db.EndTasks(g.ID, headCommit(g.WorktreePath)); err != nil && c.log != nil {
+		c.log.Warn("Failed to end task", logging.String("goblin", g.Name), logging.Err(err))
+	}
+}
+
+// EndTask closes a goblin's open task at the commit its worktree is at
+// now, as when its agent goes quiet
+func (c *Coordinator) EndTask(nameOrID string) error {
+	goblin, err := c.Get(nameOrID)
+	if err != nil {
+		return err
+	}
+	if goblin == nil {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, nameOrID)
+	}
+	_, err = c.db.EndTasks(goblin.ID, headCommit(goblin.WorktreePath))
+	return err
+}
+
+// TaskDiff returns the changes a task produced: from the commit its
+// goblin's worktree was at when it was sent to the one it ended at, or to
+// the worktree as it is now while the task is still open
+func (c *Coordinator) TaskDiff(g *Goblin, task *storage.TaskRecord) (string, error) {
+	if task.StartCommit == "" {
+		return "", fmt.Errorf("no commits were recorded for this task; it was sent before gforge tracked them or outside a git worktree")
+	}
+	if task.EndedAt.IsZero() {
+		return workspace.DiffBetween(g.WorktreePath, task.StartCommit, "", c.DiffIgnore(g))
+	}
+	if task.EndCommit == "" {
+		return "", fmt.Errorf("no end commit was recorded for this task")
+	}
+
+	// An archived goblin's commits are still in its project's repository
+	dir := g.WorktreePath
+	if _, err := os.Stat(dir); err != nil {
+		dir = g.ProjectPath
+	}
+	return workspace.DiffBetween(dir, task.StartCommit, task.EndCommit, c.DiffIgnore(g))
+}
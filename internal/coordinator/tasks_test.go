@@ -0,0 +1,71 @@
+package coordinator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestTaskDiff(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	repo, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	worktree, err := coord.createWorktree(repo, filepath.Join(cfg.WorktreeBase, "g-tasks"), "gforge/tasks", "")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	now := time.Now()
+	coord.db.RestoreGoblin(&storage.Goblin{ID: "id-tasks", Name: "tasks", Agent: "claude", Status: "running",
+		ProjectPath: repo, WorktreePath: worktree, Branch: "gforge/tasks", CreatedAt: now, UpdatedAt: now})
+	g, _ := coord.Get("tasks")
+
+	commit := func(name string) {
+		os.WriteFile(filepath.Join(worktree, name), []byte(name+"\n"), 0644)
+		gitRun(worktree, "add", name)
+		gitRun(worktree, append(identityArgs(worktree), "commit", "-m", "Add "+name)...)
+	}
+
+	coord.startTask(g, "Add one", headCommit(worktree))
+	commit("one.txt")
+	coord.startTask(g, "Add two", headCommit(worktree))
+	commit("two.txt")
+	os.WriteFile(filepath.Join(worktree, "one.txt"), []byte("edited\n"), 0644)
+
+	tasks, _ := coord.db.ListTasks(g.ID)
+	if len(tasks) != 2 || tasks[0].EndedAt.IsZero() || tasks[0].EndCommit != tasks[1].StartCommit {
+		t.Fatalf("Expected the first task ended where the second began, got %+v", tasks)
+	}
+
+	diff, err := coord.TaskDiff(g, tasks[0])
+	if err != nil || !strings.Contains(diff, "one.txt") || strings.Contains(diff, "two.txt") {
+		t.Errorf("Expected only one.txt in the first task's diff, got %q, %v", diff, err)
+	}
+	// The open task runs to the worktree, uncommitted edits included
+	diff, _ = coord.TaskDiff(g, tasks[1])
+	if !strings.Contains(diff, "two.txt") || !strings.Contains(diff, "+edited") {
+		t.Errorf("Expected two.txt and the edit in the open task's diff, got %q", diff)
+	}
+
+	if err := coord.EndTask("tasks"); err != nil {
+		t.Fatalf("EndTask failed: %v", err)
+	}
+	tasks, _ = coord.db.ListTasks(g.ID)
+	if tasks[1].EndedAt.IsZero() || tasks[1].EndCommit != headCommit(worktree) {
+		t.Errorf("Expected the second task ended at HEAD, got %+v", tasks[1])
+	}
+
+	if _, err := coord.TaskDiff(g, &storage.TaskRecord{Task: "old"}); err == nil {
+		t.Error("Expected a task without commits to fail")
+	}
+}
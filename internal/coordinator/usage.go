@@ -0,0 +1,69 @@
+package coordinator
+
+import (
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+	"github.com/astoreyai/goblin-forge/internal/usage"
+)
+
+// Price returns an agent's token price: pricing for the agent, then for
+// its provider, then the provider's list price
+func (c *Coordinator) Price(agentName string) config.PriceConfig {
+	if p, ok := c.cfg.Pricing[agentName]; ok {
+		return p
+	}
+	provider := ""
+	if a := agents.NewRegistry().Get(agentName); a != nil {
+		provider = a.Provider
+	}
+	if p, ok := c.cfg.Pricing[provider]; ok {
+		return p
+	}
+	return usage.DefaultPrices[provider]
+}
+
+// recordUsage stores estimated token usage for a goblin, priced now so
+// later price changes don't rewrite history
+func (c *Coordinator) recordUsage(g *Goblin, inputTokens, outputTokens int) {
+	u := &storage.Usage{
+		GoblinID:     g.ID,
+		GoblinName:   g.Name,
+		Agent:        g.Agent,
+		Project:      g.ProjectPath,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Cost:         usage.Cost(c.Price(g.Agent), inputTokens, outputTokens),
+	}
+	if err := c.db.RecordUsage(u); err != nil && c.log != nil {
+		c.log.Warn("Failed to record usage",
+			logging.String("goblin", g.Name),
+			logging.Err(err))
+	}
+}
+
+// recordOutputUsage estimates the agent's output tokens from its pane
+// history and records what has appeared since the last estimate. Run
+// before the session goes away.
+func (c *Coordinator) recordOutputUsage(g *Goblin) {
+	mgr := tmux.NewManager(tmux.Config{SocketName: c.Socket(g)})
+	lines := c.cfg.Tmux.HistoryLimit
+	if lines <= 0 {
+		lines = -1 // All the pane keeps
+	}
+	output, err := mgr.CapturePane(g.TmuxSession, lines)
+	if err != nil {
+		return
+	}
+
+	recorded, err := c.db.OutputTokens(g.ID)
+	if err != nil {
+		return
+	}
+	if n := tokens.Count(output) - recorded; n > 0 {
+		c.recordUsage(g, 0, n)
+	}
+}
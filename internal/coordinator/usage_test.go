@@ -0,0 +1,47 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestPrice(t *testing.T) {
+	coord, cfg, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	if p := coord.Price("claude"); p.Input != 3 || p.Output != 15 {
+		t.Errorf("Expected the anthropic list price, got %+v", p)
+	}
+
+	cfg.Pricing = map[string]config.PriceConfig{
+		"anthropic":   {Input: 1, Output: 2},
+		"claude-auto": {Input: 5, Output: 5},
+	}
+	if p := coord.Price("claude"); p.Input != 1 {
+		t.Errorf("Expected the provider override, got %+v", p)
+	}
+	if p := coord.Price("claude-auto"); p.Input != 5 {
+		t.Errorf("Expected the agent override, got %+v", p)
+	}
+	if p := coord.Price("unknown"); p.Input != 0 || p.Output != 0 {
+		t.Errorf("Expected unknown agents to be free, got %+v", p)
+	}
+}
+
+func TestRecordUsage(t *testing.T) {
+	coord, _, cleanup := setupCoordinator(t)
+	defer cleanup()
+
+	g := &Goblin{ID: "id-1", Name: "coder", Agent: "claude", ProjectPath: "/src/app"}
+	coord.recordUsage(g, 1_000_000, 0)
+
+	usage, err := coord.db.ListUsage(time.Now().Add(-time.Hour))
+	if err != nil || len(usage) != 1 {
+		t.Fatalf("Expected one usage record, got %v, %v", usage, err)
+	}
+	if u := usage[0]; u.Cost != 3 || u.Project != "/src/app" || u.Agent != "claude" {
+		t.Errorf("Unexpected usage %+v", u)
+	}
+}
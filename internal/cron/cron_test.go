@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * * someday",
+		"@fortnightly",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Expected %q refused", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// A Friday
+	from := time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 16, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 16, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"30 10 * * fri", time.Date(2026, 10, 23, 10, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are set
+		{"0 0 20 * mon", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Expected February 30th never to come, got %s", next)
+	}
+}
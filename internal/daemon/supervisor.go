@@ -0,0 +1,553 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/digest"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/notify"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/telemetry"
+)
+
+// defaultInterval paces the supervisor when daemon.interval isn't set
+const defaultInterval = 15 * time.Second
+
+// telemetryTimeout bounds sending the telemetry spool from the daemon
+const telemetryTimeout = 10 * time.Second
+
+// Supervisor is the loop run by gforge daemon: it keeps goblin statuses in
+// step with their tmux sessions, records the commits goblins make, sends
+// queued tasks to goblins that have finished the one they were on, spawns
+// scheduled runs, watches for goblins changing the same files, emails
+// the activity digest and archives goblins left idle
+type Supervisor struct {
+	// SpawnScheduled spawns the goblin named name for a schedule's run;
+	// without it schedules don't run
+	SpawnScheduled func(s *storage.Schedule, name string) (*coordinator.Goblin, error)
+
+	// SendDigest emails the activity digest for [since, until), reporting
+	// whether there was anything to send; without it digests aren't sent
+	SendDigest func(since, until time.Time) (bool, error)
+
+	coord       *coordinator.Coordinator
+	cfg         *config.Config
+	log         *logging.Logger
+	reloader    *config.Reloader
+	lastCleanup time.Time
+	lastOverlap time.Time
+	lastMerge   time.Time
+	nextDigest  time.Time
+	digestAt    string // The digest.schedule and hour nextDigest was set for
+	overlapping map[string]bool
+	missing     map[string]*coordinator.Goblin // Exited last pass, by ID
+	deferred    map[string]bool                // Schedules waiting for working hours
+	panes       map[string]pane
+}
+
+// pane is what the supervisor last saw in the pane of a goblin with
+// queued tasks
+type pane struct {
+	content string
+	since   time.Time // When the content last changed
+}
+
+// FedTask is a queued task the supervisor sent
+type FedTask struct {
+	Goblin *coordinator.Goblin
+	Task   string
+}
+
+// NewCommits are commits a goblin added to its branch since the last pass
+type NewCommits struct {
+	Goblin *coordinator.Goblin
+	Count  int
+}
+
+// ScheduledRun is a schedule that came due
+type ScheduledRun struct {
+	Schedule string
+	Goblin   string // Spawned, or when Skipped still running from the last run
+	Skipped  bool
+	Held     string // Why the run was deferred or skipped instead, such as a freeze
+	Deferred bool   // Held until working hours, staying due
+	Err      error  // Why the spawn failed
+}
+
+// Report is what one pass of the supervisor changed
+type Report struct {
+	Exited    []*coordinator.Goblin // Marked dead after their session ended
+	Committed []NewCommits          // Goblins whose branch gained commits
+	Fed       []FedTask             // Queued tasks sent to goblins that went quiet
+	Scheduled []ScheduledRun        // Schedules that came due
+	Overlaps  []coordinator.Overlap // Pairs that started changing the same files
+	Merged    []*coordinator.Goblin // Whose pull request was merged
+	Digested  bool                  // The scheduled digest was sent
+	Cleaned   []*coordinator.Goblin // Archived after auto_cleanup_days idle
+}
+
+// NewSupervisor creates a supervisor for the goblins managed by coord
+func NewSupervisor(coord *coordinator.Coordinator, cfg *config.Config, log *logging.Logger) *Supervisor {
+	return &Supervisor{coord: coord, cfg: cfg, log: log, panes: make(map[string]pane), deferred: make(map[string]bool)}
+}
+
+// ReloadConfig has the supervisor re-read the config file before each
+// pass when it changes, so intervals and cleanup age apply live
+func (s *Supervisor) ReloadConfig() {
+	s.reloader = config.NewReloader(s.cfg)
+}
+
+// Run makes a pass every daemon.interval until ctx is cancelled. Between
+// passes it sends the telemetry spool when it is due, so commands don't
+// wait on the network as they exit.
+func (s *Supervisor) Run(ctx context.Context) error {
+	for {
+		s.reload()
+		s.Tick(time.Now())
+		s.sendTelemetry(ctx)
+
+		interval := s.cfg.Daemon.Interval
+		if interval <= 0 {
+			interval = defaultInterval
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Tick marks goblins whose session ended as dead (notifying of them with
+// notifications.on_failure once the next pass finds the session still
+// gone), records the commits running goblins made,
+// sends queued tasks to goblins that went quiet and spawns the schedules
+// that came due. Each daemon.overlap_interval it records goblins that
+// started changing the same files, each daemon.merge_interval it records
+// pull requests that were merged, at each digest.schedule time it sends
+// the digest, and each daemon.cleanup_interval it
+// archives goblins idle for general.auto_cleanup_days (0 turns cleanup
+// off).
+func (s *Supervisor) Tick(now time.Time) Report {
+	var report Report
+
+	exited, err := s.coord.Reconcile()
+	if err != nil {
+		s.warn("Failed to check goblin sessions", err)
+	} else {
+		s.notifyExited(exited)
+	}
+	report.Exited = exited
+
+	report.Committed = s.recordCommits()
+	report.Fed = s.feedTasks(now)
+	report.Scheduled = s.runSchedules(now)
+	report.Overlaps = s.checkOverlaps(now)
+	report.Merged = s.checkMerged(now)
+	report.Digested = s.sendDigest(now)
+
+	days := s.cfg.General.AutoCleanupDays
+	if days <= 0 || now.Sub(s.lastCleanup) < s.cfg.Daemon.CleanupInterval {
+		return report
+	}
+	s.lastCleanup = now
+
+	cleaned, err := s.coord.Cleanup(time.Duration(days) * 24 * time.Hour)
+	if err != nil {
+		s.warn("Failed to clean up idle goblins", err)
+	}
+	report.Cleaned = cleaned
+	for _, g := range cleaned {
+		if s.log != nil {
+			s.log.Info("Archived idle goblin",
+				logging.String("name", g.Name),
+				logging.Int("days", days))
+		}
+	}
+	return report
+}
+
+// notifyExited sends a failure notification for goblins whose session
+// ended, unless gforge notify watch caught it first. Once a goblin is
+// marked dead the watcher no longer looks at it, so without this a
+// failure the daemon noticed first would go untold. Goblins that exited
+// this pass wait for the next, which must find their session still gone,
+// so a listing tmux got wrong once doesn't page anyone.
+func (s *Supervisor) notifyExited(exited []*coordinator.Goblin) {
+	pending := s.missing
+	s.missing = make(map[string]*coordinator.Goblin, len(exited))
+	for _, g := range exited {
+		s.missing[g.ID] = g
+	}
+	if !s.cfg.Notifications.OnFailure {
+		return
+	}
+
+	for _, g := range pending {
+		alive, err := s.coord.SessionAlive(g)
+		if err != nil {
+			s.warn("Failed to check the session of "+g.Name, err)
+			s.missing[g.ID] = g
+			continue
+		}
+		if alive {
+			continue
+		}
+
+		// Events since the goblin was last started belong to this run
+		told, err := s.coord.EventRecorded(g, string(notify.EventFailure), g.UpdatedAt)
+		if err != nil {
+			s.warn("Failed to read events of "+g.Name, err)
+			continue
+		}
+		if told {
+			continue
+		}
+
+		event := notify.Event{Type: notify.EventFailure, Goblin: g.Name, Message: "tmux session ended unexpectedly"}
+		if err := s.coord.RecordEvent(g.ID, string(event.Type), event.Message); err != nil {
+			s.warn("Failed to record event", err)
+		}
+		if err := notify.Send(s.cfg, event); err != nil {
+			s.warn("Failed to send notification for "+g.Name, err)
+		}
+	}
+}
+
+// recordCommits records the commits running goblins added to their
+// branches, which feeds commit webhooks
+func (s *Supervisor) recordCommits() []NewCommits {
+	goblins, err := s.coord.List()
+	if err != nil {
+		s.warn("Failed to list goblins", err)
+		return nil
+	}
+	var committed []NewCommits
+	for _, g := range goblins {
+		if g.Status != "running" {
+			continue
+		}
+		n, err := s.coord.RecordCommits(g)
+		if err != nil {
+			s.warn("Failed to record commits of "+g.Name, err)
+			continue
+		}
+		if n > 0 {
+			committed = append(committed, NewCommits{Goblin: g, Count: n})
+		}
+	}
+	return committed
+}
+
+// feedTasks sends each running goblin with queued tasks the next one once
+// its pane has gone unchanged for notifications.idle_timeout, the sign
+// its agent has finished the task it was on. Agents waiting at an
+// approval prompt are left to it.
+func (s *Supervisor) feedTasks(now time.Time) []FedTask {
+	queued, err := s.coord.QueuedTasks()
+	if err != nil {
+		s.warn("Failed to read task queues", err)
+		return nil
+	}
+	waiting := make(map[string]bool)
+	for _, q := range queued {
+		waiting[q.GoblinID] = true
+	}
+	for id := range s.panes {
+		if !waiting[id] {
+			delete(s.panes, id)
+		}
+	}
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	goblins, err := s.coord.List()
+	if err != nil {
+		s.warn("Failed to list goblins", err)
+		return nil
+	}
+	var fed []FedTask
+	for _, g := range goblins {
+		if !waiting[g.ID] || g.Status != "running" {
+			continue
+		}
+		lines, err := s.coord.Output(g, 200)
+		if err != nil || !s.quiet(g.ID, strings.Join(lines, "\n"), now) {
+			continue
+		}
+
+		task, err := s.coord.SendNextTask(g.ID)
+		if err != nil {
+			s.warn("Failed to send queued task to "+g.Name, err)
+			continue
+		}
+		if task == nil {
+			continue
+		}
+		// The next task waits for the pane to settle again
+		delete(s.panes, g.ID)
+		fed = append(fed, FedTask{Goblin: g, Task: task.Task})
+		if s.log != nil {
+			s.log.Info("Sent queued task",
+				logging.String("goblin", g.Name),
+				logging.String("task", task.Task))
+		}
+	}
+	return fed
+}
+
+// quiet records content as what a goblin's pane shows at now and reports
+// whether it has been unchanged for notifications.idle_timeout without
+// an approval prompt showing
+func (s *Supervisor) quiet(id, content string, now time.Time) bool {
+	p, ok := s.panes[id]
+	if !ok || p.content != content {
+		s.panes[id] = pane{content: content, since: now}
+		return false
+	}
+	return now.Sub(p.since) >= s.cfg.Notifications.IdleTimeout && notify.ApprovalPrompt(content) == ""
+}
+
+// runSchedules spawns a goblin for each schedule that came due, after
+// taking up the schedules in the config file. A run is skipped while the
+// goblin from the last one is still at work or a release freeze is on,
+// and deferred outside working hours. A failed spawn waits for the next
+// run rather than being retried.
+func (s *Supervisor) runSchedules(now time.Time) []ScheduledRun {
+	if s.SpawnScheduled == nil {
+		return nil
+	}
+	if err := s.coord.SyncSchedules(s.cfg.Schedules, now); err != nil {
+		s.warn("Failed to load schedules from config", err)
+	}
+	due, err := s.coord.DueSchedules(now)
+	if err != nil {
+		s.warn("Failed to read schedules", err)
+		return nil
+	}
+
+	var runs []ScheduledRun
+	for _, sched := range due {
+		busy, err := s.coord.StillRunning(sched)
+		if err != nil {
+			s.warn("Failed to check the last run of "+sched.Name, err)
+			continue
+		}
+
+		run := ScheduledRun{Schedule: sched.Name}
+		var spawned *coordinator.Goblin
+		if busy != nil {
+			run.Goblin, run.Skipped = busy.Name, true
+		} else if run.Held, run.Deferred, err = s.coord.HoldSchedule(sched, now); err != nil {
+			s.warn("Failed to check whether "+sched.Name+" may run", err)
+			continue
+		} else if run.Deferred {
+			// Logged once; the schedule stays due until the window opens
+			if !s.deferred[sched.Name] && s.log != nil {
+				s.log.Info("Deferring scheduled run",
+					logging.String("schedule", sched.Name),
+					logging.String("reason", run.Held))
+			}
+			s.deferred[sched.Name] = true
+			runs = append(runs, run)
+			continue
+		} else if run.Held != "" {
+			if s.log != nil {
+				s.log.Info("Skipping scheduled run",
+					logging.String("schedule", sched.Name),
+					logging.String("reason", run.Held))
+			}
+		} else {
+			delete(s.deferred, sched.Name)
+			spawned, run.Err = s.SpawnScheduled(sched, coordinator.ScheduledName(sched, now))
+			if run.Err != nil {
+				s.warn("Failed to spawn scheduled goblin for "+sched.Name, run.Err)
+			} else {
+				run.Goblin = spawned.Name
+			}
+		}
+
+		if err := s.coord.ScheduleRan(sched, spawned, now); err != nil {
+			s.warn("Failed to record the run of "+sched.Name, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// checkOverlaps compares goblins sharing a project once
+// daemon.overlap_interval has passed and returns the newly overlapping
+func (s *Supervisor) checkOverlaps(now time.Time) []coordinator.Overlap {
+	interval := s.cfg.Daemon.OverlapInterval
+	if interval <= 0 || now.Sub(s.lastOverlap) < interval {
+		return nil
+	}
+	s.lastOverlap = now
+
+	goblins, err := s.coord.List()
+	if err != nil {
+		s.warn("Failed to compare goblin changes", err)
+		return nil
+	}
+	fresh, current := s.coord.RecordOverlaps(s.coord.Overlaps(goblins), s.overlapping)
+	s.overlapping = current
+	for _, o := range fresh {
+		if s.log != nil {
+			s.log.Warn("Predicted merge conflict", logging.String("overlap", o.String()))
+		}
+	}
+	return fresh
+}
+
+// checkMerged looks up the goblins' pull requests once
+// daemon.merge_interval has passed, returning those newly merged
+func (s *Supervisor) checkMerged(now time.Time) []*coordinator.Goblin {
+	interval := s.cfg.Daemon.MergeInterval
+	if interval <= 0 || now.Sub(s.lastMerge) < interval {
+		return nil
+	}
+	s.lastMerge = now
+
+	goblins, err := s.coord.List()
+	if err != nil {
+		s.warn("Failed to check pull requests", err)
+		return nil
+	}
+	merged, err := s.coord.CheckMerged(goblins)
+	if err != nil {
+		s.warn("Failed to check some pull requests", err)
+	}
+	for _, g := range merged {
+		if s.log != nil {
+			s.log.Info("Pull request merged", logging.String("name", g.Name))
+		}
+	}
+	return merged
+}
+
+// reload picks up config file changes; a file that fails to load leaves
+// the running settings in place
+func (s *Supervisor) reload() {
+	if s.reloader == nil {
+		return
+	}
+	changes, err := s.reloader.Check()
+	if err != nil {
+		s.warn("Failed to reload config; keeping the running settings", err)
+		return
+	}
+	if changes != nil {
+		s.coord.ConfigReloaded(changes)
+	}
+}
+
+// sendDigest sends the digest once digest.schedule's next time has
+// passed, covering the period that ends there. The first pass only sets
+// that time, so restarting the daemon doesn't send a digest again.
+func (s *Supervisor) sendDigest(now time.Time) bool {
+	dc := s.cfg.Digest
+	if !dc.Enabled || s.SendDigest == nil {
+		s.nextDigest = time.Time{}
+		return false
+	}
+	period, err := digest.Period(dc.Schedule)
+	if err != nil {
+		s.warn("Failed to schedule digest", err)
+		return false
+	}
+
+	at := fmt.Sprintf("%s@%d", dc.Schedule, dc.Hour)
+	if s.nextDigest.IsZero() || s.digestAt != at {
+		s.nextDigest, _ = digest.Next(dc.Schedule, dc.Hour, now)
+		s.digestAt = at
+		return false
+	}
+	if now.Before(s.nextDigest) {
+		return false
+	}
+
+	due := s.nextDigest
+	s.nextDigest, _ = digest.Next(dc.Schedule, dc.Hour, now)
+	sent, err := s.SendDigest(due.Add(-period), due)
+	if err != nil {
+		s.warn("Failed to send digest", err)
+		return false
+	}
+	if sent && s.log != nil {
+		s.log.Info("Sent digest", logging.String("to", strings.Join(dc.To, ", ")))
+	}
+	return sent
+}
+
+// sendTelemetry sends spooled telemetry events once
+// telemetry.send_interval has passed since the last send
+func (s *Supervisor) sendTelemetry(ctx context.Context) {
+	if s.cfg.Telemetry.Endpoint == "" {
+		return
+	}
+	t, err := telemetry.Open(s.cfg.TelemetryDir)
+	if err != nil || !t.SendDue(s.cfg.Telemetry.SendInterval) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, telemetryTimeout)
+	defer cancel()
+	if _, err := t.Send(ctx, s.cfg.Telemetry.Endpoint); err != nil && s.log != nil {
+		s.log.Debug("Failed to send telemetry", logging.Err(err))
+	}
+}
+
+func (s *Supervisor) warn(msg string, err error) {
+	if s.log != nil {
+		s.log.Warn(msg, logging.Err(err))
+	}
+}
+
+// AcquirePIDFile records this process in path so only one supervisor runs
+// at a time. A file left by a process that has exited is taken over. The
+// returned function removes the file.
+func AcquirePIDFile(path string) (func(), error) {
+	pid, err := runningPID(path)
+	if err != nil {
+		return nil, err
+	}
+	if pid > 0 && pid != os.Getpid() {
+		return nil, fmt.Errorf("gforge daemon is already running (pid %d)", pid)
+	}
+
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return func() { os.Remove(path) }, nil
+}
+
+// Running reports whether a gforge daemon holds the PID file at path
+func Running(path string) bool {
+	pid, err := runningPID(path)
+	return err == nil && pid > 0
+}
+
+// runningPID returns the live process recorded in path, 0 when there is
+// none
+func runningPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	if !coordinator.ProcessAlive(pid) {
+		return 0, nil
+	}
+	return pid, nil
+}
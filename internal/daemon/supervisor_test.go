@@ -0,0 +1,376 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/telemetry"
+)
+
+func TestTick(t *testing.T) {
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	db.RestoreGoblin(&storage.Goblin{ID: "id-gone", Name: "gone", Agent: "claude", Status: "running",
+		ProjectPath: dir, WorktreePath: dir, TmuxSession: "gforge-no-such-session", CreatedAt: now, UpdatedAt: now})
+
+	cfg := &config.Config{
+		WorktreeBase: filepath.Join(dir, "worktrees"),
+		Tmux:         config.TmuxConfig{SocketName: "gforge-test-daemon"},
+		Daemon:       config.DaemonConfig{CleanupInterval: time.Hour},
+	}
+	s := NewSupervisor(coordinator.New(db, cfg, nil), cfg, nil)
+
+	report := s.Tick(now)
+	if len(report.Exited) != 1 || report.Exited[0].Name != "gone" {
+		t.Errorf("Expected gone reported as exited, got %v", report.Exited)
+	}
+	if g, _ := db.GetGoblin("gone"); g.Status != coordinator.StatusDead {
+		t.Errorf("Expected gone stored as dead, got %s", g.Status)
+	}
+
+	// auto_cleanup_days 0 leaves cleanup off
+	if !s.lastCleanup.IsZero() {
+		t.Error("Expected no cleanup with auto_cleanup_days 0")
+	}
+	cfg.General.AutoCleanupDays = 7
+	s.Tick(now)
+	if !s.lastCleanup.Equal(now) {
+		t.Error("Expected a cleanup once auto_cleanup_days is set")
+	}
+	s.Tick(now.Add(time.Minute))
+	if !s.lastCleanup.Equal(now) {
+		t.Error("Expected no second cleanup within cleanup_interval")
+	}
+
+	// overlap_interval 0 leaves the overlap check off
+	if !s.lastOverlap.IsZero() {
+		t.Error("Expected no overlap check with overlap_interval 0")
+	}
+	cfg.Daemon.OverlapInterval = 5 * time.Minute
+	s.Tick(now)
+	s.Tick(now.Add(time.Minute))
+	if !s.lastOverlap.Equal(now) {
+		t.Error("Expected one overlap check within overlap_interval")
+	}
+}
+
+func TestNotifyExited(t *testing.T) {
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Now().Add(-time.Hour)
+	for _, name := range []string{"gone", "told"} {
+		db.RestoreGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "running",
+			ProjectPath: dir, WorktreePath: dir, TmuxSession: "gforge-no-such-" + name, CreatedAt: started, UpdatedAt: started})
+	}
+	// gforge notify watch got to told first
+	db.RecordEvent("id-told", "told", "failure", "tmux session ended unexpectedly")
+
+	cfg := &config.Config{
+		WorktreeBase:  filepath.Join(dir, "worktrees"),
+		Tmux:          config.TmuxConfig{SocketName: "gforge-test-daemon"},
+		Notifications: config.NotificationsConfig{OnFailure: true},
+	}
+	s := NewSupervisor(coordinator.New(db, cfg, nil), cfg, nil)
+	failures := func() map[string]int {
+		counts := make(map[string]int)
+		events, _ := db.ListEvents(started)
+		for _, e := range events {
+			if e.Type == "failure" {
+				counts[e.GoblinName]++
+			}
+		}
+		return counts
+	}
+
+	if report := s.Tick(time.Now()); len(report.Exited) != 2 {
+		t.Fatalf("Expected both goblins exited, got %v", report.Exited)
+	}
+	// Not told until the next pass finds the sessions still gone
+	if got := failures(); got["gone"] != 0 {
+		t.Errorf("Expected no failure for gone after one pass, got %v", got)
+	}
+
+	s.Tick(time.Now())
+	if got := failures(); got["gone"] != 1 || got["told"] != 1 {
+		t.Errorf("Expected one failure each, the daemon's for gone only, got %v", got)
+	}
+	s.Tick(time.Now())
+	if got := failures(); got["gone"] != 1 {
+		t.Errorf("Expected gone told once, got %v", got)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	cfg := &config.Config{Notifications: config.NotificationsConfig{IdleTimeout: 30 * time.Second}}
+	s := NewSupervisor(nil, cfg, nil)
+	now := time.Now()
+
+	if s.quiet("id-1", "working...", now) {
+		t.Error("Expected a pane seen once not to count as quiet")
+	}
+	if s.quiet("id-1", "working...", now.Add(20*time.Second)) {
+		t.Error("Expected no task sent before idle_timeout")
+	}
+	if s.quiet("id-1", "still working...", now.Add(40*time.Second)) {
+		t.Error("Expected a change to restart the wait")
+	}
+	if !s.quiet("id-1", "still working...", now.Add(70*time.Second)) {
+		t.Error("Expected the pane quiet after idle_timeout unchanged")
+	}
+
+	// An agent asking for approval isn't done
+	prompt := "Do you want to proceed? [y/n]"
+	s.quiet("id-2", prompt, now)
+	if s.quiet("id-2", prompt, now.Add(time.Minute)) {
+		t.Error("Expected an approval prompt not to count as quiet")
+	}
+}
+
+func TestRunSchedules(t *testing.T) {
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{
+		WorktreeBase: filepath.Join(dir, "worktrees"),
+		Tmux:         config.TmuxConfig{SocketName: "gforge-test-daemon"},
+		Schedules: map[string]config.ScheduleConfig{
+			"audit": {Cron: "@hourly", Project: dir, Task: "Audit"},
+		},
+	}
+	coord := coordinator.New(db, cfg, nil)
+	s := NewSupervisor(coord, cfg, nil)
+
+	now := time.Date(2026, 10, 16, 10, 30, 0, 0, time.Local)
+	if report := s.Tick(now); len(report.Scheduled) != 0 {
+		t.Errorf("Expected no schedules run without a spawner, got %v", report.Scheduled)
+	}
+
+	var spawned []string
+	s.SpawnScheduled = func(sched *storage.Schedule, name string) (*coordinator.Goblin, error) {
+		spawned = append(spawned, name)
+		db.RestoreGoblin(&storage.Goblin{ID: "id-" + name, Name: name, Agent: "claude", Status: "running",
+			ProjectPath: sched.ProjectPath, CreatedAt: now, UpdatedAt: now})
+		return coord.Get(name)
+	}
+	if report := s.Tick(now); len(report.Scheduled) != 0 {
+		t.Errorf("Expected audit not due before 11, got %v", report.Scheduled)
+	}
+
+	at := now.Add(30 * time.Minute)
+	report := s.Tick(at)
+	if len(report.Scheduled) != 1 || report.Scheduled[0].Goblin != "audit-1016-1100" || report.Scheduled[0].Skipped {
+		t.Fatalf("Expected audit spawned at 11, got %+v", report.Scheduled)
+	}
+
+	// The next hour's run waits for the goblin still at work
+	report = s.Tick(at.Add(time.Hour))
+	if len(report.Scheduled) != 1 || !report.Scheduled[0].Skipped || report.Scheduled[0].Goblin != "audit-1016-1100" {
+		t.Errorf("Expected the 12:00 run skipped, got %+v", report.Scheduled)
+	}
+	if len(spawned) != 1 {
+		t.Errorf("Expected one goblin spawned, got %v", spawned)
+	}
+	db.UpdateGoblinStatus("id-audit-1016-1100", "completed")
+
+	// Outside working hours the run waits, staying due
+	cfg.WorkingHours = config.WorkingHoursConfig{Enabled: true, Days: []string{"sat"}, Start: "09:00", End: "17:00"}
+	for i := 0; i < 2; i++ {
+		report = s.Tick(at.Add(2 * time.Hour))
+		if len(report.Scheduled) != 1 || !report.Scheduled[0].Deferred || !strings.Contains(report.Scheduled[0].Held, "outside working hours") {
+			t.Fatalf("Expected the run deferred, got %+v", report.Scheduled)
+		}
+	}
+
+	if len(spawned) != 1 {
+		t.Errorf("Expected no goblin spawned while held, got %v", spawned)
+	}
+
+	// A release freeze only holds pushes, so the run goes ahead
+	cfg.WorkingHours.Enabled = false
+	ics := filepath.Join(dir, "freeze.ics")
+	os.WriteFile(ics, []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Release freeze\r\nDTSTART:"+
+		time.Now().Add(-time.Hour).UTC().Format("20060102T150405Z")+"\r\nDTEND:"+
+		time.Now().Add(time.Hour).UTC().Format("20060102T150405Z")+"\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"), 0644)
+	cfg.Scheduler = config.SchedulerConfig{FreezeCalendar: ics, FreezeKeywords: []string{"freeze"}}
+	report = s.Tick(at.Add(2 * time.Hour))
+	if len(report.Scheduled) != 1 || report.Scheduled[0].Held != "" || len(spawned) != 2 {
+		t.Fatalf("Expected the run spawned during a freeze, got %+v", report.Scheduled)
+	}
+}
+
+func TestAcquirePIDFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+
+	release, err := AcquirePIDFile(path)
+	if err != nil {
+		t.Fatalf("AcquirePIDFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("Expected our pid in the file, got %q", data)
+	}
+	release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected release to remove the file")
+	}
+
+	if Running(path) {
+		t.Error("Expected no daemon running without a pid file")
+	}
+
+	// A live process holds the file; pid 1 is always running
+	os.WriteFile(path, []byte("1\n"), 0644)
+	if _, err := AcquirePIDFile(path); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("Expected a running daemon to block, got %v", err)
+	}
+	if !Running(path) {
+		t.Error("Expected the daemon reported running")
+	}
+
+	// One that exited is taken over
+	os.WriteFile(path, []byte("999999999\n"), 0644)
+	if Running(path) {
+		t.Error("Expected an exited daemon not reported running")
+	}
+	release, err = AcquirePIDFile(path)
+	if err != nil {
+		t.Fatalf("Expected a stale pid file taken over, got %v", err)
+	}
+	release()
+}
+
+func TestSendTelemetry(t *testing.T) {
+	var received []telemetry.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	tel, _ := telemetry.Open(dir)
+	tel.SetEnabled(true)
+	tel.Record(telemetry.Event{Command: "spawn"})
+
+	cfg := &config.Config{TelemetryDir: dir}
+	cfg.Telemetry.SendInterval = time.Hour
+	s := NewSupervisor(nil, cfg, nil)
+
+	// Nothing leaves without an endpoint
+	s.sendTelemetry(context.Background())
+	if pending, _ := tel.Pending(); len(pending) != 1 {
+		t.Fatalf("Expected the event kept without an endpoint, got %v", pending)
+	}
+
+	cfg.Telemetry.Endpoint = server.URL
+	s.sendTelemetry(context.Background())
+	if len(received) != 1 || received[0].Command != "spawn" {
+		t.Errorf("Expected the spooled event sent, got %+v", received)
+	}
+	if pending, _ := tel.Pending(); len(pending) != 0 {
+		t.Errorf("Expected the spool emptied, got %v", pending)
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Digest.Schedule = "daily"
+	cfg.Digest.Hour = 8
+	s := NewSupervisor(nil, cfg, nil)
+
+	var windows [][2]time.Time
+	s.SendDigest = func(since, until time.Time) (bool, error) {
+		windows = append(windows, [2]time.Time{since, until})
+		return true, nil
+	}
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	// Off until enabled
+	if s.sendDigest(at(9, 0)) {
+		t.Fatal("Expected no digest with digests disabled")
+	}
+
+	// The first pass only schedules, so a restart doesn't send again
+	cfg.Digest.Enabled = true
+	if s.sendDigest(at(7, 0)) || s.sendDigest(at(7, 59)) {
+		t.Fatal("Expected no digest before 08:00")
+	}
+	if !s.sendDigest(at(8, 0)) {
+		t.Fatal("Expected the digest sent at 08:00")
+	}
+	if len(windows) != 1 || !windows[0][0].Equal(at(8, 0).AddDate(0, 0, -1)) || !windows[0][1].Equal(at(8, 0)) {
+		t.Errorf("Expected the day up to 08:00, got %v", windows)
+	}
+	if s.sendDigest(at(8, 1)) || len(windows) != 1 {
+		t.Errorf("Expected one digest a day, got %v", windows)
+	}
+
+	// A new hour is scheduled afresh
+	cfg.Digest.Hour = 9
+	if s.sendDigest(at(8, 30)) || !s.sendDigest(at(9, 0)) {
+		t.Error("Expected the digest moved to 09:00")
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dir)
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte("general:\n  max_concurrent_agents: 4\n"), 0644)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	db, err := storage.New(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	s := NewSupervisor(coordinator.New(db, cfg, nil), cfg, nil)
+	s.ReloadConfig()
+
+	os.WriteFile(path, []byte("general:\n  max_concurrent_agents: 8\n  agent_scan_ttl: 1m\ntmux:\n  socket_name: moved\n"), 0644)
+	os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	s.reload()
+
+	if cfg.General.MaxConcurrentAgents != 8 || cfg.General.AgentScanTTL != time.Minute {
+		t.Errorf("Expected the live settings applied, got %d, %s", cfg.General.MaxConcurrentAgents, cfg.General.AgentScanTTL)
+	}
+	if cfg.Tmux.SocketName == "moved" {
+		t.Error("Expected the socket kept until a restart")
+	}
+	events, _ := db.ListEvents(time.Now().Add(-time.Hour))
+	if len(events) != 1 || events[0].Type != coordinator.EventConfigReloaded ||
+		!strings.Contains(events[0].Detail, "restart needed for tmux.socket_name") {
+		t.Errorf("Expected a config_reloaded event naming the restart, got %+v", events)
+	}
+}
@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// UnitName is the systemd user unit installed by gforge daemon install
+const UnitName = "gforge.service"
+
+// unitTemplate keeps the unit "active" for the whole session so systemd
+// runs ExecStop, and with it gforge shutdown, when the user manager or the
+// machine goes down. Without Supervise it is a oneshot that does nothing
+// else; with it the unit runs gforge daemon. Paths are quoted, as they
+// may hold spaces.
+var unitTemplate = template.Must(template.New("unit").Funcs(template.FuncMap{"quote": unitQuote}).Parse(`[Unit]
+Description=Goblin Forge - {{if .Supervise}}supervise goblins and {{end}}stop goblins cleanly on shutdown
+Documentation=https://github.com/astoreyai/goblin-forge
+
+[Service]
+{{- if .Supervise}}
+Type=simple
+ExecStart={{quote .Exe}} daemon{{if .Config}} --config {{quote .Config}}{{end}}
+Restart=on-failure
+{{- else}}
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/true
+{{- end}}
+Environment={{quote (print "PATH=" .Path)}}
+ExecStop={{quote .Exe}} shutdown{{if .Config}} --config {{quote .Config}}{{end}}
+TimeoutStopSec=120
+
+[Install]
+WantedBy=default.target
+`))
+
+// unitQuote quotes a word for a unit file: in double quotes, with
+// backslashes and quotes escaped and % doubled so it isn't a specifier
+func unitQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s)
+	return `"` + s + `"`
+}
+
+// UnitOptions fill in the systemd unit template
+type UnitOptions struct {
+	Exe    string // Absolute path of the gforge binary
+	Config string // Optional config file passed to gforge shutdown
+	Path   string // PATH for git and tmux
+	// Supervise runs gforge daemon as the unit's process
+	Supervise bool
+}
+
+// Unit renders the systemd unit file
+func Unit(opts UnitOptions) (string, error) {
+	var buf bytes.Buffer
+	if err := unitTemplate.Execute(&buf, opts); err != nil {
+		return "", fmt.Errorf("failed to render unit: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// UnitPath returns where the user unit is installed
+func UnitPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "systemd", "user", UnitName), nil
+}
+
+// Install writes the unit and, when systemctl is available, enables and
+// starts it. It returns the unit path and whether systemd was updated.
+func Install(opts UnitOptions) (string, bool, error) {
+	unit, err := Unit(opts)
+	if err != nil {
+		return "", false, err
+	}
+
+	path, err := UnitPath()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to locate unit directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", false, fmt.Errorf("failed to create unit directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+		return "", false, fmt.Errorf("failed to write unit: %w", err)
+	}
+
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return path, false, nil
+	}
+	for _, args := range [][]string{
+		{"--user", "daemon-reload"},
+		{"--user", "enable", "--now", UnitName},
+	} {
+		if output, err := exec.Command("systemctl", args...).CombinedOutput(); err != nil {
+			return path, false, fmt.Errorf("systemctl %v failed: %s\n%s", args, err, string(output))
+		}
+	}
+
+	return path, true, nil
+}
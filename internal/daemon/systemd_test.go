@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnit(t *testing.T) {
+	unit, err := Unit(UnitOptions{Exe: "/usr/local/bin/gforge", Path: "/usr/bin:/bin"})
+	if err != nil {
+		t.Fatalf("Unit failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Type=oneshot",
+		"RemainAfterExit=yes",
+		`Environment="PATH=/usr/bin:/bin"`,
+		"ExecStop=\"/usr/local/bin/gforge\" shutdown\n",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Unit missing %q:\n%s", want, unit)
+		}
+	}
+
+	unit, _ = Unit(UnitOptions{Exe: "/bin/gforge", Config: "/etc/gforge.yaml"})
+	if !strings.Contains(unit, `ExecStop="/bin/gforge" shutdown --config "/etc/gforge.yaml"`) {
+		t.Errorf("Expected config flag in ExecStop:\n%s", unit)
+	}
+
+	// Spaces, quotes and specifiers are kept literal
+	unit, _ = Unit(UnitOptions{Exe: "/opt/my tools/gforge", Config: `/home/a"b/100%.yaml`})
+	if !strings.Contains(unit, `ExecStop="/opt/my tools/gforge" shutdown --config "/home/a\"b/100%%.yaml"`) {
+		t.Errorf("Expected quoted paths in ExecStop:\n%s", unit)
+	}
+}
+
+func TestUnitPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+	path, err := UnitPath()
+	if err != nil {
+		t.Fatalf("UnitPath failed: %v", err)
+	}
+	if path != filepath.Join("/tmp/xdg", "systemd", "user", UnitName) {
+		t.Errorf("Unexpected unit path: %s", path)
+	}
+}
+
+func TestUnitSupervise(t *testing.T) {
+	unit, err := Unit(UnitOptions{Exe: "/bin/gforge", Config: "/etc/gforge.yaml", Supervise: true})
+	if err != nil {
+		t.Fatalf("Unit failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Type=simple",
+		"ExecStart=\"/bin/gforge\" daemon --config \"/etc/gforge.yaml\"\n",
+		"Restart=on-failure",
+		"ExecStop=\"/bin/gforge\" shutdown --config \"/etc/gforge.yaml\"\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Unit missing %q:\n%s", want, unit)
+		}
+	}
+	if strings.Contains(unit, "RemainAfterExit") {
+		t.Errorf("Expected no oneshot settings when supervising:\n%s", unit)
+	}
+}
@@ -0,0 +1,220 @@
+// Package digest summarizes goblin activity and emails it on a schedule.
+package digest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/usage"
+)
+
+// Event types counted by the digest. Spawned, task, stopped, killed,
+// pr_opened and pr_merged come from the coordinator (the daemon notices
+// merges); the rest from watchers.
+const (
+	eventSpawned      = "spawned"
+	eventTask         = "task"
+	eventStopped      = "stopped"
+	eventKilled       = "killed"
+	eventTaskComplete = "task_complete"
+	eventFailure      = "failure"
+	eventApproval     = "approval"
+	eventPROpened     = "pr_opened"
+	eventPRMerged     = "pr_merged"
+)
+
+// GoblinActivity is one goblin's share of the digest
+type GoblinActivity struct {
+	Name      string
+	Agent     string
+	Tasks     int
+	Completed int
+	Failures  int
+	Summary   string // Latest output summary, when one was made in the window
+}
+
+// Digest summarizes activity over a time window
+type Digest struct {
+	Since     time.Time
+	Until     time.Time
+	Spawned   int
+	Tasks     int
+	Completed int
+	Approvals int
+	PRsOpened int
+	PRsMerged int
+	Stopped   int
+	Killed    int
+	Failures  []*storage.Event
+	Goblins   []GoblinActivity
+
+	// Costs is the estimated usage of each agent, highest cost first
+	Costs []usage.Row
+}
+
+// Build summarizes events that fall in [since, until)
+func Build(events []*storage.Event, since, until time.Time) *Digest {
+	d := &Digest{Since: since, Until: until}
+	byName := make(map[string]*GoblinActivity)
+
+	activity := func(name string) *GoblinActivity {
+		a, ok := byName[name]
+		if !ok {
+			a = &GoblinActivity{Name: name}
+			byName[name] = a
+		}
+		return a
+	}
+
+	for _, e := range events {
+		if e.CreatedAt.Before(since) || !e.CreatedAt.Before(until) {
+			continue
+		}
+
+		switch e.Type {
+		case eventSpawned:
+			d.Spawned++
+			activity(e.GoblinName).Agent = e.Detail
+		case eventTask:
+			d.Tasks++
+			activity(e.GoblinName).Tasks++
+		case eventTaskComplete:
+			d.Completed++
+			activity(e.GoblinName).Completed++
+		case eventFailure:
+			d.Failures = append(d.Failures, e)
+			activity(e.GoblinName).Failures++
+		case eventApproval:
+			d.Approvals++
+		case eventPROpened:
+			d.PRsOpened++
+		case eventPRMerged:
+			d.PRsMerged++
+		case eventStopped:
+			d.Stopped++
+		case eventKilled:
+			d.Killed++
+		}
+	}
+
+	for _, a := range byName {
+		d.Goblins = append(d.Goblins, *a)
+	}
+	sort.Slice(d.Goblins, func(i, j int) bool {
+		return d.Goblins[i].Name < d.Goblins[j].Name
+	})
+
+	return d
+}
+
+// AttachSummaries adds output summaries to the goblins they describe
+func (d *Digest) AttachSummaries(summaries []*storage.Summary) {
+	for _, s := range summaries {
+		for i := range d.Goblins {
+			if d.Goblins[i].Name == s.GoblinName {
+				d.Goblins[i].Summary = s.Summary
+			}
+		}
+	}
+}
+
+// AttachUsage totals the usage recorded in the digest's window by agent
+func (d *Digest) AttachUsage(records []*storage.Usage) {
+	var inWindow []*storage.Usage
+	for _, u := range records {
+		if !u.CreatedAt.Before(d.Since) && u.CreatedAt.Before(d.Until) {
+			inWindow = append(inWindow, u)
+		}
+	}
+	d.Costs, _ = usage.Aggregate(inWindow, usage.ByAgent)
+}
+
+// Empty reports whether nothing happened in the window
+func (d *Digest) Empty() bool {
+	return d.Spawned == 0 && d.Tasks == 0 && d.Completed == 0 && len(d.Failures) == 0 &&
+		d.Approvals == 0 && d.PRsOpened == 0 && d.PRsMerged == 0 && d.Stopped == 0 && d.Killed == 0 && len(d.Costs) == 0
+}
+
+// Subject returns an email subject line
+func (d *Digest) Subject() string {
+	subject := fmt.Sprintf("gforge digest: %d spawned, %d tasks, %d completed",
+		d.Spawned, d.Tasks, d.Completed)
+	if len(d.Failures) > 0 {
+		subject += fmt.Sprintf(", %d failed", len(d.Failures))
+	}
+	return subject
+}
+
+// Render writes the digest as plain text
+func (d *Digest) Render(w io.Writer) {
+	const stamp = "Jan 2 15:04"
+
+	fmt.Fprintf(w, "Goblin Forge activity, %s - %s\n\n",
+		d.Since.Local().Format(stamp), d.Until.Local().Format(stamp))
+
+	if d.Empty() {
+		fmt.Fprintln(w, "No activity.")
+		return
+	}
+
+	fmt.Fprintln(w, "Summary")
+	fmt.Fprintf(w, "  Goblins spawned:    %d\n", d.Spawned)
+	fmt.Fprintf(w, "  Tasks sent:         %d\n", d.Tasks)
+	fmt.Fprintf(w, "  Tasks completed:    %d\n", d.Completed)
+	fmt.Fprintf(w, "  Failures:           %d\n", len(d.Failures))
+	fmt.Fprintf(w, "  Approval requests:  %d\n", d.Approvals)
+	fmt.Fprintf(w, "  PRs opened/merged:  %d/%d\n", d.PRsOpened, d.PRsMerged)
+	fmt.Fprintf(w, "  Stopped/killed:     %d/%d\n", d.Stopped, d.Killed)
+	if len(d.Costs) > 0 {
+		fmt.Fprintf(w, "  Estimated cost:     $%.2f\n", usage.Total(d.Costs).Cost)
+	}
+
+	if len(d.Failures) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Failures")
+		for _, e := range d.Failures {
+			fmt.Fprintf(w, "  - %s (%s): %s\n", e.GoblinName, e.CreatedAt.Local().Format(stamp), e.Detail)
+		}
+	}
+
+	if len(d.Costs) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Costs (estimated)")
+		for _, r := range append(d.Costs, usage.Total(d.Costs)) {
+			key := r.Key
+			if key == "" {
+				key = "-"
+			}
+			fmt.Fprintf(w, "  %-28s %d requests, %d in / %d out tokens, $%.2f\n",
+				key, r.Requests, r.InputTokens, r.OutputTokens, r.Cost)
+		}
+	}
+
+	if len(d.Goblins) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Goblins")
+		for _, g := range d.Goblins {
+			name := g.Name
+			if g.Agent != "" {
+				name = fmt.Sprintf("%s (%s)", g.Name, g.Agent)
+			}
+			parts := []string{
+				fmt.Sprintf("%d tasks", g.Tasks),
+				fmt.Sprintf("%d completed", g.Completed),
+			}
+			if g.Failures > 0 {
+				parts = append(parts, fmt.Sprintf("%d failed", g.Failures))
+			}
+			fmt.Fprintf(w, "  %-28s %s\n", name, strings.Join(parts, ", "))
+			for _, line := range strings.Split(g.Summary, "\n") {
+				if strings.TrimSpace(line) != "" {
+					fmt.Fprintf(w, "      %s\n", strings.TrimSpace(line))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestBuild(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	at := func(h int) time.Time { return since.Add(time.Duration(h) * time.Hour) }
+
+	events := []*storage.Event{
+		{GoblinName: "coder", Type: "spawned", Detail: "claude", CreatedAt: at(1)},
+		{GoblinName: "coder", Type: "task", Detail: "fix bug", CreatedAt: at(2)},
+		{GoblinName: "coder", Type: "task_complete", CreatedAt: at(3)},
+		{GoblinName: "tester", Type: "task", Detail: "add tests", CreatedAt: at(4)},
+		{GoblinName: "tester", Type: "failure", Detail: "agent exited", CreatedAt: at(5)},
+		{GoblinName: "coder", Type: "pr_opened", CreatedAt: at(6)},
+		{GoblinName: "coder", Type: "pr_merged", CreatedAt: at(7)},
+		{GoblinName: "coder", Type: "killed", CreatedAt: at(8)},
+		{GoblinName: "late", Type: "spawned", CreatedAt: at(25)},
+	}
+
+	d := Build(events, since, until)
+
+	if d.Spawned != 1 || d.Tasks != 2 || d.Completed != 1 || len(d.Failures) != 1 {
+		t.Errorf("Unexpected counts: %+v", d)
+	}
+	if d.PRsOpened != 1 || d.PRsMerged != 1 || d.Killed != 1 {
+		t.Errorf("Unexpected PR/kill counts: %+v", d)
+	}
+	if len(d.Goblins) != 2 || d.Goblins[0].Name != "coder" || d.Goblins[0].Agent != "claude" {
+		t.Errorf("Unexpected goblins: %+v", d.Goblins)
+	}
+	if d.Empty() {
+		t.Error("Digest should not be empty")
+	}
+	if !strings.Contains(d.Subject(), "1 failed") {
+		t.Errorf("Subject should mention failures: %s", d.Subject())
+	}
+
+	var out strings.Builder
+	d.Render(&out)
+	for _, want := range []string{"Tasks sent:         2", "PRs opened/merged:  1/1", "tester", "agent exited", "coder (claude)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Render missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	d := Build(nil, time.Now().Add(-time.Hour), time.Now())
+	if !d.Empty() {
+		t.Error("Digest with no events should be empty")
+	}
+
+	var out strings.Builder
+	d.Render(&out)
+	if !strings.Contains(out.String(), "No activity.") {
+		t.Errorf("Empty digest should say so:\n%s", out.String())
+	}
+}
+
+func TestAttachSummaries(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	d := Build([]*storage.Event{
+		{GoblinName: "coder", Type: "task", Detail: "fix bug", CreatedAt: since.Add(time.Minute)},
+	}, since, time.Now())
+
+	d.AttachSummaries([]*storage.Summary{
+		{GoblinName: "coder", Summary: "- fixed the parser\n- tests pass"},
+		{GoblinName: "gone", Summary: "- not in this digest"},
+	})
+
+	if d.Goblins[0].Summary == "" {
+		t.Fatal("Expected the summary attached to coder")
+	}
+
+	var out strings.Builder
+	d.Render(&out)
+	if !strings.Contains(out.String(), "      - tests pass") || strings.Contains(out.String(), "not in this digest") {
+		t.Errorf("Unexpected summaries in render:\n%s", out.String())
+	}
+}
@@ -0,0 +1,57 @@
+package digest
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+// Send emails a digest over SMTP. The password falls back to
+// GFORGE_SMTP_PASSWORD so it can stay out of the config file.
+func Send(cfg config.DigestConfig, subject, body string) error {
+	if cfg.SMTP.Host == "" {
+		return fmt.Errorf("digest.smtp.host is not configured")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return fmt.Errorf("digest.from and digest.to must be configured")
+	}
+
+	port := cfg.SMTP.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := fmt.Sprintf("%s:%d", cfg.SMTP.Host, port)
+
+	var auth smtp.Auth
+	if cfg.SMTP.Username != "" {
+		password := cfg.SMTP.Password
+		if password == "" {
+			password = os.Getenv("GFORGE_SMTP_PASSWORD")
+		}
+		auth = smtp.PlainAuth("", cfg.SMTP.Username, password, cfg.SMTP.Host)
+	}
+
+	msg := buildMessage(cfg.From, cfg.To, subject, body, time.Now())
+	if err := smtp.SendMail(addr, auth, cfg.From, cfg.To, msg); err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	return nil
+}
+
+// buildMessage formats a plain text RFC 5322 message
+func buildMessage(from string, to []string, subject, body string, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
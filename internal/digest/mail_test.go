@@ -0,0 +1,38 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	msg := string(buildMessage("gforge@example.com", []string{"a@example.com", "b@example.com"},
+		"gforge digest", "line one\nline two\n", date))
+
+	for _, want := range []string{
+		"From: gforge@example.com\r\n",
+		"To: a@example.com, b@example.com\r\n",
+		"Subject: gforge digest\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nline one\r\nline two\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSendRequiresConfig(t *testing.T) {
+	if err := Send(config.DigestConfig{}, "s", "b"); err == nil {
+		t.Error("Expected error without SMTP host")
+	}
+
+	cfg := config.DigestConfig{SMTP: config.SMTPConfig{Host: "localhost"}}
+	if err := Send(cfg, "s", "b"); err == nil {
+		t.Error("Expected error without recipients")
+	}
+}
@@ -0,0 +1,125 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+// Period returns the window a schedule covers
+func Period(schedule string) (time.Duration, error) {
+	switch schedule {
+	case "hourly":
+		return time.Hour, nil
+	case "daily", "":
+		return 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown digest schedule: %s (use hourly or daily)", schedule)
+	}
+}
+
+// Next returns the next send time after now: the top of the next hour,
+// or hour:00 local time for daily digests
+func Next(schedule string, hour int, now time.Time) (time.Time, error) {
+	if _, err := Period(schedule); err != nil {
+		return time.Time{}, err
+	}
+
+	if schedule == "hourly" {
+		return now.Truncate(time.Hour).Add(time.Hour), nil
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next, nil
+}
+
+// Generate builds the digest for the window ending at until
+func Generate(db *storage.DB, since, until time.Time) (*Digest, error) {
+	events, err := db.ListEvents(since)
+	if err != nil {
+		return nil, err
+	}
+	d := Build(events, since, until)
+
+	summaries, err := db.ListSummaries(since)
+	if err != nil {
+		return nil, err
+	}
+	d.AttachSummaries(summaries)
+
+	records, err := db.ListUsage(since)
+	if err != nil {
+		return nil, err
+	}
+	d.AttachUsage(records)
+
+	return d, nil
+}
+
+// SendWindow emails the digest for [since, until), reporting whether it
+// was sent; a window with no activity is skipped
+func SendWindow(db *storage.DB, cfg config.DigestConfig, since, until time.Time) (bool, error) {
+	d, err := Generate(db, since, until)
+	if err != nil {
+		return false, fmt.Errorf("failed to build digest: %w", err)
+	}
+	if d.Empty() {
+		return false, nil
+	}
+
+	var body strings.Builder
+	d.Render(&body)
+	if err := Send(cfg, d.Subject(), body.String()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Run sends a digest at every scheduled time until ctx is cancelled.
+// Windows with no activity are skipped.
+func Run(ctx context.Context, db *storage.DB, cfg config.DigestConfig, log *logging.Logger) error {
+	period, err := Period(cfg.Schedule)
+	if err != nil {
+		return err
+	}
+
+	for {
+		next, err := Next(cfg.Schedule, cfg.Hour, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if log != nil {
+			log.Info("Next digest scheduled", logging.Time("at", next))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+
+		sent, err := SendWindow(db, cfg, next.Add(-period), next)
+		if err != nil {
+			if log != nil {
+				log.Error("Failed to send digest", err)
+			}
+			continue
+		}
+		if !sent {
+			continue
+		}
+
+		if log != nil {
+			log.Info("Sent digest", logging.String("to", strings.Join(cfg.To, ", ")))
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package digest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 25, 0, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		hour     int
+		expected time.Time
+	}{
+		{"hourly", 0, time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)},
+		{"daily", 18, time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)},
+		{"daily", 8, time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)},
+		{"daily", 14, time.Date(2026, 3, 11, 14, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tests {
+		got, err := Next(tc.schedule, tc.hour, now)
+		if err != nil {
+			t.Errorf("Next(%s, %d) failed: %v", tc.schedule, tc.hour, err)
+			continue
+		}
+		if !got.Equal(tc.expected) {
+			t.Errorf("Next(%s, %d) = %s, want %s", tc.schedule, tc.hour, got, tc.expected)
+		}
+	}
+
+	if _, err := Next("weekly", 0, now); err == nil {
+		t.Error("Expected error for unknown schedule")
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	if p, _ := Period("hourly"); p != time.Hour {
+		t.Errorf("Expected 1h for hourly, got %s", p)
+	}
+	if p, _ := Period("daily"); p != 24*time.Hour {
+		t.Errorf("Expected 24h for daily, got %s", p)
+	}
+}
@@ -0,0 +1,103 @@
+// Package errs defines the failure causes scripts can branch on. Each
+// cause has its own exit code on the command line and its own code in API
+// error bodies; wrap the sentinels with %w so errors.Is still finds them.
+package errs
+
+import "errors"
+
+// Failure causes
+var (
+	ErrGoblinNotFound    = errors.New("goblin not found")
+	ErrWorktreeExists    = errors.New("worktree path already exists")
+	ErrAgentNotInstalled = errors.New("agent not installed")
+	ErrTmuxUnavailable   = errors.New("tmux not available")
+	ErrProtectedBranch   = errors.New("protected branch")
+	ErrSecretsFound      = errors.New("secrets found")
+	ErrDiffTooLarge      = errors.New("diff too large")
+	ErrChecksFailed      = errors.New("checks failed")
+	ErrFrozen            = errors.New("release freeze")
+	ErrNotAuthenticated  = errors.New("GitHub CLI not authenticated")
+	ErrPathLocked        = errors.New("path locked")
+)
+
+// Exit codes. 1 covers every failure without a more specific cause and 2
+// is left for usage errors.
+const (
+	ExitFailure           = 1
+	ExitGoblinNotFound    = 3
+	ExitWorktreeExists    = 4
+	ExitAgentNotInstalled = 5
+	ExitTmuxUnavailable   = 6
+	ExitProtectedBranch   = 7
+	ExitSecretsFound      = 8
+	ExitDiffTooLarge      = 9
+	ExitChecksFailed      = 10
+	ExitFrozen            = 11
+	ExitNotAuthenticated  = 12
+	ExitPathLocked        = 13
+)
+
+// cause ties a sentinel to its exit code and API error code
+type cause struct {
+	err  error
+	exit int
+	code string
+}
+
+var causes = []cause{
+	{ErrGoblinNotFound, ExitGoblinNotFound, "goblin_not_found"},
+	{ErrWorktreeExists, ExitWorktreeExists, "worktree_exists"},
+	{ErrAgentNotInstalled, ExitAgentNotInstalled, "agent_not_installed"},
+	{ErrTmuxUnavailable, ExitTmuxUnavailable, "tmux_unavailable"},
+	{ErrProtectedBranch, ExitProtectedBranch, "protected_branch"},
+	{ErrSecretsFound, ExitSecretsFound, "secrets_found"},
+	{ErrDiffTooLarge, ExitDiffTooLarge, "diff_too_large"},
+	{ErrChecksFailed, ExitChecksFailed, "checks_failed"},
+	{ErrFrozen, ExitFrozen, "frozen"},
+	{ErrNotAuthenticated, ExitNotAuthenticated, "not_authenticated"},
+	{ErrPathLocked, ExitPathLocked, "path_locked"},
+}
+
+// ExitCode returns the process exit code for err (0 when nil)
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	for _, c := range causes {
+		if errors.Is(err, c.err) {
+			return c.exit
+		}
+	}
+	return ExitFailure
+}
+
+// Code returns the API error code for err, or "" when it has no known cause
+func Code(err error) string {
+	for _, c := range causes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ""
+}
+
+// FromCode rebuilds an error received from the API: it reads as message
+// and matches the sentinel for code. Unknown codes return nil.
+func FromCode(code, message string) error {
+	for _, c := range causes {
+		if c.code == code {
+			return &remoteError{message: message, cause: c.err}
+		}
+	}
+	return nil
+}
+
+// remoteError carries a server's message while unwrapping to the cause
+type remoteError struct {
+	message string
+	cause   error
+}
+
+func (e *remoteError) Error() string { return e.message }
+
+func (e *remoteError) Unwrap() error { return e.cause }
@@ -0,0 +1,56 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{errors.New("boom"), ExitFailure},
+		{fmt.Errorf("%w: fixer", ErrGoblinNotFound), ExitGoblinNotFound},
+		{fmt.Errorf("failed to create worktree: %w", ErrWorktreeExists), ExitWorktreeExists},
+		{fmt.Errorf("%w: codex", ErrAgentNotInstalled), ExitAgentNotInstalled},
+		{ErrTmuxUnavailable, ExitTmuxUnavailable},
+		{fmt.Errorf("%w: 2 in fixer", ErrSecretsFound), ExitSecretsFound},
+		{fmt.Errorf("%w: fixer changes 80 files", ErrDiffTooLarge), ExitDiffTooLarge},
+		{fmt.Errorf("%w: go vet ./...", ErrChecksFailed), ExitChecksFailed},
+		{fmt.Errorf("%w: Release freeze until Thu 09:00", ErrFrozen), ExitFrozen},
+		{fmt.Errorf("failed to create PR: %w", ErrNotAuthenticated), ExitNotAuthenticated},
+		{fmt.Errorf("%w: src/auth/ by fixer", ErrPathLocked), ExitPathLocked},
+	}
+
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCodeRoundTrip(t *testing.T) {
+	err := fmt.Errorf("%w: fixer", ErrGoblinNotFound)
+	code := Code(err)
+	if code != "goblin_not_found" {
+		t.Fatalf("Expected goblin_not_found, got %q", code)
+	}
+
+	remote := FromCode(code, err.Error())
+	if !errors.Is(remote, ErrGoblinNotFound) {
+		t.Error("Expected the rebuilt error to match the sentinel")
+	}
+	if remote.Error() != "goblin not found: fixer" {
+		t.Errorf("Expected the server's message kept, got %q", remote.Error())
+	}
+
+	if Code(errors.New("boom")) != "" {
+		t.Error("Expected no code for an unknown cause")
+	}
+	if FromCode("", "boom") != nil {
+		t.Error("Expected nil for an unknown code")
+	}
+}
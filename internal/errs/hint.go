@@ -0,0 +1,32 @@
+package errs
+
+import "errors"
+
+// hinted pairs an error with what fixes it
+type hinted struct {
+	err  error
+	hint string
+}
+
+func (e *hinted) Error() string { return e.err.Error() }
+
+func (e *hinted) Unwrap() error { return e.err }
+
+// WithHint attaches a remediation hint to err: the exact command, or gforge
+// subcommand, that fixes it. The hint survives wrapping with %w and is
+// shown by the CLI after the message. A nil err stays nil.
+func WithHint(err error, hint string) error {
+	if err == nil || hint == "" {
+		return err
+	}
+	return &hinted{err: err, hint: hint}
+}
+
+// Hint returns the remediation hint attached to err, or ""
+func Hint(err error) string {
+	var h *hinted
+	if errors.As(err, &h) {
+		return h.hint
+	}
+	return ""
+}
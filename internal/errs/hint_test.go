@@ -0,0 +1,29 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestHint(t *testing.T) {
+	err := WithHint(fmt.Errorf("%w: fixer", ErrGoblinNotFound), "gforge list")
+	wrapped := fmt.Errorf("failed to attach: %w", err)
+
+	if got := Hint(wrapped); got != "gforge list" {
+		t.Errorf("Expected the hint through wrapping, got %q", got)
+	}
+	if wrapped.Error() != "failed to attach: goblin not found: fixer" {
+		t.Errorf("Expected the message unchanged, got %q", wrapped)
+	}
+	if !errors.Is(wrapped, ErrGoblinNotFound) || ExitCode(wrapped) != ExitGoblinNotFound {
+		t.Error("Expected the cause still found")
+	}
+
+	if Hint(errors.New("plain")) != "" {
+		t.Error("Expected no hint on a plain error")
+	}
+	if WithHint(nil, "gforge doctor") != nil {
+		t.Error("Expected nil to stay nil")
+	}
+}
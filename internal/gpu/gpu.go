@@ -0,0 +1,122 @@
+// Package gpu detects GPUs and their memory for scheduling local models.
+package gpu
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GPU is one detected graphics card
+type GPU struct {
+	Index    int
+	Vendor   string // nvidia or amd
+	Name     string
+	MemoryMB int // Total VRAM
+	UsedMB   int
+}
+
+// Detect lists GPUs using nvidia-smi, then rocm-smi. No tool or no GPU
+// returns an empty list, not an error.
+func Detect() []GPU {
+	if out, err := exec.Command("nvidia-smi",
+		"--query-gpu=index,name,memory.total,memory.used",
+		"--format=csv,noheader,nounits").Output(); err == nil {
+		return parseNvidiaSMI(string(out))
+	}
+
+	if out, err := exec.Command("rocm-smi", "--showproductname", "--showmeminfo", "vram", "--csv").Output(); err == nil {
+		return parseROCmSMI(string(out))
+	}
+
+	return nil
+}
+
+// parseNvidiaSMI reads `index, name, total MiB, used MiB` lines
+func parseNvidiaSMI(out string) []GPU {
+	var gpus []GPU
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 4 {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			continue
+		}
+		total, _ := strconv.Atoi(strings.TrimSpace(fields[2]))
+		used, _ := strconv.Atoi(strings.TrimSpace(fields[3]))
+		gpus = append(gpus, GPU{
+			Index:    index,
+			Vendor:   "nvidia",
+			Name:     strings.TrimSpace(fields[1]),
+			MemoryMB: total,
+			UsedMB:   used,
+		})
+	}
+	return gpus
+}
+
+// parseROCmSMI reads rocm-smi CSV output, whose columns are named by
+// header; VRAM is reported in bytes
+func parseROCmSMI(out string) []GPU {
+	records, err := csv.NewReader(strings.NewReader(strings.TrimSpace(out))).ReadAll()
+	if err != nil || len(records) < 2 {
+		return nil
+	}
+
+	col := make(map[string]int)
+	for i, h := range records[0] {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	field := func(rec []string, names ...string) string {
+		for _, n := range names {
+			if i, ok := col[n]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+		}
+		return ""
+	}
+
+	var gpus []GPU
+	for i, rec := range records[1:] {
+		total, _ := strconv.ParseInt(field(rec, "vram total memory (b)"), 10, 64)
+		used, _ := strconv.ParseInt(field(rec, "vram total used memory (b)"), 10, 64)
+		name := field(rec, "card series", "card model")
+		if name == "" {
+			name = fmt.Sprintf("AMD GPU %d", i)
+		}
+		gpus = append(gpus, GPU{
+			Index:    i,
+			Vendor:   "amd",
+			Name:     name,
+			MemoryMB: int(total / (1024 * 1024)),
+			UsedMB:   int(used / (1024 * 1024)),
+		})
+	}
+	return gpus
+}
+
+// TotalMemoryMB sums VRAM across GPUs
+func TotalMemoryMB(gpus []GPU) int {
+	total := 0
+	for _, g := range gpus {
+		total += g.MemoryMB
+	}
+	return total
+}
+
+// LocalSlots returns how many local-model goblins fit in the GPUs' VRAM at
+// perGoblinMB each. Without a GPU models run on the CPU, one at a time.
+func LocalSlots(gpus []GPU, perGoblinMB int) int {
+	total := TotalMemoryMB(gpus)
+	if total == 0 || perGoblinMB <= 0 {
+		return 1
+	}
+	if slots := total / perGoblinMB; slots > 1 {
+		return slots
+	}
+	return 1
+}
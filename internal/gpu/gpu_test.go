@@ -0,0 +1,55 @@
+package gpu
+
+import "testing"
+
+func TestParseNvidiaSMI(t *testing.T) {
+	out := "0, NVIDIA GeForce RTX 4090, 24564, 1024\n1, NVIDIA RTX A4000, 16376, 0\n"
+	gpus := parseNvidiaSMI(out)
+
+	if len(gpus) != 2 {
+		t.Fatalf("Expected 2 GPUs, got %d", len(gpus))
+	}
+	if gpus[0].Name != "NVIDIA GeForce RTX 4090" || gpus[0].MemoryMB != 24564 || gpus[0].UsedMB != 1024 {
+		t.Errorf("Unexpected first GPU %+v", gpus[0])
+	}
+	if gpus[1].Index != 1 || gpus[1].Vendor != "nvidia" {
+		t.Errorf("Unexpected second GPU %+v", gpus[1])
+	}
+
+	if gpus := parseNvidiaSMI("NVIDIA-SMI has failed\n"); len(gpus) != 0 {
+		t.Errorf("Expected no GPUs from an error message, got %+v", gpus)
+	}
+}
+
+func TestParseROCmSMI(t *testing.T) {
+	out := `device,Card series,VRAM Total Memory (B),VRAM Total Used Memory (B)
+card0,Radeon RX 7900 XTX,25753026560,536870912
+`
+	gpus := parseROCmSMI(out)
+	if len(gpus) != 1 {
+		t.Fatalf("Expected 1 GPU, got %d", len(gpus))
+	}
+	if gpus[0].Name != "Radeon RX 7900 XTX" || gpus[0].MemoryMB != 24560 || gpus[0].UsedMB != 512 || gpus[0].Vendor != "amd" {
+		t.Errorf("Unexpected GPU %+v", gpus[0])
+	}
+}
+
+func TestLocalSlots(t *testing.T) {
+	gpus := []GPU{{MemoryMB: 24576}, {MemoryMB: 16384}}
+
+	tests := []struct {
+		gpus     []GPU
+		perMB    int
+		expected int
+	}{
+		{nil, 6144, 1},
+		{gpus, 6144, 6},
+		{gpus[:1], 32768, 1},
+		{gpus, 0, 1},
+	}
+	for _, tc := range tests {
+		if got := LocalSlots(tc.gpus, tc.perMB); got != tc.expected {
+			t.Errorf("LocalSlots(%d MB, %d) = %d, want %d", TotalMemoryMB(tc.gpus), tc.perMB, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package hours decides when autonomous work may run, so unattended loops
+// don't spend API budget overnight or at weekends.
+package hours
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+// Policy is a weekly working-hours window. A nil Policy is always open.
+type Policy struct {
+	days       [7]bool // Indexed by time.Weekday
+	start, end time.Duration
+	loc        *time.Location
+}
+
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// New builds the policy from config; disabled working hours return nil.
+// A window whose end is before its start runs past midnight, counting as
+// part of the day it starts on.
+func New(cfg config.WorkingHoursConfig) (*Policy, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	p := &Policy{loc: time.Local}
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid working_hours.timezone: %w", err)
+		}
+		p.loc = loc
+	}
+
+	var err error
+	if p.start, err = clock(cfg.Start); err != nil {
+		return nil, fmt.Errorf("invalid working_hours.start: %w", err)
+	}
+	if p.end, err = clock(cfg.End); err != nil {
+		return nil, fmt.Errorf("invalid working_hours.end: %w", err)
+	}
+	if p.start == p.end {
+		return nil, fmt.Errorf("working_hours.start and end are both %s", cfg.Start)
+	}
+
+	days := cfg.Days
+	if len(days) == 0 {
+		days = []string{"mon", "tue", "wed", "thu", "fri"}
+	}
+	for _, d := range days {
+		key := strings.ToLower(d)
+		if len(key) > 3 {
+			key = key[:3]
+		}
+		wd, ok := dayNames[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid working_hours day: %s", d)
+		}
+		p.days[wd] = true
+	}
+
+	return p, nil
+}
+
+// clock parses HH:MM into an offset from midnight
+func clock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Open reports whether autonomous work may run at t
+func (p *Policy) Open(t time.Time) bool {
+	if p == nil {
+		return true
+	}
+
+	t = t.In(p.loc)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, p.loc)
+	offset := t.Sub(midnight)
+
+	if p.start < p.end {
+		return p.days[t.Weekday()] && offset >= p.start && offset < p.end
+	}
+	// Overnight window: evenings belong to today, early mornings to yesterday
+	if offset >= p.start {
+		return p.days[t.Weekday()]
+	}
+	return offset < p.end && p.days[(t.Weekday()+6)%7]
+}
+
+// Next returns the next time at or after t when work may run (t itself
+// when already open)
+func (p *Policy) Next(t time.Time) time.Time {
+	if p.Open(t) {
+		return t
+	}
+
+	t = t.In(p.loc)
+	for i := 0; i <= 7; i++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+i, 0, 0, 0, 0, p.loc)
+		start := day.Add(p.start)
+		if start.After(t) && p.days[day.Weekday()] {
+			return start
+		}
+	}
+	return t
+}
+
+// Close returns when the current window ends, or t if closed at t
+func (p *Policy) Close(t time.Time) time.Time {
+	if p == nil || !p.Open(t) {
+		return t
+	}
+
+	t = t.In(p.loc)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, p.loc)
+	end := midnight.Add(p.end)
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
@@ -0,0 +1,92 @@
+package hours
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestDisabled(t *testing.T) {
+	p, err := New(config.WorkingHoursConfig{})
+	if err != nil || p != nil {
+		t.Fatalf("Expected no policy when disabled, got %v, %v", p, err)
+	}
+	if !p.Open(time.Now()) {
+		t.Error("Expected a nil policy to always be open")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	p, err := New(config.WorkingHoursConfig{Enabled: true, Start: "09:00", End: "18:00", Timezone: "UTC"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// 2026-10-16 is a Friday
+	tests := []struct {
+		at   string
+		open bool
+	}{
+		{"2026-10-16T08:59:00Z", false},
+		{"2026-10-16T09:00:00Z", true},
+		{"2026-10-16T17:59:00Z", true},
+		{"2026-10-16T18:00:00Z", false},
+		{"2026-10-17T12:00:00Z", false}, // Saturday
+	}
+	for _, tc := range tests {
+		at, _ := time.Parse(time.RFC3339, tc.at)
+		if got := p.Open(at); got != tc.open {
+			t.Errorf("Open(%s) = %v, want %v", tc.at, got, tc.open)
+		}
+	}
+
+	fri, _ := time.Parse(time.RFC3339, "2026-10-16T20:00:00Z")
+	if next := p.Next(fri); next.Format(time.RFC3339) != "2026-10-19T09:00:00Z" {
+		t.Errorf("Expected Monday morning after Friday evening, got %s", next)
+	}
+	noon, _ := time.Parse(time.RFC3339, "2026-10-16T12:00:00Z")
+	if next := p.Next(noon); !next.Equal(noon) {
+		t.Errorf("Expected an open time to be its own next, got %s", next)
+	}
+	if end := p.Close(noon); end.Format(time.RFC3339) != "2026-10-16T18:00:00Z" {
+		t.Errorf("Expected the window to close at 18:00, got %s", end)
+	}
+}
+
+func TestOvernight(t *testing.T) {
+	p, err := New(config.WorkingHoursConfig{Enabled: true, Days: []string{"Friday"}, Start: "22:00", End: "06:00", Timezone: "UTC"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for at, open := range map[string]bool{
+		"2026-10-16T23:00:00Z": true,  // Friday night
+		"2026-10-17T05:00:00Z": true,  // Early Saturday, still Friday's window
+		"2026-10-17T23:00:00Z": false, // Saturday night
+		"2026-10-16T05:00:00Z": false, // Early Friday belongs to Thursday
+	} {
+		ts, _ := time.Parse(time.RFC3339, at)
+		if got := p.Open(ts); got != open {
+			t.Errorf("Open(%s) = %v, want %v", at, got, open)
+		}
+	}
+
+	late, _ := time.Parse(time.RFC3339, "2026-10-16T23:00:00Z")
+	if end := p.Close(late); end.Format(time.RFC3339) != "2026-10-17T06:00:00Z" {
+		t.Errorf("Expected the window to close Saturday 06:00, got %s", end)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, cfg := range []config.WorkingHoursConfig{
+		{Enabled: true, Start: "9am", End: "18:00"},
+		{Enabled: true, Start: "09:00", End: "09:00"},
+		{Enabled: true, Start: "09:00", End: "18:00", Days: []string{"someday"}},
+		{Enabled: true, Start: "09:00", End: "18:00", Timezone: "Mars/Olympus"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected %+v rejected", cfg)
+		}
+	}
+}
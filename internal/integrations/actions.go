@@ -0,0 +1,128 @@
+package integrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WorkflowRun is a GitHub Actions run
+type WorkflowRun struct {
+	ID         int64     `json:"databaseId"`
+	Workflow   string    `json:"workflowName"`
+	Event      string    `json:"event"`
+	Branch     string    `json:"headBranch"`
+	Status     string    `json:"status"`     // queued, in_progress, completed...
+	Conclusion string    `json:"conclusion"` // success, failure, cancelled... once completed
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+const workflowRunFields = "databaseId,workflowName,event,headBranch,status,conclusion,url,createdAt"
+
+// DispatchWorkflow triggers a workflow_dispatch of a workflow (file or
+// name) on ref, the repository's default branch when empty. repo may be
+// [HOST/]OWNER/REPO, or empty for the repository gh runs in.
+func (g *GitHubClient) DispatchWorkflow(repo, workflow, ref string, inputs map[string]string) error {
+	args := []string{"workflow", "run", workflow}
+	if ref != "" {
+		args = append(args, "--ref", ref)
+	}
+	for _, k := range sortedKeys(inputs) {
+		args = append(args, "-f", k+"="+inputs[k])
+	}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+
+	if _, err := g.runGH(args...); err != nil {
+		return fmt.Errorf("failed to dispatch workflow %s: %w", workflow, err)
+	}
+	return nil
+}
+
+// DispatchRepository sends a repository_dispatch event with the payload
+// as its client_payload
+func (g *GitHubClient) DispatchRepository(repo, eventType string, payload map[string]string) error {
+	path := "repos/{owner}/{repo}/dispatches"
+	var args []string
+	if repo != "" {
+		parts := strings.Split(repo, "/")
+		if len(parts) == 3 {
+			args = append(args, "--hostname", parts[0])
+			parts = parts[1:]
+		}
+		path = "repos/" + strings.Join(parts, "/") + "/dispatches"
+	}
+
+	args = append([]string{"api", "--method", "POST", path, "-f", "event_type=" + eventType}, args...)
+	for _, k := range sortedKeys(payload) {
+		args = append(args, "-f", fmt.Sprintf("client_payload[%s]=%s", k, payload[k]))
+	}
+
+	if _, err := g.runGH(args...); err != nil {
+		return fmt.Errorf("failed to send repository_dispatch %s: %w", eventType, err)
+	}
+	return nil
+}
+
+// ListWorkflowRuns returns the latest runs of a workflow, newest first.
+// workflow, branch and event narrow the list when given.
+func (g *GitHubClient) ListWorkflowRuns(repo, workflow, branch, event string, limit int) ([]*WorkflowRun, error) {
+	args := []string{"run", "list", "--json", workflowRunFields, "--limit", fmt.Sprintf("%d", limit)}
+	if workflow != "" {
+		args = append(args, "--workflow", workflow)
+	}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	if event != "" {
+		args = append(args, "--event", event)
+	}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+
+	output, err := g.runGH(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+
+	var runs []*WorkflowRun
+	if err := json.Unmarshal(output, &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetWorkflowRun fetches a run by ID
+func (g *GitHubClient) GetWorkflowRun(repo string, id int64) (*WorkflowRun, error) {
+	args := []string{"run", "view", fmt.Sprintf("%d", id), "--json", workflowRunFields}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+
+	output, err := g.runGH(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+
+	var run WorkflowRun
+	if err := json.Unmarshal(output, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow run: %w", err)
+	}
+	return &run, nil
+}
+
+// sortedKeys returns a map's keys in order, so commands come out the same
+// every time
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
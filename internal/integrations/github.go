@@ -2,15 +2,23 @@ package integrations
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/trace"
 )
 
 // GitHubClient handles GitHub integration via gh CLI
 type GitHubClient struct {
 	// Uses gh CLI under the hood for authentication
+
+	// Dir is the checkout gh runs in, which picks the repository
+	// (defaults to the working directory)
+	Dir string
 }
 
 // Issue represents a GitHub issue
@@ -46,8 +54,12 @@ type PROptions struct {
 	Body     string
 	Draft    bool
 	Base     string // Target branch (default: main)
+	Repo     string // [HOST/]OWNER/REPO to open the PR in, for PRs from forks
 	Labels   []string
 	Assignee string
+
+	// Reviewers are GitHub logins or org/team slugs to request reviews from
+	Reviewers []string
 }
 
 // NewGitHubClient creates a new GitHub client
@@ -57,7 +69,7 @@ func NewGitHubClient() *GitHubClient {
 
 // IsAuthenticated checks if gh CLI is authenticated
 func (g *GitHubClient) IsAuthenticated() bool {
-	cmd := exec.Command("gh", "auth", "status")
+	cmd := trace.Command("gh", "auth", "status")
 	return cmd.Run() == nil
 }
 
@@ -166,10 +178,32 @@ func (g *GitHubClient) ListIssues(state string, limit int) ([]*Issue, error) {
 	return result, nil
 }
 
-// CreatePR creates a new pull request
+// AddIssueComment comments on an issue by reference (e.g., "owner/repo#123")
+func (g *GitHubClient) AddIssueComment(ref, body string) error {
+	owner, repo, number, err := parseIssueRef(ref)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"issue", "comment", fmt.Sprintf("%d", number), "--body", body}
+	if owner != "" && repo != "" {
+		args = append(args, "--repo", fmt.Sprintf("%s/%s", owner, repo))
+	}
+
+	if _, err := g.runGH(args...); err != nil {
+		return fmt.Errorf("failed to comment on issue: %w", err)
+	}
+	return nil
+}
+
+// CreatePR creates a new pull request. For a PR from a fork, branch is
+// OWNER:BRANCH and opts.Repo names the upstream repository.
 func (g *GitHubClient) CreatePR(branch string, opts PROptions) (*PullRequest, error) {
 	args := []string{"pr", "create", "--head", branch}
 
+	if opts.Repo != "" {
+		args = append(args, "--repo", opts.Repo)
+	}
 	if opts.Title != "" {
 		args = append(args, "--title", opts.Title)
 	}
@@ -188,6 +222,9 @@ func (g *GitHubClient) CreatePR(branch string, opts PROptions) (*PullRequest, er
 	if opts.Assignee != "" {
 		args = append(args, "--assignee", opts.Assignee)
 	}
+	if len(opts.Reviewers) > 0 {
+		args = append(args, "--reviewer", strings.Join(opts.Reviewers, ","))
+	}
 
 	output, err := g.runGH(args...)
 	if err != nil {
@@ -201,9 +238,41 @@ func (g *GitHubClient) CreatePR(branch string, opts PROptions) (*PullRequest, er
 	return g.GetPRByURL(url)
 }
 
+// CurrentUser returns the login gh is authenticated as
+func (g *GitHubClient) CurrentUser() (string, error) {
+	output, err := g.runGH("api", "user", "--jq", ".login")
+	if err != nil {
+		return "", fmt.Errorf("failed to get GitHub user: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ReviewersFromOwners turns CODEOWNERS owners into reviewers gh can
+// request: "@" is dropped from users and teams, and email addresses,
+// which gh can't request, are left out along with exclude (the author)
+func ReviewersFromOwners(owners []string, exclude string) []string {
+	var reviewers []string
+	for _, owner := range owners {
+		if !strings.HasPrefix(owner, "@") {
+			continue
+		}
+		login := strings.TrimPrefix(owner, "@")
+		if exclude != "" && strings.EqualFold(login, exclude) {
+			continue
+		}
+		reviewers = append(reviewers, login)
+	}
+	return reviewers
+}
+
 // GetPR gets a PR by number
 func (g *GitHubClient) GetPR(number int) (*PullRequest, error) {
-	args := []string{"pr", "view", fmt.Sprintf("%d", number),
+	return g.viewPR(fmt.Sprintf("%d", number))
+}
+
+// viewPR fetches a PR by number or URL
+func (g *GitHubClient) viewPR(ref string) (*PullRequest, error) {
+	args := []string{"pr", "view", ref,
 		"--json", "number,title,body,state,url,headRefName,baseRefName,isDraft,mergeable"}
 
 	output, err := g.runGH(args...)
@@ -228,10 +297,9 @@ func (g *GitHubClient) GetPRByURL(url string) (*PullRequest, error) {
 		return nil, fmt.Errorf("invalid PR URL: %s", url)
 	}
 
-	var number int
-	fmt.Sscanf(matches[1], "%d", &number)
-
-	return g.GetPR(number)
+	// The URL, not just the number, so PRs in other repositories (from
+	// forks) resolve too
+	return g.viewPR(url)
 }
 
 // MergePR merges a PR
@@ -272,8 +340,38 @@ func (g *GitHubClient) LinkIssueToPR(issueNum, prNum int) error {
 }
 
 func (g *GitHubClient) runGH(args ...string) ([]byte, error) {
-	cmd := exec.Command("gh", args...)
-	return cmd.Output()
+	cmd := trace.Command("gh", args...)
+	cmd.Dir = g.Dir
+	output, err := cmd.Output()
+	return output, ghError(err)
+}
+
+// ghError explains a failed gh run with what gh printed, and the fix when
+// gh is missing or not logged in
+func ghError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return errs.WithHint(fmt.Errorf("gh is not installed: %w", err),
+			"install the GitHub CLI (https://cli.github.com), then run gh auth login")
+	}
+
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) {
+		return err
+	}
+	stderr := strings.TrimSpace(string(exit.Stderr))
+	for _, marker := range []string{"gh auth login", "GH_TOKEN", "HTTP 401", "Bad credentials"} {
+		if strings.Contains(stderr, marker) {
+			return errs.WithHint(fmt.Errorf("%w: %s", errs.ErrNotAuthenticated, strings.SplitN(stderr, "\n", 2)[0]),
+				"gh auth login")
+		}
+	}
+	if stderr != "" {
+		return fmt.Errorf("%w: %s", err, stderr)
+	}
+	return err
 }
 
 // parseIssueRef parses "owner/repo#123" or "#123" or "123"
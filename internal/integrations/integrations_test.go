@@ -1,7 +1,17 @@
 package integrations
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
 )
 
 func TestParseIssueRef(t *testing.T) {
@@ -210,3 +220,187 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestReviewersFromOwners(t *testing.T) {
+	owners := []string{"@acme/security", "@alice", "@Bob", "docs@acme.com"}
+	reviewers := ReviewersFromOwners(owners, "bob")
+	if strings.Join(reviewers, ",") != "acme/security,alice" {
+		t.Errorf("Unexpected reviewers %v", reviewers)
+	}
+}
+
+const testPRTemplate = `## Description
+<!-- What does this change and why? -->
+
+## Changes
+
+## How Has This Been Tested?
+- [ ] Added tests
+
+## Checklist
+- [ ] Docs updated
+`
+
+func TestFillPRTemplate(t *testing.T) {
+	body := FillPRTemplate(testPRTemplate, PRDetails{
+		Commits: []string{"Add retry", "Cover retry in tests"},
+		Tasks:   []string{"Retry failed uploads"},
+		Issue:   &Issue{Number: 7},
+		Tests:   []string{"✓ go test ./..."},
+	})
+
+	want := `Resolves #7
+
+## Description
+
+Retry failed uploads
+<!-- What does this change and why? -->
+
+## Changes
+
+- Add retry
+- Cover retry in tests
+
+## How Has This Been Tested?
+
+- ✓ go test ./...
+- [ ] Added tests
+
+## Checklist
+- [ ] Docs updated
+`
+	if body != want {
+		t.Errorf("Unexpected body:\n%s", body)
+	}
+}
+
+func TestFillPRTemplateWithoutSections(t *testing.T) {
+	body := FillPRTemplate("```\n# not a heading\n```\n- [ ] Signed the CLA\n", PRDetails{Commits: []string{"Fix typo"}})
+	if !strings.HasPrefix(body, "- Fix typo\n\n```\n# not a heading\n```") {
+		t.Errorf("Expected the commits above the template, got:\n%s", body)
+	}
+}
+
+func TestBuildPRBody(t *testing.T) {
+	dir := t.TempDir()
+	details := PRDetails{Commits: []string{"Fix login bug"}}
+
+	body, err := BuildPRBody(dir, details)
+	if err != nil || !contains(body, "## Test Plan") {
+		t.Errorf("Expected the default layout without a template, got %q, %v", body, err)
+	}
+
+	os.MkdirAll(filepath.Join(dir, ".github"), 0755)
+	os.WriteFile(filepath.Join(dir, ".github", "pull_request_template.md"), []byte("## Changes\n"), 0644)
+	body, err = BuildPRBody(dir, details)
+	if err != nil || body != "## Changes\n\n- Fix login bug\n" {
+		t.Errorf("Expected the filled template, got %q, %v", body, err)
+	}
+}
+
+func TestParseTrackerRef(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"gh:acme/app#12", "gh:acme/app#12"},
+		{"github:#7", "gh:#7"},
+		{"linear:eng-42", "linear:ENG-42"},
+		{"jira:https://acme.atlassian.net/browse/OPS-9", "jira:OPS-9"},
+	}
+	for _, tt := range tests {
+		ref, err := ParseTrackerRef(tt.input)
+		if err != nil || ref.String() != tt.want {
+			t.Errorf("ParseTrackerRef(%q) = %v, %v, want %s", tt.input, ref, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"#12", "gh:", "gitlab:acme/app#1", "jira:not a key"} {
+		if _, err := ParseTrackerRef(bad); err == nil {
+			t.Errorf("ParseTrackerRef(%q) should error", bad)
+		}
+	}
+}
+
+func TestGHError(t *testing.T) {
+	if ghError(nil) != nil {
+		t.Error("Expected nil for a successful run")
+	}
+
+	_, err := exec.Command("gh-not-installed-anywhere").Output()
+	if err = ghError(err); errs.Hint(err) == "" {
+		t.Errorf("Expected an install hint for a missing gh, got %v", err)
+	}
+
+	_, err = exec.Command("sh", "-c", "echo 'To get started with GitHub CLI, please run:  gh auth login' >&2; exit 4").Output()
+	err = ghError(err)
+	if !errors.Is(err, errs.ErrNotAuthenticated) || errs.Hint(err) != "gh auth login" {
+		t.Errorf("Expected a not-authenticated error with a login hint, got %v (hint %q)", err, errs.Hint(err))
+	}
+
+	_, err = exec.Command("sh", "-c", "echo 'no pull requests found' >&2; exit 1").Output()
+	if err = ghError(err); !strings.Contains(err.Error(), "no pull requests found") || errs.Hint(err) != "" {
+		t.Errorf("Expected gh's message without a hint, got %v", err)
+	}
+}
+
+func TestSlackPost(t *testing.T) {
+	var got []map[string]string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		got = append(got, payload)
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path == "/api" && payload["channel"] == "#nope" {
+			w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	if err := NewSlackClient(server.URL+"/hook", "", "").Post("hello"); err != nil {
+		t.Fatalf("Webhook post failed: %v", err)
+	}
+	if got[0]["text"] != "hello" || auth != "" {
+		t.Errorf("Unexpected webhook request: %v (auth %q)", got[0], auth)
+	}
+
+	bot := NewSlackClient("", "xoxb-1", "#forge")
+	bot.apiURL = server.URL + "/api"
+	if err := bot.Post("hi"); err != nil {
+		t.Fatalf("Bot post failed: %v", err)
+	}
+	if got[1]["channel"] != "#forge" || got[1]["text"] != "hi" || auth != "Bearer xoxb-1" {
+		t.Errorf("Unexpected bot request: %v (auth %q)", got[1], auth)
+	}
+
+	bot.channel = "#nope"
+	if err := bot.Post("hi"); err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("Expected Slack's error reported, got %v", err)
+	}
+}
+
+func TestRenderSlackMessage(t *testing.T) {
+	msg := SlackMessage{Event: "completed", Goblin: "fixer", Branch: "gforge/fixer",
+		DiffStat: "2 files +10 -1", PRURL: "https://github.com/acme/app/pull/7"}
+	text, err := RenderSlackMessage(nil, msg)
+	if err != nil {
+		t.Fatalf("RenderSlackMessage failed: %v", err)
+	}
+	want := "*fixer* finished on `gforge/fixer` (2 files +10 -1)\n<https://github.com/acme/app/pull/7|Pull request>"
+	if text != want {
+		t.Errorf("Expected %q, got %q", want, text)
+	}
+
+	// A configured template wins
+	text, _ = RenderSlackMessage(map[string]string{"completed": "{{.Goblin}} done"}, msg)
+	if text != "fixer done" {
+		t.Errorf("Expected the configured template, got %q", text)
+	}
+
+	if _, err := RenderSlackMessage(nil, SlackMessage{Event: "spawned"}); err == nil {
+		t.Error("Expected an event without a template to fail")
+	}
+}
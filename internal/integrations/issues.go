@@ -0,0 +1,62 @@
+package integrations
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Issue trackers, as they prefix an issue reference
+const (
+	TrackerGitHub = "gh"
+	TrackerLinear = "linear"
+	TrackerJira   = "jira"
+)
+
+// IssueRef names an issue in a tracker, written tracker:id as in
+// gh:owner/repo#123, linear:PROJ-456 or jira:PROJ-789
+type IssueRef struct {
+	Tracker string
+	ID      string
+}
+
+func (r IssueRef) String() string {
+	return r.Tracker + ":" + r.ID
+}
+
+// ParseTrackerRef parses a tracker:id issue reference
+func ParseTrackerRef(ref string) (IssueRef, error) {
+	tracker, id, ok := strings.Cut(strings.TrimSpace(ref), ":")
+	if !ok || id == "" {
+		return IssueRef{}, fmt.Errorf("invalid issue reference: %s (use gh:owner/repo#123, linear:PROJ-456 or jira:PROJ-789)", ref)
+	}
+
+	switch strings.ToLower(tracker) {
+	case TrackerGitHub, "github":
+		if _, _, _, err := parseIssueRef(id); err != nil {
+			return IssueRef{}, err
+		}
+		return IssueRef{Tracker: TrackerGitHub, ID: id}, nil
+	case TrackerLinear:
+		return IssueRef{Tracker: TrackerLinear, ID: strings.ToUpper(id)}, nil
+	case TrackerJira:
+		key, err := ParseJiraRef(id)
+		if err != nil {
+			return IssueRef{}, err
+		}
+		return IssueRef{Tracker: TrackerJira, ID: key}, nil
+	}
+	return IssueRef{}, fmt.Errorf("unknown issue tracker: %s (use gh, linear or jira)", tracker)
+}
+
+// CommentOnIssue posts a comment on an issue with its tracker's client
+func CommentOnIssue(ref IssueRef, body string) error {
+	switch ref.Tracker {
+	case TrackerGitHub:
+		return NewGitHubClient().AddIssueComment(ref.ID, body)
+	case TrackerLinear:
+		return NewLinearClient().AddComment(ref.ID, body)
+	case TrackerJira:
+		return NewJiraClient().AddComment(ref.ID, body)
+	}
+	return fmt.Errorf("unknown issue tracker: %s", ref.Tracker)
+}
@@ -0,0 +1,154 @@
+package integrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PRTemplateFiles are where GitHub looks for a repository's pull request
+// template
+var PRTemplateFiles = []string{
+	".github/PULL_REQUEST_TEMPLATE.md",
+	".github/pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+}
+
+// PRDetails is what is known about a branch when its PR is opened
+type PRDetails struct {
+	Commits []string // Subjects, oldest first
+	Tasks   []string // What the agent was asked to do
+	Issue   *Issue
+	Tests   []string // One line per check run, e.g. "✓ go vet ./..."
+}
+
+// prSection is a kind of template section gforge knows how to fill
+type prSection int
+
+const (
+	sectionOther prSection = iota
+	sectionSummary
+	sectionChanges
+	sectionIssue
+	sectionTests
+)
+
+// sectionKeywords classify a template heading by the words in it, checked
+// in this order so "Testing changes" is about tests
+var sectionKeywords = []struct {
+	section  prSection
+	keywords []string
+}{
+	{sectionTests, []string{"test", "verif", "qa", "validation"}},
+	{sectionIssue, []string{"issue", "ticket", "fixes", "closes", "related"}},
+	{sectionChanges, []string{"change", "commit", "implementation"}},
+	{sectionSummary, []string{"summary", "description", "overview", "motivation", "what", "why", "context", "purpose", "about"}},
+}
+
+// FindPRTemplate returns a repository's pull request template, or ""
+// when it has none
+func FindPRTemplate(root string) (string, error) {
+	for _, name := range PRTemplateFiles {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+// BuildPRBody fills the pull request template of the repository at root,
+// falling back to GeneratePRBody when it has none
+func BuildPRBody(root string, d PRDetails) (string, error) {
+	template, err := FindPRTemplate(root)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(template) == "" {
+		return GeneratePRBody(d.Commits, d.Issue), nil
+	}
+	return FillPRTemplate(template, d), nil
+}
+
+// FillPRTemplate puts the details under the template's matching
+// headings, each kind once, ahead of whatever the section already holds
+// (prompts, checklists). Details with no section to go in are written
+// above the template.
+func FillPRTemplate(template string, d PRDetails) string {
+	content := map[prSection]string{
+		sectionSummary: strings.Join(d.Tasks, "\n\n"),
+		sectionChanges: bulletList(d.Commits),
+		sectionTests:   bulletList(d.Tests),
+	}
+	if d.Issue != nil {
+		content[sectionIssue] = fmt.Sprintf("Resolves #%d", d.Issue.Number)
+	}
+
+	var (
+		body   strings.Builder
+		filled = make(map[prSection]bool)
+		inCode bool
+	)
+	for _, line := range strings.SplitAfter(template, "\n") {
+		body.WriteString(line)
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+		}
+		if inCode || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		section := classifyHeading(trimmed)
+		if section == sectionOther || filled[section] || content[section] == "" {
+			continue
+		}
+		filled[section] = true
+		if !strings.HasSuffix(line, "\n") {
+			body.WriteString("\n")
+		}
+		body.WriteString("\n" + content[section] + "\n")
+	}
+
+	var preamble []string
+	for _, section := range []prSection{sectionIssue, sectionSummary, sectionChanges, sectionTests} {
+		if !filled[section] && content[section] != "" {
+			preamble = append(preamble, content[section])
+		}
+	}
+	if len(preamble) == 0 {
+		return body.String()
+	}
+	return strings.Join(preamble, "\n\n") + "\n\n" + body.String()
+}
+
+// classifyHeading tells which details belong under a Markdown heading
+func classifyHeading(heading string) prSection {
+	heading = strings.ToLower(strings.TrimLeft(heading, "# "))
+	for _, k := range sectionKeywords {
+		for _, keyword := range k.keywords {
+			if strings.Contains(heading, keyword) {
+				return k.section
+			}
+		}
+	}
+	return sectionOther
+}
+
+// bulletList renders items as a Markdown list
+func bulletList(items []string) string {
+	var lines []string
+	for _, item := range items {
+		lines = append(lines, "- "+item)
+	}
+	return strings.Join(lines, "\n")
+}
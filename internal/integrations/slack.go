@@ -0,0 +1,155 @@
+package integrations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// SlackClient posts messages to a Slack channel, through an incoming
+// webhook or as a bot
+type SlackClient struct {
+	webhookURL string
+	botToken   string
+	channel    string
+	apiURL     string
+	client     *http.Client
+}
+
+// SlackMessage is what a Slack message template is filled from
+type SlackMessage struct {
+	Event    string // completed or commit
+	Goblin   string
+	Agent    string
+	Project  string
+	Branch   string
+	Commit   string // Short hash of a commit event
+	Subject  string // Its subject line
+	DiffStat string // What the branch changes, e.g. "3 files +40 -2"
+	PRURL    string // The goblin's pull request, if one was opened
+}
+
+// DefaultSlackTemplates are the messages posted for each event unless
+// integrations.slack.templates overrides them
+var DefaultSlackTemplates = map[string]string{
+	"completed": "*{{.Goblin}}* finished on `{{.Branch}}`" +
+		"{{if .DiffStat}} ({{.DiffStat}}){{end}}" +
+		"{{if .PRURL}}\n<{{.PRURL}}|Pull request>{{end}}",
+	"commit": "*{{.Goblin}}* committed `{{.Commit}}` {{.Subject}} on `{{.Branch}}`" +
+		"{{if .DiffStat}} ({{.DiffStat}} so far){{end}}" +
+		"{{if .PRURL}}\n<{{.PRURL}}|Pull request>{{end}}",
+}
+
+// NewSlackClient creates a Slack client for an incoming webhook or, without
+// one, a bot token posting to channel. Empty settings fall back to
+// SLACK_WEBHOOK_URL and SLACK_BOT_TOKEN.
+func NewSlackClient(webhookURL, botToken, channel string) *SlackClient {
+	if webhookURL == "" {
+		webhookURL = os.Getenv("SLACK_WEBHOOK_URL")
+	}
+	if botToken == "" {
+		botToken = os.Getenv("SLACK_BOT_TOKEN")
+	}
+	return &SlackClient{
+		webhookURL: webhookURL,
+		botToken:   botToken,
+		channel:    channel,
+		apiURL:     slackPostMessageURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// IsConfigured checks if Slack is configured
+func (s *SlackClient) IsConfigured() bool {
+	return s.webhookURL != "" || (s.botToken != "" && s.channel != "")
+}
+
+// Post sends a message in Slack's mrkdwn
+func (s *SlackClient) Post(text string) error {
+	if !s.IsConfigured() {
+		return fmt.Errorf("slack needs a webhook_url, or a bot_token and channel")
+	}
+	if s.webhookURL != "" {
+		_, err := s.post(s.webhookURL, map[string]string{"text": text})
+		return err
+	}
+
+	body, err := s.post(s.apiURL, map[string]string{"channel": s.channel, "text": text})
+	if err != nil {
+		return err
+	}
+	// The Web API answers 200 with the failure in the body
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse slack response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack error: %s", result.Error)
+	}
+	return nil
+}
+
+// post sends payload as JSON to url and returns the response body
+func (s *SlackClient) post(url string, payload interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if url == s.apiURL {
+		req.Header.Set("Authorization", "Bearer "+s.botToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post to slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("slack returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// RenderSlackMessage fills the template for msg's event, from templates
+// when it has one and DefaultSlackTemplates otherwise
+func RenderSlackMessage(templates map[string]string, msg SlackMessage) (string, error) {
+	text, ok := templates[msg.Event]
+	if !ok {
+		text, ok = DefaultSlackTemplates[msg.Event]
+	}
+	if !ok {
+		return "", fmt.Errorf("no slack template for %s", msg.Event)
+	}
+
+	tmpl, err := template.New(msg.Event).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse slack template for %s: %w", msg.Event, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, msg); err != nil {
+		return "", fmt.Errorf("failed to fill slack template for %s: %w", msg.Event, err)
+	}
+	return b.String(), nil
+}
@@ -3,6 +3,7 @@ package ipc
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/exec"
@@ -14,7 +15,6 @@ import (
 const (
 	DefaultSocketPath = "/tmp/gforge-voice.sock"
 	connectTimeout    = 5 * time.Second
-	readTimeout       = 100 * time.Millisecond
 )
 
 // VoiceCommand represents a parsed voice command
@@ -87,18 +87,19 @@ func (c *VoiceClient) Disconnect() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.conn != nil {
-		c.conn.Close()
-		c.conn = nil
-	}
-	c.connected = false
-
-	// Signal stop
+	// Signal stop before closing, so Listen sees the closed connection as
+	// the end rather than a failure
 	select {
 	case <-c.stopCh:
 	default:
 		close(c.stopCh)
 	}
+
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	c.connected = false
 }
 
 // IsConnected returns whether the client is connected
@@ -115,7 +116,8 @@ func (c *VoiceClient) OnCommand(handler func(VoiceCommand)) {
 	c.handlers = append(c.handlers, handler)
 }
 
-// Listen starts listening for commands from the voice daemon
+// Listen starts listening for commands from the voice daemon. It returns
+// nil once Disconnect is called.
 func (c *VoiceClient) Listen() error {
 	if !c.IsConnected() {
 		if err := c.Connect(); err != nil {
@@ -123,33 +125,35 @@ func (c *VoiceClient) Listen() error {
 		}
 	}
 
+	// Reads block until a command arrives; Disconnect closes the connection
+	// to end them. (A json.Decoder stays failed after a read timeout, so
+	// polling with deadlines would stop it reading.)
 	decoder := json.NewDecoder(c.conn)
 
 	for {
-		select {
-		case <-c.stopCh:
-			return nil
-		default:
-			// Set read deadline for non-blocking check
-			c.conn.SetReadDeadline(time.Now().Add(readTimeout))
-
-			var cmd VoiceCommand
-			if err := decoder.Decode(&cmd); err != nil {
-				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-					continue // Timeout, check stopCh and try again
-				}
-				return fmt.Errorf("failed to read command: %w", err)
+		var cmd VoiceCommand
+		if err := decoder.Decode(&cmd); err != nil {
+			select {
+			case <-c.stopCh:
+				return nil
+			default:
 			}
+			return fmt.Errorf("failed to read command: %w", err)
+		}
+
+		// Replies to status requests have no action
+		if cmd.Action == "" {
+			continue
+		}
 
-			// Dispatch to handlers
-			c.mu.Lock()
-			handlers := make([]func(VoiceCommand), len(c.handlers))
-			copy(handlers, c.handlers)
-			c.mu.Unlock()
+		// Dispatch to handlers
+		c.mu.Lock()
+		handlers := make([]func(VoiceCommand), len(c.handlers))
+		copy(handlers, c.handlers)
+		c.mu.Unlock()
 
-			for _, handler := range handlers {
-				go handler(cmd)
-			}
+		for _, handler := range handlers {
+			go handler(cmd)
 		}
 	}
 }
@@ -219,25 +223,52 @@ func IsDaemonRunning() bool {
 	return err == nil
 }
 
+// DaemonOptions configure the voice daemon process
+type DaemonOptions struct {
+	Model     string // Whisper size, or backend:model
+	ModelsDir string // where downloaded models are kept
+	Device    string
+	Hotkey    string // evdev key name, or a combination such as super+shift+g
+	WakeWord  string // empty for push-to-talk only
+	Language  string
+	NoSounds  bool
+	Output    io.Writer // daemon logs; nil discards them
+}
+
+// args returns the daemon's command line options
+func (o DaemonOptions) args() []string {
+	var args []string
+	for _, opt := range []struct{ flag, value string }{
+		{"--model", o.Model},
+		{"--models-dir", o.ModelsDir},
+		{"--device", o.Device},
+		{"--hotkey", o.Hotkey},
+		{"--wake-word", o.WakeWord},
+		{"--language", o.Language},
+	} {
+		if opt.value != "" {
+			args = append(args, opt.flag, opt.value)
+		}
+	}
+	if o.NoSounds {
+		args = append(args, "--no-sounds")
+	}
+	return args
+}
+
 // StartDaemon starts the voice daemon process
-func StartDaemon(modelSize, device string) error {
+func StartDaemon(opts DaemonOptions) error {
 	// Find the daemon script
 	daemonPath := findDaemonPath()
 	if daemonPath == "" {
 		return fmt.Errorf("voice daemon not found")
 	}
 
-	args := []string{daemonPath}
-	if modelSize != "" {
-		args = append(args, "--model", modelSize)
-	}
-	if device != "" {
-		args = append(args, "--device", device)
-	}
+	args := append([]string{daemonPath}, opts.args()...)
 
 	cmd := exec.Command("python3", args...)
-	cmd.Stdout = nil // Daemon runs in background
-	cmd.Stderr = nil
+	cmd.Stdout = opts.Output // Daemon runs in background
+	cmd.Stderr = opts.Output
 
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start voice daemon: %w", err)
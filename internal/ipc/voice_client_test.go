@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -99,10 +100,7 @@ func TestVoiceCommand(t *testing.T) {
 func TestOnCommand(t *testing.T) {
 	client := NewVoiceClient("")
 
-	received := false
-	client.OnCommand(func(cmd VoiceCommand) {
-		received = true
-	})
+	client.OnCommand(func(cmd VoiceCommand) {})
 
 	if len(client.handlers) != 1 {
 		t.Errorf("Expected 1 handler, got %d", len(client.handlers))
@@ -164,3 +162,55 @@ func TestVoiceStatus(t *testing.T) {
 		t.Errorf("Expected model tiny, got %s", parsed.Model)
 	}
 }
+
+func TestListen(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to create listener: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// A status reply, then a command after a pause longer than any poll
+		conn.Write([]byte(`{"status": "ok"}`))
+		time.Sleep(300 * time.Millisecond)
+		conn.Write([]byte(`{"action": "kill", "name": "coder"}` + "\n"))
+		time.Sleep(time.Second)
+	}()
+
+	client := NewVoiceClient(socketPath)
+	received := make(chan VoiceCommand, 2)
+	client.OnCommand(func(cmd VoiceCommand) { received <- cmd })
+
+	done := make(chan error, 1)
+	go func() { done <- client.Listen() }()
+
+	select {
+	case cmd := <-received:
+		if cmd.Action != "kill" || cmd.Name != "coder" {
+			t.Errorf("Expected kill coder, got %+v", cmd)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the command delivered")
+	}
+
+	client.Disconnect()
+	if err := <-done; err != nil {
+		t.Errorf("Expected Listen to end cleanly on Disconnect, got %v", err)
+	}
+}
+
+func TestDaemonOptionsArgs(t *testing.T) {
+	opts := DaemonOptions{Model: "whisper.cpp:base.en", ModelsDir: "/data/models", Hotkey: "super+shift+g", WakeWord: "hey goblin", NoSounds: true}
+	got := strings.Join(opts.args(), " ")
+	want := "--model whisper.cpp:base.en --models-dir /data/models --hotkey super+shift+g --wake-word hey goblin --no-sounds"
+	if got != want {
+		t.Errorf("args() = %q, want %q", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/astoreyai/goblin-forge/internal/style"
 	"github.com/rs/zerolog"
 )
 
@@ -19,7 +20,7 @@ func New(verbose bool) *Logger {
 	output := zerolog.ConsoleWriter{
 		Out:        os.Stderr,
 		TimeFormat: time.RFC3339,
-		NoColor:    false,
+		NoColor:    !style.Enabled(),
 	}
 
 	// Set log level
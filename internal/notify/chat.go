@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+// Matrix posts notifications to a Matrix room as the access token's user
+type Matrix struct {
+	homeserver string
+	roomID     string
+	token      string
+	client     *http.Client
+}
+
+// NewMatrix creates a Matrix notifier, reading the access token from
+// GFORGE_MATRIX_TOKEN when the config has none
+func NewMatrix(cfg config.MatrixConfig) *Matrix {
+	token := cfg.AccessToken
+	if token == "" {
+		token = os.Getenv("GFORGE_MATRIX_TOKEN")
+	}
+	return &Matrix{
+		homeserver: strings.TrimRight(cfg.Homeserver, "/"),
+		roomID:     cfg.RoomID,
+		token:      token,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Notifier
+func (m *Matrix) Name() string {
+	return "matrix"
+}
+
+// Send posts a notice to the room
+func (m *Matrix) Send(title, body string) error {
+	if m.homeserver == "" || m.roomID == "" || m.token == "" {
+		return fmt.Errorf("matrix needs a homeserver, room_id and access token")
+	}
+
+	msg := map[string]string{
+		"msgtype":        "m.notice",
+		"body":           title + "\n" + body,
+		"format":         "org.matrix.custom.html",
+		"formatted_body": "<b>" + htmlEscape(title) + "</b><br>" + htmlEscape(body),
+	}
+	// The transaction ID makes retries of the same request idempotent
+	txn := fmt.Sprintf("gforge-%d", time.Now().UnixNano())
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		m.homeserver, url.PathEscape(m.roomID), txn)
+
+	return postJSON(m.client, http.MethodPut, endpoint, "Bearer "+m.token, msg)
+}
+
+// telegramAPI is the Bot API's address; tests point it elsewhere
+var telegramAPI = "https://api.telegram.org"
+
+// Telegram sends notifications from a bot to a chat
+type Telegram struct {
+	token  string
+	chatID string
+	client *http.Client
+}
+
+// NewTelegram creates a Telegram notifier, reading the bot token from
+// GFORGE_TELEGRAM_TOKEN when the config has none
+func NewTelegram(cfg config.TelegramConfig) *Telegram {
+	token := cfg.BotToken
+	if token == "" {
+		token = os.Getenv("GFORGE_TELEGRAM_TOKEN")
+	}
+	return &Telegram{
+		token:  token,
+		chatID: cfg.ChatID,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Notifier
+func (t *Telegram) Name() string {
+	return "telegram"
+}
+
+// Send sends a message to the chat
+func (t *Telegram) Send(title, body string) error {
+	if t.token == "" || t.chatID == "" {
+		return fmt.Errorf("telegram needs a bot token and chat_id")
+	}
+
+	msg := map[string]string{
+		"chat_id":    t.chatID,
+		"text":       "<b>" + htmlEscape(title) + "</b>\n" + htmlEscape(body),
+		"parse_mode": "HTML",
+	}
+	return postJSON(t.client, http.MethodPost, telegramAPI+"/bot"+t.token+"/sendMessage", "", msg)
+}
+
+// Notifiers returns the transports notifications are configured for
+func Notifiers(cfg config.NotificationsConfig) []Notifier {
+	var notifiers []Notifier
+	if cfg.Desktop {
+		notifiers = append(notifiers, NewDesktop())
+	}
+	if cfg.Matrix.Homeserver != "" && cfg.Matrix.RoomID != "" {
+		notifiers = append(notifiers, NewMatrix(cfg.Matrix))
+	}
+	if cfg.Telegram.ChatID != "" {
+		notifiers = append(notifiers, NewTelegram(cfg.Telegram))
+	}
+	return notifiers
+}
+
+// Route picks the notifiers an event type goes to: those its route lists,
+// or all of them when it has none
+func Route(notifiers []Notifier, routes map[string][]string, t EventType) []Notifier {
+	names, ok := routes[string(t)]
+	if !ok {
+		return notifiers
+	}
+	var routed []Notifier
+	for _, n := range notifiers {
+		for _, name := range names {
+			if strings.EqualFold(name, n.Name()) {
+				routed = append(routed, n)
+				break
+			}
+		}
+	}
+	return routed
+}
+
+// postJSON sends v as JSON and fails on any non-2xx response
+func postJSON(client *http.Client, method, endpoint, auth string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error names the URL, which holds the Telegram bot token
+		return fmt.Errorf("failed to send notification: %v", redact(err.Error(), endpoint))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to send notification: %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// redact replaces endpoint's path in msg, which may carry a credential
+func redact(msg, endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Path == "" {
+		return msg
+	}
+	return strings.ReplaceAll(msg, u.Path, "/...")
+}
+
+// htmlEscape escapes the characters Matrix and Telegram HTML treat
+// specially
+func htmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
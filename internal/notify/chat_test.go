@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestMatrixSend(t *testing.T) {
+	var path, auth string
+	var msg map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.EscapedPath(), r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&msg)
+		w.Write([]byte(`{"event_id": "$1"}`))
+	}))
+	defer srv.Close()
+
+	m := NewMatrix(config.MatrixConfig{Homeserver: srv.URL + "/", RoomID: "!room:example.org", AccessToken: "tok"})
+	if err := m.Send("gforge: coder finished", "a < b"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if !strings.HasPrefix(path, "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/gforge-") {
+		t.Errorf("Unexpected path %s", path)
+	}
+	if auth != "Bearer tok" {
+		t.Errorf("Expected the access token sent, got %q", auth)
+	}
+	if msg["body"] != "gforge: coder finished\na < b" || !strings.Contains(msg["formatted_body"], "a &lt; b") {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+}
+
+func TestTelegramSend(t *testing.T) {
+	var path string
+	var msg map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&msg)
+		if msg["chat_id"] != "42" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok": false, "description": "chat not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	orig := telegramAPI
+	telegramAPI = srv.URL
+	defer func() { telegramAPI = orig }()
+
+	if err := NewTelegram(config.TelegramConfig{BotToken: "123:abc", ChatID: "42"}).Send("title", "body"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if path != "/bot123:abc/sendMessage" || msg["text"] != "<b>title</b>\nbody" {
+		t.Errorf("Unexpected request to %s: %+v", path, msg)
+	}
+
+	err := NewTelegram(config.TelegramConfig{BotToken: "123:abc", ChatID: "7"}).Send("title", "body")
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("Expected the API's error, got %v", err)
+	}
+}
+
+func TestNotifiersAndRoute(t *testing.T) {
+	cfg := config.NotificationsConfig{
+		Desktop:  true,
+		Telegram: config.TelegramConfig{BotToken: "t", ChatID: "1"},
+		Routes:   map[string][]string{"approval": {"telegram"}, "failure": {}},
+	}
+
+	notifiers := Notifiers(cfg)
+	if len(notifiers) != 2 || notifiers[0].Name() != "desktop" || notifiers[1].Name() != "telegram" {
+		t.Fatalf("Expected desktop and telegram, got %v", notifiers)
+	}
+
+	if got := Route(notifiers, cfg.Routes, EventApproval); len(got) != 1 || got[0].Name() != "telegram" {
+		t.Errorf("Expected approvals routed to telegram only, got %v", got)
+	}
+	if got := Route(notifiers, cfg.Routes, EventFailure); len(got) != 0 {
+		t.Errorf("Expected failures routed nowhere, got %v", got)
+	}
+	if got := Route(notifiers, cfg.Routes, EventTaskComplete); len(got) != 2 {
+		t.Errorf("Expected unrouted events sent everywhere, got %v", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package notify sends notifications for goblin events to the desktop and
+// chat rooms.
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// EventType identifies what happened to a goblin
+type EventType string
+
+const (
+	EventTaskComplete EventType = "task_complete"
+	EventFailure      EventType = "failure"
+	EventApproval     EventType = "approval"
+	EventDone         EventType = "done"
+)
+
+// Event is something worth telling the user about
+type Event struct {
+	Type    EventType
+	Goblin  string
+	Message string
+}
+
+// Title returns the notification title for an event
+func (e Event) Title() string {
+	switch e.Type {
+	case EventTaskComplete:
+		return fmt.Sprintf("gforge: %s finished", e.Goblin)
+	case EventFailure:
+		return fmt.Sprintf("gforge: %s failed", e.Goblin)
+	case EventApproval:
+		return fmt.Sprintf("gforge: %s needs approval", e.Goblin)
+	case EventDone:
+		return fmt.Sprintf("gforge: %s is done", e.Goblin)
+	default:
+		return fmt.Sprintf("gforge: %s", e.Goblin)
+	}
+}
+
+// Notifier delivers notifications to one place: the desktop, a chat room
+type Notifier interface {
+	Name() string // As routes name it: desktop, matrix, telegram, voice
+	Send(title, body string) error
+}
+
+// Desktop delivers desktop notifications using the platform's native tool
+type Desktop struct {
+	goos string
+}
+
+// NewDesktop creates a desktop notifier for the current platform
+func NewDesktop() *Desktop {
+	return &Desktop{goos: runtime.GOOS}
+}
+
+// Name implements Notifier
+func (n *Desktop) Name() string {
+	return "desktop"
+}
+
+// Send shows a desktop notification
+func (n *Desktop) Send(title, body string) error {
+	args, err := notifyCommand(n.goos, title, body)
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return fmt.Errorf("%s not found: %w", args[0], err)
+	}
+
+	if output, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to send notification: %s\n%s", err, string(output))
+	}
+	return nil
+}
+
+// notifyCommand builds the command line that shows a notification on goos
+func notifyCommand(goos, title, body string) ([]string, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"notify-send", "--app-name=gforge", title, body}, nil
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptString(body), appleScriptString(title))
+		return []string{"osascript", "-e", script}, nil
+	case "windows":
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('gforge').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`,
+			powerShellString(title), powerShellString(body))
+		return []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", script}, nil
+	default:
+		return nil, fmt.Errorf("desktop notifications not supported on %s", goos)
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// powerShellString quotes s as a PowerShell single-quoted literal
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotifyCommand(t *testing.T) {
+	tests := []struct {
+		goos    string
+		program string
+	}{
+		{"linux", "notify-send"},
+		{"darwin", "osascript"},
+		{"windows", "powershell"},
+	}
+
+	for _, tc := range tests {
+		args, err := notifyCommand(tc.goos, "title", "body")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.goos, err)
+			continue
+		}
+		if args[0] != tc.program {
+			t.Errorf("%s: expected %s, got %s", tc.goos, tc.program, args[0])
+		}
+	}
+
+	if _, err := notifyCommand("plan9", "title", "body"); err == nil {
+		t.Error("Expected error for unsupported platform")
+	}
+}
+
+func TestNotifyCommandQuoting(t *testing.T) {
+	args, _ := notifyCommand("darwin", `say "hi"`, `back\slash`)
+	script := args[2]
+	if !strings.Contains(script, `"say \"hi\""`) || !strings.Contains(script, `"back\\slash"`) {
+		t.Errorf("AppleScript not escaped: %s", script)
+	}
+
+	args, _ = notifyCommand("windows", "it's", "done")
+	if !strings.Contains(args[len(args)-1], "'it''s'") {
+		t.Errorf("PowerShell not escaped: %s", args[len(args)-1])
+	}
+}
+
+func TestEventTitle(t *testing.T) {
+	tests := map[EventType]string{
+		EventTaskComplete: "gforge: coder finished",
+		EventFailure:      "gforge: coder failed",
+		EventApproval:     "gforge: coder needs approval",
+		EventDone:         "gforge: coder is done",
+	}
+
+	for eventType, expected := range tests {
+		if got := (Event{Type: eventType, Goblin: "coder"}).Title(); got != expected {
+			t.Errorf("Title() = %q, want %q", got, expected)
+		}
+	}
+}
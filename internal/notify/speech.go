@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/voice"
+)
+
+// eventFilter is implemented by notifiers that take only some event types
+type eventFilter interface {
+	Wants(t EventType) bool
+}
+
+// Speech announces goblin events aloud, for working hands-free
+type Speech struct {
+	speaker  *voice.Speaker
+	err      error // why there is no speaker
+	announce map[EventType]bool
+}
+
+// NewSpeech creates a spoken notifier for the events in voice.announce.
+// A missing engine is reported when it is first used.
+func NewSpeech(cfg config.VoiceConfig) *Speech {
+	s := &Speech{announce: make(map[EventType]bool)}
+	s.speaker, s.err = voice.NewSpeaker(cfg)
+	for _, e := range cfg.Announce {
+		s.announce[EventType(strings.ToLower(e))] = true
+	}
+	return s
+}
+
+// Name implements Notifier
+func (s *Speech) Name() string {
+	return "voice"
+}
+
+// Wants reports whether events of type t are announced
+func (s *Speech) Wants(t EventType) bool {
+	return s.announce[t]
+}
+
+// Send speaks the title; bodies, such as pane output, are too long to
+// listen to
+func (s *Speech) Send(title, body string) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.speaker.Speak(strings.TrimPrefix(title, "gforge: "))
+}
+
+// Send delivers an event outside a watcher, such as gforge done
+// announcing a finished goblin, to the transports its route allows
+func Send(cfg *config.Config, event Event) error {
+	var failed []error
+	for _, n := range recipients(Configured(cfg), cfg.Notifications.Routes, event.Type) {
+		if err := n.Send(event.Title(), event.Message); err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// recipients are the notifiers an event goes to: those routed to, less
+// those that don't take its type
+func recipients(notifiers []Notifier, routes map[string][]string, t EventType) []Notifier {
+	var to []Notifier
+	for _, n := range Route(notifiers, routes, t) {
+		if f, ok := n.(eventFilter); ok && !f.Wants(t) {
+			continue
+		}
+		to = append(to, n)
+	}
+	return to
+}
+
+// Configured returns Notifiers for cfg.Notifications plus, with voice and
+// voice.feedback_sound on, spoken announcements
+func Configured(cfg *config.Config) []Notifier {
+	notifiers := Notifiers(cfg.Notifications)
+	if cfg.Voice.Enabled && cfg.Voice.FeedbackSound {
+		notifiers = append(notifiers, NewSpeech(cfg.Voice))
+	}
+	return notifiers
+}
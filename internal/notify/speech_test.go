@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestSpeech(t *testing.T) {
+	s := NewSpeech(config.VoiceConfig{TTS: "no-such-tts-engine", Announce: []string{"failure", "Approval"}})
+	if !s.Wants(EventFailure) || !s.Wants(EventApproval) || s.Wants(EventTaskComplete) {
+		t.Errorf("Expected only failures and approvals announced, got %v", s.announce)
+	}
+	if err := s.Send("gforge: coder failed", ""); err == nil {
+		t.Error("Expected an error without the engine installed")
+	}
+}
+
+func TestConfigured(t *testing.T) {
+	cfg := &config.Config{Voice: config.VoiceConfig{Enabled: true, TTS: "auto"}}
+	if got := Configured(cfg); len(got) != 0 {
+		t.Errorf("Expected no voice without feedback_sound, got %v", got)
+	}
+
+	cfg.Voice.FeedbackSound = true
+	if got := Configured(cfg); len(got) != 1 || got[0].Name() != "voice" {
+		t.Errorf("Expected the voice notifier, got %v", got)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var titles []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]string
+		json.NewDecoder(r.Body).Decode(&msg)
+		titles = append(titles, msg["body"])
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Notifications: config.NotificationsConfig{
+		Matrix: config.MatrixConfig{Homeserver: srv.URL + "/", RoomID: "!room:example.org", AccessToken: "tok"},
+		Routes: map[string][]string{"failure": {}},
+	}}
+	if err := Send(cfg, Event{Type: EventDone, Goblin: "coder", Message: "pushed"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := Send(cfg, Event{Type: EventFailure, Goblin: "coder"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(titles) != 1 || titles[0] != "gforge: coder is done\npushed" {
+		t.Errorf("Expected only the done event sent, got %q", titles)
+	}
+}
@@ -0,0 +1,294 @@
+package notify
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/tmux"
+)
+
+// approvalPatterns match prompts where an agent is blocked on the user
+var approvalPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)do you want to (proceed|continue|make this edit|create|run|allow)`),
+	regexp.MustCompile(`(?i)\[y/n\]|\(y/n\)|\[Y/n\]|\[y/N\]`),
+	regexp.MustCompile(`(?i)allow (once|always|this)`),
+	regexp.MustCompile(`(?i)waiting for (your )?(approval|confirmation)`),
+	regexp.MustCompile(`(?i)approve\?`),
+}
+
+// Snapshot is what the watcher sees of a goblin on one poll
+type Snapshot struct {
+	Alive   bool   // tmux session exists
+	Command string // foreground process in the pane
+	Content string // visible pane text
+}
+
+// goblinState tracks one goblin between polls
+type goblinState struct {
+	content      string
+	lastChange   time.Time
+	busy         bool // output changed since the last completion
+	agentSeen    bool // the agent has been in the foreground
+	failed       bool
+	awaitingUser bool
+	captured     time.Time // when content was last read from the pane
+}
+
+// Watcher polls running goblins and turns pane activity into events
+type Watcher struct {
+	coord     *coordinator.Coordinator
+	notifiers []Notifier
+	cfg       config.NotificationsConfig
+	log       *logging.Logger
+	states    map[string]*goblinState
+	reloader  *config.Reloader
+}
+
+// NewWatcher creates a watcher for the goblins managed by coord
+func NewWatcher(coord *coordinator.Coordinator, cfg *config.Config, log *logging.Logger) *Watcher {
+	return &Watcher{
+		coord:     coord,
+		notifiers: Configured(cfg),
+		cfg:       cfg.Notifications,
+		log:       log,
+		states:    make(map[string]*goblinState),
+	}
+}
+
+// ReloadConfig has the watcher re-read cfg's file before each poll when it
+// changes, applying live settings such as notification transports
+func (w *Watcher) ReloadConfig(cfg *config.Config) {
+	w.reloader = config.NewReloader(cfg)
+}
+
+// Run polls until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.reload()
+		w.poll(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reload rebuilds the transports and notification settings when the
+// config file has changed, so a new Telegram chat or route applies from
+// the next poll. Until an edited file loads, notifications go out as before.
+func (w *Watcher) reload() {
+	if w.reloader == nil {
+		return
+	}
+	changes, err := w.reloader.Check()
+	if err != nil {
+		if w.log != nil {
+			w.log.Warn("Failed to reload config; keeping the running settings", logging.Err(err))
+		}
+		return
+	}
+	if changes == nil {
+		return
+	}
+
+	cfg := w.reloader.Config()
+	w.notifiers = Configured(cfg)
+	w.cfg = cfg.Notifications
+	w.coord.ConfigReloaded(changes)
+}
+
+// poll snapshots every running goblin and dispatches resulting events
+func (w *Watcher) poll(now time.Time) {
+	goblins, err := w.coord.List()
+	if err != nil {
+		if w.log != nil {
+			w.log.Warn("Failed to list goblins", logging.Err(err))
+		}
+		return
+	}
+
+	// One tmux call per socket covers liveness and foreground commands
+	managers := make(map[string]*tmux.Manager)
+	sessions := make(map[string]map[string]tmux.SessionInfo)
+
+	seen := make(map[string]bool)
+	for _, g := range goblins {
+		if g.Status != "running" {
+			continue
+		}
+		seen[g.Name] = true
+
+		socket := w.coord.Socket(g)
+		mgr, ok := managers[socket]
+		if !ok {
+			mgr = tmux.NewManager(tmux.Config{SocketName: socket})
+			managers[socket] = mgr
+			var err error
+			if sessions[socket], err = mgr.Sessions(); err != nil && w.log != nil {
+				w.log.Warn("Failed to list tmux sessions", logging.String("socket", socket), logging.Err(err))
+			}
+		}
+		// A failed listing says nothing about whether the goblin is alive
+		if sessions[socket] == nil {
+			continue
+		}
+
+		info, alive := sessions[socket][g.TmuxSession]
+		snap := Snapshot{Alive: alive, Command: info.Command}
+		captured := false
+		if alive {
+			// Panes with no output since the last capture keep their content
+			if st := w.states[g.Name]; st != nil && info.Activity.Before(st.captured.Truncate(time.Second)) {
+				snap.Content = st.content
+			} else {
+				snap.Content, _ = mgr.CapturePane(g.TmuxSession, 50)
+				captured = true
+			}
+		}
+
+		for _, event := range w.Observe(g.Name, snap, now) {
+			w.dispatch(event)
+		}
+		if captured {
+			w.states[g.Name].captured = now
+		}
+	}
+
+	// Forget goblins that were stopped or killed on purpose
+	for name := range w.states {
+		if !seen[name] {
+			delete(w.states, name)
+		}
+	}
+}
+
+// Observe feeds one snapshot of a goblin into its state machine and
+// returns the events it triggers
+func (w *Watcher) Observe(name string, snap Snapshot, now time.Time) []Event {
+	st, ok := w.states[name]
+	if !ok {
+		st = &goblinState{content: snap.Content, lastChange: now}
+		w.states[name] = st
+	}
+
+	if st.failed {
+		return nil
+	}
+
+	if !snap.Alive {
+		st.failed = true
+		return []Event{{Type: EventFailure, Goblin: name, Message: "tmux session ended unexpectedly"}}
+	}
+
+	if tmux.Shell(snap.Command) {
+		if st.agentSeen {
+			st.failed = true
+			return []Event{{Type: EventFailure, Goblin: name, Message: "agent exited to the shell"}}
+		}
+	} else if snap.Command != "" {
+		st.agentSeen = true
+	}
+
+	if snap.Content != st.content {
+		st.content = snap.Content
+		st.lastChange = now
+		st.busy = true
+	}
+
+	if prompt := ApprovalPrompt(snap.Content); prompt != "" {
+		if st.awaitingUser {
+			return nil
+		}
+		st.awaitingUser = true
+		st.busy = false
+		return []Event{{Type: EventApproval, Goblin: name, Message: prompt}}
+	}
+	st.awaitingUser = false
+
+	if st.busy && now.Sub(st.lastChange) >= w.cfg.IdleTimeout {
+		st.busy = false
+		return []Event{{Type: EventTaskComplete, Goblin: name, Message: "agent has gone quiet"}}
+	}
+
+	return nil
+}
+
+// dispatch records an event and sends it if it is enabled
+func (w *Watcher) dispatch(event Event) {
+	if err := w.coord.RecordEvent(event.Goblin, string(event.Type), event.Message); err != nil && w.log != nil {
+		w.log.Warn("Failed to record event", logging.Err(err))
+	}
+	// A quiet agent has finished its task; what it committed is the task's,
+	// and it is free for the next one queued
+	if event.Type == EventTaskComplete {
+		if err := w.coord.EndTask(event.Goblin); err != nil && w.log != nil {
+			w.log.Warn("Failed to end task", logging.Err(err))
+		}
+		if _, err := w.coord.SendNextTask(event.Goblin); err != nil && w.log != nil {
+			w.log.Warn("Failed to send queued task", logging.String("goblin", event.Goblin), logging.Err(err))
+		}
+	}
+
+	if !w.Enabled(event.Type) {
+		return
+	}
+
+	if w.log != nil {
+		w.log.Info("Goblin event",
+			logging.String("goblin", event.Goblin),
+			logging.String("event", string(event.Type)))
+	}
+
+	for _, n := range recipients(w.notifiers, w.cfg.Routes, event.Type) {
+		if err := n.Send(event.Title(), event.Message); err != nil && w.log != nil {
+			w.log.Warn("Failed to send notification",
+				logging.String("transport", n.Name()),
+				logging.Err(err))
+		}
+	}
+}
+
+// Enabled reports whether notifications are configured for an event type
+func (w *Watcher) Enabled(t EventType) bool {
+	switch t {
+	case EventTaskComplete:
+		return w.cfg.OnComplete
+	case EventFailure:
+		return w.cfg.OnFailure
+	case EventApproval:
+		return w.cfg.OnApproval
+	default:
+		return false
+	}
+}
+
+// ApprovalPrompt returns the last lines of content if they contain an
+// approval prompt
+func ApprovalPrompt(content string) string {
+	var tail []string
+	lines := strings.Split(strings.TrimRight(content, "\n "), "\n")
+	for i := len(lines) - 1; i >= 0 && len(tail) < 8; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			tail = append(tail, line)
+		}
+	}
+
+	for _, line := range tail {
+		for _, re := range approvalPatterns {
+			if re.MatchString(line) {
+				return line
+			}
+		}
+	}
+	return ""
+}
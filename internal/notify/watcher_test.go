@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func newTestWatcher() *Watcher {
+	return &Watcher{
+		cfg: config.NotificationsConfig{
+			OnComplete:  true,
+			OnFailure:   true,
+			IdleTimeout: 10 * time.Second,
+		},
+		states: make(map[string]*goblinState),
+	}
+}
+
+func TestObserveTaskComplete(t *testing.T) {
+	w := newTestWatcher()
+	start := time.Now()
+
+	if events := w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "ready"}, start); len(events) != 0 {
+		t.Fatalf("First snapshot should not trigger events, got %v", events)
+	}
+
+	// Quiet since the first snapshot, but nothing happened yet
+	if events := w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "ready"}, start.Add(time.Minute)); len(events) != 0 {
+		t.Fatalf("Idle goblin without activity should not complete, got %v", events)
+	}
+
+	w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "working"}, start.Add(2*time.Minute))
+	if events := w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "working"}, start.Add(2*time.Minute+5*time.Second)); len(events) != 0 {
+		t.Fatalf("Should not complete before idle timeout, got %v", events)
+	}
+
+	events := w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "working"}, start.Add(2*time.Minute+15*time.Second))
+	if len(events) != 1 || events[0].Type != EventTaskComplete {
+		t.Fatalf("Expected task_complete, got %v", events)
+	}
+
+	// Only once per burst of activity
+	if events := w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "working"}, start.Add(3*time.Minute)); len(events) != 0 {
+		t.Errorf("Completion should fire once, got %v", events)
+	}
+}
+
+func TestObserveApproval(t *testing.T) {
+	w := newTestWatcher()
+	now := time.Now()
+
+	w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "editing"}, now)
+	events := w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "Edit main.go\nDo you want to make this edit to main.go?\n> 1. Yes\n  2. No\n"}, now.Add(time.Second))
+	if len(events) != 1 || events[0].Type != EventApproval {
+		t.Fatalf("Expected approval, got %v", events)
+	}
+
+	// Still waiting: no repeat, and no completion while blocked
+	if events := w.Observe("coder", Snapshot{Alive: true, Command: "claude", Content: "Edit main.go\nDo you want to make this edit to main.go?\n> 1. Yes\n  2. No\n"}, now.Add(time.Minute)); len(events) != 0 {
+		t.Errorf("Approval should fire once, got %v", events)
+	}
+}
+
+func TestObserveFailure(t *testing.T) {
+	w := newTestWatcher()
+	now := time.Now()
+
+	// A shell before the agent starts is not a failure
+	if events := w.Observe("coder", Snapshot{Alive: true, Command: "bash"}, now); len(events) != 0 {
+		t.Fatalf("Shell before agent start should not fail, got %v", events)
+	}
+	w.Observe("coder", Snapshot{Alive: true, Command: "claude"}, now)
+
+	events := w.Observe("coder", Snapshot{Alive: true, Command: "bash"}, now)
+	if len(events) != 1 || events[0].Type != EventFailure {
+		t.Fatalf("Expected failure when agent exits, got %v", events)
+	}
+
+	if events := w.Observe("coder", Snapshot{Alive: false}, now); len(events) != 0 {
+		t.Errorf("Failure should fire once, got %v", events)
+	}
+
+	events = w.Observe("tester", Snapshot{Alive: false}, now)
+	if len(events) != 1 || events[0].Type != EventFailure {
+		t.Errorf("Expected failure for dead session, got %v", events)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	w := newTestWatcher()
+	if !w.Enabled(EventTaskComplete) || !w.Enabled(EventFailure) {
+		t.Error("Configured events should be enabled")
+	}
+	if w.Enabled(EventApproval) {
+		t.Error("Approval should be disabled")
+	}
+}
+
+func TestApprovalPrompt(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected bool
+	}{
+		{"Overwrite file? [y/N]", true},
+		{"Allow once\nAllow always\nDeny", true},
+		{"Compiling...\nDone.", false},
+		{"", false},
+	}
+
+	for _, tc := range tests {
+		if got := ApprovalPrompt(tc.content) != ""; got != tc.expected {
+			t.Errorf("ApprovalPrompt(%q) = %v, want %v", tc.content, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package providers checks that the model services behind agents are
+// reachable and accept the configured credentials.
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Check outcomes
+const (
+	StatusOK           = "ok"
+	StatusNoKey        = "no key"
+	StatusUnauthorized = "unauthorized"
+	StatusUnreachable  = "unreachable"
+	StatusError        = "error"
+)
+
+// Provider is a model service and how to probe it
+type Provider struct {
+	Name    string
+	BaseURL string
+	KeyEnv  []string // Environment variables holding the API key, first set wins
+	Agents  []string // Built-in agents that use it
+
+	// request builds the probe for a key (possibly empty)
+	request func(ctx context.Context, baseURL, key string) (*http.Request, error)
+	// detail describes a successful response body
+	detail func(body []byte) string
+}
+
+// Result is the outcome of probing one provider
+type Result struct {
+	Provider string        `json:"provider"`
+	URL      string        `json:"url"`
+	Status   string        `json:"status"`
+	Latency  time.Duration `json:"latency_ns"`
+	Detail   string        `json:"detail,omitempty"`
+	Agents   []string      `json:"agents"`
+}
+
+// Defaults returns the providers behind the built-in agents
+func Defaults() []*Provider {
+	return []*Provider{
+		{
+			Name:    "anthropic",
+			BaseURL: "https://api.anthropic.com",
+			KeyEnv:  []string{"ANTHROPIC_API_KEY"},
+			Agents:  []string{"claude", "claude-auto"},
+			request: func(ctx context.Context, base, key string) (*http.Request, error) {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/v1/models", nil)
+				if err != nil {
+					return nil, err
+				}
+				req.Header.Set("anthropic-version", "2023-06-01")
+				if key != "" {
+					req.Header.Set("x-api-key", key)
+				}
+				return req, nil
+			},
+			detail: countModels("data"),
+		},
+		{
+			Name:    "openai",
+			BaseURL: "https://api.openai.com",
+			KeyEnv:  []string{"OPENAI_API_KEY"},
+			Agents:  []string{"codex"},
+			request: bearer("/v1/models"),
+			detail:  countModels("data"),
+		},
+		{
+			Name:    "google",
+			BaseURL: "https://generativelanguage.googleapis.com",
+			KeyEnv:  []string{"GEMINI_API_KEY", "GOOGLE_API_KEY"},
+			Agents:  []string{"gemini"},
+			request: func(ctx context.Context, base, key string) (*http.Request, error) {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/v1beta/models", nil)
+				if err != nil {
+					return nil, err
+				}
+				if key != "" {
+					req.Header.Set("x-goog-api-key", key)
+				}
+				return req, nil
+			},
+			detail: countModels("models"),
+		},
+		{
+			Name:    "ollama",
+			BaseURL: OllamaURL(),
+			Agents:  []string{"ollama", "ollama-deepseek", "ollama-qwen"},
+			request: bearer("/api/tags"),
+			detail:  countModels("models"),
+		},
+	}
+}
+
+// OllamaURL returns the local ollama server address, honouring OLLAMA_HOST
+func OllamaURL() string {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		return "http://127.0.0.1:11434"
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+// Key returns the provider's API key from the environment
+func (p *Provider) Key() string {
+	for _, env := range p.KeyEnv {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Check probes a provider. Without a key the request is still made, so
+// reachability is reported even when credentials are missing.
+func Check(ctx context.Context, client *http.Client, p *Provider) Result {
+	result := Result{Provider: p.Name, URL: p.BaseURL, Agents: p.Agents}
+
+	key := p.Key()
+	req, err := p.request(ctx, p.BaseURL, key)
+	if err != nil {
+		result.Status, result.Detail = StatusError, err.Error()
+		return result
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		if uerr, ok := err.(*url.Error); ok {
+			err = uerr.Err
+		}
+		result.Status, result.Detail = StatusUnreachable, err.Error()
+		return result
+	}
+	result.Latency = time.Since(start)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		result.Status = StatusOK
+		if p.detail != nil {
+			result.Detail = p.detail(body)
+		}
+	case (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden ||
+		resp.StatusCode == http.StatusBadRequest) && len(p.KeyEnv) > 0:
+		if key == "" {
+			result.Status = StatusNoKey
+			result.Detail = fmt.Sprintf("reachable; set %s (agents may use their own login)", strings.Join(p.KeyEnv, " or "))
+		} else {
+			result.Status = StatusUnauthorized
+			result.Detail = fmt.Sprintf("%s rejected (HTTP %d)", p.KeyEnv[0], resp.StatusCode)
+		}
+	default:
+		result.Status = StatusError
+		result.Detail = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+
+	return result
+}
+
+// CheckAll probes providers concurrently, returning results in order
+func CheckAll(ctx context.Context, client *http.Client, list []*Provider) []Result {
+	results := make([]Result, len(list))
+	var wg sync.WaitGroup
+	for i, p := range list {
+		wg.Add(1)
+		go func(i int, p *Provider) {
+			defer wg.Done()
+			results[i] = Check(ctx, client, p)
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// bearer probes path with an optional bearer token
+func bearer(path string) func(ctx context.Context, base, key string) (*http.Request, error) {
+	return func(ctx context.Context, base, key string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		return req, nil
+	}
+}
+
+// countModels describes a model listing by its size
+func countModels(field string) func(body []byte) string {
+	return func(body []byte) string {
+		var listing map[string]json.RawMessage
+		if err := json.Unmarshal(body, &listing); err != nil {
+			return ""
+		}
+		var models []json.RawMessage
+		if err := json.Unmarshal(listing[field], &models); err != nil {
+			return ""
+		}
+		return fmt.Sprintf("%d model(s)", len(models))
+	}
+}
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func find(name string) *Provider {
+	for _, p := range Defaults() {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer good":
+			w.Write([]byte(`{"data":[{"id":"a"},{"id":"b"}]}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	p := find("openai")
+	p.BaseURL = server.URL
+
+	t.Setenv("OPENAI_API_KEY", "good")
+	r := Check(context.Background(), server.Client(), p)
+	if r.Status != StatusOK || r.Detail != "2 model(s)" || r.Latency <= 0 {
+		t.Errorf("Expected ok with 2 models, got %+v", r)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "bad")
+	if r := Check(context.Background(), server.Client(), p); r.Status != StatusUnauthorized {
+		t.Errorf("Expected unauthorized, got %+v", r)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "")
+	if r := Check(context.Background(), server.Client(), p); r.Status != StatusNoKey {
+		t.Errorf("Expected no key, got %+v", r)
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	p := find("ollama")
+	p.BaseURL = url
+	if r := Check(context.Background(), http.DefaultClient, p); r.Status != StatusUnreachable {
+		t.Errorf("Expected unreachable, got %+v", r)
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"models":[{"name":"qwen2.5-coder:7b"}]}`))
+	}))
+	defer server.Close()
+
+	ollama, google := find("ollama"), find("google")
+	ollama.BaseURL, google.BaseURL = server.URL, server.URL
+
+	results := CheckAll(context.Background(), server.Client(), []*Provider{ollama, google})
+	if results[0].Provider != "ollama" || results[0].Status != StatusOK || results[0].Detail != "1 model(s)" {
+		t.Errorf("Unexpected ollama result %+v", results[0])
+	}
+	if results[1].Status != StatusError || results[1].Detail != "HTTP 500" {
+		t.Errorf("Unexpected google result %+v", results[1])
+	}
+}
+
+func TestOllamaURL(t *testing.T) {
+	tests := map[string]string{
+		"":                       "http://127.0.0.1:11434",
+		"0.0.0.0:11434":          "http://0.0.0.0:11434",
+		"https://gpu.local:443/": "https://gpu.local:443",
+	}
+	for host, want := range tests {
+		t.Setenv("OLLAMA_HOST", host)
+		if got := OllamaURL(); got != want {
+			t.Errorf("OllamaURL with %q = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package ratelimit spaces out agent requests so fan-out spawns and retry
+// loops stay within provider quotas.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Window is the period limits are counted over
+const Window = time.Minute
+
+const defaultMaxWait = 10 * time.Minute
+
+// Store records requests across gforge processes
+type Store interface {
+	AcquireRequest(key string, limit int, window time.Duration, now time.Time) (time.Duration, error)
+	ReleaseRequest(key string, madeAt time.Time) error
+}
+
+// Limiter enforces requests-per-minute limits by agent and provider
+type Limiter struct {
+	store   Store
+	limits  map[string]int
+	maxWait time.Duration
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// Rule is a configured limit on one agent or provider
+type Rule struct {
+	Key       string
+	PerMinute int
+}
+
+// New creates a limiter. limits maps agent or provider names to requests
+// per minute; maxWait bounds how long Wait queues (0 for the default).
+func New(store Store, limits map[string]int, maxWait time.Duration) *Limiter {
+	if maxWait <= 0 {
+		maxWait = defaultMaxWait
+	}
+	return &Limiter{store: store, limits: limits, maxWait: maxWait, sleep: sleep}
+}
+
+// Limits returns the configured limits among keys, in the order given
+func (l *Limiter) Limits(keys ...string) []Rule {
+	var rules []Rule
+	for _, k := range keys {
+		if n, ok := l.limits[k]; ok && n > 0 {
+			rules = append(rules, Rule{Key: k, PerMinute: n})
+		}
+	}
+	return rules
+}
+
+// Wait blocks until a request is allowed under every limit found for
+// keys, such as both the agent's and its provider's. A request counts
+// against all of them or, while any is exhausted, none. onThrottle is
+// called once, with the limit and the expected wait, if the request has
+// to queue. Keys without a limit return immediately.
+func (l *Limiter) Wait(ctx context.Context, onThrottle func(key string, wait time.Duration), keys ...string) error {
+	rules := l.Limits(keys...)
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		blocked, wait, err := l.acquire(rules, time.Now())
+		if err != nil {
+			return err
+		}
+		if wait == 0 {
+			return nil
+		}
+		if attempt == 0 && onThrottle != nil {
+			onThrottle(blocked.Key, wait)
+		}
+		// Jitter spreads out processes waiting on the same slot
+		wait += Backoff(attempt, 250*time.Millisecond, 5*time.Second)
+		if waited+wait > l.maxWait {
+			return fmt.Errorf("rate limit for %s (%d/min) still exhausted after %s", blocked.Key, blocked.PerMinute, waited.Round(time.Second))
+		}
+		if err := l.sleep(ctx, wait); err != nil {
+			return err
+		}
+		waited += wait
+	}
+}
+
+// acquire counts a request made at now against every rule. When one is
+// exhausted, or the store fails, the ones already counted are released
+// and the exhausted rule returned with how long until it frees up.
+func (l *Limiter) acquire(rules []Rule, now time.Time) (Rule, time.Duration, error) {
+	for i, r := range rules {
+		wait, err := l.store.AcquireRequest(r.Key, r.PerMinute, Window, now)
+		if err == nil && wait == 0 {
+			continue
+		}
+		for _, taken := range rules[:i] {
+			if rerr := l.store.ReleaseRequest(taken.Key, now); rerr != nil && err == nil {
+				err = rerr
+			}
+		}
+		return r, wait, err
+	}
+	return Rule{}, 0, nil
+}
+
+// Backoff returns a jittered exponential delay for a retry attempt
+// (starting at 0): a random duration up to base*2^attempt, capped at max
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeStore allows the first free requests, then asks callers to wait
+type fakeStore struct {
+	free     int
+	calls    int
+	keys     []string
+	released []string
+}
+
+func (f *fakeStore) AcquireRequest(key string, limit int, window time.Duration, now time.Time) (time.Duration, error) {
+	f.calls++
+	f.keys = append(f.keys, key)
+	if f.free > 0 {
+		f.free--
+		return 0, nil
+	}
+	f.free = 1 // A slot opens after one wait
+	return time.Second, nil
+}
+
+func (f *fakeStore) ReleaseRequest(key string, madeAt time.Time) error {
+	f.released = append(f.released, key)
+	return nil
+}
+
+func TestWait(t *testing.T) {
+	store := &fakeStore{free: 1}
+	l := New(store, map[string]int{"anthropic": 10}, time.Minute)
+	var slept time.Duration
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		slept += d
+		return nil
+	}
+
+	if err := l.Wait(context.Background(), nil, "claude", "anthropic"); err != nil || slept != 0 {
+		t.Fatalf("Expected an immediate request, got %v after %s", err, slept)
+	}
+
+	var throttled []string
+	err := l.Wait(context.Background(), func(key string, wait time.Duration) {
+		throttled = append(throttled, key)
+	}, "claude", "anthropic")
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if len(throttled) != 1 || throttled[0] != "anthropic" {
+		t.Errorf("Expected one throttle notice for anthropic, got %v", throttled)
+	}
+	if slept < time.Second {
+		t.Errorf("Expected to wait at least 1s, slept %s", slept)
+	}
+	for _, k := range store.keys {
+		if k != "anthropic" {
+			t.Errorf("Expected requests counted against the provider, got %s", k)
+		}
+	}
+
+	calls := store.calls
+	if err := l.Wait(context.Background(), nil, "gemini", "google"); err != nil || store.calls != calls {
+		t.Error("Expected unlimited keys to skip the store")
+	}
+}
+
+func TestWaitMaxWait(t *testing.T) {
+	l := New(&fakeStore{}, map[string]int{"openai": 1}, 500*time.Millisecond)
+	l.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	if err := l.Wait(context.Background(), nil, "openai"); err == nil {
+		t.Error("Expected an error when the wait exceeds maxWait")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	l := New(nil, map[string]int{"codex": 5, "openai": 20}, 0)
+	rules := l.Limits("codex", "openai")
+	if len(rules) != 2 || rules[0] != (Rule{"codex", 5}) || rules[1] != (Rule{"openai", 20}) {
+		t.Errorf("Expected both the agent and provider limits, got %v", rules)
+	}
+	if rules := l.Limits("other", "openai"); len(rules) != 1 || rules[0].Key != "openai" {
+		t.Errorf("Expected the provider limit, got %v", rules)
+	}
+}
+
+// keyedStore reports each key in busy full once
+type keyedStore struct {
+	fakeStore
+	busy map[string]bool
+}
+
+func (k *keyedStore) AcquireRequest(key string, limit int, window time.Duration, now time.Time) (time.Duration, error) {
+	k.keys = append(k.keys, key)
+	if k.busy[key] {
+		k.busy[key] = false
+		return time.Second, nil
+	}
+	return 0, nil
+}
+
+func TestWaitEveryLimit(t *testing.T) {
+	// The agent's limit has room, the provider's is spent once
+	store := &keyedStore{busy: map[string]bool{"openai": true}}
+	l := New(store, map[string]int{"codex": 5, "openai": 20}, time.Minute)
+	l.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	var throttled []string
+	err := l.Wait(context.Background(), func(key string, wait time.Duration) {
+		throttled = append(throttled, key)
+	}, "codex", "openai")
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if len(throttled) != 1 || throttled[0] != "openai" {
+		t.Errorf("Expected the provider limit to throttle, got %v", throttled)
+	}
+	want := []string{"codex", "openai", "codex", "openai"}
+	if len(store.keys) != len(want) {
+		t.Fatalf("Expected both limits acquired on each attempt, got %v", store.keys)
+	}
+	for i, k := range want {
+		if store.keys[i] != k {
+			t.Errorf("Expected %v acquired, got %v", want, store.keys)
+			break
+		}
+	}
+	if len(store.released) != 1 || store.released[0] != "codex" {
+		t.Errorf("Expected the agent's request released while the provider was full, got %v", store.released)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := Backoff(attempt, 100*time.Millisecond, time.Second)
+		if d < 0 || d > time.Second {
+			t.Errorf("Backoff(%d) = %s, want within [0, 1s]", attempt, d)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Package recording writes and plays back terminal sessions in the
+// asciinema v2 (.cast) format.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// Header is the first line of a .cast file
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is a single chunk of terminal output
+type Event struct {
+	Time float64 // Seconds since the start of the recording
+	Type string  // "o" for output
+	Data string
+}
+
+// MarshalJSON encodes an event as asciinema's [time, type, data] triple
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Time, e.Type, e.Data})
+}
+
+// UnmarshalJSON decodes an asciinema [time, type, data] triple
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("invalid event: expected 3 fields, got %d", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &e.Time); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw[1], &e.Type); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[2], &e.Data)
+}
+
+// Cast is a loaded recording
+type Cast struct {
+	Header Header
+	Events []Event
+}
+
+// Record copies terminal output from r to w as a .cast stream, stamping
+// each chunk with the time it arrived. It returns when r is exhausted.
+func Record(r io.Reader, w io.Writer, header Header) error {
+	header.Version = 2
+	if header.Timestamp == 0 {
+		header.Timestamp = time.Now().Unix()
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	start := time.Now()
+	buf := make([]byte, 32*1024)
+	var pending []byte
+
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			pending = append(pending, buf[:n]...)
+
+			// Hold back a trailing partial UTF-8 sequence for the next read
+			cut := validPrefix(pending)
+			if cut > 0 {
+				event := Event{Time: time.Since(start).Seconds(), Type: "o", Data: string(pending[:cut])}
+				if err := enc.Encode(event); err != nil {
+					return fmt.Errorf("failed to write event: %w", err)
+				}
+				pending = append(pending[:0], pending[cut:]...)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(pending) > 0 {
+		event := Event{Time: time.Since(start).Seconds(), Type: "o", Data: string(pending)}
+		return enc.Encode(event)
+	}
+	return nil
+}
+
+// validPrefix returns the length of b without a trailing incomplete
+// UTF-8 sequence
+func validPrefix(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(b)
+}
+
+// Load reads a .cast file. Truncated trailing lines, as left by a session
+// that was killed mid-write, are ignored.
+func Load(path string) (*Cast, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("recording %s is empty", path)
+	}
+
+	var cast Cast
+	if err := json.Unmarshal(scanner.Bytes(), &cast.Header); err != nil {
+		return nil, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if cast.Header.Version != 2 {
+		return nil, fmt.Errorf("unsupported recording version: %d", cast.Header.Version)
+	}
+
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			break
+		}
+		cast.Events = append(cast.Events, event)
+	}
+
+	return &cast, scanner.Err()
+}
+
+// Duration returns the time of the last event
+func (c *Cast) Duration() time.Duration {
+	if len(c.Events) == 0 {
+		return 0
+	}
+	return time.Duration(c.Events[len(c.Events)-1].Time * float64(time.Second))
+}
+
+// Play writes output events to w in real time. speed scales playback and
+// idleLimit caps pauses between events (0 = no cap).
+func (c *Cast) Play(w io.Writer, speed float64, idleLimit time.Duration) error {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	last := 0.0
+	for _, event := range c.Events {
+		delay := time.Duration((event.Time - last) / speed * float64(time.Second))
+		if idleLimit > 0 && delay > idleLimit {
+			delay = idleLimit
+		}
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		last = event.Time
+
+		if event.Type != "o" {
+			continue
+		}
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
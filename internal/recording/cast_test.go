@@ -0,0 +1,94 @@
+package recording
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	var buf bytes.Buffer
+	input := strings.NewReader("hello\r\nworld\r\n")
+	if err := Record(input, &buf, Header{Width: 80, Height: 24, Title: "test"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.cast")
+	os.WriteFile(path, buf.Bytes(), 0644)
+
+	cast, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cast.Header.Version != 2 || cast.Header.Width != 80 || cast.Header.Title != "test" {
+		t.Errorf("Unexpected header: %+v", cast.Header)
+	}
+
+	var out bytes.Buffer
+	if err := cast.Play(&out, 1, 0); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if out.String() != "hello\r\nworld\r\n" {
+		t.Errorf("Unexpected playback: %q", out.String())
+	}
+}
+
+func TestLoadTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.cast")
+	content := `{"version":2,"width":80,"height":24}
+[0.1,"o","a"]
+[0.2,"o","b"]
+[0.3,"o","c`
+	os.WriteFile(path, []byte(content), 0644)
+
+	cast, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cast.Events) != 2 {
+		t.Errorf("Expected 2 events, got %d", len(cast.Events))
+	}
+	if cast.Duration().Seconds() != 0.2 {
+		t.Errorf("Expected 0.2s duration, got %s", cast.Duration())
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	empty := filepath.Join(dir, "empty.cast")
+	os.WriteFile(empty, nil, 0644)
+	if _, err := Load(empty); err == nil {
+		t.Error("Expected error for empty recording")
+	}
+
+	v1 := filepath.Join(dir, "v1.cast")
+	os.WriteFile(v1, []byte(`{"version":1}`+"\n"), 0644)
+	if _, err := Load(v1); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+}
+
+func TestValidPrefix(t *testing.T) {
+	euro := []byte("€") // 3 bytes
+
+	tests := []struct {
+		input    []byte
+		expected int
+	}{
+		{[]byte("abc"), 3},
+		{append([]byte("ab"), euro[:1]...), 2},
+		{append([]byte("ab"), euro[:2]...), 2},
+		{append([]byte("ab"), euro...), 5},
+		{nil, 0},
+	}
+
+	for _, tc := range tests {
+		if got := validPrefix(tc.input); got != tc.expected {
+			t.Errorf("validPrefix(%q) = %d, want %d", tc.input, got, tc.expected)
+		}
+	}
+}
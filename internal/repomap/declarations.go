@@ -0,0 +1,542 @@
+package repomap
+
+import "strings"
+
+// symbolList collects symbol names once each, in the order found
+type symbolList struct {
+	names []string
+	seen  map[string]bool
+}
+
+func (s *symbolList) add(name string) {
+	if name == "" || s.seen[name] {
+		return
+	}
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	s.seen[name] = true
+	s.names = append(s.names, name)
+}
+
+// tokenAt returns toks[i], or an empty token out of range
+func tokenAt(toks []lexeme, i int) lexeme {
+	if i < 0 || i >= len(toks) {
+		return lexeme{}
+	}
+	return toks[i]
+}
+
+// nesting returns how a token changes the bracket depth
+func nesting(t lexeme) int {
+	if t.kind != tokPunct {
+		return 0
+	}
+	switch t.text {
+	case "(", "[", "{":
+		return 1
+	case ")", "]", "}":
+		return -1
+	}
+	return 0
+}
+
+// closing returns the index after the bracket closing the one at toks[i]
+func closing(toks []lexeme, i int) int {
+	depth := 0
+	for ; i < len(toks); i++ {
+		depth += nesting(toks[i])
+		if depth <= 0 {
+			return i + 1
+		}
+	}
+	return len(toks)
+}
+
+// pythonSymbols lists public top-level functions and classes, and the
+// public methods of those classes as Class.method. A leading _ marks a
+// name private.
+func pythonSymbols(src []byte) []string {
+	toks := lex(src, pythonSyntax)
+
+	var (
+		symbols symbolList
+		depth   int
+		class   string // The public top-level class being read
+		bodyCol = -1   // Indentation of its body
+	)
+	for i, t := range toks {
+		// A statement starts a line outside brackets and continuations
+		start := t.first && depth == 0 && tokenAt(toks, i-1).text != `\`
+		depth = max(depth+nesting(t), 0)
+		if !start {
+			continue
+		}
+
+		kind, name := pythonDecl(toks, i)
+		public := name != "" && !strings.HasPrefix(name, "_")
+		switch {
+		case t.col == 0:
+			class, bodyCol = "", -1
+			if public {
+				symbols.add(name)
+				if kind == "class" {
+					class = name
+				}
+			}
+		case class != "":
+			if bodyCol < 0 {
+				bodyCol = t.col
+			}
+			if t.col == bodyCol && kind == "def" && public {
+				symbols.add(class + "." + name)
+			}
+		}
+	}
+	return symbols.names
+}
+
+// pythonDecl returns the def or class statement at toks[i] and its name
+func pythonDecl(toks []lexeme, i int) (kind, name string) {
+	if toks[i].text == "async" {
+		i++
+	}
+	kind = tokenAt(toks, i).text
+	if next := tokenAt(toks, i+1); (kind == "def" || kind == "class") && next.kind == tokIdent {
+		return kind, next.text
+	}
+	return "", ""
+}
+
+// jsDeclarations are the keywords an export declaration names a symbol
+// with
+var jsDeclarations = map[string]bool{
+	"function": true, "class": true, "interface": true, "type": true, "enum": true,
+	"const": true, "let": true, "var": true, "namespace": true, "module": true,
+}
+
+// jsSymbols lists what a JavaScript or TypeScript module exports: export
+// declarations and lists at the top level, and CommonJS exports
+func jsSymbols(src []byte) []string {
+	toks := lex(src, jsSyntax)
+
+	var symbols symbolList
+	depth := 0
+	for i, t := range toks {
+		if depth == 0 && t.kind == tokIdent && tokenAt(toks, i-1).text != "." {
+			switch {
+			case t.text == "export":
+				jsExport(toks, i+1, &symbols)
+			case t.text == "exports":
+				jsAssignment(toks, i+1, &symbols)
+			case t.text == "module" && tokenAt(toks, i+1).text == "." && tokenAt(toks, i+2).text == "exports":
+				jsAssignment(toks, i+3, &symbols)
+			}
+		}
+		depth = max(depth+nesting(t), 0)
+	}
+	return symbols.names
+}
+
+// jsExport reads the export statement whose keyword precedes toks[i]
+func jsExport(toks []lexeme, i int, symbols *symbolList) {
+	for {
+		switch tokenAt(toks, i).text {
+		case "default", "declare", "abstract", "async":
+			i++
+			continue
+		}
+		break
+	}
+
+	kind := tokenAt(toks, i)
+	switch {
+	case kind.text == "{":
+		jsList(toks, i, symbols)
+		return
+	case kind.text == "*":
+		// export * as ns from "..."
+		if tokenAt(toks, i+1).text == "as" {
+			symbols.add(tokenAt(toks, i+2).text)
+		}
+		return
+	case kind.kind != tokIdent || !jsDeclarations[kind.text]:
+		return
+	}
+
+	i++
+	switch next := tokenAt(toks, i); {
+	case kind.text == "type" && next.text == "{":
+		jsList(toks, i, symbols)
+		return
+	case kind.text == "const" && next.text == "enum", kind.text == "function" && next.text == "*":
+		i++
+	}
+	if name := tokenAt(toks, i); name.kind == tokIdent && name.text != "extends" && name.text != "implements" {
+		symbols.add(name.text)
+	}
+}
+
+// jsList reads the names in an export list or a CommonJS exports object
+// starting at the { at toks[i]: the last name in each entry, so aliases
+// (a as b) and types (type T) give the exported name, and an object's
+// keys rather than their values
+func jsList(toks []lexeme, i int, symbols *symbolList) {
+	end := closing(toks, i) - 1
+
+	var (
+		name  string
+		value bool // Past the : of an object property
+		depth int
+	)
+	for i++; i < end; i++ {
+		t := toks[i]
+		switch {
+		case depth == 0 && t.text == ",":
+			symbols.add(name)
+			name, value = "", false
+		case depth == 0 && t.text == ":":
+			value = true
+		case depth == 0 && !value && t.kind == tokIdent:
+			name = t.text
+		}
+		depth += nesting(t)
+	}
+	symbols.add(name)
+}
+
+// jsAssignment reads exports.name = ... or exports = {...} after the
+// exports at toks[i-1]
+func jsAssignment(toks []lexeme, i int, symbols *symbolList) {
+	switch {
+	case tokenAt(toks, i).text == "." && tokenAt(toks, i+2).text == "=" && tokenAt(toks, i+3).text != "=":
+		symbols.add(tokenAt(toks, i+1).text)
+	case tokenAt(toks, i).text == "=" && tokenAt(toks, i+1).text == "{":
+		jsList(toks, i+1, symbols)
+	}
+}
+
+// rustItems are the keywords a Rust item is declared with
+var rustItems = map[string]bool{
+	"fn": true, "struct": true, "enum": true, "trait": true, "type": true,
+	"const": true, "static": true, "mod": true, "union": true,
+}
+
+// rustSymbols lists the pub items of a module, the pub methods of its
+// impl blocks as Type::method, and exported macros as name!. Items
+// restricted with pub(crate) and the like are left out.
+func rustSymbols(src []byte) []string {
+	toks := lex(src, rustSyntax)
+
+	var (
+		symbols symbolList
+		depth   int
+		impl    string // The type of the impl block being read
+	)
+	for i, t := range toks {
+		if t.kind == tokIdent {
+			switch {
+			case t.text == "pub" && depth == 0:
+				symbols.add(rustItem(toks, i+1))
+			case t.text == "pub" && depth == 1 && impl != "":
+				if name := rustItem(toks, i+1); name != "" {
+					symbols.add(impl + "::" + name)
+				}
+			case t.text == "impl" && depth == 0:
+				impl = rustImplType(toks, i+1)
+			case t.text == "macro_rules" && depth == 0 && tokenAt(toks, i+1).text == "!" &&
+				tokenAt(toks, i-2).text == "macro_export" && tokenAt(toks, i-1).text == "]":
+				symbols.add(tokenAt(toks, i+2).text + "!")
+			}
+		}
+
+		depth = max(depth+nesting(t), 0)
+		if depth == 0 && t.text == "}" {
+			impl = ""
+		}
+	}
+	return symbols.names
+}
+
+// rustItem returns the name of the item declared after pub at toks[i-1],
+// or "" for restricted visibility and re-exports
+func rustItem(toks []lexeme, i int) string {
+	if tokenAt(toks, i).text == "(" {
+		return ""
+	}
+
+	for {
+		switch t := tokenAt(toks, i); {
+		case t.text == "async", t.text == "unsafe", t.text == "default":
+			i++
+			continue
+		case t.text == "extern":
+			i++
+			if tokenAt(toks, i).kind == tokString {
+				i++
+			}
+			continue
+		case t.text == "const" && !rustItems[tokenAt(toks, i+1).text] || t.text == "const" && tokenAt(toks, i+1).text == "fn":
+			// const fn, const unsafe fn
+			i++
+			continue
+		}
+		break
+	}
+
+	if !rustItems[tokenAt(toks, i).text] {
+		return ""
+	}
+	i++
+	if tokenAt(toks, i).text == "mut" {
+		i++
+	}
+	if name := tokenAt(toks, i); name.kind == tokIdent {
+		return name.text
+	}
+	return ""
+}
+
+// rustImplType returns the type an impl block starting at toks[i]
+// implements methods for: the last path segment outside generic
+// arguments, after for in a trait impl
+func rustImplType(toks []lexeme, i int) string {
+	name := ""
+	angles := 0
+	for ; i < len(toks); i++ {
+		t := toks[i]
+		switch {
+		case t.text == "{" || t.text == ";" || t.text == "where" && angles == 0:
+			return name
+		case t.text == "<":
+			angles++
+		case t.text == ">" && tokenAt(toks, i-1).text != "-":
+			angles--
+		case t.text == "for" && angles == 0:
+			name = ""
+		case t.kind == tokIdent && angles == 0 && t.text != "dyn" && t.text != "mut" && t.text != "unsafe":
+			name = t.text
+		}
+	}
+	return name
+}
+
+// rubyFrame is a block open in a Ruby file
+type rubyFrame struct {
+	kind    string // class, module, singleton (class << self), def or block
+	name    string // A class or module's qualified name
+	private bool   // A bare private or protected was seen in its body
+}
+
+// rubyScopes are the frames whose defs define methods on a class
+var rubyScopes = map[string]bool{"class": true, "module": true, "singleton": true}
+
+// rubySymbols lists classes and modules by qualified name, public
+// instance methods as Class#method and class methods as Class.method.
+// Methods after a bare private or protected are left out.
+func rubySymbols(src []byte) []string {
+	toks := lex(src, rubySyntax)
+
+	var (
+		symbols     symbolList
+		stack       []*rubyFrame
+		loopLine    = -1 // The line of a while, until or for, whose do opens nothing
+		privateNext bool // private def ...
+	)
+	top := func() *rubyFrame {
+		if len(stack) == 0 {
+			return nil
+		}
+		return stack[len(stack)-1]
+	}
+	push := func(f *rubyFrame) { stack = append(stack, f) }
+	pop := func() {
+		if len(stack) > 0 {
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		prev := tokenAt(toks, i-1)
+
+		if t.kind == tokPunct {
+			switch t.text {
+			case "{":
+				push(&rubyFrame{kind: "brace"})
+			case "}":
+				if f := top(); f != nil && f.kind == "brace" {
+					pop()
+				}
+			}
+			continue
+		}
+		// Method calls (x.class, y.end) aren't keywords
+		if t.kind != tokIdent || prev.text == "." || prev.text == "::" {
+			continue
+		}
+		statement := t.first || prev.text == ";"
+
+		switch t.text {
+		case "class", "module":
+			if t.text == "class" && tokenAt(toks, i+1).text == "<" && tokenAt(toks, i+2).text == "<" {
+				push(&rubyFrame{kind: "singleton"})
+				i += 2
+				continue
+			}
+			name, n := rubyConstant(toks, i+1)
+			if name == "" {
+				continue
+			}
+			if scope := rubyScope(stack); scope != nil {
+				name = scope.name + "::" + name
+			}
+			symbols.add(name)
+			push(&rubyFrame{kind: t.text, name: name})
+			i += n
+
+		case "def":
+			name, self, n := rubyMethod(toks, i+1)
+			endless := tokenAt(toks, i+1+n).text == "=" && tokenAt(toks, i+1+n).line == t.line
+			if !endless {
+				push(&rubyFrame{kind: "def"})
+			}
+
+			private := privateNext
+			privateNext = false
+			switch owner := defOwner(stack, endless); {
+			case name == "":
+			case owner == nil:
+				symbols.add(name)
+			case owner.kind == "singleton":
+				if scope := rubyScope(stack); scope != nil && !owner.private {
+					symbols.add(scope.name + "." + name)
+				}
+			case owner.kind == "class" || owner.kind == "module":
+				if self {
+					symbols.add(owner.name + "." + name)
+				} else if !private && !owner.private && name != "initialize" {
+					symbols.add(owner.name + "#" + name)
+				}
+			}
+			i += n
+
+		case "if", "unless", "while", "until":
+			// As modifiers (x if y) they open nothing
+			if statement || prev.kind == tokPunct && !strings.Contains(")]}", prev.text) {
+				push(&rubyFrame{kind: "block"})
+				if t.text == "while" || t.text == "until" {
+					loopLine = t.line
+				}
+			}
+		case "for":
+			push(&rubyFrame{kind: "block"})
+			loopLine = t.line
+		case "case", "begin":
+			push(&rubyFrame{kind: "block"})
+		case "do":
+			if loopLine == t.line {
+				loopLine = -1
+				continue
+			}
+			push(&rubyFrame{kind: "block"})
+		case "end":
+			pop()
+
+		case "private", "protected", "public":
+			next := tokenAt(toks, i+1)
+			switch {
+			case !statement:
+			case next.kind == tokIdent && next.text == "def" && !next.first:
+				privateNext = t.text != "public"
+			case next.first || i+1 == len(toks):
+				if f := top(); f != nil && rubyScopes[f.kind] {
+					f.private = t.text != "public"
+				}
+			}
+		}
+	}
+	return symbols.names
+}
+
+// defOwner returns the frame a def was written in: the top of the stack
+// for an endless def, which pushed nothing, else the one below
+func defOwner(stack []*rubyFrame, endless bool) *rubyFrame {
+	i := len(stack) - 2
+	if endless {
+		i = len(stack) - 1
+	}
+	if i < 0 {
+		return nil
+	}
+	return stack[i]
+}
+
+// rubyScope returns the innermost class or module on the stack
+func rubyScope(stack []*rubyFrame) *rubyFrame {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if k := stack[i].kind; k == "class" || k == "module" {
+			return stack[i]
+		}
+	}
+	return nil
+}
+
+// rubyConstant reads a constant path (Foo, Foo::Bar, ::Foo) at toks[i],
+// returning it and the number of tokens read
+func rubyConstant(toks []lexeme, i int) (string, int) {
+	start := i
+	if tokenAt(toks, i).text == "::" {
+		i++
+	}
+	var parts []string
+	for tokenAt(toks, i).kind == tokIdent {
+		parts = append(parts, toks[i].text)
+		if tokenAt(toks, i+1).text != "::" {
+			i++
+			break
+		}
+		i += 2
+	}
+	return strings.Join(parts, "::"), i - start
+}
+
+// rubyMethod reads a def's name at toks[i] and its parameter list,
+// returning the name ("" for operators), whether it is defined on self,
+// and the number of tokens read
+func rubyMethod(toks []lexeme, i int) (name string, self bool, n int) {
+	start := i
+	t := tokenAt(toks, i)
+	if t.kind == tokIdent && tokenAt(toks, i+1).text == "." {
+		self = true
+		i += 2
+		t = tokenAt(toks, i)
+	}
+
+	if t.kind == tokIdent {
+		name = t.text
+		i++
+		// A setter: def name=(value)
+		if eq := tokenAt(toks, i); eq.text == "=" && eq.line == t.line && eq.col == t.col+len(t.text) {
+			name += "="
+			i++
+		}
+	} else {
+		// Operators: def ==(other), def [](key)
+		for tokenAt(toks, i).kind == tokPunct && tokenAt(toks, i).line == toks[start].line {
+			if tokenAt(toks, i).text == "(" && i > start {
+				break
+			}
+			if tokenAt(toks, i).text == "[" {
+				i += 2
+				continue
+			}
+			i++
+		}
+	}
+
+	if p := tokenAt(toks, i); p.text == "(" && p.line == toks[start].line {
+		i = closing(toks, i)
+	}
+	return name, self, i - start
+}
@@ -0,0 +1,74 @@
+package repomap
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// File is a file in a repository
+type File struct {
+	Path    string // Slash-separated, relative to the repository root
+	Size    int64
+	Symbols []string
+}
+
+// ListFiles returns tracked files under paths (everything when empty),
+// sorted by path. Outside git, every non-hidden file is listed.
+func ListFiles(root string, paths []string) ([]File, error) {
+	var names []string
+
+	args := append([]string{"-C", root, "ls-files", "--"}, paths...)
+	if output, err := trace.Command("git", args...).Output(); err == nil {
+		names = strings.Split(strings.TrimSpace(string(output)), "\n")
+	} else {
+		names = walk(root, paths)
+	}
+
+	var files []File
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(root, name))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, File{Path: name, Size: info.Size()})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// walk lists non-hidden files under paths for projects without git
+func walk(root string, paths []string) []string {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	var names []string
+	for _, p := range paths {
+		start := filepath.Join(root, p)
+		filepath.Walk(start, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if path != start && strings.HasPrefix(info.Name(), ".") {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.IsDir() {
+				rel, _ := filepath.Rel(root, path)
+				names = append(names, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+	}
+	return names
+}
@@ -0,0 +1,461 @@
+package repomap
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokPunct
+	tokString
+)
+
+// lexeme is a lexed identifier, punctuation mark or literal. Comments are
+// dropped and literals keep only their opening quote, so declarations
+// written inside them are never mistaken for code.
+type lexeme struct {
+	kind  tokenKind
+	text  string
+	line  int  // Offset of the start of the token's line
+	col   int  // Byte column
+	first bool // First token on its line
+}
+
+// syntax describes the lexical rules of one language: enough to find
+// where comments and literals start and end
+type syntax struct {
+	lineComment  string
+	blockComment [2]string // Rust's nest
+	nestedBlocks bool
+	quotes       string // Characters opening a string literal
+	multiline    bool   // Plain strings may span lines
+	interpolate  string // Opens an expression inside a string, e.g. ${
+	interpQuotes string // Quotes that interpolate
+	identChars   string // Beyond letters, digits and _
+	triple       bool   // Python's ''' and """
+	regexps      bool   // JavaScript's /.../ literals
+	rust         bool   // Raw strings, lifetimes and char literals
+	ruby         bool   // Heredocs, =begin comments, %w() literals, method names ending ? or !
+}
+
+var (
+	pythonSyntax = &syntax{lineComment: "#", quotes: `"'`, triple: true}
+	jsSyntax     = &syntax{lineComment: "//", blockComment: [2]string{"/*", "*/"}, quotes: "\"'`",
+		interpolate: "${", interpQuotes: "`", identChars: "$", regexps: true}
+	rustSyntax = &syntax{lineComment: "//", blockComment: [2]string{"/*", "*/"}, nestedBlocks: true,
+		quotes: `"'`, multiline: true, rust: true}
+	rubySyntax = &syntax{lineComment: "#", quotes: "\"'`", multiline: true,
+		interpolate: "#{", interpQuotes: "\"`", ruby: true}
+)
+
+// heredocPattern matches the opener of a Ruby heredoc: <<ID, <<-ID, <<~ID
+var heredocPattern = regexp.MustCompile("^<<([~-]?)([\"'`]?)([A-Za-z_][A-Za-z0-9_]*)([\"'`]?)")
+
+// regexpAfter are keywords after which a JavaScript / starts a regexp
+var regexpAfter = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true, "of": true,
+	"new": true, "delete": true, "void": true, "throw": true, "yield": true, "await": true, "instanceof": true,
+}
+
+// heredoc is a Ruby heredoc whose body starts on the next line
+type heredoc struct {
+	id       string
+	indented bool // <<- and <<~ allow the terminator to be indented
+}
+
+type lexer struct {
+	syn       *syntax
+	src       []byte
+	pos       int
+	lineStart int
+	heredocs  []heredoc
+	tokens    []lexeme
+}
+
+// lex splits src into tokens under syn
+func lex(src []byte, syn *syntax) []lexeme {
+	l := &lexer{syn: syn, src: src}
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.pos++
+			l.lineStart = l.pos
+			l.skipHeredocs()
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			l.pos++
+		case l.at(syn.lineComment):
+			l.skipLine()
+		case syn.blockComment[0] != "" && l.at(syn.blockComment[0]):
+			l.skipBlockComment()
+		case syn.ruby && l.pos == l.lineStart && l.at("=begin"):
+			l.skipRubyComment()
+		case isIdentStart(c) || strings.IndexByte(syn.identChars, c) >= 0:
+			l.ident()
+		case c >= '0' && c <= '9':
+			start := l.pos
+			for l.pos < len(l.src) && (isIdentPart(l.src[l.pos]) || l.src[l.pos] == '.') {
+				l.pos++
+			}
+			l.emit(tokIdent, start)
+		case strings.IndexByte(syn.quotes, c) >= 0:
+			l.quoted()
+		default:
+			l.punct()
+		}
+	}
+	return l.tokens
+}
+
+func (l *lexer) at(prefix string) bool {
+	return prefix != "" && bytes.HasPrefix(l.src[l.pos:], []byte(prefix))
+}
+
+// emit records the token from start to the current position
+func (l *lexer) emit(kind tokenKind, start int) {
+	lineStart := bytes.LastIndexByte(l.src[:start], '\n') + 1
+	first := len(l.tokens) == 0 || l.tokens[len(l.tokens)-1].line != lineStart
+	text := string(l.src[start:l.pos])
+	if kind == tokString {
+		text = text[:1]
+	}
+	l.tokens = append(l.tokens, lexeme{kind: kind, text: text, line: lineStart, col: start - lineStart, first: first})
+}
+
+func (l *lexer) prev() *lexeme {
+	if len(l.tokens) == 0 {
+		return nil
+	}
+	return &l.tokens[len(l.tokens)-1]
+}
+
+func (l *lexer) skipLine() {
+	if i := bytes.IndexByte(l.src[l.pos:], '\n'); i >= 0 {
+		l.pos += i
+	} else {
+		l.pos = len(l.src)
+	}
+}
+
+func (l *lexer) skipBlockComment() {
+	open, close := l.syn.blockComment[0], l.syn.blockComment[1]
+	depth := 0
+	for l.pos < len(l.src) {
+		switch {
+		case l.at(open) && (depth == 0 || l.syn.nestedBlocks):
+			depth++
+			l.pos += len(open)
+		case l.at(close):
+			depth--
+			l.pos += len(close)
+			if depth == 0 {
+				return
+			}
+		default:
+			l.pos++
+		}
+	}
+}
+
+// skipRubyComment skips an =begin ... =end block
+func (l *lexer) skipRubyComment() {
+	for l.pos < len(l.src) {
+		l.skipLine()
+		if l.pos < len(l.src) {
+			l.pos++
+			l.lineStart = l.pos
+		}
+		if l.at("=end") {
+			l.skipLine()
+			return
+		}
+	}
+}
+
+func (l *lexer) ident() {
+	start := l.pos
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c >= utf8.RuneSelf {
+			_, size := utf8.DecodeRune(l.src[l.pos:])
+			l.pos += size
+			continue
+		}
+		if !isIdentPart(c) && strings.IndexByte(l.syn.identChars, c) < 0 {
+			break
+		}
+		l.pos++
+	}
+	word := string(l.src[start:l.pos])
+
+	// Ruby method names may end in ? or ! (but not != or ?: spacing)
+	if l.syn.ruby && l.pos < len(l.src) && (l.src[l.pos] == '?' || l.src[l.pos] == '!') &&
+		(l.pos+1 >= len(l.src) || l.src[l.pos+1] != '=') {
+		l.pos++
+	}
+
+	// Rust raw strings: r"...", r#"..."#, br"..."
+	if l.syn.rust && (word == "r" || word == "br") && l.pos < len(l.src) && (l.src[l.pos] == '"' || l.src[l.pos] == '#') {
+		hashes := 0
+		for l.pos+hashes < len(l.src) && l.src[l.pos+hashes] == '#' {
+			hashes++
+		}
+		if l.pos+hashes < len(l.src) && l.src[l.pos+hashes] == '"' {
+			end := []byte("\"" + strings.Repeat("#", hashes))
+			l.pos += hashes + 1
+			if i := bytes.Index(l.src[l.pos:], end); i >= 0 {
+				l.pos += i + len(end)
+			} else {
+				l.pos = len(l.src)
+			}
+			l.emit(tokString, start)
+			return
+		}
+	}
+
+	l.emit(tokIdent, start)
+}
+
+func (l *lexer) quoted() {
+	start := l.pos
+	c := l.src[l.pos]
+
+	if l.syn.rust && c == '\'' {
+		// A lifetime ('a) unless it closes like a char literal ('a', '\n')
+		_, size := utf8.DecodeRune(l.src[l.pos+1:])
+		if l.pos+1 < len(l.src) && l.src[l.pos+1] != '\\' &&
+			(l.pos+1+size >= len(l.src) || l.src[l.pos+1+size] != '\'') {
+			l.pos++
+			for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+				l.pos++
+			}
+			l.emit(tokPunct, start)
+			return
+		}
+	}
+
+	if l.syn.triple && l.at(strings.Repeat(string(c), 3)) {
+		l.pos += 3
+		l.skipString(string([]byte{c, c, c}), true, false)
+	} else {
+		l.pos++
+		l.skipString(string(c), l.syn.multiline || c == '`',
+			l.syn.interpolate != "" && strings.IndexByte(l.syn.interpQuotes, c) >= 0)
+	}
+	l.emit(tokString, start)
+}
+
+// skipString moves past the closing quote of a literal whose opening
+// quote has been read. Interpolated expressions are skipped with the
+// literals nested in them; a single-line string ends at the line's end
+// when it isn't closed.
+func (l *lexer) skipString(quote string, multiline, interpolates bool) {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '\\':
+			l.pos += 2
+		case l.at(quote):
+			l.pos += len(quote)
+			return
+		case c == '\n' && !multiline:
+			return
+		case interpolates && l.at(l.syn.interpolate):
+			l.pos += len(l.syn.interpolate)
+			l.skipInterpolation()
+		default:
+			l.pos++
+		}
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+}
+
+// skipInterpolation moves past the } closing an interpolated expression
+func (l *lexer) skipInterpolation() {
+	depth := 1
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				l.pos++
+				return
+			}
+		case strings.IndexByte(l.syn.quotes, c) >= 0:
+			l.pos++
+			l.skipString(string(c), true, strings.IndexByte(l.syn.interpQuotes, c) >= 0)
+			continue
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) punct() {
+	start := l.pos
+	c := l.src[l.pos]
+
+	switch {
+	case (l.syn.rust || l.syn.ruby) && l.at("::"):
+		l.pos += 2
+		l.emit(tokPunct, start)
+		return
+	case l.syn.regexps && c == '/' && l.regexpAllowed() && l.skipRegexp():
+		l.emit(tokString, start)
+		return
+	case l.syn.ruby && c == '<':
+		if h, n := l.heredocAt(); n > 0 {
+			l.heredocs = append(l.heredocs, h)
+			l.pos += n
+			l.emit(tokString, start)
+			return
+		}
+	case l.syn.ruby && c == '%' && l.skipPercentLiteral():
+		l.emit(tokString, start)
+		return
+	}
+
+	l.pos++
+	l.emit(tokPunct, start)
+}
+
+// regexpAllowed reports whether a / here starts a regexp rather than
+// dividing: it follows an operator, an opening bracket or a keyword
+func (l *lexer) regexpAllowed() bool {
+	prev := l.prev()
+	switch {
+	case prev == nil:
+		return true
+	case prev.kind == tokPunct:
+		return !strings.Contains(")]}", prev.text)
+	case prev.kind == tokIdent:
+		return regexpAfter[prev.text]
+	}
+	return false
+}
+
+// skipRegexp moves past a regexp literal, reporting false (and moving
+// nothing) when the line ends first
+func (l *lexer) skipRegexp() bool {
+	inClass := false
+	for i := l.pos + 1; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '\\':
+			i++
+		case '\n':
+			return false
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				i++
+				for i < len(l.src) && isIdentPart(l.src[i]) {
+					i++
+				}
+				l.pos = i
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// skipPercentLiteral moves past a Ruby %w[], %i(), %q{} or %() literal.
+// After a value the % is the modulo operator instead.
+func (l *lexer) skipPercentLiteral() bool {
+	if prev := l.prev(); prev != nil && (prev.kind != tokPunct || strings.Contains(")]}", prev.text)) {
+		return false
+	}
+
+	i := l.pos + 1
+	if i < len(l.src) && strings.IndexByte("qQwWiIrsx", l.src[i]) >= 0 {
+		i++
+	}
+	if i >= len(l.src) {
+		return false
+	}
+	open := l.src[i]
+	close := map[byte]byte{'(': ')', '[': ']', '{': '}', '<': '>', '|': '|', '!': '!', '/': '/'}[open]
+	if close == 0 {
+		return false
+	}
+
+	depth := 0
+	for i++; i < len(l.src); i++ {
+		switch c := l.src[i]; {
+		case c == '\\':
+			i++
+		case c == close && depth == 0:
+			l.pos = i + 1
+			return true
+		case c == close:
+			depth--
+		case c == open:
+			depth++
+		}
+	}
+	l.pos = len(l.src)
+	return true
+}
+
+// heredocAt returns the Ruby heredoc opened here and the opener's
+// length, or 0 when this is a shift: nothing follows the << directly, or
+// no later line ends the heredoc
+func (l *lexer) heredocAt() (heredoc, int) {
+	m := heredocPattern.FindSubmatch(l.src[l.pos:])
+	if m == nil || string(m[2]) != string(m[4]) {
+		return heredoc{}, 0
+	}
+	h := heredoc{id: string(m[3]), indented: len(m[1]) > 0}
+
+	rest := l.src[l.pos:]
+	if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+		for _, line := range strings.Split(string(rest[i+1:]), "\n") {
+			if line == h.id || (h.indented && strings.TrimSpace(line) == h.id) {
+				return h, len(m[0])
+			}
+		}
+	}
+	return heredoc{}, 0
+}
+
+// skipHeredocs skips the bodies of heredocs opened on the line just ended
+func (l *lexer) skipHeredocs() {
+	for len(l.heredocs) > 0 {
+		h := l.heredocs[0]
+		l.heredocs = l.heredocs[1:]
+		for l.pos < len(l.src) {
+			end := bytes.IndexByte(l.src[l.pos:], '\n')
+			if end < 0 {
+				end = len(l.src) - l.pos
+			}
+			line := string(l.src[l.pos : l.pos+end])
+			l.pos += end
+			if l.pos < len(l.src) {
+				l.pos++
+			}
+			l.lineStart = l.pos
+			if line == h.id || (h.indented && strings.TrimSpace(line) == h.id) {
+				break
+			}
+		}
+	}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= utf8.RuneSelf
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,74 @@
+package repomap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// goLoadTimeout bounds listing a project's Go packages
+const goLoadTimeout = 30 * time.Second
+
+// goPackage is a Go package as the go command builds it here
+type goPackage struct {
+	path  string          // Import path
+	files map[string]bool // Absolute paths of the files in this platform's build
+}
+
+// loadGoPackages lists the Go packages under root with go/packages, keyed
+// by slash-separated directory relative to root. Files that build
+// constraints leave out of this platform's build, and those of nested
+// modules, aren't in them. Modules are not downloaded. It fails outside a
+// Go module or without a Go toolchain.
+func loadGoPackages(root string) (map[string]*goPackage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), goLoadTimeout)
+	defer cancel()
+
+	pkgs, err := packages.Load(&packages.Config{
+		Context: ctx,
+		Dir:     root,
+		Env:     append(os.Environ(), "GOPROXY=off"),
+		Mode:    packages.NeedName | packages.NeedFiles,
+	}, "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, _ := filepath.EvalSymlinks(root)
+	byDir := make(map[string]*goPackage)
+	for _, p := range pkgs {
+		all := append(append([]string{}, p.GoFiles...), p.IgnoredFiles...)
+		if len(all) == 0 {
+			continue
+		}
+		dir := relativeDir(filepath.Dir(all[0]), root, resolved)
+		if dir == "" {
+			continue
+		}
+
+		pkg := &goPackage{path: p.PkgPath, files: make(map[string]bool)}
+		for _, f := range p.GoFiles {
+			pkg.files[filepath.Join(root, dir, filepath.Base(f))] = true
+		}
+		byDir[filepath.ToSlash(dir)] = pkg
+	}
+	return byDir, nil
+}
+
+// relativeDir returns dir relative to root, which the go command may
+// report with symlinks resolved, or "" when it is outside root
+func relativeDir(dir, root, resolved string) string {
+	for _, base := range []string{root, resolved} {
+		if base == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(base, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return rel
+		}
+	}
+	return ""
+}
@@ -0,0 +1,194 @@
+// Package repomap builds a compact overview of a repository for agents:
+// its packages, files, sizes and public symbols. Go packages are loaded
+// with go/packages, so import paths and build constraints are the go
+// command's; Python, JavaScript/TypeScript, Rust and Ruby files are
+// lexed and their declarations read by nesting.
+package repomap
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+)
+
+const (
+	maxSymbolsPerFile = 12
+	maxParseSize      = 512 * 1024
+)
+
+// languages names directories by their dominant file extension
+var languages = map[string]string{
+	".go": "go", ".py": "python", ".js": "javascript", ".jsx": "javascript",
+	".ts": "typescript", ".tsx": "typescript", ".rs": "rust", ".rb": "ruby",
+	".java": "java", ".kt": "kotlin", ".c": "c", ".h": "c", ".cpp": "c++",
+	".md": "markdown", ".yaml": "yaml", ".yml": "yaml", ".json": "json", ".sh": "shell",
+}
+
+// Package is one directory of a repository map
+type Package struct {
+	Dir        string
+	ImportPath string // For a Go package
+	Language   string
+	Size       int64
+	Files      []File
+}
+
+// Map is a compact overview of a repository: its directories, files,
+// sizes and public symbols
+type Map struct {
+	Root     string
+	Size     int64
+	Files    int
+	Packages []Package
+}
+
+// Build maps the files under paths (everything when empty)
+func Build(root string, paths []string) (*Map, error) {
+	files, err := ListFiles(root, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a module or toolchain, Go files are read one by one
+	var goPkgs map[string]*goPackage
+	if hasGo(files) {
+		goPkgs, _ = loadGoPackages(root)
+	}
+
+	m := &Map{Root: root, Files: len(files)}
+	byDir := make(map[string]*Package)
+	var dirs []string
+
+	for _, f := range files {
+		dir := path.Dir(f.Path)
+		goPkg := goPkgs[dir]
+
+		extract, ok := extractors[filepath.Ext(f.Path)]
+		if ok && filepath.Ext(f.Path) == ".go" && goPkg != nil {
+			ok = goPkg.files[filepath.Join(root, f.Path)]
+		}
+		if ok && f.Size <= maxParseSize && !isTest(f.Path) {
+			if src, err := os.ReadFile(filepath.Join(root, f.Path)); err == nil {
+				f.Symbols = extract(src)
+			}
+		}
+
+		pkg, ok := byDir[dir]
+		if !ok {
+			pkg = &Package{Dir: dir}
+			if goPkg != nil {
+				pkg.ImportPath = goPkg.path
+			}
+			byDir[dir] = pkg
+			dirs = append(dirs, dir)
+		}
+		pkg.Files = append(pkg.Files, f)
+		pkg.Size += f.Size
+		m.Size += f.Size
+	}
+
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		pkg := byDir[dir]
+		pkg.Language = dominantLanguage(pkg.Files)
+		m.Packages = append(m.Packages, *pkg)
+	}
+
+	return m, nil
+}
+
+// Render formats the map as compact text, stopping before maxTokens
+// (0 for no limit)
+func (m *Map) Render(maxTokens int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Repository map: %s (%d files, %s)\n", filepath.Base(m.Root), m.Files, FormatSize(m.Size))
+
+	shown := 0
+	for _, pkg := range m.Packages {
+		var section strings.Builder
+		header := pkg.Dir + "/"
+		if pkg.Dir == "." {
+			header = "./"
+		}
+		fmt.Fprintf(&section, "\n%s [", header)
+		if pkg.Language != "" {
+			fmt.Fprintf(&section, "%s, ", pkg.Language)
+		}
+		fmt.Fprintf(&section, "%d files, %s]", len(pkg.Files), FormatSize(pkg.Size))
+		if pkg.ImportPath != "" {
+			fmt.Fprintf(&section, " %s", pkg.ImportPath)
+		}
+		section.WriteString("\n")
+
+		for _, f := range pkg.Files {
+			fmt.Fprintf(&section, "  %s (%s)", path.Base(f.Path), FormatSize(f.Size))
+			if len(f.Symbols) > 0 {
+				symbols := f.Symbols
+				more := ""
+				if len(symbols) > maxSymbolsPerFile {
+					more = fmt.Sprintf(", +%d more", len(symbols)-maxSymbolsPerFile)
+					symbols = symbols[:maxSymbolsPerFile]
+				}
+				fmt.Fprintf(&section, ": %s%s", strings.Join(symbols, ", "), more)
+			}
+			section.WriteString("\n")
+		}
+
+		if maxTokens > 0 && tokens.Count(b.String()+section.String()) > maxTokens {
+			fmt.Fprintf(&b, "\n... %d more files not shown\n", m.Files-shown)
+			break
+		}
+		b.WriteString(section.String())
+		shown += len(pkg.Files)
+	}
+
+	return b.String()
+}
+
+// FormatSize renders a byte count for humans
+func FormatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+func dominantLanguage(files []File) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, f := range files {
+		lang := languages[filepath.Ext(f.Path)]
+		if lang == "" {
+			continue
+		}
+		counts[lang]++
+		if counts[lang] > counts[best] || (counts[lang] == counts[best] && lang < best) {
+			best = lang
+		}
+	}
+	return best
+}
+
+func hasGo(files []File) bool {
+	for _, f := range files {
+		if filepath.Ext(f.Path) == ".go" {
+			return true
+		}
+	}
+	return false
+}
+
+func isTest(p string) bool {
+	base := path.Base(p)
+	return strings.HasSuffix(base, "_test.go") || strings.Contains(base, ".test.") ||
+		strings.Contains(base, ".spec.") || strings.HasPrefix(base, "test_")
+}
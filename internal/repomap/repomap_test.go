@@ -0,0 +1,115 @@
+package repomap
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	path := filepath.Join(root, rel)
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", rel, err)
+	}
+}
+
+func TestBuildAndRender(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "README.md", "# Demo\n")
+	writeFile(t, root, "internal/api/server.go", "package api\n\ntype Server struct{}\n\nfunc New() *Server { return nil }\n")
+	writeFile(t, root, "internal/api/server_test.go", "package api\n\nfunc TestHelper() {}\n")
+	writeFile(t, root, "web/app.ts", "export function render() {}\n")
+	writeFile(t, root, ".hidden/secret.go", "package hidden\n")
+
+	m, err := Build(root, nil)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if m.Files != 4 || len(m.Packages) != 3 {
+		t.Fatalf("Expected 4 files in 3 directories, got %d in %d", m.Files, len(m.Packages))
+	}
+
+	out := m.Render(0)
+	for _, want := range []string{
+		"# Repository map:",
+		"internal/api/ [go, 2 files,",
+		"  server.go (",
+		"): Server, New\n",
+		"web/ [typescript, 1 files,",
+		"app.ts (28 B): render",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Map missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "TestHelper") {
+		t.Error("Test files should not contribute symbols")
+	}
+
+	m, _ = Build(root, []string{"web"})
+	if m.Files != 1 || m.Packages[0].Dir != "web" {
+		t.Errorf("Expected only web/, got %+v", m.Packages)
+	}
+}
+
+func TestBuildGoPackages(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.21\n")
+	writeFile(t, root, "store/store.go", "package store\n\nfunc Open() {}\n")
+	writeFile(t, root, "store/store_other.go", "//go:build ignore\n\npackage store\n\nfunc Ignored() {}\n")
+	writeFile(t, root, "tools/nested/go.mod", "module example.com/tools\n")
+	writeFile(t, root, "tools/nested/gen.go", "package nested\n\nfunc Generate() {}\n")
+
+	m, err := Build(root, nil)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	out := m.Render(0)
+	for _, want := range []string{
+		"store/ [go, 2 files,",
+		"] example.com/demo/store\n",
+		"store.go (30 B): Open\n",
+		"gen.go (35 B): Generate\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Map missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Ignored") {
+		t.Errorf("Files left out of the build should not contribute symbols:\n%s", out)
+	}
+}
+
+func TestRenderBudget(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "b", "c", "d"} {
+		writeFile(t, root, dir+"/file.go", "package "+dir+"\n\nfunc "+strings.Repeat("Long", 40)+"() {}\n")
+	}
+
+	m, _ := Build(root, nil)
+	out := m.Render(80)
+	if tokens.Count(out) > 100 {
+		t.Errorf("Render overran the budget: %d tokens", tokens.Count(out))
+	}
+	if !strings.Contains(out, "more files not shown") {
+		t.Errorf("Expected truncation note:\n%s", out)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{512: "512 B", 2048: "2.0 KB", 3 * 1024 * 1024: "3.0 MB"}
+	for size, want := range tests {
+		if got := FormatSize(size); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
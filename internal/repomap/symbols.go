@@ -0,0 +1,89 @@
+package repomap
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// Extractor lists the top-level public symbols declared in a source file
+type Extractor func(src []byte) []string
+
+// extractors by file extension. Go is parsed with go/parser; the other
+// languages are lexed (so comments and string literals are skipped) and
+// their declarations read by nesting depth, which finds top-level and
+// class-level public declarations wherever they sit on the line.
+var extractors = map[string]Extractor{
+	".go":  goSymbols,
+	".py":  pythonSymbols,
+	".js":  jsSymbols,
+	".jsx": jsSymbols,
+	".mjs": jsSymbols,
+	".ts":  jsSymbols,
+	".tsx": jsSymbols,
+	".rs":  rustSymbols,
+	".rb":  rubySymbols,
+}
+
+// goSymbols lists exported functions, methods, types, constants and
+// variables. Test files contribute no symbols.
+func goSymbols(src []byte) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var symbols []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				recv := receiverName(d.Recv.List[0].Type)
+				if recv == "" || !ast.IsExported(strings.TrimPrefix(recv, "*")) {
+					continue
+				}
+				symbols = append(symbols, fmt.Sprintf("(%s).%s", recv, d.Name.Name))
+				continue
+			}
+			symbols = append(symbols, d.Name.Name)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						symbols = append(symbols, s.Name.Name)
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if name.IsExported() {
+							symbols = append(symbols, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// receiverName renders a method receiver type such as *Coordinator
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		if name := receiverName(t.X); name != "" {
+			return "*" + name
+		}
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	}
+	return ""
+}
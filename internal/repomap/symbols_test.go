@@ -0,0 +1,171 @@
+package repomap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGoSymbols(t *testing.T) {
+	src := []byte(`package demo
+
+type Server struct{}
+type config struct{}
+
+const Version = "1"
+var ErrClosed, errInternal = 1, 2
+
+func New() *Server { return nil }
+func helper() {}
+func (s *Server) Start() error { return nil }
+func (s *Server) stop() {}
+func (c config) Load() {}
+func (l *List[T]) Push(v T) {}
+`)
+
+	got := goSymbols(src)
+	want := []string{"Server", "Version", "ErrClosed", "New", "(*Server).Start", "(*List).Push"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("goSymbols = %v, want %v", got, want)
+	}
+
+	if got := goSymbols([]byte("not go")); got != nil {
+		t.Errorf("Expected nil for unparsable source, got %v", got)
+	}
+}
+
+func TestDeclarationSymbols(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		src  string
+		want []string
+	}{
+		{"python", ".py", `"""Module docs.
+
+def not_code():
+    pass
+"""
+import os
+
+class Model(Base):
+    """A model."""
+
+    field = 1
+
+    def method(self):
+        return """
+def inner_text(): pass
+"""
+
+    def _hidden(self): pass
+
+    @property
+    def name(self):
+        def nested(): pass
+
+def load(path,
+         mode="r"):
+    pass
+def _private(): pass
+async def fetch(): pass
+# def commented(): pass
+`, []string{"Model", "Model.method", "Model.name", "load", "fetch"}},
+
+		{"typescript", ".ts", `/* export function commented() {} */
+export function handler() {}
+export default class App {}
+export const API_URL = '', other = 1
+function local() { const x = "export const inString = 1" }
+export interface Props {}
+export declare abstract class Base {}
+export const enum Mode { A }
+const re = /export function inRegexp/
+const tpl = ` + "`export const ${ {a: `nested`}.a } = 1`" + `
+export { local as renamed, type Props as P }
+export * as utils from "./utils"
+namespace N { export function member() {} }
+`, []string{"handler", "App", "API_URL", "Props", "Base", "Mode", "renamed", "P", "utils"}},
+
+		{"commonjs", ".js", `exports.parse = function () {}
+exports.x == 1
+module.exports = { format, write: writeFile, nested: { inner: 1 } }
+`, []string{"parse", "format", "write", "nested"}},
+
+		{"rust", ".rs", `//! pub fn in_doc() {}
+pub fn run() {}
+fn private() {}
+pub struct Config;
+pub(crate) fn scoped() {}
+pub const fn limit() -> u32 { 1 }
+pub static mut COUNTER: u32 = 0;
+pub extern "C" fn callback() {}
+const S: &str = "pub fn in_string() {}";
+const R: &str = r#"pub fn in_raw() {}"#;
+/* /* nested */ pub fn commented() {} */
+impl<'a, T: Clone> Config<T> where T: Default {
+    pub fn new() -> Self { let c = '{'; Self }
+    fn helper(&self) {}
+    pub(crate) fn internal(&self) {}
+}
+impl fmt::Display for Config {
+    fn fmt(&self) {}
+}
+pub mod inner {
+    pub fn nested() {}
+}
+#[macro_export]
+macro_rules! config { () => {} }
+`, []string{"run", "Config", "limit", "COUNTER", "callback", "Config::new", "inner", "config!"}},
+
+		{"ruby", ".rb", `=begin
+class Commented
+end
+=end
+module Billing
+  class Invoice < Base
+    SQL = <<~SQL
+      def in_heredoc
+      end
+    SQL
+
+    def total
+      items.each do |i|
+        return 0 if i.nil?
+      end
+      x = if paid? then 1 else 2 end
+    end
+
+    def self.build(attrs) = new(attrs)
+
+    def paid?; end
+
+    def amount=(value)
+      while value > 0 do value -= 1 end
+    end
+
+    class << self
+      def find(id); end
+    end
+
+    private
+
+    def secret; end
+  end
+
+  class Reports::Monthly
+    private def hidden; end
+    def render; "#{"def in_string"}" end
+  end
+end
+`, []string{"Billing", "Billing::Invoice", "Billing::Invoice#total", "Billing::Invoice.build",
+			"Billing::Invoice#paid?", "Billing::Invoice#amount=", "Billing::Invoice.find",
+			"Billing::Reports::Monthly", "Billing::Reports::Monthly#render"}},
+	}
+
+	for _, tc := range tests {
+		got := extractors[tc.ext]([]byte(tc.src))
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s symbols = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
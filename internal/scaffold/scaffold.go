@@ -0,0 +1,177 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+// gitignoreMarker identifies the block scaffold appends to .gitignore
+const gitignoreMarker = "# gforge"
+
+// gitignoreBlock keeps project-local worktrees and session recordings out
+// of the repository
+const gitignoreBlock = gitignoreMarker + `
+.gforge/worktrees/
+*.cast
+`
+
+// file is a scaffolded file and its permissions
+type file struct {
+	content string
+	mode    os.FileMode
+}
+
+var files = map[string]file{
+	config.ProjectFile: {mode: 0644, content: `# gforge project settings, shared by everyone working on this repository.
+# Empty values fall back to each user's ~/.config/gforge/config.yaml.
+
+# Agent used by "gforge spawn" when --agent is not given
+agent: claude
+
+# Prefix for generated goblin branches
+branch_prefix: gforge/
+
+# Run agents inside the project environment: off, auto, devcontainer, nix
+dev_env: off
+
+# Onboarding docs every new goblin is asked to read before its first task.
+# Files missing from the repository are skipped.
+context:
+  - README.md
+  - CONTRIBUTING.md
+  - docs/ARCHITECTURE.md
+
+# Also send a map of directories, files and public symbols (see gforge map)
+repo_map: false
+repo_map_tokens: 2000
+
+# Shell commands run in the goblin's worktree. GFORGE_GOBLIN, GFORGE_BRANCH,
+# GFORGE_PROJECT and GFORGE_WORKTREE describe the goblin.
+hooks:
+  post_spawn:
+    # - ./.gforge/hooks/post-spawn.sh
+  pre_stop:
+    # - git status --short
+`},
+
+	filepath.Join(config.ProjectTasksDir, "review.md"): {mode: 0644, content: `Review the changes on this branch against the base branch. Point out bugs,
+missing tests and unclear code, then fix what you find. Keep the diff focused
+on the review findings.
+`},
+
+	filepath.Join(config.ProjectTasksDir, "fix-tests.md"): {mode: 0644, content: `Run the test suite, find the failing tests and fix the underlying code.
+Do not delete or weaken tests to make them pass. Summarize each fix when done.
+`},
+
+	filepath.Join(config.ProjectTasksDir, "document.md"): {mode: 0644, content: `Add or update documentation for the code changed on this branch: doc
+comments, README sections and examples. Match the existing style.
+`},
+
+	".gforge/hooks/post-spawn.sh": {mode: 0755, content: `#!/bin/sh
+# Example post_spawn hook: prepare a fresh worktree before the agent starts.
+# Enable it under hooks.post_spawn in .gforge.yaml.
+set -e
+
+echo "Preparing $GFORGE_GOBLIN on $GFORGE_BRANCH"
+
+# Copy local settings the worktree does not get from git
+if [ -f "$GFORGE_PROJECT/.env" ] && [ ! -f .env ]; then
+	cp "$GFORGE_PROJECT/.env" .env
+fi
+
+# Install dependencies
+# npm ci
+# go mod download
+# pip install -r requirements.txt
+`},
+}
+
+// Result lists what Write changed in a repository
+type Result struct {
+	Created          []string
+	Skipped          []string // Existing files left untouched
+	GitignoreUpdated bool
+}
+
+// Paths returns the files scaffold writes, relative to the repository
+func Paths() []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write drops the gforge starter files into dir and appends the
+// recommended .gitignore entries. Existing files are kept unless force is
+// set.
+func Write(dir string, force bool) (*Result, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	result := &Result{}
+	for _, rel := range Paths() {
+		f := files[rel]
+		path := filepath.Join(dir, rel)
+
+		if _, err := os.Stat(path); err == nil && !force {
+			result.Skipped = append(result.Skipped, rel)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return result, fmt.Errorf("failed to create directory for %s: %w", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), f.mode); err != nil {
+			return result, fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+		result.Created = append(result.Created, rel)
+	}
+
+	updated, err := updateGitignore(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return result, err
+	}
+	result.GitignoreUpdated = updated
+
+	return result, nil
+}
+
+// updateGitignore appends the gforge block unless it is already present
+func updateGitignore(path string) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == gitignoreMarker {
+			return false, nil
+		}
+	}
+
+	content := string(existing)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	content += gitignoreBlock
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to update .gitignore: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,75 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("node_modules/"), 0644)
+
+	result, err := Write(dir, false)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if len(result.Created) != len(Paths()) || !result.GitignoreUpdated {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	// The scaffolded project file must be valid for gforge itself
+	pc, err := config.LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Scaffolded %s does not load: %v", config.ProjectFile, err)
+	}
+	if pc.Agent != "claude" || pc.DevEnv != "off" || len(pc.Context) != 3 {
+		t.Errorf("Unexpected project config: %+v", pc)
+	}
+	if names := config.ProjectTasks(dir); len(names) != 3 {
+		t.Errorf("Expected 3 task templates, got %v", names)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, ".gforge/hooks/post-spawn.sh"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected executable hook example, got %v (%v)", info, err)
+	}
+
+	gitignore, _ := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if !strings.HasPrefix(string(gitignore), "node_modules/\n\n# gforge\n") {
+		t.Errorf("Unexpected .gitignore:\n%s", gitignore)
+	}
+
+	// Running again keeps edits and does not duplicate .gitignore entries
+	os.WriteFile(filepath.Join(dir, config.ProjectFile), []byte("agent: codex\n"), 0644)
+
+	result, err = Write(dir, false)
+	if err != nil {
+		t.Fatalf("Second Write failed: %v", err)
+	}
+	if len(result.Created) != 0 || len(result.Skipped) != len(Paths()) || result.GitignoreUpdated {
+		t.Errorf("Expected nothing changed on second run, got %+v", result)
+	}
+	if pc, _ := config.LoadProject(dir); pc.Agent != "codex" {
+		t.Error("Existing project file should not be overwritten")
+	}
+
+	if result, _ := Write(dir, true); len(result.Created) != len(Paths()) {
+		t.Errorf("Expected force to rewrite every file, got %+v", result)
+	}
+}
+
+func TestWriteNotDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, nil, 0644)
+
+	if _, err := Write(path, false); err == nil {
+		t.Error("Expected error for non-directory")
+	}
+	if _, err := Write(filepath.Join(t.TempDir(), "missing"), false); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+}
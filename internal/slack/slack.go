@@ -0,0 +1,267 @@
+// Package slack serves the /gforge slash command on the REST API server,
+// running spawn, status and logs for Slack users mapped to an API token.
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/api"
+)
+
+// maxSkew is how old a request may be; older ones could be replays
+const maxSkew = 5 * time.Minute
+
+// Lines of output shown by logs, by default and at most
+const (
+	defaultLogLines = 20
+	maxLogLines     = 200
+)
+
+// Backend is what commands are run against, normally the server's own
+type Backend interface {
+	Spawn(req api.SpawnRequest) (*api.Goblin, error)
+	ListGoblins() ([]api.Goblin, error)
+	GetGoblin(nameOrID string) (*api.Goblin, error)
+	Logs(nameOrID string, lines int) ([]string, error)
+}
+
+// Response is a slash command reply. Ephemeral replies are seen only by
+// the user who ran the command.
+type Response struct {
+	ResponseType string `json:"response_type"` // ephemeral or in_channel
+	Text         string `json:"text"`
+}
+
+// Handler serves POST /slack/commands
+type Handler struct {
+	SigningSecret string
+
+	// Backend returns what to run a Slack user's commands against, or
+	// false when the user has no access
+	Backend func(userID string) (Backend, bool)
+
+	// Respond sends a delayed reply to a command's response_url; nil
+	// posts it there
+	Respond func(responseURL string, r Response) error
+}
+
+// Verify checks a request's X-Slack-Signature against its timestamp and
+// body
+func Verify(body []byte, timestamp, signature, secret string, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || secret == "" {
+		return false
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > maxSkew || d < -maxSkew {
+		return false
+	}
+	sig, ok := strings.CutPrefix(signature, "v0=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/slack/commands" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.SigningSecret == "" {
+		http.Error(w, "Slack commands are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if !Verify(body, r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), h.SigningSecret, time.Now()) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	resp := h.Run(form.Get("user_id"), form.Get("command"), form.Get("text"), form.Get("response_url"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Run runs a command for a Slack user and returns the immediate reply.
+// Spawns take longer than Slack waits, so their result follows at
+// responseURL.
+func (h *Handler) Run(userID, command, text, responseURL string) Response {
+	backend, ok := h.Backend(userID)
+	if !ok {
+		return ephemeral("You aren't allowed to use gforge; ask an admin to map your Slack user (%s) to an API token.", userID)
+	}
+
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		return ephemeral("%s", usage(command))
+	}
+
+	switch args[0] {
+	case "spawn":
+		req, err := parseSpawn(args[1:])
+		if err != nil {
+			return ephemeral("%v\n%s", err, usage(command))
+		}
+		go h.spawn(backend, req, responseURL)
+		return ephemeral("Spawning %s in %s...", req.Name, req.ProjectPath)
+
+	case "status":
+		if len(args) > 1 {
+			g, err := backend.GetGoblin(args[1])
+			if err != nil {
+				return ephemeral("Failed to get %s: %v", args[1], err)
+			}
+			return ephemeral("%s", goblinTable([]api.Goblin{*g}))
+		}
+		goblins, err := backend.ListGoblins()
+		if err != nil {
+			return ephemeral("Failed to list goblins: %v", err)
+		}
+		if len(goblins) == 0 {
+			return ephemeral("No goblins")
+		}
+		return ephemeral("%s", goblinTable(goblins))
+
+	case "logs":
+		if len(args) < 2 {
+			return ephemeral("%s", usage(command))
+		}
+		lines := defaultLogLines
+		if len(args) > 2 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < 1 {
+				return ephemeral("Invalid line count: %s", args[2])
+			}
+			lines = min(n, maxLogLines)
+		}
+		output, err := backend.Logs(args[1], lines)
+		if err != nil {
+			return ephemeral("Failed to get logs of %s: %v", args[1], err)
+		}
+		if len(output) == 0 {
+			return ephemeral("No output from %s yet", args[1])
+		}
+		return ephemeral("```\n%s\n```", strings.Join(output, "\n"))
+	}
+
+	return ephemeral("%s", usage(command))
+}
+
+// spawn spawns a goblin and reports back in the channel
+func (h *Handler) spawn(backend Backend, req api.SpawnRequest, responseURL string) {
+	var resp Response
+	if g, err := backend.Spawn(req); err != nil {
+		resp = ephemeral("Failed to spawn %s: %v", req.Name, err)
+	} else {
+		resp = Response{
+			ResponseType: "in_channel",
+			Text:         fmt.Sprintf("Spawned goblin *%s* (%s) on branch `%s`", g.Name, g.Agent, g.Branch),
+		}
+	}
+
+	respond := h.Respond
+	if respond == nil {
+		respond = postResponse
+	}
+	respond(responseURL, resp)
+}
+
+// parseSpawn reads spawn's arguments: [--agent A] [--priority P] name
+// project [task...]
+func parseSpawn(args []string) (api.SpawnRequest, error) {
+	var req api.SpawnRequest
+	for len(args) > 1 && strings.HasPrefix(args[0], "--") {
+		switch args[0] {
+		case "--agent":
+			req.Agent = args[1]
+		case "--priority":
+			req.Priority = args[1]
+		default:
+			return req, fmt.Errorf("unknown option %s", args[0])
+		}
+		args = args[2:]
+	}
+	if len(args) < 2 {
+		return req, fmt.Errorf("spawn needs a name and a project path")
+	}
+	req.Name, req.ProjectPath = args[0], args[1]
+	req.Task = strings.Join(args[2:], " ")
+	return req, nil
+}
+
+// goblinTable renders goblins as a code block
+func goblinTable(goblins []api.Goblin) string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tAGENT\tSTATUS\tBRANCH")
+	for _, g := range goblins {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", g.Name, g.Agent, g.Status, g.Branch)
+	}
+	w.Flush()
+	return "```\n" + strings.TrimRight(b.String(), "\n") + "\n```"
+}
+
+func usage(command string) string {
+	if command == "" {
+		command = "/gforge"
+	}
+	return fmt.Sprintf("Usage:\n"+
+		"`%[1]s spawn [--agent A] [--priority P] <name> <project> [task]`\n"+
+		"`%[1]s status [name]`\n"+
+		"`%[1]s logs <name> [lines]`", command)
+}
+
+func ephemeral(format string, args ...interface{}) Response {
+	return Response{ResponseType: "ephemeral", Text: fmt.Sprintf(format, args...)}
+}
+
+// postResponse sends a delayed reply to a command's response_url
+func postResponse(responseURL string, r Response) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(responseURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to send Slack response: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send Slack response: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/api"
+)
+
+// fakeBackend serves one goblin and records spawns
+type fakeBackend struct {
+	spawned []api.SpawnRequest
+}
+
+func (f *fakeBackend) Spawn(req api.SpawnRequest) (*api.Goblin, error) {
+	f.spawned = append(f.spawned, req)
+	return &api.Goblin{Name: req.Name, Agent: "claude", Branch: "gforge/" + req.Name}, nil
+}
+
+func (f *fakeBackend) ListGoblins() ([]api.Goblin, error) {
+	return []api.Goblin{{Name: "coder", Agent: "claude", Status: "running", Branch: "gforge/coder"}}, nil
+}
+
+func (f *fakeBackend) GetGoblin(name string) (*api.Goblin, error) {
+	if name != "coder" {
+		return nil, fmt.Errorf("goblin not found: %s", name)
+	}
+	return &api.Goblin{Name: "coder", Agent: "claude", Status: "running"}, nil
+}
+
+func (f *fakeBackend) Logs(name string, lines int) ([]string, error) {
+	return []string{fmt.Sprintf("%d lines of %s", lines, name)}, nil
+}
+
+func sign(body, timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ts := "1700000000"
+	body := []byte("command=%2Fgforge&text=status")
+
+	if !Verify(body, ts, sign(string(body), ts, "s3cret"), "s3cret", now) {
+		t.Error("Expected a correctly signed request to verify")
+	}
+	if Verify(body, ts, sign(string(body), ts, "other"), "s3cret", now) {
+		t.Error("Expected a request signed with another secret refused")
+	}
+	if Verify(body, ts, sign(string(body), ts, "s3cret"), "s3cret", now.Add(10*time.Minute)) {
+		t.Error("Expected a stale request refused")
+	}
+}
+
+func TestRun(t *testing.T) {
+	backend := &fakeBackend{}
+	responded := make(chan Response, 1)
+	h := &Handler{
+		Backend: func(userID string) (Backend, bool) { return backend, userID == "U1" },
+		Respond: func(_ string, r Response) error {
+			responded <- r
+			return nil
+		},
+	}
+
+	if r := h.Run("U2", "/gforge", "status", ""); !strings.Contains(r.Text, "aren't allowed") {
+		t.Errorf("Expected an unmapped user refused, got %q", r.Text)
+	}
+
+	if r := h.Run("U1", "/gforge", "status", ""); !strings.Contains(r.Text, "gforge/coder") || r.ResponseType != "ephemeral" {
+		t.Errorf("Expected the goblin table, got %+v", r)
+	}
+	if r := h.Run("U1", "/gforge", "logs coder 500", ""); !strings.Contains(r.Text, "200 lines of coder") {
+		t.Errorf("Expected logs capped at 200 lines, got %q", r.Text)
+	}
+	if r := h.Run("U1", "/gforge", "spawn onlyname", ""); !strings.Contains(r.Text, "needs a name and a project") {
+		t.Errorf("Expected spawn usage, got %q", r.Text)
+	}
+
+	r := h.Run("U1", "/gforge", "spawn --agent codex fixer ~/src/app fix the login bug", "https://hooks.slack.com/x")
+	if !strings.Contains(r.Text, "Spawning fixer") {
+		t.Errorf("Expected a spawning reply, got %q", r.Text)
+	}
+	done := <-responded
+	if done.ResponseType != "in_channel" || !strings.Contains(done.Text, "fixer") {
+		t.Errorf("Expected the spawn announced in the channel, got %+v", done)
+	}
+	want := api.SpawnRequest{Name: "fixer", Agent: "codex", ProjectPath: "~/src/app", Task: "fix the login bug"}
+	if len(backend.spawned) != 1 || backend.spawned[0] != want {
+		t.Errorf("Expected %+v spawned, got %+v", want, backend.spawned)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	h := &Handler{
+		SigningSecret: "s3cret",
+		Backend:       func(string) (Backend, bool) { return &fakeBackend{}, true },
+	}
+
+	body := url.Values{"user_id": {"U1"}, "command": {"/gforge"}, "text": {"status coder"}}.Encode()
+	ts := fmt.Sprintf("%d", time.Now().Unix())
+
+	post := func(signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", ts)
+		req.Header.Set("X-Slack-Signature", signature)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post("v0=00"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a bad signature, got %d", rec.Code)
+	}
+
+	rec := post(sign(body, ts, "s3cret"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var resp Response
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || !strings.Contains(resp.Text, "coder") {
+		t.Errorf("Expected coder's status, got %+v, %v", resp, err)
+	}
+}
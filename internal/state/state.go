@@ -0,0 +1,243 @@
+package state
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the current state file format
+const Version = 1
+
+// State is a portable snapshot of goblin definitions. Machine-specific and
+// transient data (worktree paths, tmux sessions, output logs) is left out.
+type State struct {
+	Version    int       `yaml:"version"`
+	ExportedAt time.Time `yaml:"exported_at"`
+	Goblins    []Goblin  `yaml:"goblins"`
+}
+
+// Goblin is the exported definition of one goblin
+type Goblin struct {
+	ID        string    `yaml:"id"`
+	Name      string    `yaml:"name"`
+	Agent     string    `yaml:"agent"`
+	Status    string    `yaml:"status"`
+	Project   string    `yaml:"project"`
+	Branch    string    `yaml:"branch,omitempty"`
+	BaseRef   string    `yaml:"base_ref,omitempty"`
+	Priority  string    `yaml:"priority,omitempty"`
+	Squad     string    `yaml:"squad,omitempty"`
+	Pinned    bool      `yaml:"pinned,omitempty"`
+	CreatedAt time.Time `yaml:"created_at"`
+	Aliases   []string  `yaml:"aliases,omitempty"`
+	Tasks     []Task    `yaml:"tasks,omitempty"`
+	Queue     []Queued  `yaml:"queue,omitempty"`
+	Notes     []Note    `yaml:"notes,omitempty"`
+}
+
+// Task is a task that was sent to a goblin
+type Task struct {
+	Prompt string    `yaml:"prompt"`
+	SentAt time.Time `yaml:"sent_at"`
+}
+
+// Queued is a task waiting in a goblin's queue
+type Queued struct {
+	Prompt   string    `yaml:"prompt"`
+	QueuedAt time.Time `yaml:"queued_at"`
+}
+
+// Note is an observation someone attached to a goblin
+type Note struct {
+	Body      string    `yaml:"body"`
+	WrittenAt time.Time `yaml:"written_at"`
+}
+
+// ImportResult lists what an import created and what it left alone
+type ImportResult struct {
+	Imported []string
+	Skipped  []string // Goblins whose name or ID already exists
+}
+
+// Export snapshots every goblin with its aliases, task history, queued
+// tasks and notes
+func Export(db *storage.DB) (*State, error) {
+	goblins, err := db.ListGoblins()
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := db.ListAliases()
+	if err != nil {
+		return nil, err
+	}
+	byGoblin := make(map[string][]string)
+	for alias, id := range aliases {
+		byGoblin[id] = append(byGoblin[id], alias)
+	}
+
+	st := &State{Version: Version, ExportedAt: time.Now().UTC()}
+
+	// Oldest first, so an import recreates goblins in their original order.
+	// The list puts pinned goblins first, so it is sorted again.
+	for i, j := 0, len(goblins)-1; i < j; i, j = i+1, j-1 {
+		goblins[i], goblins[j] = goblins[j], goblins[i]
+	}
+	sort.SliceStable(goblins, func(i, j int) bool {
+		return goblins[i].CreatedAt.Before(goblins[j].CreatedAt)
+	})
+	for _, g := range goblins {
+		sort.Strings(byGoblin[g.ID])
+
+		tasks, err := db.ListTasks(g.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		queue, err := db.ListTaskQueue(g.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		notes, err := db.ListNotes(g.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := Goblin{
+			ID:        g.ID,
+			Name:      g.Name,
+			Agent:     g.Agent,
+			Status:    g.Status,
+			Project:   g.ProjectPath,
+			Branch:    g.Branch,
+			BaseRef:   g.BaseRef,
+			Priority:  g.Priority,
+			Squad:     g.Squad,
+			Pinned:    g.Pinned,
+			CreatedAt: g.CreatedAt.UTC(),
+			Aliases:   byGoblin[g.ID],
+		}
+		for _, t := range tasks {
+			entry.Tasks = append(entry.Tasks, Task{Prompt: t.Task, SentAt: t.StartedAt.UTC()})
+		}
+		for _, q := range queue {
+			entry.Queue = append(entry.Queue, Queued{Prompt: q.Task, QueuedAt: q.QueuedAt.UTC()})
+		}
+		for _, n := range notes {
+			entry.Notes = append(entry.Notes, Note{Body: n.Body, WrittenAt: n.CreatedAt.UTC()})
+		}
+
+		st.Goblins = append(st.Goblins, entry)
+	}
+
+	return st, nil
+}
+
+// Import recreates goblin definitions from a snapshot. Imported goblins
+// have no tmux session, so active ones come back as stopped.
+func Import(db *storage.DB, st *State) (*ImportResult, error) {
+	if st.Version > Version {
+		return nil, fmt.Errorf("state version %d is newer than supported version %d", st.Version, Version)
+	}
+
+	result := &ImportResult{}
+	for _, g := range st.Goblins {
+		if g.Name == "" || g.ID == "" || g.Agent == "" {
+			return result, fmt.Errorf("goblin entry missing id, name or agent: %+v", g)
+		}
+
+		if exists(db, g.ID) || exists(db, g.Name) {
+			result.Skipped = append(result.Skipped, g.Name)
+			continue
+		}
+
+		status := g.Status
+		if status != "stopped" && status != "failed" && status != "completed" {
+			status = "stopped"
+		}
+
+		if err := db.RestoreGoblin(&storage.Goblin{
+			ID:          g.ID,
+			Name:        g.Name,
+			Agent:       g.Agent,
+			Status:      status,
+			ProjectPath: g.Project,
+			Branch:      g.Branch,
+			BaseRef:     g.BaseRef,
+			Priority:    g.Priority,
+			Squad:       g.Squad,
+			CreatedAt:   g.CreatedAt,
+			UpdatedAt:   time.Now(),
+		}); err != nil {
+			return result, err
+		}
+
+		for _, t := range g.Tasks {
+			if err := db.RecordTaskAt(g.ID, t.Prompt, t.SentAt); err != nil {
+				return result, err
+			}
+		}
+
+		for _, q := range g.Queue {
+			if err := db.EnqueueTaskAt(g.ID, q.Prompt, q.QueuedAt); err != nil {
+				return result, err
+			}
+		}
+
+		if g.Pinned {
+			if err := db.SetGoblinPinned(g.ID, true); err != nil {
+				return result, err
+			}
+		}
+
+		for _, n := range g.Notes {
+			if _, err := db.AddNoteAt(g.ID, n.Body, n.WrittenAt); err != nil {
+				return result, err
+			}
+		}
+
+		for _, alias := range g.Aliases {
+			if exists(db, alias) {
+				continue
+			}
+			if err := db.SetAlias(alias, g.ID); err != nil {
+				return result, err
+			}
+		}
+
+		result.Imported = append(result.Imported, g.Name)
+	}
+
+	return result, nil
+}
+
+// Write encodes a snapshot as YAML
+func Write(w io.Writer, st *State) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(st); err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	return enc.Close()
+}
+
+// Read decodes a YAML snapshot
+func Read(r io.Reader) (*State, error) {
+	var st State
+	if err := yaml.NewDecoder(r).Decode(&st); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	return &st, nil
+}
+
+// exists reports whether a reference already resolves to a goblin
+func exists(db *storage.DB, ref string) bool {
+	g, err := db.GetGoblin(ref)
+	return err == nil && g != nil
+}
@@ -0,0 +1,118 @@
+package state
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func newDB(t *testing.T) *storage.DB {
+	db, err := storage.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := newDB(t)
+	created := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+
+	src.RestoreGoblin(&storage.Goblin{
+		ID: "aaaa1111", Name: "auth", Agent: "claude", Status: "running",
+		ProjectPath: "/src/app", WorktreePath: "/wt/aaaa1111", Branch: "gforge/auth",
+		TmuxSession: "gforge-aaaa1111", BaseRef: "abc123", Priority: "high", Squad: "auth",
+		CreatedAt: created, UpdatedAt: created,
+	})
+	src.RestoreGoblin(&storage.Goblin{
+		ID: "bbbb2222", Name: "docs", Agent: "codex", Status: "failed", ProjectPath: "/src/app",
+		CreatedAt: created.Add(time.Hour), UpdatedAt: created.Add(time.Hour),
+	})
+	src.RecordTaskAt("aaaa1111", "Add OAuth login", created.Add(time.Minute))
+	src.EnqueueTaskAt("aaaa1111", "Add logout", created.Add(3*time.Minute))
+	src.SetAlias("login", "aaaa1111")
+	src.SetGoblinPinned("aaaa1111", true)
+	src.AddNoteAt("aaaa1111", "skipped the token refresh tests", created.Add(2*time.Minute))
+
+	st, err := Export(src)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, st); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	out := buf.String()
+	for _, unwanted := range []string{"/wt/aaaa1111", "gforge-aaaa1111"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("Export should not contain transient %q:\n%s", unwanted, out)
+		}
+	}
+
+	loaded, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(loaded.Goblins) != 2 || loaded.Goblins[0].Name != "auth" {
+		t.Fatalf("Expected auth then docs, got %+v", loaded.Goblins)
+	}
+
+	dst := newDB(t)
+	result, err := Import(dst, loaded)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(result.Imported) != 2 {
+		t.Errorf("Expected 2 imported, got %v", result.Imported)
+	}
+
+	g, _ := dst.GetGoblin("login")
+	if g == nil || g.Name != "auth" {
+		t.Fatalf("Expected alias to resolve to auth, got %+v", g)
+	}
+	if g.Status != "stopped" || g.TmuxSession != "" || g.BaseRef != "abc123" || g.Priority != "high" || g.Squad != "auth" || !g.Pinned || !g.CreatedAt.Equal(created) {
+		t.Errorf("Unexpected imported goblin: %+v", g)
+	}
+	if docs, _ := dst.GetGoblin("docs"); docs == nil || docs.Status != "failed" {
+		t.Errorf("Expected failed status preserved, got %+v", docs)
+	}
+
+	tasks, _ := dst.ListTasks("aaaa1111")
+	if len(tasks) != 1 || tasks[0].Task != "Add OAuth login" {
+		t.Errorf("Expected task history imported, got %+v", tasks)
+	}
+	queue, _ := dst.ListTaskQueue("aaaa1111")
+	if len(queue) != 1 || queue[0].Task != "Add logout" || !queue[0].QueuedAt.Equal(created.Add(3*time.Minute)) {
+		t.Errorf("Expected queued task imported, got %+v", queue)
+	}
+	notes, _ := dst.ListNotes("aaaa1111")
+	if len(notes) != 1 || notes[0].Body != "skipped the token refresh tests" || !notes[0].CreatedAt.Equal(created.Add(2*time.Minute)) {
+		t.Errorf("Expected notes imported, got %+v", notes)
+	}
+
+	// Importing again leaves existing goblins alone
+	result, err = Import(dst, loaded)
+	if err != nil {
+		t.Fatalf("Second import failed: %v", err)
+	}
+	if len(result.Imported) != 0 || len(result.Skipped) != 2 {
+		t.Errorf("Expected everything skipped, got %+v", result)
+	}
+}
+
+func TestImportRejects(t *testing.T) {
+	db := newDB(t)
+
+	if _, err := Import(db, &State{Version: Version + 1}); err == nil {
+		t.Error("Expected error for newer state version")
+	}
+	if _, err := Import(db, &State{Version: Version, Goblins: []Goblin{{Name: "x"}}}); err == nil {
+		t.Error("Expected error for incomplete goblin entry")
+	}
+}
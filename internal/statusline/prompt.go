@@ -0,0 +1,49 @@
+package statusline
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptInfo describes the goblin whose worktree the shell is in
+type PromptInfo struct {
+	Name   string
+	Agent  string
+	Status string
+	Branch string
+}
+
+// Named prompt formats accepted by FormatPrompt
+var promptFormats = map[string]string{
+	"default": "👺 {{.Name}} ({{.Agent}}, {{.Status}})",
+	"plain":   "{{.Name}}:{{.Agent}}:{{.Status}}",
+	"name":    "{{.Name}}",
+}
+
+// FormatPrompt renders prompt info using a named format (default, plain,
+// name) or a Go template over PromptInfo
+func FormatPrompt(info PromptInfo, format string) (string, error) {
+	if format == "" {
+		format = "default"
+	}
+
+	text, ok := promptFormats[format]
+	if !ok {
+		if !strings.Contains(format, "{{") {
+			return "", fmt.Errorf("unknown format: %s (use default, plain, name or a Go template)", format)
+		}
+		text = format
+	}
+
+	tmpl, err := template.New("prompt").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid format: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, info); err != nil {
+		return "", fmt.Errorf("invalid format: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,34 @@
+package statusline
+
+import (
+	"testing"
+)
+
+func TestFormatPrompt(t *testing.T) {
+	info := PromptInfo{Name: "coder", Agent: "claude", Status: "running", Branch: "gforge/coder"}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"", "👺 coder (claude, running)"},
+		{"plain", "coder:claude:running"},
+		{"name", "coder"},
+		{"[{{.Name}}@{{.Branch}}]", "[coder@gforge/coder]"},
+	}
+
+	for _, tc := range tests {
+		got, err := FormatPrompt(info, tc.format)
+		if err != nil {
+			t.Errorf("FormatPrompt(%q) failed: %v", tc.format, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("FormatPrompt(%q) = %q, want %q", tc.format, got, tc.expected)
+		}
+	}
+
+	if _, err := FormatPrompt(info, "fancy"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
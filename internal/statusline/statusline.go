@@ -0,0 +1,71 @@
+// Package statusline renders compact goblin summaries for status bars
+// (tmux, starship, polybar) and shell prompts.
+package statusline
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Counts buckets goblins by how much attention they need
+type Counts struct {
+	Running int // working
+	Waiting int // paused or not yet started
+	Stopped int // stopped or failed
+}
+
+// Total returns the number of goblins counted
+func (c Counts) Total() int {
+	return c.Running + c.Waiting + c.Stopped
+}
+
+// FromStatuses buckets per-status goblin counts
+func FromStatuses(byStatus map[string]int) Counts {
+	var c Counts
+	for status, n := range byStatus {
+		switch status {
+		case "running":
+			c.Running += n
+		case "paused", "created":
+			c.Waiting += n
+		case "stopped", "failed", "dead", "completed":
+			c.Stopped += n
+		}
+	}
+	return c
+}
+
+// Named formats accepted by Format
+var formats = map[string]string{
+	"emoji": "🟢{{.Running}} 🟡{{.Waiting}} 🔴{{.Stopped}}",
+	"plain": "R{{.Running}} W{{.Waiting}} S{{.Stopped}}",
+	"tmux":  "#[fg=green]●{{.Running}} #[fg=yellow]●{{.Waiting}} #[fg=red]●{{.Stopped}}#[default]",
+}
+
+// Format renders counts using a named format (emoji, plain, tmux) or a
+// Go template over Counts, e.g. "{{.Running}}/{{.Total}}"
+func Format(c Counts, format string) (string, error) {
+	if format == "" {
+		format = "emoji"
+	}
+
+	text, ok := formats[format]
+	if !ok {
+		if !strings.Contains(format, "{{") {
+			return "", fmt.Errorf("unknown format: %s (use emoji, plain, tmux or a Go template)", format)
+		}
+		text = format
+	}
+
+	tmpl, err := template.New("statusline").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid format: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, c); err != nil {
+		return "", fmt.Errorf("invalid format: %w", err)
+	}
+	return b.String(), nil
+}
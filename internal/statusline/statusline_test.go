@@ -0,0 +1,54 @@
+package statusline
+
+import (
+	"testing"
+)
+
+func TestFromStatuses(t *testing.T) {
+	c := FromStatuses(map[string]int{
+		"running": 3,
+		"paused":  1,
+		"created": 1,
+		"stopped": 2,
+		"unknown": 5,
+	})
+
+	if c.Running != 3 || c.Waiting != 2 || c.Stopped != 2 {
+		t.Errorf("Unexpected counts: %+v", c)
+	}
+	if c.Total() != 7 {
+		t.Errorf("Expected total 7, got %d", c.Total())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	c := Counts{Running: 3, Waiting: 1, Stopped: 0}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"", "🟢3 🟡1 🔴0"},
+		{"emoji", "🟢3 🟡1 🔴0"},
+		{"plain", "R3 W1 S0"},
+		{"tmux", "#[fg=green]●3 #[fg=yellow]●1 #[fg=red]●0#[default]"},
+		{"{{.Running}}/{{.Total}}", "3/4"},
+	}
+
+	for _, tc := range tests {
+		got, err := Format(c, tc.format)
+		if err != nil {
+			t.Errorf("Format(%q) failed: %v", tc.format, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("Format(%q) = %q, want %q", tc.format, got, tc.expected)
+		}
+	}
+
+	for _, bad := range []string{"fancy", "{{.Nope}}", "{{"} {
+		if _, err := Format(c, bad); err == nil {
+			t.Errorf("Format(%q) should fail", bad)
+		}
+	}
+}
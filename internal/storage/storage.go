@@ -3,8 +3,10 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/astoreyai/goblin-forge/internal/errs"
 	_ "modernc.org/sqlite"
 )
 
@@ -16,7 +18,9 @@ type DB struct {
 
 // New creates a new database connection and runs migrations
 func New(path string) (*DB, error) {
-	conn, err := sql.Open("sqlite", path)
+	// Every pooled connection waits on a busy database rather than failing,
+	// down to the pragmas below when many gforge processes start at once
+	conn, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -26,7 +30,6 @@ func New(path string) (*DB, error) {
 		"PRAGMA foreign_keys = ON",
 		"PRAGMA journal_mode = WAL",
 		"PRAGMA synchronous = NORMAL",
-		"PRAGMA busy_timeout = 5000",
 	}
 
 	for _, pragma := range pragmas {
@@ -107,11 +110,244 @@ func (db *DB) migrate() error {
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Activity log; outlives the goblins it describes
+		`CREATE TABLE IF NOT EXISTS events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			goblin_name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			detail TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// User-defined short names for goblins
+		`CREATE TABLE IF NOT EXISTS aliases (
+			alias TEXT PRIMARY KEY,
+			goblin_id TEXT NOT NULL,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Rolling summaries of each goblin's output
+		`CREATE TABLE IF NOT EXISTS summaries (
+			goblin_id TEXT PRIMARY KEY,
+			goblin_name TEXT NOT NULL,
+			summary TEXT NOT NULL,
+			output_hash TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Agent requests made under a rate limit, keyed by agent or provider
+		`CREATE TABLE IF NOT EXISTS rate_requests (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			key TEXT NOT NULL,
+			made_at INTEGER NOT NULL
+		)`,
+
+		// Spawns waiting for a concurrency or GPU slot, one row per
+		// waiting gforge process
+		`CREATE TABLE IF NOT EXISTS spawn_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			local BOOLEAN NOT NULL DEFAULT FALSE,
+			rank INTEGER NOT NULL,
+			pid INTEGER NOT NULL,
+			queued_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Tasks waiting for their goblin to finish the one it is on
+		`CREATE TABLE IF NOT EXISTS task_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			task TEXT NOT NULL,
+			queued_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Estimated token usage and cost of each request to an agent. Rows
+		// outlive their goblin so reports cover killed goblins too.
+		`CREATE TABLE IF NOT EXISTS usage (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			goblin_name TEXT NOT NULL,
+			agent TEXT NOT NULL,
+			project TEXT NOT NULL DEFAULT '',
+			input_tokens INTEGER NOT NULL DEFAULT 0,
+			output_tokens INTEGER NOT NULL DEFAULT 0,
+			cost REAL NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Periodic goblin counts for gforge stats trends
+		`CREATE TABLE IF NOT EXISTS stats_snapshots (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			total INTEGER NOT NULL,
+			running INTEGER NOT NULL,
+			paused INTEGER NOT NULL,
+			completed INTEGER NOT NULL,
+			throttled INTEGER NOT NULL,
+			taken_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Credentials found in a goblin's branch by the last secret scan
+		`CREATE TABLE IF NOT EXISTS secret_findings (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			file TEXT NOT NULL,
+			line INTEGER NOT NULL,
+			rule TEXT NOT NULL,
+			redacted TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Observations people make while supervising a goblin
+		`CREATE TABLE IF NOT EXISTS notes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			body TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Size of each goblin branch when it was pushed, for reporting
+		// how often work outgrows one review. Rows outlive their goblin.
+		`CREATE TABLE IF NOT EXISTS diff_sizes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			goblin_name TEXT NOT NULL,
+			project TEXT NOT NULL DEFAULT '',
+			files INTEGER NOT NULL,
+			insertions INTEGER NOT NULL,
+			deletions INTEGER NOT NULL,
+			oversized BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Tombstones of killed goblins, for browsing past runs. Rows
+		// outlive their goblin.
+		`CREATE TABLE IF NOT EXISTS goblin_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			agent TEXT NOT NULL,
+			project TEXT NOT NULL DEFAULT '',
+			branch TEXT NOT NULL DEFAULT '',
+			outcome TEXT NOT NULL,
+			files INTEGER NOT NULL DEFAULT 0,
+			insertions INTEGER NOT NULL DEFAULT 0,
+			deletions INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			ended_at DATETIME NOT NULL
+		)`,
+
+		// Results of the last run of a project's checks on a goblin
+		`CREATE TABLE IF NOT EXISTS check_results (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			command TEXT NOT NULL,
+			passed BOOLEAN NOT NULL,
+			output TEXT NOT NULL DEFAULT '',
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Issues goblins report their progress to
+		`CREATE TABLE IF NOT EXISTS issue_links (
+			goblin_id TEXT PRIMARY KEY,
+			ref TEXT NOT NULL,
+			commented_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// GitHub Actions workflows dispatched for goblins
+		`CREATE TABLE IF NOT EXISTS workflow_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			trigger TEXT NOT NULL,
+			workflow TEXT NOT NULL,
+			event TEXT NOT NULL,
+			repo TEXT NOT NULL DEFAULT '',
+			ref TEXT NOT NULL DEFAULT '',
+			run_id INTEGER NOT NULL DEFAULT 0,
+			status TEXT NOT NULL DEFAULT '',
+			conclusion TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Paths goblins have claimed in their project so others keep off
+		`CREATE TABLE IF NOT EXISTS path_locks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			goblin_id TEXT NOT NULL,
+			project TEXT NOT NULL,
+			path TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (goblin_id, path),
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Notes cooperating goblins share with their squad. Entries
+		// outlive the goblin that wrote them.
+		`CREATE TABLE IF NOT EXISTS scratchpad (
+			squad TEXT NOT NULL,
+			key TEXT NOT NULL,
+			value TEXT NOT NULL,
+			goblin_name TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (squad, key)
+		)`,
+
+		// Files two goblins both change, as of the daemon's last overlap
+		// check; one row per shared file
+		`CREATE TABLE IF NOT EXISTS overlaps (
+			goblin_id TEXT NOT NULL,
+			other_id TEXT NOT NULL,
+			file TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (goblin_id, other_id, file),
+			FOREIGN KEY (goblin_id) REFERENCES goblins(id) ON DELETE CASCADE,
+			FOREIGN KEY (other_id) REFERENCES goblins(id) ON DELETE CASCADE
+		)`,
+
+		// Recurring goblin runs, from gforge schedule add or the config
+		`CREATE TABLE IF NOT EXISTS schedules (
+			name TEXT PRIMARY KEY,
+			cron TEXT NOT NULL,
+			project_path TEXT NOT NULL,
+			agent TEXT NOT NULL DEFAULT '',
+			task TEXT NOT NULL DEFAULT '',
+			priority TEXT NOT NULL DEFAULT '',
+			squad TEXT NOT NULL DEFAULT '',
+			from_config BOOLEAN NOT NULL DEFAULT FALSE,
+			next_run DATETIME NOT NULL,
+			last_run DATETIME,
+			last_goblin TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+
 		// Indexes
 		`CREATE INDEX IF NOT EXISTS idx_goblins_status ON goblins(status)`,
 		`CREATE INDEX IF NOT EXISTS idx_goblins_name ON goblins(name)`,
 		`CREATE INDEX IF NOT EXISTS idx_output_logs_goblin ON output_logs(goblin_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_projects_path ON projects(path)`,
+		`CREATE INDEX IF NOT EXISTS idx_events_created ON events(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_events_goblin ON events(goblin_id, type)`,
+		`CREATE INDEX IF NOT EXISTS idx_rate_requests_key ON rate_requests(key, made_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_usage_created ON usage(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_stats_snapshots_taken ON stats_snapshots(taken_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_secret_findings_goblin ON secret_findings(goblin_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_notes_goblin ON notes(goblin_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_diff_sizes_created ON diff_sizes(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_goblin_history_ended ON goblin_history(ended_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_check_results_goblin ON check_results(goblin_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_workflow_runs_goblin ON workflow_runs(goblin_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_path_locks_project ON path_locks(project)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_queue_goblin ON task_queue(goblin_id)`,
 	}
 
 	for _, m := range migrations {
@@ -120,6 +356,66 @@ func (db *DB) migrate() error {
 		}
 	}
 
+	// Columns added after the initial schema
+	columns := []struct {
+		table, column, definition string
+	}{
+		{"goblins", "base_ref", "TEXT NOT NULL DEFAULT ''"},
+		{"goblins", "priority", "TEXT NOT NULL DEFAULT 'normal'"},
+		{"goblins", "tmux_socket", "TEXT NOT NULL DEFAULT ''"},
+		{"voice_commands", "decision", "TEXT NOT NULL DEFAULT ''"},
+		{"goblins", "pinned", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"sessions", "start_commit", "TEXT NOT NULL DEFAULT ''"},
+		{"sessions", "end_commit", "TEXT NOT NULL DEFAULT ''"},
+		{"goblins", "squad", "TEXT NOT NULL DEFAULT ''"},
+		{"goblins", "commit_seen", "TEXT NOT NULL DEFAULT ''"},
+		{"secret_findings", "commit_hash", "TEXT NOT NULL DEFAULT ''"},
+	}
+
+	for _, c := range columns {
+		if err := db.addColumn(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addColumn adds a column to an existing table unless it is already there
+func (db *DB) addColumn(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	rows.Close()
+
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := db.conn.Exec(query); err != nil {
+		// Another gforge opening a new database at the same time got there
+		// first
+		if strings.Contains(err.Error(), "duplicate column name") {
+			return nil
+		}
+		return fmt.Errorf("migration failed: %w\nSQL: %s", err, query)
+	}
 	return nil
 }
 
@@ -133,10 +429,34 @@ type Goblin struct {
 	WorktreePath string
 	Branch       string
 	TmuxSession  string
+	TmuxSocket   string // Socket the session runs on; empty for tmux.socket_name
+	BaseRef      string // Commit the goblin's worktree started from
+	Priority     string // high, normal or low
+	Pinned       bool   // Listed first and never preempted
+	Squad        string // Scratchpad it shares; empty for its project's
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
 
+// goblinColumns is the column list matching scanGoblin
+const goblinColumns = `id, name, agent, status, project_path, worktree_path, branch, tmux_session, tmux_socket, base_ref, priority, pinned, squad, created_at, updated_at`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanGoblin scans a row selected with goblinColumns
+func scanGoblin(row rowScanner) (*Goblin, error) {
+	var g Goblin
+	err := row.Scan(&g.ID, &g.Name, &g.Agent, &g.Status, &g.ProjectPath,
+		&g.WorktreePath, &g.Branch, &g.TmuxSession, &g.TmuxSocket, &g.BaseRef, &g.Priority, &g.Pinned, &g.Squad, &g.CreatedAt, &g.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
 // Age returns a human-readable age string
 func (g *Goblin) Age() string {
 	duration := time.Since(g.CreatedAt)
@@ -154,44 +474,72 @@ func (g *Goblin) Age() string {
 // CreateGoblin inserts a new goblin
 func (db *DB) CreateGoblin(g *Goblin) error {
 	query := `
-		INSERT INTO goblins (id, name, agent, status, project_path, worktree_path, branch, tmux_session)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO goblins (id, name, agent, status, project_path, worktree_path, branch, tmux_session, tmux_socket, base_ref, priority, squad)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err := db.conn.Exec(query,
-		g.ID, g.Name, g.Agent, g.Status, g.ProjectPath, g.WorktreePath, g.Branch, g.TmuxSession)
+		g.ID, g.Name, g.Agent, g.Status, g.ProjectPath, g.WorktreePath, g.Branch, g.TmuxSession, g.TmuxSocket, g.BaseRef,
+		priority(g.Priority), g.Squad)
 	if err != nil {
 		return fmt.Errorf("failed to create goblin: %w", err)
 	}
 	return nil
 }
 
-// GetGoblin retrieves a goblin by ID or name
-func (db *DB) GetGoblin(idOrName string) (*Goblin, error) {
+// priority defaults an unset goblin priority to normal
+func priority(p string) string {
+	if p == "" {
+		return "normal"
+	}
+	return p
+}
+
+// sqliteTime formats a time the way CURRENT_TIMESTAMP stores it
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// RestoreGoblin inserts a goblin keeping its original timestamps, for
+// importing exported state
+func (db *DB) RestoreGoblin(g *Goblin) error {
 	query := `
-		SELECT id, name, agent, status, project_path, worktree_path, branch, tmux_session, created_at, updated_at
+		INSERT INTO goblins (id, name, agent, status, project_path, worktree_path, branch, tmux_session, tmux_socket, base_ref, priority, squad, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.conn.Exec(query,
+		g.ID, g.Name, g.Agent, g.Status, g.ProjectPath, g.WorktreePath, g.Branch, g.TmuxSession, g.TmuxSocket, g.BaseRef,
+		priority(g.Priority), g.Squad, sqliteTime(g.CreatedAt), sqliteTime(g.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to restore goblin: %w", err)
+	}
+	return nil
+}
+
+// GetGoblin retrieves a goblin by ID, name or alias. IDs and names take
+// precedence over aliases.
+func (db *DB) GetGoblin(idOrName string) (*Goblin, error) {
+	query := `SELECT ` + goblinColumns + `
 		FROM goblins
 		WHERE id = ? OR name = ?
+			OR id = (SELECT goblin_id FROM aliases WHERE alias = ?)
+		ORDER BY (id = ? OR name = ?) DESC
+		LIMIT 1
 	`
-	row := db.conn.QueryRow(query, idOrName, idOrName)
-
-	var g Goblin
-	err := row.Scan(&g.ID, &g.Name, &g.Agent, &g.Status, &g.ProjectPath,
-		&g.WorktreePath, &g.Branch, &g.TmuxSession, &g.CreatedAt, &g.UpdatedAt)
+	g, err := scanGoblin(db.conn.QueryRow(query, idOrName, idOrName, idOrName, idOrName, idOrName))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get goblin: %w", err)
 	}
-	return &g, nil
+	return g, nil
 }
 
-// ListGoblins returns all goblins
+// ListGoblins returns all goblins, pinned ones first, then newest first
 func (db *DB) ListGoblins() ([]*Goblin, error) {
-	query := `
-		SELECT id, name, agent, status, project_path, worktree_path, branch, tmux_session, created_at, updated_at
+	query := `SELECT ` + goblinColumns + `
 		FROM goblins
-		ORDER BY created_at DESC
+		ORDER BY pinned DESC, created_at DESC, rowid DESC
 	`
 	rows, err := db.conn.Query(query)
 	if err != nil {
@@ -201,22 +549,41 @@ func (db *DB) ListGoblins() ([]*Goblin, error) {
 
 	var goblins []*Goblin
 	for rows.Next() {
-		var g Goblin
-		err := rows.Scan(&g.ID, &g.Name, &g.Agent, &g.Status, &g.ProjectPath,
-			&g.WorktreePath, &g.Branch, &g.TmuxSession, &g.CreatedAt, &g.UpdatedAt)
+		g, err := scanGoblin(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan goblin: %w", err)
 		}
-		goblins = append(goblins, &g)
+		goblins = append(goblins, g)
 	}
 
 	return goblins, nil
 }
 
+// GetGoblinByIndex returns the goblin at a 1-based position in
+// ListGoblins order, as numbered by `gforge list`
+func (db *DB) GetGoblinByIndex(index int) (*Goblin, error) {
+	if index < 1 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + goblinColumns + `
+		FROM goblins
+		ORDER BY pinned DESC, created_at DESC, rowid DESC
+		LIMIT 1 OFFSET ?
+	`
+	g, err := scanGoblin(db.conn.QueryRow(query, index-1))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goblin: %w", err)
+	}
+	return g, nil
+}
+
 // ListGoblinsByStatus returns goblins with a specific status
 func (db *DB) ListGoblinsByStatus(status string) ([]*Goblin, error) {
-	query := `
-		SELECT id, name, agent, status, project_path, worktree_path, branch, tmux_session, created_at, updated_at
+	query := `SELECT ` + goblinColumns + `
 		FROM goblins
 		WHERE status = ?
 		ORDER BY created_at DESC
@@ -229,13 +596,11 @@ func (db *DB) ListGoblinsByStatus(status string) ([]*Goblin, error) {
 
 	var goblins []*Goblin
 	for rows.Next() {
-		var g Goblin
-		err := rows.Scan(&g.ID, &g.Name, &g.Agent, &g.Status, &g.ProjectPath,
-			&g.WorktreePath, &g.Branch, &g.TmuxSession, &g.CreatedAt, &g.UpdatedAt)
+		g, err := scanGoblin(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan goblin: %w", err)
 		}
-		goblins = append(goblins, &g)
+		goblins = append(goblins, g)
 	}
 
 	return goblins, nil
@@ -251,9 +616,48 @@ func (db *DB) UpdateGoblinStatus(id, status string) error {
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("goblin not found: %s", id)
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, id)
+	}
+
+	return nil
+}
+
+// SetGoblinPinned pins or unpins a goblin
+func (db *DB) SetGoblinPinned(id string, pinned bool) error {
+	query := `UPDATE goblins SET pinned = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? OR name = ?`
+	result, err := db.conn.Exec(query, pinned, id, id)
+	if err != nil {
+		return fmt.Errorf("failed to update goblin pin: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, id)
+	}
+
+	return nil
+}
+
+// CommitSeen returns the last commit of a goblin's branch reported as
+// created, or "" before the first
+func (db *DB) CommitSeen(id string) (string, error) {
+	var commit string
+	err := db.conn.QueryRow(`SELECT commit_seen FROM goblins WHERE id = ?`, id).Scan(&commit)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, id)
 	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get seen commit: %w", err)
+	}
+	return commit, nil
+}
 
+// SetCommitSeen records the last commit of a goblin's branch reported as
+// created
+func (db *DB) SetCommitSeen(id, commit string) error {
+	if _, err := db.conn.Exec(`UPDATE goblins SET commit_seen = ? WHERE id = ?`, commit, id); err != nil {
+		return fmt.Errorf("failed to set seen commit: %w", err)
+	}
 	return nil
 }
 
@@ -267,7 +671,7 @@ func (db *DB) DeleteGoblin(id string) error {
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("goblin not found: %s", id)
+		return fmt.Errorf("%w: %s", errs.ErrGoblinNotFound, id)
 	}
 
 	return nil
@@ -279,6 +683,7 @@ type Stats struct {
 	Running   int
 	Paused    int
 	Completed int
+	Throttled int
 }
 
 // GetStats returns aggregate statistics
@@ -298,7 +703,7 @@ func (db *DB) GetStats() (*Stats, error) {
 	}
 
 	// Paused count
-	row = db.conn.QueryRow("SELECT COUNT(*) FROM goblins WHERE status = 'paused'")
+	row = db.conn.QueryRow("SELECT COUNT(*) FROM goblins WHERE status IN ('paused', 'preempted')")
 	if err := row.Scan(&stats.Paused); err != nil {
 		return nil, err
 	}
@@ -309,9 +714,37 @@ func (db *DB) GetStats() (*Stats, error) {
 		return nil, err
 	}
 
+	// Throttled count
+	row = db.conn.QueryRow("SELECT COUNT(*) FROM goblins WHERE status = 'throttled'")
+	if err := row.Scan(&stats.Throttled); err != nil {
+		return nil, err
+	}
+
 	return stats, nil
 }
 
+// CountByStatus returns the number of goblins in each status in a single
+// query, for callers that poll frequently
+func (db *DB) CountByStatus() (map[string]int, error) {
+	rows, err := db.conn.Query("SELECT status, COUNT(*) FROM goblins GROUP BY status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count goblins: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("failed to count goblins: %w", err)
+		}
+		counts[status] = n
+	}
+
+	return counts, nil
+}
+
 // LogOutput stores agent output
 func (db *DB) LogOutput(goblinID, content string) error {
 	query := `INSERT INTO output_logs (goblin_id, content) VALUES (?, ?)`
@@ -349,3 +782,1356 @@ func (db *DB) GetRecentOutput(goblinID string, limit int) ([]string, error) {
 
 	return output, nil
 }
+
+// TaskRecord is a task sent to a goblin, stored in the sessions table.
+// The commits its goblin's worktree was at when it was sent and when it
+// ended bound the changes it produced.
+type TaskRecord struct {
+	ID          string
+	GoblinID    string
+	Task        string
+	StartedAt   time.Time
+	EndedAt     time.Time // Zero while the task is open
+	StartCommit string
+	EndCommit   string
+}
+
+// RecordTask stores a task sent to a goblin
+func (db *DB) RecordTask(goblinID, task string) error {
+	return db.RecordTaskFrom(goblinID, task, "")
+}
+
+// RecordTaskFrom stores a task sent to a goblin whose worktree was at
+// startCommit
+func (db *DB) RecordTaskFrom(goblinID, task, startCommit string) error {
+	id := fmt.Sprintf("%s-%d", goblinID, time.Now().UnixNano())
+	query := `INSERT INTO sessions (id, goblin_id, task, start_commit) VALUES (?, ?, ?, ?)`
+	if _, err := db.conn.Exec(query, id, goblinID, task, startCommit); err != nil {
+		return fmt.Errorf("failed to record task: %w", err)
+	}
+	return nil
+}
+
+// EndTasks closes a goblin's open tasks at endCommit, reporting how many
+// there were
+func (db *DB) EndTasks(goblinID, endCommit string) (int, error) {
+	query := `
+		UPDATE sessions SET ended_at = CURRENT_TIMESTAMP, end_commit = ?, status = 'completed'
+		WHERE goblin_id = ? AND task IS NOT NULL AND ended_at IS NULL
+	`
+	result, err := db.conn.Exec(query, endCommit, goblinID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to end tasks: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// RecordTaskAt stores a task with the time it was originally sent
+func (db *DB) RecordTaskAt(goblinID, task string, sentAt time.Time) error {
+	id := fmt.Sprintf("%s-%d", goblinID, time.Now().UnixNano())
+	query := `INSERT INTO sessions (id, goblin_id, task, started_at) VALUES (?, ?, ?, ?)`
+	if _, err := db.conn.Exec(query, id, goblinID, task, sqliteTime(sentAt)); err != nil {
+		return fmt.Errorf("failed to record task: %w", err)
+	}
+	return nil
+}
+
+// ListTasks returns the tasks sent to a goblin in the order they were sent
+func (db *DB) ListTasks(goblinID string) ([]*TaskRecord, error) {
+	query := `
+		SELECT id, goblin_id, task, started_at, ended_at, start_commit, end_commit FROM sessions
+		WHERE goblin_id = ? AND task IS NOT NULL
+		ORDER BY started_at, rowid
+	`
+	rows, err := db.conn.Query(query, goblinID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*TaskRecord
+	for rows.Next() {
+		var t TaskRecord
+		var ended sql.NullTime
+		if err := rows.Scan(&t.ID, &t.GoblinID, &t.Task, &t.StartedAt, &ended, &t.StartCommit, &t.EndCommit); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		t.EndedAt = ended.Time
+		tasks = append(tasks, &t)
+	}
+
+	return tasks, nil
+}
+
+// Event is an entry in the goblin activity log
+type Event struct {
+	ID         int64
+	GoblinID   string
+	GoblinName string
+	Type       string
+	Detail     string
+	CreatedAt  time.Time
+}
+
+// RecordEvent appends an entry to the activity log
+func (db *DB) RecordEvent(goblinID, goblinName, eventType, detail string) error {
+	query := `INSERT INTO events (goblin_id, goblin_name, type, detail) VALUES (?, ?, ?, ?)`
+	if _, err := db.conn.Exec(query, goblinID, goblinName, eventType, detail); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	return nil
+}
+
+// ListEvents returns activity log entries recorded at or after since,
+// oldest first
+func (db *DB) ListEvents(since time.Time) ([]*Event, error) {
+	query := `
+		SELECT id, goblin_id, goblin_name, type, detail, created_at FROM events
+		WHERE created_at >= ?
+		ORDER BY created_at, id
+	`
+	rows, err := db.conn.Query(query, sqliteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.GoblinID, &e.GoblinName, &e.Type, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, &e)
+	}
+
+	return events, nil
+}
+
+// LastEvent returns the latest event of eventType recorded for a goblin,
+// or nil if there is none
+func (db *DB) LastEvent(goblinID, eventType string) (*Event, error) {
+	query := `
+		SELECT id, goblin_id, goblin_name, type, detail, created_at FROM events
+		WHERE goblin_id = ? AND type = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+	var e Event
+	err := db.conn.QueryRow(query, goblinID, eventType).Scan(&e.ID, &e.GoblinID, &e.GoblinName, &e.Type, &e.Detail, &e.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return &e, nil
+}
+
+// SetAlias points an alias at a goblin, replacing any existing target
+func (db *DB) SetAlias(alias, goblinID string) error {
+	query := `INSERT OR REPLACE INTO aliases (alias, goblin_id) VALUES (?, ?)`
+	if _, err := db.conn.Exec(query, alias, goblinID); err != nil {
+		return fmt.Errorf("failed to set alias: %w", err)
+	}
+	return nil
+}
+
+// DeleteAlias removes an alias
+func (db *DB) DeleteAlias(alias string) error {
+	result, err := db.conn.Exec(`DELETE FROM aliases WHERE alias = ?`, alias)
+	if err != nil {
+		return fmt.Errorf("failed to delete alias: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("alias not found: %s", alias)
+	}
+	return nil
+}
+
+// ListAliases returns every alias keyed by alias name, mapped to goblin ID
+func (db *DB) ListAliases() (map[string]string, error) {
+	rows, err := db.conn.Query(`SELECT alias, goblin_id FROM aliases ORDER BY alias`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make(map[string]string)
+	for rows.Next() {
+		var alias, goblinID string
+		if err := rows.Scan(&alias, &goblinID); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases[alias] = goblinID
+	}
+
+	return aliases, nil
+}
+
+// Summary is the latest condensed account of a goblin's output
+type Summary struct {
+	GoblinID   string
+	GoblinName string
+	Summary    string
+	OutputHash string // Hash of the output last summarized
+	UpdatedAt  time.Time
+}
+
+// SaveSummary stores a goblin's summary, replacing the previous one
+func (db *DB) SaveSummary(s *Summary) error {
+	query := `
+		INSERT OR REPLACE INTO summaries (goblin_id, goblin_name, summary, output_hash, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`
+	if _, err := db.conn.Exec(query, s.GoblinID, s.GoblinName, s.Summary, s.OutputHash); err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+	return nil
+}
+
+// GetSummary returns a goblin's summary, or nil if it has none
+func (db *DB) GetSummary(goblinID string) (*Summary, error) {
+	query := `
+		SELECT goblin_id, goblin_name, summary, output_hash, updated_at FROM summaries
+		WHERE goblin_id = ?
+	`
+	var s Summary
+	err := db.conn.QueryRow(query, goblinID).Scan(&s.GoblinID, &s.GoblinName, &s.Summary, &s.OutputHash, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get summary: %w", err)
+	}
+	return &s, nil
+}
+
+// ListSummaries returns summaries updated at or after since, by goblin name
+func (db *DB) ListSummaries(since time.Time) ([]*Summary, error) {
+	query := `
+		SELECT goblin_id, goblin_name, summary, output_hash, updated_at FROM summaries
+		WHERE updated_at >= ?
+		ORDER BY goblin_name
+	`
+	rows, err := db.conn.Query(query, sqliteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list summaries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*Summary
+	for rows.Next() {
+		var s Summary
+		if err := rows.Scan(&s.GoblinID, &s.GoblinName, &s.Summary, &s.OutputHash, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan summary: %w", err)
+		}
+		summaries = append(summaries, &s)
+	}
+
+	return summaries, nil
+}
+
+// AcquireRequest records a request for key if fewer than limit were made
+// in the window before now, returning 0. Otherwise nothing is recorded and
+// the time until the oldest request leaves the window is returned. The
+// check and insert share a transaction so concurrent gforge processes
+// respect the same limit.
+func (db *DB) AcquireRequest(key string, limit int, window time.Duration, now time.Time) (time.Duration, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire request: %w", err)
+	}
+	defer tx.Rollback()
+
+	start := now.Add(-window).UnixNano()
+	if _, err := tx.Exec(`DELETE FROM rate_requests WHERE key = ? AND made_at <= ?`, key, start); err != nil {
+		return 0, fmt.Errorf("failed to acquire request: %w", err)
+	}
+
+	var count int
+	var oldest sql.NullInt64
+	row := tx.QueryRow(`SELECT COUNT(*), MIN(made_at) FROM rate_requests WHERE key = ?`, key)
+	if err := row.Scan(&count, &oldest); err != nil {
+		return 0, fmt.Errorf("failed to acquire request: %w", err)
+	}
+
+	if count >= limit {
+		return time.Duration(oldest.Int64 - start), nil
+	}
+
+	if _, err := tx.Exec(`INSERT INTO rate_requests (key, made_at) VALUES (?, ?)`, key, now.UnixNano()); err != nil {
+		return 0, fmt.Errorf("failed to acquire request: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to acquire request: %w", err)
+	}
+	return 0, nil
+}
+
+// ReleaseRequest takes back a request AcquireRequest recorded for key at
+// madeAt
+func (db *DB) ReleaseRequest(key string, madeAt time.Time) error {
+	_, err := db.conn.Exec(`DELETE FROM rate_requests WHERE id = (
+		SELECT id FROM rate_requests WHERE key = ? AND made_at = ? LIMIT 1)`, key, madeAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to release request: %w", err)
+	}
+	return nil
+}
+
+// CountRequests returns how many requests were made for key since since
+func (db *DB) CountRequests(key string, since time.Time) (int, error) {
+	var count int
+	row := db.conn.QueryRow(`SELECT COUNT(*) FROM rate_requests WHERE key = ? AND made_at > ?`, key, since.UnixNano())
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count requests: %w", err)
+	}
+	return count, nil
+}
+
+// QueuedSpawn is a spawn waiting for a free slot
+type QueuedSpawn struct {
+	ID       int64
+	Name     string
+	Local    bool // Needs a local-model slot as well as a goblin slot
+	Rank     int  // Higher is admitted first
+	PID      int  // Waiting gforge process
+	QueuedAt time.Time
+}
+
+// EnqueueSpawn adds a waiting spawn and returns its queue ID
+func (db *DB) EnqueueSpawn(q *QueuedSpawn) (int64, error) {
+	result, err := db.conn.Exec(`INSERT INTO spawn_queue (name, local, rank, pid) VALUES (?, ?, ?, ?)`,
+		q.Name, q.Local, q.Rank, q.PID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue spawn: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// DequeueSpawn removes a spawn from the queue
+func (db *DB) DequeueSpawn(id int64) error {
+	if _, err := db.conn.Exec(`DELETE FROM spawn_queue WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to dequeue spawn: %w", err)
+	}
+	return nil
+}
+
+// ListSpawnQueue returns waiting spawns in admission order: highest rank
+// first, then first come
+func (db *DB) ListSpawnQueue() ([]*QueuedSpawn, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, name, local, rank, pid, queued_at FROM spawn_queue
+		ORDER BY rank DESC, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spawn queue: %w", err)
+	}
+	defer rows.Close()
+
+	var queue []*QueuedSpawn
+	for rows.Next() {
+		var q QueuedSpawn
+		if err := rows.Scan(&q.ID, &q.Name, &q.Local, &q.Rank, &q.PID, &q.QueuedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan queued spawn: %w", err)
+		}
+		queue = append(queue, &q)
+	}
+
+	return queue, nil
+}
+
+// QueuedTask is a task waiting for its goblin to finish the one it is on
+type QueuedTask struct {
+	ID       int64
+	GoblinID string
+	Task     string
+	QueuedAt time.Time
+}
+
+// EnqueueTask adds a task to the end of a goblin's queue and returns its
+// queue ID
+func (db *DB) EnqueueTask(goblinID, task string) (int64, error) {
+	result, err := db.conn.Exec(`INSERT INTO task_queue (goblin_id, task) VALUES (?, ?)`, goblinID, task)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue task: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// EnqueueTaskAt adds a task to the end of a goblin's queue with the time
+// it was originally queued
+func (db *DB) EnqueueTaskAt(goblinID, task string, queuedAt time.Time) error {
+	_, err := db.conn.Exec(`INSERT INTO task_queue (goblin_id, task, queued_at) VALUES (?, ?, ?)`,
+		goblinID, task, sqliteTime(queuedAt))
+	if err != nil {
+		return fmt.Errorf("failed to queue task: %w", err)
+	}
+	return nil
+}
+
+// DequeueTask removes a task from its goblin's queue, reporting whether it
+// was still there
+func (db *DB) DequeueTask(id int64) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM task_queue WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to dequeue task: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// RequeueTask puts a dequeued task back in its place in its goblin's
+// queue
+func (db *DB) RequeueTask(q *QueuedTask) error {
+	_, err := db.conn.Exec(`INSERT INTO task_queue (id, goblin_id, task, queued_at) VALUES (?, ?, ?, ?)`,
+		q.ID, q.GoblinID, q.Task, sqliteTime(q.QueuedAt))
+	if err != nil {
+		return fmt.Errorf("failed to requeue task: %w", err)
+	}
+	return nil
+}
+
+// ClearTaskQueue drops every task queued for a goblin and returns how many
+// there were
+func (db *DB) ClearTaskQueue(goblinID string) (int, error) {
+	result, err := db.conn.Exec(`DELETE FROM task_queue WHERE goblin_id = ?`, goblinID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear task queue: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// ListTaskQueue returns the tasks queued for a goblin, or for every goblin
+// when goblinID is empty, first queued first
+func (db *DB) ListTaskQueue(goblinID string) ([]*QueuedTask, error) {
+	rows, err := db.conn.Query(`
+		SELECT id, goblin_id, task, queued_at FROM task_queue
+		WHERE ? = '' OR goblin_id = ?
+		ORDER BY id
+	`, goblinID, goblinID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task queue: %w", err)
+	}
+	defer rows.Close()
+
+	var queue []*QueuedTask
+	for rows.Next() {
+		var q QueuedTask
+		if err := rows.Scan(&q.ID, &q.GoblinID, &q.Task, &q.QueuedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan queued task: %w", err)
+		}
+		queue = append(queue, &q)
+	}
+
+	return queue, nil
+}
+
+// Usage is the estimated token usage and cost of agent requests
+type Usage struct {
+	ID           int64
+	GoblinID     string
+	GoblinName   string
+	Agent        string
+	Project      string
+	InputTokens  int
+	OutputTokens int
+	Cost         float64 // USD
+	CreatedAt    time.Time
+}
+
+// RecordUsage stores a usage record
+func (db *DB) RecordUsage(u *Usage) error {
+	query := `
+		INSERT INTO usage (goblin_id, goblin_name, agent, project, input_tokens, output_tokens, cost)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := db.conn.Exec(query, u.GoblinID, u.GoblinName, u.Agent, u.Project,
+		u.InputTokens, u.OutputTokens, u.Cost); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// ListUsage returns usage recorded at or after since, oldest first
+func (db *DB) ListUsage(since time.Time) ([]*Usage, error) {
+	query := `
+		SELECT id, goblin_id, goblin_name, agent, project, input_tokens, output_tokens, cost, created_at
+		FROM usage WHERE created_at >= ?
+		ORDER BY created_at, id
+	`
+	rows, err := db.conn.Query(query, sqliteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*Usage
+	for rows.Next() {
+		var u Usage
+		if err := rows.Scan(&u.ID, &u.GoblinID, &u.GoblinName, &u.Agent, &u.Project,
+			&u.InputTokens, &u.OutputTokens, &u.Cost, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		usage = append(usage, &u)
+	}
+
+	return usage, nil
+}
+
+// OutputTokens returns the output tokens recorded so far for a goblin
+func (db *DB) OutputTokens(goblinID string) (int, error) {
+	var total int
+	err := db.conn.QueryRow(`SELECT COALESCE(SUM(output_tokens), 0) FROM usage WHERE goblin_id = ?`, goblinID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum output tokens: %w", err)
+	}
+	return total, nil
+}
+
+// StatsSnapshot is the goblin counts at a point in time
+type StatsSnapshot struct {
+	Stats
+	TakenAt time.Time
+}
+
+// SaveStatsSnapshot records the current counts
+func (db *DB) SaveStatsSnapshot(s *Stats) error {
+	query := `
+		INSERT INTO stats_snapshots (total, running, paused, completed, throttled)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	if _, err := db.conn.Exec(query, s.Total, s.Running, s.Paused, s.Completed, s.Throttled); err != nil {
+		return fmt.Errorf("failed to save stats snapshot: %w", err)
+	}
+	return nil
+}
+
+// LastStatsSnapshot returns when the latest snapshot was taken, or the
+// zero time if there is none
+func (db *DB) LastStatsSnapshot() (time.Time, error) {
+	var takenAt time.Time
+	err := db.conn.QueryRow(`SELECT taken_at FROM stats_snapshots ORDER BY taken_at DESC, id DESC LIMIT 1`).Scan(&takenAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last stats snapshot: %w", err)
+	}
+	return takenAt, nil
+}
+
+// ListStatsSnapshots returns snapshots taken at or after since, oldest first
+func (db *DB) ListStatsSnapshots(since time.Time) ([]*StatsSnapshot, error) {
+	query := `
+		SELECT total, running, paused, completed, throttled, taken_at FROM stats_snapshots
+		WHERE taken_at >= ?
+		ORDER BY taken_at, id
+	`
+	rows, err := db.conn.Query(query, sqliteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stats snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*StatsSnapshot
+	for rows.Next() {
+		var s StatsSnapshot
+		if err := rows.Scan(&s.Total, &s.Running, &s.Paused, &s.Completed, &s.Throttled, &s.TakenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stats snapshot: %w", err)
+		}
+		snapshots = append(snapshots, &s)
+	}
+
+	return snapshots, nil
+}
+
+// SecretFinding is a credential found in a goblin's branch. Match is
+// redacted before it is stored.
+type SecretFinding struct {
+	GoblinID  string
+	Commit    string // The commit that added it
+	File      string
+	Line      int
+	Rule      string
+	Match     string
+	CreatedAt time.Time
+}
+
+// SaveSecretFindings replaces a goblin's findings with those of its latest
+// scan; an empty scan clears them
+func (db *DB) SaveSecretFindings(goblinID string, findings []*SecretFinding) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save secret findings: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM secret_findings WHERE goblin_id = ?`, goblinID); err != nil {
+		return fmt.Errorf("failed to save secret findings: %w", err)
+	}
+	for _, f := range findings {
+		query := `INSERT INTO secret_findings (goblin_id, commit_hash, file, line, rule, redacted) VALUES (?, ?, ?, ?, ?, ?)`
+		if _, err := tx.Exec(query, goblinID, f.Commit, f.File, f.Line, f.Rule, f.Match); err != nil {
+			return fmt.Errorf("failed to save secret findings: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save secret findings: %w", err)
+	}
+	return nil
+}
+
+// ListSecretFindings returns a goblin's findings by file and line
+func (db *DB) ListSecretFindings(goblinID string) ([]*SecretFinding, error) {
+	query := `
+		SELECT goblin_id, commit_hash, file, line, rule, redacted, created_at FROM secret_findings
+		WHERE goblin_id = ?
+		ORDER BY file, line, id
+	`
+	rows, err := db.conn.Query(query, goblinID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secret findings: %w", err)
+	}
+	defer rows.Close()
+
+	var findings []*SecretFinding
+	for rows.Next() {
+		var f SecretFinding
+		if err := rows.Scan(&f.GoblinID, &f.Commit, &f.File, &f.Line, &f.Rule, &f.Match, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan secret finding: %w", err)
+		}
+		findings = append(findings, &f)
+	}
+
+	return findings, nil
+}
+
+// Overlap is a pair of goblins found changing the same files
+type Overlap struct {
+	GoblinID string
+	OtherID  string
+	Files    []string
+}
+
+// SaveOverlaps replaces the stored overlaps with those of the latest
+// check; an empty check clears them
+func (db *DB) SaveOverlaps(overlaps []*Overlap) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save overlaps: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM overlaps`); err != nil {
+		return fmt.Errorf("failed to save overlaps: %w", err)
+	}
+	for _, o := range overlaps {
+		for _, file := range o.Files {
+			query := `INSERT OR IGNORE INTO overlaps (goblin_id, other_id, file) VALUES (?, ?, ?)`
+			if _, err := tx.Exec(query, o.GoblinID, o.OtherID, file); err != nil {
+				return fmt.Errorf("failed to save overlaps: %w", err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save overlaps: %w", err)
+	}
+	return nil
+}
+
+// ListOverlaps returns the stored overlaps, each pair once with its files
+// in order
+func (db *DB) ListOverlaps() ([]*Overlap, error) {
+	query := `SELECT goblin_id, other_id, file FROM overlaps ORDER BY goblin_id, other_id, file`
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overlaps: %w", err)
+	}
+	defer rows.Close()
+
+	var overlaps []*Overlap
+	for rows.Next() {
+		var goblinID, otherID, file string
+		if err := rows.Scan(&goblinID, &otherID, &file); err != nil {
+			return nil, fmt.Errorf("failed to scan overlap: %w", err)
+		}
+		if n := len(overlaps); n > 0 && overlaps[n-1].GoblinID == goblinID && overlaps[n-1].OtherID == otherID {
+			overlaps[n-1].Files = append(overlaps[n-1].Files, file)
+			continue
+		}
+		overlaps = append(overlaps, &Overlap{GoblinID: goblinID, OtherID: otherID, Files: []string{file}})
+	}
+
+	return overlaps, nil
+}
+
+// DiffSize is the size of a goblin's branch when it was checked
+type DiffSize struct {
+	ID         int64
+	GoblinID   string
+	GoblinName string
+	Project    string
+	Files      int
+	Insertions int
+	Deletions  int
+	Oversized  bool // Over git.max_diff_files or git.max_diff_lines
+	CreatedAt  time.Time
+}
+
+// RecordDiffSize stores a branch size measurement
+func (db *DB) RecordDiffSize(d *DiffSize) error {
+	query := `
+		INSERT INTO diff_sizes (goblin_id, goblin_name, project, files, insertions, deletions, oversized)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := db.conn.Exec(query, d.GoblinID, d.GoblinName, d.Project, d.Files, d.Insertions, d.Deletions, d.Oversized); err != nil {
+		return fmt.Errorf("failed to record diff size: %w", err)
+	}
+	return nil
+}
+
+// ListDiffSizes returns measurements taken at or after since, oldest first
+func (db *DB) ListDiffSizes(since time.Time) ([]*DiffSize, error) {
+	query := `
+		SELECT id, goblin_id, goblin_name, project, files, insertions, deletions, oversized, created_at
+		FROM diff_sizes
+		WHERE created_at >= ?
+		ORDER BY created_at, id
+	`
+	rows, err := db.conn.Query(query, sqliteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list diff sizes: %w", err)
+	}
+	defer rows.Close()
+
+	var sizes []*DiffSize
+	for rows.Next() {
+		var d DiffSize
+		if err := rows.Scan(&d.ID, &d.GoblinID, &d.GoblinName, &d.Project, &d.Files,
+			&d.Insertions, &d.Deletions, &d.Oversized, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan diff size: %w", err)
+		}
+		sizes = append(sizes, &d)
+	}
+
+	return sizes, nil
+}
+
+// CheckResult is the outcome of one of a project's check commands on a
+// goblin's worktree
+type CheckResult struct {
+	GoblinID  string
+	Command   string
+	Passed    bool
+	Output    string // Combined output, trimmed to its end
+	Duration  time.Duration
+	CreatedAt time.Time
+}
+
+// SaveCheckResults replaces a goblin's check results with those of its
+// latest run
+func (db *DB) SaveCheckResults(goblinID string, results []*CheckResult) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save check results: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM check_results WHERE goblin_id = ?`, goblinID); err != nil {
+		return fmt.Errorf("failed to save check results: %w", err)
+	}
+	for _, r := range results {
+		query := `INSERT INTO check_results (goblin_id, command, passed, output, duration_ms) VALUES (?, ?, ?, ?, ?)`
+		if _, err := tx.Exec(query, goblinID, r.Command, r.Passed, r.Output, r.Duration.Milliseconds()); err != nil {
+			return fmt.Errorf("failed to save check results: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save check results: %w", err)
+	}
+	return nil
+}
+
+// ListCheckResults returns a goblin's check results in the order they ran
+func (db *DB) ListCheckResults(goblinID string) ([]*CheckResult, error) {
+	query := `
+		SELECT goblin_id, command, passed, output, duration_ms, created_at FROM check_results
+		WHERE goblin_id = ?
+		ORDER BY id
+	`
+	rows, err := db.conn.Query(query, goblinID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*CheckResult
+	for rows.Next() {
+		var r CheckResult
+		var ms int64
+		if err := rows.Scan(&r.GoblinID, &r.Command, &r.Passed, &r.Output, &ms, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan check result: %w", err)
+		}
+		r.Duration = time.Duration(ms) * time.Millisecond
+		results = append(results, &r)
+	}
+
+	return results, nil
+}
+
+// IssueLink ties a goblin to the issue it is working on, which gets
+// progress comments
+type IssueLink struct {
+	GoblinID    string
+	Ref         string    // tracker:id, e.g. gh:owner/repo#123
+	CommentedAt time.Time // Zero before the first progress comment
+	CreatedAt   time.Time
+}
+
+// LinkIssue links a goblin to an issue, replacing any earlier link
+func (db *DB) LinkIssue(goblinID, ref string) error {
+	query := `INSERT OR REPLACE INTO issue_links (goblin_id, ref) VALUES (?, ?)`
+	if _, err := db.conn.Exec(query, goblinID, ref); err != nil {
+		return fmt.Errorf("failed to link issue: %w", err)
+	}
+	return nil
+}
+
+// UnlinkIssue removes a goblin's issue link
+func (db *DB) UnlinkIssue(goblinID string) error {
+	if _, err := db.conn.Exec(`DELETE FROM issue_links WHERE goblin_id = ?`, goblinID); err != nil {
+		return fmt.Errorf("failed to unlink issue: %w", err)
+	}
+	return nil
+}
+
+// GetIssueLink returns a goblin's issue link, or nil if it has none
+func (db *DB) GetIssueLink(goblinID string) (*IssueLink, error) {
+	query := `SELECT goblin_id, ref, commented_at, created_at FROM issue_links WHERE goblin_id = ?`
+	var l IssueLink
+	err := db.conn.QueryRow(query, goblinID).Scan(&l.GoblinID, &l.Ref, &l.CommentedAt, &l.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue link: %w", err)
+	}
+	if l.CommentedAt.Unix() <= 0 {
+		l.CommentedAt = time.Time{}
+	}
+	return &l, nil
+}
+
+// ListIssueLinks returns every goblin's issue link
+func (db *DB) ListIssueLinks() ([]*IssueLink, error) {
+	rows, err := db.conn.Query(`SELECT goblin_id, ref, commented_at, created_at FROM issue_links ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issue links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*IssueLink
+	for rows.Next() {
+		var l IssueLink
+		if err := rows.Scan(&l.GoblinID, &l.Ref, &l.CommentedAt, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan issue link: %w", err)
+		}
+		if l.CommentedAt.Unix() <= 0 {
+			l.CommentedAt = time.Time{}
+		}
+		links = append(links, &l)
+	}
+
+	return links, nil
+}
+
+// MarkIssueCommented records when a goblin's issue last got a progress
+// comment
+func (db *DB) MarkIssueCommented(goblinID string, at time.Time) error {
+	query := `UPDATE issue_links SET commented_at = ? WHERE goblin_id = ?`
+	if _, err := db.conn.Exec(query, sqliteTime(at), goblinID); err != nil {
+		return fmt.Errorf("failed to update issue link: %w", err)
+	}
+	return nil
+}
+
+// WorkflowRun is a GitHub Actions workflow dispatched for a goblin. RunID
+// is 0 until the run it started is found.
+type WorkflowRun struct {
+	ID         int64
+	GoblinID   string
+	Trigger    string // push or merge
+	Workflow   string // Workflow file or name, or repository_dispatch event type
+	Event      string // workflow_dispatch or repository_dispatch
+	Repo       string
+	Ref        string
+	RunID      int64
+	Status     string
+	Conclusion string
+	URL        string
+	CreatedAt  time.Time // When it was dispatched
+	UpdatedAt  time.Time
+}
+
+// CreateWorkflowRun records a dispatched workflow
+func (db *DB) CreateWorkflowRun(r *WorkflowRun) error {
+	query := `INSERT INTO workflow_runs (goblin_id, trigger, workflow, event, repo, ref, status) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	res, err := db.conn.Exec(query, r.GoblinID, r.Trigger, r.Workflow, r.Event, r.Repo, r.Ref, r.Status)
+	if err != nil {
+		return fmt.Errorf("failed to record workflow run: %w", err)
+	}
+	r.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// UpdateWorkflowRun saves what is known of a dispatched workflow's run
+func (db *DB) UpdateWorkflowRun(r *WorkflowRun) error {
+	query := `
+		UPDATE workflow_runs SET run_id = ?, status = ?, conclusion = ?, url = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	if _, err := db.conn.Exec(query, r.RunID, r.Status, r.Conclusion, r.URL, r.ID); err != nil {
+		return fmt.Errorf("failed to update workflow run: %w", err)
+	}
+	return nil
+}
+
+// ListWorkflowRuns returns the workflows dispatched for a goblin, oldest
+// first
+func (db *DB) ListWorkflowRuns(goblinID string) ([]*WorkflowRun, error) {
+	query := `
+		SELECT id, goblin_id, trigger, workflow, event, repo, ref, run_id, status, conclusion, url, created_at, updated_at
+		FROM workflow_runs WHERE goblin_id = ?
+		ORDER BY id
+	`
+	rows, err := db.conn.Query(query, goblinID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*WorkflowRun
+	for rows.Next() {
+		var r WorkflowRun
+		if err := rows.Scan(&r.ID, &r.GoblinID, &r.Trigger, &r.Workflow, &r.Event, &r.Repo, &r.Ref, &r.RunID,
+			&r.Status, &r.Conclusion, &r.URL, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow run: %w", err)
+		}
+		runs = append(runs, &r)
+	}
+
+	return runs, nil
+}
+
+// VoiceCommand is a command heard by the voice listener. Params holds the
+// parsed command as JSON.
+type VoiceCommand struct {
+	ID        int64
+	RawText   string
+	Action    string
+	Params    string
+	Executed  bool
+	Decision  string // run, refused, awaiting, confirmed, cancelled or expired
+	CreatedAt time.Time
+}
+
+// RecordVoiceCommand stores a heard command
+func (db *DB) RecordVoiceCommand(c *VoiceCommand) error {
+	query := `INSERT INTO voice_commands (raw_text, parsed_action, parsed_params, executed, decision) VALUES (?, ?, ?, ?, ?)`
+	res, err := db.conn.Exec(query, c.RawText, c.Action, c.Params, c.Executed, c.Decision)
+	if err != nil {
+		return fmt.Errorf("failed to record voice command: %w", err)
+	}
+	c.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// DecideVoiceCommand records what became of a command awaiting
+// confirmation
+func (db *DB) DecideVoiceCommand(id int64, decision string, executed bool) error {
+	query := `UPDATE voice_commands SET decision = ?, executed = ? WHERE id = ?`
+	if _, err := db.conn.Exec(query, decision, executed, id); err != nil {
+		return fmt.Errorf("failed to update voice command: %w", err)
+	}
+	return nil
+}
+
+// ListVoiceCommands returns the latest heard commands, newest first
+func (db *DB) ListVoiceCommands(limit int) ([]*VoiceCommand, error) {
+	query := `
+		SELECT id, raw_text, COALESCE(parsed_action, ''), COALESCE(parsed_params, ''), executed, decision, created_at
+		FROM voice_commands
+		ORDER BY id DESC
+		LIMIT ?
+	`
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voice commands: %w", err)
+	}
+	defer rows.Close()
+
+	var commands []*VoiceCommand
+	for rows.Next() {
+		var c VoiceCommand
+		if err := rows.Scan(&c.ID, &c.RawText, &c.Action, &c.Params, &c.Executed, &c.Decision, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan voice command: %w", err)
+		}
+		commands = append(commands, &c)
+	}
+
+	return commands, nil
+}
+
+// Note is an observation attached to a goblin
+type Note struct {
+	ID        int64
+	GoblinID  string
+	Body      string
+	CreatedAt time.Time
+}
+
+// AddNote attaches a note to a goblin
+func (db *DB) AddNote(goblinID, body string) (*Note, error) {
+	return db.AddNoteAt(goblinID, body, time.Now())
+}
+
+// AddNoteAt attaches a note with the time it was originally written
+func (db *DB) AddNoteAt(goblinID, body string, at time.Time) (*Note, error) {
+	query := `INSERT INTO notes (goblin_id, body, created_at) VALUES (?, ?, ?)`
+	res, err := db.conn.Exec(query, goblinID, body, sqliteTime(at))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add note: %w", err)
+	}
+	id, _ := res.LastInsertId()
+	return &Note{ID: id, GoblinID: goblinID, Body: body, CreatedAt: at}, nil
+}
+
+// ListNotes returns a goblin's notes in the order they were written
+func (db *DB) ListNotes(goblinID string) ([]*Note, error) {
+	query := `SELECT id, goblin_id, body, created_at FROM notes WHERE goblin_id = ? ORDER BY created_at, id`
+	rows, err := db.conn.Query(query, goblinID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*Note
+	for rows.Next() {
+		var n Note
+		if err := rows.Scan(&n.ID, &n.GoblinID, &n.Body, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+		notes = append(notes, &n)
+	}
+
+	return notes, nil
+}
+
+// Tombstone is what remains of a killed goblin
+type Tombstone struct {
+	ID         int64
+	GoblinID   string
+	Name       string
+	Agent      string
+	Project    string
+	Branch     string
+	Outcome    string // How the goblin ended, see coordinator.Kill
+	Files      int    // Final diffstat of its branch
+	Insertions int
+	Deletions  int
+	CreatedAt  time.Time // When the goblin was spawned
+	EndedAt    time.Time
+}
+
+// Duration returns how long the goblin lived
+func (t *Tombstone) Duration() time.Duration {
+	return t.EndedAt.Sub(t.CreatedAt)
+}
+
+// AddTombstone records a killed goblin
+func (db *DB) AddTombstone(t *Tombstone) error {
+	query := `
+		INSERT INTO goblin_history (goblin_id, name, agent, project, branch, outcome,
+			files, insertions, deletions, created_at, ended_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := db.conn.Exec(query, t.GoblinID, t.Name, t.Agent, t.Project, t.Branch, t.Outcome,
+		t.Files, t.Insertions, t.Deletions, sqliteTime(t.CreatedAt), sqliteTime(t.EndedAt)); err != nil {
+		return fmt.Errorf("failed to record tombstone: %w", err)
+	}
+	return nil
+}
+
+// ListTombstones returns goblins killed at or after since, most recent first
+func (db *DB) ListTombstones(since time.Time) ([]*Tombstone, error) {
+	query := `
+		SELECT id, goblin_id, name, agent, project, branch, outcome,
+			files, insertions, deletions, created_at, ended_at
+		FROM goblin_history
+		WHERE ended_at >= ?
+		ORDER BY ended_at DESC, id DESC
+	`
+	rows, err := db.conn.Query(query, sqliteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tombstones: %w", err)
+	}
+	defer rows.Close()
+
+	var tombstones []*Tombstone
+	for rows.Next() {
+		var t Tombstone
+		if err := rows.Scan(&t.ID, &t.GoblinID, &t.Name, &t.Agent, &t.Project, &t.Branch, &t.Outcome,
+			&t.Files, &t.Insertions, &t.Deletions, &t.CreatedAt, &t.EndedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tombstone: %w", err)
+		}
+		tombstones = append(tombstones, &t)
+	}
+
+	return tombstones, nil
+}
+
+// PathLock is a file or directory a goblin has claimed in its project
+type PathLock struct {
+	ID         int64
+	GoblinID   string
+	GoblinName string
+	Project    string
+	Path       string // Relative to the project; directories end in a slash
+	CreatedAt  time.Time
+}
+
+// AddPathLock records a goblin's claim on path; claiming a path twice
+// keeps the first claim
+func (db *DB) AddPathLock(goblinID, project, path string) error {
+	query := `INSERT OR IGNORE INTO path_locks (goblin_id, project, path) VALUES (?, ?, ?)`
+	if _, err := db.conn.Exec(query, goblinID, project, path); err != nil {
+		return fmt.Errorf("failed to add path lock: %w", err)
+	}
+	return nil
+}
+
+// ListPathLocks returns the paths claimed in project, or in every project
+// when it is empty, by project and path
+func (db *DB) ListPathLocks(project string) ([]*PathLock, error) {
+	query := `
+		SELECT l.id, l.goblin_id, g.name, l.project, l.path, l.created_at
+		FROM path_locks l JOIN goblins g ON g.id = l.goblin_id
+		WHERE ? = '' OR l.project = ?
+		ORDER BY l.project, l.path, l.id
+	`
+	rows, err := db.conn.Query(query, project, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list path locks: %w", err)
+	}
+	defer rows.Close()
+
+	var locks []*PathLock
+	for rows.Next() {
+		var l PathLock
+		if err := rows.Scan(&l.ID, &l.GoblinID, &l.GoblinName, &l.Project, &l.Path, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan path lock: %w", err)
+		}
+		locks = append(locks, &l)
+	}
+
+	return locks, nil
+}
+
+// RemovePathLocks drops a goblin's claims on paths, or all of them when
+// none are given, and returns how many were dropped
+func (db *DB) RemovePathLocks(goblinID string, paths ...string) (int, error) {
+	query := `DELETE FROM path_locks WHERE goblin_id = ?`
+	args := []interface{}{goblinID}
+	if len(paths) > 0 {
+		query += ` AND path IN (?` + strings.Repeat(", ?", len(paths)-1) + `)`
+		for _, p := range paths {
+			args = append(args, p)
+		}
+	}
+
+	result, err := db.conn.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove path locks: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// PadEntry is one key of a squad's scratchpad
+type PadEntry struct {
+	Squad      string
+	Key        string
+	Value      string
+	GoblinName string // Who wrote it last; empty when written by hand
+	UpdatedAt  time.Time
+}
+
+// SetPadEntry writes a key of a squad's scratchpad, replacing its value
+func (db *DB) SetPadEntry(e *PadEntry) error {
+	query := `
+		INSERT INTO scratchpad (squad, key, value, goblin_name, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (squad, key) DO UPDATE SET
+			value = excluded.value,
+			goblin_name = excluded.goblin_name,
+			updated_at = excluded.updated_at
+	`
+	if _, err := db.conn.Exec(query, e.Squad, e.Key, e.Value, e.GoblinName); err != nil {
+		return fmt.Errorf("failed to write scratchpad: %w", err)
+	}
+	return nil
+}
+
+// GetPadEntry reads a key of a squad's scratchpad; nil when it isn't set
+func (db *DB) GetPadEntry(squad, key string) (*PadEntry, error) {
+	query := `SELECT squad, key, value, goblin_name, updated_at FROM scratchpad WHERE squad = ? AND key = ?`
+	var e PadEntry
+	err := db.conn.QueryRow(query, squad, key).Scan(&e.Squad, &e.Key, &e.Value, &e.GoblinName, &e.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scratchpad: %w", err)
+	}
+	return &e, nil
+}
+
+// ListPadEntries returns a squad's scratchpad by key
+func (db *DB) ListPadEntries(squad string) ([]*PadEntry, error) {
+	query := `SELECT squad, key, value, goblin_name, updated_at FROM scratchpad WHERE squad = ? ORDER BY key`
+	rows, err := db.conn.Query(query, squad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scratchpad: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*PadEntry
+	for rows.Next() {
+		var e PadEntry
+		if err := rows.Scan(&e.Squad, &e.Key, &e.Value, &e.GoblinName, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scratchpad entry: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+
+	return entries, nil
+}
+
+// DeletePadEntry removes a key of a squad's scratchpad, reporting whether
+// it was set
+func (db *DB) DeletePadEntry(squad, key string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM scratchpad WHERE squad = ? AND key = ?`, squad, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete scratchpad entry: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// Schedule is a recurring goblin run, spawned by gforge daemon each time
+// its cron expression comes round
+type Schedule struct {
+	Name        string
+	Cron        string
+	ProjectPath string
+	Agent       string // Empty for the default agent
+	Task        string
+	Priority    string
+	Squad       string
+	FromConfig  bool      // Defined under schedules in the config file
+	NextRun     time.Time // When it is next due
+	LastRun     time.Time // Zero until it first runs
+	LastGoblin  string    // Name of the goblin the last run spawned
+	CreatedAt   time.Time
+}
+
+const scheduleColumns = `name, cron, project_path, agent, task, priority, squad, from_config,
+	next_run, last_run, last_goblin, created_at`
+
+// SaveSchedule stores a schedule, replacing the one of the same name
+func (db *DB) SaveSchedule(s *Schedule) error {
+	var lastRun interface{}
+	if !s.LastRun.IsZero() {
+		lastRun = sqliteTime(s.LastRun)
+	}
+	query := `
+		INSERT INTO schedules (name, cron, project_path, agent, task, priority, squad, from_config, next_run, last_run, last_goblin)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			cron = excluded.cron,
+			project_path = excluded.project_path,
+			agent = excluded.agent,
+			task = excluded.task,
+			priority = excluded.priority,
+			squad = excluded.squad,
+			from_config = excluded.from_config,
+			next_run = excluded.next_run,
+			last_run = excluded.last_run,
+			last_goblin = excluded.last_goblin
+	`
+	if _, err := db.conn.Exec(query, s.Name, s.Cron, s.ProjectPath, s.Agent, s.Task, s.Priority, s.Squad,
+		s.FromConfig, sqliteTime(s.NextRun), lastRun, s.LastGoblin); err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+	return nil
+}
+
+// GetSchedule returns a schedule by name; nil when there is none
+func (db *DB) GetSchedule(name string) (*Schedule, error) {
+	row := db.conn.QueryRow(`SELECT `+scheduleColumns+` FROM schedules WHERE name = ?`, name)
+	s, err := scanSchedule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	return s, nil
+}
+
+// ListSchedules returns every schedule by name
+func (db *DB) ListSchedules() ([]*Schedule, error) {
+	rows, err := db.conn.Query(`SELECT ` + scheduleColumns + ` FROM schedules ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*Schedule
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, s)
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule removes a schedule, reporting whether there was one
+func (db *DB) DeleteSchedule(name string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM schedules WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+func scanSchedule(row rowScanner) (*Schedule, error) {
+	var s Schedule
+	var lastRun sql.NullTime
+	if err := row.Scan(&s.Name, &s.Cron, &s.ProjectPath, &s.Agent, &s.Task, &s.Priority, &s.Squad, &s.FromConfig,
+		&s.NextRun, &lastRun, &s.LastGoblin, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	s.LastRun = lastRun.Time
+	return &s, nil
+}
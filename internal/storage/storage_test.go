@@ -3,7 +3,9 @@ package storage
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -29,6 +31,27 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewConcurrent(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// As gforge commands started together all create the database
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			db, err := New(dbPath)
+			if err == nil {
+				db.Close()
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Concurrent New failed: %v", err)
+		}
+	}
+}
+
 func TestGoblinCRUD(t *testing.T) {
 	// Create temp database
 	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
@@ -53,6 +76,7 @@ func TestGoblinCRUD(t *testing.T) {
 		WorktreePath: "/tmp/test-worktree",
 		Branch:       "gforge/test",
 		TmuxSession:  "gforge-test-123",
+		Squad:        "auth",
 	}
 
 	err = db.CreateGoblin(goblin)
@@ -71,6 +95,9 @@ func TestGoblinCRUD(t *testing.T) {
 	if retrieved.Name != "test-goblin" {
 		t.Errorf("Expected name 'test-goblin', got '%s'", retrieved.Name)
 	}
+	if retrieved.Squad != "auth" {
+		t.Errorf("Expected squad 'auth', got '%s'", retrieved.Squad)
+	}
 
 	// Test Get by Name
 	retrieved, err = db.GetGoblin("test-goblin")
@@ -101,6 +128,32 @@ func TestGoblinCRUD(t *testing.T) {
 		t.Errorf("Expected status 'paused', got '%s'", retrieved.Status)
 	}
 
+	// Test Pin
+	if retrieved.Pinned {
+		t.Error("Expected goblins unpinned by default")
+	}
+	if err := db.SetGoblinPinned("test-goblin", true); err != nil {
+		t.Fatalf("Failed to pin: %v", err)
+	}
+	if retrieved, _ = db.GetGoblin("test-123"); !retrieved.Pinned {
+		t.Error("Expected the goblin pinned")
+	}
+	if err := db.SetGoblinPinned("missing", true); err == nil {
+		t.Error("Expected an error pinning a missing goblin")
+	}
+
+	// Test CommitSeen
+	if seen, err := db.CommitSeen("test-123"); err != nil || seen != "" {
+		t.Errorf("Expected no commit seen yet, got %q, %v", seen, err)
+	}
+	db.SetCommitSeen("test-123", "abc123")
+	if seen, _ := db.CommitSeen("test-123"); seen != "abc123" {
+		t.Errorf("Expected abc123 seen, got %q", seen)
+	}
+	if _, err := db.CommitSeen("missing"); err == nil {
+		t.Error("Expected an error for a missing goblin")
+	}
+
 	// Test Delete
 	err = db.DeleteGoblin("test-123")
 	if err != nil {
@@ -199,6 +252,14 @@ func TestStats(t *testing.T) {
 	if stats.Completed != 2 {
 		t.Errorf("Expected completed 2, got %d", stats.Completed)
 	}
+
+	counts, err := db.CountByStatus()
+	if err != nil {
+		t.Fatalf("Failed to count by status: %v", err)
+	}
+	if counts["running"] != 2 || counts["paused"] != 1 || counts["completed"] != 2 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
 }
 
 func TestOutputLogs(t *testing.T) {
@@ -296,3 +357,971 @@ func TestDuplicateGoblinName(t *testing.T) {
 		t.Error("Expected error when creating goblin with duplicate name")
 	}
 }
+
+func TestTaskRecords(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	goblin := &Goblin{
+		ID:          "task-1",
+		Name:        "tasker",
+		Agent:       "claude",
+		Status:      "running",
+		ProjectPath: "/tmp",
+		BaseRef:     "abc123",
+	}
+	if err := db.CreateGoblin(goblin); err != nil {
+		t.Fatalf("Failed to create goblin: %v", err)
+	}
+
+	retrieved, err := db.GetGoblin("tasker")
+	if err != nil || retrieved == nil {
+		t.Fatalf("Failed to get goblin: %v", err)
+	}
+	if retrieved.BaseRef != "abc123" {
+		t.Errorf("Expected base ref 'abc123', got '%s'", retrieved.BaseRef)
+	}
+
+	for _, task := range []string{"first", "second", "third"} {
+		if err := db.RecordTask("task-1", task); err != nil {
+			t.Fatalf("Failed to record task: %v", err)
+		}
+	}
+
+	tasks, err := db.ListTasks("task-1")
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("Expected 3 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Task != "first" || tasks[2].Task != "third" {
+		t.Errorf("Tasks out of order: %s, %s", tasks[0].Task, tasks[2].Task)
+	}
+
+	// Ending closes every open task at the commit given
+	if n, err := db.EndTasks("task-1", "c1"); err != nil || n != 3 {
+		t.Fatalf("Expected 3 tasks ended, got %d, %v", n, err)
+	}
+	db.RecordTaskFrom("task-1", "fourth", "c1")
+	tasks, _ = db.ListTasks("task-1")
+	if tasks[2].EndedAt.IsZero() || tasks[2].EndCommit != "c1" {
+		t.Errorf("Expected the third task ended at c1, got %+v", tasks[2])
+	}
+	if last := tasks[3]; !last.EndedAt.IsZero() || last.StartCommit != "c1" || last.EndCommit != "" {
+		t.Errorf("Expected the fourth task open from c1, got %+v", last)
+	}
+	if n, _ := db.EndTasks("task-1", "c2"); n != 1 {
+		t.Errorf("Expected only the open task ended, got %d", n)
+	}
+}
+
+func TestMigrateIdempotent(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dbPath := filepath.Join(tmpDir, "test.db")
+	db, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	db.Close()
+
+	// Reopening must not try to re-add columns
+	db, err = New(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	db.Close()
+}
+
+func TestEvents(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.RecordEvent("ev-1", "coder", "spawned", "claude"); err != nil {
+		t.Fatalf("Failed to record event: %v", err)
+	}
+	if err := db.RecordEvent("ev-1", "coder", "killed", ""); err != nil {
+		t.Fatalf("Failed to record event: %v", err)
+	}
+
+	events, err := db.ListEvents(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != "spawned" || events[0].GoblinName != "coder" || events[0].Detail != "claude" {
+		t.Errorf("Unexpected first event: %+v", events[0])
+	}
+
+	events, err = db.ListEvents(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected no future events, got %d", len(events))
+	}
+
+	db.RecordEvent("ev-1", "coder", "pr", "https://example.com/pull/1")
+	db.RecordEvent("ev-2", "other", "pr", "https://example.com/pull/2")
+	db.RecordEvent("ev-1", "coder", "pr", "https://example.com/pull/3")
+	if e, err := db.LastEvent("ev-1", "pr"); err != nil || e == nil || e.Detail != "https://example.com/pull/3" {
+		t.Errorf("Expected the goblin's latest pr event, got %+v, %v", e, err)
+	}
+	if e, err := db.LastEvent("ev-2", "killed"); err != nil || e != nil {
+		t.Errorf("Expected no event, got %+v, %v", e, err)
+	}
+}
+
+func TestAliasesAndIndexes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, g := range []*Goblin{
+		{ID: "id-old", Name: "refactor-authentication-module", Agent: "claude", Status: "running", ProjectPath: "/tmp"},
+		{ID: "id-new", Name: "fix-flaky-tests", Agent: "codex", Status: "running", ProjectPath: "/tmp"},
+	} {
+		if err := db.CreateGoblin(g); err != nil {
+			t.Fatalf("Failed to create goblin: %v", err)
+		}
+	}
+
+	if err := db.SetAlias("auth", "id-old"); err != nil {
+		t.Fatalf("Failed to set alias: %v", err)
+	}
+
+	g, err := db.GetGoblin("auth")
+	if err != nil || g == nil || g.ID != "id-old" {
+		t.Fatalf("Expected alias to resolve to id-old, got %v (%v)", g, err)
+	}
+
+	// A name wins over an alias with the same text
+	db.SetAlias("fix-flaky-tests", "id-old")
+	g, _ = db.GetGoblin("fix-flaky-tests")
+	if g == nil || g.ID != "id-new" {
+		t.Errorf("Name should take precedence over alias, got %v", g)
+	}
+
+	// Index 1 is the newest goblin, matching list order
+	first, _ := db.GetGoblinByIndex(1)
+	second, _ := db.GetGoblinByIndex(2)
+	if first == nil || first.ID != "id-new" || second == nil || second.ID != "id-old" {
+		t.Errorf("Unexpected index order: %v, %v", first, second)
+	}
+	if g, _ := db.GetGoblinByIndex(3); g != nil {
+		t.Errorf("Expected nil past the end, got %v", g)
+	}
+
+	aliases, err := db.ListAliases()
+	if err != nil || len(aliases) != 2 {
+		t.Fatalf("Expected 2 aliases, got %v (%v)", aliases, err)
+	}
+
+	if err := db.DeleteAlias("auth"); err != nil {
+		t.Fatalf("Failed to delete alias: %v", err)
+	}
+	if err := db.DeleteAlias("auth"); err == nil {
+		t.Error("Deleting a missing alias should fail")
+	}
+
+	// Aliases go away with their goblin
+	db.DeleteGoblin("id-old")
+	aliases, _ = db.ListAliases()
+	if len(aliases) != 0 {
+		t.Errorf("Expected aliases removed with goblin, got %v", aliases)
+	}
+}
+
+func TestRestoreGoblin(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := db.RestoreGoblin(&Goblin{
+		ID: "id-1", Name: "restored", Agent: "claude", Status: "stopped", ProjectPath: "/tmp",
+		CreatedAt: created, UpdatedAt: created,
+	}); err != nil {
+		t.Fatalf("RestoreGoblin failed: %v", err)
+	}
+
+	g, err := db.GetGoblin("restored")
+	if err != nil || g == nil {
+		t.Fatalf("Expected restored goblin, got %v (%v)", g, err)
+	}
+	if !g.CreatedAt.Equal(created) {
+		t.Errorf("Expected created_at %s, got %s", created, g.CreatedAt)
+	}
+
+	sent := created.Add(time.Hour)
+	db.RecordTaskAt("id-1", "second", sent.Add(time.Minute))
+	db.RecordTaskAt("id-1", "first", sent)
+
+	tasks, _ := db.ListTasks("id-1")
+	if len(tasks) != 2 || tasks[0].Task != "first" || !tasks[0].StartedAt.Equal(sent) {
+		t.Errorf("Expected tasks ordered by send time, got %+v", tasks)
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "coder", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	if s, err := db.GetSummary("id-1"); err != nil || s != nil {
+		t.Fatalf("Expected no summary yet, got %v (%v)", s, err)
+	}
+
+	db.SaveSummary(&Summary{GoblinID: "id-1", GoblinName: "coder", Summary: "first", OutputHash: "a"})
+	if err := db.SaveSummary(&Summary{GoblinID: "id-1", GoblinName: "coder", Summary: "second", OutputHash: "b"}); err != nil {
+		t.Fatalf("SaveSummary failed: %v", err)
+	}
+
+	s, err := db.GetSummary("id-1")
+	if err != nil || s == nil || s.Summary != "second" || s.OutputHash != "b" {
+		t.Fatalf("Expected the latest summary, got %+v (%v)", s, err)
+	}
+
+	recent, _ := db.ListSummaries(time.Now().Add(-time.Hour))
+	if len(recent) != 1 || recent[0].GoblinName != "coder" {
+		t.Errorf("Expected one recent summary, got %+v", recent)
+	}
+	if later, _ := db.ListSummaries(time.Now().Add(time.Hour)); len(later) != 0 {
+		t.Errorf("Expected no summaries after now, got %d", len(later))
+	}
+}
+
+func TestAcquireRequest(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		wait, err := db.AcquireRequest("anthropic", 2, time.Minute, now.Add(time.Duration(i)*10*time.Second))
+		if err != nil || wait != 0 {
+			t.Fatalf("Expected request %d to be allowed, got wait %s (%v)", i, wait, err)
+		}
+	}
+
+	wait, _ := db.AcquireRequest("anthropic", 2, time.Minute, now.Add(20*time.Second))
+	if wait != 40*time.Second {
+		t.Errorf("Expected to wait 40s for the oldest request to expire, got %s", wait)
+	}
+
+	if wait, _ := db.AcquireRequest("openai", 2, time.Minute, now); wait != 0 {
+		t.Error("Expected limits to be kept per key")
+	}
+
+	if wait, _ := db.AcquireRequest("anthropic", 2, time.Minute, now.Add(61*time.Second)); wait != 0 {
+		t.Errorf("Expected a slot once the window moved on, got wait %s", wait)
+	}
+
+	if n, _ := db.CountRequests("anthropic", now); n != 2 {
+		t.Errorf("Expected 2 requests in the window, got %d", n)
+	}
+
+	if err := db.ReleaseRequest("anthropic", now.Add(61*time.Second)); err != nil {
+		t.Fatalf("ReleaseRequest failed: %v", err)
+	}
+	if n, _ := db.CountRequests("anthropic", now); n != 1 {
+		t.Errorf("Expected the released request gone, got %d", n)
+	}
+}
+
+func TestSpawnQueue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	low, _ := db.EnqueueSpawn(&QueuedSpawn{Name: "low", Rank: 0, PID: 1})
+	db.EnqueueSpawn(&QueuedSpawn{Name: "normal", Rank: 1, PID: 1})
+	db.EnqueueSpawn(&QueuedSpawn{Name: "high", Local: true, Rank: 2, PID: 1})
+	db.EnqueueSpawn(&QueuedSpawn{Name: "normal-2", Rank: 1, PID: 1})
+
+	queue, err := db.ListSpawnQueue()
+	if err != nil {
+		t.Fatalf("ListSpawnQueue failed: %v", err)
+	}
+	var names []string
+	for _, q := range queue {
+		names = append(names, q.Name)
+	}
+	if strings.Join(names, ",") != "high,normal,normal-2,low" || !queue[0].Local {
+		t.Errorf("Unexpected queue order %v", names)
+	}
+
+	db.DequeueSpawn(low)
+	if queue, _ := db.ListSpawnQueue(); len(queue) != 3 {
+		t.Errorf("Expected 3 queued spawns after dequeue, got %d", len(queue))
+	}
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "urgent", Agent: "claude", Status: "running", ProjectPath: "/tmp", Priority: "high"})
+	db.CreateGoblin(&Goblin{ID: "id-2", Name: "default", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+	if g, _ := db.GetGoblin("urgent"); g.Priority != "high" {
+		t.Errorf("Expected priority high, got %q", g.Priority)
+	}
+	if g, _ := db.GetGoblin("default"); g.Priority != "normal" {
+		t.Errorf("Expected priority to default to normal, got %q", g.Priority)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.RecordUsage(&Usage{GoblinID: "id-1", GoblinName: "coder", Agent: "claude", Project: "/src/app", InputTokens: 100, Cost: 0.5})
+	db.RecordUsage(&Usage{GoblinID: "id-1", GoblinName: "coder", Agent: "claude", Project: "/src/app", OutputTokens: 40, Cost: 0.25})
+	db.RecordUsage(&Usage{GoblinID: "id-2", GoblinName: "local", Agent: "ollama", OutputTokens: 7})
+
+	usage, err := db.ListUsage(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListUsage failed: %v", err)
+	}
+	if len(usage) != 3 || usage[0].InputTokens != 100 || usage[1].Cost != 0.25 || usage[0].Project != "/src/app" {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+
+	if n, _ := db.OutputTokens("id-1"); n != 40 {
+		t.Errorf("Expected 40 output tokens, got %d", n)
+	}
+	if n, _ := db.OutputTokens("missing"); n != 0 {
+		t.Errorf("Expected 0 output tokens for an unknown goblin, got %d", n)
+	}
+	if usage, _ := db.ListUsage(time.Now().Add(time.Hour)); len(usage) != 0 {
+		t.Errorf("Expected no usage in the future, got %d", len(usage))
+	}
+}
+
+func TestStatsSnapshots(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if last, err := db.LastStatsSnapshot(); err != nil || !last.IsZero() {
+		t.Fatalf("Expected no snapshot yet, got %v, %v", last, err)
+	}
+
+	db.SaveStatsSnapshot(&Stats{Total: 3, Running: 2, Completed: 1})
+	db.SaveStatsSnapshot(&Stats{Total: 4, Running: 1, Paused: 1, Throttled: 1})
+
+	if last, _ := db.LastStatsSnapshot(); time.Since(last) > time.Minute {
+		t.Errorf("Expected a recent snapshot, got %v", last)
+	}
+
+	snapshots, err := db.ListStatsSnapshots(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListStatsSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Running != 2 || snapshots[1].Throttled != 1 || snapshots[1].Total != 4 {
+		t.Errorf("Unexpected snapshots %+v", snapshots)
+	}
+}
+
+func TestSecretFindings(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "leaky", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	err = db.SaveSecretFindings("id-1", []*SecretFinding{
+		{Commit: "abc123", File: "config.go", Line: 12, Rule: "github-token", Match: "ghp_********"},
+		{File: ".env", Line: 3, Rule: "aws-access-key-id", Match: "AKIA********"},
+	})
+	if err != nil {
+		t.Fatalf("SaveSecretFindings failed: %v", err)
+	}
+
+	findings, err := db.ListSecretFindings("id-1")
+	if err != nil {
+		t.Fatalf("ListSecretFindings failed: %v", err)
+	}
+	if len(findings) != 2 || findings[0].File != ".env" || findings[1].Line != 12 || findings[1].Commit != "abc123" {
+		t.Errorf("Unexpected findings %+v", findings)
+	}
+
+	// A clean rescan clears them
+	db.SaveSecretFindings("id-1", nil)
+	if findings, _ := db.ListSecretFindings("id-1"); len(findings) != 0 {
+		t.Errorf("Expected findings cleared, got %d", len(findings))
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, id := range []string{"id-1", "id-2", "id-3"} {
+		db.CreateGoblin(&Goblin{ID: id, Name: id, Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+	}
+
+	err = db.SaveOverlaps([]*Overlap{
+		{GoblinID: "id-1", OtherID: "id-2", Files: []string{"b.go", "a.go"}},
+		{GoblinID: "id-2", OtherID: "id-3", Files: []string{"c.go"}},
+	})
+	if err != nil {
+		t.Fatalf("SaveOverlaps failed: %v", err)
+	}
+
+	overlaps, err := db.ListOverlaps()
+	if err != nil {
+		t.Fatalf("ListOverlaps failed: %v", err)
+	}
+	if len(overlaps) != 2 || strings.Join(overlaps[0].Files, ",") != "a.go,b.go" || overlaps[1].OtherID != "id-3" {
+		t.Errorf("Unexpected overlaps %+v", overlaps)
+	}
+
+	// The next check replaces them
+	db.SaveOverlaps([]*Overlap{{GoblinID: "id-2", OtherID: "id-3", Files: []string{"d.go"}}})
+	if overlaps, _ := db.ListOverlaps(); len(overlaps) != 1 || overlaps[0].Files[0] != "d.go" {
+		t.Errorf("Expected the overlaps replaced, got %+v", overlaps)
+	}
+}
+
+func TestDiffSizes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.RecordDiffSize(&DiffSize{GoblinID: "id-1", GoblinName: "small", Project: "app", Files: 2, Insertions: 10, Deletions: 3})
+	db.RecordDiffSize(&DiffSize{GoblinID: "id-2", GoblinName: "huge", Project: "app", Files: 80, Insertions: 4000, Oversized: true})
+
+	sizes, err := db.ListDiffSizes(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListDiffSizes failed: %v", err)
+	}
+	if len(sizes) != 2 || sizes[0].GoblinName != "small" || !sizes[1].Oversized || sizes[1].Insertions != 4000 {
+		t.Errorf("Unexpected sizes %+v", sizes)
+	}
+
+	if sizes, _ := db.ListDiffSizes(time.Now().Add(time.Hour)); len(sizes) != 0 {
+		t.Errorf("Expected nothing in a future window, got %d", len(sizes))
+	}
+}
+
+func TestCheckResults(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "linted", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	err = db.SaveCheckResults("id-1", []*CheckResult{
+		{Command: "go vet ./...", Passed: true, Duration: 1500 * time.Millisecond},
+		{Command: "golangci-lint run", Passed: false, Output: "main.go:3: unused variable"},
+	})
+	if err != nil {
+		t.Fatalf("SaveCheckResults failed: %v", err)
+	}
+
+	results, err := db.ListCheckResults("id-1")
+	if err != nil {
+		t.Fatalf("ListCheckResults failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Command != "go vet ./..." || results[0].Duration != 1500*time.Millisecond {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+	if results[1].Passed || results[1].Output != "main.go:3: unused variable" {
+		t.Errorf("Expected the failing lint stored with its output, got %+v", results[1])
+	}
+}
+
+func TestIssueLinks(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "tracked", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	if link, err := db.GetIssueLink("id-1"); err != nil || link != nil {
+		t.Fatalf("Expected no link yet, got %+v, %v", link, err)
+	}
+
+	if err := db.LinkIssue("id-1", "gh:acme/app#12"); err != nil {
+		t.Fatalf("LinkIssue failed: %v", err)
+	}
+	link, err := db.GetIssueLink("id-1")
+	if err != nil || link == nil || link.Ref != "gh:acme/app#12" || !link.CommentedAt.IsZero() {
+		t.Fatalf("Expected an uncommented link, got %+v, %v", link, err)
+	}
+
+	at := time.Now().Add(-time.Minute).Truncate(time.Second)
+	db.MarkIssueCommented("id-1", at)
+	links, err := db.ListIssueLinks()
+	if err != nil || len(links) != 1 || !links[0].CommentedAt.Equal(at) {
+		t.Errorf("Expected the comment time stored, got %+v, %v", links, err)
+	}
+
+	db.UnlinkIssue("id-1")
+	if link, _ := db.GetIssueLink("id-1"); link != nil {
+		t.Errorf("Expected the link removed, got %+v", link)
+	}
+}
+
+func TestWorkflowRuns(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "pusher", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	run := &WorkflowRun{GoblinID: "id-1", Trigger: "push", Workflow: "e2e.yml", Event: "workflow_dispatch", Repo: "acme/app", Ref: "gforge/pusher"}
+	if err := db.CreateWorkflowRun(run); err != nil {
+		t.Fatalf("CreateWorkflowRun failed: %v", err)
+	}
+	if run.ID == 0 {
+		t.Fatal("Expected the run's ID set")
+	}
+
+	run.RunID, run.Status, run.Conclusion, run.URL = 99, "completed", "success", "https://github.com/acme/app/actions/runs/99"
+	if err := db.UpdateWorkflowRun(run); err != nil {
+		t.Fatalf("UpdateWorkflowRun failed: %v", err)
+	}
+
+	runs, err := db.ListWorkflowRuns("id-1")
+	if err != nil {
+		t.Fatalf("ListWorkflowRuns failed: %v", err)
+	}
+	if len(runs) != 1 || runs[0].RunID != 99 || runs[0].Conclusion != "success" || runs[0].Ref != "gforge/pusher" {
+		t.Errorf("Unexpected runs: %+v", runs)
+	}
+}
+
+func TestVoiceCommands(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.RecordVoiceCommand(&VoiceCommand{RawText: "list goblins", Action: "list", Executed: true, Decision: "run"})
+	heard := &VoiceCommand{RawText: "kill coder", Action: "kill", Params: `{"name":"coder"}`, Decision: "awaiting"}
+	if err := db.RecordVoiceCommand(heard); err != nil {
+		t.Fatalf("RecordVoiceCommand failed: %v", err)
+	}
+	if heard.ID == 0 {
+		t.Error("Expected the ID set")
+	}
+
+	commands, err := db.ListVoiceCommands(10)
+	if err != nil {
+		t.Fatalf("ListVoiceCommands failed: %v", err)
+	}
+	if len(commands) != 2 || commands[0].Action != "kill" || commands[0].Executed || !commands[1].Executed {
+		t.Errorf("Expected both commands newest first, got %+v", commands)
+	}
+
+	if err := db.DecideVoiceCommand(heard.ID, "confirmed", true); err != nil {
+		t.Fatalf("DecideVoiceCommand failed: %v", err)
+	}
+	commands, _ = db.ListVoiceCommands(1)
+	if len(commands) != 1 || commands[0].Decision != "confirmed" || !commands[0].Executed {
+		t.Errorf("Expected the kill confirmed and executed, got %+v", commands)
+	}
+}
+
+func TestNotes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "coder", Agent: "claude", Status: "running", ProjectPath: "/tmp"})
+
+	if _, err := db.AddNote("id-1", "keeps retrying the flaky test"); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	earlier := time.Now().Add(-time.Hour)
+	if _, err := db.AddNoteAt("id-1", "started on the wrong branch", earlier); err != nil {
+		t.Fatalf("AddNoteAt failed: %v", err)
+	}
+
+	notes, err := db.ListNotes("id-1")
+	if err != nil {
+		t.Fatalf("ListNotes failed: %v", err)
+	}
+	if len(notes) != 2 || notes[0].Body != "started on the wrong branch" || notes[1].Body != "keeps retrying the flaky test" {
+		t.Errorf("Expected both notes oldest first, got %+v", notes)
+	}
+
+	// Notes go with their goblin
+	db.DeleteGoblin("id-1")
+	if notes, _ := db.ListNotes("id-1"); len(notes) != 0 {
+		t.Errorf("Expected notes deleted with the goblin, got %d", len(notes))
+	}
+}
+
+func TestTombstones(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "gforge-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := New(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	db.AddTombstone(&Tombstone{GoblinID: "id-1", Name: "old", Agent: "claude", Outcome: "stopped",
+		CreatedAt: now.Add(-50 * time.Hour), EndedAt: now.Add(-48 * time.Hour)})
+	db.AddTombstone(&Tombstone{GoblinID: "id-2", Name: "recent", Agent: "codex", Outcome: "failed", Files: 3, Insertions: 40,
+		CreatedAt: now.Add(-90 * time.Minute), EndedAt: now.Add(-time.Hour)})
+
+	tombstones, err := db.ListTombstones(now.Add(-72 * time.Hour))
+	if err != nil {
+		t.Fatalf("ListTombstones failed: %v", err)
+	}
+	if len(tombstones) != 2 || tombstones[0].Name != "recent" || tombstones[1].Name != "old" {
+		t.Fatalf("Expected most recent first, got %+v", tombstones)
+	}
+	if tombstones[0].Files != 3 || tombstones[0].Insertions != 40 || tombstones[0].Outcome != "failed" {
+		t.Errorf("Unexpected tombstone %+v", tombstones[0])
+	}
+	if d := tombstones[0].Duration().Round(time.Minute); d != 30*time.Minute {
+		t.Errorf("Expected a 30m run, got %s", d)
+	}
+
+	if tombstones, _ := db.ListTombstones(now.Add(-24 * time.Hour)); len(tombstones) != 1 {
+		t.Errorf("Expected 1 tombstone in the last day, got %d", len(tombstones))
+	}
+}
+
+func TestPathLocks(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "coder", Agent: "claude", Status: "running", ProjectPath: "/repo"})
+	db.CreateGoblin(&Goblin{ID: "id-2", Name: "tester", Agent: "claude", Status: "running", ProjectPath: "/other"})
+
+	for _, l := range []struct{ id, project, path string }{
+		{"id-1", "/repo", "src/auth/"},
+		{"id-1", "/repo", "go.mod"},
+		{"id-1", "/repo", "go.mod"}, // Claimed twice, kept once
+		{"id-2", "/other", "api.go"},
+	} {
+		if err := db.AddPathLock(l.id, l.project, l.path); err != nil {
+			t.Fatalf("AddPathLock failed: %v", err)
+		}
+	}
+
+	locks, err := db.ListPathLocks("/repo")
+	if err != nil || len(locks) != 2 {
+		t.Fatalf("Expected 2 locks in /repo, got %v, %v", locks, err)
+	}
+	if locks[0].Path != "go.mod" || locks[0].GoblinName != "coder" {
+		t.Errorf("Unexpected first lock %+v", locks[0])
+	}
+	if all, _ := db.ListPathLocks(""); len(all) != 3 {
+		t.Errorf("Expected 3 locks in all projects, got %d", len(all))
+	}
+
+	if n, err := db.RemovePathLocks("id-1", "go.mod", "missing"); err != nil || n != 1 {
+		t.Errorf("Expected 1 lock removed, got %d, %v", n, err)
+	}
+	if n, _ := db.RemovePathLocks("id-1"); n != 1 {
+		t.Errorf("Expected the rest removed, got %d", n)
+	}
+
+	// Locks go with their goblin
+	db.DeleteGoblin("id-2")
+	if all, _ := db.ListPathLocks(""); len(all) != 0 {
+		t.Errorf("Expected no locks left, got %v", all)
+	}
+}
+
+func TestScratchpad(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, e := range []*PadEntry{
+		{Squad: "auth", Key: "contract", Value: "POST /login", GoblinName: "api"},
+		{Squad: "auth", Key: "split", Value: "api: handlers, ui: forms", GoblinName: "lead"},
+		{Squad: "auth", Key: "contract", Value: "POST /v1/login", GoblinName: "ui"},
+		{Squad: "/src/other", Key: "contract", Value: "gRPC"},
+	} {
+		if err := db.SetPadEntry(e); err != nil {
+			t.Fatalf("SetPadEntry failed: %v", err)
+		}
+	}
+
+	e, err := db.GetPadEntry("auth", "contract")
+	if err != nil || e == nil {
+		t.Fatalf("Expected the contract, got %v, %v", e, err)
+	}
+	if e.Value != "POST /v1/login" || e.GoblinName != "ui" {
+		t.Errorf("Expected the last write to win, got %+v", e)
+	}
+	if e, _ := db.GetPadEntry("auth", "missing"); e != nil {
+		t.Errorf("Expected nil for an unset key, got %+v", e)
+	}
+
+	entries, err := db.ListPadEntries("auth")
+	if err != nil || len(entries) != 2 || entries[0].Key != "contract" || entries[1].Key != "split" {
+		t.Errorf("Expected the squad's 2 keys in order, got %v, %v", entries, err)
+	}
+
+	if ok, err := db.DeletePadEntry("auth", "split"); err != nil || !ok {
+		t.Errorf("Expected split deleted, got %v, %v", ok, err)
+	}
+	if ok, _ := db.DeletePadEntry("auth", "split"); ok {
+		t.Error("Expected deleting an unset key to report false")
+	}
+	if entries, _ := db.ListPadEntries("/src/other"); len(entries) != 1 {
+		t.Errorf("Expected other squads untouched, got %v", entries)
+	}
+}
+
+func TestTaskQueue(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	db.CreateGoblin(&Goblin{ID: "id-1", Name: "coder", Agent: "claude", Status: "running"})
+	db.CreateGoblin(&Goblin{ID: "id-2", Name: "tester", Agent: "claude", Status: "running"})
+
+	first, err := db.EnqueueTask("id-1", "Write the handler")
+	if err != nil {
+		t.Fatalf("EnqueueTask failed: %v", err)
+	}
+	db.EnqueueTask("id-2", "Run the tests")
+	db.EnqueueTask("id-1", "Add docs")
+
+	queue, err := db.ListTaskQueue("id-1")
+	if err != nil || len(queue) != 2 || queue[0].ID != first || queue[1].Task != "Add docs" {
+		t.Fatalf("Expected coder's 2 tasks in order, got %v, %v", queue, err)
+	}
+	if all, _ := db.ListTaskQueue(""); len(all) != 3 {
+		t.Errorf("Expected 3 queued tasks in all, got %d", len(all))
+	}
+
+	if ok, err := db.DequeueTask(first); err != nil || !ok {
+		t.Errorf("Expected the first task dequeued, got %v, %v", ok, err)
+	}
+	if ok, _ := db.DequeueTask(first); ok {
+		t.Error("Expected a task to be dequeued only once")
+	}
+
+	// A task put back keeps its place
+	if err := db.RequeueTask(queue[0]); err != nil {
+		t.Fatalf("RequeueTask failed: %v", err)
+	}
+	if again, _ := db.ListTaskQueue("id-1"); len(again) != 2 || again[0].ID != first {
+		t.Fatalf("Expected the task back first, got %v", again)
+	}
+	db.DequeueTask(first)
+
+	if n, err := db.ClearTaskQueue("id-1"); err != nil || n != 1 {
+		t.Errorf("Expected 1 task cleared, got %d, %v", n, err)
+	}
+
+	// Queued tasks go with their goblin
+	db.DeleteGoblin("id-2")
+	if all, _ := db.ListTaskQueue(""); len(all) != 0 {
+		t.Errorf("Expected no queued tasks left, got %v", all)
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	next := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	deps := &Schedule{Name: "deps", Cron: "0 9 * * mon", ProjectPath: "/src/app", Task: "Update dependencies", NextRun: next}
+	if err := db.SaveSchedule(deps); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+	db.SaveSchedule(&Schedule{Name: "audit", Cron: "@daily", ProjectPath: "/src/app", FromConfig: true, NextRun: next})
+
+	s, err := db.GetSchedule("deps")
+	if err != nil || s == nil {
+		t.Fatalf("Expected deps, got %v, %v", s, err)
+	}
+	if s.Task != "Update dependencies" || !s.NextRun.Equal(next) || !s.LastRun.IsZero() {
+		t.Errorf("Expected deps as saved and never run, got %+v", s)
+	}
+	if s, _ := db.GetSchedule("missing"); s != nil {
+		t.Errorf("Expected nil for an unknown schedule, got %+v", s)
+	}
+
+	// Saving again updates it in place
+	ran := next.Add(time.Minute)
+	deps.LastRun, deps.LastGoblin, deps.NextRun = ran, "deps-1019-0900", next.AddDate(0, 0, 7)
+	if err := db.SaveSchedule(deps); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+	schedules, err := db.ListSchedules()
+	if err != nil || len(schedules) != 2 || schedules[0].Name != "audit" || !schedules[0].FromConfig {
+		t.Fatalf("Expected audit then deps, got %v, %v", schedules, err)
+	}
+	if s := schedules[1]; !s.LastRun.Equal(ran) || s.LastGoblin != "deps-1019-0900" || !s.NextRun.Equal(next.AddDate(0, 0, 7)) {
+		t.Errorf("Expected the run recorded, got %+v", s)
+	}
+
+	if ok, err := db.DeleteSchedule("deps"); err != nil || !ok {
+		t.Errorf("Expected deps deleted, got %v, %v", ok, err)
+	}
+	if ok, _ := db.DeleteSchedule("deps"); ok {
+		t.Error("Expected deleting an unknown schedule to report false")
+	}
+}
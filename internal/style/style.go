@@ -0,0 +1,163 @@
+// Package style holds the colors gforge prints with. Output picks colors by
+// role (accent, success, warning...) from the current theme, so a theme or
+// turning color off changes tables, diffs, logs and the dashboard at once.
+package style
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme names
+const (
+	Default      = "default"
+	Light        = "light"
+	HighContrast = "high-contrast"
+)
+
+// Theme assigns a color to each role in gforge's output
+type Theme struct {
+	Accent  lipgloss.TerminalColor // Titles, keys and agent names
+	Text    lipgloss.TerminalColor // Emphasized text, such as the selected goblin
+	Normal  lipgloss.TerminalColor // Ordinary text
+	Subtle  lipgloss.TerminalColor // Secondary text: ages, hints, help
+	Border  lipgloss.TerminalColor
+	Success lipgloss.TerminalColor // Running goblins, added lines
+	Warning lipgloss.TerminalColor // Paused goblins, pins, warnings
+	Danger  lipgloss.TerminalColor // Failures, removed lines
+	Info    lipgloss.TerminalColor // Diff hunks and other markers
+}
+
+var themes = map[string]Theme{
+	// For dark terminals
+	Default: {
+		Accent:  lipgloss.Color("#7D56F4"),
+		Text:    lipgloss.Color("#FAFAFA"),
+		Normal:  lipgloss.Color("#AAAAAA"),
+		Subtle:  lipgloss.Color("#666666"),
+		Border:  lipgloss.Color("#333333"),
+		Success: lipgloss.Color("#04B575"),
+		Warning: lipgloss.Color("#FFCC00"),
+		Danger:  lipgloss.Color("#FF5F5F"),
+		Info:    lipgloss.Color("#00AFAF"),
+	},
+	// For light terminals
+	Light: {
+		Accent:  lipgloss.Color("#5A3FC0"),
+		Text:    lipgloss.Color("#1A1A1A"),
+		Normal:  lipgloss.Color("#3A3A3A"),
+		Subtle:  lipgloss.Color("#6E6E6E"),
+		Border:  lipgloss.Color("#BBBBBB"),
+		Success: lipgloss.Color("#0A7F3F"),
+		Warning: lipgloss.Color("#9A6700"),
+		Danger:  lipgloss.Color("#C62828"),
+		Info:    lipgloss.Color("#00796B"),
+	},
+	// The bright ANSI colors, which the terminal's own palette controls,
+	// with nothing dimmed
+	HighContrast: {
+		Accent:  lipgloss.Color("13"),
+		Text:    lipgloss.Color("15"),
+		Normal:  lipgloss.Color("15"),
+		Subtle:  lipgloss.Color("7"),
+		Border:  lipgloss.Color("15"),
+		Success: lipgloss.Color("10"),
+		Warning: lipgloss.Color("11"),
+		Danger:  lipgloss.Color("9"),
+		Info:    lipgloss.Color("14"),
+	},
+}
+
+// plain is the theme when color is off
+var plain = Theme{
+	Accent:  lipgloss.NoColor{},
+	Text:    lipgloss.NoColor{},
+	Normal:  lipgloss.NoColor{},
+	Subtle:  lipgloss.NoColor{},
+	Border:  lipgloss.NoColor{},
+	Success: lipgloss.NoColor{},
+	Warning: lipgloss.NoColor{},
+	Danger:  lipgloss.NoColor{},
+	Info:    lipgloss.NoColor{},
+}
+
+var (
+	current = themes[Default]
+	enabled = true
+)
+
+// Names returns the available themes
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Configure selects the theme (empty for the default) and whether to use
+// color at all. Color is off when noColor is set or the NO_COLOR
+// environment variable is (see no-color.org); output that isn't a terminal
+// is never colored either way.
+func Configure(theme string, noColor bool) error {
+	if theme == "" {
+		theme = Default
+	}
+	t, ok := themes[theme]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (use %s)", theme, strings.Join(Names(), ", "))
+	}
+
+	enabled = !noColor && os.Getenv("NO_COLOR") == ""
+	current = t
+	if !enabled {
+		current = plain
+	}
+	return nil
+}
+
+// Current returns the theme in use
+func Current() Theme {
+	return current
+}
+
+// Enabled reports whether output may be colored
+func Enabled() bool {
+	return enabled
+}
+
+// Paint renders text in color c, leaving tabs alone
+func Paint(c lipgloss.TerminalColor, text string) string {
+	return lipgloss.NewStyle().Foreground(c).TabWidth(lipgloss.NoTabConversion).Render(text)
+}
+
+// Bold renders text in bold when color is on
+func Bold(text string) string {
+	if !enabled {
+		return text
+	}
+	return lipgloss.NewStyle().Bold(true).TabWidth(lipgloss.NoTabConversion).Render(text)
+}
+
+// StatusColor returns the color for a goblin status
+func (t Theme) StatusColor(status string) lipgloss.TerminalColor {
+	switch status {
+	case "running", "completed":
+		return t.Success
+	case "paused", "preempted", "throttled", "created":
+		return t.Warning
+	case "failed", "dead":
+		return t.Danger
+	}
+	return t.Subtle
+}
+
+// Status paints a goblin status in its color
+func Status(status string) string {
+	return Paint(current.StatusColor(status), status)
+}
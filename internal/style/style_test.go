@@ -0,0 +1,68 @@
+package style
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	defer Configure("", false)
+
+	if err := Configure("neon", false); err == nil {
+		t.Error("Expected an unknown theme rejected")
+	}
+
+	if err := Configure(HighContrast, false); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	if !Enabled() || Current().Danger != themes[HighContrast].Danger {
+		t.Errorf("Expected the high-contrast theme, got %+v", Current())
+	}
+
+	if err := Configure("", false); err != nil || Current().Accent != themes[Default].Accent {
+		t.Errorf("Expected an empty name to select the default theme, got %v", err)
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	defer Configure("", false)
+
+	t.Setenv("NO_COLOR", "")
+	Configure(Light, true)
+	if Enabled() || Current().Success != (lipgloss.NoColor{}) {
+		t.Error("Expected --no-color to turn color off")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	Configure(Light, false)
+	if Enabled() {
+		t.Error("Expected NO_COLOR to turn color off")
+	}
+	if got := Bold("diff"); got != "diff" {
+		t.Errorf("Expected plain text without color, got %q", got)
+	}
+}
+
+func TestStatusColor(t *testing.T) {
+	theme := themes[Default]
+	cases := map[string]lipgloss.TerminalColor{
+		"running": theme.Success,
+		"paused":  theme.Warning,
+		"failed":  theme.Danger,
+		"stopped": theme.Subtle,
+	}
+	for status, want := range cases {
+		if got := theme.StatusColor(status); got != want {
+			t.Errorf("StatusColor(%s) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if len(names) != 3 || names[0] != Default || names[1] != HighContrast || names[2] != Light {
+		t.Errorf("Unexpected theme names: %v", names)
+	}
+}
@@ -0,0 +1,197 @@
+// Package summarize condenses long agent output with a small model.
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+)
+
+// Backends
+const (
+	BackendOllama = "ollama"
+	BackendOpenAI = "openai"
+)
+
+const (
+	defaultOllamaURL = "http://127.0.0.1:11434"
+	defaultOpenAIURL = "https://api.openai.com/v1"
+	defaultMaxTokens = 6000
+)
+
+// instructions tell the model what a summary should cover
+const instructions = `You summarize the terminal output of an AI coding agent for the developer supervising it.
+Write at most 8 short bullet points covering: what the agent was asked to do, what it changed,
+where it is now, and anything blocking it (errors, failing tests, questions awaiting an answer).
+Be concrete: name files, commands and errors. Do not add preamble.`
+
+// Summarizer sends output to a model and returns its summary
+type Summarizer struct {
+	backend   string
+	model     string
+	url       string
+	apiKey    string
+	maxTokens int
+	client    *http.Client
+}
+
+// New creates a summarizer from configuration
+func New(cfg config.SummarizerConfig) (*Summarizer, error) {
+	s := &Summarizer{
+		backend:   cfg.Backend,
+		model:     cfg.Model,
+		url:       strings.TrimRight(cfg.URL, "/"),
+		apiKey:    cfg.APIKey,
+		maxTokens: cfg.MaxTokens,
+		client:    &http.Client{},
+	}
+	if s.maxTokens <= 0 {
+		s.maxTokens = defaultMaxTokens
+	}
+	if s.model == "" {
+		return nil, fmt.Errorf("summarizer.model is not set")
+	}
+
+	switch s.backend {
+	case BackendOllama, "":
+		s.backend = BackendOllama
+		if s.url == "" {
+			s.url = defaultOllamaURL
+		}
+	case BackendOpenAI:
+		if s.url == "" {
+			s.url = defaultOpenAIURL
+		}
+		if s.apiKey == "" {
+			s.apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if s.apiKey == "" {
+			return nil, fmt.Errorf("summarizer.api_key or OPENAI_API_KEY is required for the openai backend")
+		}
+	default:
+		return nil, fmt.Errorf("unknown summarizer backend: %s (use ollama or openai)", s.backend)
+	}
+
+	return s, nil
+}
+
+// Prompt builds the request for a rolling summary: the previous summary,
+// if any, is updated with the latest output. Output beyond maxTokens is
+// cut from the front, as the newest lines matter most.
+func Prompt(previous, output string, maxTokens int) string {
+	var b strings.Builder
+	b.WriteString(instructions)
+	if previous != "" {
+		b.WriteString("\n\nPrevious summary (update it; drop what is no longer true):\n")
+		b.WriteString(previous)
+	}
+	b.WriteString("\n\nLatest output:\n")
+
+	budget := maxTokens - tokens.Count(b.String())
+	b.WriteString(tokens.TruncateTail(strings.TrimSpace(output), budget))
+	return b.String()
+}
+
+// Summarize returns an updated summary of output
+func (s *Summarizer) Summarize(ctx context.Context, previous, output string) (string, error) {
+	prompt := Prompt(previous, output, s.maxTokens)
+
+	var (
+		summary string
+		err     error
+	)
+	if s.backend == BackendOpenAI {
+		summary, err = s.openAI(ctx, prompt)
+	} else {
+		summary, err = s.ollama(ctx, prompt)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize: %w", err)
+	}
+
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", fmt.Errorf("failed to summarize: %s returned an empty response", s.model)
+	}
+	return summary, nil
+}
+
+// ollama calls the generate endpoint without streaming
+func (s *Summarizer) ollama(ctx context.Context, prompt string) (string, error) {
+	body := map[string]interface{}{
+		"model":  s.model,
+		"prompt": prompt,
+		"stream": false,
+	}
+	var result struct {
+		Response string `json:"response"`
+	}
+	if err := s.post(ctx, s.url+"/api/generate", body, &result); err != nil {
+		return "", err
+	}
+	return result.Response, nil
+}
+
+// openAI calls the chat completions endpoint
+func (s *Summarizer) openAI(ctx context.Context, prompt string) (string, error) {
+	body := map[string]interface{}{
+		"model": s.model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := s.post(ctx, s.url+"/chat/completions", body, &result); err != nil {
+		return "", err
+	}
+	if len(result.Choices) == 0 {
+		return "", nil
+	}
+	return result.Choices[0].Message.Content, nil
+}
+
+func (s *Summarizer) post(ctx context.Context, url string, body, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d: %s", url, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return json.Unmarshal(respBody, result)
+}
@@ -0,0 +1,94 @@
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/tokens"
+)
+
+func TestNew(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	if _, err := New(config.SummarizerConfig{Backend: "ollama"}); err == nil {
+		t.Error("Expected an error without a model")
+	}
+	if _, err := New(config.SummarizerConfig{Backend: "openai", Model: "gpt-4o-mini"}); err == nil {
+		t.Error("Expected an error for openai without an API key")
+	}
+	if _, err := New(config.SummarizerConfig{Backend: "bard", Model: "x"}); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+
+	s, err := New(config.SummarizerConfig{Model: "qwen2.5:1.5b"})
+	if err != nil || s.backend != BackendOllama || s.url != defaultOllamaURL {
+		t.Errorf("Expected ollama defaults, got %+v (%v)", s, err)
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	p := Prompt("", "ran tests", 1000)
+	if strings.Contains(p, "Previous summary") || !strings.HasSuffix(p, "ran tests") {
+		t.Errorf("Unexpected prompt without a previous summary:\n%s", p)
+	}
+
+	p = Prompt("- fixed bug", "ran tests", 1000)
+	if !strings.Contains(p, "Previous summary") || !strings.Contains(p, "- fixed bug") {
+		t.Errorf("Expected the previous summary in the prompt:\n%s", p)
+	}
+
+	long := "oldest line\n" + strings.Repeat("compiling module\n", 2000) + "newest line"
+	p = Prompt("", long, 500)
+	if tokens.Count(p) > 500 {
+		t.Errorf("Prompt overran its budget: %d tokens", tokens.Count(p))
+	}
+	if strings.Contains(p, "oldest line") || !strings.HasSuffix(p, "newest line") {
+		t.Error("Expected long output to keep its newest lines")
+	}
+}
+
+func TestSummarizeOllama(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/generate" || body["model"] != "tiny" || body["stream"] != false {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"response": "  - added tests\n"})
+	}))
+	defer server.Close()
+
+	s, _ := New(config.SummarizerConfig{Backend: "ollama", Model: "tiny", URL: server.URL})
+	got, err := s.Summarize(context.Background(), "", "output")
+	if err != nil || got != "- added tests" {
+		t.Errorf("Summarize = %q (%v)", got, err)
+	}
+}
+
+func TestSummarizeOpenAI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"- refactored parser"}}]}`))
+	}))
+	defer server.Close()
+
+	s, _ := New(config.SummarizerConfig{Backend: "openai", Model: "mini", URL: server.URL, APIKey: "key"})
+	got, err := s.Summarize(context.Background(), "", "output")
+	if err != nil || got != "- refactored parser" {
+		t.Errorf("Summarize = %q (%v)", got, err)
+	}
+
+	s, _ = New(config.SummarizerConfig{Backend: "openai", Model: "mini", URL: server.URL, APIKey: "wrong"})
+	if _, err := s.Summarize(context.Background(), "", "output"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the status in the error, got %v", err)
+	}
+}
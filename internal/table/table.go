@@ -0,0 +1,154 @@
+// Package table renders tabular command output as aligned text, CSV or
+// Markdown, so listings can be pasted into spreadsheets and docs.
+package table
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// Output formats
+const (
+	Text     = "table"
+	CSV      = "csv"
+	Markdown = "markdown"
+)
+
+// Table is a header and rows of cells
+type Table struct {
+	Header []string
+	Rows   [][]string
+
+	paint map[int]func(string) string
+}
+
+// New creates a table with the given column names
+func New(header ...string) *Table {
+	return &Table{Header: header}
+}
+
+// Add appends a row
+func (t *Table) Add(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Paint styles the cells of a column, such as coloring statuses, in text
+// output. CSV and Markdown stay plain.
+func (t *Table) Paint(column int, fn func(string) string) {
+	if t.paint == nil {
+		t.paint = make(map[int]func(string) string)
+	}
+	t.paint[column] = fn
+}
+
+// Validate checks an output format name
+func Validate(format string) error {
+	switch format {
+	case Text, CSV, Markdown, "md", "":
+		return nil
+	}
+	return fmt.Errorf("unknown output format: %s (use table, csv or markdown)", format)
+}
+
+// Write renders the table in format
+func (t *Table) Write(w io.Writer, format string) error {
+	if err := Validate(format); err != nil {
+		return err
+	}
+
+	switch format {
+	case CSV:
+		cw := csv.NewWriter(w)
+		cw.Write(t.Header)
+		cw.WriteAll(t.Rows)
+		return cw.Error()
+
+	case Markdown, "md":
+		fmt.Fprintf(w, "| %s |\n", strings.Join(escape(t.Header), " | "))
+		sep := make([]string, len(t.Header))
+		for i := range sep {
+			sep[i] = "---"
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(sep, " | "))
+		for _, row := range t.Rows {
+			fmt.Fprintf(w, "| %s |\n", strings.Join(escape(row), " | "))
+		}
+		return nil
+	}
+
+	under := make([]string, len(t.Header))
+	for i, h := range t.Header {
+		under[i] = strings.Repeat("-", len(h))
+	}
+	if len(t.paint) > 0 && t.singleLine() {
+		return t.writePainted(w, under)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.Header, "\t"))
+	fmt.Fprintln(tw, strings.Join(under, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writePainted lays the table out like tabwriter would, measuring cells
+// before they are painted so escape codes don't skew the columns
+func (t *Table) writePainted(w io.Writer, under []string) error {
+	lines := append([][]string{t.Header, under}, t.Rows...)
+	var widths []int
+	for _, cells := range lines {
+		for i, c := range cells {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(c))
+		}
+	}
+
+	for n, cells := range lines {
+		var b strings.Builder
+		for i, c := range cells {
+			text := c
+			if fn := t.paint[i]; fn != nil && n >= 2 {
+				text = fn(c)
+			}
+			b.WriteString(text)
+			if i < len(cells)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c)+2))
+			}
+		}
+		b.WriteString("\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// singleLine reports whether no cell spans lines or columns
+func (t *Table) singleLine() bool {
+	for _, row := range t.Rows {
+		for _, c := range row {
+			if strings.ContainsAny(c, "\t\n") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// escape keeps cells on one Markdown table line
+func escape(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, c := range cells {
+		c = strings.ReplaceAll(c, "|", `\|`)
+		out[i] = strings.ReplaceAll(strings.TrimSpace(c), "\n", "<br>")
+	}
+	return out
+}
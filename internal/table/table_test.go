@@ -0,0 +1,78 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testTable() *Table {
+	t := New("NAME", "STATUS")
+	t.Add("coder", "running")
+	t.Add("a|b", "line one\nline two")
+	return t
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testTable().Write(&buf, Text); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	want := "NAME   STATUS\n----   ------\ncoder  running\n"
+	if got := buf.String(); got[:len(want)] != want {
+		t.Errorf("Unexpected table:\n%s", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	testTable().Write(&buf, CSV)
+	want := "NAME,STATUS\ncoder,running\na|b,\"line one\nline two\"\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected csv:\n%s", buf.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	testTable().Write(&buf, Markdown)
+	want := "| NAME | STATUS |\n| --- | --- |\n| coder | running |\n| a\\|b | line one<br>line two |\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected markdown:\n%s", buf.String())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("xlsx"); err == nil {
+		t.Error("Expected an unknown format rejected")
+	}
+	if err := Validate("md"); err != nil {
+		t.Errorf("Expected md accepted as markdown, got %v", err)
+	}
+}
+
+func TestPaint(t *testing.T) {
+	plain := New("NAME", "STATUS", "AGE")
+	plain.Add("coder", "running", "2h")
+	plain.Add("reviewer-long", "paused", "5m")
+
+	painted := New("NAME", "STATUS", "AGE")
+	painted.Rows = plain.Rows
+	painted.Paint(1, func(s string) string { return "<" + s + ">" })
+
+	var want, got bytes.Buffer
+	plain.Write(&want, Text)
+	painted.Write(&got, Text)
+
+	// Columns line up as if the cells were unpainted
+	expected := strings.NewReplacer("running", "<running>", "paused", "<paused>").Replace(want.String())
+	if got.String() != expected {
+		t.Errorf("Unexpected painted table:\n%s\nwant:\n%s", got.String(), expected)
+	}
+
+	var csvOut bytes.Buffer
+	painted.Write(&csvOut, CSV)
+	if strings.Contains(csvOut.String(), "<") {
+		t.Errorf("Expected csv left plain, got %q", csvOut.String())
+	}
+}
@@ -0,0 +1,222 @@
+// Package telemetry keeps opt-in, anonymous usage counts: which commands
+// run, with which kind of agent, and how they fail. Events are spooled
+// locally, where `gforge telemetry status` shows them, and sent in batches
+// to the configured endpoint. Nothing is recorded until turned on.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Files in the telemetry directory
+const (
+	stateFile = "state.json"
+	spoolFile = "spool.jsonl"
+)
+
+// Event is one command run. It carries no names, paths, arguments or
+// messages.
+type Event struct {
+	Day     string `json:"day"` // UTC date, YYYY-MM-DD
+	Install string `json:"install"`
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	Command string `json:"command"`         // e.g. "spawn" or "context send"
+	Agent   string `json:"agent,omitempty"` // Built-in agent name, or "custom"
+	Error   string `json:"error,omitempty"` // Error category, "" on success
+}
+
+// State is whether telemetry is on, and the random ID events are grouped by
+type State struct {
+	Enabled   bool      `json:"enabled"`
+	InstallID string    `json:"install_id,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+	LastSent  time.Time `json:"last_sent,omitempty"`
+}
+
+// Telemetry is the spool in a directory
+type Telemetry struct {
+	dir   string
+	state State
+}
+
+// Open reads the telemetry state in dir. A missing state means off.
+func Open(dir string) (*Telemetry, error) {
+	t := &Telemetry{dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telemetry state: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.state); err != nil {
+		return nil, fmt.Errorf("failed to parse telemetry state: %w", err)
+	}
+	return t, nil
+}
+
+// State returns the saved state
+func (t *Telemetry) State() State {
+	return t.state
+}
+
+// OptedOut reports whether the environment forbids telemetry regardless
+// of the saved state: DO_NOT_TRACK=1 or GFORGE_TELEMETRY=0
+func OptedOut() bool {
+	return os.Getenv("DO_NOT_TRACK") == "1" || os.Getenv("GFORGE_TELEMETRY") == "0"
+}
+
+// Enabled reports whether events are recorded
+func (t *Telemetry) Enabled() bool {
+	return t.state.Enabled && !OptedOut()
+}
+
+// SetEnabled turns telemetry on or off. Turning it on picks an install ID
+// if there is none; turning it off deletes the spool and the ID.
+func (t *Telemetry) SetEnabled(enabled bool) error {
+	if enabled && t.state.InstallID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return fmt.Errorf("failed to generate install ID: %w", err)
+		}
+		t.state.InstallID = hex.EncodeToString(id)
+	}
+	if !enabled {
+		t.state.InstallID = ""
+		if err := os.Remove(t.SpoolPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to delete telemetry spool: %w", err)
+		}
+	}
+	t.state.Enabled = enabled
+	t.state.ChangedAt = time.Now().UTC()
+	return t.save()
+}
+
+// Record spools an event when telemetry is enabled, filling in the
+// install, day and platform
+func (t *Telemetry) Record(e Event) error {
+	if !t.Enabled() {
+		return nil
+	}
+	e.Day = time.Now().UTC().Format("2006-01-02")
+	e.Install = t.state.InstallID
+	e.OS = runtime.GOOS
+	e.Arch = runtime.GOARCH
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	f, err := os.OpenFile(t.SpoolPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open telemetry spool: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Pending returns the spooled events not yet sent
+func (t *Telemetry) Pending() ([]Event, error) {
+	f, err := os.Open(t.SpoolPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open telemetry spool: %w", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// SendDue reports whether spooled events are waiting and the last send
+// was at least interval ago
+func (t *Telemetry) SendDue(interval time.Duration) bool {
+	if !t.Enabled() || time.Since(t.state.LastSent) < interval {
+		return false
+	}
+	info, err := os.Stat(t.SpoolPath())
+	return err == nil && info.Size() > 0
+}
+
+// Send posts the pending events to endpoint as a JSON array and empties
+// the spool. It returns how many were sent.
+func (t *Telemetry) Send(ctx context.Context, endpoint string) (int, error) {
+	if !t.Enabled() || endpoint == "" {
+		return 0, nil
+	}
+	events, err := t.Pending()
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	body, err := json.Marshal(events)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("failed to send telemetry: %s", resp.Status)
+	}
+
+	if err := os.Remove(t.SpoolPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return len(events), fmt.Errorf("failed to clear telemetry spool: %w", err)
+	}
+	t.state.LastSent = time.Now().UTC()
+	return len(events), t.save()
+}
+
+// SpoolPath returns the file events wait in
+func (t *Telemetry) SpoolPath() string {
+	return filepath.Join(t.dir, spoolFile)
+}
+
+func (t *Telemetry) save() error {
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	data, err := json.MarshalIndent(t.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, stateFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to save telemetry state: %w", err)
+	}
+	return nil
+}
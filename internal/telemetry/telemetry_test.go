@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOffByDefault(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("GFORGE_TELEMETRY", "")
+	tel, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if tel.Enabled() {
+		t.Error("Expected telemetry off without opting in")
+	}
+	tel.Record(Event{Command: "list"})
+	if _, err := os.Stat(tel.SpoolPath()); !os.IsNotExist(err) {
+		t.Error("Expected nothing spooled while off")
+	}
+}
+
+func TestOnAndOff(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("GFORGE_TELEMETRY", "")
+	dir := t.TempDir()
+	tel, _ := Open(dir)
+	if err := tel.SetEnabled(true); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+
+	// The state survives reopening
+	tel, _ = Open(dir)
+	if !tel.Enabled() || len(tel.State().InstallID) != 32 {
+		t.Fatalf("Expected telemetry on with an install ID, got %+v", tel.State())
+	}
+
+	tel.Record(Event{Command: "spawn", Agent: "claude"})
+	tel.Record(Event{Command: "kill", Error: "goblin_not_found"})
+	events, err := tel.Pending()
+	if err != nil || len(events) != 2 {
+		t.Fatalf("Expected 2 pending events, got %d (%v)", len(events), err)
+	}
+	if e := events[0]; e.Install != tel.State().InstallID || e.Day == "" || e.OS == "" || e.Agent != "claude" {
+		t.Errorf("Unexpected event: %+v", e)
+	}
+
+	t.Setenv("DO_NOT_TRACK", "1")
+	if tel.Enabled() {
+		t.Error("Expected DO_NOT_TRACK to override the saved state")
+	}
+	t.Setenv("DO_NOT_TRACK", "")
+
+	if err := tel.SetEnabled(false); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	if events, _ := tel.Pending(); len(events) != 0 || tel.State().InstallID != "" {
+		t.Error("Expected turning off to delete the spool and install ID")
+	}
+}
+
+func TestSend(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("GFORGE_TELEMETRY", "")
+
+	var received []Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+
+	tel, _ := Open(t.TempDir())
+	tel.SetEnabled(true)
+	if tel.SendDue(time.Hour) {
+		t.Error("Expected nothing due with an empty spool")
+	}
+	tel.Record(Event{Command: "list"})
+	if !tel.SendDue(time.Hour) {
+		t.Fatal("Expected events never sent to be due")
+	}
+
+	n, err := tel.Send(context.Background(), srv.URL)
+	if err != nil || n != 1 || len(received) != 1 || received[0].Command != "list" {
+		t.Fatalf("Send() = %d, %v; server got %+v", n, err, received)
+	}
+	if events, _ := tel.Pending(); len(events) != 0 {
+		t.Error("Expected the spool emptied after sending")
+	}
+	tel.Record(Event{Command: "list"})
+	if tel.SendDue(time.Hour) {
+		t.Error("Expected no send due within the interval")
+	}
+}
@@ -3,6 +3,7 @@ package template
 import (
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,7 +12,7 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-//go:embed builtin/*.yaml
+//go:embed builtin
 var builtinTemplates embed.FS
 
 // Engine manages project templates and auto-detection
@@ -72,57 +73,36 @@ func New() (*Engine, error) {
 
 // loadBuiltinTemplates loads templates from embedded filesystem
 func (e *Engine) loadBuiltinTemplates() error {
-	entries, err := builtinTemplates.ReadDir("builtin")
-	if err != nil {
-		// If no embedded templates, load from disk (development mode)
-		return e.loadTemplatesFromDisk()
-	}
-
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
-			continue
-		}
-
-		data, err := builtinTemplates.ReadFile("builtin/" + entry.Name())
-		if err != nil {
-			return fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
-		}
-
-		var tmpl Template
-		if err := yaml.Unmarshal(data, &tmpl); err != nil {
-			return fmt.Errorf("failed to parse template %s: %w", entry.Name(), err)
-		}
-
-		name := strings.TrimSuffix(entry.Name(), ".yaml")
-		tmpl.Name = name
-		e.builtin[name] = &tmpl
-	}
-
-	return nil
+	return e.loadTemplates(builtinTemplates, "builtin")
 }
 
-// loadTemplatesFromDisk loads templates from the templates directory
+// loadTemplatesFromDisk loads templates from the source tree's builtin
+// directory, for running from a checkout
 func (e *Engine) loadTemplatesFromDisk() error {
-	templatesDir := "templates/builtin"
+	return e.loadTemplates(os.DirFS("internal/template"), "builtin")
+}
 
+// loadTemplates loads the templates under root in fsys, taking each one's
+// category from its subdirectory
+func (e *Engine) loadTemplates(fsys fs.FS, root string) error {
 	// Walk through all subdirectories
-	return filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
+	return fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil // Skip errors
 		}
 
-		if info.IsDir() || !strings.HasSuffix(path, ".yaml") {
+		if d.IsDir() || !strings.HasSuffix(path, ".yaml") {
 			return nil
 		}
 
-		data, err := os.ReadFile(path)
+		data, err := fs.ReadFile(fsys, path)
 		if err != nil {
-			return nil // Skip files we can't read
+			return fmt.Errorf("failed to read template %s: %w", path, err)
 		}
 
 		var tmpl Template
 		if err := yaml.Unmarshal(data, &tmpl); err != nil {
-			return nil // Skip invalid templates
+			return fmt.Errorf("failed to parse template %s: %w", path, err)
 		}
 
 		// Extract name from path
@@ -132,9 +112,8 @@ func (e *Engine) loadTemplatesFromDisk() error {
 		}
 
 		// Set category from directory
-		dir := filepath.Dir(path)
-		category := filepath.Base(dir)
-		if tmpl.Category == "" && category != "builtin" {
+		category := filepath.Base(filepath.Dir(path))
+		if tmpl.Category == "" && category != root {
 			tmpl.Category = category
 		}
 
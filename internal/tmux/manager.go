@@ -6,18 +6,23 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/trace"
 )
 
 // Manager handles tmux session lifecycle
 type Manager struct {
-	socketName  string
-	socketPath  string
-	sessions    map[string]*Session
-	mu          sync.RWMutex
-	captureDir  string
+	socketName   string
+	socketPath   string
+	sessions     map[string]*Session
+	mu           sync.RWMutex
+	captureDir   string
+	historyLimit int
 }
 
 // Session represents a tmux session
@@ -48,7 +53,7 @@ const (
 type Config struct {
 	SocketName   string
 	CaptureDir   string
-	HistoryLimit int
+	HistoryLimit int // Most scrollback lines captured; 0 takes all the pane keeps
 	DefaultShell string
 }
 
@@ -60,26 +65,15 @@ func NewManager(cfg Config) *Manager {
 	if cfg.CaptureDir == "" {
 		cfg.CaptureDir = filepath.Join(os.TempDir(), "gforge", "captures")
 	}
-	if cfg.HistoryLimit == 0 {
-		cfg.HistoryLimit = 50000
-	}
-
 	// Ensure capture directory exists
 	os.MkdirAll(cfg.CaptureDir, 0755)
 
-	// Determine socket path
-	socketPath := ""
-	if tmpDir := os.Getenv("TMUX_TMPDIR"); tmpDir != "" {
-		socketPath = filepath.Join(tmpDir, cfg.SocketName)
-	} else if tmpDir := os.Getenv("TMPDIR"); tmpDir != "" {
-		socketPath = filepath.Join(tmpDir, fmt.Sprintf("tmux-%d", os.Getuid()), cfg.SocketName)
-	}
-
 	return &Manager{
-		socketName: cfg.SocketName,
-		socketPath: socketPath,
-		sessions:   make(map[string]*Session),
-		captureDir: cfg.CaptureDir,
+		socketName:   cfg.SocketName,
+		socketPath:   SocketPath(cfg.SocketName),
+		sessions:     make(map[string]*Session),
+		captureDir:   cfg.CaptureDir,
+		historyLimit: cfg.HistoryLimit,
 	}
 }
 
@@ -119,8 +113,9 @@ func (m *Manager) Create(name, workingDir string) (*Session, error) {
 		args = append(args, "-c", workingDir)
 	}
 
-	cmd := exec.Command("tmux", args...)
-	output, err := cmd.CombinedOutput()
+	output, err := trace.Retry(trace.DefaultBackoff, nil, func() *trace.Cmd {
+		return trace.Command("tmux", args...)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create tmux session: %w\nOutput: %s", err, string(output))
 	}
@@ -154,13 +149,13 @@ func (m *Manager) Create(name, workingDir string) (*Session, error) {
 
 // sessionExists checks if a tmux session exists
 func (m *Manager) sessionExists(name string) bool {
-	cmd := exec.Command("tmux", "-L", m.socketName, "has-session", "-t", name)
+	cmd := trace.Command("tmux", "-L", m.socketName, "has-session", "-t", name)
 	return cmd.Run() == nil
 }
 
 // getSessionInfo retrieves window and pane IDs for a session
 func (m *Manager) getSessionInfo(name string) (windowID, paneID string) {
-	cmd := exec.Command("tmux", "-L", m.socketName,
+	cmd := trace.Command("tmux", "-L", m.socketName,
 		"list-panes", "-t", name, "-F", "#{window_id}:#{pane_id}")
 	output, err := cmd.Output()
 	if err != nil {
@@ -184,7 +179,7 @@ func (m *Manager) startCapture(session *Session) error {
 	f.Close()
 
 	// Use pipe-pane to capture output
-	cmd := exec.Command("tmux", "-L", m.socketName,
+	cmd := trace.Command("tmux", "-L", m.socketName,
 		"pipe-pane", "-t", session.Name,
 		fmt.Sprintf("cat >> %s", session.capturePath))
 
@@ -193,7 +188,7 @@ func (m *Manager) startCapture(session *Session) error {
 
 // stopCapture stops capturing output
 func (m *Manager) stopCapture(session *Session) error {
-	cmd := exec.Command("tmux", "-L", m.socketName,
+	cmd := trace.Command("tmux", "-L", m.socketName,
 		"pipe-pane", "-t", session.Name)
 	return cmd.Run()
 }
@@ -218,7 +213,7 @@ func (m *Manager) Attach(name string) error {
 	// Attach to tmux session (replaces current process)
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
-		return fmt.Errorf("tmux not found: %w", err)
+		return errs.WithHint(fmt.Errorf("%w: %v", errs.ErrTmuxUnavailable, err), InstallHint)
 	}
 
 	args := []string{"tmux", "-L", m.socketName, "attach-session", "-t", name}
@@ -230,7 +225,7 @@ func (m *Manager) Attach(name string) error {
 
 // execCommand is a wrapper that can be mocked in tests
 var execCommand = func(path string, args []string, env []string) error {
-	cmd := exec.Command(path, args[1:]...)
+	cmd := trace.Command(path, args[1:]...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -247,8 +242,9 @@ func (m *Manager) SendKeys(name string, keys ...string) error {
 	args := []string{"-L", m.socketName, "send-keys", "-t", name}
 	args = append(args, keys...)
 
-	cmd := exec.Command("tmux", args...)
-	output, err := cmd.CombinedOutput()
+	output, err := trace.Retry(trace.DefaultBackoff, nil, func() *trace.Cmd {
+		return trace.Command("tmux", args...)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to send keys: %w\nOutput: %s", err, string(output))
 	}
@@ -256,6 +252,27 @@ func (m *Manager) SendKeys(name string, keys ...string) error {
 	return nil
 }
 
+// Paste types multi-line text into a session as a single bracketed paste,
+// so agents receive it as one message instead of submitting each line
+func (m *Manager) Paste(name, text string) error {
+	buffer := fmt.Sprintf("gforge-paste-%d", time.Now().UnixNano())
+
+	load := trace.Command("tmux", "-L", m.socketName, "load-buffer", "-b", buffer, "-")
+	load.Stdin = strings.NewReader(text)
+	if output, err := load.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to load paste buffer: %w\nOutput: %s", err, string(output))
+	}
+
+	paste := trace.Command("tmux", "-L", m.socketName,
+		"paste-buffer", "-p", "-d", "-b", buffer, "-t", name)
+	if output, err := paste.CombinedOutput(); err != nil {
+		trace.Command("tmux", "-L", m.socketName, "delete-buffer", "-b", buffer).Run()
+		return fmt.Errorf("failed to paste into session: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
 // SendCommand sends a command (with Enter key) to a session
 func (m *Manager) SendCommand(name, command string) error {
 	return m.SendKeys(name, command, "Enter")
@@ -275,27 +292,41 @@ func (m *Manager) Kill(name string) error {
 	}
 
 	// Kill tmux session
-	cmd := exec.Command("tmux", "-L", m.socketName, "kill-session", "-t", name)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		// Session might already be dead
-		if !strings.Contains(string(output), "no such session") {
-			return fmt.Errorf("failed to kill session: %w\nOutput: %s", err, string(output))
-		}
+	output, err := trace.Retry(trace.DefaultBackoff, nil, func() *trace.Cmd {
+		return trace.Command("tmux", "-L", m.socketName, "kill-session", "-t", name)
+	})
+	if err != nil && !SessionGone(string(output)) {
+		return fmt.Errorf("failed to kill session: %w\nOutput: %s", err, string(output))
 	}
 
 	return nil
 }
 
+// SessionGone reports whether a failed tmux command's output says the
+// session it named, or the whole server, no longer exists
+func SessionGone(output string) bool {
+	for _, s := range []string{"can't find session", "no such session", "no server running"} {
+		if strings.Contains(output, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // List returns all tracked sessions
 func (m *Manager) List() []*Session {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	live, err := m.Sessions()
 	sessions := make([]*Session, 0, len(m.sessions))
 	for _, s := range m.sessions {
-		// Update status
-		if m.sessionExists(s.Name) {
+		// Update status, unless tmux couldn't say which sessions are alive
+		if err != nil {
+			sessions = append(sessions, s)
+			continue
+		}
+		if _, ok := live[s.Name]; ok {
 			if s.Status == StatusDead {
 				s.Status = StatusRunning
 			}
@@ -317,7 +348,7 @@ func (m *Manager) Get(name string) *Session {
 
 // ListTmuxSessions lists all tmux sessions (including untracked)
 func (m *Manager) ListTmuxSessions() ([]string, error) {
-	cmd := exec.Command("tmux", "-L", m.socketName, "list-sessions", "-F", "#{session_name}")
+	cmd := trace.Command("tmux", "-L", m.socketName, "list-sessions", "-F", "#{session_name}")
 	output, err := cmd.Output()
 	if err != nil {
 		// No sessions might exist
@@ -336,21 +367,160 @@ func (m *Manager) ListTmuxSessions() ([]string, error) {
 	return sessions, nil
 }
 
-// CapturePane captures the current pane content
+// SessionInfo is the live state of a tmux session
+type SessionInfo struct {
+	Name     string
+	Command  string    // foreground process in the active pane
+	Activity time.Time // last output, to the second
+	Attached bool
+}
+
+// shells are foreground commands that mean the program a session was
+// started with has exited
+var shells = map[string]bool{
+	"bash": true, "zsh": true, "sh": true, "fish": true, "dash": true, "ksh": true,
+}
+
+// Shell reports whether a pane's foreground command is a shell, as when
+// an agent started from it has exited
+func Shell(command string) bool {
+	return shells[command]
+}
+
+// Sessions returns every session on the socket with one tmux call, so
+// checking a large fleet doesn't cost a process per goblin. An empty map
+// means no server is running; any other failure is an error, since it
+// says nothing about which sessions are alive.
+func (m *Manager) Sessions() (map[string]SessionInfo, error) {
+	output, err := trace.Retry(trace.DefaultBackoff, nil, func() *trace.Cmd {
+		return trace.Command("tmux", "-L", m.socketName, "list-sessions", "-F",
+			"#{session_name}\t#{pane_current_command}\t#{session_activity}\t#{session_attached}")
+	})
+	if err != nil {
+		if NoServer(string(output)) {
+			return map[string]SessionInfo{}, nil
+		}
+		return nil, fmt.Errorf("failed to list sessions: %w\nOutput: %s", err, string(output))
+	}
+	return parseSessions(string(output)), nil
+}
+
+// NoServer reports whether a failed tmux command's output says no server
+// is running on its socket, or the socket doesn't exist
+func NoServer(output string) bool {
+	return strings.Contains(output, "no server running") ||
+		(strings.Contains(output, "error connecting to") && strings.Contains(output, "No such file or directory"))
+}
+
+// parseSessions reads the list-sessions output of Sessions
+func parseSessions(output string) map[string]SessionInfo {
+	sessions := make(map[string]SessionInfo)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 4 || fields[0] == "" {
+			continue
+		}
+		info := SessionInfo{Name: fields[0], Command: fields[1], Attached: fields[3] != "0"}
+		if secs, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			info.Activity = time.Unix(secs, 0)
+		}
+		sessions[info.Name] = info
+	}
+	return sessions
+}
+
+// CapturePane captures the current pane content with up to lines lines of
+// scrollback: 1000 when lines is 0, all of it when lines is negative
 func (m *Manager) CapturePane(name string, lines int) (string, error) {
 	if lines == 0 {
 		lines = 1000
 	}
 
-	cmd := exec.Command("tmux", "-L", m.socketName,
-		"capture-pane", "-t", name, "-p", "-S", fmt.Sprintf("-%d", lines))
+	output, err := trace.Retry(trace.DefaultBackoff, nil, func() *trace.Cmd {
+		return trace.Command("tmux", "-L", m.socketName,
+			"capture-pane", "-t", name, "-p", "-S", historyStart(lines))
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane: %w\nOutput: %s", err, string(output))
+	}
+
+	return string(output), nil
+}
+
+// CaptureHistory captures a pane's scrollback along with its visible
+// content, at most lines lines and never more than the history limit
+// (lines <= 0 takes all of it). Wrapped lines are joined and the blank
+// rows below the cursor dropped, so the text reads like a log.
+func (m *Manager) CaptureHistory(name string, lines int) (string, error) {
+	if lines <= 0 || (m.historyLimit > 0 && lines > m.historyLimit) {
+		lines = m.historyLimit
+	}
+
+	cmd := trace.Command("tmux", "-L", m.socketName,
+		"capture-pane", "-t", name, "-p", "-J", "-S", historyStart(lines))
 
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to capture pane: %w", err)
 	}
 
-	return string(output), nil
+	return strings.TrimRight(string(output), "\n") + "\n", nil
+}
+
+// historyStart is capture-pane's -S for the last lines lines of
+// scrollback, or with none given its start ("-S -0" would be the first
+// visible line)
+func historyStart(lines int) string {
+	if lines <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("-%d", lines)
+}
+
+// PaneCommand returns the name of the process in the foreground of a
+// session's active pane
+func (m *Manager) PaneCommand(name string) (string, error) {
+	cmd := trace.Command("tmux", "-L", m.socketName,
+		"display-message", "-p", "-t", name, "#{pane_current_command}")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get pane command: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// WaitIdle polls a session's pane until its content has not changed for
+// idle. Returns true when the pane went quiet or the session exited, and
+// false if timeout elapsed first.
+func (m *Manager) WaitIdle(name string, idle, timeout, poll time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	lastChange := time.Now()
+	lastOutput := ""
+
+	for time.Now().Before(deadline) {
+		time.Sleep(poll)
+
+		output, err := m.CapturePane(name, 200)
+		if err != nil {
+			// Session is gone, the program exited
+			return true
+		}
+
+		if output != lastOutput {
+			lastOutput = output
+			lastChange = time.Now()
+			continue
+		}
+
+		if time.Since(lastChange) >= idle {
+			return true
+		}
+	}
+
+	return false
 }
 
 // GetOutput reads captured output from a session
@@ -390,7 +560,7 @@ func (m *Manager) GetOutput(name string, lines int) ([]string, error) {
 
 // Resize resizes a session
 func (m *Manager) Resize(name string, width, height int) error {
-	cmd := exec.Command("tmux", "-L", m.socketName,
+	cmd := trace.Command("tmux", "-L", m.socketName,
 		"resize-window", "-t", name, "-x", fmt.Sprintf("%d", width), "-y", fmt.Sprintf("%d", height))
 
 	output, err := cmd.CombinedOutput()
@@ -403,7 +573,7 @@ func (m *Manager) Resize(name string, width, height int) error {
 
 // SetEnvironment sets an environment variable in a session
 func (m *Manager) SetEnvironment(name, key, value string) error {
-	cmd := exec.Command("tmux", "-L", m.socketName,
+	cmd := trace.Command("tmux", "-L", m.socketName,
 		"set-environment", "-t", name, key, value)
 
 	return cmd.Run()
@@ -411,7 +581,7 @@ func (m *Manager) SetEnvironment(name, key, value string) error {
 
 // IsServerRunning checks if the tmux server is running
 func (m *Manager) IsServerRunning() bool {
-	cmd := exec.Command("tmux", "-L", m.socketName, "list-sessions")
+	cmd := trace.Command("tmux", "-L", m.socketName, "list-sessions")
 	return cmd.Run() == nil
 }
 
@@ -421,7 +591,7 @@ func (m *Manager) StartServer() error {
 		return nil
 	}
 
-	cmd := exec.Command("tmux", "-L", m.socketName, "start-server")
+	cmd := trace.Command("tmux", "-L", m.socketName, "start-server")
 	return cmd.Run()
 }
 
@@ -433,7 +603,7 @@ func (m *Manager) KillServer() error {
 	// Clear tracked sessions
 	m.sessions = make(map[string]*Session)
 
-	cmd := exec.Command("tmux", "-L", m.socketName, "kill-server")
+	cmd := trace.Command("tmux", "-L", m.socketName, "kill-server")
 	cmd.Run() // Ignore errors - server might not be running
 
 	return nil
@@ -4,7 +4,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewManager(t *testing.T) {
@@ -152,6 +154,24 @@ func TestKillSession(t *testing.T) {
 	if mgr.sessionExists("kill-test") {
 		t.Error("Session should not exist after kill")
 	}
+
+	// Killing it again finds nothing to do
+	if err := mgr.Kill("kill-test"); err != nil {
+		t.Errorf("Expected killing an ended session to succeed, got %v", err)
+	}
+}
+
+func TestSessionGone(t *testing.T) {
+	for output, want := range map[string]bool{
+		"can't find session: gforge-x":            true,
+		"no server running on /tmp/tmux-0/gforge": true,
+		"server exited unexpectedly":              false,
+		"error connecting to /tmp/tmux-0/gforge":  false,
+	} {
+		if got := SessionGone(output); got != want {
+			t.Errorf("SessionGone(%q) = %v, want %v", output, got, want)
+		}
+	}
 }
 
 func TestSendKeys(t *testing.T) {
@@ -331,3 +351,229 @@ func TestCaptureDirectory(t *testing.T) {
 
 	_ = mgr
 }
+
+func TestWaitIdle(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not available")
+	}
+
+	tmpDir, _ := os.MkdirTemp("", "gforge-tmux-test-*")
+	defer os.RemoveAll(tmpDir)
+
+	mgr := NewManager(Config{
+		SocketName: "gforge-test-idle",
+		CaptureDir: tmpDir,
+	})
+
+	// A missing session counts as exited
+	if !mgr.WaitIdle("no-such-session", time.Second, time.Second, 10*time.Millisecond) {
+		t.Error("WaitIdle should return true for a missing session")
+	}
+
+	_, err := mgr.Create("idle-test", tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer mgr.Kill("idle-test")
+
+	// A quiet shell settles well before the timeout
+	if !mgr.WaitIdle("idle-test", 200*time.Millisecond, 5*time.Second, 50*time.Millisecond) {
+		t.Error("WaitIdle should return true for a quiet session")
+	}
+
+	// An idle period longer than the timeout cannot be satisfied
+	if mgr.WaitIdle("idle-test", time.Minute, 300*time.Millisecond, 50*time.Millisecond) {
+		t.Error("WaitIdle should time out")
+	}
+}
+
+func TestPaneCommand(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not available")
+	}
+
+	tmpDir, _ := os.MkdirTemp("", "gforge-tmux-test-*")
+	defer os.RemoveAll(tmpDir)
+
+	mgr := NewManager(Config{
+		SocketName: "gforge-test-panecmd",
+		CaptureDir: tmpDir,
+	})
+
+	if _, err := mgr.PaneCommand("no-such-session"); err == nil {
+		t.Error("PaneCommand should fail for a missing session")
+	}
+
+	_, err := mgr.Create("panecmd-test", tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer mgr.Kill("panecmd-test")
+
+	command, err := mgr.PaneCommand("panecmd-test")
+	if err != nil {
+		t.Fatalf("PaneCommand failed: %v", err)
+	}
+	if command == "" {
+		t.Error("Expected a foreground command")
+	}
+}
+
+func TestCaptureHistory(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not available")
+	}
+
+	socket := "gforge-test-history"
+	defer exec.Command("tmux", "-L", socket, "kill-server").Run()
+
+	if err := exec.Command("tmux", "-L", socket, "new-session", "-d", "-s", "history-test",
+		"-x", "80", "-y", "24", "seq 1 300; sleep 30").Run(); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	mgr := NewManager(Config{SocketName: socket, HistoryLimit: 100})
+
+	all, err := mgr.CaptureHistory("history-test", 0)
+	if err != nil {
+		t.Fatalf("CaptureHistory failed: %v", err)
+	}
+	rows := strings.Split(strings.TrimSuffix(all, "\n"), "\n")
+	if rows[len(rows)-1] != "300" {
+		t.Errorf("Expected trailing blank rows dropped, last row %q", rows[len(rows)-1])
+	}
+	if len(rows) <= 24 {
+		t.Errorf("Expected scrollback beyond the visible pane, got %d rows", len(rows))
+	}
+	if len(rows) > 100+24 {
+		t.Errorf("Expected at most the history limit plus the pane, got %d rows", len(rows))
+	}
+
+	some, err := mgr.CaptureHistory("history-test", 10)
+	if err != nil {
+		t.Fatalf("CaptureHistory failed: %v", err)
+	}
+	if n := strings.Count(some, "\n"); n > 10+24 {
+		t.Errorf("Expected at most 10 lines of history, got %d rows", n)
+	}
+
+	// Without a limit the whole scrollback comes back
+	unlimited, err := NewManager(Config{SocketName: socket}).CaptureHistory("history-test", 0)
+	if err != nil {
+		t.Fatalf("CaptureHistory failed: %v", err)
+	}
+	if !strings.HasPrefix(unlimited, "1\n2\n") {
+		t.Errorf("Expected the history from its first line, got %q", unlimited[:min(len(unlimited), 20)])
+	}
+
+	if _, err := mgr.CaptureHistory("no-such-session", 0); err == nil {
+		t.Error("CaptureHistory should fail for a missing session")
+	}
+}
+
+func TestPaste(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not available")
+	}
+
+	tmpDir, _ := os.MkdirTemp("", "gforge-tmux-test-*")
+	defer os.RemoveAll(tmpDir)
+
+	mgr := NewManager(Config{
+		SocketName: "gforge-test-paste",
+		CaptureDir: tmpDir,
+	})
+
+	if err := mgr.Paste("no-such-session", "text"); err == nil {
+		t.Error("Paste should fail for a missing session")
+	}
+
+	if _, err := mgr.Create("paste-test", tmpDir); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer mgr.Kill("paste-test")
+
+	// cat echoes pasted lines back without running them
+	mgr.SendCommand("paste-test", "cat")
+	time.Sleep(300 * time.Millisecond)
+
+	if err := mgr.Paste("paste-test", "first line\nsecond line\n"); err != nil {
+		t.Fatalf("Paste failed: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	output, _ := mgr.CapturePane("paste-test", 50)
+	if !strings.Contains(output, "first line") || !strings.Contains(output, "second line") {
+		t.Errorf("Expected pasted text in pane, got:\n%s", output)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	if !tmuxAvailable() {
+		t.Skip("tmux not available")
+	}
+
+	tmpDir, _ := os.MkdirTemp("", "gforge-tmux-test-*")
+	defer os.RemoveAll(tmpDir)
+
+	mgr := NewManager(Config{
+		SocketName: "gforge-test-sessions",
+		CaptureDir: tmpDir,
+	})
+
+	if sessions, err := mgr.Sessions(); err != nil || len(sessions) != 0 {
+		t.Errorf("Expected no sessions without a server, got %v, %v", sessions, err)
+	}
+
+	for _, name := range []string{"sessions-a", "sessions-b"} {
+		if _, err := mgr.Create(name, tmpDir); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		defer mgr.Kill(name)
+	}
+
+	sessions, err := mgr.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions failed: %v", err)
+	}
+	for _, name := range []string{"sessions-a", "sessions-b"} {
+		info, ok := sessions[name]
+		if !ok {
+			t.Fatalf("Expected %s listed", name)
+		}
+		if info.Command == "" || info.Activity.IsZero() || info.Attached {
+			t.Errorf("Expected a detached session with a command and activity, got %+v", info)
+		}
+	}
+}
+
+func TestParseSessions(t *testing.T) {
+	sessions := parseSessions("build\tclaude\t1700000000\t1\n\nbroken line\nweird name\tbash\t\t0\n")
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if s := sessions["build"]; s.Command != "claude" || !s.Attached || s.Activity.Unix() != 1700000000 {
+		t.Errorf("Unexpected session: %+v", s)
+	}
+	if s := sessions["weird name"]; !s.Activity.IsZero() {
+		t.Errorf("Expected no activity, got %v", s.Activity)
+	}
+}
+
+func TestNoServer(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"no server running on /tmp/tmux-1000/gforge", true},
+		{"error connecting to /tmp/tmux-1000/gforge (No such file or directory)", true},
+		{"error connecting to /tmp/tmux-1000/gforge (Connection refused)", false},
+		{"server exited unexpectedly", false},
+	}
+	for _, tt := range tests {
+		if got := NoServer(tt.output); got != tt.want {
+			t.Errorf("NoServer(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+// SocketState is what CheckSocket found at a socket's path
+type SocketState string
+
+const (
+	SocketMissing SocketState = "missing" // No server has used it yet
+	SocketLive    SocketState = "live"    // A server is listening
+	SocketStale   SocketState = "stale"   // Left behind by a server that died
+	SocketForeign SocketState = "foreign" // Owned by another user
+	SocketUnsafe  SocketState = "unsafe"  // Not a socket, or tmux would refuse its directory
+)
+
+// SocketPath returns where tmux keeps the socket for `tmux -L name`:
+// $TMUX_TMPDIR (or /tmp), then tmux-UID
+func SocketPath(name string) string {
+	dir := os.Getenv("TMUX_TMPDIR")
+	if dir == "" {
+		dir = "/tmp"
+	}
+	return filepath.Join(dir, fmt.Sprintf("tmux-%d", os.Getuid()), name)
+}
+
+// SocketStatus is the result of checking a socket
+type SocketStatus struct {
+	Name   string
+	Path   string
+	State  SocketState
+	Detail string
+}
+
+// CheckSocket inspects a named socket and its directory the way tmux will,
+// so ownership and permission problems can be reported clearly instead of
+// as tmux's own errors
+func CheckSocket(name string) SocketStatus {
+	path := SocketPath(name)
+	status := SocketStatus{Name: name, Path: path}
+
+	if owner, mode, err := statOwner(filepath.Dir(path)); err == nil {
+		if owner != os.Getuid() {
+			status.State = SocketForeign
+			status.Detail = fmt.Sprintf("directory %s belongs to uid %d", filepath.Dir(path), owner)
+			return status
+		}
+		if mode.Perm()&0007 != 0 {
+			status.State = SocketUnsafe
+			status.Detail = fmt.Sprintf("directory %s is accessible to other users (mode %o)", filepath.Dir(path), mode.Perm())
+			return status
+		}
+	}
+
+	owner, mode, err := statOwner(path)
+	if err != nil {
+		status.State = SocketMissing
+		return status
+	}
+	if mode&os.ModeSocket == 0 {
+		status.State = SocketUnsafe
+		status.Detail = fmt.Sprintf("%s is not a socket", path)
+		return status
+	}
+	if owner != os.Getuid() {
+		status.State = SocketForeign
+		status.Detail = fmt.Sprintf("%s belongs to uid %d", path, owner)
+		return status
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			status.State = SocketStale
+			status.Detail = "no server is listening"
+			return status
+		}
+		status.State = SocketUnsafe
+		status.Detail = err.Error()
+		return status
+	}
+	conn.Close()
+	status.State = SocketLive
+	return status
+}
+
+// InstallHint says how to get tmux when it is missing
+const InstallHint = "install tmux (e.g. apt install tmux, brew install tmux)"
+
+// Err explains a socket tmux can't use, wrapping ErrTmuxUnavailable. Nil
+// for missing, live and stale sockets, which tmux starts or replaces.
+func (s SocketStatus) Err() error {
+	switch s.State {
+	case SocketForeign:
+		return errs.WithHint(fmt.Errorf("%w: tmux socket %q can't be used: %s", errs.ErrTmuxUnavailable, s.Name, s.Detail),
+			"set another tmux.socket_name or tmux.socket_template in $(gforge config path)")
+	case SocketUnsafe:
+		return errs.WithHint(fmt.Errorf("%w: tmux socket %q can't be used: %s", errs.ErrTmuxUnavailable, s.Name, s.Detail),
+			"gforge doctor --fix")
+	}
+	return nil
+}
+
+// Fix repairs what can be repaired without touching a live server or
+// another user's files: a stale socket is removed and a socket directory
+// open to other users is restricted to its owner. Reports whether it
+// changed anything.
+func (s SocketStatus) Fix() (bool, error) {
+	switch s.State {
+	case SocketStale:
+		if err := os.Remove(s.Path); err != nil {
+			return false, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+		return true, nil
+	case SocketUnsafe:
+		dir := filepath.Dir(s.Path)
+		owner, mode, err := statOwner(dir)
+		if err != nil || owner != os.Getuid() || mode.Perm()&0007 == 0 {
+			return false, nil
+		}
+		if err := os.Chmod(dir, 0700); err != nil {
+			return false, fmt.Errorf("failed to restrict %s: %w", dir, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// statOwner returns the owner and mode of a path, without following a
+// final symlink
+func statOwner(path string) (int, os.FileMode, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return os.Getuid(), info.Mode(), nil
+	}
+	return int(st.Uid), info.Mode(), nil
+}
@@ -0,0 +1,71 @@
+package tmux
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+func TestCheckSocket(t *testing.T) {
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	dir := filepath.Dir(SocketPath("x"))
+	os.MkdirAll(dir, 0700)
+
+	if s := CheckSocket("none"); s.State != SocketMissing || s.Err() != nil {
+		t.Errorf("Expected a missing socket, got %+v", s)
+	}
+
+	live, err := net.Listen("unix", SocketPath("live"))
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer live.Close()
+	if s := CheckSocket("live"); s.State != SocketLive {
+		t.Errorf("Expected a live socket, got %+v", s)
+	}
+
+	// A server that died without removing its socket
+	stale, _ := net.Listen("unix", SocketPath("stale"))
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	s := CheckSocket("stale")
+	if s.State != SocketStale || s.Err() != nil {
+		t.Fatalf("Expected a stale socket tmux can replace, got %+v", s)
+	}
+	if fixed, err := s.Fix(); !fixed || err != nil {
+		t.Errorf("Expected the stale socket removed, got %v, %v", fixed, err)
+	}
+	if s := CheckSocket("stale"); s.State != SocketMissing {
+		t.Errorf("Expected the socket gone, got %+v", s)
+	}
+
+	os.WriteFile(SocketPath("file"), nil, 0600)
+	if s := CheckSocket("file"); s.State != SocketUnsafe || !errors.Is(s.Err(), errs.ErrTmuxUnavailable) {
+		t.Errorf("Expected a plain file reported as unusable, got %+v", s)
+	}
+}
+
+func TestCheckSocketUnsafeDir(t *testing.T) {
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	dir := filepath.Dir(SocketPath("x"))
+	os.MkdirAll(dir, 0700)
+	os.Chmod(dir, 0777)
+
+	s := CheckSocket("gforge")
+	if s.State != SocketUnsafe || s.Err() == nil {
+		t.Fatalf("Expected a world-accessible directory reported, got %+v", s)
+	}
+	if fixed, err := s.Fix(); !fixed || err != nil {
+		t.Fatalf("Expected the directory restricted, got %v, %v", fixed, err)
+	}
+	if info, _ := os.Stat(dir); info.Mode().Perm() != 0700 {
+		t.Errorf("Expected mode 0700, got %o", info.Mode().Perm())
+	}
+	if s := CheckSocket("gforge"); s.State != SocketMissing {
+		t.Errorf("Expected the socket usable after the fix, got %+v", s)
+	}
+}
@@ -0,0 +1,163 @@
+// Package tokens estimates token counts without a model's vocabulary. The
+// counts are a heuristic modelled on cl100k-style BPE and can be off by a
+// fair margin for any given tokenizer, so budgets built on them should
+// leave headroom.
+package tokens
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// pieces splits text the way cl100k-style BPE tokenizers pre-tokenize it:
+// contractions, words with their leading space, runs of up to three
+// digits, punctuation runs and whitespace
+var pieces = regexp.MustCompile(`(?i:'s|'t|'re|'ve|'m|'ll|'d)| ?\p{L}+| ?\p{N}{1,3}| ?[^\s\p{L}\p{N}]+|\s+`)
+
+// pieceTokens estimates the BPE tokens in one pre-tokenized piece. Common
+// short words and digit groups are a single token; longer words split into
+// roughly four-character chunks, and symbols and non-ASCII text into
+// smaller ones.
+func pieceTokens(piece string) int {
+	word := strings.TrimPrefix(piece, " ")
+	if word == "" {
+		return 1
+	}
+
+	n := utf8.RuneCountInString(word)
+	switch {
+	case strings.TrimSpace(word) == "":
+		return 1
+	case len(word) != n:
+		// Multi-byte text: about one token per character
+		return n
+	case word[0] >= '0' && word[0] <= '9':
+		// Digits are pre-split into groups of three, one token each
+		return 1
+	case isLetters(word):
+		if n <= 6 {
+			return 1
+		}
+		return (n + 3) / 4
+	default:
+		return (n + 1) / 2
+	}
+}
+
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the estimated number of tokens in text
+func Count(text string) int {
+	total := 0
+	for _, p := range pieces.FindAllString(text, -1) {
+		total += pieceTokens(p)
+	}
+	return total
+}
+
+// span is a pre-tokenized piece with its byte offset
+type span struct {
+	end    int
+	tokens int
+}
+
+func spans(text string) []span {
+	var out []span
+	for _, loc := range pieces.FindAllStringIndex(text, -1) {
+		out = append(out, span{end: loc[1], tokens: pieceTokens(text[loc[0]:loc[1]])})
+	}
+	return out
+}
+
+// marker notes how much was cut. It stays on one line: prompts are typed
+// into agent sessions, where a newline would submit them early.
+func marker(dropped int) string {
+	return fmt.Sprintf(" [... %d tokens truncated ...] ", dropped)
+}
+
+// Truncate keeps the beginning of text within max tokens, noting how much
+// was cut. Text that fits is returned unchanged.
+func Truncate(text string, max int) string {
+	total := Count(text)
+	if total <= max {
+		return text
+	}
+
+	budget := max - Count(marker(total))
+	used, cut := 0, 0
+	for _, s := range spans(text) {
+		if used+s.tokens > budget {
+			break
+		}
+		used += s.tokens
+		cut = s.end
+	}
+	return text[:cut] + marker(total-used)
+}
+
+// TruncateTail keeps the end of text within max tokens, for logs and
+// transcripts where the latest output matters most
+func TruncateTail(text string, max int) string {
+	total := Count(text)
+	if total <= max {
+		return text
+	}
+
+	budget := max - Count(marker(total))
+	all := spans(text)
+	used, start := 0, len(text)
+	for i := len(all) - 1; i >= 0; i-- {
+		if used+all[i].tokens > budget {
+			break
+		}
+		used += all[i].tokens
+		start = 0
+		if i > 0 {
+			start = all[i-1].end
+		}
+	}
+	return marker(total-used) + text[start:]
+}
+
+// TruncateMiddle keeps the beginning and end of text within max tokens,
+// for prompts whose instructions lead and whose latest content trails
+func TruncateMiddle(text string, max int) string {
+	total := Count(text)
+	if total <= max {
+		return text
+	}
+
+	budget := max - Count(marker(total))
+	all := spans(text)
+
+	headBudget := budget / 2
+	headUsed, headEnd, i := 0, 0, 0
+	for ; i < len(all) && headUsed+all[i].tokens <= headBudget; i++ {
+		headUsed += all[i].tokens
+		headEnd = all[i].end
+	}
+
+	tailUsed, tailStart := 0, len(text)
+	for j := len(all) - 1; j >= i; j-- {
+		if headUsed+tailUsed+all[j].tokens > budget {
+			break
+		}
+		tailUsed += all[j].tokens
+		tailStart = 0
+		if j > 0 {
+			tailStart = all[j-1].end
+		}
+	}
+
+	return text[:headEnd] + marker(total-headUsed-tailUsed) + text[tailStart:]
+}
@@ -0,0 +1,84 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 1},
+		{"hello world", 2},
+		{"Hello, world!", 4},
+		{"12345", 2},
+		{"internationalization", 5},
+	}
+
+	for _, tc := range tests {
+		if got := Count(tc.text); got != tc.expected {
+			t.Errorf("Count(%q) = %d, want %d", tc.text, got, tc.expected)
+		}
+	}
+}
+
+func TestCountCode(t *testing.T) {
+	src := "func main() {\n\tfmt.Println(\"hello\")\n}\n"
+	got := Count(src)
+	// Real tokenizers put this at about 15 tokens
+	if got < 10 || got > 25 {
+		t.Errorf("Count of a small Go file = %d, expected 10-25", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	text := strings.Repeat("alpha beta gamma ", 200)
+
+	if got := Truncate("short text", 100); got != "short text" {
+		t.Errorf("Expected text within budget unchanged, got %q", got)
+	}
+
+	got := Truncate(text, 100)
+	if Count(got) > 100 {
+		t.Errorf("Truncate overran the budget: %d tokens", Count(got))
+	}
+	if !strings.HasPrefix(got, "alpha beta gamma") {
+		t.Errorf("Expected the beginning kept, got %q", got[:40])
+	}
+	if !strings.Contains(got, "tokens truncated") {
+		t.Error("Expected a truncation marker")
+	}
+	if strings.Contains(got, "\n") {
+		t.Error("Expected the marker kept on one line")
+	}
+}
+
+func TestTruncateTail(t *testing.T) {
+	text := "first line\n" + strings.Repeat("middle ", 500) + "\nlast line"
+
+	got := TruncateTail(text, 50)
+	if Count(got) > 50 {
+		t.Errorf("TruncateTail overran the budget: %d tokens", Count(got))
+	}
+	if !strings.HasSuffix(got, "last line") || strings.Contains(got, "first line") {
+		t.Errorf("Expected only the end kept, got %q", got)
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	text := "first line\n" + strings.Repeat("middle ", 500) + "\nlast line"
+
+	got := TruncateMiddle(text, 50)
+	if Count(got) > 50 {
+		t.Errorf("TruncateMiddle overran the budget: %d tokens", Count(got))
+	}
+	if !strings.HasPrefix(got, "first line") || !strings.HasSuffix(got, "last line") {
+		t.Errorf("Expected the beginning and end kept, got %q", got)
+	}
+	if !strings.Contains(got, "tokens truncated") {
+		t.Error("Expected a truncation marker")
+	}
+}
@@ -0,0 +1,67 @@
+package trace
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// Backoff bounds the retries of a command that failed for a passing
+// reason: Attempts runs in all, the first retry after Delay and each one
+// after that waiting twice as long as the last, up to MaxDelay
+type Backoff struct {
+	Attempts int
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+// DefaultBackoff rides out another git process holding a lock or a tmux
+// server starting up, giving up after a few seconds
+var DefaultBackoff = Backoff{Attempts: 5, Delay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+// transientErrors are the output of git and tmux failures that can pass
+var transientErrors = []string{
+	".lock': File exists", // index.lock, a ref or the config held by another git
+	"Another git process seems to be running",
+	"cannot lock ref",
+	"server exited unexpectedly", // tmux server still starting or going away
+	"(Connection refused)",       // its socket there but not yet accepting; a missing one means no server
+	"lost server",
+}
+
+// Transient reports whether a failed command's output says running it
+// again may succeed
+func Transient(output string) bool {
+	for _, s := range transientErrors {
+		if strings.Contains(output, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry runs the command build makes and returns its combined output,
+// running a fresh one after each transient failure until b.Attempts have
+// been made. Retries are logged to log when it isn't nil; every attempt
+// is traced like any other command.
+func Retry(b Backoff, log *logging.Logger, build func() *Cmd) ([]byte, error) {
+	wait := b.Delay
+	for attempt := 1; ; attempt++ {
+		cmd := build()
+		output, err := cmd.CombinedOutput()
+		if err == nil || attempt >= b.Attempts || !Transient(string(output)) {
+			return output, err
+		}
+
+		if log != nil {
+			log.Warn("Retrying command",
+				logging.String("command", cmd.String()),
+				logging.String("output", strings.TrimSpace(string(output))),
+				logging.Int("attempt", attempt+1),
+				logging.Duration("wait", wait))
+		}
+		time.Sleep(wait)
+		wait = min(wait*2, b.MaxDelay)
+	}
+}
@@ -0,0 +1,70 @@
+package trace
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// lockedScript fails as git does with index.lock held until it has run
+// as many times as its argument says
+const lockedScript = `n=$(cat "$1" 2>/dev/null || echo 0); n=$((n+1)); echo $n > "$1"
+if [ $n -lt $2 ]; then echo "fatal: Unable to create '.git/index.lock': File exists." >&2; exit 128; fi
+echo done`
+
+func TestRetry(t *testing.T) {
+	b := Backoff{Attempts: 3, Delay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	count := filepath.Join(t.TempDir(), "count")
+	var logged bytes.Buffer
+
+	output, err := Retry(b, logging.NewWithWriter(&logged, false), func() *Cmd {
+		return Command("sh", "-c", lockedScript, "sh", count, "3")
+	})
+	if err != nil || strings.TrimSpace(string(output)) != "done" {
+		t.Fatalf("Expected the third attempt to succeed, got %q, %v", output, err)
+	}
+	if n := strings.Count(logged.String(), "Retrying command"); n != 2 {
+		t.Errorf("Expected 2 retries logged, got %d:\n%s", n, logged.String())
+	}
+
+	// Attempts bounds the runs
+	os.Remove(count)
+	if _, err := Retry(b, nil, func() *Cmd {
+		return Command("sh", "-c", lockedScript, "sh", count, "5")
+	}); err == nil {
+		t.Error("Expected the lock still held after 3 attempts")
+	}
+	if data, _ := os.ReadFile(count); strings.TrimSpace(string(data)) != "3" {
+		t.Errorf("Expected 3 runs, got %s", data)
+	}
+
+	// Other failures aren't retried
+	os.Remove(count)
+	Retry(b, nil, func() *Cmd {
+		return Command("sh", "-c", `echo x >> "$1"; echo "fatal: not a git repository" >&2; exit 128`, "sh", count)
+	})
+	if data, _ := os.ReadFile(count); strings.Count(string(data), "x") != 1 {
+		t.Errorf("Expected one run for a lasting failure, got %q", data)
+	}
+}
+
+func TestTransient(t *testing.T) {
+	for output, want := range map[string]bool{
+		"fatal: Unable to create '/repo/.git/index.lock': File exists.":         true,
+		"error: cannot lock ref 'refs/heads/gforge/x'":                          true,
+		"server exited unexpectedly":                                            true,
+		"error connecting to /tmp/tmux-1000/gforge (Connection refused)":        true,
+		"error connecting to /tmp/tmux-1000/gforge (No such file or directory)": false,
+		"can't find session: gforge-x":                                          false,
+		"fatal: invalid reference: main":                                        false,
+	} {
+		if got := Transient(output); got != want {
+			t.Errorf("Transient(%q) = %v, want %v", output, got, want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package trace runs the external commands gforge orchestrates (git, tmux,
+// gh) so they can be watched: -vv echoes each one with its timing, and
+// GFORGE_TRACE records their raw output to a file. Retry runs a command
+// again when it fails for a passing reason, such as a held git lock.
+package trace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvVar turns on the trace file: "1" writes to the default path, any
+// other value (except "0") names the file
+const EnvVar = "GFORGE_TRACE"
+
+var (
+	mu      sync.Mutex
+	echo    io.Writer // Where commands are echoed, nil when off
+	file    string    // Trace file, empty when off
+	opened  *os.File
+	openErr error
+)
+
+// Configure sets where commands are echoed (nil for nowhere) and the trace
+// file (empty for none)
+func Configure(echoTo io.Writer, traceFile string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if opened != nil {
+		opened.Close()
+	}
+	echo, file, opened, openErr = echoTo, traceFile, nil, nil
+}
+
+// FileFromEnv returns the trace file GFORGE_TRACE asks for, with
+// defaultPath standing in for "1"
+func FileFromEnv(defaultPath string) string {
+	switch v := os.Getenv(EnvVar); v {
+	case "", "0", "false":
+		return ""
+	case "1", "true":
+		return defaultPath
+	default:
+		return v
+	}
+}
+
+// Cmd is an exec.Cmd that reports itself when it finishes
+type Cmd struct {
+	*exec.Cmd
+
+	start          time.Time
+	stdout, stderr *bytes.Buffer // Copies kept for the trace file
+}
+
+// Command is exec.Command, traced
+func Command(name string, args ...string) *Cmd {
+	return &Cmd{Cmd: exec.Command(name, args...)}
+}
+
+// CommandContext is exec.CommandContext, traced
+func CommandContext(ctx context.Context, name string, args ...string) *Cmd {
+	return &Cmd{Cmd: exec.CommandContext(ctx, name, args...)}
+}
+
+// Run starts the command and waits for it
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Start starts the command without waiting for it
+func (c *Cmd) Start() error {
+	c.start = time.Now()
+	if recording() {
+		c.stdout, c.stderr = &bytes.Buffer{}, &bytes.Buffer{}
+		c.Stdout = tee(c.Stdout, c.stdout)
+		c.Stderr = tee(c.Stderr, c.stderr)
+	}
+	err := c.Cmd.Start()
+	if err != nil {
+		c.finish(nil, nil, err)
+	}
+	return err
+}
+
+// Wait waits for a started command to exit
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	c.finish(c.stdout, c.stderr, err)
+	return err
+}
+
+// Output runs the command and returns its stdout. Like exec.Cmd.Output,
+// a failure's *exec.ExitError carries stderr.
+func (c *Cmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	captureStderr := c.Stderr == nil
+	if captureStderr {
+		c.Stderr = &stderr
+	}
+
+	c.start = time.Now()
+	err := c.Cmd.Run()
+	var exitErr *exec.ExitError
+	if captureStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	c.finish(&stdout, &stderr, err)
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its stdout and stderr
+// together
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil || c.Stderr != nil {
+		return nil, errors.New("exec: Stdout or Stderr already set")
+	}
+	var out bytes.Buffer
+	c.Stdout = &out
+	c.Stderr = &out
+
+	c.start = time.Now()
+	err := c.Cmd.Run()
+	c.finish(&out, nil, err)
+	return out.Bytes(), err
+}
+
+// String renders the command line as a shell would take it
+func (c *Cmd) String() string {
+	words := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		words[i] = quote(a)
+	}
+	return strings.Join(words, " ")
+}
+
+// finish echoes the finished command and records its output
+func (c *Cmd) finish(stdout, stderr *bytes.Buffer, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if echo == nil && file == "" {
+		return
+	}
+
+	elapsed := time.Since(c.start).Round(time.Millisecond)
+	result := fmt.Sprintf("%s, %s", elapsed, exitStatus(err))
+	if echo != nil {
+		fmt.Fprintf(echo, "+ %s (%s)\n", c, result)
+	}
+
+	f := traceFile()
+	if f == nil {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "=== %s %s\n", c.start.Format(time.RFC3339Nano), c)
+	if c.Dir != "" {
+		fmt.Fprintf(&b, "dir: %s\n", c.Dir)
+	}
+	fmt.Fprintf(&b, "result: %s\n", result)
+	section(&b, "stdout", stdout)
+	section(&b, "stderr", stderr)
+	f.WriteString(b.String())
+}
+
+// recording reports whether commands' output is being kept
+func recording() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return file != ""
+}
+
+// traceFile opens the trace file on first use. Callers hold mu.
+func traceFile() *os.File {
+	if opened != nil || openErr != nil || file == "" {
+		return opened
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		openErr = err
+	} else {
+		opened, openErr = os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	}
+	if openErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to open trace file %s: %v\n", file, openErr)
+	}
+	return opened
+}
+
+// tee adds copy to a command's output, unless the output is a file such
+// as the terminal, which must reach the command directly
+func tee(w io.Writer, copy *bytes.Buffer) io.Writer {
+	switch w.(type) {
+	case nil:
+		return copy
+	case *os.File:
+		return w
+	}
+	return io.MultiWriter(w, copy)
+}
+
+func section(b *strings.Builder, name string, out *bytes.Buffer) {
+	if out == nil || out.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(b, "--- %s\n%s", name, out.String())
+	if !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		b.WriteString("\n")
+	}
+}
+
+func exitStatus(err error) string {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return "exit 0"
+	case errors.As(err, &exitErr):
+		return fmt.Sprintf("exit %d", exitErr.ExitCode())
+	}
+	return err.Error()
+}
+
+// quote leaves plain words alone and quotes the rest
+func quote(s string) string {
+	if s == "" {
+		return `""`
+	}
+	if strings.ContainsAny(s, " \t\n\"'\\$`*?;&|<>(){}[]#~") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+package trace
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEcho(t *testing.T) {
+	var echo bytes.Buffer
+	Configure(&echo, "")
+	defer Configure(nil, "")
+
+	out, err := Command("sh", "-c", "echo hi").Output()
+	if err != nil || string(out) != "hi\n" {
+		t.Fatalf("Output() = %q, %v", out, err)
+	}
+	Command("sh", "-c", "exit 3").Run()
+
+	lines := strings.Split(strings.TrimSpace(echo.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 echoed commands, got %q", echo.String())
+	}
+	if !strings.HasPrefix(lines[0], `+ sh -c "echo hi" (`) || !strings.HasSuffix(lines[0], ", exit 0)") {
+		t.Errorf("Unexpected echo: %s", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ", exit 3)") {
+		t.Errorf("Expected the exit status echoed, got %s", lines[1])
+	}
+}
+
+func TestTraceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace", "trace.log")
+	Configure(nil, path)
+	defer Configure(nil, "")
+
+	_, err := Command("sh", "-c", "echo out; echo oops >&2; exit 1").Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || string(exitErr.Stderr) != "oops\n" {
+		t.Fatalf("Expected stderr kept on the exit error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd := Command("sh", "-c", "echo ran")
+	cmd.Stdout = &buf
+	if err := cmd.Run(); err != nil || buf.String() != "ran\n" {
+		t.Fatalf("Run() wrote %q, %v", buf.String(), err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read trace: %v", err)
+	}
+	trace := string(data)
+	for _, want := range []string{"result: ", "exit 1", "--- stdout\nout\n", "--- stderr\noops\n", "--- stdout\nran\n"} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("Expected %q in trace:\n%s", want, trace)
+		}
+	}
+}
+
+func TestOff(t *testing.T) {
+	Configure(nil, "")
+	out, err := Command("sh", "-c", "echo quiet").CombinedOutput()
+	if err != nil || string(out) != "quiet\n" {
+		t.Errorf("CombinedOutput() = %q, %v", out, err)
+	}
+}
+
+func TestFileFromEnv(t *testing.T) {
+	cases := map[string]string{
+		"":               "",
+		"0":              "",
+		"1":              "/data/trace.log",
+		"/tmp/trace.out": "/tmp/trace.out",
+	}
+	for value, want := range cases {
+		t.Setenv(EnvVar, value)
+		if got := FileFromEnv("/data/trace.log"); got != want {
+			t.Errorf("FileFromEnv with %s=%q = %q, want %q", EnvVar, value, got, want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	cmd := Command("git", "commit", "-m", "fix it", "")
+	if got, want := cmd.String(), `git commit -m "fix it" ""`; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
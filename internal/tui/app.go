@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/style"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -24,6 +25,15 @@ const (
 	ViewSpawn
 )
 
+// PromptType is the question the footer is asking, if any
+type PromptType int
+
+const (
+	PromptNone PromptType = iota
+	PromptTask            // Typing a task for the selected goblin
+	PromptKill            // Confirming a kill
+)
+
 // App is the main TUI application
 type App struct {
 	coordinator *coordinator.Coordinator
@@ -37,6 +47,12 @@ type App struct {
 	height        int
 	voiceEnabled  bool
 	err           error
+	notice        string
+
+	// Prompt in the footer and the goblin it is about
+	prompt PromptType
+	target *coordinator.Goblin
+	input  string
 
 	// Timing
 	lastUpdate time.Time
@@ -75,10 +91,17 @@ func (a *App) refreshGoblins() tea.Cmd {
 		if a.coordinator == nil {
 			return goblinListMsg{goblins: []*coordinator.Goblin{}}
 		}
-		goblins, err := a.coordinator.List()
+		all, err := a.coordinator.List()
 		if err != nil {
 			return errMsg{err}
 		}
+		// Archived goblins have no session to show
+		goblins := make([]*coordinator.Goblin, 0, len(all))
+		for _, g := range all {
+			if g.Status != coordinator.StatusArchived {
+				goblins = append(goblins, g)
+			}
+		}
 		return goblinListMsg{goblins: goblins}
 	}
 }
@@ -92,8 +115,10 @@ type errMsg struct {
 	err error
 }
 type outputMsg struct {
+	id    string
 	lines []string
 }
+type noticeMsg string
 
 // Update implements tea.Model
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -108,7 +133,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		a.lastUpdate = time.Time(msg)
-		return a, tea.Batch(a.tickCmd(), a.refreshGoblins())
+		return a, tea.Batch(a.tickCmd(), a.refreshGoblins(), a.refreshOutput())
 
 	case goblinListMsg:
 		a.goblins = msg.goblins
@@ -120,10 +145,19 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case errMsg:
 		a.err = msg.err
+		a.notice = ""
+		return a, nil
+
+	case noticeMsg:
+		a.notice = string(msg)
+		a.err = nil
 		return a, nil
 
 	case outputMsg:
-		a.output = msg.lines
+		// Output captured before the selection moved is dropped
+		if g := a.selected(); g != nil && g.ID == msg.id {
+			a.output = msg.lines
+		}
 		return a, nil
 	}
 
@@ -138,6 +172,11 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.activeView = ViewDashboard
 		return a, nil
 	}
+	if a.prompt != PromptNone {
+		return a.handlePromptKey(msg)
+	}
+	a.err = nil
+	a.notice = ""
 
 	switch msg.String() {
 	case "q", "ctrl+c":
@@ -150,14 +189,16 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "j", "down":
 		if len(a.goblins) > 0 {
 			a.selectedIndex = (a.selectedIndex + 1) % len(a.goblins)
+			a.output = nil
 		}
-		return a, nil
+		return a, a.refreshOutput()
 
 	case "k", "up":
 		if len(a.goblins) > 0 {
 			a.selectedIndex = (a.selectedIndex - 1 + len(a.goblins)) % len(a.goblins)
+			a.output = nil
 		}
-		return a, nil
+		return a, a.refreshOutput()
 
 	case "a", "enter":
 		return a, a.attachToSelected()
@@ -165,12 +206,20 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "s":
 		return a, a.stopSelected()
 
-	case "K": // Shift+K for kill
-		return a, a.killSelected()
+	case "K": // Shift+K for kill, once confirmed
+		a.startPrompt(PromptKill)
+		return a, nil
+
+	case "t":
+		a.startPrompt(PromptTask)
+		return a, nil
 
 	case "p":
 		return a, a.pauseSelected()
 
+	case "P": // Shift+P to pin or unpin
+		return a, a.togglePinSelected()
+
 	case "r":
 		return a, a.refreshGoblins()
 
@@ -181,19 +230,103 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
-// attachToSelected attaches to the selected goblin's tmux session
+// startPrompt asks about the selected goblin in the footer
+func (a *App) startPrompt(prompt PromptType) {
+	a.target = a.selected()
+	if a.target == nil {
+		return
+	}
+	a.prompt = prompt
+	a.input = ""
+	a.err = nil
+	a.notice = ""
+}
+
+// handlePromptKey takes a key while the footer asks something: a task
+// being typed, or y to confirm a kill
+func (a *App) handlePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt, target := a.prompt, a.target
+
+	if prompt == PromptKill {
+		a.prompt = PromptNone
+		if msg.String() == "y" || msg.String() == "Y" {
+			return a, a.kill(target)
+		}
+		return a, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		a.prompt = PromptNone
+		if task := strings.TrimSpace(a.input); task != "" {
+			return a, a.sendTask(target, task)
+		}
+	case tea.KeyEsc, tea.KeyCtrlC:
+		a.prompt = PromptNone
+	case tea.KeyBackspace:
+		if r := []rune(a.input); len(r) > 0 {
+			a.input = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		a.input += " "
+	case tea.KeyRunes:
+		a.input += string(msg.Runes)
+	}
+	return a, nil
+}
+
+// selected returns the selected goblin, or nil when there is none
+func (a *App) selected() *coordinator.Goblin {
+	if a.selectedIndex < 0 || a.selectedIndex >= len(a.goblins) {
+		return nil
+	}
+	return a.goblins[a.selectedIndex]
+}
+
+// refreshOutput captures the selected goblin's recent output to fill the
+// output panel
+func (a *App) refreshOutput() tea.Cmd {
+	goblin := a.selected()
+	if goblin == nil || a.coordinator == nil {
+		return nil
+	}
+	lines := max(a.height-8, 10)
+
+	return func() tea.Msg {
+		// A goblin without a session has no output to show
+		output, _ := a.coordinator.Output(goblin, lines)
+		return outputMsg{id: goblin.ID, lines: output}
+	}
+}
+
+// attachToSelected attaches to the selected goblin's tmux session the way
+// tmux.attach_mode says
 func (a *App) attachToSelected() tea.Cmd {
-	if len(a.goblins) == 0 || a.coordinator == nil {
+	goblin := a.selected()
+	if goblin == nil || a.coordinator == nil {
 		return nil
 	}
-	goblin := a.goblins[a.selectedIndex]
 
-	return tea.ExecProcess(
-		tea.Command("tmux", "-L", "gforge", "attach-session", "-t", goblin.TmuxSession),
-		func(err error) tea.Msg {
-			return a.refreshGoblins()()
-		},
-	)
+	cmd, err := a.coordinator.AttachCommand(goblin, "")
+	if err != nil {
+		return func() tea.Msg { return errMsg{err} }
+	}
+	return tea.ExecProcess(cmd.Cmd, func(err error) tea.Msg {
+		return a.refreshGoblins()()
+	})
+}
+
+// sendTask sends a task to a goblin
+func (a *App) sendTask(goblin *coordinator.Goblin, task string) tea.Cmd {
+	if a.coordinator == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := a.coordinator.SendTask(goblin.ID, task); err != nil {
+			return errMsg{err}
+		}
+		return noticeMsg("Task sent to " + goblin.Name)
+	}
 }
 
 // stopSelected stops the selected goblin
@@ -212,16 +345,28 @@ func (a *App) stopSelected() tea.Cmd {
 	}
 }
 
-// killSelected kills the selected goblin
-func (a *App) killSelected() tea.Cmd {
+// kill kills a goblin and removes its worktree
+func (a *App) kill(goblin *coordinator.Goblin) tea.Cmd {
+	if a.coordinator == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := a.coordinator.Kill(goblin.ID); err != nil {
+			return errMsg{err}
+		}
+		return noticeMsg("Killed " + goblin.Name)
+	}
+}
+
+// togglePinSelected pins the selected goblin, or unpins it
+func (a *App) togglePinSelected() tea.Cmd {
 	if len(a.goblins) == 0 || a.coordinator == nil {
 		return nil
 	}
 	goblin := a.goblins[a.selectedIndex]
 
 	return func() tea.Msg {
-		err := a.coordinator.Kill(goblin.Name)
-		if err != nil {
+		if _, err := a.coordinator.Pin(goblin.ID, !goblin.Pinned); err != nil {
 			return errMsg{err}
 		}
 		return a.refreshGoblins()()
@@ -281,17 +426,17 @@ func (a *App) renderDashboard() string {
 func (a *App) renderHeader() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(style.Current().Accent).
 		Padding(0, 1)
 
 	versionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666"))
+		Foreground(style.Current().Subtle)
 
 	voiceStatus := "OFF"
-	voiceColor := lipgloss.Color("#666666")
+	voiceColor := style.Current().Subtle
 	if a.voiceEnabled {
 		voiceStatus = "ON"
-		voiceColor = lipgloss.Color("#04B575")
+		voiceColor = style.Current().Success
 	}
 
 	voiceStyle := lipgloss.NewStyle().
@@ -300,7 +445,7 @@ func (a *App) renderHeader() string {
 	title := titleStyle.Render("GOBLIN FORGE")
 	version := versionStyle.Render(fmt.Sprintf("v%s", AppVersion))
 	voice := voiceStyle.Render(fmt.Sprintf("Voice: %s", voiceStatus))
-	quit := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render("q: quit")
+	quit := lipgloss.NewStyle().Foreground(style.Current().Subtle).Render("q: quit")
 
 	// Calculate spacing
 	leftPart := title + " " + version
@@ -313,7 +458,7 @@ func (a *App) renderHeader() string {
 	headerStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderBottom(true).
-		BorderForeground(lipgloss.Color("#333333")).
+		BorderForeground(style.Current().Border).
 		Width(a.width)
 
 	return headerStyle.Render(headerContent)
@@ -339,7 +484,7 @@ func (a *App) renderBody(height int) string {
 func (a *App) renderGoblinList(width, height int) string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
+		Foreground(style.Current().Text).
 		MarginBottom(1)
 
 	title := titleStyle.Render(fmt.Sprintf("GOBLINS (%d)", len(a.goblins)))
@@ -350,11 +495,11 @@ func (a *App) renderGoblinList(width, height int) string {
 
 	if len(a.goblins) == 0 {
 		emptyStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
+			Foreground(style.Current().Subtle).
 			Italic(true)
 		lines = append(lines, emptyStyle.Render("No active goblins"))
 		lines = append(lines, "")
-		lines = append(lines, emptyStyle.Render("Press 'n' to spawn one"))
+		lines = append(lines, emptyStyle.Render("Spawn one with gforge spawn"))
 	} else {
 		for i, g := range a.goblins {
 			line := a.renderGoblinLine(i, g, width-4)
@@ -369,7 +514,7 @@ func (a *App) renderGoblinList(width, height int) string {
 		Height(height).
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderRight(true).
-		BorderForeground(lipgloss.Color("#333333")).
+		BorderForeground(style.Current().Border).
 		Padding(0, 1)
 
 	return panelStyle.Render(content)
@@ -381,20 +526,17 @@ func (a *App) renderGoblinLine(index int, g *coordinator.Goblin, width int) stri
 
 	// Status indicator
 	var statusIcon string
-	var statusColor lipgloss.Color
 	switch g.Status {
 	case "running":
 		statusIcon = "▶"
-		statusColor = lipgloss.Color("#04B575")
 	case "paused":
 		statusIcon = "⏸"
-		statusColor = lipgloss.Color("#FFCC00")
 	case "stopped":
 		statusIcon = "■"
-		statusColor = lipgloss.Color("#666666")
+	case "completed":
+		statusIcon = "✓"
 	default:
 		statusIcon = "○"
-		statusColor = lipgloss.Color("#666666")
 	}
 
 	// Build line
@@ -405,26 +547,37 @@ func (a *App) renderGoblinLine(index int, g *coordinator.Goblin, width int) stri
 
 	nameStyle := lipgloss.NewStyle()
 	if isSelected {
-		nameStyle = nameStyle.Bold(true).Foreground(lipgloss.Color("#FAFAFA"))
+		nameStyle = nameStyle.Bold(true).Foreground(style.Current().Text)
 	} else {
-		nameStyle = nameStyle.Foreground(lipgloss.Color("#AAAAAA"))
+		nameStyle = nameStyle.Foreground(style.Current().Normal)
 	}
 
 	agentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4"))
+		Foreground(style.Current().Accent)
 
 	statusStyle := lipgloss.NewStyle().
-		Foreground(statusColor)
+		Foreground(style.Current().StatusColor(g.Status))
 
 	ageStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666"))
+		Foreground(style.Current().Subtle)
+
+	branchStyle := lipgloss.NewStyle().
+		Foreground(style.Current().Info)
 
 	name := nameStyle.Render(truncate(g.Name, 12))
+	if g.Pinned {
+		name = lipgloss.NewStyle().Foreground(style.Current().Warning).Render("*") + name
+	}
 	agent := agentStyle.Render(fmt.Sprintf("[%s]", truncate(g.Agent, 8)))
 	status := statusStyle.Render(statusIcon)
 	age := ageStyle.Render(g.Age())
+	line := fmt.Sprintf("%s%d. %s %s %s %s", prefix, index+1, name, agent, status, age)
 
-	return fmt.Sprintf("%s%d. %s %s %s %s", prefix, index+1, name, agent, status, age)
+	// The branch goes last, in whatever room is left
+	if room := width - lipgloss.Width(line) - 1; g.Branch != "" && room > 3 {
+		line += " " + branchStyle.Render(truncate(g.Branch, room))
+	}
+	return line
 }
 
 // renderOutputPanel renders the output panel
@@ -438,7 +591,7 @@ func (a *App) renderOutputPanel(width, height int) string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
+		Foreground(style.Current().Text).
 		MarginBottom(1)
 
 	title := "OUTPUT"
@@ -452,13 +605,19 @@ func (a *App) renderOutputPanel(width, height int) string {
 
 	if len(a.output) == 0 {
 		emptyStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
+			Foreground(style.Current().Subtle).
 			Italic(true)
 		lines = append(lines, emptyStyle.Render("No output yet"))
 		lines = append(lines, "")
 		lines = append(lines, emptyStyle.Render("Select a goblin and press 'a' to attach"))
 	} else {
-		for _, line := range a.output {
+		// The most recent lines that fit under the title, its margin and
+		// the rule
+		output := a.output
+		if room := height - 3; len(output) > room {
+			output = output[len(output)-max(room, 0):]
+		}
+		for _, line := range output {
 			lines = append(lines, truncate(line, width-4))
 		}
 	}
@@ -476,19 +635,42 @@ func (a *App) renderOutputPanel(width, height int) string {
 // renderFooter renders the bottom keybinding bar
 func (a *App) renderFooter() string {
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(style.Current().Accent).
 		Bold(true)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666"))
+		Foreground(style.Current().Subtle)
+
+	footerStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderTop(true).
+		BorderForeground(style.Current().Border).
+		Width(a.width).
+		Padding(0, 1)
+
+	// A prompt, error or notice takes the place of the keybindings
+	switch {
+	case a.prompt == PromptTask:
+		return footerStyle.Render(keyStyle.Render("Task for "+a.target.Name+": ") + a.input + "█  " +
+			descStyle.Render("enter: send  esc: cancel"))
+	case a.prompt == PromptKill:
+		return footerStyle.Render(lipgloss.NewStyle().Foreground(style.Current().Danger).Bold(true).
+			Render("Kill "+a.target.Name+" and remove its worktree?") + " " + descStyle.Render("y: kill  any other key: cancel"))
+	case a.err != nil:
+		return footerStyle.Render(lipgloss.NewStyle().Foreground(style.Current().Danger).Render("Error: " + a.err.Error()))
+	case a.notice != "":
+		return footerStyle.Render(lipgloss.NewStyle().Foreground(style.Current().Success).Render(a.notice))
+	}
 
 	bindings := []struct {
 		key  string
 		desc string
 	}{
 		{"a", "attach"},
+		{"t", "task"},
 		{"s", "stop"},
 		{"K", "kill"},
+		{"P", "pin"},
 		{"d", "diff"},
 		{"r", "refresh"},
 		{"?", "help"},
@@ -499,36 +681,27 @@ func (a *App) renderFooter() string {
 		parts = append(parts, keyStyle.Render(b.key)+":"+descStyle.Render(b.desc))
 	}
 
-	content := strings.Join(parts, "  ")
-
-	footerStyle := lipgloss.NewStyle().
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderTop(true).
-		BorderForeground(lipgloss.Color("#333333")).
-		Width(a.width).
-		Padding(0, 1)
-
-	return footerStyle.Render(content)
+	return footerStyle.Render(strings.Join(parts, "  "))
 }
 
 // renderHelp renders the help view
 func (a *App) renderHelp() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(style.Current().Accent).
 		MarginBottom(2)
 
 	sectionStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
+		Foreground(style.Current().Text).
 		MarginTop(1)
 
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(style.Current().Accent).
 		Width(15)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#AAAAAA"))
+		Foreground(style.Current().Normal)
 
 	var lines []string
 	lines = append(lines, titleStyle.Render("GOBLIN FORGE - KEYBINDINGS"))
@@ -541,9 +714,11 @@ func (a *App) renderHelp() string {
 	lines = append(lines, "")
 
 	lines = append(lines, sectionStyle.Render("Actions"))
+	lines = append(lines, keyStyle.Render("t")+"  "+descStyle.Render("Send a task to selected goblin"))
 	lines = append(lines, keyStyle.Render("s")+"  "+descStyle.Render("Stop selected goblin"))
-	lines = append(lines, keyStyle.Render("K (Shift+k)")+"  "+descStyle.Render("Kill selected goblin"))
+	lines = append(lines, keyStyle.Render("K (Shift+k)")+"  "+descStyle.Render("Kill selected goblin, once confirmed"))
 	lines = append(lines, keyStyle.Render("p")+"  "+descStyle.Render("Pause selected goblin"))
+	lines = append(lines, keyStyle.Render("P (Shift+p)")+"  "+descStyle.Render("Pin or unpin selected goblin"))
 	lines = append(lines, keyStyle.Render("d")+"  "+descStyle.Render("Show diff for selected goblin"))
 	lines = append(lines, keyStyle.Render("r")+"  "+descStyle.Render("Refresh goblin list"))
 	lines = append(lines, "")
@@ -555,7 +730,7 @@ func (a *App) renderHelp() string {
 	lines = append(lines, "")
 
 	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
+		Foreground(style.Current().Subtle).
 		Italic(true)
 	lines = append(lines, footerStyle.Render("Press any key to return to dashboard"))
 
@@ -563,7 +738,7 @@ func (a *App) renderHelp() string {
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
+		BorderForeground(style.Current().Accent).
 		Padding(2, 4).
 		Width(60)
 
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -175,6 +177,111 @@ func TestKeyNavigation(t *testing.T) {
 	}
 }
 
+func TestTaskPrompt(t *testing.T) {
+	app := New(nil)
+	app.width = 100
+	app.goblins = []*coordinator.Goblin{{ID: "1", Name: "coder"}, {ID: "2", Name: "tester"}}
+	app.selectedIndex = 1
+
+	press := func(msg tea.KeyMsg) {
+		model, _ := app.Update(msg)
+		app = model.(*App)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if app.prompt != PromptTask || app.target.Name != "tester" {
+		t.Fatalf("Expected a task prompt for tester, got %v for %v", app.prompt, app.target)
+	}
+
+	// Keys are typed into the task, not taken as commands
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("run")})
+	press(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("testsq")})
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	if app.input != "run tests" {
+		t.Errorf("Expected 'run tests' typed, got %q", app.input)
+	}
+	if !strings.Contains(app.renderFooter(), "Task for tester: run tests") {
+		t.Errorf("Expected the prompt in the footer, got %q", app.renderFooter())
+	}
+
+	// The selection moving doesn't change whom the task is for
+	app.selectedIndex = 0
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.prompt != PromptNone || app.target.Name != "tester" {
+		t.Errorf("Expected the prompt closed after sending to tester, got %v for %v", app.prompt, app.target)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.prompt != PromptNone {
+		t.Error("Expected esc to cancel the task")
+	}
+}
+
+func TestKillPrompt(t *testing.T) {
+	app := New(nil)
+	app.width = 100
+	app.goblins = []*coordinator.Goblin{{ID: "1", Name: "coder"}}
+
+	model, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	app = model.(*App)
+	if app.prompt != PromptKill {
+		t.Fatal("Expected K to ask before killing")
+	}
+	if !strings.Contains(app.renderFooter(), "Kill coder") {
+		t.Errorf("Expected the confirmation in the footer, got %q", app.renderFooter())
+	}
+
+	// Anything but y cancels
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	app = model.(*App)
+	if app.prompt != PromptNone {
+		t.Error("Expected n to cancel the kill")
+	}
+
+	// No goblin, no prompt
+	app.goblins = nil
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	if model.(*App).prompt != PromptNone {
+		t.Error("Expected no prompt without a goblin")
+	}
+}
+
+func TestOutputMsg(t *testing.T) {
+	app := New(nil)
+	app.goblins = []*coordinator.Goblin{{ID: "1", Name: "coder"}, {ID: "2", Name: "tester"}}
+
+	model, _ := app.Update(outputMsg{id: "1", lines: []string{"$ go test", "ok"}})
+	app = model.(*App)
+	if len(app.output) != 2 {
+		t.Errorf("Expected the selected goblin's output shown, got %v", app.output)
+	}
+
+	// Output for a goblin no longer selected is dropped
+	model, _ = app.Update(outputMsg{id: "2", lines: []string{"other"}})
+	if got := model.(*App).output; len(got) != 2 {
+		t.Errorf("Expected stale output dropped, got %v", got)
+	}
+}
+
+func TestNoticeMsg(t *testing.T) {
+	app := New(nil)
+	app.width = 100
+	app.err = errTest
+
+	model, _ := app.Update(noticeMsg("Task sent to coder"))
+	app = model.(*App)
+	if app.err != nil || !strings.Contains(app.renderFooter(), "Task sent to coder") {
+		t.Errorf("Expected the notice in place of the error, got %q", app.renderFooter())
+	}
+
+	// The next key clears it
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if model.(*App).notice != "" {
+		t.Error("Expected a key press to clear the notice")
+	}
+}
+
 func TestKeyNavigationEmpty(t *testing.T) {
 	app := New(nil)
 	app.goblins = []*coordinator.Goblin{}
@@ -252,6 +359,11 @@ func TestRenderGoblinList(t *testing.T) {
 	if list == "" {
 		t.Error("Goblin list should not be empty")
 	}
+
+	app.goblins[0].Branch = "gforge/coder"
+	if line := app.renderGoblinLine(0, app.goblins[0], 60); !strings.Contains(line, "gforge/coder") {
+		t.Errorf("Expected the branch in the goblin line, got %q", line)
+	}
 }
 
 func TestRenderGoblinListEmpty(t *testing.T) {
@@ -324,6 +436,8 @@ func TestMax(t *testing.T) {
 	}
 }
 
+var errTest = errors.New("test error")
+
 func TestErrMsg(t *testing.T) {
 	app := New(nil)
 
@@ -0,0 +1,134 @@
+// Package usage prices agent token usage and aggregates it into reports.
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+	"github.com/astoreyai/goblin-forge/internal/table"
+)
+
+// DefaultPrices are list prices by provider, in USD per million tokens.
+// Override them (or price single agents) with pricing in config.
+var DefaultPrices = map[string]config.PriceConfig{
+	"anthropic": {Input: 3, Output: 15},
+	"openai":    {Input: 1.25, Output: 10},
+	"google":    {Input: 1.25, Output: 10},
+	"ollama":    {Input: 0, Output: 0},
+}
+
+// Cost prices a request at p
+func Cost(p config.PriceConfig, inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*p.Input + float64(outputTokens)*p.Output) / 1e6
+}
+
+// Groupings for reports
+const (
+	ByDay     = "day"
+	ByAgent   = "agent"
+	ByProject = "project"
+	ByGoblin  = "goblin"
+)
+
+// Row is the usage of one group
+type Row struct {
+	Key          string  `json:"key"`
+	Requests     int     `json:"requests"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost_usd"`
+}
+
+// Aggregate totals usage records by day, agent, project or goblin. Days
+// come out in date order, everything else by cost, highest first.
+func Aggregate(records []*storage.Usage, by string) ([]Row, error) {
+	var key func(u *storage.Usage) string
+	switch by {
+	case ByDay:
+		key = func(u *storage.Usage) string { return u.CreatedAt.Local().Format("2006-01-02") }
+	case ByAgent:
+		key = func(u *storage.Usage) string { return u.Agent }
+	case ByProject:
+		key = func(u *storage.Usage) string { return u.Project }
+	case ByGoblin:
+		key = func(u *storage.Usage) string { return u.GoblinName }
+	default:
+		return nil, fmt.Errorf("unknown grouping: %s (use day, agent, project or goblin)", by)
+	}
+
+	index := make(map[string]int)
+	var rows []Row
+	for _, u := range records {
+		k := key(u)
+		i, ok := index[k]
+		if !ok {
+			i = len(rows)
+			index[k] = i
+			rows = append(rows, Row{Key: k})
+		}
+		rows[i].Requests++
+		rows[i].InputTokens += u.InputTokens
+		rows[i].OutputTokens += u.OutputTokens
+		rows[i].Cost += u.Cost
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if by == ByDay {
+			return rows[i].Key < rows[j].Key
+		}
+		return rows[i].Cost > rows[j].Cost
+	})
+	return rows, nil
+}
+
+// Total sums rows
+func Total(rows []Row) Row {
+	total := Row{Key: "total"}
+	for _, r := range rows {
+		total.Requests += r.Requests
+		total.InputTokens += r.InputTokens
+		total.OutputTokens += r.OutputTokens
+		total.Cost += r.Cost
+	}
+	return total
+}
+
+// Write renders rows as json, csv (raw numbers for spreadsheets) or a
+// text or markdown table with a total line
+func Write(w io.Writer, rows []Row, by, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+
+	case table.CSV:
+		t := table.New(by, "requests", "input_tokens", "output_tokens", "cost_usd")
+		for _, r := range rows {
+			t.Add(r.Key, strconv.Itoa(r.Requests), strconv.Itoa(r.InputTokens),
+				strconv.Itoa(r.OutputTokens), strconv.FormatFloat(r.Cost, 'f', 4, 64))
+		}
+		return t.Write(w, format)
+	}
+
+	if err := table.Validate(format); err != nil {
+		return fmt.Errorf("unknown format: %s (use table, json, csv or markdown)", format)
+	}
+
+	t := table.New(strings.ToUpper(by), "REQUESTS", "INPUT", "OUTPUT", "COST")
+	for _, r := range append(rows, Total(rows)) {
+		key := r.Key
+		if key == "" {
+			key = "-"
+		}
+		t.Add(key, strconv.Itoa(r.Requests), strconv.Itoa(r.InputTokens),
+			strconv.Itoa(r.OutputTokens), fmt.Sprintf("$%.2f", r.Cost))
+	}
+	return t.Write(w, format)
+}
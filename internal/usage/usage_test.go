@@ -0,0 +1,88 @@
+package usage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func TestCost(t *testing.T) {
+	if got := Cost(config.PriceConfig{Input: 3, Output: 15}, 1_000_000, 100_000); got != 4.5 {
+		t.Errorf("Expected $4.50, got %v", got)
+	}
+	if got := Cost(DefaultPrices["ollama"], 5000, 5000); got != 0 {
+		t.Errorf("Expected local models to be free, got %v", got)
+	}
+}
+
+func testRecords() []*storage.Usage {
+	day1 := time.Date(2026, 10, 12, 10, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	return []*storage.Usage{
+		{GoblinName: "coder", Agent: "claude", Project: "/src/app", InputTokens: 100, Cost: 1, CreatedAt: day2},
+		{GoblinName: "coder", Agent: "claude", Project: "/src/app", OutputTokens: 50, Cost: 2, CreatedAt: day2},
+		{GoblinName: "helper", Agent: "codex", Project: "/src/lib", InputTokens: 10, Cost: 0.5, CreatedAt: day1},
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	rows, err := Aggregate(testRecords(), ByAgent)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Key != "claude" || rows[0].Requests != 2 || rows[0].Cost != 3 || rows[0].OutputTokens != 50 {
+		t.Errorf("Unexpected agent rows %+v", rows)
+	}
+
+	rows, _ = Aggregate(testRecords(), ByDay)
+	if len(rows) != 2 || rows[0].Key != "2026-10-12" || rows[1].Key != "2026-10-13" {
+		t.Errorf("Expected days in date order, got %+v", rows)
+	}
+
+	if total := Total(rows); total.Requests != 3 || total.Cost != 3.5 || total.InputTokens != 110 {
+		t.Errorf("Unexpected total %+v", total)
+	}
+
+	if _, err := Aggregate(nil, "week"); err == nil {
+		t.Error("Expected an unknown grouping rejected")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rows, _ := Aggregate(testRecords(), ByProject)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, rows, ByProject, "csv"); err != nil {
+		t.Fatalf("Write csv failed: %v", err)
+	}
+	want := "project,requests,input_tokens,output_tokens,cost_usd\n/src/app,2,100,50,3.0000\n/src/lib,1,10,0,0.5000\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected csv:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	Write(&buf, rows, ByProject, "table")
+	if !strings.Contains(buf.String(), "$3.50") || !strings.Contains(buf.String(), "total") {
+		t.Errorf("Expected a total line in the table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	Write(&buf, rows, ByProject, "json")
+	if !strings.Contains(buf.String(), `"cost_usd": 3`) {
+		t.Errorf("Unexpected json:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	Write(&buf, rows, ByProject, "markdown")
+	if !strings.Contains(buf.String(), "| /src/app | 2 | 100 | 50 | $3.00 |") {
+		t.Errorf("Unexpected markdown:\n%s", buf.String())
+	}
+
+	if err := Write(&buf, rows, ByProject, "xml"); err == nil {
+		t.Error("Expected an unknown format rejected")
+	}
+}
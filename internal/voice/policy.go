@@ -0,0 +1,80 @@
+package voice
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+// Classes of voice command, each with its own confirmation policy
+const (
+	ClassRead        = "read"
+	ClassChange      = "change"
+	ClassDestructive = "destructive"
+)
+
+// What happens to a heard command
+const (
+	PolicyRun     = "run"     // carry it out at once
+	PolicyConfirm = "confirm" // wait for a spoken or typed yes
+	PolicyRefuse  = "refuse"  // never by voice
+)
+
+// DefaultConfirmTimeout is how long a confirmation is awaited when
+// voice.confirm.timeout isn't set
+const DefaultConfirmTimeout = 30 * time.Second
+
+var classes = map[string]string{
+	"list": ClassRead, "status": ClassRead, "attach": ClassRead, "diff": ClassRead,
+	"top": ClassRead, "help": ClassRead,
+	"spawn": ClassChange, "task": ClassChange, "push": ClassChange, "commit": ClassChange,
+	"edit": ClassChange,
+	"stop": ClassDestructive, "kill": ClassDestructive, "merge": ClassDestructive,
+}
+
+// Class returns the class of an action. Actions it doesn't know are
+// treated as destructive.
+func Class(action string) string {
+	if c, ok := classes[action]; ok {
+		return c
+	}
+	return ClassDestructive
+}
+
+// Policy returns what voice.confirm says to do with action. A policy it
+// doesn't recognize asks for confirmation.
+func Policy(cfg config.VoiceConfirmConfig, action string) string {
+	var p string
+	switch Class(action) {
+	case ClassRead:
+		p = cfg.Read
+	case ClassChange:
+		p = cfg.Change
+	default:
+		p = cfg.Destructive
+	}
+
+	switch p = strings.ToLower(strings.TrimSpace(p)); p {
+	case PolicyRun, PolicyRefuse:
+		return p
+	}
+	return PolicyConfirm
+}
+
+// ConfirmTimeout returns how long a confirmation is awaited
+func ConfirmTimeout(cfg config.VoiceConfirmConfig) time.Duration {
+	if cfg.Timeout <= 0 {
+		return DefaultConfirmTimeout
+	}
+	return cfg.Timeout
+}
+
+// Question asks for confirmation of a command, as spoken to the user
+func Question(action, name string) string {
+	q := strings.ToUpper(action[:1]) + action[1:]
+	if name != "" {
+		q += " " + name
+	}
+	return q + "? Say confirm or cancel."
+}
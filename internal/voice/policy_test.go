@@ -0,0 +1,56 @@
+package voice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestPolicy(t *testing.T) {
+	cfg := config.VoiceConfirmConfig{Read: "run", Change: "Confirm", Destructive: "refuse"}
+
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{"list", PolicyRun},
+		{"status", PolicyRun},
+		{"spawn", PolicyConfirm},
+		{"push", PolicyConfirm},
+		{"kill", PolicyRefuse},
+		{"merge", PolicyRefuse},
+		{"something-new", PolicyRefuse}, // unknown actions are destructive
+	}
+	for _, tt := range tests {
+		if got := Policy(cfg, tt.action); got != tt.want {
+			t.Errorf("Policy(%q) = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+
+	// Unrecognized or unset policies ask first
+	if got := Policy(config.VoiceConfirmConfig{Destructive: "yolo"}, "kill"); got != PolicyConfirm {
+		t.Errorf("Expected an unrecognized policy to confirm, got %q", got)
+	}
+	if got := Policy(config.VoiceConfirmConfig{}, "list"); got != PolicyConfirm {
+		t.Errorf("Expected an unset policy to confirm, got %q", got)
+	}
+}
+
+func TestConfirmTimeout(t *testing.T) {
+	if got := ConfirmTimeout(config.VoiceConfirmConfig{}); got != DefaultConfirmTimeout {
+		t.Errorf("Expected the default timeout, got %v", got)
+	}
+	if got := ConfirmTimeout(config.VoiceConfirmConfig{Timeout: time.Minute}); got != time.Minute {
+		t.Errorf("Expected a minute, got %v", got)
+	}
+}
+
+func TestQuestion(t *testing.T) {
+	if got := Question("kill", "coder"); got != "Kill coder? Say confirm or cancel." {
+		t.Errorf("Question = %q", got)
+	}
+	if got := Question("push", ""); got != "Push? Say confirm or cancel." {
+		t.Errorf("Question = %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package voice
+
+import (
+	"fmt"
+
+	"github.com/astoreyai/goblin-forge/internal/ipc"
+)
+
+// onScreen are actions that show something in a terminal, which the
+// background listener doesn't have
+var onScreen = map[string]bool{
+	"attach": true, "diff": true, "edit": true, "top": true, "help": true,
+}
+
+// Args returns the gforge command line carrying out a voice command, with
+// spawns made in project. list, status and exit_voice are left to the
+// listener.
+func Args(cmd ipc.VoiceCommand, project string) ([]string, error) {
+	if onScreen[cmd.Action] {
+		return nil, fmt.Errorf("%s needs a terminal; run it there", cmd.Action)
+	}
+
+	needsName := func() error {
+		if cmd.Name == "" {
+			return fmt.Errorf("%s needs a goblin name", cmd.Action)
+		}
+		return nil
+	}
+
+	switch cmd.Action {
+	case "spawn":
+		if err := needsName(); err != nil {
+			return nil, err
+		}
+		args := []string{"spawn", cmd.Name, "--project", project}
+		if cmd.Agent != "" {
+			args = append(args, "--agent", cmd.Agent)
+		}
+		if cmd.Task != "" {
+			args = append(args, "--task", cmd.Task)
+		}
+		return args, nil
+
+	case "stop", "kill":
+		if err := needsName(); err != nil {
+			return nil, err
+		}
+		return []string{cmd.Action, cmd.Name}, nil
+
+	case "push":
+		// Without a name, the current goblin (gforge use)
+		if cmd.Name == "" {
+			return []string{"push"}, nil
+		}
+		return []string{"push", cmd.Name}, nil
+
+	case "task":
+		if err := needsName(); err != nil {
+			return nil, err
+		}
+		if cmd.Description == "" {
+			return nil, fmt.Errorf("task needs a description")
+		}
+		return []string{"task", "--goblin", cmd.Name, cmd.Description}, nil
+	}
+
+	return nil, fmt.Errorf("can't do %q by voice", cmd.Action)
+}
+
+// Confirmation describes a command the listener carries out, as spoken
+// back to the user before it runs
+func Confirmation(cmd ipc.VoiceCommand) string {
+	switch cmd.Action {
+	case "spawn":
+		agent := cmd.Agent
+		if agent == "" {
+			agent = "claude"
+		}
+		return fmt.Sprintf("Spawning %s with %s", cmd.Name, agent)
+	case "stop":
+		return "Stopping " + cmd.Name
+	case "kill":
+		return "Killing " + cmd.Name
+	case "push":
+		if cmd.Name == "" {
+			return "Pushing"
+		}
+		return "Pushing " + cmd.Name
+	case "task":
+		return "Sending the task to " + cmd.Name
+	case "exit_voice":
+		return "Voice control off"
+	}
+	return "Sorry, I didn't catch that"
+}
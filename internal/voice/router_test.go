@@ -0,0 +1,60 @@
+package voice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/ipc"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		cmd  ipc.VoiceCommand
+		want string
+	}{
+		{ipc.VoiceCommand{Action: "spawn", Name: "coder", Agent: "aider", Task: "fix the tests"},
+			"spawn coder --project /src/app --agent aider --task fix the tests"},
+		{ipc.VoiceCommand{Action: "kill", Name: "coder"}, "kill coder"},
+		{ipc.VoiceCommand{Action: "push"}, "push"},
+		{ipc.VoiceCommand{Action: "task", Name: "coder", Description: "add docs"}, "task --goblin coder add docs"},
+	}
+	for _, tt := range tests {
+		args, err := Args(tt.cmd, "/src/app")
+		if err != nil {
+			t.Errorf("Args(%+v) failed: %v", tt.cmd, err)
+			continue
+		}
+		if got := strings.Join(args, " "); got != tt.want {
+			t.Errorf("Args(%+v) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+
+	for _, cmd := range []ipc.VoiceCommand{
+		{Action: "attach", Name: "coder"},
+		{Action: "stop"},
+		{Action: "task", Name: "coder"},
+		{Action: "unknown", Raw: "do something random"},
+	} {
+		if _, err := Args(cmd, "/src/app"); err == nil {
+			t.Errorf("Expected %+v refused", cmd)
+		}
+	}
+}
+
+func TestConfirmation(t *testing.T) {
+	tests := []struct {
+		cmd  ipc.VoiceCommand
+		want string
+	}{
+		{ipc.VoiceCommand{Action: "spawn", Name: "coder", Agent: "aider"}, "Spawning coder with aider"},
+		{ipc.VoiceCommand{Action: "spawn", Name: "coder"}, "Spawning coder with claude"},
+		{ipc.VoiceCommand{Action: "kill", Name: "tester"}, "Killing tester"},
+		{ipc.VoiceCommand{Action: "push"}, "Pushing"},
+		{ipc.VoiceCommand{Action: "unknown", Raw: "do something random"}, "Sorry, I didn't catch that"},
+	}
+	for _, tt := range tests {
+		if got := Confirmation(tt.cmd); got != tt.want {
+			t.Errorf("Confirmation(%+v) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package voice speaks gforge's feedback aloud, complementing the voice
+// daemon's command input.
+package voice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/workspace"
+)
+
+// Engines, in the order auto tries them. piper needs voice.piper_model.
+var engines = []string{"piper", "say", "espeak-ng", "espeak", "spd-say"}
+
+// piperSampleRate is used when a piper model's .json doesn't give one
+const piperSampleRate = 22050
+
+// Speaker reads text aloud with a local text-to-speech engine
+type Speaker struct {
+	engine string
+	model  string // piper voice (.onnx)
+
+	// lookPath and run are replaced in tests
+	lookPath func(file string) (string, error)
+	run      func(stdin string, name string, args ...string) error
+}
+
+// NewSpeaker creates a speaker using voice.tts: auto picks the first
+// engine installed
+func NewSpeaker(cfg config.VoiceConfig) (*Speaker, error) {
+	s := &Speaker{model: cfg.PiperModel, lookPath: exec.LookPath, run: runWithInput}
+	if err := s.pick(cfg.TTS); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// pick settles the engine to use for name
+func (s *Speaker) pick(name string) error {
+	if name != "" && name != "auto" {
+		if name == "piper" && s.model == "" {
+			return fmt.Errorf("piper needs voice.piper_model")
+		}
+		if _, err := s.lookPath(name); err != nil {
+			return fmt.Errorf("%s not found: %w", name, err)
+		}
+		s.engine = name
+		return nil
+	}
+
+	for _, e := range engines {
+		if e == "piper" && s.model == "" {
+			continue
+		}
+		if _, err := s.lookPath(e); err == nil {
+			s.engine = e
+			return nil
+		}
+	}
+	return fmt.Errorf("no text-to-speech engine found; install %s", strings.Join(engines[1:], ", "))
+}
+
+// Engine returns the engine in use
+func (s *Speaker) Engine() string {
+	return s.engine
+}
+
+// Speak reads text aloud, returning once it has been spoken
+func (s *Speaker) Speak(text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	var err error
+	switch s.engine {
+	case "piper":
+		err = s.speakPiper(text)
+	case "say":
+		err = s.run(text, "say", "-f", "-")
+	case "espeak-ng", "espeak":
+		err = s.run(text, s.engine, "--stdin")
+	case "spd-say":
+		err = s.run("", "spd-say", "--wait", "--", text)
+	default:
+		err = fmt.Errorf("unknown text-to-speech engine: %s", s.engine)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to speak: %w", err)
+	}
+	return nil
+}
+
+// speakPiper synthesizes raw audio with piper and plays it with aplay
+func (s *Speaker) speakPiper(text string) error {
+	if _, err := s.lookPath("aplay"); err != nil {
+		return fmt.Errorf("piper output needs aplay: %w", err)
+	}
+	script := fmt.Sprintf("piper --model %s --output_raw | aplay -q -r %d -f S16_LE -t raw -",
+		workspace.ShellQuote(s.model), sampleRate(s.model))
+	return s.run(text, "sh", "-c", script)
+}
+
+// sampleRate reads a piper model's rate from the .json next to it
+func sampleRate(model string) int {
+	data, err := os.ReadFile(model + ".json")
+	if err != nil {
+		return piperSampleRate
+	}
+	var meta struct {
+		Audio struct {
+			SampleRate int `json:"sample_rate"`
+		} `json:"audio"`
+	}
+	if json.Unmarshal(data, &meta) != nil || meta.Audio.SampleRate == 0 {
+		return piperSampleRate
+	}
+	return meta.Audio.SampleRate
+}
+
+func runWithInput(stdin string, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s\n%s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
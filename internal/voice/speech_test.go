@@ -0,0 +1,92 @@
+package voice
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSpeaker has the given programs installed and records what it runs
+func fakeSpeaker(model string, installed ...string) (*Speaker, *[]string) {
+	var ran []string
+	s := &Speaker{
+		model: model,
+		lookPath: func(file string) (string, error) {
+			for _, p := range installed {
+				if p == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", fmt.Errorf("not found")
+		},
+		run: func(stdin string, name string, args ...string) error {
+			ran = append(ran, fmt.Sprintf("%s %s <%s", name, strings.Join(args, " "), stdin))
+			return nil
+		},
+	}
+	return s, &ran
+}
+
+func TestPick(t *testing.T) {
+	s, _ := fakeSpeaker("", "espeak", "spd-say")
+	if err := s.pick("auto"); err != nil || s.Engine() != "espeak" {
+		t.Errorf("Expected espeak picked, got %q, %v", s.Engine(), err)
+	}
+
+	// piper is skipped without a model, even when installed
+	s, _ = fakeSpeaker("", "piper", "say")
+	if err := s.pick(""); err != nil || s.Engine() != "say" {
+		t.Errorf("Expected say picked, got %q, %v", s.Engine(), err)
+	}
+	if err := s.pick("piper"); err == nil {
+		t.Error("Expected piper refused without a model")
+	}
+
+	s, _ = fakeSpeaker("")
+	if err := s.pick("auto"); err == nil {
+		t.Error("Expected an error with no engine installed")
+	}
+	if err := s.pick("espeak"); err == nil {
+		t.Error("Expected an error for an engine that isn't installed")
+	}
+}
+
+func TestSpeak(t *testing.T) {
+	s, ran := fakeSpeaker("", "espeak-ng")
+	s.pick("espeak-ng")
+	if err := s.Speak("coder finished"); err != nil {
+		t.Fatalf("Speak failed: %v", err)
+	}
+	if err := s.Speak("  "); err != nil {
+		t.Fatalf("Speak failed: %v", err)
+	}
+	if len(*ran) != 1 || (*ran)[0] != "espeak-ng --stdin <coder finished" {
+		t.Errorf("Expected one espeak-ng run, got %v", *ran)
+	}
+}
+
+func TestSpeakPiper(t *testing.T) {
+	model := filepath.Join(t.TempDir(), "en_US-amy-low.onnx")
+	os.WriteFile(model+".json", []byte(`{"audio": {"sample_rate": 16000}}`), 0644)
+
+	s, ran := fakeSpeaker(model, "piper")
+	s.pick("piper")
+	if err := s.Speak("hello"); err == nil {
+		t.Error("Expected piper to need aplay")
+	}
+
+	s, ran = fakeSpeaker(model, "piper", "aplay")
+	s.pick("piper")
+	if err := s.Speak("hello"); err != nil {
+		t.Fatalf("Speak failed: %v", err)
+	}
+	if len(*ran) != 1 || !strings.Contains((*ran)[0], "aplay -q -r 16000") || !strings.HasSuffix((*ran)[0], "<hello") {
+		t.Errorf("Expected piper piped to aplay at the model's rate, got %v", *ran)
+	}
+
+	if got := sampleRate("/nonexistent.onnx"); got != piperSampleRate {
+		t.Errorf("Expected the default rate without a model config, got %d", got)
+	}
+}
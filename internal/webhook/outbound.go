@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// Lifecycle events sent to outbound webhooks
+const (
+	EventSpawned   = "spawned"
+	EventCompleted = "completed"
+	EventFailed    = "failed" // The goblin's session ended without gforge stopping it
+	EventStopped   = "stopped"
+	EventKilled    = "killed"
+	EventCommit    = "commit" // A commit was added to the goblin's branch
+	EventPing      = "ping"   // Sent by gforge webhook test
+)
+
+// Delivery is the JSON body posted for a lifecycle event
+type Delivery struct {
+	Event    string    `json:"event"`
+	GoblinID string    `json:"goblin_id"`
+	Goblin   string    `json:"goblin"`
+	Agent    string    `json:"agent,omitempty"`
+	Project  string    `json:"project,omitempty"`
+	Branch   string    `json:"branch,omitempty"`
+	Commit   string    `json:"commit,omitempty"`
+	Detail   string    `json:"detail,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// NewDelivery builds the body for a lifecycle event emitted by the
+// coordinator
+func NewDelivery(e agents.LifecycleEvent) Delivery {
+	return Delivery{
+		Event:    e.Type,
+		GoblinID: e.GoblinID,
+		Goblin:   e.Details["name"],
+		Agent:    e.AgentName,
+		Project:  e.Details["project"],
+		Branch:   e.Details["branch"],
+		Commit:   e.Details["commit"],
+		Detail:   e.Details["detail"],
+		Time:     e.Timestamp,
+	}
+}
+
+// Sender posts lifecycle events to the outbound webhooks in config
+type Sender struct {
+	cfg    *config.Config
+	client *http.Client
+	log    *logging.Logger
+}
+
+// NewSender creates a sender for the webhooks under webhooks.outbound,
+// read at each event so a reloaded config applies
+func NewSender(cfg *config.Config, log *logging.Logger) *Sender {
+	return &Sender{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}, log: log}
+}
+
+// Subscribe has lm's events posted to every webhook that wants them, side
+// by side. Failed deliveries are logged, never retried.
+func (s *Sender) Subscribe(lm *agents.LifecycleManager) {
+	lm.OnEvent(func(e agents.LifecycleEvent) {
+		var wg sync.WaitGroup
+		for _, hook := range s.cfg.Webhooks.Outbound {
+			if !Wants(hook, e.Type) {
+				continue
+			}
+			wg.Add(1)
+			go func(hook config.OutboundWebhook) {
+				defer wg.Done()
+				if err := s.Send(hook, NewDelivery(e)); err != nil && s.log != nil {
+					s.log.Warn("Failed to deliver webhook",
+						logging.String("url", hook.URL),
+						logging.String("event", e.Type),
+						logging.Err(err))
+				}
+			}(hook)
+		}
+		wg.Wait()
+	})
+}
+
+// Wants reports whether a webhook takes an event
+func Wants(hook config.OutboundWebhook, event string) bool {
+	if len(hook.Events) == 0 || event == EventPing {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Send posts a delivery to a webhook, signed with its secret if it has
+// one. Any status outside 2xx is an error.
+func (s *Sender) Send(hook config.OutboundWebhook, d Delivery) error {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gforge")
+	req.Header.Set("X-Gforge-Event", d.Event)
+	if hook.Secret != "" {
+		req.Header.Set("X-Gforge-Signature", "sha256="+Sign(body, hook.Secret))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", hook.URL, resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body, as receivers check it
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/agents"
+	"github.com/astoreyai/goblin-forge/internal/config"
+)
+
+func TestSend(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []Delivery
+		signed   bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var d Delivery
+		if err := json.Unmarshal(body, &d); err != nil {
+			t.Errorf("Failed to parse delivery: %v", err)
+		}
+		mu.Lock()
+		received = append(received, d)
+		signed = r.Header.Get("X-Gforge-Signature") == "sha256="+Sign(body, "s3cret")
+		mu.Unlock()
+		if r.Header.Get("X-Gforge-Event") != d.Event {
+			t.Errorf("Expected X-Gforge-Event %s, got %s", d.Event, r.Header.Get("X-Gforge-Event"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Webhooks: config.WebhooksConfig{Outbound: []config.OutboundWebhook{
+		{URL: server.URL, Secret: "s3cret", Events: []string{EventCompleted, EventCommit}},
+	}}}
+	lm := agents.NewLifecycleManager()
+	NewSender(cfg, nil).Subscribe(lm)
+
+	lm.Emit(agents.LifecycleEvent{Type: EventSpawned, GoblinID: "id-1", Details: map[string]string{"name": "fixer"}})
+	lm.Emit(agents.LifecycleEvent{Type: EventCompleted, GoblinID: "id-1", AgentName: "claude",
+		Details: map[string]string{"name": "fixer", "branch": "gforge/fixer"}})
+	lm.Wait()
+
+	if len(received) != 1 {
+		t.Fatalf("Expected only the completed event delivered, got %+v", received)
+	}
+	d := received[0]
+	if d.Event != EventCompleted || d.Goblin != "fixer" || d.Agent != "claude" || d.Branch != "gforge/fixer" || d.Time.IsZero() {
+		t.Errorf("Unexpected delivery: %+v", d)
+	}
+	if !signed {
+		t.Error("Expected the delivery signed with the secret")
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s := NewSender(&config.Config{}, nil)
+	if err := s.Send(config.OutboundWebhook{URL: server.URL}, Delivery{Event: EventPing}); err == nil {
+		t.Error("Expected a 500 reported")
+	}
+}
+
+func TestWants(t *testing.T) {
+	all := config.OutboundWebhook{URL: "http://example.com"}
+	some := config.OutboundWebhook{URL: "http://example.com", Events: []string{EventFailed}}
+
+	if !Wants(all, EventCommit) || !Wants(some, EventFailed) || Wants(some, EventSpawned) {
+		t.Error("Expected events filtered by the webhook's list, all when it has none")
+	}
+	if !Wants(some, EventPing) {
+		t.Error("Expected pings sent to every webhook")
+	}
+}
@@ -0,0 +1,287 @@
+// Package webhook turns issue tracker webhooks into goblins: labeling a
+// GitHub or Linear issue spawns one to work on it. It also posts goblin
+// lifecycle events to outbound webhooks.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/integrations"
+)
+
+// maxBody caps the payloads read; issue webhooks are far smaller
+const maxBody = 5 << 20
+
+// linearMaxAge is how old a Linear delivery's webhookTimestamp may be
+// before it is refused as a replay
+const linearMaxAge = time.Minute
+
+// IssueEvent is an issue that was given the spawn label
+type IssueEvent struct {
+	Ref   integrations.IssueRef
+	Repo  string // GitHub owner/repo or Linear team key
+	Title string
+	Body  string
+	URL   string
+}
+
+// Task is what the goblin is asked to do, preset instructions first
+func (e IssueEvent) Task(instructions string) string {
+	var b strings.Builder
+	if instructions = strings.TrimSpace(instructions); instructions != "" {
+		b.WriteString(instructions + "\n\n")
+	}
+	fmt.Fprintf(&b, "Resolve %s: %s", e.Ref, e.Title)
+	if body := strings.TrimSpace(e.Body); body != "" {
+		b.WriteString("\n\n" + body)
+	}
+	if e.URL != "" {
+		b.WriteString("\n\n" + e.URL)
+	}
+	return b.String()
+}
+
+var nameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// GoblinName names the goblin for the issue, e.g. repo-123 or proj-456
+func (e IssueEvent) GoblinName() string {
+	id := e.Ref.ID
+	if e.Ref.Tracker == integrations.TrackerGitHub {
+		// owner/repo#123 -> repo-123
+		if i := strings.LastIndex(id, "/"); i >= 0 {
+			id = id[i+1:]
+		}
+	}
+	return strings.Trim(nameUnsafe.ReplaceAllString(strings.ToLower(id), "-"), "-")
+}
+
+// VerifyGitHub checks a delivery's X-Hub-Signature-256 header
+func VerifyGitHub(body []byte, signature, secret string) bool {
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	return ok && validMAC(body, sig, secret)
+}
+
+// VerifyLinear checks a delivery's Linear-Signature header
+func VerifyLinear(body []byte, signature, secret string) bool {
+	return validMAC(body, signature, secret)
+}
+
+// FreshLinear reports whether a Linear delivery's webhookTimestamp, in
+// milliseconds, is within linearMaxAge of now. Linear signs the body, so
+// this keeps a captured delivery from being replayed later.
+func FreshLinear(body []byte, now time.Time) bool {
+	var payload struct {
+		WebhookTimestamp int64 `json:"webhookTimestamp"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.WebhookTimestamp == 0 {
+		return false
+	}
+	age := now.Sub(time.UnixMilli(payload.WebhookTimestamp))
+	return age.Abs() <= linearMaxAge
+}
+
+// validMAC reports whether sig is the hex HMAC-SHA256 of body
+func validMAC(body []byte, sig, secret string) bool {
+	got, err := hex.DecodeString(sig)
+	if err != nil || secret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// ParseGitHub reads a GitHub delivery of the given X-GitHub-Event type,
+// returning nil for anything but an issue being given the label
+func ParseGitHub(eventType string, body []byte, label string) (*IssueEvent, error) {
+	if eventType != "issues" {
+		return nil, nil
+	}
+
+	var payload struct {
+		Action string `json:"action"`
+		Label  struct {
+			Name string `json:"name"`
+		} `json:"label"`
+		Issue struct {
+			Number  int    `json:"number"`
+			Title   string `json:"title"`
+			Body    string `json:"body"`
+			HTMLURL string `json:"html_url"`
+		} `json:"issue"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub payload: %w", err)
+	}
+	if payload.Action != "labeled" || !strings.EqualFold(payload.Label.Name, label) {
+		return nil, nil
+	}
+	if payload.Repository.FullName == "" || payload.Issue.Number == 0 {
+		return nil, fmt.Errorf("GitHub payload is missing its repository or issue")
+	}
+
+	return &IssueEvent{
+		Ref: integrations.IssueRef{
+			Tracker: integrations.TrackerGitHub,
+			ID:      fmt.Sprintf("%s#%d", payload.Repository.FullName, payload.Issue.Number),
+		},
+		Repo:  payload.Repository.FullName,
+		Title: payload.Issue.Title,
+		Body:  payload.Issue.Body,
+		URL:   payload.Issue.HTMLURL,
+	}, nil
+}
+
+// ParseLinear reads a Linear delivery, returning nil for anything but an
+// issue created with the label or updated to have it
+func ParseLinear(body []byte, label string) (*IssueEvent, error) {
+	var payload struct {
+		Action string `json:"action"`
+		Type   string `json:"type"`
+		Data   struct {
+			Identifier  string `json:"identifier"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			URL         string `json:"url"`
+			Team        struct {
+				Key string `json:"key"`
+			} `json:"team"`
+			Labels []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"labels"`
+		} `json:"data"`
+		// Holds labelIds, as they were, only when the labels changed
+		UpdatedFrom struct {
+			LabelIDs *[]string `json:"labelIds"`
+		} `json:"updatedFrom"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse Linear payload: %w", err)
+	}
+	if payload.Type != "Issue" {
+		return nil, nil
+	}
+
+	labelID := ""
+	for _, l := range payload.Data.Labels {
+		if strings.EqualFold(l.Name, label) {
+			labelID = l.ID
+		}
+	}
+	if labelID == "" {
+		return nil, nil
+	}
+	switch payload.Action {
+	case "create":
+	case "update":
+		if payload.UpdatedFrom.LabelIDs == nil {
+			return nil, nil
+		}
+		for _, id := range *payload.UpdatedFrom.LabelIDs {
+			if id == labelID {
+				return nil, nil
+			}
+		}
+	default:
+		return nil, nil
+	}
+	if payload.Data.Identifier == "" {
+		return nil, fmt.Errorf("Linear payload is missing its issue identifier")
+	}
+
+	team := payload.Data.Team.Key
+	if team == "" {
+		team, _, _ = strings.Cut(payload.Data.Identifier, "-")
+	}
+	return &IssueEvent{
+		Ref: integrations.IssueRef{
+			Tracker: integrations.TrackerLinear,
+			ID:      strings.ToUpper(payload.Data.Identifier),
+		},
+		Repo:  team,
+		Title: payload.Data.Title,
+		Body:  payload.Data.Description,
+		URL:   payload.Data.URL,
+	}, nil
+}
+
+// Handler serves POST /webhooks/github and POST /webhooks/linear. It
+// answers as soon as a delivery checks out and leaves the spawning to
+// Spawn, which it calls in the background.
+type Handler struct {
+	Label        string
+	GitHubSecret string
+	LinearSecret string
+	Spawn        func(IssueEvent)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var tracker, secret string
+	switch r.URL.Path {
+	case "/webhooks/github":
+		tracker, secret = "GitHub", h.GitHubSecret
+	case "/webhooks/linear":
+		tracker, secret = "Linear", h.LinearSecret
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if secret == "" {
+		http.Error(w, tracker+" webhooks are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var ev *IssueEvent
+	if tracker == "GitHub" {
+		if !VerifyGitHub(body, r.Header.Get("X-Hub-Signature-256"), secret) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		ev, err = ParseGitHub(r.Header.Get("X-GitHub-Event"), body, h.Label)
+	} else {
+		if !VerifyLinear(body, r.Header.Get("Linear-Signature"), secret) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		if !FreshLinear(body, time.Now()) {
+			http.Error(w, "stale or missing webhookTimestamp", http.StatusUnauthorized)
+			return
+		}
+		ev, err = ParseLinear(body, h.Label)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ev == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	go h.Spawn(*ev)
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sign(body, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+const githubLabeled = `{
+	"action": "labeled",
+	"label": {"name": "gforge"},
+	"issue": {"number": 42, "title": "Fix login", "body": "It breaks", "html_url": "https://github.com/acme/app/issues/42"},
+	"repository": {"full_name": "acme/app"}
+}`
+
+func TestParseGitHub(t *testing.T) {
+	ev, err := ParseGitHub("issues", []byte(githubLabeled), "gforge")
+	if err != nil {
+		t.Fatalf("ParseGitHub failed: %v", err)
+	}
+	if ev == nil {
+		t.Fatal("Expected an event")
+	}
+	if ev.Ref.String() != "gh:acme/app#42" || ev.Repo != "acme/app" || ev.Title != "Fix login" {
+		t.Errorf("Unexpected event: %+v", ev)
+	}
+	if ev.GoblinName() != "app-42" {
+		t.Errorf("Expected name app-42, got %s", ev.GoblinName())
+	}
+
+	task := ev.Task("Open a PR when done.")
+	for _, want := range []string{"Open a PR when done.\n\n", "Resolve gh:acme/app#42: Fix login", "It breaks", "https://github.com/acme/app/issues/42"} {
+		if !strings.Contains(task, want) {
+			t.Errorf("Task missing %q:\n%s", want, task)
+		}
+	}
+
+	for _, tc := range []struct{ eventType, label string }{
+		{"issues", "bug"},
+		{"pull_request", "gforge"},
+	} {
+		if ev, err := ParseGitHub(tc.eventType, []byte(githubLabeled), tc.label); err != nil || ev != nil {
+			t.Errorf("%s with label %s: expected nothing, got %+v, %v", tc.eventType, tc.label, ev, err)
+		}
+	}
+}
+
+func TestParseLinear(t *testing.T) {
+	data := `"data": {"identifier": "ENG-7", "title": "Add export", "team": {"key": "ENG"},
+		"labels": [{"id": "l1", "name": "Gforge"}]}`
+
+	tests := []struct {
+		name    string
+		payload string
+		want    bool
+	}{
+		{"created with label", `{"action": "create", "type": "Issue", ` + data + `}`, true},
+		{"label added", `{"action": "update", "type": "Issue", ` + data + `, "updatedFrom": {"labelIds": []}}`, true},
+		{"label already there", `{"action": "update", "type": "Issue", ` + data + `, "updatedFrom": {"labelIds": ["l1"]}}`, false},
+		{"labels unchanged", `{"action": "update", "type": "Issue", ` + data + `, "updatedFrom": {"title": "Old"}}`, false},
+		{"comment", `{"action": "create", "type": "Comment", ` + data + `}`, false},
+	}
+
+	for _, tc := range tests {
+		ev, err := ParseLinear([]byte(tc.payload), "gforge")
+		if err != nil {
+			t.Fatalf("%s: ParseLinear failed: %v", tc.name, err)
+		}
+		if (ev != nil) != tc.want {
+			t.Errorf("%s: expected event %v, got %+v", tc.name, tc.want, ev)
+			continue
+		}
+		if ev != nil && (ev.Ref.String() != "linear:ENG-7" || ev.Repo != "ENG" || ev.GoblinName() != "eng-7") {
+			t.Errorf("%s: unexpected event %+v", tc.name, ev)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	spawned := make(chan IssueEvent, 1)
+	h := &Handler{
+		Label:        "gforge",
+		GitHubSecret: "s3cret",
+		Spawn:        func(ev IssueEvent) { spawned <- ev },
+	}
+
+	post := func(path, signature, event string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(githubLabeled))
+		req.Header.Set("X-Hub-Signature-256", signature)
+		req.Header.Set("X-GitHub-Event", event)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("/webhooks/github", "sha256=00", "issues"); code != http.StatusUnauthorized {
+		t.Errorf("Bad signature: expected 401, got %d", code)
+	}
+	if code := post("/webhooks/linear", "", "issues"); code != http.StatusServiceUnavailable {
+		t.Errorf("Unconfigured tracker: expected 503, got %d", code)
+	}
+
+	good := "sha256=" + sign(githubLabeled, "s3cret")
+	if code := post("/webhooks/github", good, "ping"); code != http.StatusNoContent {
+		t.Errorf("Ping: expected 204, got %d", code)
+	}
+	if code := post("/webhooks/github", good, "issues"); code != http.StatusAccepted {
+		t.Fatalf("Labeled issue: expected 202, got %d", code)
+	}
+	if ev := <-spawned; ev.Ref.String() != "gh:acme/app#42" {
+		t.Errorf("Spawned for %s", ev.Ref)
+	}
+}
+
+func TestLinearReplay(t *testing.T) {
+	spawned := make(chan IssueEvent, 1)
+	h := &Handler{
+		Label:        "gforge",
+		LinearSecret: "s3cret",
+		Spawn:        func(ev IssueEvent) { spawned <- ev },
+	}
+
+	post := func(sent time.Time) int {
+		body := fmt.Sprintf(`{"action": "create", "type": "Issue", "webhookTimestamp": %d,
+			"data": {"identifier": "ENG-7", "title": "Add export", "labels": [{"id": "l1", "name": "gforge"}]}}`,
+			sent.UnixMilli())
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/linear", strings.NewReader(body))
+		req.Header.Set("Linear-Signature", sign(body, "s3cret"))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post(time.Now().Add(-5 * time.Minute)); code != http.StatusUnauthorized {
+		t.Errorf("Old delivery: expected 401, got %d", code)
+	}
+	if code := post(time.Now()); code != http.StatusAccepted {
+		t.Fatalf("Fresh delivery: expected 202, got %d", code)
+	}
+	if ev := <-spawned; ev.Ref.String() != "linear:ENG-7" {
+		t.Errorf("Spawned for %s", ev.Ref)
+	}
+
+	if FreshLinear([]byte(`{"type": "Issue"}`), time.Now()) {
+		t.Error("Expected a delivery without webhookTimestamp refused")
+	}
+}
@@ -0,0 +1,214 @@
+package workflow
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/logging"
+)
+
+// EventWorkflowStep records a goblin spawned for a workflow step
+const EventWorkflowStep = "workflow_step"
+
+// Step outcomes
+const (
+	StepRunning = "running"
+	StepDone    = "done"    // Went quiet, its work committed and completed
+	StepFailed  = "failed"  // Failed to spawn or still busy at its timeout
+	StepSkipped = "skipped" // A step it needs didn't finish
+)
+
+// StepResult is what became of a step
+type StepResult struct {
+	Step   string
+	Status string
+	Goblin *coordinator.Goblin // nil when it never spawned
+	Err    error
+}
+
+// Runner runs workflows, each step in a goblin of its own
+type Runner struct {
+	coord *coordinator.Coordinator
+	log   *logging.Logger
+
+	// Options says how to spawn the goblin name with agent (empty for the
+	// default) in the project: its agent, branch and dev environment
+	Options func(name, agent, project string) (coordinator.SpawnOptions, error)
+
+	// OnStep hears of each step starting and ending
+	OnStep func(StepResult)
+
+	// Spawns run one at a time so worktrees are created in turn
+	spawnMu sync.Mutex
+
+	// step runs one step; runStep outside tests
+	step func(wf *Workflow, step Step, finished map[string]*coordinator.Goblin) StepResult
+}
+
+// NewRunner creates a workflow runner
+func NewRunner(coord *coordinator.Coordinator, log *logging.Logger) *Runner {
+	r := &Runner{coord: coord, log: log}
+	r.step = r.runStep
+	return r
+}
+
+// Run spawns each step's goblin once the steps it needs are done, running
+// independent steps side by side. A step starts from the branch of the
+// step it is From and its task is sent with placeholders filled in. It
+// is done once its agent goes quiet for its idle timeout: its work is
+// then committed, its artifacts collected and the goblin completed.
+// Steps after one that failed are skipped. The goblins share a scratchpad
+// named after the workflow.
+func (r *Runner) Run(wf *Workflow) ([]StepResult, error) {
+	for _, step := range wf.Steps {
+		name := wf.GoblinName(step)
+		if g, err := r.coord.Get(name); err != nil {
+			return nil, err
+		} else if g != nil {
+			return nil, fmt.Errorf("goblin %s already exists; kill it or rename the workflow", name)
+		}
+	}
+
+	// In dependency order, so a skip reaches every step after it at once
+	steps, err := wf.order()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*StepResult)
+	finished := make(map[string]*coordinator.Goblin)
+	done := make(chan StepResult)
+	running := 0
+
+	for {
+		// Start what is ready and skip what never can be
+		for _, step := range steps {
+			if results[step.Name] != nil {
+				continue
+			}
+			wait, failed := blocked(step, results)
+			if failed != nil {
+				results[step.Name] = &StepResult{Step: step.Name, Status: StepSkipped,
+					Err: fmt.Errorf("%s %s", failed.Step, failed.Status)}
+				r.notify(*results[step.Name])
+				continue
+			}
+			if wait {
+				continue
+			}
+
+			results[step.Name] = &StepResult{Step: step.Name, Status: StepRunning}
+			r.notify(*results[step.Name])
+			running++
+			snapshot := make(map[string]*coordinator.Goblin, len(finished))
+			for k, v := range finished {
+				snapshot[k] = v
+			}
+			go func(step Step) {
+				done <- r.step(wf, step, snapshot)
+			}(step)
+		}
+
+		if running == 0 {
+			break
+		}
+		res := <-done
+		running--
+		results[res.Step] = &res
+		if res.Status == StepDone {
+			finished[res.Step] = res.Goblin
+		}
+		r.notify(res)
+	}
+
+	ordered := make([]StepResult, len(wf.Steps))
+	for i, step := range wf.Steps {
+		ordered[i] = *results[step.Name]
+	}
+	return ordered, nil
+}
+
+// blocked reports whether a step waits on steps it needs, and returns
+// the first of them that failed or was skipped, if any
+func blocked(step Step, results map[string]*StepResult) (bool, *StepResult) {
+	wait := false
+	for _, need := range step.Needs {
+		res := results[need]
+		switch {
+		case res == nil || res.Status == StepRunning:
+			wait = true
+		case res.Status != StepDone:
+			return true, res
+		}
+	}
+	return wait, nil
+}
+
+// runStep spawns a step's goblin and waits for it to finish
+func (r *Runner) runStep(wf *Workflow, step Step, finished map[string]*coordinator.Goblin) StepResult {
+	result := StepResult{Step: step.Name, Status: StepFailed}
+
+	opts, err := r.Options(wf.GoblinName(step), step.Agent, wf.Project)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if step.From != "" {
+		opts.BaseRef = finished[step.From].Branch
+	}
+	opts.Task = Expand(step.Task, finished)
+	opts.Squad = wf.Name
+
+	r.spawnMu.Lock()
+	goblin, err := r.coord.Spawn(opts)
+	r.spawnMu.Unlock()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Goblin = goblin
+	r.coord.RecordEvent(goblin.ID, EventWorkflowStep, wf.Name+": "+step.Name)
+
+	if busy := r.coord.WaitQuiet([]*coordinator.Goblin{goblin}, step.IdleTimeout, step.Timeout); len(busy) > 0 {
+		result.Err = fmt.Errorf("still working after %s", step.Timeout)
+		return result
+	}
+
+	if _, err := r.coord.CommitWork(goblin, fmt.Sprintf("gforge: %s step %s", wf.Name, step.Name)); err != nil {
+		result.Err = err
+		return result
+	}
+	project, err := config.LoadProject(goblin.ProjectPath)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if patterns := append(project.Artifacts, step.Artifacts...); len(patterns) > 0 {
+		if _, err := r.coord.CollectArtifacts(goblin, patterns); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	if _, err := r.coord.Complete(goblin.ID); err != nil {
+		result.Err = err
+		return result
+	}
+	goblin.Status = coordinator.StatusCompleted
+
+	if r.log != nil {
+		r.log.Info("Workflow step done",
+			logging.String("workflow", wf.Name),
+			logging.String("step", step.Name),
+			logging.String("goblin", goblin.Name))
+	}
+	result.Status = StepDone
+	return result
+}
+
+func (r *Runner) notify(res StepResult) {
+	if r.OnStep != nil {
+		r.OnStep(res)
+	}
+}
@@ -0,0 +1,95 @@
+package workflow
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/config"
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"github.com/astoreyai/goblin-forge/internal/storage"
+)
+
+func setupRunner(t *testing.T) (*Runner, *storage.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cfg := &config.Config{
+		WorktreeBase: filepath.Join(dir, "worktrees"),
+		Tmux:         config.TmuxConfig{SocketName: "gforge-test-workflow"},
+	}
+	return NewRunner(coordinator.New(db, cfg, nil), nil), db
+}
+
+func TestRun(t *testing.T) {
+	runner, _ := setupRunner(t)
+
+	wf := &Workflow{Name: "wf", Steps: []Step{
+		{Name: "docs", Task: "Document {api.branch}", Needs: []string{"api"}},
+		{Name: "api", Task: "Build it", Needs: []string{"plan"}},
+		{Name: "plan", Task: "Plan it"},
+		{Name: "lint", Task: "Lint it"},
+		{Name: "ship", Task: "Ship it", Needs: []string{"docs", "lint"}},
+	}}
+	if err := wf.validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var tasks []string
+	runner.step = func(wf *Workflow, step Step, finished map[string]*coordinator.Goblin) StepResult {
+		for _, need := range step.Needs {
+			if finished[need] == nil {
+				t.Errorf("%s started before %s finished", step.Name, need)
+			}
+		}
+		mu.Lock()
+		tasks = append(tasks, Expand(step.Task, finished))
+		mu.Unlock()
+
+		if step.Name == "lint" {
+			return StepResult{Step: step.Name, Status: StepFailed, Err: errors.New("still working")}
+		}
+		g := &coordinator.Goblin{Name: wf.GoblinName(step), Branch: "gforge/" + wf.GoblinName(step)}
+		return StepResult{Step: step.Name, Status: StepDone, Goblin: g}
+	}
+
+	results, err := runner.Run(wf)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := map[string]string{"docs": StepDone, "api": StepDone, "plan": StepDone, "lint": StepFailed, "ship": StepSkipped}
+	for i, res := range results {
+		if res.Step != wf.Steps[i].Name || res.Status != want[res.Step] {
+			t.Errorf("Expected %s %s, got %+v", wf.Steps[i].Name, want[wf.Steps[i].Name], res)
+		}
+	}
+	if len(tasks) != 4 {
+		t.Errorf("Expected 4 steps run, got %v", tasks)
+	}
+	for _, task := range tasks {
+		if task == "Document {api.branch}" {
+			t.Error("Expected docs sent the branch of api")
+		}
+	}
+}
+
+func TestRunExisting(t *testing.T) {
+	runner, db := setupRunner(t)
+	now := time.Now()
+	db.RestoreGoblin(&storage.Goblin{ID: "id-1", Name: "wf-plan", Agent: "claude", Status: "running",
+		ProjectPath: "/src/app", CreatedAt: now, UpdatedAt: now})
+
+	wf := &Workflow{Name: "wf", Steps: []Step{{Name: "plan", Task: "Plan it"}}}
+	if _, err := runner.Run(wf); err == nil {
+		t.Error("Expected a workflow whose goblin already exists refused")
+	}
+}
@@ -0,0 +1,241 @@
+// Package workflow runs pipelines of goblins: each step spawns a goblin
+// once the steps it needs have finished, starting from their work.
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTimeout     = 30 * time.Minute
+	defaultIdleTimeout = 60 * time.Second
+)
+
+// Workflow is a dependency graph of goblins run against one project
+type Workflow struct {
+	Name    string `yaml:"name"`
+	Project string `yaml:"project"`
+	Steps   []Step `yaml:"steps"`
+}
+
+// Step is one goblin of a workflow
+type Step struct {
+	Name  string   `yaml:"name"`
+	Agent string   `yaml:"agent"` // Empty for the project's or default agent
+	Task  string   `yaml:"task"`  // May use {step.branch}, {step.worktree}, {step.name}
+	Needs []string `yaml:"needs"` // Steps that must finish first
+
+	// From is the step whose branch this one starts from; the first of
+	// Needs when unset
+	From string `yaml:"from"`
+
+	Timeout     time.Duration `yaml:"timeout"`      // Hard limit for the agent
+	IdleTimeout time.Duration `yaml:"idle_timeout"` // Quiet period that counts as finished
+
+	// Artifacts are collected from the step's worktree once it is done,
+	// along with the project's
+	Artifacts []string `yaml:"artifacts"`
+}
+
+// placeholder matches {step.field} in a task
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9_-]+)\.(branch|worktree|name)\}`)
+
+// validName matches step and workflow names, which name goblins and
+// branches
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Load reads and validates a workflow file. The project is relative to
+// the file, and the workflow is named after it unless it says otherwise.
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+
+	var wf Workflow
+	if err := yaml.Unmarshal(data, &wf); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+
+	if wf.Name == "" {
+		wf.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if wf.Project == "" {
+		wf.Project = "."
+	}
+	if !filepath.IsAbs(wf.Project) {
+		wf.Project = filepath.Join(filepath.Dir(path), wf.Project)
+	}
+
+	if err := wf.validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %w", path, err)
+	}
+	return &wf, nil
+}
+
+// validate checks names, dependencies and placeholders, fills in
+// defaults and rejects cycles
+func (wf *Workflow) validate() error {
+	if !validName.MatchString(wf.Name) {
+		return fmt.Errorf("name %q: use letters, digits, - and _", wf.Name)
+	}
+	if len(wf.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+
+	steps := make(map[string]*Step)
+	for i := range wf.Steps {
+		step := &wf.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step-%d", i+1)
+		}
+		if !validName.MatchString(step.Name) {
+			return fmt.Errorf("step name %q: use letters, digits, - and _", step.Name)
+		}
+		if steps[step.Name] != nil {
+			return fmt.Errorf("duplicate step name: %s", step.Name)
+		}
+		steps[step.Name] = step
+
+		if step.Task == "" {
+			return fmt.Errorf("step %s has no task", step.Name)
+		}
+		if step.Timeout == 0 {
+			step.Timeout = defaultTimeout
+		}
+		if step.IdleTimeout == 0 {
+			step.IdleTimeout = defaultIdleTimeout
+		}
+	}
+
+	for i := range wf.Steps {
+		step := &wf.Steps[i]
+		for _, need := range step.Needs {
+			if steps[need] == nil {
+				return fmt.Errorf("step %s needs unknown step %s", step.Name, need)
+			}
+			if need == step.Name {
+				return fmt.Errorf("step %s needs itself", step.Name)
+			}
+		}
+		if step.From == "" && len(step.Needs) > 0 {
+			step.From = step.Needs[0]
+		}
+		if step.From != "" && !contains(step.Needs, step.From) {
+			return fmt.Errorf("step %s starts from %s, which it doesn't need", step.Name, step.From)
+		}
+	}
+
+	if _, err := wf.order(); err != nil {
+		return err
+	}
+
+	// Only steps finished before this one have a branch to refer to
+	for _, step := range wf.Steps {
+		before := wf.ancestors(step.Name)
+		for _, m := range placeholder.FindAllStringSubmatch(step.Task, -1) {
+			if !before[m[1]] {
+				return fmt.Errorf("step %s refers to %s, which doesn't finish before it", step.Name, m[0])
+			}
+		}
+	}
+	return nil
+}
+
+// order returns the steps with every step after those it needs, or an
+// error naming a step in a cycle
+func (wf *Workflow) order() ([]Step, error) {
+	placed := make(map[string]bool)
+	var ordered []Step
+	for len(ordered) < len(wf.Steps) {
+		progress := false
+		for _, step := range wf.Steps {
+			if placed[step.Name] || !allIn(step.Needs, placed) {
+				continue
+			}
+			placed[step.Name] = true
+			ordered = append(ordered, step)
+			progress = true
+		}
+		if !progress {
+			for _, step := range wf.Steps {
+				if !placed[step.Name] {
+					return nil, fmt.Errorf("steps depend on each other in a cycle, e.g. %s", step.Name)
+				}
+			}
+		}
+	}
+	return ordered, nil
+}
+
+// ancestors returns every step that must finish before the named one
+func (wf *Workflow) ancestors(name string) map[string]bool {
+	seen := make(map[string]bool)
+	var visit func(string)
+	visit = func(n string) {
+		for _, step := range wf.Steps {
+			if step.Name != n {
+				continue
+			}
+			for _, need := range step.Needs {
+				if !seen[need] {
+					seen[need] = true
+					visit(need)
+				}
+			}
+		}
+	}
+	visit(name)
+	return seen
+}
+
+// GoblinName returns the name of the goblin a step spawns
+func (wf *Workflow) GoblinName(step Step) string {
+	return wf.Name + "-" + step.Name
+}
+
+// Expand fills in a task's placeholders from the goblins of finished
+// steps
+func Expand(task string, finished map[string]*coordinator.Goblin) string {
+	return placeholder.ReplaceAllStringFunc(task, func(m string) string {
+		parts := placeholder.FindStringSubmatch(m)
+		g := finished[parts[1]]
+		if g == nil {
+			return m
+		}
+		switch parts[2] {
+		case "branch":
+			return g.Branch
+		case "worktree":
+			return g.WorktreePath
+		default:
+			return g.Name
+		}
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func allIn(names []string, set map[string]bool) bool {
+	for _, n := range names {
+		if !set[n] {
+			return false
+		}
+	}
+	return true
+}
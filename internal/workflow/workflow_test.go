@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/coordinator"
+)
+
+func writeWorkflow(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeWorkflow(t, "release.yaml", `
+project: app
+steps:
+  - name: plan
+    task: Plan the release
+  - name: backend
+    task: Implement the plan in {plan.worktree}
+    needs: [plan]
+    timeout: 1h
+  - task: Write docs
+    needs: [plan, backend]
+    from: backend
+`)
+
+	wf, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if wf.Name != "release" {
+		t.Errorf("Expected the workflow named after its file, got %s", wf.Name)
+	}
+	if want := filepath.Join(filepath.Dir(path), "app"); wf.Project != want {
+		t.Errorf("Expected project %s, got %s", want, wf.Project)
+	}
+
+	plan, backend, docs := wf.Steps[0], wf.Steps[1], wf.Steps[2]
+	if plan.Timeout != defaultTimeout || plan.IdleTimeout != defaultIdleTimeout || plan.From != "" {
+		t.Errorf("Expected plan to get default timeouts and start from the base, got %+v", plan)
+	}
+	if backend.From != "plan" || backend.Timeout != time.Hour {
+		t.Errorf("Expected backend from plan with a 1h timeout, got %+v", backend)
+	}
+	if docs.Name != "step-3" || docs.From != "backend" {
+		t.Errorf("Expected step-3 from backend, got %+v", docs)
+	}
+	if name := wf.GoblinName(docs); name != "release-step-3" {
+		t.Errorf("Expected goblin release-step-3, got %s", name)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no steps":  `name: empty`,
+		"bad name":  "name: my release\nsteps:\n  - task: x",
+		"no task":   "steps:\n  - name: a",
+		"duplicate": "steps:\n  - {name: a, task: x}\n  - {name: a, task: y}",
+		"unknown":   "steps:\n  - {name: a, task: x, needs: [b]}",
+		"self":      "steps:\n  - {name: a, task: x, needs: [a]}",
+		"from":      "steps:\n  - {name: a, task: x}\n  - {name: b, task: y}\n  - {name: c, task: z, needs: [a], from: b}",
+		"cycle":     "steps:\n  - {name: a, task: x, needs: [b]}\n  - {name: b, task: y, needs: [a]}",
+		"sibling":   "steps:\n  - {name: a, task: x}\n  - {name: b, task: 'Review {a.branch}'}",
+		"yaml":      "steps: [",
+	}
+
+	for name, content := range tests {
+		if _, err := Load(writeWorkflow(t, "wf.yaml", content)); err == nil {
+			t.Errorf("%s: expected the workflow refused", name)
+		}
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected a missing file reported")
+	}
+}
+
+func TestOrder(t *testing.T) {
+	wf := &Workflow{Name: "wf", Steps: []Step{
+		{Name: "docs", Task: "x", Needs: []string{"api"}},
+		{Name: "api", Task: "x", Needs: []string{"plan"}},
+		{Name: "plan", Task: "x"},
+	}}
+	if err := wf.validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+
+	steps, _ := wf.order()
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+	if got := strings.Join(names, ","); got != "plan,api,docs" {
+		t.Errorf("Expected plan,api,docs, got %s", got)
+	}
+	if before := wf.ancestors("docs"); !before["api"] || !before["plan"] || len(before) != 2 {
+		t.Errorf("Expected docs after api and plan, got %v", before)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	finished := map[string]*coordinator.Goblin{
+		"plan": {Name: "wf-plan", Branch: "gforge/wf-plan", WorktreePath: "/wt/wf-plan"},
+	}
+
+	got := Expand("Read {plan.worktree}/PLAN.md, merge {plan.branch} from {plan.name}; ignore {docs.branch}", finished)
+	want := "Read /wt/wf-plan/PLAN.md, merge gforge/wf-plan from wf-plan; ignore {docs.branch}"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
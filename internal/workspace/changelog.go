@@ -0,0 +1,244 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Changelog formats gforge can add entries to
+const (
+	ChangelogKeepAChangelog = "keepachangelog"
+	ChangelogTowncrier      = "towncrier"
+)
+
+// ChangelogEntry is one change to record
+type ChangelogEntry struct {
+	Type string // keepachangelog section or towncrier fragment type
+	Text string
+	Name string // Fragment file name (towncrier)
+}
+
+// changeKind is a kind of change as each format names it
+type changeKind struct {
+	section  string // keepachangelog
+	fragment string // towncrier
+}
+
+var (
+	kindAdded      = changeKind{"Added", "feature"}
+	kindChanged    = changeKind{"Changed", "misc"}
+	kindDeprecated = changeKind{"Deprecated", "removal"}
+	kindRemoved    = changeKind{"Removed", "removal"}
+	kindFixed      = changeKind{"Fixed", "bugfix"}
+	kindSecurity   = changeKind{"Security", "bugfix"}
+	kindDocs       = changeKind{"Changed", "doc"}
+)
+
+// commitKinds read the kind of change from a conventional commit type or
+// the verb a subject starts with
+var commitKinds = map[string]changeKind{
+	"feat": kindAdded, "add": kindAdded, "implement": kindAdded, "introduce": kindAdded,
+	"fix": kindFixed, "bugfix": kindFixed,
+	"remove": kindRemoved, "delete": kindRemoved, "drop": kindRemoved,
+	"deprecate": kindDeprecated, "security": kindSecurity,
+	"docs": kindDocs, "doc": kindDocs,
+	"change": kindChanged, "update": kindChanged, "refactor": kindChanged, "perf": kindChanged,
+}
+
+var conventionalCommit = regexp.MustCompile(`^([a-zA-Z]+)(?:\([^)]*\))?!?:\s*(.+)$`)
+
+// NewChangelogEntry describes a goblin's work for a changelog: the first
+// line of its first task, else its first commit, filed under the kind of
+// change its commits name. Commits that don't say get defaultType.
+func NewChangelogEntry(format, defaultType, name string, tasks, commits []string) ChangelogEntry {
+	e := ChangelogEntry{Name: name}
+
+	for _, commit := range commits {
+		word, _ := commitKind(commit)
+		if kind, ok := commitKinds[word]; ok {
+			e.Type = kind.section
+			if format == ChangelogTowncrier {
+				e.Type = kind.fragment
+			}
+			break
+		}
+	}
+	if e.Type == "" {
+		e.Type = defaultType
+	}
+	if e.Type == "" {
+		e.Type = kindChanged.section
+		if format == ChangelogTowncrier {
+			e.Type = kindChanged.fragment
+		}
+	}
+
+	switch {
+	case len(tasks) > 0:
+		e.Text, _, _ = strings.Cut(strings.TrimSpace(tasks[0]), "\n")
+	case len(commits) > 0:
+		_, e.Text = commitKind(commits[0])
+	default:
+		e.Text = name
+	}
+	e.Text = strings.TrimSpace(e.Text)
+	if e.Text != "" && !strings.HasSuffix(e.Text, ".") {
+		e.Text += "."
+	}
+	return e
+}
+
+// commitKind splits a commit subject into the lowercased word naming its
+// kind and the subject without a conventional commit prefix
+func commitKind(subject string) (string, string) {
+	if m := conventionalCommit.FindStringSubmatch(subject); m != nil {
+		return strings.ToLower(m[1]), m[2]
+	}
+	word, _, _ := strings.Cut(subject, " ")
+	word = strings.ToLower(word)
+	if _, ok := commitKinds[word]; ok {
+		return word, subject
+	}
+	// "Adds", "Fixed" and the like
+	for _, suffix := range []string{"es", "ed", "s", "d"} {
+		if _, ok := commitKinds[strings.TrimSuffix(word, suffix)]; ok && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix), subject
+		}
+	}
+	return word, subject
+}
+
+// AddChangelogEntry records an entry in a worktree's changelog, at path
+// (CHANGELOG.md, or changelog.d for towncrier, when empty). It returns the
+// file written, relative to the worktree, or "" when the entry is already
+// there.
+func AddChangelogEntry(worktreePath, format, path string, e ChangelogEntry) (string, error) {
+	switch format {
+	case ChangelogKeepAChangelog:
+		if path == "" {
+			path = "CHANGELOG.md"
+		}
+		file := filepath.Join(worktreePath, path)
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		content := string(data)
+		if content == "" {
+			content = keepAChangelogHeader
+		}
+		updated := InsertKeepAChangelog(content, e)
+		if updated == content {
+			return "", nil
+		}
+		if err := os.WriteFile(file, []byte(updated), 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return path, nil
+
+	case ChangelogTowncrier:
+		if path == "" {
+			path = "changelog.d"
+		}
+		fragment := filepath.Join(path, fmt.Sprintf("%s.%s.md", e.Name, e.Type))
+		file := filepath.Join(worktreePath, fragment)
+		if _, err := os.Stat(file); err == nil {
+			return "", nil
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		if err := os.WriteFile(file, []byte(e.Text+"\n"), 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", fragment, err)
+		}
+		return fragment, nil
+	}
+	return "", fmt.Errorf("unknown changelog format: %s (use %s or %s)", format, ChangelogKeepAChangelog, ChangelogTowncrier)
+}
+
+const keepAChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+`
+
+// InsertKeepAChangelog adds an entry to the Unreleased section of a Keep a
+// Changelog file, creating the section and its subsection as needed.
+// Content already holding the entry is returned as it was.
+func InsertKeepAChangelog(content string, e ChangelogEntry) string {
+	bullet := "- " + e.Text
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	unreleased := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") && strings.Contains(strings.ToLower(line), "unreleased") {
+			unreleased = i
+			break
+		}
+	}
+	if unreleased < 0 {
+		// Above the first release, or at the end of a new changelog
+		at := len(lines)
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				at = i
+				break
+			}
+		}
+		if at < len(lines) {
+			lines = insertLines(lines, at, "## [Unreleased]", "")
+			unreleased = at
+		} else {
+			lines = append(lines, "", "## [Unreleased]")
+			unreleased = len(lines) - 1
+		}
+	}
+
+	end := len(lines)
+	subsection := -1
+	for i := unreleased + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			end = i
+			break
+		}
+		if lines[i] == bullet {
+			return content
+		}
+		if subsection < 0 && strings.HasPrefix(lines[i], "### ") &&
+			strings.EqualFold(strings.TrimSpace(lines[i][4:]), e.Type) {
+			subsection = i
+		}
+	}
+
+	if subsection < 0 {
+		// A new subsection goes first, right under the Unreleased heading
+		lines = insertLines(lines, unreleased+1, "", "### "+e.Type, "", bullet)
+		if unreleased+5 < len(lines) && lines[unreleased+5] != "" {
+			lines = insertLines(lines, unreleased+5, "")
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	// After the subsection's last item
+	at := subsection + 1
+	for at < end && lines[at] == "" {
+		at++
+	}
+	for at < end && (strings.HasPrefix(lines[at], "- ") || strings.HasPrefix(lines[at], "* ") || strings.HasPrefix(lines[at], "  ")) {
+		at++
+	}
+	lines = insertLines(lines, at, bullet)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// insertLines inserts lines into s before index at
+func insertLines(s []string, at int, lines ...string) []string {
+	out := make([]string, 0, len(s)+len(lines))
+	out = append(out, s[:at]...)
+	out = append(out, lines...)
+	return append(out, s[at:]...)
+}
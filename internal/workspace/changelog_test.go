@@ -0,0 +1,134 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewChangelogEntry(t *testing.T) {
+	tests := []struct {
+		format  string
+		tasks   []string
+		commits []string
+		want    ChangelogEntry
+	}{
+		{ChangelogKeepAChangelog, []string{"Retry failed uploads\n\nUse backoff."}, []string{"feat(upload): retry"},
+			ChangelogEntry{Type: "Added", Text: "Retry failed uploads.", Name: "g"}},
+		{ChangelogKeepAChangelog, nil, []string{"Fixed the login crash", "Add test"},
+			ChangelogEntry{Type: "Fixed", Text: "Fixed the login crash.", Name: "g"}},
+		{ChangelogTowncrier, nil, []string{"fix: handle empty input."},
+			ChangelogEntry{Type: "bugfix", Text: "handle empty input.", Name: "g"}},
+		{ChangelogTowncrier, nil, []string{"Tidy up"},
+			ChangelogEntry{Type: "misc", Text: "Tidy up.", Name: "g"}},
+	}
+	for _, tt := range tests {
+		if got := NewChangelogEntry(tt.format, "", "g", tt.tasks, tt.commits); got != tt.want {
+			t.Errorf("NewChangelogEntry(%v) = %+v, want %+v", tt.commits, got, tt.want)
+		}
+	}
+
+	if e := NewChangelogEntry(ChangelogKeepAChangelog, "Security", "g", nil, []string{"Tidy up"}); e.Type != "Security" {
+		t.Errorf("Expected the project's default type, got %s", e.Type)
+	}
+}
+
+func TestInsertKeepAChangelog(t *testing.T) {
+	changelog := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Crash on start.
+
+## [1.0.0] - 2026-01-01
+
+### Added
+
+- First release.
+`
+
+	got := InsertKeepAChangelog(changelog, ChangelogEntry{Type: "Fixed", Text: "Login loop."})
+	want := `# Changelog
+
+## [Unreleased]
+
+### Fixed
+
+- Crash on start.
+- Login loop.
+
+## [1.0.0] - 2026-01-01
+
+### Added
+
+- First release.
+`
+	if got != want {
+		t.Errorf("Unexpected changelog:\n%s", got)
+	}
+	if again := InsertKeepAChangelog(got, ChangelogEntry{Type: "Fixed", Text: "Login loop."}); again != got {
+		t.Error("Expected an entry already there left alone")
+	}
+
+	got = InsertKeepAChangelog(changelog, ChangelogEntry{Type: "Added", Text: "Dark mode."})
+	want = `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Dark mode.
+
+### Fixed
+
+- Crash on start.
+
+## [1.0.0] - 2026-01-01
+
+### Added
+
+- First release.
+`
+	if got != want {
+		t.Errorf("Unexpected changelog:\n%s", got)
+	}
+
+	// Without an Unreleased section, one goes above the latest release
+	got = InsertKeepAChangelog("# Changelog\n\n## [1.0.0]\n\n- First.\n", ChangelogEntry{Type: "Added", Text: "Dark mode."})
+	want = "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Dark mode.\n\n## [1.0.0]\n\n- First.\n"
+	if got != want {
+		t.Errorf("Unexpected changelog:\n%s", got)
+	}
+}
+
+func TestAddChangelogEntry(t *testing.T) {
+	dir := t.TempDir()
+	entry := ChangelogEntry{Type: "feature", Text: "Dark mode.", Name: "theming"}
+
+	path, err := AddChangelogEntry(dir, ChangelogTowncrier, "", entry)
+	if err != nil || path != filepath.Join("changelog.d", "theming.feature.md") {
+		t.Fatalf("Expected a towncrier fragment, got %q, %v", path, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, path)); string(data) != "Dark mode.\n" {
+		t.Errorf("Unexpected fragment %q", data)
+	}
+	if path, err := AddChangelogEntry(dir, ChangelogTowncrier, "", entry); err != nil || path != "" {
+		t.Errorf("Expected the existing fragment kept, got %q, %v", path, err)
+	}
+
+	entry.Type = "Added"
+	path, err = AddChangelogEntry(dir, ChangelogKeepAChangelog, "", entry)
+	if err != nil || path != "CHANGELOG.md" {
+		t.Fatalf("Expected a new CHANGELOG.md, got %q, %v", path, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, path))
+	if string(data) != keepAChangelogHeader+"\n## [Unreleased]\n\n### Added\n\n- Dark mode.\n" {
+		t.Errorf("Unexpected changelog:\n%s", data)
+	}
+
+	if _, err := AddChangelogEntry(dir, "news", "", entry); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,92 @@
+package workspace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// Checkpoint is a commit on a goblin's branch: the agent's own commits,
+// the checkpoints taken when gforge shuts down and gforge done's last one
+type Checkpoint struct {
+	Commit  string
+	Subject string
+	Time    time.Time // Committer time
+}
+
+// Short returns the checkpoint's abbreviated commit hash
+func (c Checkpoint) Short() string {
+	if len(c.Commit) > 8 {
+		return c.Commit[:8]
+	}
+	return c.Commit
+}
+
+// BranchCheckpoints lists the commits on a worktree's branch since base,
+// or since its upstream when base is empty, oldest first
+func BranchCheckpoints(worktreePath, base string) ([]Checkpoint, error) {
+	base, err := branchBase(worktreePath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := trace.Command("git", "-C", worktreePath, "log", "--reverse", "--format=%H%x00%ct%x00%s", base+"..HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	var checkpoints []Checkpoint
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(fields[1], 10, 64)
+		checkpoints = append(checkpoints, Checkpoint{Commit: fields[0], Subject: fields[2], Time: time.Unix(unix, 0)})
+	}
+	return checkpoints, nil
+}
+
+// CheckpointAt returns the last of checkpoints committed at or before t,
+// which is what the branch held then. It reports false when t is before
+// the first one.
+func CheckpointAt(checkpoints []Checkpoint, t time.Time) (Checkpoint, bool) {
+	var at Checkpoint
+	found := false
+	for _, c := range checkpoints {
+		if c.Time.After(t) {
+			break
+		}
+		at, found = c, true
+	}
+	return at, found
+}
+
+// ResolveCommit returns the full hash of a revision in a worktree
+func ResolveCommit(worktreePath, rev string) (string, error) {
+	out, err := trace.Command("git", "-C", worktreePath, "rev-parse", "--verify", "-q", rev+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("no commit %s in %s", rev, worktreePath)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// DiffBetween returns the changes from one commit to another, or to the
+// worktree as it is now, uncommitted changes to tracked files included,
+// when to is empty. Ignored files are left out.
+func DiffBetween(worktreePath, from, to string, ignore Ignore) (string, error) {
+	args := []string{"-C", worktreePath, "diff", "--submodule=diff", from}
+	if to != "" {
+		args = append(args, to)
+	}
+	if specs := ignore.Pathspecs(); specs != nil {
+		args = append(append(args, "--"), specs...)
+	}
+	output, err := trace.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return string(output), nil
+}
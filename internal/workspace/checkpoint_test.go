@@ -0,0 +1,66 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBranchCheckpoints(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	out, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	base := strings.TrimSpace(string(out))
+
+	// Two commits an hour apart
+	for i, name := range []string{"one.txt", "two.txt"} {
+		os.WriteFile(filepath.Join(repo, name), []byte(name+"\n"), 0644)
+		exec.Command("git", "-C", repo, "add", name).Run()
+		cmd := exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add "+name)
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+time.Date(2026, 1, 2, 10+i, 0, 0, 0, time.UTC).Format(time.RFC3339))
+		cmd.Run()
+	}
+	os.WriteFile(filepath.Join(repo, "wip.txt"), []byte("wip\n"), 0644)
+	exec.Command("git", "-C", repo, "add", "wip.txt").Run()
+
+	checkpoints, err := BranchCheckpoints(repo, base)
+	if err != nil || len(checkpoints) != 2 {
+		t.Fatalf("Expected 2 checkpoints, got %v, %v", checkpoints, err)
+	}
+	if checkpoints[0].Subject != "Add one.txt" || len(checkpoints[0].Short()) != 8 {
+		t.Errorf("Unexpected first checkpoint %+v", checkpoints[0])
+	}
+
+	if _, ok := CheckpointAt(checkpoints, time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)); ok {
+		t.Error("Expected no checkpoint before the first commit")
+	}
+	at, ok := CheckpointAt(checkpoints, time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC))
+	if !ok || at.Commit != checkpoints[0].Commit {
+		t.Errorf("Expected the first checkpoint at 10:30, got %+v", at)
+	}
+
+	// Between the two checkpoints only two.txt was added
+	diff, err := DiffBetween(repo, checkpoints[0].Commit, checkpoints[1].Commit, nil)
+	if err != nil || !strings.Contains(diff, "two.txt") || strings.Contains(diff, "one.txt") {
+		t.Errorf("Unexpected diff between checkpoints: %q, %v", diff, err)
+	}
+	// To the worktree picks up uncommitted work too
+	diff, _ = DiffBetween(repo, checkpoints[1].Commit, "", nil)
+	if !strings.Contains(diff, "wip.txt") {
+		t.Errorf("Expected the uncommitted file in the diff, got %q", diff)
+	}
+
+	if commit, err := ResolveCommit(repo, checkpoints[0].Short()); err != nil || commit != checkpoints[0].Commit {
+		t.Errorf("ResolveCommit() = %s, %v", commit, err)
+	}
+	if _, err := ResolveCommit(repo, "no-such-rev"); err == nil {
+		t.Error("Expected an unknown revision to fail")
+	}
+}
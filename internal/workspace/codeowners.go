@@ -0,0 +1,203 @@
+package workspace
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CodeOwnersFiles are where GitHub looks for a CODEOWNERS file, in the
+// order it looks
+var CodeOwnersFiles = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwnerRule gives the files matching a pattern to its owners. A rule
+// without owners leaves its files unowned.
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []string // @user, @org/team or an email address
+
+	re *regexp.Regexp
+}
+
+// CodeOwners is a parsed CODEOWNERS file
+type CodeOwners struct {
+	Path  string // Relative to the repository root
+	Rules []CodeOwnerRule
+}
+
+// LoadCodeOwners reads the CODEOWNERS file of the repository at root,
+// returning nil when it has none
+func LoadCodeOwners(root string) (*CodeOwners, error) {
+	for _, name := range CodeOwnersFiles {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		co := ParseCodeOwners(string(data))
+		co.Path = name
+		return co, nil
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses CODEOWNERS content. Lines that don't make a valid
+// pattern are skipped, as GitHub does.
+func ParseCodeOwners(content string) *CodeOwners {
+	co := &CodeOwners{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		re, err := ownerPattern(fields[0])
+		if err != nil {
+			continue
+		}
+		co.Rules = append(co.Rules, CodeOwnerRule{Pattern: fields[0], Owners: fields[1:], re: re})
+	}
+	return co
+}
+
+// ownerPattern compiles a CODEOWNERS pattern, which follows gitignore:
+// a pattern with no slash but a trailing one matches at any depth, "/"
+// anchors it at the root, and a match on a directory covers everything
+// under it
+func ownerPattern(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*"):
+		// GitHub reads "docs/*" as the files directly in docs only
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// Owners returns the owners of a file, from the last rule matching it.
+// A directory is given with a trailing slash.
+func (co *CodeOwners) Owners(file string) []string {
+	file = strings.TrimPrefix(filepath.ToSlash(file), "/")
+	for i := len(co.Rules) - 1; i >= 0; i-- {
+		if co.Rules[i].re.MatchString(file) {
+			return co.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnersOf returns everyone owning any of the files, sorted
+func (co *CodeOwners) OwnersOf(files []string) []string {
+	seen := make(map[string]bool)
+	var owners []string
+	for _, file := range files {
+		for _, owner := range co.Owners(file) {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// NeedingSignoff maps each path owned by one of the signoff owners to
+// those owners
+func (co *CodeOwners) NeedingSignoff(paths, signoff []string) map[string][]string {
+	required := make(map[string]bool)
+	for _, owner := range signoff {
+		required[strings.ToLower(owner)] = true
+	}
+
+	found := make(map[string][]string)
+	for _, path := range paths {
+		for _, owner := range co.Owners(path) {
+			if required[strings.ToLower(owner)] {
+				found[path] = append(found[path], owner)
+			}
+		}
+	}
+	return found
+}
+
+// TaskPaths picks out the words of a task that name files or directories
+// in the repository at root, directories with a trailing slash
+func TaskPaths(root, task string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, word := range PathWords(task) {
+		info, err := os.Stat(filepath.Join(root, word))
+		if err != nil {
+			continue
+		}
+		if info.IsDir() && !strings.HasSuffix(word, "/") {
+			word += "/"
+		}
+		if !seen[word] {
+			seen[word] = true
+			paths = append(paths, word)
+		}
+	}
+	return paths
+}
+
+// PathWords picks out the words of a task that look like relative paths
+// (they contain a slash or a dot), whether or not they exist yet
+func PathWords(task string) []string {
+	var words []string
+	for _, word := range strings.Fields(task) {
+		word = strings.Trim(word, "`'\"()[]{},;:!?")
+		word = strings.TrimSuffix(strings.TrimPrefix(word, "./"), ".")
+		if word == "" || !strings.ContainsAny(word, "/.") || strings.Contains(word, "..") || filepath.IsAbs(word) {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCodeOwners = `# Default owners
+*                   @acme/core
+
+*.md                docs@acme.com # Docs team
+/internal/auth/     @acme/security @alice
+docs/*              @acme/writers
+**/migrations       @acme/dba
+/vendor/
+`
+
+func TestCodeOwnersOwners(t *testing.T) {
+	co := ParseCodeOwners(testCodeOwners)
+	if len(co.Rules) != 6 {
+		t.Fatalf("Expected 6 rules, got %d", len(co.Rules))
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"main.go", "@acme/core"},
+		{"internal/README.md", "docs@acme.com"},
+		{"internal/auth/token.go", "@acme/security,@alice"},
+		{"internal/auth/", "@acme/security,@alice"},
+		{"pkg/internal/auth/token.go", "@acme/core"},
+		{"docs/guide.md", "@acme/writers"},
+		{"docs/api/index.md", "docs@acme.com"},
+		{"db/migrations/001.sql", "@acme/dba"},
+		{"vendor/lib/lib.go", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(co.Owners(tt.file), ","); got != tt.want {
+			t.Errorf("Owners(%s) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+
+	owners := co.OwnersOf([]string{"main.go", "internal/auth/token.go", "cmd/main.go"})
+	if strings.Join(owners, ",") != "@acme/core,@acme/security,@alice" {
+		t.Errorf("Unexpected owners %v", owners)
+	}
+}
+
+func TestNeedingSignoff(t *testing.T) {
+	co := ParseCodeOwners(testCodeOwners)
+	found := co.NeedingSignoff([]string{"main.go", "internal/auth/", "docs/guide.md"}, []string{"@Acme/Security"})
+	if len(found) != 1 || strings.Join(found["internal/auth/"], ",") != "@acme/security" {
+		t.Errorf("Expected internal/auth/ to need security signoff, got %v", found)
+	}
+}
+
+func TestLoadCodeOwners(t *testing.T) {
+	dir := t.TempDir()
+	if co, err := LoadCodeOwners(dir); err != nil || co != nil {
+		t.Errorf("Expected nothing without a CODEOWNERS file, got %v, %v", co, err)
+	}
+
+	os.MkdirAll(filepath.Join(dir, "docs"), 0755)
+	os.WriteFile(filepath.Join(dir, "docs", "CODEOWNERS"), []byte("* @docs\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, ".github"), 0755)
+	os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("* @github\n"), 0644)
+
+	co, err := LoadCodeOwners(dir)
+	if err != nil || co == nil {
+		t.Fatalf("LoadCodeOwners failed: %v", err)
+	}
+	if co.Path != ".github/CODEOWNERS" || co.Owners("x.go")[0] != "@github" {
+		t.Errorf("Expected .github/CODEOWNERS to win, got %s", co.Path)
+	}
+}
+
+func TestTaskPaths(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "internal", "auth"), 0755)
+	os.WriteFile(filepath.Join(dir, "main.go"), nil, 0644)
+
+	paths := TaskPaths(dir, "Refactor `internal/auth` and main.go. Leave ./cmd/ and the README alone.")
+	if strings.Join(paths, ",") != "internal/auth/,main.go" {
+		t.Errorf("Unexpected task paths %v", paths)
+	}
+}
+
+func TestPathWords(t *testing.T) {
+	words := PathWords("Create `internal/auth/token.go`, then update go.mod. Skip ../secrets and /etc/hosts.")
+	if strings.Join(words, ",") != "internal/auth/token.go,go.mod" {
+		t.Errorf("Unexpected path words %v", words)
+	}
+}
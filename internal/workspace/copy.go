@@ -0,0 +1,99 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// How CopyTree copies files (git.copy_method)
+const (
+	CopyAuto    = "auto"    // Copy-on-write clones where the filesystem supports them
+	CopyReflink = "reflink" // Copy-on-write clones only; fail elsewhere
+	CopyPlain   = "copy"    // Full copies with rsync (or cp)
+)
+
+// CopyTree copies the contents of src into dst. On btrfs, XFS, ZFS and
+// APFS clones share blocks until modified, so even multi-GB trees copy
+// almost instantly and use no extra space.
+func CopyTree(src, dst, method string) error {
+	src = strings.TrimSuffix(src, "/")
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	switch method {
+	case CopyPlain:
+		return plainCopy(src, dst)
+	case CopyReflink:
+		return run(reflinkCommand(src, dst, true))
+	case CopyAuto, "":
+		if err := run(reflinkCommand(src, dst, false)); err == nil {
+			return nil
+		}
+		// A partial clone is replaced by a full copy
+		os.RemoveAll(dst)
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			return err
+		}
+		return plainCopy(src, dst)
+	default:
+		return fmt.Errorf("unknown copy method: %s (use auto, reflink or copy)", method)
+	}
+}
+
+// reflinkCommand clones src into dst. GNU cp falls back to a normal copy
+// by itself unless strict; macOS cp -c fails off APFS either way.
+func reflinkCommand(src, dst string, strict bool) *exec.Cmd {
+	if runtime.GOOS == "darwin" {
+		return exec.Command("cp", "-c", "-R", src+"/.", dst)
+	}
+	mode := "auto"
+	if strict {
+		mode = "always"
+	}
+	return exec.Command("cp", "-a", "--reflink="+mode, src+"/.", dst)
+}
+
+// plainCopy copies src into dst, preferring rsync and falling back to cp
+func plainCopy(src, dst string) error {
+	if _, err := exec.LookPath("rsync"); err == nil {
+		return run(exec.Command("rsync", "-a", src+"/", dst+"/"))
+	}
+	return run(exec.Command("cp", "-a", src+"/.", dst))
+}
+
+// run runs cmd, folding its output into the error
+func run(cmd *exec.Cmd) error {
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CopyPath copies a file or directory from src to dst, cloning where the
+// filesystem allows
+func CopyPath(src, dst, method string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return CopyTree(src, dst, method)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if runtime.GOOS != "darwin" && method != CopyPlain {
+		mode := "auto"
+		if method == CopyReflink {
+			mode = "always"
+		}
+		return run(exec.Command("cp", "-a", "--reflink="+mode, src, dst))
+	}
+	return run(exec.Command("cp", "-p", src, dst))
+}
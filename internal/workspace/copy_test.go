@@ -0,0 +1,50 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyTree(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "assets", "img"), 0755)
+	os.WriteFile(filepath.Join(src, "assets", "img", "logo.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(src, ".env"), []byte("KEY=1\n"), 0600)
+
+	for _, method := range []string{CopyAuto, CopyPlain} {
+		dst := filepath.Join(t.TempDir(), "copy")
+		if err := CopyTree(src, dst, method); err != nil {
+			t.Fatalf("CopyTree(%s) failed: %v", method, err)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dst, "assets", "img", "logo.png")); string(data) != "png" {
+			t.Errorf("Expected nested files copied with %s, got %q", method, data)
+		}
+		if info, err := os.Stat(filepath.Join(dst, ".env")); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("Expected dotfiles copied with their mode using %s: %v", method, err)
+		}
+
+		// Copies are independent of the source
+		os.WriteFile(filepath.Join(dst, ".env"), []byte("KEY=2\n"), 0600)
+		if data, _ := os.ReadFile(filepath.Join(src, ".env")); string(data) != "KEY=1\n" {
+			t.Errorf("Expected the source untouched, got %q", data)
+		}
+	}
+
+	if err := CopyTree(src, t.TempDir(), "symlink"); err == nil {
+		t.Error("Expected an unknown method rejected")
+	}
+}
+
+func TestCopyPath(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "schema.json"), []byte("{}"), 0644)
+
+	dst := filepath.Join(t.TempDir(), "gen", "schema.json")
+	if err := CopyPath(filepath.Join(src, "schema.json"), dst, CopyAuto); err != nil {
+		t.Fatalf("CopyPath failed: %v", err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "{}" {
+		t.Errorf("Expected the file copied, got %q", data)
+	}
+}
@@ -0,0 +1,310 @@
+package workspace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// DepChange is a dependency added, removed or moved to another version in
+// a manifest. Old is empty for an added dependency and New for a removed
+// one.
+type DepChange struct {
+	Manifest string
+	Name     string
+	Old      string
+	New      string
+}
+
+func (d DepChange) String() string {
+	switch {
+	case d.Old == "":
+		return fmt.Sprintf("+ %s %s", d.Name, d.New)
+	case d.New == "":
+		return fmt.Sprintf("- %s %s", d.Name, d.Old)
+	}
+	return fmt.Sprintf("~ %s %s -> %s", d.Name, d.Old, d.New)
+}
+
+// LicenseChange is an added file that carries a license: a LICENSE,
+// COPYING or NOTICE file, or source with an SPDX identifier
+type LicenseChange struct {
+	File    string
+	License string // SPDX identifier, or "unknown"
+}
+
+// DependencyReport is what a branch changes in dependency manifests and
+// licenses
+type DependencyReport struct {
+	Manifests []string // Manifests the branch modifies
+	Deps      []DepChange
+	Licenses  []LicenseChange
+}
+
+// Empty reports whether the branch leaves dependencies and licenses alone
+func (r *DependencyReport) Empty() bool {
+	return len(r.Manifests) == 0 && len(r.Licenses) == 0
+}
+
+// Summary is a one-line account of the report, for events
+func (r *DependencyReport) Summary() string {
+	var parts []string
+	if n := len(r.Deps); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d dependency change(s) in %s", n, strings.Join(r.Manifests, ", ")))
+	} else if len(r.Manifests) > 0 {
+		parts = append(parts, "modifies "+strings.Join(r.Manifests, ", "))
+	}
+	if n := len(r.Licenses); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d new license file(s)", n))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// manifestParsers read the dependencies of the manifests gforge
+// understands, by file name. Other manifests in dependencyManifests are
+// reported as modified without a breakdown.
+var manifestParsers = map[string]func(string) map[string]string{
+	"go.mod":           parseGoMod,
+	"package.json":     parsePackageJSON,
+	"requirements.txt": parseRequirements,
+	"Cargo.toml":       parseCargoToml,
+}
+
+var dependencyManifests = map[string]bool{
+	"go.mod": true, "package.json": true, "requirements.txt": true, "Cargo.toml": true,
+	"pyproject.toml": true, "Pipfile": true, "Gemfile": true, "composer.json": true,
+	"pom.xml": true, "build.gradle": true, "build.gradle.kts": true, "mix.exs": true,
+}
+
+// IsDependencyManifest reports whether a file lists a project's
+// dependencies
+func IsDependencyManifest(file string) bool {
+	return dependencyManifests[manifestName(file)]
+}
+
+// manifestName is a manifest's file name, with pip's requirements-dev.txt
+// and the like read as requirements.txt
+func manifestName(file string) string {
+	base := path.Base(file)
+	if strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt") {
+		return "requirements.txt"
+	}
+	return base
+}
+
+var licenseFile = regexp.MustCompile(`(?i)^(LICEN[CS]E|COPYING|NOTICE)([.-].*)?$`)
+
+var spdxIdentifier = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+() -]+?)\s*(?:\*/|-->)?\s*$`)
+
+// licenseMarkers recognize common licenses from their text
+var licenseMarkers = []struct {
+	id     string
+	marker string
+}{
+	{"AGPL-3.0", "GNU AFFERO GENERAL PUBLIC LICENSE"},
+	{"LGPL", "GNU LESSER GENERAL PUBLIC LICENSE"},
+	{"GPL", "GNU GENERAL PUBLIC LICENSE"},
+	{"MPL-2.0", "Mozilla Public License"},
+	{"Apache-2.0", "Apache License"},
+	{"MIT", "Permission is hereby granted, free of charge"},
+	{"BSD", "Redistribution and use in source and binary forms"},
+	{"ISC", "Permission to use, copy, modify, and/or distribute this software"},
+	{"Unlicense", "This is free and unencumbered software"},
+}
+
+// DetectDependencyChanges reports the dependency manifests and licenses
+// changed by the commits on a worktree's branch since base, or since its
+// upstream when base is empty
+func DetectDependencyChanges(worktreePath, base string) (*DependencyReport, error) {
+	base, err := branchBase(worktreePath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := trace.Command("git", "-C", worktreePath, "diff", "--name-status", "--no-renames", base, "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+
+	report := &DependencyReport{}
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		status, file := fields[0], fields[1]
+
+		if IsDependencyManifest(file) {
+			report.Manifests = append(report.Manifests, file)
+			if parse := manifestParsers[manifestName(file)]; parse != nil {
+				before := parse(showFile(worktreePath, base, file))
+				after := parse(showFile(worktreePath, "HEAD", file))
+				report.Deps = append(report.Deps, diffDeps(file, before, after)...)
+			}
+		}
+
+		if status == "A" {
+			if license := detectLicense(file, showFile(worktreePath, "HEAD", file)); license != "" {
+				report.Licenses = append(report.Licenses, LicenseChange{File: file, License: license})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// showFile returns a file's content at rev, or "" where it doesn't exist
+func showFile(worktreePath, rev, file string) string {
+	out, err := trace.Command("git", "-C", worktreePath, "show", rev+":"+file).Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// diffDeps compares two dependency maps, by name
+func diffDeps(manifest string, before, after map[string]string) []DepChange {
+	var changes []DepChange
+	for name, version := range after {
+		if prev, ok := before[name]; !ok {
+			changes = append(changes, DepChange{Manifest: manifest, Name: name, New: version})
+		} else if prev != version {
+			changes = append(changes, DepChange{Manifest: manifest, Name: name, Old: prev, New: version})
+		}
+	}
+	for name, version := range before {
+		if _, ok := after[name]; !ok {
+			changes = append(changes, DepChange{Manifest: manifest, Name: name, Old: version})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// detectLicense names the license an added file carries, or "" when it
+// carries none
+func detectLicense(file, content string) string {
+	isLicenseFile := licenseFile.MatchString(path.Base(file))
+
+	// Only the header of a source file is checked for an SPDX tag
+	head := content
+	if !isLicenseFile && len(head) > 4096 {
+		head = head[:4096]
+	}
+	for _, line := range strings.Split(head, "\n") {
+		if m := spdxIdentifier.FindStringSubmatch(line); m != nil {
+			return strings.TrimSpace(m[1])
+		}
+	}
+
+	if !isLicenseFile {
+		return ""
+	}
+	for _, l := range licenseMarkers {
+		if strings.Contains(content, l.marker) {
+			return l.id
+		}
+	}
+	return "unknown"
+}
+
+var goRequire = regexp.MustCompile(`^\s*(?:require\s+)?([^\s()]+)\s+(v[^\s]+)`)
+
+// parseGoMod reads the require directives of a go.mod
+func parseGoMod(content string) map[string]string {
+	deps := make(map[string]string)
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.SplitN(line, "//", 2)[0])
+		switch {
+		case strings.HasPrefix(line, "require ("):
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case !inBlock && !strings.HasPrefix(line, "require "):
+			continue
+		}
+		if m := goRequire.FindStringSubmatch(line); m != nil {
+			deps[m[1]] = m[2]
+		}
+	}
+	return deps
+}
+
+// parsePackageJSON reads every dependency section of a package.json
+func parsePackageJSON(content string) map[string]string {
+	var pkg map[string]json.RawMessage
+	if json.Unmarshal([]byte(content), &pkg) != nil {
+		return nil
+	}
+
+	deps := make(map[string]string)
+	for _, section := range []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"} {
+		var entries map[string]string
+		if json.Unmarshal(pkg[section], &entries) != nil {
+			continue
+		}
+		for name, version := range entries {
+			deps[name] = version
+		}
+	}
+	return deps
+}
+
+var requirement = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*(.*)$`)
+
+// parseRequirements reads a pip requirements file
+func parseRequirements(content string) map[string]string {
+	deps := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.SplitN(line, "#", 2)[0])
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if m := requirement.FindStringSubmatch(line); m != nil {
+			deps[strings.ToLower(m[1])] = strings.TrimSpace(m[2])
+		}
+	}
+	return deps
+}
+
+var (
+	tomlSection = regexp.MustCompile(`^\[([^\]]+)\]$`)
+	tomlKey     = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*=\s*(.+)$`)
+	tomlVersion = regexp.MustCompile(`version\s*=\s*"([^"]*)"`)
+)
+
+// parseCargoToml reads the dependency tables of a Cargo.toml
+func parseCargoToml(content string) map[string]string {
+	deps := make(map[string]string)
+	inDeps := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if m := tomlSection.FindStringSubmatch(line); m != nil {
+			inDeps = strings.HasSuffix(m[1], "dependencies")
+			continue
+		}
+		if !inDeps {
+			continue
+		}
+		m := tomlKey.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		version := strings.Trim(m[2], `"`)
+		if v := tomlVersion.FindStringSubmatch(m[2]); v != nil {
+			version = v[1]
+		}
+		deps[m[1]] = version
+	}
+	return deps
+}
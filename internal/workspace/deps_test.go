@@ -0,0 +1,124 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGoMod(t *testing.T) {
+	deps := parseGoMod(`module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/rs/zerolog v1.31.0
+	golang.org/x/sys v0.15.0 // indirect
+)
+`)
+	want := map[string]string{
+		"github.com/spf13/cobra": "v1.8.0",
+		"github.com/rs/zerolog":  "v1.31.0",
+		"golang.org/x/sys":       "v0.15.0",
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("Expected %d dependencies, got %v", len(want), deps)
+	}
+	for name, version := range want {
+		if deps[name] != version {
+			t.Errorf("Expected %s %s, got %q", name, version, deps[name])
+		}
+	}
+}
+
+func TestParseManifests(t *testing.T) {
+	pkg := parsePackageJSON(`{"name": "app", "dependencies": {"react": "^18.2.0"}, "devDependencies": {"vite": "5.0.0"}}`)
+	if pkg["react"] != "^18.2.0" || pkg["vite"] != "5.0.0" || len(pkg) != 2 {
+		t.Errorf("Unexpected package.json dependencies %v", pkg)
+	}
+
+	reqs := parseRequirements("# pinned\nrequests==2.31.0\nuvicorn[standard]>=0.24\n-r base.txt\n")
+	if reqs["requests"] != "==2.31.0" || reqs["uvicorn"] != ">=0.24" || len(reqs) != 2 {
+		t.Errorf("Unexpected requirements %v", reqs)
+	}
+
+	cargo := parseCargoToml(`[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+anyhow = "1"
+
+[dev-dependencies]
+tempfile = "3"
+`)
+	if cargo["serde"] != "1.0" || cargo["anyhow"] != "1" || cargo["tempfile"] != "3" || len(cargo) != 3 {
+		t.Errorf("Unexpected Cargo.toml dependencies %v", cargo)
+	}
+}
+
+func TestDetectLicense(t *testing.T) {
+	tests := []struct {
+		file, content, want string
+	}{
+		{"vendor/lib/LICENSE", "MIT License\n\nPermission is hereby granted, free of charge, to any person", "MIT"},
+		{"third_party/COPYING", "GNU GENERAL PUBLIC LICENSE\nVersion 3", "GPL"},
+		{"LICENSE.md", "All rights reserved.", "unknown"},
+		{"src/util.c", "// SPDX-License-Identifier: GPL-2.0-only\nint x;", "GPL-2.0-only"},
+		{"src/page.html", "<!-- SPDX-License-Identifier: MIT -->", "MIT"},
+		{"src/main.go", "package main", ""},
+	}
+	for _, tt := range tests {
+		if got := detectLicense(tt.file, tt.content); got != tt.want {
+			t.Errorf("detectLicense(%s) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestDetectDependencyChanges(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module app\n\nrequire (\n\tgithub.com/a/old v1.0.0\n\tgithub.com/b/kept v1.0.0\n)\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add go.mod").Run()
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+
+	os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module app\n\nrequire (\n\tgithub.com/b/kept v1.2.0\n\tgithub.com/c/new v0.3.0\n)\n"), 0644)
+	os.MkdirAll(filepath.Join(repo, "vendor", "c"), 0755)
+	os.WriteFile(filepath.Join(repo, "vendor", "c", "LICENSE"), []byte("Apache License\nVersion 2.0\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "Gemfile"), []byte("gem 'rails'\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Bump").Run()
+
+	report, err := DetectDependencyChanges(repo, strings.TrimSpace(string(base)))
+	if err != nil {
+		t.Fatalf("DetectDependencyChanges failed: %v", err)
+	}
+
+	if strings.Join(report.Manifests, ",") != "Gemfile,go.mod" {
+		t.Errorf("Expected Gemfile and go.mod, got %v", report.Manifests)
+	}
+	var changes []string
+	for _, d := range report.Deps {
+		changes = append(changes, d.String())
+	}
+	want := "- github.com/a/old v1.0.0|~ github.com/b/kept v1.0.0 -> v1.2.0|+ github.com/c/new v0.3.0"
+	if got := strings.Join(changes, "|"); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if len(report.Licenses) != 1 || report.Licenses[0] != (LicenseChange{File: "vendor/c/LICENSE", License: "Apache-2.0"}) {
+		t.Errorf("Expected the vendored Apache license, got %v", report.Licenses)
+	}
+	if report.Empty() || report.Summary() == "" {
+		t.Error("Expected a non-empty report with a summary")
+	}
+}
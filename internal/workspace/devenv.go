@@ -0,0 +1,114 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DevEnvKind identifies a reproducible development environment
+type DevEnvKind string
+
+const (
+	DevEnvDevcontainer DevEnvKind = "devcontainer"
+	DevEnvNixFlake     DevEnvKind = "nix-flake"
+	DevEnvNixShell     DevEnvKind = "nix-shell"
+)
+
+// Dev environment modes accepted in config and on the command line
+const (
+	DevEnvModeOff          = "off"
+	DevEnvModeAuto         = "auto"
+	DevEnvModeDevcontainer = "devcontainer"
+	DevEnvModeNix          = "nix"
+)
+
+// DevEnv is a development environment declared by a project
+type DevEnv struct {
+	Kind DevEnvKind
+	Path string // Declaration file that triggered detection
+}
+
+// devEnvFile is a declaration file and the environment it sets up
+type devEnvFile struct {
+	kind DevEnvKind
+	file string
+}
+
+// Declaration files by mode, in the order they are looked for
+var (
+	devcontainerFiles = []devEnvFile{
+		{DevEnvDevcontainer, filepath.Join(".devcontainer", "devcontainer.json")},
+		{DevEnvDevcontainer, ".devcontainer.json"},
+	}
+	nixFiles = []devEnvFile{
+		{DevEnvNixFlake, "flake.nix"},
+		{DevEnvNixShell, "shell.nix"},
+	}
+)
+
+// DetectDevEnv looks for a devcontainer or Nix declaration in a project.
+// Devcontainers win over Nix when both are present.
+func DetectDevEnv(projectPath string) *DevEnv {
+	if env := findDevEnv(projectPath, devcontainerFiles); env != nil {
+		return env
+	}
+	return findDevEnv(projectPath, nixFiles)
+}
+
+// findDevEnv returns the environment of the first of files in a project
+func findDevEnv(projectPath string, files []devEnvFile) *DevEnv {
+	for _, f := range files {
+		path := filepath.Join(projectPath, f.file)
+		if _, err := os.Stat(path); err == nil {
+			return &DevEnv{Kind: f.kind, Path: path}
+		}
+	}
+	return nil
+}
+
+// ResolveDevEnv applies a mode (off, auto, devcontainer, nix) to a project
+// and returns the environment to run in, or nil to run on the host
+func ResolveDevEnv(mode, projectPath string) (*DevEnv, error) {
+	switch mode {
+	case "", DevEnvModeOff:
+		return nil, nil
+	case DevEnvModeAuto:
+		return DetectDevEnv(projectPath), nil
+	case DevEnvModeDevcontainer, DevEnvModeNix:
+		files := nixFiles
+		if mode == DevEnvModeDevcontainer {
+			files = devcontainerFiles
+		}
+		env := findDevEnv(projectPath, files)
+		if env == nil {
+			return nil, fmt.Errorf("no %s environment found in %s", mode, projectPath)
+		}
+		return env, nil
+	default:
+		return nil, fmt.Errorf("unknown dev environment mode: %s (use off, auto, devcontainer, nix)", mode)
+	}
+}
+
+// WrapCommand wraps a shell command so it runs inside the environment.
+// Devcontainers are brought up first so the first spawn builds the image.
+func (e *DevEnv) WrapCommand(command, workdir string) string {
+	switch e.Kind {
+	case DevEnvDevcontainer:
+		folder := ShellQuote(workdir)
+		return fmt.Sprintf("devcontainer up --workspace-folder %s && devcontainer exec --workspace-folder %s %s",
+			folder, folder, command)
+	case DevEnvNixFlake:
+		return fmt.Sprintf("nix develop %s --command %s", ShellQuote(workdir), command)
+	case DevEnvNixShell:
+		return fmt.Sprintf("nix-shell %s --run %s",
+			ShellQuote(filepath.Join(workdir, "shell.nix")), ShellQuote(command))
+	}
+	return command
+}
+
+// ShellQuote single-quotes a string for POSIX shells
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectDevEnv(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  DevEnvKind
+	}{
+		{[]string{".devcontainer/devcontainer.json"}, DevEnvDevcontainer},
+		{[]string{".devcontainer.json"}, DevEnvDevcontainer},
+		{[]string{"flake.nix"}, DevEnvNixFlake},
+		{[]string{"shell.nix"}, DevEnvNixShell},
+		{[]string{"flake.nix", ".devcontainer/devcontainer.json"}, DevEnvDevcontainer},
+		{[]string{"README.md"}, ""},
+	}
+
+	for _, tc := range tests {
+		dir := t.TempDir()
+		for _, f := range tc.files {
+			path := filepath.Join(dir, f)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte("{}"), 0644)
+		}
+
+		env := DetectDevEnv(dir)
+		if tc.want == "" {
+			if env != nil {
+				t.Errorf("%v: expected no environment, got %s", tc.files, env.Kind)
+			}
+			continue
+		}
+		if env == nil || env.Kind != tc.want {
+			t.Errorf("%v: expected %s, got %+v", tc.files, tc.want, env)
+		}
+	}
+}
+
+func TestResolveDevEnv(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "flake.nix"), []byte("{}"), 0644)
+
+	if env, err := ResolveDevEnv("off", dir); err != nil || env != nil {
+		t.Errorf("off: expected nil, got %+v, %v", env, err)
+	}
+
+	env, err := ResolveDevEnv("auto", dir)
+	if err != nil || env == nil || env.Kind != DevEnvNixFlake {
+		t.Errorf("auto: expected nix-flake, got %+v, %v", env, err)
+	}
+
+	if _, err := ResolveDevEnv("nix", dir); err != nil {
+		t.Errorf("nix: unexpected error: %v", err)
+	}
+
+	if _, err := ResolveDevEnv("devcontainer", dir); err == nil {
+		t.Error("devcontainer: expected error when only flake.nix exists")
+	}
+
+	if _, err := ResolveDevEnv("docker", dir); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+
+	// With both declared, each mode picks its own
+	os.MkdirAll(filepath.Join(dir, ".devcontainer"), 0755)
+	os.WriteFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"), []byte("{}"), 0644)
+	if env, err := ResolveDevEnv("nix", dir); err != nil || env.Kind != DevEnvNixFlake {
+		t.Errorf("nix with both: expected nix-flake, got %+v, %v", env, err)
+	}
+	if env, err := ResolveDevEnv("devcontainer", dir); err != nil || env.Kind != DevEnvDevcontainer {
+		t.Errorf("devcontainer with both: expected devcontainer, got %+v, %v", env, err)
+	}
+	if env, _ := ResolveDevEnv("auto", dir); env == nil || env.Kind != DevEnvDevcontainer {
+		t.Errorf("auto with both: expected devcontainer, got %+v", env)
+	}
+}
+
+func TestWrapCommand(t *testing.T) {
+	devcontainer := &DevEnv{Kind: DevEnvDevcontainer}
+	cmd := devcontainer.WrapCommand("claude", "/work/tree")
+	if !strings.HasPrefix(cmd, "devcontainer up --workspace-folder '/work/tree' && devcontainer exec") {
+		t.Errorf("Unexpected devcontainer command: %s", cmd)
+	}
+	if !strings.HasSuffix(cmd, " claude") {
+		t.Errorf("Agent command should come last: %s", cmd)
+	}
+
+	flake := &DevEnv{Kind: DevEnvNixFlake}
+	if got := flake.WrapCommand("aider --yes", "/w"); got != "nix develop '/w' --command aider --yes" {
+		t.Errorf("Unexpected flake command: %s", got)
+	}
+
+	shell := &DevEnv{Kind: DevEnvNixShell}
+	if got := shell.WrapCommand("it's", "/w"); got != `nix-shell '/w/shell.nix' --run 'it'\''s'` {
+		t.Errorf("Unexpected nix-shell command: %s", got)
+	}
+}
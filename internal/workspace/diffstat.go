@@ -0,0 +1,338 @@
+package workspace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// DiffStat summarizes a worktree's uncommitted changes
+type DiffStat struct {
+	Files      int `json:"files"`
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+// String renders the stat as "3 files +12 -4", or "clean"
+func (s DiffStat) String() string {
+	if s.Files == 0 {
+		return "clean"
+	}
+	files := "files"
+	if s.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s +%d -%d", s.Files, files, s.Insertions, s.Deletions)
+}
+
+// GetDiffStat counts the files changed in a worktree and the lines added
+// and removed in tracked files since HEAD
+func (m *WorktreeManager) GetDiffStat(worktreePath string) (*DiffStat, error) {
+	changes, err := m.GetChanges(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	return m.diffStat(worktreePath, changes), nil
+}
+
+// diffStat totals the lines changed since HEAD in a worktree whose changed
+// files are already known
+func (m *WorktreeManager) diffStat(worktreePath string, changes []string) *DiffStat {
+	stat := &DiffStat{Files: len(changes)}
+	if len(changes) == 0 {
+		return stat
+	}
+
+	// Fails before the first commit, leaving just the file count
+	args := []string{"-C", worktreePath, "diff", "HEAD", "--numstat"}
+	if specs := m.ignore.Pathspecs(); specs != nil {
+		args = append(append(args, "--"), specs...)
+	}
+	output, err := trace.Command("git", args...).Output()
+	if err != nil {
+		return stat
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		// Binary files show "-" for both counts
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		stat.Insertions += added
+		stat.Deletions += removed
+	}
+	return stat
+}
+
+// BranchDiffStat totals the changes committed on a worktree's branch since
+// base, or since its upstream when base is empty, less ignored files
+func BranchDiffStat(worktreePath, base string, ignore Ignore) (*DiffStat, error) {
+	base, err := branchBase(worktreePath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"-C", worktreePath, "diff", "--numstat", base, "HEAD"}
+	if specs := ignore.Pathspecs(); specs != nil {
+		args = append(append(args, "--"), specs...)
+	}
+	output, err := trace.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff stat: %w", err)
+	}
+
+	stat := &DiffStat{}
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		stat.Files++
+		stat.Insertions += added
+		stat.Deletions += removed
+	}
+	return stat, nil
+}
+
+// branchBase returns the commit a branch's own work starts after: base
+// when given, else the branch's upstream
+func branchBase(worktreePath, base string) (string, error) {
+	if base != "" {
+		return base, nil
+	}
+	out, err := trace.Command("git", "-C", worktreePath, "rev-parse", "--verify", "-q", "@{upstream}").Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot tell which commits are new in %s: no base commit or upstream", worktreePath)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// BranchFiles lists the files changed by the commits on a worktree's
+// branch since base, or since its upstream when base is empty
+func BranchFiles(worktreePath, base string) ([]string, error) {
+	base, err := branchBase(worktreePath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := trace.Command("git", "-C", worktreePath, "diff", "--name-only", base, "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	var files []string
+	for _, file := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// TouchedFiles lists every file a worktree has changed since base, or
+// since its upstream when base is empty: committed, uncommitted or
+// untracked. Both sides of a rename are listed.
+func TouchedFiles(worktreePath, base string) ([]string, error) {
+	base, err := branchBase(worktreePath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := trace.Command("git", "-C", worktreePath, "diff", "--name-only", "--no-renames", base).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	untracked, err := trace.Command("git", "-C", worktreePath, "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(changed)+"\n"+string(untracked), "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// BranchCommits returns the subjects of the commits on a worktree's
+// branch since base, or since its upstream when base is empty, oldest
+// first
+func BranchCommits(worktreePath, base string) ([]string, error) {
+	base, err := branchBase(worktreePath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := trace.Command("git", "-C", worktreePath, "log", "--reverse", "--format=%s", base+"..HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	var commits []string
+	for _, subject := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if subject != "" {
+			commits = append(commits, subject)
+		}
+	}
+	return commits, nil
+}
+
+// StatCache keeps worktree changes between commands, so listing dozens of
+// goblins doesn't run git in each worktree every time. An entry is reused
+// while the worktree's HEAD, index, top-level directory and changed files
+// are untouched, for at most the cache's TTL; in-place edits to files that
+// were clean are only picked up when the TTL runs out.
+type StatCache struct {
+	file string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*statEntry
+	dirty   bool
+}
+
+// statEntry is one worktree's cached changes
+type statEntry struct {
+	Stamp   string    `json:"stamp"`
+	Changes []string  `json:"changes"`
+	Stat    DiffStat  `json:"stat"`
+	At      time.Time `json:"at"`
+}
+
+// NewStatCache loads the cache stored in file. A missing or unreadable
+// file starts an empty cache.
+func NewStatCache(file string, ttl time.Duration) *StatCache {
+	c := &StatCache{file: file, ttl: ttl, entries: make(map[string]*statEntry)}
+	if data, err := os.ReadFile(file); err == nil {
+		json.Unmarshal(data, &c.entries)
+	}
+	return c
+}
+
+// Get returns a worktree's changed files and diff stat, from the cache when
+// the worktree hasn't changed since they were computed. Safe for
+// concurrent use.
+func (c *StatCache) Get(m *WorktreeManager, worktreePath string) ([]string, *DiffStat, error) {
+	c.mu.Lock()
+	entry := c.entries[worktreePath]
+	c.mu.Unlock()
+
+	if entry != nil && time.Since(entry.At) < c.ttl && entry.Stamp == worktreeStamp(worktreePath, entry.Changes) {
+		stat := entry.Stat
+		return entry.Changes, &stat, nil
+	}
+
+	changes, err := m.GetChanges(worktreePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	stat := m.diffStat(worktreePath, changes)
+
+	// Stamped afterwards, since git status may refresh the index; edits
+	// racing the computation are caught by the TTL
+	if stamp := worktreeStamp(worktreePath, changes); stamp != "" {
+		c.mu.Lock()
+		c.entries[worktreePath] = &statEntry{Stamp: stamp, Changes: changes, Stat: *stat, At: time.Now()}
+		c.dirty = true
+		c.mu.Unlock()
+	}
+
+	return changes, stat, nil
+}
+
+// Save writes the cache back to its file, dropping entries for worktrees
+// that no longer exist
+func (c *StatCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+	for path := range c.entries {
+		if _, err := os.Stat(path); err != nil {
+			delete(c.entries, path)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode stat cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write stat cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// worktreeStamp fingerprints what git status and diff depend on: HEAD and
+// its reflog (moved by commits, checkouts and resets), the index, the
+// worktree's top-level directory and the given changed files. Empty when
+// the worktree's git directory can't be found.
+func worktreeStamp(worktreePath string, changes []string) string {
+	gitDir := resolveGitDir(worktreePath)
+	if gitDir == "" {
+		return ""
+	}
+
+	paths := []string{
+		filepath.Join(gitDir, "HEAD"),
+		filepath.Join(gitDir, "logs", "HEAD"),
+		filepath.Join(gitDir, "index"),
+		worktreePath,
+	}
+	for _, f := range changes {
+		paths = append(paths, filepath.Join(worktreePath, f))
+	}
+
+	var b strings.Builder
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&b, "%d:%d;", info.ModTime().UnixNano(), info.Size())
+		} else {
+			b.WriteString("-;")
+		}
+	}
+	return b.String()
+}
+
+// resolveGitDir returns a checkout's git directory, following the
+// "gitdir:" file linked worktrees use
+func resolveGitDir(worktreePath string) string {
+	dotGit := filepath.Join(worktreePath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+	dir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(worktreePath, dir)
+	}
+	return dir
+}
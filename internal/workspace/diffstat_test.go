@@ -0,0 +1,127 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetDiffStat(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+	m := NewWorktreeManager(Config{BasePath: t.TempDir()})
+
+	stat, err := m.GetDiffStat(repo)
+	if err != nil {
+		t.Fatalf("GetDiffStat failed: %v", err)
+	}
+	if stat.String() != "clean" {
+		t.Errorf("Expected a clean worktree, got %s", stat)
+	}
+
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Changed\nline\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "new.txt"), []byte("new\n"), 0644)
+
+	stat, _ = m.GetDiffStat(repo)
+	if *stat != (DiffStat{Files: 2, Insertions: 2, Deletions: 1}) {
+		t.Errorf("Expected 2 files +2 -1, got %s", stat)
+	}
+}
+
+func TestStatCache(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+	m := NewWorktreeManager(Config{BasePath: t.TempDir()})
+	file := filepath.Join(t.TempDir(), "stats.json")
+
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Changed\n"), 0644)
+
+	cache := NewStatCache(file, time.Hour)
+	changes, _, err := cache.Get(m, repo)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v, %v", changes, err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A reloaded cache serves an unchanged worktree without running git
+	cache = NewStatCache(file, time.Hour)
+	cache.entries[repo].Stat.Insertions = 99
+	if _, stat, _ := cache.Get(m, repo); stat.Insertions != 99 {
+		t.Errorf("Expected the cached entry reused, got %s", stat)
+	}
+
+	// Editing a changed file again invalidates the entry
+	time.Sleep(10 * time.Millisecond)
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Changed\nagain\n"), 0644)
+	if _, stat, _ := cache.Get(m, repo); stat.Insertions != 2 {
+		t.Errorf("Expected a recomputed stat after an edit, got %s", stat)
+	}
+
+	// So does a commit
+	exec.Command("git", "-C", repo, "commit", "-qam", "edit").Run()
+	if _, stat, _ := cache.Get(m, repo); stat.Files != 0 {
+		t.Errorf("Expected a clean worktree after committing, got %s", stat)
+	}
+
+	// And the TTL
+	cache = NewStatCache(file, 0)
+	cache.entries[repo] = &statEntry{Stamp: worktreeStamp(repo, nil), Stat: DiffStat{Files: 42}, At: time.Now()}
+	if _, stat, _ := cache.Get(m, repo); stat.Files != 0 {
+		t.Errorf("Expected an expired entry recomputed, got %s", stat)
+	}
+}
+
+func TestBranchDiffStat(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Changed\nline\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "new.txt"), []byte("a\nb\nc\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Change").Run()
+	// Uncommitted edits are not part of the branch yet
+	os.WriteFile(filepath.Join(repo, "draft.txt"), []byte("draft\n"), 0644)
+
+	stat, err := BranchDiffStat(repo, strings.TrimSpace(string(base)), nil)
+	if err != nil {
+		t.Fatalf("BranchDiffStat failed: %v", err)
+	}
+	if *stat != (DiffStat{Files: 2, Insertions: 5, Deletions: 1}) {
+		t.Errorf("Expected 2 files +5 -1, got %s", stat)
+	}
+
+	if _, err := BranchDiffStat(repo, "", nil); err == nil {
+		t.Error("Expected an error without a base or upstream")
+	}
+
+	files, err := BranchFiles(repo, strings.TrimSpace(string(base)))
+	if err != nil || strings.Join(files, ",") != "README.md,new.txt" {
+		t.Errorf("Expected README.md and new.txt, got %v, %v", files, err)
+	}
+	commits, err := BranchCommits(repo, strings.TrimSpace(string(base)))
+	if err != nil || strings.Join(commits, ",") != "Change" {
+		t.Errorf("Expected the one commit, got %v, %v", commits, err)
+	}
+
+	// Touched files take in the uncommitted work too
+	touched, err := TouchedFiles(repo, strings.TrimSpace(string(base)))
+	if err != nil || strings.Join(touched, ",") != "README.md,new.txt,draft.txt" {
+		t.Errorf("Expected README.md, new.txt and draft.txt, got %v, %v", touched, err)
+	}
+}
@@ -0,0 +1,191 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// FilePatch is one file's part of a unified diff
+type FilePatch struct {
+	Path   string   // The file's new path, or its old one when deleted
+	Header []string // From "diff --git" up to the first hunk, binary data included
+	Hunks  []*Hunk  // None for binary files, renames and mode changes
+}
+
+// Changes counts the parts of a file that can be accepted on their own:
+// its hunks, or the whole file when it has none
+func (f *FilePatch) Changes() int {
+	if len(f.Hunks) == 0 {
+		return 1
+	}
+	return len(f.Hunks)
+}
+
+// Hunk is one @@ section of a file's diff
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Section            string   // Text after the closing @@, often a function name
+	Lines              []string // Context, removed and added lines
+}
+
+// String renders the hunk as it appears in a diff
+func (h *Hunk) String() string {
+	return h.render(h.NewStart) + strings.Join(h.Lines, "\n") + "\n"
+}
+
+func (h *Hunk) render(newStart int) string {
+	return fmt.Sprintf("@@ -%s +%s @@%s\n", hunkRange(h.OldStart, h.OldLines), hunkRange(newStart, h.NewLines), h.Section)
+}
+
+// hunkRange writes a hunk's start and line count as git does, leaving
+// out a count of 1
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// BranchPatch returns the changes committed on a worktree's branch since
+// base as a patch git apply takes, binary files included
+func BranchPatch(worktreePath, base string) (string, error) {
+	output, err := trace.Command("git", "-C", worktreePath, "diff", "--binary", "--no-color", base, "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return string(output), nil
+}
+
+// ParsePatch splits a unified diff from git into files and hunks
+func ParsePatch(diff string) []*FilePatch {
+	var (
+		files            []*FilePatch
+		file             *FilePatch
+		hunk             *Hunk
+		oldLeft, newLeft int
+	)
+
+	for _, text := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		// Inside a hunk every line is content, whatever it starts with
+		if hunk != nil && (oldLeft > 0 || newLeft > 0 || strings.HasPrefix(text, `\`)) {
+			hunk.Lines = append(hunk.Lines, text)
+			switch {
+			case strings.HasPrefix(text, "+"):
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, " "), text == "":
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+		hunk = nil
+
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			file = &FilePatch{Path: gitDiffPath(text)}
+			files = append(files, file)
+			file.Header = append(file.Header, text)
+		case file == nil:
+			continue
+		case strings.HasPrefix(text, "@@"):
+			m := hunkHeader.FindStringSubmatch(text)
+			if m == nil {
+				continue
+			}
+			hunk = &Hunk{OldLines: hunkCount(m[1]), NewLines: hunkCount(m[3])}
+			fmt.Sscanf(text, "@@ -%d", &hunk.OldStart)
+			hunk.NewStart, _ = strconv.Atoi(m[2])
+			if end := strings.Index(text[2:], "@@"); end >= 0 {
+				hunk.Section = text[end+4:]
+			}
+			oldLeft, newLeft = hunk.OldLines, hunk.NewLines
+			file.Hunks = append(file.Hunks, hunk)
+		case len(file.Hunks) == 0:
+			if strings.HasPrefix(text, "+++ ") && text != "+++ /dev/null" {
+				file.Path = strings.TrimPrefix(text, "+++ b/")
+			}
+			file.Header = append(file.Header, text)
+		}
+	}
+	return files
+}
+
+// gitDiffPath reads the new path from a "diff --git a/x b/x" line
+func gitDiffPath(line string) string {
+	if i := strings.LastIndex(line, " b/"); i >= 0 {
+		return line[i+3:]
+	}
+	return strings.TrimPrefix(line, "diff --git ")
+}
+
+// BuildPatch renders the changes keep accepts, by file and change index
+// (see Changes), as a patch git apply takes. Hunks after a left-out one
+// are moved to where they now start; files with nothing kept are dropped.
+func BuildPatch(files []*FilePatch, keep func(file, change int) bool) string {
+	var b strings.Builder
+	for fi, f := range files {
+		if len(f.Hunks) == 0 {
+			if keep(fi, 0) {
+				b.WriteString(strings.Join(f.Header, "\n") + "\n")
+			}
+			continue
+		}
+
+		var body strings.Builder
+		shift := 0 // Lines the left-out hunks above would have added
+		for hi, h := range f.Hunks {
+			if !keep(fi, hi) {
+				shift += h.NewLines - h.OldLines
+				continue
+			}
+			body.WriteString(h.render(h.NewStart - shift))
+			body.WriteString(strings.Join(h.Lines, "\n") + "\n")
+		}
+		if body.Len() > 0 {
+			b.WriteString(strings.Join(f.Header, "\n") + "\n")
+			b.WriteString(body.String())
+		}
+	}
+	return b.String()
+}
+
+// WorkPatch returns everything a worktree changed since base as a patch
+// git apply takes: commits, uncommitted edits and new files, limited to
+// paths when given. Untracked files are staged in a scratch index, so the
+// worktree's own index is left alone.
+func WorkPatch(worktreePath, base string, paths []string) (string, error) {
+	index, err := os.CreateTemp("", "gforge-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create index: %w", err)
+	}
+	index.Close()
+	defer os.Remove(index.Name())
+	env := append(os.Environ(), "GIT_INDEX_FILE="+index.Name())
+
+	for _, args := range [][]string{{"read-tree", "HEAD"}, {"add", "-A"}} {
+		cmd := trace.Command("git", append([]string{"-C", worktreePath}, args...)...)
+		cmd.Env = env
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to stage work: %s", strings.TrimSpace(string(output)))
+		}
+	}
+
+	args := []string{"-C", worktreePath, "diff", "--cached", "--binary", "--no-color", base}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := trace.Command("git", args...)
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return string(output), nil
+}
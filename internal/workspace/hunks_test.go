@@ -0,0 +1,63 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseAndBuildPatch(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, "line")
+	}
+	os.WriteFile(filepath.Join(repo, "long.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add long.txt").Run()
+	out, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	base := strings.TrimSpace(string(out))
+
+	// Two hunks far apart in long.txt, the first adding lines, and a new file
+	edited := append([]string{"top 1", "top 2"}, lines...)
+	edited[len(edited)-1] = "changed bottom"
+	os.WriteFile(filepath.Join(repo, "long.txt"), []byte(strings.Join(edited, "\n")+"\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "new.txt"), []byte("@@ not a header\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Edit").Run()
+
+	patch, err := BranchPatch(repo, base)
+	if err != nil {
+		t.Fatalf("BranchPatch failed: %v", err)
+	}
+	files := ParsePatch(patch)
+	if len(files) != 2 || files[0].Path != "long.txt" || len(files[0].Hunks) != 2 || files[1].Path != "new.txt" || files[1].Changes() != 1 {
+		t.Fatalf("Unexpected files: %+v", files)
+	}
+	if got := BuildPatch(files, func(int, int) bool { return true }); got != patch {
+		t.Errorf("Expected keeping everything to give the diff back, got\n%s", got)
+	}
+
+	// Keeping only the bottom hunk moves it up past the left-out insertion
+	exec.Command("git", "-C", repo, "checkout", "-q", base).Run()
+	partial := BuildPatch(files, func(file, change int) bool { return file == 0 && change == 1 })
+	if strings.Contains(partial, "new.txt") || strings.Contains(partial, "top 1") {
+		t.Errorf("Expected only the bottom hunk, got\n%s", partial)
+	}
+	apply := exec.Command("git", "-C", repo, "apply", "-")
+	apply.Stdin = strings.NewReader(partial)
+	if output, err := apply.CombinedOutput(); err != nil {
+		t.Fatalf("Partial patch does not apply: %v\n%s\n%s", err, output, partial)
+	}
+	data, _ := os.ReadFile(filepath.Join(repo, "long.txt"))
+	if strings.HasPrefix(string(data), "top") || !strings.HasSuffix(string(data), "changed bottom\n") {
+		t.Errorf("Unexpected result:\n%s", data)
+	}
+}
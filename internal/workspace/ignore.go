@@ -0,0 +1,123 @@
+package workspace
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Ignore holds a project's patterns for files left out of change counts
+// and review diffs: build output, lockfiles, generated code. Patterns
+// follow .gitignore: one without a slash matches a name at any depth, a
+// leading slash anchors it to the worktree root, and ** spans directories.
+// A pattern naming a directory covers everything inside it.
+type Ignore []string
+
+// Match reports whether path, relative to the worktree root, is ignored
+func (ig Ignore) Match(path string) bool {
+	path = strings.TrimPrefix(path, "./")
+	for _, pattern := range ig {
+		for _, glob := range ignoreGlobs(pattern) {
+			if globRegexp(glob).MatchString(path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Filter returns paths without the ignored ones. Renames ("old -> new",
+// as git status lists them) are judged by their new path.
+func (ig Ignore) Filter(paths []string) []string {
+	if len(ig) == 0 {
+		return paths
+	}
+	var kept []string
+	for _, p := range paths {
+		name := p
+		if i := strings.Index(p, " -> "); i >= 0 {
+			name = p[i+4:]
+		}
+		if !ig.Match(name) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// Pathspecs returns the git pathspecs that limit a command to the files
+// not ignored, to go after "--"; none when nothing is ignored
+func (ig Ignore) Pathspecs() []string {
+	if len(ig) == 0 {
+		return nil
+	}
+	specs := []string{"."}
+	for _, pattern := range ig {
+		for _, glob := range ignoreGlobs(pattern) {
+			specs = append(specs, ":(exclude,glob)"+glob)
+		}
+	}
+	return specs
+}
+
+// ignoreGlobs turns a .gitignore-style pattern into the root-relative
+// globs it stands for: the path itself and everything below it
+func ignoreGlobs(pattern string) []string {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return nil
+	}
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	if strings.HasPrefix(pattern, "/") {
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else if !strings.Contains(pattern, "/") && !strings.HasPrefix(pattern, "**") {
+		pattern = "**/" + pattern
+	}
+
+	if dirOnly || strings.HasSuffix(pattern, "/**") {
+		return []string{strings.TrimSuffix(pattern, "/**") + "/**"}
+	}
+	return []string{pattern, pattern + "/**"}
+}
+
+// globRegexp compiles a glob with git's glob pathspec rules: * and ? stay
+// within a directory, **/ matches any number of them and /** everything
+// below
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end >= 0 {
+				class := glob[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + class + "]")
+				i += end + 1
+				continue
+			}
+			b.WriteString(`\[`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return regexp.MustCompile(`^$.`)
+	}
+	return re
+}
@@ -0,0 +1,88 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	ig := Ignore{"*.lock", "dist/", "/build", "**/*.pb.go", "docs/gen/**", "vendor"}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"Cargo.lock", true},
+		{"web/yarn.lock", true},
+		{"dist/app.js", true},
+		{"web/dist/app.js", true},
+		{"dist", false}, // A directory pattern only covers what is inside
+		{"build/out.o", true},
+		{"web/build/out.o", false}, // Anchored to the root
+		{"api/v1/service.pb.go", true},
+		{"service.pb.go", true},
+		{"docs/gen/index.html", true},
+		{"docs/guide.md", false},
+		{"vendor", true},
+		{"vendor/lib/lib.go", true},
+		{"src/vendor.go", false},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		if got := ig.Match(tt.path); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if got := ig.Filter([]string{"main.go", "Cargo.lock", "old.go -> dist/new.go"}); strings.Join(got, ",") != "main.go" {
+		t.Errorf("Unexpected Filter result: %v", got)
+	}
+	if Ignore(nil).Pathspecs() != nil {
+		t.Error("Expected no pathspecs without patterns")
+	}
+}
+
+func TestIgnoreChanges(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	out, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	base := strings.TrimSpace(string(out))
+
+	os.MkdirAll(filepath.Join(repo, "gen"), 0755)
+	os.WriteFile(filepath.Join(repo, "gen", "api.go"), []byte("package gen\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "go.sum"), []byte("a\nb\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Generate").Run()
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Changed\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "go.sum"), []byte("a\nb\nc\n"), 0644)
+
+	ignore := Ignore{"gen/", "go.sum"}
+	m := NewWorktreeManager(Config{BasePath: t.TempDir(), Ignore: ignore})
+
+	changes, err := m.GetChanges(repo)
+	if err != nil || strings.Join(changes, ",") != "README.md" {
+		t.Errorf("Expected only README.md changed, got %v, %v", changes, err)
+	}
+	diff, _ := m.GetDiff(repo, false)
+	if !strings.Contains(diff, "README.md") || strings.Contains(diff, "go.sum") {
+		t.Errorf("Expected go.sum left out of the diff, got %q", diff)
+	}
+	if stat, _ := m.GetDiffStat(repo); stat.Files != 1 || stat.Insertions != 1 {
+		t.Errorf("Expected 1 file +1, got %s", stat)
+	}
+
+	// The branch only generated files
+	if stat, err := BranchDiffStat(repo, base, ignore); err != nil || stat.Files != 0 {
+		t.Errorf("Expected a clean branch stat, got %v, %v", stat, err)
+	}
+	if stat, _ := BranchDiffStat(repo, base, nil); stat.Files != 2 {
+		t.Errorf("Expected 2 files without ignore patterns, got %s", stat)
+	}
+}
@@ -0,0 +1,110 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// UsesLFS reports whether a checkout uses Git LFS: a .gitattributes file
+// at any depth, or .git/info/attributes, declares the lfs filter, or git
+// check-attr filters one of its files, tracked or new, through it
+func UsesLFS(worktreePath string) bool {
+	files, err := trace.Command("git", "-C", worktreePath, "ls-files", "-z", "--cached", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return false
+	}
+
+	attributes := []string{}
+	if info, err := trace.Command("git", "-C", worktreePath, "rev-parse", "--git-path", "info/attributes").Output(); err == nil {
+		attributes = append(attributes, strings.TrimSpace(string(info)))
+	}
+	for _, f := range strings.Split(string(files), "\x00") {
+		if filepath.Base(f) == ".gitattributes" {
+			attributes = append(attributes, f)
+		}
+	}
+	for _, f := range attributes {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(worktreePath, f)
+		}
+		if data, err := os.ReadFile(f); err == nil && strings.Contains(string(data), "filter=lfs") {
+			return true
+		}
+	}
+
+	if len(files) == 0 {
+		return false
+	}
+	cmd := trace.Command("git", "-C", worktreePath, "check-attr", "-z", "--stdin", "filter")
+	cmd.Stdin = bytes.NewReader(files)
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	// Each file gives three fields: path, attribute and value
+	fields := strings.Split(string(output), "\x00")
+	for i := 2; i < len(fields); i += 3 {
+		if fields[i] == "lfs" {
+			return true
+		}
+	}
+	return false
+}
+
+// lfsAvailable reports whether the git-lfs extension is installed
+func lfsAvailable() bool {
+	return trace.Command("git", "lfs", "version").Run() == nil
+}
+
+// requireLFS fails when a checkout uses LFS but git-lfs is missing:
+// committing would then store binaries in git, and checkouts hold pointer
+// files instead of content
+func requireLFS(worktreePath string) error {
+	if UsesLFS(worktreePath) && !lfsAvailable() {
+		return fmt.Errorf("%s uses Git LFS but git-lfs is not installed", worktreePath)
+	}
+	return nil
+}
+
+// SetupLFS installs the LFS hooks for a checkout and replaces pointer
+// files with their content. Checkouts without LFS are left alone.
+func SetupLFS(worktreePath string) error {
+	if !UsesLFS(worktreePath) {
+		return nil
+	}
+	if err := requireLFS(worktreePath); err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{{"install", "--local"}, {"pull"}} {
+		cmd := trace.Command("git", append([]string{"-C", worktreePath, "lfs"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git lfs %s failed: %w\nOutput: %s", args[0], err, string(output))
+		}
+	}
+	return nil
+}
+
+// pushLFS uploads the LFS objects of branch before the branch itself, so
+// the remote never receives pointers to content it doesn't have
+func pushLFS(worktreePath, remote, branch string, env []string) error {
+	if !UsesLFS(worktreePath) {
+		return nil
+	}
+	if err := requireLFS(worktreePath); err != nil {
+		return err
+	}
+
+	cmd := trace.Command("git", "-C", worktreePath, "lfs", "push", remote, branch)
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to push LFS objects: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
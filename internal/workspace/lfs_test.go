@@ -0,0 +1,64 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUsesLFS(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+	if UsesLFS(t.TempDir()) {
+		t.Error("Expected no LFS outside a repository")
+	}
+
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+	if UsesLFS(repoPath) {
+		t.Error("Expected no LFS without .gitattributes")
+	}
+
+	os.WriteFile(filepath.Join(repoPath, ".gitattributes"), []byte("*.txt text\n"), 0644)
+	if UsesLFS(repoPath) {
+		t.Error("Expected no LFS without an lfs filter")
+	}
+
+	// Declared in a nested .gitattributes
+	os.MkdirAll(filepath.Join(repoPath, "assets"), 0755)
+	os.WriteFile(filepath.Join(repoPath, "assets", ".gitattributes"), []byte("*.psd filter=lfs diff=lfs merge=lfs -text\n"), 0644)
+	if !UsesLFS(repoPath) {
+		t.Error("Expected LFS detected from assets/.gitattributes")
+	}
+
+	// Or only in the repository's own attributes, matching a file
+	os.RemoveAll(filepath.Join(repoPath, "assets"))
+	os.WriteFile(filepath.Join(repoPath, ".git", "info", "attributes"), []byte("*.bin filter=lfs -text\n"), 0644)
+	if !UsesLFS(repoPath) {
+		t.Error("Expected LFS detected from .git/info/attributes")
+	}
+}
+
+func TestCommitRequiresLFS(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+	if lfsAvailable() {
+		t.Skip("git-lfs is installed")
+	}
+
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	os.WriteFile(filepath.Join(repoPath, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644)
+	os.WriteFile(filepath.Join(repoPath, "asset.bin"), []byte{0, 1, 2}, 0644)
+
+	mgr := NewWorktreeManager(Config{BasePath: t.TempDir()})
+	if _, err := mgr.Commit(repoPath, "Add asset"); err == nil {
+		t.Error("Expected the commit refused without git-lfs")
+	}
+	if err := SetupLFS(repoPath); err == nil {
+		t.Error("Expected setup to fail without git-lfs")
+	}
+}
@@ -0,0 +1,50 @@
+package workspace
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+// BranchPolicy guards protected branches (e.g. main, release/*) against
+// writes gforge makes on its own: branch creation, pushes and merges.
+// The zero policy protects nothing.
+type BranchPolicy struct {
+	Protected []string // Branch names or glob patterns
+}
+
+// IsProtected reports whether branch matches a protected pattern
+func (p BranchPolicy) IsProtected(branch string) bool {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	for _, pattern := range p.Protected {
+		if pattern == branch {
+			return true
+		}
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Check refuses action (e.g. "push to") on a protected branch
+func (p BranchPolicy) Check(action, branch string) error {
+	if p.IsProtected(branch) {
+		return fmt.Errorf("%w: refusing to %s %s", errs.ErrProtectedBranch, action, branch)
+	}
+	return nil
+}
+
+// CheckPR validates the refs of a pull request: work must come from an
+// unprotected head branch into a different base
+func (p BranchPolicy) CheckPR(head, base string) error {
+	if head == "" {
+		return fmt.Errorf("pull request has no head branch")
+	}
+	if head == base {
+		return fmt.Errorf("pull request head and base are both %s", head)
+	}
+	return p.Check("open a pull request from", head)
+}
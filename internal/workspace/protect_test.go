@@ -0,0 +1,66 @@
+package workspace
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+)
+
+func TestBranchPolicy(t *testing.T) {
+	policy := BranchPolicy{Protected: []string{"main", "release/*"}}
+
+	tests := []struct {
+		branch    string
+		protected bool
+	}{
+		{"main", true},
+		{"refs/heads/main", true},
+		{"release/1.2", true},
+		{"release/1.2/hotfix", false},
+		{"gforge/main", false},
+		{"maintenance", false},
+	}
+	for _, tt := range tests {
+		if got := policy.IsProtected(tt.branch); got != tt.protected {
+			t.Errorf("IsProtected(%q) = %v, want %v", tt.branch, got, tt.protected)
+		}
+	}
+
+	if err := policy.Check("push to", "release/2.0"); !errors.Is(err, errs.ErrProtectedBranch) {
+		t.Errorf("Expected ErrProtectedBranch, got %v", err)
+	}
+	if err := (BranchPolicy{}).Check("push to", "main"); err != nil {
+		t.Errorf("Expected the zero policy to allow everything, got %v", err)
+	}
+
+	if err := policy.CheckPR("gforge/fix", "main"); err != nil {
+		t.Errorf("Expected a PR into main allowed, got %v", err)
+	}
+	if err := policy.CheckPR("main", "release/1.2"); err == nil {
+		t.Error("Expected a PR from a protected branch refused")
+	}
+	if err := policy.CheckPR("gforge/fix", "gforge/fix"); err == nil {
+		t.Error("Expected a PR into its own head refused")
+	}
+}
+
+func TestCreateProtectedBranch(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	mgr := NewWorktreeManager(Config{
+		BasePath: t.TempDir(),
+		Branches: BranchPolicy{Protected: []string{"release/*"}},
+	})
+	if _, err := mgr.Create(repoPath, "prot", "release/1.0"); !errors.Is(err, errs.ErrProtectedBranch) {
+		t.Errorf("Expected the protected branch refused, got %v", err)
+	}
+	if _, err := mgr.Create(repoPath, "ok", "gforge/ok"); err != nil {
+		t.Errorf("Expected an unprotected branch created, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package workspace
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// RemoteWebURL turns a git remote URL (https, ssh or scp-style) into the
+// repository's web address, e.g. git@github.com:acme/app.git becomes
+// https://github.com/acme/app. Unrecognised URLs return "".
+func RemoteWebURL(remoteURL string) string {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	var host, repoPath string
+	if u, err := url.Parse(remoteURL); err == nil && u.Host != "" {
+		switch u.Scheme {
+		case "https", "http", "ssh", "git":
+		default:
+			return ""
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else if at := strings.Index(remoteURL, "@"); at >= 0 && strings.Contains(remoteURL[at:], ":") {
+		// scp-style: git@github.com:acme/app.git
+		rest := remoteURL[at+1:]
+		colon := strings.Index(rest, ":")
+		host, repoPath = rest[:colon], rest[colon+1:]
+	} else {
+		return ""
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return ""
+	}
+	return "https://" + host + "/" + repoPath
+}
+
+// remoteWebURL returns the web address of a checkout's remote, or ""
+func remoteWebURL(worktreePath, remote string) string {
+	output, err := trace.Command("git", "-C", worktreePath, "remote", "get-url", remote).Output()
+	if err != nil {
+		return ""
+	}
+	return RemoteWebURL(string(output))
+}
+
+// RemoteRepo returns a checkout's remote as HOST/OWNER/REPO, the form gh
+// --repo takes, or "" when it isn't a recognisable hosted repository
+func RemoteRepo(worktreePath, remote string) string {
+	return strings.TrimPrefix(remoteWebURL(worktreePath, remote), "https://")
+}
+
+// RemoteOwner returns the account or group owning a checkout's remote
+// (acme for github.com/acme/app), or ""
+func RemoteOwner(worktreePath, remote string) string {
+	parts := strings.Split(RemoteRepo(worktreePath, remote), "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+// BranchWebURL returns the web address of branch on a checkout's remote,
+// or "" when the remote isn't a recognisable hosted repository
+func BranchWebURL(worktreePath, remote, branch string) string {
+	repo := remoteWebURL(worktreePath, remote)
+	if repo == "" {
+		return ""
+	}
+
+	// GitLab puts repository pages under /-/
+	if strings.Contains(repo, "gitlab") {
+		return repo + "/-/tree/" + branch
+	}
+	return repo + "/tree/" + branch
+}
@@ -0,0 +1,51 @@
+package workspace
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestRemoteWebURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"git@github.com:acme/app.git", "https://github.com/acme/app"},
+		{"https://github.com/acme/app.git\n", "https://github.com/acme/app"},
+		{"https://token@gitlab.com/group/sub/app", "https://gitlab.com/group/sub/app"},
+		{"ssh://git@git.example.com:2222/acme/app.git", "https://git.example.com/acme/app"},
+		{"/srv/git/app.git", ""},
+		{"file:///srv/git/app.git", ""},
+	}
+
+	for _, tt := range tests {
+		if got := RemoteWebURL(tt.remote); got != tt.want {
+			t.Errorf("RemoteWebURL(%q) = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestRemoteRepo(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	exec.Command("git", "-C", repoPath, "remote", "add", "origin", "git@github.com:acme/app.git").Run()
+	exec.Command("git", "-C", repoPath, "remote", "add", "fork", "https://github.com/alice/app.git").Run()
+
+	if repo := RemoteRepo(repoPath, "origin"); repo != "github.com/acme/app" {
+		t.Errorf("Expected github.com/acme/app, got %q", repo)
+	}
+	if owner := RemoteOwner(repoPath, "fork"); owner != "alice" {
+		t.Errorf("Expected the fork owned by alice, got %q", owner)
+	}
+	if url := BranchWebURL(repoPath, "fork", "gforge/fix"); url != "https://github.com/alice/app/tree/gforge/fix" {
+		t.Errorf("Unexpected branch URL %q", url)
+	}
+	if RemoteRepo(repoPath, "missing") != "" || RemoteOwner(repoPath, "missing") != "" {
+		t.Error("Expected nothing for a missing remote")
+	}
+}
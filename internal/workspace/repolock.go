@@ -0,0 +1,85 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/logging"
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// repoLockFile is kept in a repository's git directory, shared by all its
+// worktrees
+const repoLockFile = "gforge-worktree.lock"
+
+// repoLockTimeout bounds the wait for another gforge to finish with a
+// repository's worktrees
+var repoLockTimeout = 2 * time.Minute
+
+// repoLockPoll is how often a waiting LockRepo tries again
+var repoLockPoll = 50 * time.Millisecond
+
+// LockRepo takes the lock gforge holds on a repository while it adds,
+// removes or prunes worktrees or fetches, waiting for any other gforge
+// process (or goroutine) holding it. Run side by side, those commands
+// race on git's own lock files. The returned function releases the lock.
+//
+// The lock is an flock(2) on a file in the git directory, so it only holds
+// between processes on unix and on a local filesystem; like tmux, which
+// gforge needs anyway, it does not build or work on Windows.
+func LockRepo(repoPath string) (func(), error) {
+	output, err := trace.Command("git", "-C", repoPath, "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find git directory of %s: %w", repoPath, err)
+	}
+	gitDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoPath, gitDir)
+	}
+
+	f, err := os.OpenFile(filepath.Join(gitDir, repoLockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worktree lock: %w", err)
+	}
+	deadline := time.Now().Add(repoLockTimeout)
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock worktrees of %s: %w", repoPath, err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out after %s waiting for another gforge to finish with the worktrees of %s", repoLockTimeout, repoPath)
+		}
+		time.Sleep(repoLockPoll)
+	}
+
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// GitWorktree runs `git worktree args...` in a repository holding its
+// worktree lock, retrying transient failures, and returns the combined
+// output
+func GitWorktree(repoPath string, log *logging.Logger, args ...string) ([]byte, error) {
+	unlock, err := LockRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return trace.Retry(trace.DefaultBackoff, log, func() *trace.Cmd {
+		return trace.Command("git", append([]string{"-C", repoPath, "worktree"}, args...)...)
+	})
+}
@@ -0,0 +1,88 @@
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockRepo(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	unlock, err := LockRepo(repo)
+	if err != nil {
+		t.Fatalf("LockRepo failed: %v", err)
+	}
+
+	// Another taker waits for the lock, even in the same process
+	taken := make(chan func())
+	go func() {
+		second, err := LockRepo(filepath.Join(repo, "."))
+		if err != nil {
+			t.Errorf("Second LockRepo failed: %v", err)
+		}
+		taken <- second
+	}()
+	select {
+	case <-taken:
+		t.Fatal("Expected the second taker to wait")
+	case <-time.After(200 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case second := <-taken:
+		second()
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the lock taken once released")
+	}
+
+	// A lock held too long gives up
+	unlock, _ = LockRepo(repo)
+	defer unlock()
+	timeout := repoLockTimeout
+	repoLockTimeout = 100 * time.Millisecond
+	defer func() { repoLockTimeout = timeout }()
+	if _, err := LockRepo(repo); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+}
+
+func TestGitWorktreeConcurrent(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+	base := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("g%d", i)
+			if output, err := GitWorktree(repo, nil, "add", "-b", "gforge/"+name, filepath.Join(base, name)); err != nil {
+				errs[i] = fmt.Errorf("%v: %s", err, output)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Worktree %d failed: %v", i, err)
+		}
+	}
+
+	worktrees, err := NewWorktreeManager(Config{BasePath: base}).List(repo)
+	if err != nil || len(worktrees) != len(errs)+1 {
+		t.Errorf("Expected %d worktrees besides the main one, got %d, %v", len(errs), len(worktrees)-1, err)
+	}
+}
@@ -0,0 +1,220 @@
+package workspace
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// SecretRule matches one kind of credential. When the pattern has a
+// capture group, the first group is the secret itself.
+type SecretRule struct {
+	ID          string
+	Description string
+	Pattern     *regexp.Regexp
+
+	// MinEntropy rejects matches whose secret is less random than this
+	// (bits per character), for rules that would otherwise catch
+	// placeholders and ordinary words
+	MinEntropy float64
+}
+
+// SecretRules are the rules added lines are checked against, after the
+// ones gitleaks ships for the same providers
+var SecretRules = []SecretRule{
+	{ID: "aws-access-key-id", Description: "AWS access key ID",
+		Pattern: regexp.MustCompile(`\b((?:A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16})\b`)},
+	{ID: "aws-secret-access-key", Description: "AWS secret access key",
+		Pattern:    regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|key).{0,20}?[=:]\s*['"]?([A-Za-z0-9/+=]{40})\b`),
+		MinEntropy: 4},
+	{ID: "github-token", Description: "GitHub token",
+		Pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{82})\b`)},
+	{ID: "gitlab-token", Description: "GitLab personal access token",
+		Pattern: regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20})\b`)},
+	{ID: "slack-token", Description: "Slack token",
+		Pattern: regexp.MustCompile(`\b(xox[baprs]-[A-Za-z0-9-]{10,})\b`)},
+	{ID: "slack-webhook", Description: "Slack incoming webhook",
+		Pattern: regexp.MustCompile(`(https://hooks\.slack\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]+)`)},
+	{ID: "stripe-key", Description: "Stripe live key",
+		Pattern: regexp.MustCompile(`\b([rs]k_live_[A-Za-z0-9]{24,})\b`)},
+	{ID: "google-api-key", Description: "Google API key",
+		Pattern: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`)},
+	{ID: "anthropic-api-key", Description: "Anthropic API key",
+		Pattern: regexp.MustCompile(`\b(sk-ant-[A-Za-z0-9_-]{20,})`)},
+	{ID: "openai-api-key", Description: "OpenAI API key",
+		Pattern: regexp.MustCompile(`\b(sk-(?:proj-)?[A-Za-z0-9_-]{20,}T3BlbkFJ[A-Za-z0-9_-]{20,})`)},
+	{ID: "private-key", Description: "Private key",
+		Pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`)},
+	{ID: "jwt", Description: "JSON web token",
+		Pattern: regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`)},
+	{ID: "generic-secret", Description: "Hard-coded secret",
+		Pattern:    regexp.MustCompile(`(?i)(?:api[_-]?key|secret|token|passw(?:or)?d)[a-z0-9_.-]{0,20}['"]?\s*[:=]\s*['"]([^'"\s]{12,})['"]`),
+		MinEntropy: 3.5},
+}
+
+// secretAllowMarkers on a line let it through, for test fixtures and
+// documented examples
+var secretAllowMarkers = []string{"gforge:allow-secret", "gitleaks:allow"}
+
+// SecretFinding is a credential on a line added by a diff
+type SecretFinding struct {
+	Commit string // The commit that added it, when scanning a log
+	File   string
+	Line   int
+	Rule   string
+	Match  string // Redacted, so findings can be shown and stored safely
+}
+
+func (f SecretFinding) String() string {
+	if f.Commit != "" {
+		return fmt.Sprintf("%s %s:%d: %s (%s)", Checkpoint{Commit: f.Commit}.Short(), f.File, f.Line, f.Rule, f.Match)
+	}
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Rule, f.Match)
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ScanDiffForSecrets checks the lines a unified diff adds against
+// SecretRules. Removed and context lines are ignored: taking a secret out
+// is not a leak. The diff may be a patch log, each commit's changes headed
+// by "commit <hash>", and findings then carry the commit.
+func ScanDiffForSecrets(diff string) []SecretFinding {
+	var (
+		findings []SecretFinding
+		commit   string
+		file     string
+		line     int
+
+		// Lines left in the current hunk, so content that happens to
+		// start with "+++" or "@@" isn't read as a header
+		oldLeft, newLeft int
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if oldLeft == 0 && newLeft == 0 {
+			switch {
+			case strings.HasPrefix(text, "commit "):
+				commit, file = strings.TrimPrefix(text, "commit "), ""
+			case strings.HasPrefix(text, "+++ "):
+				file = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+				if file == "/dev/null" {
+					file = ""
+				}
+			case strings.HasPrefix(text, "@@"):
+				if m := hunkHeader.FindStringSubmatch(text); m != nil {
+					oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[3])
+					line, _ = strconv.Atoi(m[2])
+				}
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+"):
+			if file != "" {
+				for _, f := range scanLine(file, line, text[1:]) {
+					f.Commit = commit
+					findings = append(findings, f)
+				}
+			}
+			line++
+			newLeft--
+		case strings.HasPrefix(text, "-"):
+			oldLeft--
+		case strings.HasPrefix(text, " "):
+			line++
+			oldLeft--
+			newLeft--
+		}
+	}
+
+	return findings
+}
+
+// hunkCount reads a hunk header's line count, which is 1 when omitted
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// scanLine returns the findings on one added line
+func scanLine(file string, line int, text string) []SecretFinding {
+	for _, marker := range secretAllowMarkers {
+		if strings.Contains(text, marker) {
+			return nil
+		}
+	}
+
+	var findings []SecretFinding
+	for _, rule := range SecretRules {
+		for _, m := range rule.Pattern.FindAllStringSubmatch(text, -1) {
+			secret := m[0]
+			if len(m) > 1 && m[1] != "" {
+				secret = m[1]
+			}
+			if rule.MinEntropy > 0 && entropy(secret) < rule.MinEntropy {
+				continue
+			}
+			findings = append(findings, SecretFinding{
+				File:  file,
+				Line:  line,
+				Rule:  rule.ID,
+				Match: redact(secret),
+			})
+		}
+	}
+	return findings
+}
+
+// entropy is the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	var h float64
+	n := float64(len([]rune(s)))
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// redact keeps enough of a secret to recognize it
+func redact(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", 8)
+}
+
+// ScanSecrets scans the commits on a worktree's branch since base for
+// credentials. Without a base, the commits not yet on the branch's
+// upstream are scanned. Each commit is read on its own, so a secret added
+// and later removed is still found: it stays in the branch's history.
+func ScanSecrets(worktreePath, base string) ([]SecretFinding, error) {
+	base, err := branchBase(worktreePath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := trace.Command("git", "-C", worktreePath, "log", "-p", "--no-color", "--no-ext-diff",
+		"-U0", "--format=commit %H", base+"..HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	return ScanDiffForSecrets(string(output)), nil
+}
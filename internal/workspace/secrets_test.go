@@ -0,0 +1,98 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Fake credentials are assembled at run time so the source itself doesn't
+// trip secret scanners
+var (
+	fakeGitHubToken = "ghp" + "_" + strings.Repeat("aB3dE5", 6)
+	fakeAWSKeyID    = "AKIA" + "IOSFODNN7EXAMPLE"
+)
+
+func TestScanDiffForSecrets(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/config.go b/config.go",
+		"--- a/config.go",
+		"+++ b/config.go",
+		"@@ -10,0 +11,3 @@ func setup() {",
+		`+	token := "` + fakeGitHubToken + `"`,
+		"+	region := \"us-east-1\"",
+		"+	key := \"" + fakeAWSKeyID + "\" // gforge:allow-secret",
+		"diff --git a/old.env b/old.env",
+		"--- a/old.env",
+		"+++ b/old.env",
+		"@@ -1 +1 @@",
+		"-AWS_KEY=" + fakeAWSKeyID,
+		"+AWS_KEY=",
+		"diff --git a/notes.md b/notes.md",
+		"--- a/notes.md",
+		"+++ b/notes.md",
+		"@@ -0,0 +1,2 @@",
+		"++++ " + fakeAWSKeyID,
+		"+password = \"hunter2hunter2\"",
+	}, "\n")
+
+	findings := ScanDiffForSecrets(diff)
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %v", findings)
+	}
+
+	if f := findings[0]; f.File != "config.go" || f.Line != 11 || f.Rule != "github-token" {
+		t.Errorf("Unexpected finding %s", f)
+	}
+	if strings.Contains(findings[0].Match, fakeGitHubToken[8:]) {
+		t.Errorf("Expected the match redacted, got %s", findings[0].Match)
+	}
+	// Added lines that look like diff headers are still content
+	if f := findings[1]; f.File != "notes.md" || f.Line != 1 || f.Rule != "aws-access-key-id" {
+		t.Errorf("Unexpected finding %s", f)
+	}
+}
+
+func TestGenericSecretEntropy(t *testing.T) {
+	if findings := scanLine("a.go", 1, `password = "passwordpassword"`); len(findings) != 0 {
+		t.Errorf("Expected a low-entropy value ignored, got %v", findings)
+	}
+	if findings := scanLine("a.go", 1, `api_key: "q8Zr2LkV9xW4mTp7"`); len(findings) != 1 {
+		t.Errorf("Expected a random value reported, got %v", findings)
+	}
+}
+
+func TestScanSecrets(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	repo, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	base, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+
+	os.WriteFile(filepath.Join(repo, "deploy.sh"), []byte("export GH_TOKEN="+fakeGitHubToken+"\n"), 0644)
+	exec.Command("git", "-C", repo, "add", ".").Run()
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-m", "Add deploy").Run()
+
+	added, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+
+	// Taking the token out in a later commit leaves it in the history
+	os.WriteFile(filepath.Join(repo, "deploy.sh"), []byte("export GH_TOKEN=$TOKEN\n"), 0644)
+	exec.Command("git", "-C", repo, "commit", "--no-gpg-sign", "-am", "Read the token from the env").Run()
+
+	findings, err := ScanSecrets(repo, strings.TrimSpace(string(base)))
+	if err != nil {
+		t.Fatalf("ScanSecrets failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].File != "deploy.sh" || findings[0].Commit != strings.TrimSpace(string(added)) {
+		t.Errorf("Expected the token found in the commit that added it, got %v", findings)
+	}
+
+	// Without a base or upstream there is nothing to compare against
+	if _, err := ScanSecrets(repo, ""); err == nil {
+		t.Error("Expected an error without a base or upstream")
+	}
+}
@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/astoreyai/goblin-forge/internal/trace"
+)
+
+// HasSubmodules reports whether a checkout declares git submodules
+func HasSubmodules(worktreePath string) bool {
+	_, err := os.Stat(filepath.Join(worktreePath, ".gitmodules"))
+	return err == nil
+}
+
+// UpdateSubmodules initialises and checks out a checkout's submodules,
+// recursively. A depth above zero makes shallow clones of them.
+func UpdateSubmodules(worktreePath string, depth int) error {
+	if !HasSubmodules(worktreePath) {
+		return nil
+	}
+
+	args := []string{"-C", worktreePath, "submodule", "update", "--init", "--recursive"}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	output, err := trace.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to update submodules: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// submodulePaths lists a checkout's initialised submodules, nested ones
+// included, relative to worktreePath and parents before children
+func submodulePaths(worktreePath string) []string {
+	if !HasSubmodules(worktreePath) {
+		return nil
+	}
+
+	output, err := trace.Command("git", "-C", worktreePath, "submodule", "status", "--recursive").Output()
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// "-" marks a submodule that was never initialised
+		if line == "" || line[0] == '-' {
+			continue
+		}
+		if fields := strings.Fields(line[1:]); len(fields) >= 2 {
+			paths = append(paths, fields[1])
+		}
+	}
+	return paths
+}
+
+// commitSubmodules commits changes inside each submodule, innermost first,
+// so the parent commit records their new commits
+func commitSubmodules(worktreePath, message string) error {
+	paths := submodulePaths(worktreePath)
+	for i := len(paths) - 1; i >= 0; i-- {
+		dir := filepath.Join(worktreePath, paths[i])
+
+		status, err := trace.Command("git", "-C", dir, "status", "--porcelain").Output()
+		if err != nil {
+			return fmt.Errorf("failed to check submodule %s: %w", paths[i], err)
+		}
+		if strings.TrimSpace(string(status)) == "" {
+			continue
+		}
+
+		if output, err := trace.Command("git", "-C", dir, "add", "-A").CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to stage submodule %s: %w\nOutput: %s", paths[i], err, string(output))
+		}
+		output, err := trace.Command("git", "-C", dir, "commit", "--no-gpg-sign", "-m", message).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to commit submodule %s: %w\nOutput: %s", paths[i], err, string(output))
+		}
+	}
+	return nil
+}
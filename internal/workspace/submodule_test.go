@@ -0,0 +1,78 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSubmodules(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	// Local submodules are cloned over the file protocol
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	libPath, libCleanup := createTestRepo(t)
+	defer libCleanup()
+	repoPath, repoCleanup := createTestRepo(t)
+	defer repoCleanup()
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git(repoPath, "submodule", "add", libPath, "lib")
+	git(repoPath, "commit", "-m", "Add lib")
+
+	mgr := NewWorktreeManager(Config{BasePath: t.TempDir(), Submodules: true, SubmoduleDepth: 1})
+	wt, err := mgr.Create(repoPath, "sub-test", "gforge/sub")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	libReadme := filepath.Join(wt.Path, "lib", "README.md")
+	if _, err := os.Stat(libReadme); err != nil {
+		t.Fatalf("Expected the submodule checked out: %v", err)
+	}
+	for _, dir := range []string{wt.Path, filepath.Join(wt.Path, "lib")} {
+		git(dir, "config", "user.email", "test@test.com")
+		git(dir, "config", "user.name", "Test")
+	}
+
+	os.WriteFile(libReadme, []byte("# Lib\nchanged\n"), 0644)
+
+	changes, err := mgr.GetChanges(wt.Path)
+	if err != nil {
+		t.Fatalf("GetChanges failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0] != "lib/README.md" {
+		t.Errorf("Expected the file inside the submodule, got %v", changes)
+	}
+
+	diff, err := mgr.GetDiff(wt.Path, false)
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+changed") {
+		t.Errorf("Expected the submodule's diff, got %q", diff)
+	}
+
+	if _, err := mgr.Commit(wt.Path, "Change lib"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if changes, _ := mgr.GetChanges(wt.Path); len(changes) != 0 {
+		t.Errorf("Expected everything committed, got %v", changes)
+	}
+	log, _ := exec.Command("git", "-C", filepath.Join(wt.Path, "lib"), "log", "-1", "--format=%s").Output()
+	if strings.TrimSpace(string(log)) != "Change lib" {
+		t.Errorf("Expected the change committed in the submodule, got %q", log)
+	}
+}
@@ -0,0 +1,153 @@
+package workspace
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Toolchain describes a language toolchain as seen from a worktree
+type Toolchain struct {
+	Name    string
+	Binary  string
+	Path    string
+	Version string
+	Found   bool
+}
+
+// toolchainProbe defines how to locate and version a toolchain
+type toolchainProbe struct {
+	name   string
+	binary string
+	args   []string
+}
+
+var toolchainProbes = []toolchainProbe{
+	{"go", "go", []string{"version"}},
+	{"node", "node", []string{"--version"}},
+	{"python", "python3", []string{"--version"}},
+	{"rust", "cargo", []string{"--version"}},
+	{"ruby", "ruby", []string{"--version"}},
+	{"elixir", "elixir", []string{"--version"}},
+	{"java", "java", []string{"-version"}},
+	{"dotnet", "dotnet", []string{"--version"}},
+}
+
+// projectToolchains maps detected project types to the toolchains they need
+var projectToolchains = map[string][]string{
+	"golang":        {"go"},
+	"gin":           {"go"},
+	"nodejs":        {"node"},
+	"nodejs-bun":    {"node"},
+	"nodejs-pnpm":   {"node"},
+	"nodejs-yarn":   {"node"},
+	"nextjs":        {"node"},
+	"vite":          {"node"},
+	"remix":         {"node"},
+	"astro":         {"node"},
+	"python":        {"python"},
+	"python-uv":     {"python"},
+	"python-poetry": {"python"},
+	"python-pipenv": {"python"},
+	"fastapi":       {"python"},
+	"django":        {"python"},
+	"flask":         {"python"},
+	"rust":          {"rust"},
+	"actix":         {"rust"},
+	"ruby":          {"ruby"},
+	"rails":         {"ruby"},
+	"elixir":        {"elixir"},
+	"phoenix":       {"elixir"},
+	"java-maven":    {"java"},
+	"java-gradle":   {"java"},
+	"dotnet":        {"dotnet"},
+}
+
+// probeTimeout caps each version command; shims that download a missing
+// version on first use would otherwise stall status
+var probeTimeout = 5 * time.Second
+
+// DetectToolchains finds each toolchain's binary on PATH and runs its
+// version command in workdir. Probes run in parallel. Version managers
+// aren't consulted: a shim that picks its version by directory reports
+// the worktree's, anything else (nvm's shell function) the PATH binary's.
+func DetectToolchains(workdir string) []Toolchain {
+	toolchains := make([]Toolchain, len(toolchainProbes))
+
+	var wg sync.WaitGroup
+	for i, probe := range toolchainProbes {
+		toolchains[i] = Toolchain{Name: probe.name, Binary: probe.binary}
+
+		path, err := exec.LookPath(probe.binary)
+		if err != nil {
+			continue
+		}
+		toolchains[i].Path = path
+		toolchains[i].Found = true
+
+		wg.Add(1)
+		go func(tc *Toolchain, probe toolchainProbe) {
+			defer wg.Done()
+			tc.Version = probeVersion(probe, workdir)
+		}(&toolchains[i], probe)
+	}
+	wg.Wait()
+
+	return toolchains
+}
+
+// RequiredToolchains returns the toolchains a project type needs
+func RequiredToolchains(projectType string) []string {
+	return projectToolchains[projectType]
+}
+
+// MissingToolchains returns the required toolchains not found in a set
+func MissingToolchains(projectType string, available []Toolchain) []string {
+	found := make(map[string]bool)
+	for _, tc := range available {
+		if tc.Found {
+			found[tc.Name] = true
+		}
+	}
+
+	var missing []string
+	for _, name := range RequiredToolchains(projectType) {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// probeVersion runs a toolchain's version command and extracts the version
+func probeVersion(probe toolchainProbe, workdir string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, probe.binary, probe.args...)
+	cmd.Dir = workdir
+	cmd.WaitDelay = 100 * time.Millisecond
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "unknown"
+	}
+	return parseVersion(string(output))
+}
+
+// parseVersion pulls the first version-looking token out of tool output
+func parseVersion(output string) string {
+	line := strings.TrimSpace(strings.SplitN(output, "\n", 2)[0])
+	for _, field := range strings.Fields(line) {
+		field = strings.Trim(field, `"`)
+		v := strings.TrimPrefix(strings.TrimPrefix(field, "go"), "v")
+		if len(v) > 0 && v[0] >= '0' && v[0] <= '9' && strings.Contains(v, ".") {
+			return v
+		}
+	}
+	if line == "" {
+		return "unknown"
+	}
+	return line
+}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{"go version go1.22.3 linux/amd64\n", "1.22.3"},
+		{"v20.11.1\n", "20.11.1"},
+		{"Python 3.12.2\n", "3.12.2"},
+		{"cargo 1.77.0 (3fe68eabf 2024-02-29)\n", "1.77.0"},
+		{`openjdk version "21.0.2" 2024-01-16` + "\n", "21.0.2"},
+		{"", "unknown"},
+	}
+
+	for _, tc := range tests {
+		if got := parseVersion(tc.output); got != tc.expected {
+			t.Errorf("parseVersion(%q) = %q, want %q", tc.output, got, tc.expected)
+		}
+	}
+}
+
+func TestMissingToolchains(t *testing.T) {
+	available := []Toolchain{
+		{Name: "go", Found: true},
+		{Name: "node", Found: false},
+	}
+
+	if missing := MissingToolchains("golang", available); len(missing) != 0 {
+		t.Errorf("Expected nothing missing for golang, got %v", missing)
+	}
+
+	missing := MissingToolchains("nextjs", available)
+	if len(missing) != 1 || missing[0] != "node" {
+		t.Errorf("Expected [node] missing for nextjs, got %v", missing)
+	}
+
+	if missing := MissingToolchains("unknown-type", available); len(missing) != 0 {
+		t.Errorf("Expected nothing missing for unknown type, got %v", missing)
+	}
+}
+
+func TestDetectToolchains(t *testing.T) {
+	toolchains := DetectToolchains(t.TempDir())
+	if len(toolchains) != len(toolchainProbes) {
+		t.Fatalf("Expected %d toolchains, got %d", len(toolchainProbes), len(toolchains))
+	}
+
+	for _, tc := range toolchains {
+		if tc.Found && tc.Path == "" {
+			t.Errorf("Found toolchain %s should have a path", tc.Name)
+		}
+		if !tc.Found && tc.Version != "" {
+			t.Errorf("Missing toolchain %s should have no version", tc.Name)
+		}
+	}
+}
@@ -4,15 +4,23 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/astoreyai/goblin-forge/internal/errs"
+	"github.com/astoreyai/goblin-forge/internal/trace"
 )
 
 // WorktreeManager handles git worktree operations
 type WorktreeManager struct {
-	basePath string
+	basePath       string
+	submodules     bool
+	submoduleDepth int
+	lfs            bool
+	branches       BranchPolicy
+	ignore         Ignore
 }
 
 // Worktree represents a git worktree
@@ -27,6 +35,21 @@ type Worktree struct {
 // Config holds worktree manager configuration
 type Config struct {
 	BasePath string
+
+	// Submodules checks out submodules in new worktrees, shallow when
+	// SubmoduleDepth is above zero
+	Submodules     bool
+	SubmoduleDepth int
+
+	// LFS fetches Git LFS content in new worktrees of repositories that
+	// use it
+	LFS bool
+
+	// Branches refuses creating or pushing protected branches
+	Branches BranchPolicy
+
+	// Ignore leaves files out of GetChanges, GetDiff and GetDiffStat
+	Ignore Ignore
 }
 
 // NewWorktreeManager creates a new worktree manager
@@ -40,7 +63,12 @@ func NewWorktreeManager(cfg Config) *WorktreeManager {
 	os.MkdirAll(cfg.BasePath, 0755)
 
 	return &WorktreeManager{
-		basePath: cfg.BasePath,
+		basePath:       cfg.BasePath,
+		submodules:     cfg.Submodules,
+		submoduleDepth: cfg.SubmoduleDepth,
+		lfs:            cfg.LFS,
+		branches:       cfg.Branches,
+		ignore:         cfg.Ignore,
 	}
 }
 
@@ -51,12 +79,16 @@ func (m *WorktreeManager) Create(repoPath, worktreeID, branchName string) (*Work
 		return nil, fmt.Errorf("not a git repository: %s", repoPath)
 	}
 
+	if err := m.branches.Check("create a worktree on", branchName); err != nil {
+		return nil, err
+	}
+
 	// Generate worktree path
 	worktreePath := filepath.Join(m.basePath, worktreeID)
 
 	// Check if worktree path already exists
 	if _, err := os.Stat(worktreePath); err == nil {
-		return nil, fmt.Errorf("worktree path already exists: %s", worktreePath)
+		return nil, fmt.Errorf("%w: %s", errs.ErrWorktreeExists, worktreePath)
 	}
 
 	// Fetch latest from remote (optional, ignore errors)
@@ -65,20 +97,31 @@ func (m *WorktreeManager) Create(repoPath, worktreeID, branchName string) (*Work
 	// Check if branch already exists
 	branchExists := m.branchExists(repoPath, branchName)
 
-	var cmd *exec.Cmd
-	if branchExists {
-		// Use existing branch
-		cmd = exec.Command("git", "-C", repoPath, "worktree", "add", worktreePath, branchName)
-	} else {
+	args := []string{"add", worktreePath, branchName}
+	if !branchExists {
 		// Create new branch
-		cmd = exec.Command("git", "-C", repoPath, "worktree", "add", "-b", branchName, worktreePath)
+		args = []string{"add", "-b", branchName, worktreePath}
 	}
 
-	output, err := cmd.CombinedOutput()
+	output, err := GitWorktree(repoPath, nil, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create worktree: %w\nOutput: %s", err, string(output))
 	}
 
+	// A worktree starts with empty submodule directories
+	if m.submodules {
+		if err := UpdateSubmodules(worktreePath, m.submoduleDepth); err != nil {
+			m.Remove(worktreePath, true)
+			return nil, err
+		}
+	}
+	if m.lfs {
+		if err := SetupLFS(worktreePath); err != nil {
+			m.Remove(worktreePath, true)
+			return nil, err
+		}
+	}
+
 	// Get commit hash
 	commitHash := m.getHeadCommit(worktreePath)
 
@@ -106,26 +149,23 @@ func (m *WorktreeManager) Remove(worktreePath string, force bool) error {
 	}
 
 	// Remove worktree using git
-	args := []string{"-C", mainRepo, "worktree", "remove", worktreePath}
+	args := []string{"remove", worktreePath}
 	if force {
 		args = append(args, "--force")
 	}
 
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
+	if _, err := GitWorktree(mainRepo, nil, args...); err != nil {
 		// Try force remove if regular remove fails
 		if !force {
 			return m.Remove(worktreePath, true)
 		}
-		// Last resort: remove directory manually
-		os.RemoveAll(worktreePath)
-		// Prune worktrees
-		exec.Command("git", "-C", mainRepo, "worktree", "prune").Run()
-		return nil
+		// Last resort: remove directory manually and prune its entry
+		if err := os.RemoveAll(worktreePath); err != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+		return m.Prune(mainRepo)
 	}
 
-	_ = output
 	return nil
 }
 
@@ -135,7 +175,7 @@ func (m *WorktreeManager) List(repoPath string) ([]*Worktree, error) {
 		return nil, fmt.Errorf("not a git repository: %s", repoPath)
 	}
 
-	cmd := exec.Command("git", "-C", repoPath, "worktree", "list", "--porcelain")
+	cmd := trace.Command("git", "-C", repoPath, "worktree", "list", "--porcelain")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
@@ -207,9 +247,31 @@ func (m *WorktreeManager) Get(worktreePath string) (*Worktree, error) {
 	}, nil
 }
 
-// GetChanges returns the list of changed files in a worktree
+// GetChanges returns the list of changed files in a worktree, including
+// files changed inside its submodules (as paths from the worktree root),
+// less the ignored ones
 func (m *WorktreeManager) GetChanges(worktreePath string) ([]string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "status", "--porcelain")
+	changes, err := changedFiles(worktreePath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range submodulePaths(worktreePath) {
+		subChanges, err := changedFiles(filepath.Join(worktreePath, sub), sub)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, subChanges...)
+	}
+
+	return m.ignore.Filter(changes), nil
+}
+
+// changedFiles lists the changed files in one checkout, prefixed with
+// prefix. A submodule is only listed itself when its commit moved; the
+// files changed inside it are listed from its own checkout.
+func changedFiles(dir, prefix string) ([]string, error) {
+	cmd := trace.Command("git", "-C", dir, "status", "--porcelain", "--ignore-submodules=dirty")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get changes: %w", err)
@@ -220,21 +282,25 @@ func (m *WorktreeManager) GetChanges(worktreePath string) ([]string, error) {
 	for scanner.Scan() {
 		line := scanner.Text()
 		if len(line) > 3 {
-			changes = append(changes, strings.TrimSpace(line[3:]))
+			changes = append(changes, path.Join(prefix, strings.TrimSpace(line[3:])))
 		}
 	}
 
 	return changes, nil
 }
 
-// GetDiff returns the diff for a worktree
+// GetDiff returns the diff for a worktree, with changes inside submodules
+// shown as diffs of their files and ignored files left out
 func (m *WorktreeManager) GetDiff(worktreePath string, staged bool) (string, error) {
-	args := []string{"-C", worktreePath, "diff"}
+	args := []string{"-C", worktreePath, "diff", "--submodule=diff"}
 	if staged {
 		args = append(args, "--staged")
 	}
+	if specs := m.ignore.Pathspecs(); specs != nil {
+		args = append(append(args, "--"), specs...)
+	}
 
-	cmd := exec.Command("git", args...)
+	cmd := trace.Command("git", args...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get diff: %w", err)
@@ -243,16 +309,25 @@ func (m *WorktreeManager) GetDiff(worktreePath string, staged bool) (string, err
 	return string(output), nil
 }
 
-// Commit commits changes in a worktree
+// Commit commits changes in a worktree. Changes inside submodules are
+// committed there first, with the same message, and the parent commit
+// records the new submodule commits.
 func (m *WorktreeManager) Commit(worktreePath, message string) (string, error) {
+	if err := requireLFS(worktreePath); err != nil {
+		return "", err
+	}
+	if err := commitSubmodules(worktreePath, message); err != nil {
+		return "", err
+	}
+
 	// Stage all changes
-	stageCmd := exec.Command("git", "-C", worktreePath, "add", "-A")
+	stageCmd := trace.Command("git", "-C", worktreePath, "add", "-A")
 	if output, err := stageCmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("failed to stage changes: %w\nOutput: %s", err, string(output))
 	}
 
 	// Commit (with --no-gpg-sign to avoid signing issues in automated environments)
-	commitCmd := exec.Command("git", "-C", worktreePath, "commit", "--no-gpg-sign", "-m", message)
+	commitCmd := trace.Command("git", "-C", worktreePath, "commit", "--no-gpg-sign", "-m", message)
 	output, err := commitCmd.CombinedOutput()
 	if err != nil {
 		// Check if there's nothing to commit
@@ -267,22 +342,50 @@ func (m *WorktreeManager) Commit(worktreePath, message string) (string, error) {
 	return hash, nil
 }
 
-// Push pushes the worktree branch to remote
-func (m *WorktreeManager) Push(worktreePath string, force bool) error {
+// PushOptions control how a worktree's branch is pushed
+type PushOptions struct {
+	Remote string // Defaults to origin
+
+	// ForceWithLease overwrites the remote branch, but only if it is still
+	// where this checkout last saw it
+	ForceWithLease bool
+
+	// Env is added to git's environment, e.g. a project's credentials
+	Env []string
+}
+
+// Push pushes the worktree branch to a remote and sets it as upstream,
+// returning the branch name
+func (m *WorktreeManager) Push(worktreePath string, opts PushOptions) (string, error) {
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+
 	branch := m.getCurrentBranch(worktreePath)
+	if branch == "" || branch == "HEAD" {
+		return "", fmt.Errorf("no branch checked out in %s", worktreePath)
+	}
+	if err := m.branches.Check("push to", branch); err != nil {
+		return "", err
+	}
+	if err := pushLFS(worktreePath, opts.Remote, branch, opts.Env); err != nil {
+		return "", err
+	}
 
-	args := []string{"-C", worktreePath, "push", "-u", "origin", branch}
-	if force {
-		args = append(args[:len(args)-2], "--force", args[len(args)-2], args[len(args)-1])
+	args := []string{"-C", worktreePath, "push", "-u"}
+	if opts.ForceWithLease {
+		args = append(args, "--force-with-lease")
 	}
+	args = append(args, opts.Remote, branch)
 
-	cmd := exec.Command("git", args...)
+	cmd := trace.Command("git", args...)
+	cmd.Env = append(os.Environ(), opts.Env...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to push: %w\nOutput: %s", err, string(output))
+		return "", fmt.Errorf("failed to push: %w\nOutput: %s", err, string(output))
 	}
 
-	return nil
+	return branch, nil
 }
 
 // Stash stashes changes in a worktree
@@ -292,7 +395,7 @@ func (m *WorktreeManager) Stash(worktreePath, message string) error {
 		args = append(args, "-m", message)
 	}
 
-	cmd := exec.Command("git", args...)
+	cmd := trace.Command("git", args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to stash: %w\nOutput: %s", err, string(output))
@@ -303,7 +406,7 @@ func (m *WorktreeManager) Stash(worktreePath, message string) error {
 
 // StashPop pops the latest stash
 func (m *WorktreeManager) StashPop(worktreePath string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "stash", "pop")
+	cmd := trace.Command("git", "-C", worktreePath, "stash", "pop")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to pop stash: %w\nOutput: %s", err, string(output))
@@ -314,8 +417,7 @@ func (m *WorktreeManager) StashPop(worktreePath string) error {
 
 // Prune removes stale worktree entries
 func (m *WorktreeManager) Prune(repoPath string) error {
-	cmd := exec.Command("git", "-C", repoPath, "worktree", "prune")
-	output, err := cmd.CombinedOutput()
+	output, err := GitWorktree(repoPath, nil, "prune")
 	if err != nil {
 		return fmt.Errorf("failed to prune: %w\nOutput: %s", err, string(output))
 	}
@@ -368,17 +470,22 @@ func (m *WorktreeManager) isGitRepo(path string) bool {
 }
 
 func (m *WorktreeManager) branchExists(repoPath, branch string) bool {
-	cmd := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", branch)
+	cmd := trace.Command("git", "-C", repoPath, "rev-parse", "--verify", branch)
 	return cmd.Run() == nil
 }
 
 func (m *WorktreeManager) gitFetch(repoPath string) {
-	cmd := exec.Command("git", "-C", repoPath, "fetch", "--all", "--prune")
+	unlock, err := LockRepo(repoPath)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	cmd := trace.Command("git", "-C", repoPath, "fetch", "--all", "--prune")
 	cmd.Run() // Ignore errors
 }
 
 func (m *WorktreeManager) getHeadCommit(worktreePath string) string {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--short", "HEAD")
+	cmd := trace.Command("git", "-C", worktreePath, "rev-parse", "--short", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -387,7 +494,7 @@ func (m *WorktreeManager) getHeadCommit(worktreePath string) string {
 }
 
 func (m *WorktreeManager) getCurrentBranch(worktreePath string) string {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := trace.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -515,3 +515,41 @@ branch refs/heads/feature/test
 		t.Errorf("Expected branch 'feature/test', got '%s'", worktrees[1].Branch)
 	}
 }
+
+func TestPush(t *testing.T) {
+	if !gitAvailable() {
+		t.Skip("git not available")
+	}
+
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	bare := t.TempDir()
+	exec.Command("git", "init", "--bare", "-q", bare).Run()
+	exec.Command("git", "-C", repoPath, "remote", "add", "origin", bare).Run()
+
+	mgr := NewWorktreeManager(Config{BasePath: t.TempDir()})
+	wt, err := mgr.Create(repoPath, "push-test", "gforge/push")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(wt.Path, "work.txt"), []byte("v1\n"), 0644)
+	mgr.Commit(wt.Path, "First")
+
+	branch, err := mgr.Push(wt.Path, PushOptions{})
+	if err != nil || branch != "gforge/push" {
+		t.Fatalf("Push failed: %s, %v", branch, err)
+	}
+	if err := exec.Command("git", "-C", bare, "rev-parse", "--verify", "gforge/push").Run(); err != nil {
+		t.Error("Expected the branch on the remote")
+	}
+
+	// Rewritten history needs --force-with-lease
+	exec.Command("git", "-C", wt.Path, "commit", "--amend", "-q", "-m", "Amended").Run()
+	if _, err := mgr.Push(wt.Path, PushOptions{}); err == nil {
+		t.Error("Expected a plain push of rewritten history rejected")
+	}
+	if _, err := mgr.Push(wt.Path, PushOptions{ForceWithLease: true}); err != nil {
+		t.Errorf("Expected --force-with-lease to push, got %v", err)
+	}
+}
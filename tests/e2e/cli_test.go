@@ -68,6 +68,23 @@ func runCLI(args ...string) (string, string, error) {
 	return stdout.String(), stderr.String(), err
 }
 
+// runCLIIsolated runs the CLI with its own config and data directories
+func runCLIIsolated(t *testing.T, args ...string) (string, string, error) {
+	tmpDir := t.TempDir()
+
+	cmd := exec.Command(binaryPath, args...)
+	cmd.Env = append(os.Environ(),
+		"XDG_CONFIG_HOME="+filepath.Join(tmpDir, "config"),
+		"XDG_DATA_HOME="+filepath.Join(tmpDir, "data"))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
 func TestCLIVersion(t *testing.T) {
 	stdout, _, err := runCLI("version")
 	if err != nil {
@@ -217,10 +234,10 @@ func TestCLISpawnMissingArgs(t *testing.T) {
 func TestCLIStopMissingArgs(t *testing.T) {
 	_, stderr, err := runCLI("stop")
 	if err == nil {
-		t.Error("stop without args should fail")
+		t.Error("stop without args or selection flags should fail")
 	}
 
-	if !strings.Contains(stderr, "accepts 1 arg") {
+	if !strings.Contains(stderr, "requires a goblin name") {
 		t.Errorf("Expected args error, got: %s", stderr)
 	}
 }
@@ -228,55 +245,55 @@ func TestCLIStopMissingArgs(t *testing.T) {
 func TestCLIKillMissingArgs(t *testing.T) {
 	_, stderr, err := runCLI("kill")
 	if err == nil {
-		t.Error("kill without args should fail")
+		t.Error("kill without args or selection flags should fail")
 	}
 
-	if !strings.Contains(stderr, "accepts 1 arg") {
+	if !strings.Contains(stderr, "requires a goblin name") {
 		t.Errorf("Expected args error, got: %s", stderr)
 	}
 }
 
 func TestCLIDiffMissingArgs(t *testing.T) {
-	_, stderr, err := runCLI("diff")
+	_, stderr, err := runCLIIsolated(t, "diff")
 	if err == nil {
-		t.Error("diff without args should fail")
+		t.Error("diff without args or current goblin should fail")
 	}
 
-	if !strings.Contains(stderr, "accepts 1 arg") {
-		t.Errorf("Expected args error, got: %s", stderr)
+	if !strings.Contains(stderr, "no current goblin") {
+		t.Errorf("Expected current goblin error, got: %s", stderr)
 	}
 }
 
 func TestCLILogsMissingArgs(t *testing.T) {
-	_, stderr, err := runCLI("logs")
+	_, stderr, err := runCLIIsolated(t, "logs")
 	if err == nil {
-		t.Error("logs without args should fail")
+		t.Error("logs without args or current goblin should fail")
 	}
 
-	if !strings.Contains(stderr, "accepts 1 arg") {
-		t.Errorf("Expected args error, got: %s", stderr)
+	if !strings.Contains(stderr, "no current goblin") {
+		t.Errorf("Expected current goblin error, got: %s", stderr)
 	}
 }
 
 func TestCLIAttachMissingArgs(t *testing.T) {
-	_, stderr, err := runCLI("attach")
+	_, stderr, err := runCLIIsolated(t, "attach")
 	if err == nil {
-		t.Error("attach without args should fail")
+		t.Error("attach without args or current goblin should fail")
 	}
 
-	if !strings.Contains(stderr, "accepts 1 arg") {
-		t.Errorf("Expected args error, got: %s", stderr)
+	if !strings.Contains(stderr, "no current goblin") {
+		t.Errorf("Expected current goblin error, got: %s", stderr)
 	}
 }
 
 func TestCLITaskMissingGoblin(t *testing.T) {
-	_, stderr, err := runCLI("task", "do something")
+	_, stderr, err := runCLIIsolated(t, "task", "do something")
 	if err == nil {
-		t.Error("task without --goblin should fail")
+		t.Error("task without --goblin or current goblin should fail")
 	}
 
-	if !strings.Contains(stderr, "required flag") || !strings.Contains(stderr, "goblin") {
-		t.Errorf("Expected required flag error, got: %s", stderr)
+	if !strings.Contains(stderr, "no current goblin") {
+		t.Errorf("Expected current goblin error, got: %s", stderr)
 	}
 }
 
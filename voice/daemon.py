@@ -28,14 +28,9 @@ from typing import Optional, Callable
 import numpy as np
 import sounddevice as sd
 
-# Optional imports with graceful fallback
-try:
-    from faster_whisper import WhisperModel
-    WHISPER_AVAILABLE = True
-except ImportError:
-    WHISPER_AVAILABLE = False
-    WhisperModel = None
+from transcribers import Transcriber, make_transcriber
 
+# Optional imports with graceful fallback
 try:
     from evdev import InputDevice, ecodes, list_devices
     EVDEV_AVAILABLE = True
@@ -50,17 +45,23 @@ logging.basicConfig(
 )
 logger = logging.getLogger('gforge-voice')
 
+# Seconds after a lone wake word in which speech is taken as the command
+WAKE_WINDOW = 5.0
+
 
 @dataclass
 class Config:
     """Voice daemon configuration"""
     socket_path: str = "/tmp/gforge-voice.sock"
-    model_size: str = "tiny"  # tiny, base, small, medium, large
+    model_size: str = "tiny"  # tiny ... large-v3, or backend:model (whisper.cpp:base.en, openai)
+    models_dir: Optional[str] = None  # Where downloaded models are kept
     device: str = "cpu"  # cpu, cuda, auto
     sample_rate: int = 16000
     channels: int = 1
     hotkey_device: Optional[str] = None  # Auto-detect if None
-    hotkey_key: str = "KEY_SCROLLLOCK"  # Default hotkey
+    hotkey_key: str = "KEY_SCROLLLOCK"  # Key name or combination, e.g. super+shift+g
+    wake_word: str = ""  # Phrase that starts a command; empty for hotkey only
+    language: str = "en"  # Transcription language, or auto
     min_recording_duration: float = 0.5  # Minimum seconds to record
     max_recording_duration: float = 30.0  # Maximum seconds to record
     silence_threshold: float = 0.01  # RMS threshold for silence detection
@@ -72,6 +73,13 @@ class CommandParser:
     """Parse voice transcriptions into gforge commands"""
 
     PATTERNS = [
+        # Answers to gforge asking for confirmation
+        (r"(?:yes|yeah|yep|confirm(?:ed)?|do it|go ahead)\W*$",
+         lambda m: {"action": "confirm"}),
+
+        (r"(?:no|nope|cancel|never mind|don't)\W*$",
+         lambda m: {"action": "cancel"}),
+
         # Spawn commands
         (r"spawn (?:a )?(?:new )?(?:goblin )?(?:called |named )?(\w+)(?: with | using )?(?:agent )?(\w+)?(?:(?: for | to )(.+))?",
          lambda m: {"action": "spawn", "name": m.group(1), "agent": m.group(2) or "claude", "task": m.group(3)}),
@@ -166,6 +174,48 @@ class CommandParser:
         return {"action": "unknown", "raw": text}
 
 
+# Modifier names in hotkey combinations, matching either side's key
+MODIFIERS = {
+    "super": ["KEY_LEFTMETA", "KEY_RIGHTMETA"],
+    "meta": ["KEY_LEFTMETA", "KEY_RIGHTMETA"],
+    "shift": ["KEY_LEFTSHIFT", "KEY_RIGHTSHIFT"],
+    "ctrl": ["KEY_LEFTCTRL", "KEY_RIGHTCTRL"],
+    "control": ["KEY_LEFTCTRL", "KEY_RIGHTCTRL"],
+    "alt": ["KEY_LEFTALT", "KEY_RIGHTALT"],
+}
+
+
+def parse_hotkey(spec: str):
+    """Split a hotkey such as super+shift+g or KEY_SCROLLLOCK into the
+    modifier groups that must be held and the evdev name of the key"""
+    parts = [p.strip() for p in spec.split("+") if p.strip()]
+    if not parts:
+        raise ValueError("empty hotkey")
+    modifiers = []
+    for part in parts[:-1]:
+        if part.lower() not in MODIFIERS:
+            raise ValueError(f"unknown modifier: {part}")
+        modifiers.append(MODIFIERS[part.lower()])
+    key = parts[-1]
+    if not key.upper().startswith("KEY_"):
+        key = "KEY_" + key
+    return modifiers, key.upper()
+
+
+def normalize(text: str) -> str:
+    """Lowercase text and drop punctuation, as transcripts vary in both"""
+    return re.sub(r"\s+", " ", re.sub(r"[^\w\s]", " ", text.lower())).strip()
+
+
+def strip_wake_word(text: str, wake_word: str) -> Optional[str]:
+    """Return what follows the wake word at the start of text, or None
+    when text doesn't start with it"""
+    said, wake = normalize(text), normalize(wake_word)
+    if not wake or not (said == wake or said.startswith(wake + " ")):
+        return None
+    return said[len(wake):].strip()
+
+
 class AudioRecorder:
     """Handle audio recording with silence detection"""
 
@@ -225,10 +275,12 @@ class VoiceDaemon:
         self.config = config
         self.parser = CommandParser()
         self.recorder = AudioRecorder(config)
-        self.model: Optional[WhisperModel] = None
+        self.transcriber: Optional[Transcriber] = None
         self.socket: Optional[socket.socket] = None
         self.running = False
         self.recording_active = False
+        self.clients = set()
+        self.armed_until = 0.0  # Until when speech after a lone wake word is a command
 
     async def start(self):
         """Start the voice daemon"""
@@ -238,14 +290,15 @@ class VoiceDaemon:
         for sig in (signal.SIGTERM, signal.SIGINT):
             signal.signal(sig, lambda *_: self._shutdown())
 
-        # Initialize Whisper model
-        await self._init_model()
-
-        # Create socket
+        # Create socket first, so gforge sees the daemon while the model loads
         await self._init_socket()
 
-        # Start hotkey listener
+        # Initialize the transcription backend
+        await self._init_transcriber()
+
+        # Start hotkey and wake word listeners
         asyncio.create_task(self._hotkey_listener())
+        asyncio.create_task(self._wake_word_listener())
 
         # Start socket server
         asyncio.create_task(self._socket_server())
@@ -253,40 +306,28 @@ class VoiceDaemon:
         logger.info(f"Voice daemon started (model={self.config.model_size})")
         logger.info(f"Listening on {self.config.socket_path}")
         logger.info(f"Hotkey: {self.config.hotkey_key}")
+        if self.config.wake_word:
+            logger.info(f"Wake word: {self.config.wake_word!r}")
 
         # Keep running
         while self.running:
             await asyncio.sleep(1)
 
-    async def _init_model(self):
-        """Initialize Whisper model"""
-        if not WHISPER_AVAILABLE:
+    async def _init_transcriber(self):
+        """Initialize the transcription backend, downloading its model if needed"""
+        logger.info(f"Loading transcription model: {self.config.model_size}...")
+        loop = asyncio.get_event_loop()
+        try:
+            self.transcriber = await loop.run_in_executor(
+                None, make_transcriber,
+                self.config.model_size, self.config.device, self.config.models_dir)
+        except ImportError:
             logger.warning("faster-whisper not installed - transcription disabled")
             return
-
-        logger.info(f"Loading Whisper model: {self.config.model_size}...")
-
-        # Determine compute type based on device
-        compute_type = "float32"
-        if self.config.device == "cuda":
-            compute_type = "float16"
-        elif self.config.device == "auto":
-            try:
-                import torch
-                if torch.cuda.is_available():
-                    self.config.device = "cuda"
-                    compute_type = "float16"
-                else:
-                    self.config.device = "cpu"
-            except ImportError:
-                self.config.device = "cpu"
-
-        self.model = WhisperModel(
-            self.config.model_size,
-            device=self.config.device,
-            compute_type=compute_type
-        )
-        logger.info(f"Model loaded on {self.config.device}")
+        except Exception as e:
+            logger.error(f"Could not load {self.config.model_size}: {e} - transcription disabled")
+            return
+        logger.info(f"Model loaded ({self.transcriber.name})")
 
     async def _init_socket(self):
         """Initialize Unix socket"""
@@ -327,14 +368,102 @@ class VoiceDaemon:
 
         logger.info(f"Hotkey listener attached to {device.name}")
 
-        # Get key code
-        key_code = getattr(ecodes, self.config.hotkey_key, ecodes.KEY_SCROLLLOCK)
-
+        # Get key codes
+        try:
+            modifiers, key = parse_hotkey(self.config.hotkey_key)
+        except ValueError as e:
+            logger.warning(f"Invalid hotkey {self.config.hotkey_key!r} ({e}) - using KEY_SCROLLLOCK")
+            modifiers, key = [], "KEY_SCROLLLOCK"
+        key_code = getattr(ecodes, key, ecodes.KEY_SCROLLLOCK)
+        modifier_codes = [{getattr(ecodes, name) for name in group} for group in modifiers]
+
+        held = set()
         async for event in device.async_read_loop():
-            if event.type == ecodes.EV_KEY and event.code == key_code:
-                if event.value == 1:  # Key down
+            if event.type != ecodes.EV_KEY:
+                continue
+            if event.value == 0:  # Key up
+                held.discard(event.code)
+                continue
+            held.add(event.code)
+            if event.code == key_code and event.value == 1:  # Key down
+                if all(held & group for group in modifier_codes):
                     await self._toggle_recording()
 
+    async def _wake_word_listener(self):
+        """Listen continuously, treating utterances that start with the
+        wake word as commands"""
+        if not self.config.wake_word:
+            return
+        if not self.transcriber:
+            logger.warning("Model not loaded - wake word disabled")
+            return
+
+        loop = asyncio.get_event_loop()
+        queue: asyncio.Queue = asyncio.Queue()
+
+        def callback(indata, frames, time_info, status):
+            loop.call_soon_threadsafe(queue.put_nowait, indata.copy())
+
+        rate = self.config.sample_rate
+        stream = sd.InputStream(
+            samplerate=rate,
+            channels=self.config.channels,
+            dtype='float32',
+            blocksize=rate // 10,
+            callback=callback
+        )
+        stream.start()
+
+        utterance, silent_for = [], 0.0
+        try:
+            while self.running:
+                chunk = await queue.get()
+                if self.recording_active:
+                    # The hotkey has the microphone
+                    utterance, silent_for = [], 0.0
+                    continue
+
+                loud = not self.recorder.is_silence(chunk)
+                if loud or utterance:
+                    utterance.append(chunk)
+                silent_for = 0.0 if loud else silent_for + len(chunk) / rate
+                if not utterance:
+                    continue
+
+                duration = sum(len(c) for c in utterance) / rate
+                if silent_for < self.config.silence_duration and duration < self.config.max_recording_duration:
+                    continue
+
+                audio = np.concatenate(utterance)
+                utterance, silent_for = [], 0.0
+                if duration - self.config.silence_duration < self.config.min_recording_duration:
+                    continue
+                await self._on_utterance(await self._transcribe(audio))
+        finally:
+            stream.stop()
+            stream.close()
+
+    async def _on_utterance(self, text: str):
+        """Handle speech heard while listening for the wake word"""
+        if not text:
+            return
+        rest = strip_wake_word(text, self.config.wake_word)
+        if rest is None:
+            if time.monotonic() > self.armed_until:
+                return
+            rest = text  # The command after a lone wake word
+
+        if not rest:
+            # Just the wake word: the next utterance is the command
+            self.armed_until = time.monotonic() + WAKE_WINDOW
+            if self.config.feedback_sounds:
+                await self._play_sound("start")
+            return
+
+        self.armed_until = 0.0
+        logger.info(f"Heard after wake word: {rest}")
+        await self._handle_text(rest)
+
     async def _toggle_recording(self):
         """Toggle recording state"""
         if not self.recording_active:
@@ -368,8 +497,11 @@ class VoiceDaemon:
             return
 
         logger.info(f"Transcribed: {text}")
+        await self._handle_text(text)
 
-        # Parse command
+    async def _handle_text(self, text: str):
+        """Parse a transcript and send it to gforge, which speaks the
+        confirmation"""
         command = self.parser.parse(text)
         logger.info(f"Command: {command}")
 
@@ -378,11 +510,11 @@ class VoiceDaemon:
 
     async def _transcribe(self, audio: np.ndarray) -> str:
         """Transcribe audio to text"""
-        if not self.model:
+        if not self.transcriber:
             logger.warning("Model not loaded, cannot transcribe")
             return ""
 
-        # Save to temp file (faster-whisper needs a file)
+        # Save to temp file (every backend takes a file)
         with tempfile.NamedTemporaryFile(suffix=".wav", delete=False) as f:
             import wave
             with wave.open(f.name, 'wb') as wf:
@@ -394,19 +526,28 @@ class VoiceDaemon:
             temp_path = f.name
 
         try:
-            segments, _ = self.model.transcribe(temp_path, language="en")
-            text = " ".join([s.text for s in segments])
-            return text.strip()
+            language = None if self.config.language in ("", "auto") else self.config.language
+            loop = asyncio.get_event_loop()
+            return await loop.run_in_executor(
+                None, self.transcriber.transcribe, temp_path, language)
+        except Exception as e:
+            logger.error(f"Transcription failed: {e}")
+            return ""
         finally:
             Path(temp_path).unlink()
 
     async def _send_command(self, command: dict):
         """Send command to gforge via socket"""
         # Broadcast to all connected clients
-        data = json.dumps(command).encode()
-        # The Go client will connect to receive commands
-        # For now, just log it
-        logger.info(f"Would send to gforge: {command}")
+        if not self.clients:
+            logger.info(f"No gforge listener connected, dropping: {command}")
+            return
+        for client in list(self.clients):
+            try:
+                await self._send_to_client(client, command)
+            except OSError as e:
+                logger.warning(f"Failed to send command: {e}")
+                self.clients.discard(client)
 
     async def _socket_server(self):
         """Handle incoming socket connections"""
@@ -427,6 +568,7 @@ class VoiceDaemon:
         client.setblocking(False)
 
         logger.info("Client connected")
+        self.clients.add(client)
 
         try:
             while self.running:
@@ -446,6 +588,7 @@ class VoiceDaemon:
                 except json.JSONDecodeError:
                     continue
         finally:
+            self.clients.discard(client)
             client.close()
             logger.info("Client disconnected")
 
@@ -461,6 +604,9 @@ class VoiceDaemon:
             await self._stop_recording()
             await self._send_to_client(client, {"status": "stopped"})
 
+        elif action == "exit_voice":
+            self._shutdown()
+
         elif action == "status":
             await self._send_to_client(client, {
                 "status": "ok",
@@ -471,7 +617,7 @@ class VoiceDaemon:
     async def _send_to_client(self, client: socket.socket, msg: dict):
         """Send message to client"""
         loop = asyncio.get_event_loop()
-        data = json.dumps(msg).encode()
+        data = json.dumps(msg).encode() + b"\n"
         await loop.sock_sendall(client, data)
 
     async def _play_sound(self, sound_type: str):
@@ -502,13 +648,19 @@ def main():
     parser.add_argument("--socket", default="/tmp/gforge-voice.sock",
                         help="Unix socket path")
     parser.add_argument("--model", default="tiny",
-                        choices=["tiny", "base", "small", "medium", "large"],
-                        help="Whisper model size")
+                        help="Whisper model (tiny, base, small, medium, large-v3), "
+                             "optionally behind a backend: local:, whisper.cpp: or openai[:model]")
+    parser.add_argument("--models-dir",
+                        help="Where downloaded models are kept")
     parser.add_argument("--device", default="auto",
                         choices=["cpu", "cuda", "auto"],
                         help="Compute device")
     parser.add_argument("--hotkey", default="KEY_SCROLLLOCK",
-                        help="Hotkey for push-to-talk")
+                        help="Hotkey for push-to-talk: a key name or combination (super+shift+g)")
+    parser.add_argument("--wake-word", default="",
+                        help="Phrase that starts a spoken command (e.g. 'hey goblin')")
+    parser.add_argument("--language", default="en",
+                        help="Transcription language, or auto")
     parser.add_argument("--no-sounds", action="store_true",
                         help="Disable feedback sounds")
     parser.add_argument("--debug", action="store_true",
@@ -522,8 +674,11 @@ def main():
     config = Config(
         socket_path=args.socket,
         model_size=args.model,
+        models_dir=args.models_dir,
         device=args.device,
         hotkey_key=args.hotkey,
+        wake_word=args.wake_word,
+        language=args.language,
         feedback_sounds=not args.no_sounds
     )
 
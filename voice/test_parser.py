@@ -5,7 +5,7 @@ import unittest
 import sys
 sys.path.insert(0, '.')
 
-from daemon import CommandParser
+from daemon import CommandParser, parse_hotkey, strip_wake_word
 
 
 class TestCommandParser(unittest.TestCase):
@@ -117,6 +117,35 @@ class TestCommandParser(unittest.TestCase):
         self.assertEqual(result["action"], "spawn")
         self.assertEqual(result["name"], "coder")
 
+    def test_answers(self):
+        for text in ["yes", "Confirm.", "go ahead"]:
+            self.assertEqual(self.parser.parse(text)["action"], "confirm", text)
+        for text in ["no", "Cancel!", "never mind"]:
+            self.assertEqual(self.parser.parse(text)["action"], "cancel", text)
+        self.assertEqual(self.parser.parse("no goblins")["action"], "unknown")
+
+
+class TestHotkeyAndWakeWord(unittest.TestCase):
+    """Test hotkey and wake word handling"""
+
+    def test_key_name(self):
+        self.assertEqual(parse_hotkey("KEY_SCROLLLOCK"), ([], "KEY_SCROLLLOCK"))
+
+    def test_combination(self):
+        modifiers, key = parse_hotkey("super+shift+g")
+        self.assertEqual(key, "KEY_G")
+        self.assertEqual(modifiers, [["KEY_LEFTMETA", "KEY_RIGHTMETA"], ["KEY_LEFTSHIFT", "KEY_RIGHTSHIFT"]])
+
+    def test_unknown_modifier(self):
+        with self.assertRaises(ValueError):
+            parse_hotkey("hyper+g")
+
+    def test_wake_word(self):
+        self.assertEqual(strip_wake_word("Hey, Goblin! List goblins.", "hey goblin"), "list goblins")
+        self.assertEqual(strip_wake_word("hey goblin", "hey goblin"), "")
+        self.assertIsNone(strip_wake_word("hey goblins everywhere", "hey goblin"))
+        self.assertIsNone(strip_wake_word("list goblins", "hey goblin"))
+
 
 if __name__ == "__main__":
     unittest.main()
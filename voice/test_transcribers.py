@@ -0,0 +1,83 @@
+#!/usr/bin/env python3
+"""Tests for the transcription backends"""
+
+import os
+import tempfile
+import unittest
+import sys
+from pathlib import Path
+from unittest import mock
+sys.path.insert(0, '.')
+
+from transcribers import (OpenAITranscriber, WhisperCppTranscriber, ggml_model_path,
+                          make_transcriber, parse_model_spec)
+
+
+class TestParseModelSpec(unittest.TestCase):
+    """Test choosing a backend from voice.model"""
+
+    def test_plain_size_is_local(self):
+        self.assertEqual(parse_model_spec("small"), ("local", "small"))
+        self.assertEqual(parse_model_spec("local:large-v3"), ("local", "large-v3"))
+
+    def test_backends(self):
+        self.assertEqual(parse_model_spec("whisper.cpp:base.en"), ("whisper.cpp", "base.en"))
+        self.assertEqual(parse_model_spec("cpp:tiny"), ("whisper.cpp", "tiny"))
+        self.assertEqual(parse_model_spec("openai"), ("openai", ""))
+        self.assertEqual(parse_model_spec("openai:gpt-4o-transcribe"), ("openai", "gpt-4o-transcribe"))
+
+    def test_unknown_backend(self):
+        with self.assertRaises(ValueError):
+            parse_model_spec("vosk:small")
+
+
+class TestWhisperCpp(unittest.TestCase):
+    """Test the whisper.cpp backend"""
+
+    def test_model_path(self):
+        self.assertEqual(ggml_model_path("base.en", "/models"), Path("/models/ggml-base.en.bin"))
+        self.assertEqual(ggml_model_path("/opt/ggml-tiny.bin", "/models"), Path("/opt/ggml-tiny.bin"))
+        with self.assertRaises(ValueError):
+            ggml_model_path("base en?", "/models")
+
+    def test_downloads_missing_model(self):
+        with tempfile.TemporaryDirectory() as d, \
+                mock.patch("transcribers.shutil.which", return_value="/usr/bin/whisper-cli"), \
+                mock.patch("transcribers.download") as download:
+            t = make_transcriber("whisper.cpp:tiny", models_dir=d)
+            download.assert_called_once()
+            self.assertTrue(download.call_args[0][0].endswith("/ggml-tiny.bin"))
+
+            cmd = t.command("/tmp/a.wav", None)
+            self.assertEqual(cmd[:3], ["whisper-cli", "-m", os.path.join(d, "ggml-tiny.bin")])
+            self.assertIn("auto", cmd)
+
+    def test_needs_binary(self):
+        with mock.patch("transcribers.shutil.which", return_value=None):
+            with self.assertRaises(RuntimeError):
+                WhisperCppTranscriber("tiny", "/models")
+
+
+class TestOpenAI(unittest.TestCase):
+    """Test the OpenAI backend"""
+
+    def test_needs_key(self):
+        with mock.patch.dict(os.environ, {"OPENAI_API_KEY": ""}):
+            with self.assertRaises(RuntimeError):
+                OpenAITranscriber("")
+
+    def test_request(self):
+        with tempfile.NamedTemporaryFile(suffix=".wav") as f, \
+                mock.patch.dict(os.environ, {"OPENAI_API_KEY": "sk-test"}, clear=True):
+            f.write(b"RIFFdata")
+            f.flush()
+            req = OpenAITranscriber("").request(f.name, "en")
+            self.assertEqual(req.full_url, "https://api.openai.com/v1/audio/transcriptions")
+            self.assertEqual(req.get_header("Authorization"), "Bearer sk-test")
+            self.assertIn(b"whisper-1", req.data)
+            self.assertIn(b"RIFFdata", req.data)
+            self.assertIn(b'name="language"', req.data)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,202 @@
+"""
+Transcription backends for the voice daemon.
+
+voice.model picks the backend and its model:
+    small                   faster-whisper (local python whisper), any size
+    local:large-v3          the same, spelled out
+    whisper.cpp:base.en     a whisper.cpp binary; ggml models are downloaded
+    openai                  the OpenAI audio API (OPENAI_API_KEY), whisper-1
+    openai:gpt-4o-transcribe
+"""
+
+import json
+import logging
+import os
+import re
+import shutil
+import subprocess
+import tempfile
+import urllib.request
+import uuid
+from pathlib import Path
+from typing import Optional, Tuple
+
+logger = logging.getLogger('gforge-voice')
+
+# Where whisper.cpp's ggml models are downloaded from
+GGML_URL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-{model}.bin"
+
+# Names the whisper.cpp binary is installed under. Its old build name,
+# "main", is left out: any program by that name on PATH would be run.
+WHISPER_CPP_BINARIES = ["whisper-cli", "whisper-cpp", "whisper.cpp"]
+
+BACKEND_ALIASES = {
+    "local": "local",
+    "faster-whisper": "local",
+    "whisper.cpp": "whisper.cpp",
+    "whispercpp": "whisper.cpp",
+    "cpp": "whisper.cpp",
+    "openai": "openai",
+}
+
+
+def default_models_dir() -> str:
+    """gforge's data directory for downloaded models"""
+    data = os.environ.get("XDG_DATA_HOME") or os.path.expanduser("~/.local/share")
+    return os.path.join(data, "gforge", "models")
+
+
+def parse_model_spec(spec: str) -> Tuple[str, str]:
+    """Split voice.model into a backend and its model"""
+    spec = spec.strip()
+    backend, sep, model = spec.partition(":")
+    if not sep:
+        if spec.lower() in ("openai", "whisper.cpp"):
+            return BACKEND_ALIASES[spec.lower()], ""
+        return "local", spec
+    if backend.lower() not in BACKEND_ALIASES:
+        raise ValueError(f"unknown transcription backend: {backend}")
+    return BACKEND_ALIASES[backend.lower()], model
+
+
+class Transcriber:
+    """Turns a recorded WAV file into text"""
+
+    name = ""
+
+    def transcribe(self, wav_path: str, language: Optional[str]) -> str:
+        raise NotImplementedError
+
+
+class FasterWhisperTranscriber(Transcriber):
+    """Local python whisper (faster-whisper), which downloads its models"""
+
+    name = "local"
+
+    def __init__(self, model: str, device: str = "auto", models_dir: Optional[str] = None):
+        from faster_whisper import WhisperModel
+
+        # Determine compute type based on device
+        compute_type = "float32"
+        if device == "cuda":
+            compute_type = "float16"
+        elif device == "auto":
+            device = "cpu"
+            try:
+                import torch
+                if torch.cuda.is_available():
+                    device = "cuda"
+                    compute_type = "float16"
+            except ImportError:
+                pass
+
+        self.device = device
+        self.model = WhisperModel(model or "small", device=device, compute_type=compute_type,
+                                  download_root=models_dir)
+
+    def transcribe(self, wav_path: str, language: Optional[str]) -> str:
+        segments, _ = self.model.transcribe(wav_path, language=language)
+        return " ".join(s.text for s in segments).strip()
+
+
+def ggml_model_path(model: str, models_dir: str) -> Path:
+    """Where a whisper.cpp model is kept; a path to a model file is used
+    as is"""
+    if os.sep in model or model.endswith(".bin"):
+        return Path(os.path.expanduser(model))
+    if not re.fullmatch(r"[\w.\-]+", model):
+        raise ValueError(f"invalid whisper.cpp model name: {model}")
+    return Path(models_dir) / f"ggml-{model}.bin"
+
+
+def download(url: str, dest: Path):
+    """Download url to dest, leaving nothing behind on failure"""
+    dest.parent.mkdir(parents=True, exist_ok=True)
+    logger.info(f"Downloading {url} to {dest}...")
+    fd, tmp = tempfile.mkstemp(dir=dest.parent, suffix=".part")
+    try:
+        with os.fdopen(fd, "wb") as out, urllib.request.urlopen(url, timeout=60) as resp:
+            shutil.copyfileobj(resp, out)
+        os.replace(tmp, dest)
+    except BaseException:
+        Path(tmp).unlink(missing_ok=True)
+        raise
+
+
+class WhisperCppTranscriber(Transcriber):
+    """A whisper.cpp binary, downloading its ggml model on first use"""
+
+    name = "whisper.cpp"
+
+    def __init__(self, model: str, models_dir: str):
+        self.binary = next((b for b in WHISPER_CPP_BINARIES if shutil.which(b)), None)
+        if not self.binary:
+            raise RuntimeError("whisper.cpp not found; install it as " + " or ".join(WHISPER_CPP_BINARIES))
+
+        self.model_path = ggml_model_path(model or "base.en", models_dir)
+        if not self.model_path.exists():
+            if os.sep in model:
+                raise RuntimeError(f"whisper.cpp model not found: {self.model_path}")
+            download(GGML_URL.format(model=model or "base.en"), self.model_path)
+
+    def command(self, wav_path: str, language: Optional[str]) -> list:
+        return [self.binary, "-m", str(self.model_path), "-f", wav_path,
+                "-l", language or "auto", "--no-timestamps"]
+
+    def transcribe(self, wav_path: str, language: Optional[str]) -> str:
+        result = subprocess.run(self.command(wav_path, language), capture_output=True, text=True)
+        if result.returncode != 0:
+            raise RuntimeError(f"whisper.cpp failed: {result.stderr.strip()[-500:]}")
+        return " ".join(line.strip() for line in result.stdout.splitlines() if line.strip())
+
+
+class OpenAITranscriber(Transcriber):
+    """The OpenAI audio transcription API"""
+
+    name = "openai"
+
+    def __init__(self, model: str):
+        self.model = model or "whisper-1"
+        self.api_key = os.environ.get("OPENAI_API_KEY", "")
+        if not self.api_key:
+            raise RuntimeError("the openai backend needs OPENAI_API_KEY")
+        self.base_url = os.environ.get("OPENAI_BASE_URL", "https://api.openai.com/v1").rstrip("/")
+
+    def request(self, wav_path: str, language: Optional[str]) -> urllib.request.Request:
+        boundary = uuid.uuid4().hex
+        fields = {"model": self.model, "response_format": "json"}
+        if language:
+            fields["language"] = language
+
+        body = b""
+        for name, value in fields.items():
+            body += (f"--{boundary}\r\nContent-Disposition: form-data; name=\"{name}\"\r\n\r\n"
+                     f"{value}\r\n").encode()
+        body += (f"--{boundary}\r\nContent-Disposition: form-data; name=\"file\"; filename=\"speech.wav\"\r\n"
+                 "Content-Type: audio/wav\r\n\r\n").encode()
+        body += Path(wav_path).read_bytes() + f"\r\n--{boundary}--\r\n".encode()
+
+        return urllib.request.Request(
+            self.base_url + "/audio/transcriptions",
+            data=body,
+            headers={
+                "Authorization": f"Bearer {self.api_key}",
+                "Content-Type": f"multipart/form-data; boundary={boundary}",
+            },
+            method="POST",
+        )
+
+    def transcribe(self, wav_path: str, language: Optional[str]) -> str:
+        with urllib.request.urlopen(self.request(wav_path, language), timeout=60) as resp:
+            return json.load(resp).get("text", "").strip()
+
+
+def make_transcriber(spec: str, device: str = "auto", models_dir: Optional[str] = None) -> Transcriber:
+    """Create the transcriber voice.model asks for"""
+    backend, model = parse_model_spec(spec)
+    models_dir = models_dir or default_models_dir()
+    if backend == "whisper.cpp":
+        return WhisperCppTranscriber(model, models_dir)
+    if backend == "openai":
+        return OpenAITranscriber(model)
+    return FasterWhisperTranscriber(model, device, models_dir)